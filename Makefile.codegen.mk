@@ -17,6 +17,19 @@ else
 	@$(foreach d,$(APPS),(cd $(d) && sqlc vet) &&) true
 endif
 
+.PHONY: proto-generate
+proto-generate:
+	@cd core/proto && go run . generate
+
+.PHONY: proto-lint
+proto-lint:
+	@cd core/proto && go run . lint
+
+# AGAINST selects the git revision whose committed image is the baseline.
+.PHONY: proto-breaking
+proto-breaking:
+	@cd core/proto && go run . breaking -against $(or $(AGAINST),HEAD~1)
+
 .PHONY: docs-schema
 docs-schema:
 	./tools/scripts/generate-schema-doc.sh
@@ -14,6 +14,9 @@ validate-naming:
 validate-quality:
 	./tools/scripts/scan-quality.sh
 
+.PHONY: validate-proto
+validate-proto: proto-lint proto-breaking
+
 .PHONY: validate-security
 validate-security:
 	@$(foreach d,$(MODULES),(cd $(d) && echo "Executing [govulncheck] on $(d):" && govulncheck ./...) &&) true
@@ -23,4 +26,4 @@ update-doc-hashes:
 	./tools/scripts/update-doc-hashes.sh
 
 .PHONY: guard
-guard: validate-docs validate-architecture validate-naming validate-quality validate-security
+guard: validate-docs validate-architecture validate-naming validate-quality validate-proto validate-security
@@ -27,6 +27,7 @@ func (s *ArchitectureSuite) TestForbiddenImports() {
 		layer   string
 		pattern string
 		denied  []string
+		skip    []string
 	}
 
 	rules := []rule{
@@ -42,6 +43,7 @@ func (s *ArchitectureSuite) TestForbiddenImports() {
 				"database/sql",
 				"net/http",
 				"go-chi/chi",
+				"google.golang.org/grpc",
 			},
 		},
 		{
@@ -54,6 +56,7 @@ func (s *ArchitectureSuite) TestForbiddenImports() {
 				"database/sql",
 				"net/http",
 				"go-chi/chi",
+				"google.golang.org/grpc",
 			},
 		},
 		{
@@ -64,6 +67,19 @@ func (s *ArchitectureSuite) TestForbiddenImports() {
 				"internal/transport",
 				"net/http",
 				"go-chi/chi",
+				"google.golang.org/grpc",
+			},
+			skip: []string{"internal/infrastructure/outbound"},
+		},
+		{
+			// Outbound adapters are HTTP clients, so they alone may use net/http.
+			layer:   "outbound",
+			pattern: "internal/infrastructure/outbound",
+			denied: []string{
+				"internal/service",
+				"internal/transport",
+				"go-chi/chi",
+				"google.golang.org/grpc",
 			},
 		},
 		{
@@ -85,9 +101,20 @@ func (s *ArchitectureSuite) TestForbiddenImports() {
 			}
 
 			err := filepath.Walk(layerDir, func(path string, info os.FileInfo, err error) error {
-				if err != nil || info.IsDir() || !strings.HasSuffix(path, ".go") {
+				if err != nil {
 					return err
 				}
+				if info.IsDir() {
+					for _, skip := range r.skip {
+						if path == filepath.Join(s.moduleRoot, skip) {
+							return filepath.SkipDir
+						}
+					}
+					return nil
+				}
+				if !strings.HasSuffix(path, ".go") {
+					return nil
+				}
 				if strings.HasSuffix(path, "_test.go") || strings.Contains(path, "sqlcgen") {
 					return nil
 				}
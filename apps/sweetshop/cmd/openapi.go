@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	transportroutes "github.com/bbsbb/go-edge/sweetshop/internal/transport/http"
+)
+
+// RunOpenAPI writes the OpenAPI document to outPath, or to stdout when outPath is empty or "-".
+func RunOpenAPI(outPath string) error {
+	doc, err := transportroutes.OpenAPIDocument()
+	if err != nil {
+		return fmt.Errorf("build openapi document: %w", err)
+	}
+
+	body, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode openapi document: %w", err)
+	}
+	body = append(body, '\n')
+
+	if outPath == "" || outPath == "-" {
+		_, err = os.Stdout.Write(body)
+		return err
+	}
+	if err := os.WriteFile(outPath, body, 0o600); err != nil {
+		return fmt.Errorf("write openapi document: %w", err)
+	}
+	return nil
+}
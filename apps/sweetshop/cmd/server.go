@@ -11,6 +11,8 @@ import (
 	"go.uber.org/fx"
 
 	"github.com/bbsbb/go-edge/core/fx/bootfx"
+	"github.com/bbsbb/go-edge/core/fx/grpcserverfx"
+	"github.com/bbsbb/go-edge/core/fx/httpclientfx"
 	"github.com/bbsbb/go-edge/core/fx/httpserverfx"
 	"github.com/bbsbb/go-edge/core/fx/middlewarefx"
 	"github.com/bbsbb/go-edge/core/fx/otelfx"
@@ -18,8 +20,11 @@ import (
 	"github.com/bbsbb/go-edge/core/fx/rlsfx"
 	transporthttp "github.com/bbsbb/go-edge/core/transport/http"
 	"github.com/bbsbb/go-edge/sweetshop/internal/config"
+	"github.com/bbsbb/go-edge/sweetshop/internal/infrastructure/outbound"
 	"github.com/bbsbb/go-edge/sweetshop/internal/infrastructure/persistence"
+	transportgrpc "github.com/bbsbb/go-edge/sweetshop/internal/transport/grpc"
 	transportroutes "github.com/bbsbb/go-edge/sweetshop/internal/transport/http"
+	"github.com/bbsbb/go-edge/sweetshop/internal/transport/job"
 )
 
 func RunServer(configPath string) error {
@@ -33,12 +38,18 @@ func RunServer(configPath string) error {
 	app := fx.New(
 		bootfx.BootFx(cfg,
 			httpserverfx.Module,
+			grpcserverfx.Module,
 			psqlfx.Module,
+			psqlfx.ListenerModule,
 			rlsfx.Module,
 			otelfx.Module,
 			middlewarefx.Module,
+			httpclientfx.Module,
 			persistence.Module,
+			outbound.Module,
 			transportroutes.RouteModule,
+			transportgrpc.Module,
+			job.Module,
 			fx.Invoke(registerHealthRoutes),
 		),
 	)
@@ -49,12 +60,17 @@ func RunServer(configPath string) error {
 
 type healthParams struct {
 	fx.In
-	Mux    *chi.Mux
-	Pool   *pgxpool.Pool
-	Logger *slog.Logger
+	Mux      *chi.Mux
+	Pool     *pgxpool.Pool
+	Listener *psqlfx.Listener
+	Logger   *slog.Logger
 }
 
 func registerHealthRoutes(p healthParams) {
 	p.Mux.Get("/healthz", transporthttp.LivenessHandler())
-	p.Mux.Get("/readyz", transporthttp.ReadinessHandler(p.Pool, 0, p.Logger))
+	p.Mux.Get("/readyz", transporthttp.ReadinessHandler(p.Pool, 0, p.Logger, transporthttp.ComponentCheck{
+		Name:          "postgres_listener",
+		ComponentType: transporthttp.ComponentTypeDatastore,
+		Check:         p.Listener.Check,
+	}))
 }
@@ -8,6 +8,8 @@ import (
 	"go.uber.org/fx"
 
 	"github.com/bbsbb/go-edge/core/configuration"
+	"github.com/bbsbb/go-edge/core/fx/grpcserverfx"
+	"github.com/bbsbb/go-edge/core/fx/httpclientfx"
 	"github.com/bbsbb/go-edge/core/fx/httpserverfx"
 	"github.com/bbsbb/go-edge/core/fx/loggerfx"
 	"github.com/bbsbb/go-edge/core/fx/middlewarefx"
@@ -19,20 +21,29 @@ import (
 var (
 	_ loggerfx.WithLogging        = (*AppConfiguration)(nil)
 	_ httpserverfx.WithHTTPServer = (*AppConfiguration)(nil)
+	_ grpcserverfx.WithGRPCServer = (*AppConfiguration)(nil)
 	_ psqlfx.WithPSQL             = (*AppConfiguration)(nil)
 	_ rlsfx.WithRLS               = (*AppConfiguration)(nil)
 	_ otelfx.WithOTel             = (*AppConfiguration)(nil)
 	_ middlewarefx.WithMiddleware = (*AppConfiguration)(nil)
+	_ httpclientfx.WithHTTPClient = (*AppConfiguration)(nil)
 )
 
 type AppConfiguration struct {
 	Environment configuration.Environment   `yaml:"-" env:"ENVIRONMENT,overwrite"`
 	Logging     *loggerfx.Configuration     `yaml:"logging" env:",prefix=LOGGING_,noinit"`
 	HTTPServer  *httpserverfx.Configuration `yaml:"http_server" env:",prefix=HTTP_,noinit"`
+	GRPCServer  *grpcserverfx.Configuration `yaml:"grpc_server" env:",prefix=GRPC_,noinit"`
 	PSQL        *psqlfx.Configuration       `yaml:"psql" env:",prefix=PSQL_,noinit"`
 	OTel        *otelfx.Configuration       `yaml:"otel" env:",prefix=OTEL_,noinit"`
 	RLS         *rlsfx.Configuration        `yaml:"rls" env:",prefix=RLS_,noinit"`
 	Middleware  *middlewarefx.Configuration `yaml:"middleware" env:",prefix=MW_,noinit"`
+	HTTPClient  *httpclientfx.Configuration `yaml:"http_client" env:",prefix=HTTP_CLIENT_,noinit"`
+
+	ProductPurge *ProductPurgeConfiguration `yaml:"product_purge" env:",prefix=PRODUCT_PURGE_,noinit"`
+	Webhooks     *WebhookConfiguration      `yaml:"webhooks" env:",prefix=WEBHOOKS_,noinit"`
+	Payments     *PaymentConfiguration      `yaml:"payments" env:",prefix=PAYMENTS_,noinit"`
+	SalesReports *SalesReportConfiguration  `yaml:"sales_reports" env:",prefix=SALES_REPORTS_,noinit"`
 }
 
 func NewAppConfiguration(ctx context.Context, configPath string) (*AppConfiguration, error) {
@@ -53,6 +64,10 @@ func (c *AppConfiguration) HTTPServerConfiguration() *httpserverfx.Configuration
 	return c.HTTPServer
 }
 
+func (c *AppConfiguration) GRPCServerConfiguration() *grpcserverfx.Configuration {
+	return c.GRPCServer
+}
+
 func (c *AppConfiguration) PSQLConfiguration() *psqlfx.Configuration {
 	return c.PSQL
 }
@@ -75,6 +90,38 @@ func (c *AppConfiguration) MiddlewareConfiguration() *middlewarefx.Configuration
 	return c.Middleware
 }
 
+func (c *AppConfiguration) HTTPClientConfiguration() *httpclientfx.Configuration {
+	return c.HTTPClient
+}
+
+func (c *AppConfiguration) ProductPurgeConfiguration() *ProductPurgeConfiguration {
+	if c.ProductPurge == nil {
+		return &ProductPurgeConfiguration{}
+	}
+	return c.ProductPurge
+}
+
+func (c *AppConfiguration) WebhookConfiguration() *WebhookConfiguration {
+	if c.Webhooks == nil {
+		return &WebhookConfiguration{}
+	}
+	return c.Webhooks
+}
+
+func (c *AppConfiguration) PaymentConfiguration() *PaymentConfiguration {
+	if c.Payments == nil {
+		return &PaymentConfiguration{}
+	}
+	return c.Payments
+}
+
+func (c *AppConfiguration) SalesReportConfiguration() *SalesReportConfiguration {
+	if c.SalesReports == nil {
+		return &SalesReportConfiguration{}
+	}
+	return c.SalesReports
+}
+
 func (c *AppConfiguration) AsFx() fx.Option {
 	return fx.Supply(
 		c,
@@ -82,10 +129,12 @@ func (c *AppConfiguration) AsFx() fx.Option {
 			c,
 			fx.As(new(loggerfx.WithLogging)),
 			fx.As(new(httpserverfx.WithHTTPServer)),
+			fx.As(new(grpcserverfx.WithGRPCServer)),
 			fx.As(new(psqlfx.WithPSQL)),
 			fx.As(new(otelfx.WithOTel)),
 			fx.As(new(rlsfx.WithRLS)),
 			fx.As(new(middlewarefx.WithMiddleware)),
+			fx.As(new(httpclientfx.WithHTTPClient)),
 		),
 	)
 }
@@ -0,0 +1,51 @@
+package config
+
+import (
+	"time"
+
+	"github.com/bbsbb/go-edge/core/configuration"
+)
+
+// PaymentProviderFake is the in-memory provider used until a real one is
+// integrated.
+const PaymentProviderFake = "fake"
+
+var _ configuration.WithValidation = (*PaymentConfiguration)(nil)
+
+// PaymentConfiguration selects the payment gateway orders are paid through.
+// Gateway calls are made outside the order's row lock, with the order
+// claimed instead; see OrderService.
+type PaymentConfiguration struct {
+	Provider string `yaml:"provider" env:"PROVIDER,overwrite" validate:"omitempty,oneof=fake"`
+	// CallTimeout bounds each gateway call, retries included.
+	CallTimeout time.Duration `yaml:"call_timeout" env:"CALL_TIMEOUT,overwrite" validate:"gte=0"`
+	// Lease is how long an order stays claimed for its gateway calls if the
+	// claim is not released. A transition makes up to three calls, so it
+	// must exceed three call timeouts.
+	Lease time.Duration `yaml:"lease" env:"LEASE,overwrite" validate:"gte=0"`
+}
+
+func (c *PaymentConfiguration) Validate() error {
+	return configuration.Validate.Struct(c)
+}
+
+func (c *PaymentConfiguration) ProviderOrDefault() string {
+	if c.Provider != "" {
+		return c.Provider
+	}
+	return PaymentProviderFake
+}
+
+func (c *PaymentConfiguration) CallTimeoutOrDefault() time.Duration {
+	if c.CallTimeout > 0 {
+		return c.CallTimeout
+	}
+	return 10 * time.Second
+}
+
+func (c *PaymentConfiguration) LeaseOrDefault() time.Duration {
+	if c.Lease > 0 {
+		return c.Lease
+	}
+	return time.Minute
+}
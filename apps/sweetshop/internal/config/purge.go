@@ -0,0 +1,35 @@
+package config
+
+import (
+	"time"
+
+	"github.com/bbsbb/go-edge/core/configuration"
+)
+
+var _ configuration.WithValidation = (*ProductPurgeConfiguration)(nil)
+
+// ProductPurgeConfiguration controls the background job that hard-deletes
+// soft-deleted products once their retention period has elapsed.
+type ProductPurgeConfiguration struct {
+	Enabled   bool          `yaml:"enabled" env:"ENABLED,overwrite"`
+	Interval  time.Duration `yaml:"interval" env:"INTERVAL,overwrite" validate:"gte=0"`
+	Retention time.Duration `yaml:"retention" env:"RETENTION,overwrite" validate:"gte=0"`
+}
+
+func (c *ProductPurgeConfiguration) Validate() error {
+	return configuration.Validate.Struct(c)
+}
+
+func (c *ProductPurgeConfiguration) IntervalOrDefault() time.Duration {
+	if c.Interval > 0 {
+		return c.Interval
+	}
+	return time.Hour
+}
+
+func (c *ProductPurgeConfiguration) RetentionOrDefault() time.Duration {
+	if c.Retention > 0 {
+		return c.Retention
+	}
+	return 30 * 24 * time.Hour
+}
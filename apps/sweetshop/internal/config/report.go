@@ -0,0 +1,28 @@
+package config
+
+import (
+	"time"
+
+	"github.com/bbsbb/go-edge/core/configuration"
+)
+
+var _ configuration.WithValidation = (*SalesReportConfiguration)(nil)
+
+// SalesReportConfiguration controls the background job that refreshes the
+// daily sales aggregates reports are read from. Sales show up in reports at
+// most one interval after they are made.
+type SalesReportConfiguration struct {
+	Enabled  bool          `yaml:"enabled" env:"ENABLED,overwrite"`
+	Interval time.Duration `yaml:"interval" env:"INTERVAL,overwrite" validate:"gte=0"`
+}
+
+func (c *SalesReportConfiguration) Validate() error {
+	return configuration.Validate.Struct(c)
+}
+
+func (c *SalesReportConfiguration) IntervalOrDefault() time.Duration {
+	if c.Interval > 0 {
+		return c.Interval
+	}
+	return 15 * time.Minute
+}
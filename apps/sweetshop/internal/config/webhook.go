@@ -0,0 +1,98 @@
+package config
+
+import (
+	"net/netip"
+	"time"
+
+	"github.com/bbsbb/go-edge/core/configuration"
+)
+
+var _ configuration.WithValidation = (*WebhookConfiguration)(nil)
+
+// WebhookConfiguration controls the background job that sends queued webhook
+// deliveries. Requests go through the "webhooks" client of http_client, whose
+// own retries should stay off: failed deliveries are retried by this job.
+type WebhookConfiguration struct {
+	Enabled      bool          `yaml:"enabled" env:"ENABLED,overwrite"`
+	PollInterval time.Duration `yaml:"poll_interval" env:"POLL_INTERVAL,overwrite" validate:"gte=0"`
+	BatchSize    int32         `yaml:"batch_size" env:"BATCH_SIZE,overwrite" validate:"gte=0"`
+	// Lease is how long a claimed delivery is kept from other dispatchers. It
+	// must exceed the webhooks client timeout.
+	Lease         time.Duration `yaml:"lease" env:"LEASE,overwrite" validate:"gte=0"`
+	MaxAttempts   int32         `yaml:"max_attempts" env:"MAX_ATTEMPTS,overwrite" validate:"gte=0"`
+	MinRetryDelay time.Duration `yaml:"min_retry_delay" env:"MIN_RETRY_DELAY,overwrite" validate:"gte=0"`
+	MaxRetryDelay time.Duration `yaml:"max_retry_delay" env:"MAX_RETRY_DELAY,overwrite" validate:"gte=0"`
+	// DisableAfter is the number of consecutive failed attempts, across all of
+	// an endpoint's deliveries, after which the endpoint is disabled.
+	DisableAfter int32 `yaml:"disable_after" env:"DISABLE_AFTER,overwrite" validate:"gte=0"`
+	// AllowHTTP accepts endpoints with plain http URLs. Only development and
+	// test setups should turn it on.
+	AllowHTTP bool `yaml:"allow_http" env:"ALLOW_HTTP,overwrite"`
+	// AllowedNetworks lists the CIDRs of loopback, private and link-local
+	// addresses deliveries may still be sent to, such as a local receiver.
+	AllowedNetworks []string `yaml:"allowed_networks" env:"ALLOWED_NETWORKS,overwrite" validate:"dive,cidr"`
+}
+
+func (c *WebhookConfiguration) Validate() error {
+	return configuration.Validate.Struct(c)
+}
+
+func (c *WebhookConfiguration) PollIntervalOrDefault() time.Duration {
+	if c.PollInterval > 0 {
+		return c.PollInterval
+	}
+	return 5 * time.Second
+}
+
+func (c *WebhookConfiguration) BatchSizeOrDefault() int32 {
+	if c.BatchSize > 0 {
+		return c.BatchSize
+	}
+	return 50
+}
+
+func (c *WebhookConfiguration) LeaseOrDefault() time.Duration {
+	if c.Lease > 0 {
+		return c.Lease
+	}
+	return time.Minute
+}
+
+func (c *WebhookConfiguration) MaxAttemptsOrDefault() int32 {
+	if c.MaxAttempts > 0 {
+		return c.MaxAttempts
+	}
+	return 10
+}
+
+func (c *WebhookConfiguration) MinRetryDelayOrDefault() time.Duration {
+	if c.MinRetryDelay > 0 {
+		return c.MinRetryDelay
+	}
+	return 30 * time.Second
+}
+
+func (c *WebhookConfiguration) MaxRetryDelayOrDefault() time.Duration {
+	if c.MaxRetryDelay > 0 {
+		return max(c.MaxRetryDelay, c.MinRetryDelayOrDefault())
+	}
+	return max(6*time.Hour, c.MinRetryDelayOrDefault())
+}
+
+func (c *WebhookConfiguration) DisableAfterOrDefault() int32 {
+	if c.DisableAfter > 0 {
+		return c.DisableAfter
+	}
+	return 20
+}
+
+// AllowedPrefixes parses AllowedNetworks, which Validate has checked.
+func (c *WebhookConfiguration) AllowedPrefixes() []netip.Prefix {
+	prefixes := make([]netip.Prefix, 0, len(c.AllowedNetworks))
+	for _, n := range c.AllowedNetworks {
+		if p, err := netip.ParsePrefix(n); err == nil {
+			prefixes = append(prefixes, p.Masked())
+		}
+	}
+	return prefixes
+}
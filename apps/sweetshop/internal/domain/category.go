@@ -0,0 +1,31 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	coredomain "github.com/bbsbb/go-edge/core/domain"
+)
+
+// Category groups an organization's products. Products, promotions and tax
+// rates refer to it by Slug, which they follow when it changes.
+type Category struct {
+	ID             uuid.UUID
+	OrganizationID uuid.UUID
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	Slug           ProductCategory
+	Name           string
+	SortOrder      int32
+}
+
+// Validate checks that the category's slug is well formed.
+func (c *Category) Validate() error {
+	if !c.Slug.IsWellFormed() {
+		return coredomain.NewError(coredomain.CodeValidation, fmt.Sprintf(
+			"slug must be lowercase letters and digits in words joined by underscores, at most %d characters", MaxCategorySlugLength))
+	}
+	return nil
+}
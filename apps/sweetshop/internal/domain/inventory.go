@@ -0,0 +1,118 @@
+package domain
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+
+	coredomain "github.com/bbsbb/go-edge/core/domain"
+)
+
+// MetaAvailableStock carries the units that were available when a
+// reservation failed.
+var MetaAvailableStock = coredomain.NewMetaKey[int32]("available")
+
+// Inventory is a product's stock. OnHand counts the units in the shop and
+// Reserved the units held by items of orders that are neither fulfilled nor
+// cancelled. A product whose stock is not Tracked can be ordered without
+// limit; it becomes tracked with its first adjustment.
+type Inventory struct {
+	ProductID      uuid.UUID
+	OrganizationID uuid.UUID
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	Tracked        bool
+	OnHand         int32
+	Reserved       int32
+}
+
+func (i *Inventory) Available() int32 {
+	return i.OnHand - i.Reserved
+}
+
+// Reserve holds quantity units for an order item.
+func (i *Inventory) Reserve(quantity int32) error {
+	if quantity > i.Available() {
+		err := coredomain.NewError(coredomain.CodeInvariant,
+			fmt.Sprintf("insufficient stock: %d requested, %d available", quantity, i.Available()))
+		return coredomain.WithMeta(err, MetaAvailableStock, i.Available())
+	}
+	i.Reserved += quantity
+	return nil
+}
+
+// Release returns quantity units held by an order item to available stock.
+func (i *Inventory) Release(quantity int32) {
+	i.Reserved -= quantity
+}
+
+// Apply changes the on-hand stock by the adjustment's delta and records the
+// resulting level on it. Stock already reserved cannot be adjusted away.
+func (i *Inventory) Apply(a *StockAdjustment, at time.Time) error {
+	onHand := i.OnHand + a.QuantityDelta
+	if onHand < i.Reserved {
+		return coredomain.NewError(coredomain.CodeInvariant,
+			fmt.Sprintf("adjustment would leave %d on hand, below the %d reserved", onHand, i.Reserved))
+	}
+	i.Tracked = true
+	i.OnHand = onHand
+	i.UpdatedAt = at
+	a.OnHandAfter = onHand
+	return nil
+}
+
+type StockAdjustmentReason string
+
+const (
+	StockAdjustmentReceived   StockAdjustmentReason = "received"
+	StockAdjustmentReturned   StockAdjustmentReason = "returned"
+	StockAdjustmentDamaged    StockAdjustmentReason = "damaged"
+	StockAdjustmentExpired    StockAdjustmentReason = "expired"
+	StockAdjustmentCorrection StockAdjustmentReason = "correction"
+)
+
+func (r StockAdjustmentReason) IsValid() bool {
+	return slices.Contains([]StockAdjustmentReason{
+		StockAdjustmentReceived, StockAdjustmentReturned, StockAdjustmentDamaged,
+		StockAdjustmentExpired, StockAdjustmentCorrection,
+	}, r)
+}
+
+// StockAdjustment is a manual change to a product's on-hand stock.
+// OnHandAfter is the level it left.
+type StockAdjustment struct {
+	ID             uuid.UUID
+	OrganizationID uuid.UUID
+	ProductID      uuid.UUID
+	CreatedAt      time.Time
+	QuantityDelta  int32
+	Reason         StockAdjustmentReason
+	Note           *string
+	OnHandAfter    int32
+}
+
+// StockSettlement is what an order status change does to the stock reserved
+// by the order's items.
+type StockSettlement string
+
+const (
+	// StockHeld keeps the reservations in place.
+	StockHeld StockSettlement = "held"
+	// StockReleased returns the reserved units to available stock.
+	StockReleased StockSettlement = "released"
+	// StockCommitted takes the reserved units off the shelf.
+	StockCommitted StockSettlement = "committed"
+)
+
+func (c OrderStatusChange) StockSettlement() StockSettlement {
+	switch c.To {
+	case OrderStatusCancelled:
+		return StockReleased
+	case OrderStatusFulfilled:
+		return StockCommitted
+	default:
+		return StockHeld
+	}
+}
@@ -1,66 +1,337 @@
 package domain
 
 import (
+	"fmt"
 	"slices"
 	"time"
 
 	"github.com/google/uuid"
 
+	"github.com/bbsbb/go-edge/core/datatype"
 	coredomain "github.com/bbsbb/go-edge/core/domain"
 )
 
 type OrderStatus string
 
 const (
-	OrderStatusOpen   OrderStatus = "open"
-	OrderStatusClosed OrderStatus = "closed"
+	OrderStatusOpen      OrderStatus = "open"
+	OrderStatusSubmitted OrderStatus = "submitted"
+	OrderStatusPaid      OrderStatus = "paid"
+	OrderStatusFulfilled OrderStatus = "fulfilled"
+	OrderStatusCancelled OrderStatus = "cancelled"
 )
 
 func (s OrderStatus) IsValid() bool {
-	return slices.Contains([]OrderStatus{OrderStatusOpen, OrderStatusClosed}, s)
+	return slices.Contains([]OrderStatus{
+		OrderStatusOpen, OrderStatusSubmitted, OrderStatusPaid, OrderStatusFulfilled, OrderStatusCancelled,
+	}, s)
 }
 
+// Order is a customer's order. Its status only changes through Transition;
+// the *At fields record when it entered each status past open. Currency is
+// the organization's when the order was opened; its items and payments are
+// all in it. Adjustments are the discounts promotions give its items and Tax
+// the tax charged on them; see ApplyPromotions and ApplyTax.
 type Order struct {
 	ID             uuid.UUID
 	OrganizationID uuid.UUID
 	CreatedAt      time.Time
 	UpdatedAt      time.Time
 	Status         OrderStatus
+	Currency       datatype.Currency
+	SubmittedAt    *time.Time
+	PaidAt         *time.Time
+	FulfilledAt    *time.Time
+	CancelledAt    *time.Time
 	Items          []OrderItem
+	Adjustments    []OrderAdjustment
+	Tax            *OrderTax
+	Payments       []Payment
 }
 
-func (o *Order) CanAddItem() error {
+// CanAddItem reports whether item can be added to the order: the order must
+// be open, the item priced in the order's currency, and the new total must
+// still be representable.
+func (o *Order) CanAddItem(item *OrderItem) error {
 	if o.Status != OrderStatusOpen {
-		return coredomain.NewError(coredomain.CodeInvariant, "cannot add items to a closed order")
+		return coredomain.NewError(coredomain.CodeInvariant, "items can only be added to an open order")
+	}
+	if item.UnitPrice.Currency() != o.Currency {
+		return coredomain.NewError(coredomain.CodeInvariant,
+			fmt.Sprintf("item is priced in %s but the order is in %s", item.UnitPrice.Currency(), o.Currency))
+	}
+	return o.canPrice(append(slices.Clone(o.Items), *item))
+}
+
+// CanChangeItems reports whether the order's items can be changed or
+// removed, which is only while it is open.
+func (o *Order) CanChangeItems() error {
+	if o.Status != OrderStatusOpen {
+		return coredomain.NewError(coredomain.CodeInvariant, "items can only be changed while the order is open")
+	}
+	return nil
+}
+
+// CanSetQuantity reports whether the order's item can be changed to quantity
+// units: the order must be open, the quantity positive and the new total
+// still representable.
+func (o *Order) CanSetQuantity(item *OrderItem, quantity int32) error {
+	if err := o.CanChangeItems(); err != nil {
+		return err
+	}
+	if quantity <= 0 {
+		return coredomain.NewError(coredomain.CodeValidation, "quantity must be positive")
+	}
+	items := slices.Clone(o.Items)
+	for i := range items {
+		if items[i].ID == item.ID {
+			items[i].Quantity = quantity
+		}
+	}
+	return o.canPrice(items)
+}
+
+// canPrice reports whether the order's subtotal is representable with items
+// as its lines.
+func (o *Order) canPrice(items []OrderItem) error {
+	if _, err := subtotal(o.Currency, items); err != nil {
+		return coredomain.WrapError(coredomain.CodeInvariant, "order total is out of range", err)
+	}
+	return nil
+}
+
+// Item returns the order's item with the given ID, or a not-found error.
+func (o *Order) Item(id uuid.UUID) (*OrderItem, error) {
+	for i := range o.Items {
+		if o.Items[i].ID == id {
+			return &o.Items[i], nil
+		}
+	}
+	return nil, coredomain.NewError(coredomain.CodeNotFound, "order item not found")
+}
+
+// LineFor returns the order's item that item merges into: the line of the
+// same product with the same options at the same unit price. It returns nil
+// when there is none, so a product added again after its price changed gets a
+// line of its own.
+func (o *Order) LineFor(item *OrderItem) *OrderItem {
+	for i := range o.Items {
+		line := &o.Items[i]
+		if line.ProductID == item.ProductID && line.UnitPrice == item.UnitPrice && sameOptions(line.Options, item.Options) {
+			return line
+		}
 	}
 	return nil
 }
 
-func (o *Order) CanClose() error {
-	if o.Status == OrderStatusClosed {
-		return coredomain.NewError(coredomain.CodeInvariant, "order is already closed")
+// CanRefund reports whether a fulfilled order's capture can be returned to
+// the customer. Orders that are not fulfilled yet are cancelled instead.
+func (o *Order) CanRefund() error {
+	if o.Status != OrderStatusFulfilled {
+		return coredomain.NewError(coredomain.CodeInvariant, "only fulfilled orders can be refunded")
+	}
+	if o.PaymentStatus() != PaymentStatusCaptured {
+		return coredomain.NewError(coredomain.CodeInvariant, "order has no captured payment to refund")
+	}
+	return nil
+}
+
+// PaymentStatus is the state reached by the order's successful payments.
+func (o *Order) PaymentStatus() PaymentStatus {
+	status := PaymentStatusUnpaid
+	for _, p := range o.Payments {
+		if !p.Succeeded() {
+			continue
+		}
+		switch p.Operation {
+		case PaymentOperationAuthorize:
+			status = PaymentStatusAuthorized
+		case PaymentOperationCapture:
+			status = PaymentStatusCaptured
+		case PaymentOperationVoid:
+			status = PaymentStatusVoided
+		case PaymentOperationRefund:
+			status = PaymentStatusRefunded
+		}
+	}
+	return status
+}
+
+// Authorization returns the order's latest successful authorization, or nil
+// if it has none.
+func (o *Order) Authorization() *Payment {
+	for i := len(o.Payments) - 1; i >= 0; i-- {
+		p := &o.Payments[i]
+		if p.Succeeded() && p.Operation == PaymentOperationAuthorize {
+			return p
+		}
 	}
 	return nil
 }
 
-func (o *Order) TotalCents() int32 {
-	var total int32
-	for _, item := range o.Items {
-		total += item.LineTotalCents()
+func (o *Order) Captured() (datatype.Money, error) {
+	return o.paid(PaymentOperationCapture)
+}
+
+func (o *Order) Refunded() (datatype.Money, error) {
+	return o.paid(PaymentOperationRefund)
+}
+
+func (o *Order) paid(op PaymentOperation) (datatype.Money, error) {
+	total, err := datatype.NewMoney(0, o.Currency)
+	if err != nil {
+		return datatype.Money{}, err
+	}
+	for _, p := range o.Payments {
+		if p.Succeeded() && p.Operation == op {
+			if total, err = total.Add(p.Amount); err != nil {
+				return datatype.Money{}, err
+			}
+		}
+	}
+	return total, nil
+}
+
+// Subtotal is the sum of the items' line totals, before adjustments.
+func (o *Order) Subtotal() (datatype.Money, error) {
+	return subtotal(o.Currency, o.Items)
+}
+
+func subtotal(currency datatype.Currency, items []OrderItem) (datatype.Money, error) {
+	total, err := datatype.NewMoney(0, currency)
+	if err != nil {
+		return datatype.Money{}, err
+	}
+	for _, item := range items {
+		line, err := item.LineTotal()
+		if err != nil {
+			return datatype.Money{}, err
+		}
+		if total, err = total.Add(line); err != nil {
+			return datatype.Money{}, err
+		}
+	}
+	return total, nil
+}
+
+// Discount is the sum of the adjustments, zero or negative.
+func (o *Order) Discount() (datatype.Money, error) {
+	total, err := datatype.NewMoney(0, o.Currency)
+	if err != nil {
+		return datatype.Money{}, err
+	}
+	for _, a := range o.Adjustments {
+		if total, err = total.Add(a.Amount); err != nil {
+			return datatype.Money{}, err
+		}
+	}
+	return total, nil
+}
+
+// Total is what the customer pays: the subtotal with the discount applied,
+// plus tax unless prices include it.
+func (o *Order) Total() (datatype.Money, error) {
+	total, err := o.Subtotal()
+	if err != nil {
+		return datatype.Money{}, err
+	}
+	discount, err := o.Discount()
+	if err != nil {
+		return datatype.Money{}, err
+	}
+	if total, err = total.Add(discount); err != nil {
+		return datatype.Money{}, err
+	}
+	if o.Tax == nil || o.Tax.Pricing == TaxPricingInclusive {
+		return total, nil
+	}
+	tax, err := o.TaxTotal()
+	if err != nil {
+		return datatype.Money{}, err
+	}
+	return total.Add(tax)
+}
+
+// ItemAdjustments returns the adjustments made to one of the order's items.
+func (o *Order) ItemAdjustments(itemID uuid.UUID) []OrderAdjustment {
+	var adjustments []OrderAdjustment
+	for _, a := range o.Adjustments {
+		if a.OrderItemID == itemID {
+			adjustments = append(adjustments, a)
+		}
 	}
-	return total
+	return adjustments
 }
 
+// OrderItem is a line of an order. UnitPrice is the product's price with the
+// chosen Options when the item was added; ProductCategory is the product's current category, which
+// decides the promotions that apply to it and its tax rate. ReservedQuantity
+// is the number of units it holds from its product's tracked stock; it is
+// zero for products whose stock is not tracked.
 type OrderItem struct {
-	ID             uuid.UUID
-	OrganizationID uuid.UUID
-	OrderID        uuid.UUID
-	ProductID      uuid.UUID
-	CreatedAt      time.Time
-	Quantity       int32
-	PriceCents     int32
+	ID               uuid.UUID
+	OrganizationID   uuid.UUID
+	OrderID          uuid.UUID
+	ProductID        uuid.UUID
+	ProductName      string
+	ProductCategory  ProductCategory
+	CreatedAt        time.Time
+	Quantity         int32
+	UnitPrice        datatype.Money
+	ReservedQuantity int32
+	Options          []OrderItemOption
+}
+
+func (i *OrderItem) LineTotal() (datatype.Money, error) {
+	return i.UnitPrice.Mul(int64(i.Quantity))
+}
+
+// OrderFilter narrows an order listing; nil fields match every order.
+// CreatedFrom is inclusive and CreatedTo exclusive. MinTotal and MaxTotal are
+// in minor units of TotalCurrency; they only match orders whose total was
+// fixed when they were submitted, in that currency.
+type OrderFilter struct {
+	Status        *OrderStatus
+	CreatedFrom   *time.Time
+	CreatedTo     *time.Time
+	MinTotal      *int64
+	MaxTotal      *int64
+	TotalCurrency datatype.Currency
+	ProductID     *uuid.UUID
+}
+
+// HasTotal reports whether the filter bounds the total.
+func (f *OrderFilter) HasTotal() bool {
+	return f.MinTotal != nil || f.MaxTotal != nil
+}
+
+func (f *OrderFilter) Validate() error {
+	if f.Status != nil && !f.Status.IsValid() {
+		return coredomain.NewError(coredomain.CodeValidation, fmt.Sprintf("status %q is not an order status", *f.Status))
+	}
+	if f.CreatedFrom != nil && f.CreatedTo != nil && !f.CreatedFrom.Before(*f.CreatedTo) {
+		return coredomain.NewError(coredomain.CodeValidation, "created_to must be after created_from")
+	}
+	if f.MinTotal != nil && f.MaxTotal != nil && *f.MinTotal > *f.MaxTotal {
+		return coredomain.NewError(coredomain.CodeValidation, "max_total must not be below min_total")
+	}
+	if f.HasTotal() && !f.TotalCurrency.IsValid() {
+		return coredomain.NewError(coredomain.CodeValidation, "total bounds need a currency")
+	}
+	return nil
+}
+
+// OrderSummary is an order as listed: the order with its items, but without
+// adjustments, tax lines or payments. Total is the total fixed when it was
+// submitted, nil while it is open.
+type OrderSummary struct {
+	Order Order
+	Total *datatype.Money
 }
 
-func (i *OrderItem) LineTotalCents() int32 {
-	return i.Quantity * i.PriceCents
+// OrderPage is a page of an order listing, newest first. Next is the cursor
+// to pass for the following page, nil on the last one.
+type OrderPage struct {
+	Orders []OrderSummary
+	Next   *uuid.UUID
 }
@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type OrderEventType string
+
+const (
+	OrderEventOpened        OrderEventType = "order.opened"
+	OrderEventStatusChanged OrderEventType = "order.status_changed"
+)
+
+// OrderEvent records a change in an order's lifecycle. IDs are UUIDv7, so they
+// sort in the order the events were recorded.
+type OrderEvent struct {
+	ID             uuid.UUID
+	OrganizationID uuid.UUID
+	OrderID        uuid.UUID
+	OccurredAt     time.Time
+	Type           OrderEventType
+	Status         OrderStatus
+}
+
+// OrderEventSubscription is a live feed of the caller's order events.
+// Events is closed when the subscriber falls behind or the feed stops;
+// Close releases the subscription.
+type OrderEventSubscription struct {
+	Events <-chan OrderEvent
+	Close  func()
+}
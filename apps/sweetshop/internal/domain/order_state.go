@@ -0,0 +1,150 @@
+package domain
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+
+	coredomain "github.com/bbsbb/go-edge/core/domain"
+)
+
+// OrderAction moves an order from one status to another.
+type OrderAction string
+
+const (
+	OrderActionSubmit OrderAction = "submit"
+	OrderActionPay    OrderAction = "pay"
+	OrderActionFulfil OrderAction = "fulfil"
+	OrderActionCancel OrderAction = "cancel"
+)
+
+// orderTransition is one action of the order state machine. guard is checked
+// before the action's side effects, such as calls to the payment gateway;
+// payments lists the payment states the order may be in once they are done.
+type orderTransition struct {
+	from     []OrderStatus
+	to       OrderStatus
+	guard    func(*Order) error
+	payments []PaymentStatus
+}
+
+// orderTransitions is the order state machine:
+//
+//	open ─submit→ submitted ─pay→ paid ─fulfil→ fulfilled
+//	  └──────────────┴────────────┴─cancel→ cancelled
+//
+// The order_status_history table's CHECK constraint allows the same pairs.
+var orderTransitions = map[OrderAction]orderTransition{
+	OrderActionSubmit: {
+		from:     []OrderStatus{OrderStatusOpen},
+		to:       OrderStatusSubmitted,
+		guard:    requireItems,
+		payments: []PaymentStatus{PaymentStatusAuthorized},
+	},
+	OrderActionPay: {
+		from:     []OrderStatus{OrderStatusSubmitted},
+		to:       OrderStatusPaid,
+		payments: []PaymentStatus{PaymentStatusCaptured},
+	},
+	OrderActionFulfil: {
+		from:     []OrderStatus{OrderStatusPaid},
+		to:       OrderStatusFulfilled,
+		payments: []PaymentStatus{PaymentStatusCaptured},
+	},
+	OrderActionCancel: {
+		from:     []OrderStatus{OrderStatusOpen, OrderStatusSubmitted, OrderStatusPaid},
+		to:       OrderStatusCancelled,
+		payments: []PaymentStatus{PaymentStatusUnpaid, PaymentStatusVoided, PaymentStatusRefunded},
+	},
+}
+
+func requireItems(o *Order) error {
+	if len(o.Items) == 0 {
+		return coredomain.NewError(coredomain.CodeInvariant, "cannot submit an order without items")
+	}
+	return nil
+}
+
+// isFree reports whether the order's total is zero, as when promotions
+// discount every item in full. A free order moves through the lifecycle
+// without payments.
+func (o *Order) isFree() bool {
+	total, err := o.Total()
+	return err == nil && total.IsZero()
+}
+
+// OrderStatusChange is one entry of an order's status history. From is empty
+// for the entry recording the order being opened.
+type OrderStatusChange struct {
+	ID             uuid.UUID
+	OrganizationID uuid.UUID
+	OrderID        uuid.UUID
+	At             time.Time
+	From           OrderStatus
+	To             OrderStatus
+}
+
+// Opened returns the history entry recording the order being opened.
+func (o *Order) Opened() OrderStatusChange {
+	return OrderStatusChange{
+		ID:             uuid.Must(uuid.NewV7()),
+		OrganizationID: o.OrganizationID,
+		OrderID:        o.ID,
+		At:             o.CreatedAt,
+		To:             OrderStatusOpen,
+	}
+}
+
+// CanTransition reports whether action is allowed from the order's status
+// and passes its guard.
+func (o *Order) CanTransition(action OrderAction) error {
+	t, ok := orderTransitions[action]
+	if !ok {
+		return coredomain.NewError(coredomain.CodeValidation, "unknown order action")
+	}
+	if !slices.Contains(t.from, o.Status) {
+		return coredomain.NewError(coredomain.CodeInvariant,
+			fmt.Sprintf("cannot %s an order that is %s", action, o.Status))
+	}
+	if t.guard != nil {
+		return t.guard(o)
+	}
+	return nil
+}
+
+// Transition applies action at the given time and returns the history entry
+// for it. The action's payment calls must already have been made.
+func (o *Order) Transition(action OrderAction, at time.Time) (OrderStatusChange, error) {
+	if err := o.CanTransition(action); err != nil {
+		return OrderStatusChange{}, err
+	}
+	t := orderTransitions[action]
+	if payment := o.PaymentStatus(); !slices.Contains(t.payments, payment) && !o.isFree() {
+		return OrderStatusChange{}, coredomain.NewError(coredomain.CodeInvariant,
+			fmt.Sprintf("cannot %s an order whose payment is %s", action, payment))
+	}
+
+	change := OrderStatusChange{
+		ID:             uuid.Must(uuid.NewV7()),
+		OrganizationID: o.OrganizationID,
+		OrderID:        o.ID,
+		At:             at,
+		From:           o.Status,
+		To:             t.to,
+	}
+	o.Status = t.to
+	o.UpdatedAt = at
+	switch t.to {
+	case OrderStatusSubmitted:
+		o.SubmittedAt = &at
+	case OrderStatusPaid:
+		o.PaidAt = &at
+	case OrderStatusFulfilled:
+		o.FulfilledAt = &at
+	case OrderStatusCancelled:
+		o.CancelledAt = &at
+	}
+	return change, nil
+}
@@ -0,0 +1,18 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/bbsbb/go-edge/core/datatype"
+)
+
+// OrganizationSettings are an organization's shop preferences. Currency is
+// the one its products are priced in and new orders are opened in; changing
+// it leaves existing orders in the currency they were opened in.
+type OrganizationSettings struct {
+	OrganizationID uuid.UUID
+	UpdatedAt      time.Time
+	Currency       datatype.Currency
+}
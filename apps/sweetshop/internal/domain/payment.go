@@ -0,0 +1,86 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/bbsbb/go-edge/core/datatype"
+	coredomain "github.com/bbsbb/go-edge/core/domain"
+)
+
+// PaymentOperation is a call made to the payment gateway.
+type PaymentOperation string
+
+const (
+	PaymentOperationAuthorize PaymentOperation = "authorize"
+	PaymentOperationCapture   PaymentOperation = "capture"
+	PaymentOperationVoid      PaymentOperation = "void"
+	PaymentOperationRefund    PaymentOperation = "refund"
+)
+
+type PaymentAttemptStatus string
+
+const (
+	PaymentAttemptSucceeded PaymentAttemptStatus = "succeeded"
+	PaymentAttemptFailed    PaymentAttemptStatus = "failed"
+)
+
+// Payment records one call to the payment gateway for an order. Reference is
+// the provider's identifier for the authorization the call acted on, and is
+// empty when an authorization failed.
+type Payment struct {
+	ID             uuid.UUID
+	OrganizationID uuid.UUID
+	OrderID        uuid.UUID
+	CreatedAt      time.Time
+	Provider       string
+	Operation      PaymentOperation
+	Status         PaymentAttemptStatus
+	Amount         datatype.Money
+	Reference      string
+	Error          *string
+}
+
+func (p *Payment) Succeeded() bool {
+	return p.Status == PaymentAttemptSucceeded
+}
+
+// RecordOutcome marks the payment with the result of its gateway call.
+func (p *Payment) RecordOutcome(err error) {
+	if err == nil {
+		p.Status = PaymentAttemptSucceeded
+		p.Error = nil
+		return
+	}
+	msg := err.Error()
+	p.Status = PaymentAttemptFailed
+	p.Error = &msg
+}
+
+// NewPaymentInProgressError reports that the order is claimed by a payment
+// step, whose gateway calls are still being made.
+func NewPaymentInProgressError() error {
+	return coredomain.NewError(coredomain.CodeInvariant, "a payment for this order is in progress")
+}
+
+// PaymentStatus summarizes the successful payments of an order.
+type PaymentStatus string
+
+const (
+	PaymentStatusUnpaid     PaymentStatus = "unpaid"
+	PaymentStatusAuthorized PaymentStatus = "authorized"
+	PaymentStatusCaptured   PaymentStatus = "captured"
+	PaymentStatusVoided     PaymentStatus = "voided"
+	PaymentStatusRefunded   PaymentStatus = "refunded"
+)
+
+// PaymentDeclinedError is returned by a PaymentGateway when the provider
+// refuses an operation, as opposed to failing to process it.
+type PaymentDeclinedError struct {
+	Reason string
+}
+
+func (e *PaymentDeclinedError) Error() string {
+	return "payment declined: " + e.Reason
+}
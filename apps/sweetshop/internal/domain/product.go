@@ -2,21 +2,28 @@
 package domain
 
 import (
-	"slices"
+	"fmt"
+	"regexp"
 	"time"
 
 	"github.com/google/uuid"
+
+	"github.com/bbsbb/go-edge/core/datatype"
+	coredomain "github.com/bbsbb/go-edge/core/domain"
 )
 
+// ProductCategory is the slug of one of the organization's categories.
 type ProductCategory string
 
-const (
-	ProductCategoryIceCream    ProductCategory = "ice_cream"
-	ProductCategoryMarshmallow ProductCategory = "marshmallow"
-)
+// MaxCategorySlugLength is the longest slug a category can have.
+const MaxCategorySlugLength = 64
 
-func (c ProductCategory) IsValid() bool {
-	return slices.Contains([]ProductCategory{ProductCategoryIceCream, ProductCategoryMarshmallow}, c)
+var categorySlugPattern = regexp.MustCompile(`^[a-z0-9]+(_[a-z0-9]+)*$`)
+
+// IsWellFormed reports whether c can be a category's slug: lowercase letters
+// and digits, in words joined by single underscores.
+func (c ProductCategory) IsWellFormed() bool {
+	return len(c) <= MaxCategorySlugLength && categorySlugPattern.MatchString(string(c))
 }
 
 type Product struct {
@@ -26,5 +33,35 @@ type Product struct {
 	UpdatedAt      time.Time
 	Name           string
 	Category       ProductCategory
-	PriceCents     int32
+	Price          datatype.Money
+	// SKU is the organization's stock keeping unit for the product, unique
+	// among its live products; nil if it has none.
+	SKU         *string
+	Description string
+	// Active is false once the product is archived: it stays listed but can
+	// no longer be ordered.
+	Active    bool
+	SortOrder int32
+	DeletedAt *time.Time
+}
+
+// CheckFields checks the fields a product is created or updated with: its
+// price must be positive and in currency, the organization's, and its
+// category one of categories, the slugs of the organization's categories.
+func (p *Product) CheckFields(currency datatype.Currency, categories map[ProductCategory]bool) error {
+	if !p.Price.IsPositive() {
+		return coredomain.NewError(coredomain.CodeValidation, "price must be positive")
+	}
+	if p.Price.Currency() != currency {
+		return coredomain.NewError(coredomain.CodeValidation, "price must be in the organization currency "+string(currency))
+	}
+	if !categories[p.Category] {
+		return coredomain.NewError(coredomain.CodeValidation, fmt.Sprintf("category %q does not exist", p.Category))
+	}
+	return nil
+}
+
+// IsDeleted reports whether the product has been soft-deleted.
+func (p *Product) IsDeleted() bool {
+	return p.DeletedAt != nil
 }
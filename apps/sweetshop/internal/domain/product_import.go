@@ -0,0 +1,107 @@
+package domain
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+
+	coredomain "github.com/bbsbb/go-edge/core/domain"
+)
+
+// MaxProductImportRows bounds the rows of one product import.
+const MaxProductImportRows = 5000
+
+// ProductImportRow is the product read from line Line of an import, or the
+// error that kept it from being read.
+type ProductImportRow struct {
+	Line   int
+	Fields *Product
+	Err    error
+}
+
+// ProductImportError is why the row on line Line was not imported.
+type ProductImportError struct {
+	Line int
+	Err  error
+}
+
+// ProductImportReport tells what an import did, or would do when it is a dry
+// run. Rows counts every row read; Created and Updated count the valid ones.
+type ProductImportReport struct {
+	DryRun  bool
+	Rows    int
+	Created int
+	Updated int
+	Errors  []ProductImportError
+}
+
+// ProductImport matches the rows of an import to an organization's live
+// products. A row updates the product with its SKU or, failing that, the one
+// with its name, and creates a product otherwise.
+type ProductImport struct {
+	bySKU  map[string]*Product
+	byName map[string]*Product
+	// skuLines, nameLines and productLines are the lines that already
+	// claimed a SKU, a name or an existing product.
+	skuLines     map[string]int
+	nameLines    map[string]int
+	productLines map[uuid.UUID]int
+}
+
+func NewProductImport(existing []*Product) *ProductImport {
+	imp := &ProductImport{
+		bySKU:        make(map[string]*Product, len(existing)),
+		byName:       make(map[string]*Product, len(existing)),
+		skuLines:     map[string]int{},
+		nameLines:    map[string]int{},
+		productLines: map[uuid.UUID]int{},
+	}
+	for _, p := range existing {
+		imp.byName[p.Name] = p
+		if p.SKU != nil {
+			imp.bySKU[*p.SKU] = p
+		}
+	}
+	return imp
+}
+
+// Match returns the live product the row on line updates, or nil if it
+// creates one. It fails with a validation error when the row repeats the SKU,
+// name or product of an earlier row, or takes the name of a product other than
+// the one it updates; renaming products into each other's names is left to
+// Update.
+func (imp *ProductImport) Match(line int, fields *Product) (*Product, error) {
+	var target *Product
+	if fields.SKU != nil {
+		if earlier, ok := imp.skuLines[*fields.SKU]; ok {
+			return nil, coredomain.NewError(coredomain.CodeValidation,
+				fmt.Sprintf("SKU %q is already imported on line %d", *fields.SKU, earlier))
+		}
+		target = imp.bySKU[*fields.SKU]
+	}
+	if earlier, ok := imp.nameLines[fields.Name]; ok {
+		return nil, coredomain.NewError(coredomain.CodeValidation,
+			fmt.Sprintf("name %q is already imported on line %d", fields.Name, earlier))
+	}
+	named := imp.byName[fields.Name]
+	if target == nil {
+		target = named
+	}
+	if named != nil && named != target {
+		return nil, coredomain.NewError(coredomain.CodeValidation,
+			fmt.Sprintf("name %q is taken by another product", fields.Name))
+	}
+
+	if target != nil {
+		if earlier, ok := imp.productLines[target.ID]; ok {
+			return nil, coredomain.NewError(coredomain.CodeValidation,
+				fmt.Sprintf("product %q is already imported on line %d", target.Name, earlier))
+		}
+		imp.productLines[target.ID] = line
+	}
+	if fields.SKU != nil {
+		imp.skuLines[*fields.SKU] = line
+	}
+	imp.nameLines[fields.Name] = line
+	return target, nil
+}
@@ -0,0 +1,78 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+
+	coredomain "github.com/bbsbb/go-edge/core/domain"
+)
+
+type ProductImportSuite struct {
+	suite.Suite
+	rocky   *Product
+	vanilla *Product
+	imp     *ProductImport
+}
+
+func sku(s string) *string {
+	return &s
+}
+
+func (s *ProductImportSuite) SetupTest() {
+	s.rocky = &Product{ID: uuid.Must(uuid.NewV7()), Name: "Rocky Road", SKU: sku("IC-RR")}
+	s.vanilla = &Product{ID: uuid.Must(uuid.NewV7()), Name: "Vanilla"}
+	s.imp = NewProductImport([]*Product{s.rocky, s.vanilla})
+}
+
+func (s *ProductImportSuite) TestMatch() {
+	cases := []struct {
+		name   string
+		fields *Product
+		want   *Product
+	}{
+		{"by SKU, renamed", &Product{Name: "Rocky Road Deluxe", SKU: sku("IC-RR")}, s.rocky},
+		{"by name", &Product{Name: "Vanilla"}, s.vanilla},
+		{"by name, given a SKU", &Product{Name: "Vanilla", SKU: sku("IC-VA")}, s.vanilla},
+		{"new", &Product{Name: "Mint", SKU: sku("IC-MI")}, nil},
+	}
+	for _, tc := range cases {
+		s.Run(tc.name, func() {
+			got, err := NewProductImport([]*Product{s.rocky, s.vanilla}).Match(2, tc.fields)
+			s.Require().NoError(err)
+			s.Assert().Equal(tc.want, got)
+		})
+	}
+}
+
+func (s *ProductImportSuite) TestMatch_NameOfAnotherProduct() {
+	_, err := s.imp.Match(2, &Product{Name: "Vanilla", SKU: sku("IC-RR")})
+	s.Assert().ErrorIs(err, coredomain.ErrValidation)
+}
+
+func (s *ProductImportSuite) TestMatch_RepeatedRows() {
+	_, err := s.imp.Match(2, &Product{Name: "Mint", SKU: sku("IC-MI")})
+	s.Require().NoError(err)
+	_, err = s.imp.Match(3, &Product{Name: "Rocky Road Deluxe", SKU: sku("IC-RR")})
+	s.Require().NoError(err)
+
+	cases := []struct {
+		name   string
+		fields *Product
+	}{
+		{"same SKU", &Product{Name: "Mint Chip", SKU: sku("IC-MI")}},
+		{"same name", &Product{Name: "Mint"}},
+		{"same product", &Product{Name: "Rocky Road"}},
+	}
+	for _, tc := range cases {
+		s.Run(tc.name, func() {
+			_, err := s.imp.Match(4, tc.fields)
+			s.Assert().ErrorIs(err, coredomain.ErrValidation)
+		})
+	}
+}
+
+func TestProductImportSuite(t *testing.T) {
+	suite.Run(t, new(ProductImportSuite))
+}
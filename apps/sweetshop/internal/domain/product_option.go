@@ -0,0 +1,160 @@
+package domain
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/google/uuid"
+
+	"github.com/bbsbb/go-edge/core/datatype"
+	coredomain "github.com/bbsbb/go-edge/core/domain"
+)
+
+// OptionGroupKind says how the options of a group are chosen.
+type OptionGroupKind string
+
+const (
+	// OptionGroupVariant is a choice of exactly one option, such as a size.
+	OptionGroupVariant OptionGroupKind = "variant"
+	// OptionGroupModifier is a choice of between MinSelections and
+	// MaxSelections options, such as toppings.
+	OptionGroupModifier OptionGroupKind = "modifier"
+)
+
+func (k OptionGroupKind) IsValid() bool {
+	return k == OptionGroupVariant || k == OptionGroupModifier
+}
+
+// OptionGroup is a set of options chosen together when a product is ordered.
+// Groups and their options are listed by sort order.
+type OptionGroup struct {
+	ID             uuid.UUID
+	OrganizationID uuid.UUID
+	ProductID      uuid.UUID
+	Name           string
+	Kind           OptionGroupKind
+	MinSelections  int32
+	MaxSelections  int32
+	SortOrder      int32
+	Options        []ProductOption
+}
+
+// ProductOption is one choice of an option group. PriceDelta is added to the
+// product's price when it is chosen; it may be negative or zero.
+type ProductOption struct {
+	ID         uuid.UUID
+	GroupID    uuid.UUID
+	Name       string
+	PriceDelta datatype.Money
+	SortOrder  int32
+}
+
+// Validate checks that the group has options priced in currency, none of
+// them named twice, and selection bounds its options can meet. A variant
+// takes exactly one option.
+func (g *OptionGroup) Validate(currency datatype.Currency) error {
+	invalid := func(msg string) error {
+		return coredomain.NewError(coredomain.CodeValidation, fmt.Sprintf("option group %q: %s", g.Name, msg))
+	}
+	if !g.Kind.IsValid() {
+		return invalid("invalid kind")
+	}
+	if len(g.Options) == 0 {
+		return invalid("needs at least one option")
+	}
+	if g.Kind == OptionGroupVariant && (g.MinSelections != 1 || g.MaxSelections != 1) {
+		return invalid("a variant takes exactly one option")
+	}
+	if g.MinSelections < 0 || g.MaxSelections < 1 || g.MinSelections > g.MaxSelections {
+		return invalid("max_selections must be at least 1 and not below min_selections")
+	}
+	if int(g.MaxSelections) > len(g.Options) {
+		return invalid("max_selections is more than the group has options")
+	}
+	names := make(map[string]bool, len(g.Options))
+	for _, o := range g.Options {
+		if names[o.Name] {
+			return invalid(fmt.Sprintf("option %q is named twice", o.Name))
+		}
+		names[o.Name] = true
+		if o.PriceDelta.Currency() != currency {
+			return invalid(fmt.Sprintf("option %q is priced in %s but the organization uses %s", o.Name, o.PriceDelta.Currency(), currency))
+		}
+	}
+	return nil
+}
+
+// OrderItemOption is an option chosen for an order item, as it was when the
+// item was added.
+type OrderItemOption struct {
+	OptionID   uuid.UUID
+	Group      string
+	Name       string
+	PriceDelta datatype.Money
+}
+
+// SelectOptions returns the options with the given IDs from groups, in the
+// order of the groups and their options. Each ID must be an option of one of
+// the groups and be given once, and each group must get between its minimum
+// and maximum number of options.
+func SelectOptions(groups []OptionGroup, ids []uuid.UUID) ([]OrderItemOption, error) {
+	invalid := func(msg string) error {
+		return coredomain.NewError(coredomain.CodeValidation, msg)
+	}
+	chosen := make(map[uuid.UUID]bool, len(ids))
+	for _, id := range ids {
+		if chosen[id] {
+			return nil, invalid(fmt.Sprintf("option %s is chosen twice", id))
+		}
+		chosen[id] = true
+	}
+
+	var selected []OrderItemOption
+	for _, g := range groups {
+		var n int32
+		for _, o := range g.Options {
+			if !chosen[o.ID] {
+				continue
+			}
+			delete(chosen, o.ID)
+			n++
+			selected = append(selected, OrderItemOption{OptionID: o.ID, Group: g.Name, Name: o.Name, PriceDelta: o.PriceDelta})
+		}
+		switch {
+		case g.Kind == OptionGroupVariant && n != 1:
+			return nil, invalid(fmt.Sprintf("choose one option of %q", g.Name))
+		case n < g.MinSelections:
+			return nil, invalid(fmt.Sprintf("choose at least %d options of %q", g.MinSelections, g.Name))
+		case n > g.MaxSelections:
+			return nil, invalid(fmt.Sprintf("choose at most %d options of %q", g.MaxSelections, g.Name))
+		}
+	}
+	for _, id := range ids {
+		if chosen[id] {
+			return nil, invalid(fmt.Sprintf("option %s is not an option of the product", id))
+		}
+	}
+	return selected, nil
+}
+
+// PriceWith returns the product's price with the options' price deltas
+// added. The result must still be positive.
+func (p *Product) PriceWith(options []OrderItemOption) (datatype.Money, error) {
+	price := p.Price
+	for _, o := range options {
+		var err error
+		if price, err = price.Add(o.PriceDelta); err != nil {
+			return datatype.Money{}, coredomain.WrapError(coredomain.CodeInvariant, fmt.Sprintf("option %q cannot be priced", o.Name), err)
+		}
+	}
+	if !price.IsPositive() {
+		return datatype.Money{}, coredomain.NewError(coredomain.CodeInvariant, "price with the chosen options must be positive")
+	}
+	return price, nil
+}
+
+// sameOptions reports whether a and b are the same options in the same
+// order.
+func sameOptions(a, b []OrderItemOption) bool {
+	return slices.EqualFunc(a, b, func(x, y OrderItemOption) bool { return x.OptionID == y.OptionID })
+}
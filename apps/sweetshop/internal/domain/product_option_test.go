@@ -0,0 +1,140 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bbsbb/go-edge/core/datatype"
+	coredomain "github.com/bbsbb/go-edge/core/domain"
+)
+
+type ProductOptionSuite struct {
+	suite.Suite
+	size     OptionGroup
+	toppings OptionGroup
+}
+
+func option(name string, delta int64) ProductOption {
+	return ProductOption{ID: uuid.Must(uuid.NewV7()), Name: name, PriceDelta: eur(delta)}
+}
+
+func (s *ProductOptionSuite) SetupTest() {
+	s.size = OptionGroup{
+		Name: "Size", Kind: OptionGroupVariant, MinSelections: 1, MaxSelections: 1,
+		Options: []ProductOption{option("Small", -50), option("Medium", 0), option("Large", 100)},
+	}
+	s.toppings = OptionGroup{
+		Name: "Toppings", Kind: OptionGroupModifier, MinSelections: 0, MaxSelections: 2,
+		Options: []ProductOption{option("Sprinkles", 30), option("Fudge", 60), option("Nuts", 40)},
+	}
+}
+
+func (s *ProductOptionSuite) groups() []OptionGroup {
+	return []OptionGroup{s.size, s.toppings}
+}
+
+func (s *ProductOptionSuite) TestSelectOptions_OrdersByGroupAndOption() {
+	nuts, large, sprinkles := s.toppings.Options[2], s.size.Options[2], s.toppings.Options[0]
+
+	selected, err := SelectOptions(s.groups(), []uuid.UUID{nuts.ID, large.ID, sprinkles.ID})
+	s.Require().NoError(err)
+	s.Assert().Equal([]OrderItemOption{
+		{OptionID: large.ID, Group: "Size", Name: "Large", PriceDelta: eur(100)},
+		{OptionID: sprinkles.ID, Group: "Toppings", Name: "Sprinkles", PriceDelta: eur(30)},
+		{OptionID: nuts.ID, Group: "Toppings", Name: "Nuts", PriceDelta: eur(40)},
+	}, selected)
+}
+
+func (s *ProductOptionSuite) TestSelectOptions_NoGroups() {
+	selected, err := SelectOptions(nil, nil)
+	s.Require().NoError(err)
+	s.Assert().Empty(selected)
+}
+
+func (s *ProductOptionSuite) TestSelectOptions_Errors() {
+	small, large := s.size.Options[0].ID, s.size.Options[2].ID
+	sprinkles, fudge, nuts := s.toppings.Options[0].ID, s.toppings.Options[1].ID, s.toppings.Options[2].ID
+	cases := []struct {
+		name string
+		ids  []uuid.UUID
+	}{
+		{"no variant", []uuid.UUID{sprinkles}},
+		{"two variants", []uuid.UUID{small, large}},
+		{"too many modifiers", []uuid.UUID{small, sprinkles, fudge, nuts}},
+		{"chosen twice", []uuid.UUID{small, sprinkles, sprinkles}},
+		{"unknown option", []uuid.UUID{small, uuid.Must(uuid.NewV7())}},
+	}
+	for _, tc := range cases {
+		s.Run(tc.name, func() {
+			_, err := SelectOptions(s.groups(), tc.ids)
+			s.Assert().ErrorIs(err, coredomain.ErrValidation)
+		})
+	}
+}
+
+func (s *ProductOptionSuite) TestSelectOptions_MinimumModifiers() {
+	s.toppings.MinSelections = 1
+	_, err := SelectOptions(s.groups(), []uuid.UUID{s.size.Options[1].ID})
+	s.Assert().ErrorIs(err, coredomain.ErrValidation)
+}
+
+func (s *ProductOptionSuite) TestPriceWith() {
+	product := &Product{Price: eur(350)}
+	selected, err := SelectOptions(s.groups(), []uuid.UUID{s.size.Options[0].ID, s.toppings.Options[1].ID})
+	s.Require().NoError(err)
+
+	price, err := product.PriceWith(selected)
+	s.Require().NoError(err)
+	s.Assert().Equal(eur(360), price)
+
+	product.Price = eur(50)
+	_, err = product.PriceWith(selected[:1])
+	s.Assert().ErrorIs(err, coredomain.ErrInvariant, "a price that drops to zero is rejected")
+}
+
+func (s *ProductOptionSuite) TestValidate() {
+	s.Require().NoError(s.size.Validate("EUR"))
+	s.Require().NoError(s.toppings.Validate("EUR"))
+
+	cases := []struct {
+		name   string
+		change func(g *OptionGroup)
+	}{
+		{"unknown kind", func(g *OptionGroup) { g.Kind = "extra" }},
+		{"no options", func(g *OptionGroup) { g.Options = nil }},
+		{"variant of two", func(g *OptionGroup) { g.Kind, g.MinSelections = OptionGroupVariant, 2 }},
+		{"max below min", func(g *OptionGroup) { g.MinSelections = 2; g.MaxSelections = 1 }},
+		{"max above options", func(g *OptionGroup) { g.MaxSelections = 4 }},
+		{"no selections", func(g *OptionGroup) { g.MaxSelections = 0 }},
+		{"duplicate name", func(g *OptionGroup) { g.Options[1].Name = "Sprinkles" }},
+		{"other currency", func(g *OptionGroup) { g.Options[0].PriceDelta = datatype.MustMoney(30, "USD") }},
+	}
+	for _, tc := range cases {
+		s.Run(tc.name, func() {
+			g := s.toppings
+			g.Options = append([]ProductOption(nil), s.toppings.Options...)
+			tc.change(&g)
+			err := g.Validate("EUR")
+			s.Assert().ErrorIs(err, coredomain.ErrValidation)
+		})
+	}
+}
+
+func (s *ProductOptionSuite) TestLineFor_MatchesOptions() {
+	product := uuid.Must(uuid.NewV7())
+	small := OrderItemOption{OptionID: s.size.Options[0].ID, Name: "Small", PriceDelta: eur(0)}
+	large := OrderItemOption{OptionID: s.size.Options[2].ID, Name: "Large", PriceDelta: eur(0)}
+	o := &Order{Items: []OrderItem{
+		{ID: uuid.Must(uuid.NewV7()), ProductID: product, UnitPrice: eur(350), Options: []OrderItemOption{small}},
+	}}
+
+	s.Assert().Equal(&o.Items[0], o.LineFor(&OrderItem{ProductID: product, UnitPrice: eur(350), Options: []OrderItemOption{small}}))
+	s.Assert().Nil(o.LineFor(&OrderItem{ProductID: product, UnitPrice: eur(350), Options: []OrderItemOption{large}}))
+	s.Assert().Nil(o.LineFor(&OrderItem{ProductID: product, UnitPrice: eur(350)}))
+}
+
+func TestProductOptionSuite(t *testing.T) {
+	suite.Run(t, new(ProductOptionSuite))
+}
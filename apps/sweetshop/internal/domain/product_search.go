@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	coredomain "github.com/bbsbb/go-edge/core/domain"
+)
+
+// MaxProductSearchLength bounds the characters of a product search's text.
+const MaxProductSearchLength = 200
+
+// ProductSearch asks for the Limit live products that best match Text, such
+// as the start of a name typed at the till.
+type ProductSearch struct {
+	Text  string
+	Limit int32
+}
+
+// Terms returns the words of the text in lowercase. Anything other than a
+// letter or digit separates words.
+func (q *ProductSearch) Terms() []string {
+	return strings.FieldsFunc(strings.ToLower(q.Text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+func (q *ProductSearch) Validate() error {
+	if n := utf8.RuneCountInString(q.Text); n > MaxProductSearchLength {
+		return coredomain.NewError(coredomain.CodeValidation,
+			fmt.Sprintf("search text must be at most %d characters, got %d", MaxProductSearchLength, n))
+	}
+	if len(q.Terms()) == 0 {
+		return coredomain.NewError(coredomain.CodeValidation, "search text must contain a letter or digit")
+	}
+	return nil
+}
+
+// ProductMatch is a product found by a search. Rank scores how well its
+// name, SKU and description match the words searched for, and is zero when
+// only Similarity, the closest trigram similarity of a word of its name,
+// found it. The highlights are the name and an excerpt of the description,
+// HTML escaped, with the matched words wrapped in <mark> tags.
+type ProductMatch struct {
+	Product              *Product
+	Rank                 float32
+	Similarity           float32
+	NameHighlight        string
+	DescriptionHighlight string
+}
@@ -0,0 +1,48 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	coredomain "github.com/bbsbb/go-edge/core/domain"
+)
+
+type ProductSearchSuite struct {
+	suite.Suite
+}
+
+func (s *ProductSearchSuite) TestTerms() {
+	cases := []struct {
+		text string
+		want []string
+	}{
+		{"marsh", []string{"marsh"}},
+		{"  Rocky   ROAD ", []string{"rocky", "road"}},
+		{"IC-RR-01", []string{"ic", "rr", "01"}},
+		{"crème brûlée", []string{"crème", "brûlée"}},
+		{"fudge:* & !nuts", []string{"fudge", "nuts"}},
+		{"'|()", []string{}},
+	}
+	for _, tc := range cases {
+		s.Run(tc.text, func() {
+			q := ProductSearch{Text: tc.text}
+			s.Assert().Equal(tc.want, q.Terms())
+		})
+	}
+}
+
+func (s *ProductSearchSuite) TestValidate() {
+	s.Require().NoError((&ProductSearch{Text: "marsh"}).Validate())
+	s.Require().NoError((&ProductSearch{Text: strings.Repeat("é", MaxProductSearchLength)}).Validate())
+
+	for _, text := range []string{"", " - ", strings.Repeat("a", MaxProductSearchLength+1)} {
+		err := (&ProductSearch{Text: text}).Validate()
+		s.Assert().ErrorIs(err, coredomain.ErrValidation, text)
+	}
+}
+
+func TestProductSearchSuite(t *testing.T) {
+	suite.Run(t, new(ProductSearchSuite))
+}
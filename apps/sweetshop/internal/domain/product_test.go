@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bbsbb/go-edge/core/datatype"
+	coredomain "github.com/bbsbb/go-edge/core/domain"
+)
+
+type ProductSuite struct {
+	suite.Suite
+}
+
+func (s *ProductSuite) TestCheckFields() {
+	categories := map[ProductCategory]bool{categoryIceCream: true}
+	cases := []struct {
+		name    string
+		fields  Product
+		message string
+	}{
+		{"valid", Product{Category: categoryIceCream, Price: datatype.MustMoney(350, "EUR")}, ""},
+		{"zero price", Product{Category: categoryIceCream, Price: datatype.MustMoney(0, "EUR")}, "price must be positive"},
+		{"other currency", Product{Category: categoryIceCream, Price: datatype.MustMoney(350, "USD")}, "price must be in the organization currency EUR"},
+		{"unknown category", Product{Category: categoryMarshmallow, Price: datatype.MustMoney(350, "EUR")}, `category "marshmallow" does not exist`},
+	}
+	for _, tc := range cases {
+		s.Run(tc.name, func() {
+			err := tc.fields.CheckFields("EUR", categories)
+			if tc.message == "" {
+				s.Assert().NoError(err)
+				return
+			}
+			s.Assert().ErrorIs(err, coredomain.ErrValidation)
+			s.Assert().ErrorContains(err, tc.message)
+		})
+	}
+}
+
+func TestProductSuite(t *testing.T) {
+	suite.Run(t, new(ProductSuite))
+}
@@ -0,0 +1,305 @@
+package domain
+
+import (
+	"bytes"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/bbsbb/go-edge/core/datatype"
+	coredomain "github.com/bbsbb/go-edge/core/domain"
+)
+
+type PromotionKind string
+
+const (
+	// PromotionKindPercentage takes PercentOff percent off each eligible item.
+	PromotionKindPercentage PromotionKind = "percentage"
+	// PromotionKindFixedAmount takes AmountOff off the eligible items
+	// together, spread over them in proportion to their prices.
+	PromotionKindFixedAmount PromotionKind = "fixed_amount"
+	// PromotionKindBuyXGetY makes GetQuantity units free for every
+	// BuyQuantity units of the same product bought.
+	PromotionKindBuyXGetY PromotionKind = "buy_x_get_y"
+)
+
+func (k PromotionKind) IsValid() bool {
+	return slices.Contains([]PromotionKind{PromotionKindPercentage, PromotionKindFixedAmount, PromotionKindBuyXGetY}, k)
+}
+
+// Promotion is a discount rule of an organization. Only the fields of its
+// Kind are set. A promotion with a Category applies to items of that category
+// only; without one it applies to every item. It can be applied from StartsAt
+// until EndsAt, when set, and to at most UsageLimit orders, when set;
+// UsageCount is the number of submitted orders it was applied to.
+type Promotion struct {
+	ID             uuid.UUID
+	OrganizationID uuid.UUID
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	Name           string
+	Kind           PromotionKind
+	Category       *ProductCategory
+	PercentOff     int32
+	AmountOff      *datatype.Money
+	BuyQuantity    int32
+	GetQuantity    int32
+	StartsAt       *time.Time
+	EndsAt         *time.Time
+	UsageLimit     *int32
+	UsageCount     int32
+}
+
+// Validate checks that the promotion's fields fit its kind.
+func (p *Promotion) Validate() error {
+	invalid := func(msg string) error {
+		return coredomain.NewError(coredomain.CodeValidation, msg)
+	}
+	if p.Category != nil && !p.Category.IsWellFormed() {
+		return invalid("invalid product category")
+	}
+	switch p.Kind {
+	case PromotionKindPercentage:
+		if p.PercentOff < 1 || p.PercentOff > 100 {
+			return invalid("percent_off must be between 1 and 100")
+		}
+		if p.AmountOff != nil || p.BuyQuantity != 0 || p.GetQuantity != 0 {
+			return invalid("a percentage promotion only takes percent_off")
+		}
+	case PromotionKindFixedAmount:
+		if p.AmountOff == nil || !p.AmountOff.IsPositive() {
+			return invalid("amount_off must be positive")
+		}
+		if p.PercentOff != 0 || p.BuyQuantity != 0 || p.GetQuantity != 0 {
+			return invalid("a fixed amount promotion only takes amount_off")
+		}
+	case PromotionKindBuyXGetY:
+		if p.BuyQuantity < 1 || p.GetQuantity < 1 {
+			return invalid("buy_quantity and get_quantity must be positive")
+		}
+		if p.PercentOff != 0 || p.AmountOff != nil {
+			return invalid("a buy X get Y promotion only takes buy_quantity and get_quantity")
+		}
+	default:
+		return invalid("invalid promotion kind")
+	}
+	if p.StartsAt != nil && p.EndsAt != nil && !p.EndsAt.After(*p.StartsAt) {
+		return invalid("ends_at must be after starts_at")
+	}
+	if p.UsageLimit != nil && *p.UsageLimit < 1 {
+		return invalid("usage_limit must be positive")
+	}
+	return nil
+}
+
+// IsActiveAt reports whether the promotion can be applied to an order at the
+// given time: at is within its window and it has uses left.
+func (p *Promotion) IsActiveAt(at time.Time) bool {
+	if p.StartsAt != nil && at.Before(*p.StartsAt) {
+		return false
+	}
+	if p.EndsAt != nil && !at.Before(*p.EndsAt) {
+		return false
+	}
+	return p.UsageLimit == nil || p.UsageCount < *p.UsageLimit
+}
+
+func (p *Promotion) appliesTo(item *OrderItem) bool {
+	return p.Category == nil || *p.Category == item.ProductCategory
+}
+
+// OrderAdjustment is a change a promotion makes to an item's price. Amount is
+// negative. PromotionID is nil once the promotion has been deleted; the
+// adjustment keeps the promotion's name as its Description.
+type OrderAdjustment struct {
+	ID             uuid.UUID
+	OrganizationID uuid.UUID
+	OrderID        uuid.UUID
+	OrderItemID    uuid.UUID
+	PromotionID    *uuid.UUID
+	CreatedAt      time.Time
+	Description    string
+	Amount         datatype.Money
+}
+
+// ApplyPromotions replaces the order's adjustments with the ones the
+// promotions active at the given time give its items. Promotions apply in ID
+// order, each to what is left of an item's line total after the ones before
+// it, so an item never drops below zero. Fixed amounts in another currency
+// than the order's are skipped. The result only depends on the order, the
+// promotions and at.
+func (o *Order) ApplyPromotions(promotions []Promotion, at time.Time) error {
+	promotions = slices.Clone(promotions)
+	slices.SortFunc(promotions, func(a, b Promotion) int {
+		return bytes.Compare(a.ID[:], b.ID[:])
+	})
+
+	remaining := make([]datatype.Money, len(o.Items))
+	for i := range o.Items {
+		line, err := o.Items[i].LineTotal()
+		if err != nil {
+			return err
+		}
+		remaining[i] = line
+	}
+
+	var adjustments []OrderAdjustment
+	for _, p := range promotions {
+		if !p.IsActiveAt(at) {
+			continue
+		}
+		discounts, err := p.discounts(o.Items, remaining)
+		if err != nil {
+			return err
+		}
+		for i, discount := range discounts {
+			if !discount.IsPositive() {
+				continue
+			}
+			if remaining[i], err = remaining[i].Sub(discount); err != nil {
+				return err
+			}
+			amount, err := discount.Zero().Sub(discount)
+			if err != nil {
+				return err
+			}
+			adjustments = append(adjustments, OrderAdjustment{
+				ID:             uuid.Must(uuid.NewV7()),
+				OrganizationID: o.OrganizationID,
+				OrderID:        o.ID,
+				OrderItemID:    o.Items[i].ID,
+				PromotionID:    &p.ID,
+				CreatedAt:      at,
+				Description:    p.Name,
+				Amount:         amount,
+			})
+		}
+	}
+	o.Adjustments = adjustments
+	return nil
+}
+
+// discounts returns what the promotion takes off each item, given what is
+// left of the items' line totals. Items it does not apply to get zero.
+func (p *Promotion) discounts(items []OrderItem, remaining []datatype.Money) ([]datatype.Money, error) {
+	discounts := make([]datatype.Money, len(items))
+	for i := range discounts {
+		discounts[i] = remaining[i].Zero()
+	}
+
+	switch p.Kind {
+	case PromotionKindPercentage:
+		// Allocate hands the unit lost to rounding to the first part, so the
+		// discount is rounded up.
+		for i := range items {
+			if !p.appliesTo(&items[i]) {
+				continue
+			}
+			parts, err := remaining[i].Allocate(int64(p.PercentOff), int64(100-p.PercentOff))
+			if err != nil {
+				return nil, err
+			}
+			discounts[i] = parts[0]
+		}
+
+	case PromotionKindFixedAmount:
+		if len(items) == 0 || p.AmountOff == nil || p.AmountOff.Currency() != remaining[0].Currency() {
+			return discounts, nil
+		}
+		eligible := remaining[0].Zero()
+		ratios := make([]int64, len(items))
+		for i := range items {
+			if !p.appliesTo(&items[i]) {
+				continue
+			}
+			var err error
+			if eligible, err = eligible.Add(remaining[i]); err != nil {
+				return nil, err
+			}
+			ratios[i] = remaining[i].Amount()
+		}
+		if !eligible.IsPositive() {
+			return discounts, nil
+		}
+		off := *p.AmountOff
+		if cmp, err := off.Cmp(eligible); err != nil {
+			return nil, err
+		} else if cmp > 0 {
+			off = eligible
+		}
+		return off.Allocate(ratios...)
+
+	case PromotionKindBuyXGetY:
+		// Free units are counted per product across its items and handed to
+		// the items in order.
+		bought := map[uuid.UUID]int64{}
+		for i := range items {
+			if p.appliesTo(&items[i]) {
+				bought[items[i].ProductID] += int64(items[i].Quantity)
+			}
+		}
+		group := int64(p.BuyQuantity) + int64(p.GetQuantity)
+		free := map[uuid.UUID]int64{}
+		for product, quantity := range bought {
+			free[product] = quantity / group * int64(p.GetQuantity)
+		}
+		for i := range items {
+			left := free[items[i].ProductID]
+			if left == 0 || !p.appliesTo(&items[i]) {
+				continue
+			}
+			units := min(left, int64(items[i].Quantity))
+			free[items[i].ProductID] -= units
+			discount, err := items[i].UnitPrice.Mul(units)
+			if err != nil {
+				return nil, err
+			}
+			if cmp, err := discount.Cmp(remaining[i]); err != nil {
+				return nil, err
+			} else if cmp > 0 {
+				discount = remaining[i]
+			}
+			discounts[i] = discount
+		}
+	}
+	return discounts, nil
+}
+
+// PromotionSettlement is what an order status change does to the promotions
+// applied to the order.
+type PromotionSettlement string
+
+const (
+	// PromotionsHeld leaves the order's adjustments and the promotions' usage
+	// as they are.
+	PromotionsHeld PromotionSettlement = "held"
+	// PromotionsRedeemed stores the order's adjustments, which no longer
+	// change, and counts a use of each promotion they come from.
+	PromotionsRedeemed PromotionSettlement = "redeemed"
+	// PromotionsReleased gives the uses counted for the order back.
+	PromotionsReleased PromotionSettlement = "released"
+)
+
+func (c OrderStatusChange) PromotionSettlement() PromotionSettlement {
+	switch {
+	case c.To == OrderStatusSubmitted:
+		return PromotionsRedeemed
+	case c.To == OrderStatusCancelled && c.From != OrderStatusOpen:
+		return PromotionsReleased
+	default:
+		return PromotionsHeld
+	}
+}
+
+// RedeemedPromotions returns the IDs of the promotions the order's
+// adjustments come from, each once.
+func (o *Order) RedeemedPromotions() []uuid.UUID {
+	var ids []uuid.UUID
+	for _, a := range o.Adjustments {
+		if a.PromotionID != nil && !slices.Contains(ids, *a.PromotionID) {
+			ids = append(ids, *a.PromotionID)
+		}
+	}
+	return ids
+}
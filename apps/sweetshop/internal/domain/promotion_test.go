@@ -0,0 +1,285 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bbsbb/go-edge/core/datatype"
+)
+
+// The categories the test orders' products belong to.
+const (
+	categoryIceCream    ProductCategory = "ice_cream"
+	categoryMarshmallow ProductCategory = "marshmallow"
+)
+
+type PromotionSuite struct {
+	suite.Suite
+	now         time.Time
+	iceCream    uuid.UUID
+	marshmallow uuid.UUID
+}
+
+func (s *PromotionSuite) SetupTest() {
+	s.now = time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	s.iceCream = uuid.Must(uuid.NewV7())
+	s.marshmallow = uuid.Must(uuid.NewV7())
+}
+
+func eur(amount int64) datatype.Money {
+	return datatype.MustMoney(amount, "EUR")
+}
+
+func (s *PromotionSuite) order(items ...OrderItem) *Order {
+	o := &Order{ID: uuid.Must(uuid.NewV7()), Status: OrderStatusOpen, Currency: "EUR"}
+	for _, item := range items {
+		item.ID = uuid.Must(uuid.NewV7())
+		item.OrderID = o.ID
+		o.Items = append(o.Items, item)
+	}
+	return o
+}
+
+func (s *PromotionSuite) iceCreams(quantity int32, unitPrice int64) OrderItem {
+	return OrderItem{ProductID: s.iceCream, ProductCategory: categoryIceCream, Quantity: quantity, UnitPrice: eur(unitPrice)}
+}
+
+func (s *PromotionSuite) marshmallows(quantity int32, unitPrice int64) OrderItem {
+	return OrderItem{ProductID: s.marshmallow, ProductCategory: categoryMarshmallow, Quantity: quantity, UnitPrice: eur(unitPrice)}
+}
+
+func promotion(p Promotion) Promotion {
+	p.ID = uuid.Must(uuid.NewV7())
+	if p.Name == "" {
+		p.Name = string(p.Kind)
+	}
+	return p
+}
+
+// discounts returns the adjustment amounts per item, in item order.
+func discounts(o *Order) [][]int64 {
+	out := make([][]int64, len(o.Items))
+	for i, item := range o.Items {
+		for _, a := range o.ItemAdjustments(item.ID) {
+			out[i] = append(out[i], a.Amount.Amount())
+		}
+	}
+	return out
+}
+
+func (s *PromotionSuite) total(o *Order) int64 {
+	total, err := o.Total()
+	s.Require().NoError(err)
+	return total.Amount()
+}
+
+func (s *PromotionSuite) TestPercentage() {
+	o := s.order(s.iceCreams(2, 350), s.marshmallows(1, 200))
+	s.Require().NoError(o.ApplyPromotions([]Promotion{
+		promotion(Promotion{Kind: PromotionKindPercentage, PercentOff: 10}),
+	}, s.now))
+
+	s.Assert().Equal([][]int64{{-70}, {-20}}, discounts(o))
+	s.Assert().Equal(int64(810), s.total(o))
+}
+
+func (s *PromotionSuite) TestPercentage_RoundsDiscountUp() {
+	o := s.order(s.iceCreams(1, 355))
+	s.Require().NoError(o.ApplyPromotions([]Promotion{
+		promotion(Promotion{Kind: PromotionKindPercentage, PercentOff: 10}),
+	}, s.now))
+
+	s.Assert().Equal([][]int64{{-36}}, discounts(o))
+}
+
+func (s *PromotionSuite) TestCategoryScope() {
+	category := categoryMarshmallow
+	o := s.order(s.iceCreams(2, 350), s.marshmallows(1, 200))
+	s.Require().NoError(o.ApplyPromotions([]Promotion{
+		promotion(Promotion{Kind: PromotionKindPercentage, PercentOff: 50, Category: &category}),
+	}, s.now))
+
+	s.Assert().Equal([][]int64{nil, {-100}}, discounts(o))
+}
+
+func (s *PromotionSuite) TestFixedAmount_SpreadByLineTotal() {
+	off := eur(100)
+	o := s.order(s.iceCreams(2, 350), s.marshmallows(1, 300))
+	s.Require().NoError(o.ApplyPromotions([]Promotion{
+		promotion(Promotion{Kind: PromotionKindFixedAmount, AmountOff: &off}),
+	}, s.now))
+
+	s.Assert().Equal([][]int64{{-70}, {-30}}, discounts(o))
+	s.Assert().Equal(int64(900), s.total(o))
+}
+
+func (s *PromotionSuite) TestFixedAmount_CappedAtEligibleTotal() {
+	off := eur(5000)
+	category := categoryMarshmallow
+	o := s.order(s.iceCreams(1, 350), s.marshmallows(2, 200))
+	s.Require().NoError(o.ApplyPromotions([]Promotion{
+		promotion(Promotion{Kind: PromotionKindFixedAmount, AmountOff: &off, Category: &category}),
+	}, s.now))
+
+	s.Assert().Equal([][]int64{nil, {-400}}, discounts(o))
+	s.Assert().Equal(int64(350), s.total(o))
+}
+
+func (s *PromotionSuite) TestFixedAmount_OtherCurrencySkipped() {
+	off := datatype.MustMoney(100, "USD")
+	o := s.order(s.iceCreams(1, 350))
+	s.Require().NoError(o.ApplyPromotions([]Promotion{
+		promotion(Promotion{Kind: PromotionKindFixedAmount, AmountOff: &off}),
+	}, s.now))
+
+	s.Assert().Empty(o.Adjustments)
+}
+
+func (s *PromotionSuite) TestBuyXGetY() {
+	cases := []struct {
+		name     string
+		items    []OrderItem
+		expected [][]int64
+	}{
+		{"one pair", []OrderItem{s.marshmallows(2, 200)}, [][]int64{{-200}}},
+		{"odd unit pays", []OrderItem{s.marshmallows(3, 200)}, [][]int64{{-200}}},
+		{"two pairs", []OrderItem{s.marshmallows(4, 200)}, [][]int64{{-400}}},
+		{"counted across items", []OrderItem{s.marshmallows(1, 200), s.marshmallows(1, 200)}, [][]int64{{-200}, nil}},
+		{"not across products", []OrderItem{s.marshmallows(1, 200), s.iceCreams(1, 350)}, [][]int64{nil, nil}},
+	}
+	for _, tc := range cases {
+		s.Run(tc.name, func() {
+			o := s.order(tc.items...)
+			s.Require().NoError(o.ApplyPromotions([]Promotion{
+				promotion(Promotion{Kind: PromotionKindBuyXGetY, BuyQuantity: 1, GetQuantity: 1}),
+			}, s.now))
+			s.Assert().Equal(tc.expected, discounts(o))
+		})
+	}
+}
+
+func (s *PromotionSuite) TestStacking_NeverBelowZero() {
+	off := eur(1000)
+	o := s.order(s.iceCreams(2, 350))
+	s.Require().NoError(o.ApplyPromotions([]Promotion{
+		promotion(Promotion{Kind: PromotionKindPercentage, PercentOff: 50}),
+		promotion(Promotion{Kind: PromotionKindFixedAmount, AmountOff: &off}),
+		promotion(Promotion{Kind: PromotionKindPercentage, PercentOff: 10}),
+	}, s.now))
+
+	s.Assert().Equal([][]int64{{-350, -350}}, discounts(o))
+	s.Assert().Equal(int64(0), s.total(o))
+	s.Assert().True(o.isFree())
+}
+
+func (s *PromotionSuite) TestDeterministicOrder() {
+	off := eur(100)
+	first := promotion(Promotion{Kind: PromotionKindFixedAmount, AmountOff: &off})
+	second := promotion(Promotion{Kind: PromotionKindPercentage, PercentOff: 50})
+
+	a := s.order(s.iceCreams(1, 350))
+	s.Require().NoError(a.ApplyPromotions([]Promotion{first, second}, s.now))
+	b := s.order(s.iceCreams(1, 350))
+	s.Require().NoError(b.ApplyPromotions([]Promotion{second, first}, s.now))
+
+	s.Assert().Equal([][]int64{{-100, -125}}, discounts(a))
+	s.Assert().Equal(discounts(a), discounts(b))
+}
+
+func (s *PromotionSuite) TestInactivePromotionsSkipped() {
+	later := s.now.Add(time.Hour)
+	earlier := s.now.Add(-time.Hour)
+	limit := int32(3)
+	o := s.order(s.iceCreams(1, 350))
+	s.Require().NoError(o.ApplyPromotions([]Promotion{
+		promotion(Promotion{Kind: PromotionKindPercentage, PercentOff: 10, StartsAt: &later}),
+		promotion(Promotion{Kind: PromotionKindPercentage, PercentOff: 10, EndsAt: &s.now}),
+		promotion(Promotion{Kind: PromotionKindPercentage, PercentOff: 10, UsageLimit: &limit, UsageCount: 3}),
+	}, s.now))
+	s.Assert().Empty(o.Adjustments)
+
+	s.Require().NoError(o.ApplyPromotions([]Promotion{
+		promotion(Promotion{Kind: PromotionKindPercentage, PercentOff: 10, StartsAt: &earlier, EndsAt: &later, UsageLimit: &limit, UsageCount: 2}),
+	}, s.now))
+	s.Assert().Len(o.Adjustments, 1)
+}
+
+func (s *PromotionSuite) TestRedeemedPromotions() {
+	p := promotion(Promotion{Kind: PromotionKindPercentage, PercentOff: 10})
+	o := s.order(s.iceCreams(1, 350), s.marshmallows(1, 200))
+	s.Require().NoError(o.ApplyPromotions([]Promotion{p}, s.now))
+
+	s.Assert().Len(o.Adjustments, 2)
+	s.Assert().Equal([]uuid.UUID{p.ID}, o.RedeemedPromotions())
+}
+
+func (s *PromotionSuite) TestValidate() {
+	off := eur(100)
+	zero := eur(0)
+	limit := int32(0)
+	category := ProductCategory("Candy Floss")
+	later := s.now.Add(time.Hour)
+
+	valid := []Promotion{
+		{Kind: PromotionKindPercentage, PercentOff: 100},
+		{Kind: PromotionKindFixedAmount, AmountOff: &off},
+		{Kind: PromotionKindBuyXGetY, BuyQuantity: 2, GetQuantity: 1},
+		{Kind: PromotionKindPercentage, PercentOff: 5, StartsAt: &s.now, EndsAt: &later},
+	}
+	for _, p := range valid {
+		s.Assert().NoError(p.Validate(), p.Kind)
+	}
+
+	invalid := map[string]Promotion{
+		"unknown kind":        {Kind: "bogo"},
+		"percent over 100":    {Kind: PromotionKindPercentage, PercentOff: 101},
+		"percent missing":     {Kind: PromotionKindPercentage},
+		"percent with amount": {Kind: PromotionKindPercentage, PercentOff: 10, AmountOff: &off},
+		"zero amount":         {Kind: PromotionKindFixedAmount, AmountOff: &zero},
+		"amount missing":      {Kind: PromotionKindFixedAmount},
+		"get missing":         {Kind: PromotionKindBuyXGetY, BuyQuantity: 1},
+		"unknown category":    {Kind: PromotionKindPercentage, PercentOff: 10, Category: &category},
+		"window reversed":     {Kind: PromotionKindPercentage, PercentOff: 10, StartsAt: &later, EndsAt: &s.now},
+		"zero usage limit":    {Kind: PromotionKindPercentage, PercentOff: 10, UsageLimit: &limit},
+	}
+	for name, p := range invalid {
+		s.Assert().Error(p.Validate(), name)
+	}
+}
+
+func (s *PromotionSuite) TestSettlement() {
+	cases := []struct {
+		from, to OrderStatus
+		expected PromotionSettlement
+	}{
+		{OrderStatusOpen, OrderStatusSubmitted, PromotionsRedeemed},
+		{OrderStatusSubmitted, OrderStatusPaid, PromotionsHeld},
+		{OrderStatusOpen, OrderStatusCancelled, PromotionsHeld},
+		{OrderStatusSubmitted, OrderStatusCancelled, PromotionsReleased},
+		{OrderStatusPaid, OrderStatusCancelled, PromotionsReleased},
+	}
+	for _, tc := range cases {
+		change := OrderStatusChange{From: tc.from, To: tc.to}
+		s.Assert().Equal(tc.expected, change.PromotionSettlement(), "%s -> %s", tc.from, tc.to)
+	}
+}
+
+func (s *PromotionSuite) TestFreeOrderNeedsNoPayment() {
+	o := s.order(s.iceCreams(1, 350))
+	s.Require().NoError(o.ApplyPromotions([]Promotion{
+		promotion(Promotion{Kind: PromotionKindPercentage, PercentOff: 100}),
+	}, s.now))
+
+	for _, action := range []OrderAction{OrderActionSubmit, OrderActionPay, OrderActionFulfil} {
+		_, err := o.Transition(action, s.now)
+		s.Require().NoError(err, action)
+	}
+	s.Assert().Equal(OrderStatusFulfilled, o.Status)
+}
+
+func TestPromotionSuite(t *testing.T) {
+	suite.Run(t, new(PromotionSuite))
+}
@@ -0,0 +1,148 @@
+package domain
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/bbsbb/go-edge/core/datatype"
+	coredomain "github.com/bbsbb/go-edge/core/domain"
+)
+
+// MaxSalesReportDays bounds the days one report covers.
+const MaxSalesReportDays = 1096
+
+// SalesGranularity is the length of the periods a sales report is broken into.
+type SalesGranularity string
+
+const (
+	SalesByDay   SalesGranularity = "day"
+	SalesByWeek  SalesGranularity = "week"
+	SalesByMonth SalesGranularity = "month"
+)
+
+func (g SalesGranularity) IsValid() bool {
+	return slices.Contains([]SalesGranularity{SalesByDay, SalesByWeek, SalesByMonth}, g)
+}
+
+// TopProductsMetric is what products are ranked by.
+type TopProductsMetric string
+
+const (
+	TopProductsByUnits   TopProductsMetric = "units"
+	TopProductsByRevenue TopProductsMetric = "revenue"
+)
+
+func (m TopProductsMetric) IsValid() bool {
+	return slices.Contains([]TopProductsMetric{TopProductsByUnits, TopProductsByRevenue}, m)
+}
+
+// SalesQuery asks for the sales from From to To, both included, broken into
+// periods of Granularity. Days are UTC dates at midnight.
+type SalesQuery struct {
+	From        time.Time
+	To          time.Time
+	Granularity SalesGranularity
+}
+
+func (q *SalesQuery) Validate() error {
+	if !q.Granularity.IsValid() {
+		return coredomain.NewError(coredomain.CodeValidation, fmt.Sprintf("granularity %q is not one of day, week or month", q.Granularity))
+	}
+	return validateSalesDays(q.From, q.To)
+}
+
+// TopProductsQuery asks for the Limit products that sold the most in Currency
+// from From to To, both included.
+type TopProductsQuery struct {
+	From     time.Time
+	To       time.Time
+	Currency datatype.Currency
+	By       TopProductsMetric
+	Limit    int32
+}
+
+func (q *TopProductsQuery) Validate() error {
+	if !q.By.IsValid() {
+		return coredomain.NewError(coredomain.CodeValidation, fmt.Sprintf("by %q is not one of units or revenue", q.By))
+	}
+	if !q.Currency.IsValid() {
+		return coredomain.NewError(coredomain.CodeValidation, "top products need a currency")
+	}
+	return validateSalesDays(q.From, q.To)
+}
+
+func validateSalesDays(from, to time.Time) error {
+	if to.Before(from) {
+		return coredomain.NewError(coredomain.CodeValidation, "to must not be before from")
+	}
+	if SalesDays(from, to) > MaxSalesReportDays {
+		return coredomain.NewError(coredomain.CodeValidation, fmt.Sprintf("a report covers at most %d days", MaxSalesReportDays))
+	}
+	return nil
+}
+
+// SalesDay is the UTC date of t, at midnight.
+func SalesDay(t time.Time) time.Time {
+	y, m, d := t.UTC().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// SalesDays is the number of days from from to to, both included.
+func SalesDays(from, to time.Time) int {
+	return int(SalesDay(to).Sub(SalesDay(from))/(24*time.Hour)) + 1
+}
+
+// SalesPeriod is what an organization sold in one currency over a period that
+// starts on Start. Orders are the orders fulfilled in it, at the price fixed
+// when they were submitted: Discount is negative and Revenue is what their
+// customers paid. Refunded is what was refunded in the period, on orders
+// fulfilled in it or before.
+type SalesPeriod struct {
+	Start    time.Time
+	Orders   int64
+	Units    int64
+	Subtotal datatype.Money
+	Discount datatype.Money
+	Tax      datatype.Money
+	Revenue  datatype.Money
+	Refunded datatype.Money
+}
+
+// NetRevenue is the revenue less refunds.
+func (p *SalesPeriod) NetRevenue() (datatype.Money, error) {
+	return p.Revenue.Sub(p.Refunded)
+}
+
+// AverageOrderValue is the revenue per order, rounded down; zero for a period
+// with refunds only.
+func (p *SalesPeriod) AverageOrderValue() (datatype.Money, error) {
+	if p.Orders == 0 {
+		return p.Revenue.Zero(), nil
+	}
+	return datatype.NewMoney(p.Revenue.Amount()/p.Orders, p.Revenue.Currency())
+}
+
+// SalesReport is the answer to a SalesQuery, with its defaults filled in.
+type SalesReport struct {
+	SalesQuery
+	Periods []SalesPeriod
+}
+
+// TopProductsReport is the answer to a TopProductsQuery, with its defaults
+// filled in.
+type TopProductsReport struct {
+	TopProductsQuery
+	Products []ProductSales
+}
+
+// ProductSales is what one product sold over a report's days. Revenue is
+// what its lines came to after their discounts.
+type ProductSales struct {
+	ProductID   uuid.UUID
+	ProductName string
+	Units       int64
+	Revenue     datatype.Money
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/google/uuid"
 
+	"github.com/bbsbb/go-edge/core/datatype"
 	coredomain "github.com/bbsbb/go-edge/core/domain"
 )
 
@@ -13,6 +14,9 @@ type OrganizationRepository interface {
 	FindByID(ctx context.Context, id uuid.UUID) (*coredomain.Organization, error)
 	FindBySlug(ctx context.Context, slug string) (*coredomain.Organization, error)
 	Create(ctx context.Context, org *coredomain.Organization) error
+	List(ctx context.Context) ([]*coredomain.Organization, error)
+	FindSettings(ctx context.Context, id uuid.UUID) (*OrganizationSettings, error)
+	UpdateSettings(ctx context.Context, settings *OrganizationSettings) error
 }
 
 type ProductRepository interface {
@@ -20,13 +24,192 @@ type ProductRepository interface {
 	List(ctx context.Context) ([]*Product, error)
 	Create(ctx context.Context, product *Product) error
 	Update(ctx context.Context, product *Product) error
+	Delete(ctx context.Context, id uuid.UUID, deletedAt time.Time) error
+	Restore(ctx context.Context, id uuid.UUID, restoredAt time.Time) (*Product, error)
+	PurgeDeleted(ctx context.Context, deletedBefore time.Time) (int64, error)
+	// Search returns the live products matching query, best match first.
+	Search(ctx context.Context, query ProductSearch) ([]ProductMatch, error)
+	// Upsert creates the products that do not exist and updates the live ones
+	// that do, all or none of them. It returns a conflict error if one of the
+	// products was deleted since it was read.
+	Upsert(ctx context.Context, products []*Product) error
+	// Each calls fn with every live product, in the order of List, as it is
+	// read, stopping at the first error fn returns.
+	Each(ctx context.Context, fn func(*Product) error) error
+}
+
+type CategoryRepository interface {
+	FindByID(ctx context.Context, id uuid.UUID) (*Category, error)
+	FindBySlug(ctx context.Context, slug ProductCategory) (*Category, error)
+	// List returns the organization's categories by sort order, then name.
+	List(ctx context.Context) ([]*Category, error)
+	Create(ctx context.Context, category *Category) error
+	Update(ctx context.Context, category *Category) error
+	// Delete fails with a conflict error while products or promotions use the
+	// category.
 	Delete(ctx context.Context, id uuid.UUID) error
 }
 
+type ProductOptionRepository interface {
+	// ListByProductID returns the product's option groups with their options,
+	// by sort order.
+	ListByProductID(ctx context.Context, productID uuid.UUID) ([]OptionGroup, error)
+	// Replace replaces the product's option groups and their options with
+	// groups.
+	Replace(ctx context.Context, productID uuid.UUID, groups []OptionGroup) error
+}
+
 type OrderRepository interface {
 	FindByID(ctx context.Context, id uuid.UUID) (*Order, error)
 	Create(ctx context.Context, order *Order) error
-	Close(ctx context.Context, id uuid.UUID, updatedAt time.Time) (*Order, error)
+	// Lock runs fn in a transaction that holds the order's row locked, or
+	// returns a not-found error. Repository calls made with fn's context join
+	// the transaction, which commits if fn returns nil.
+	Lock(ctx context.Context, id uuid.UUID, fn func(ctx context.Context) error) error
+	// Transition stores the order's new status and timestamps together with
+	// the history entry, provided the stored status is still change.From. It
+	// returns a conflict error otherwise. The stock reserved by the order's
+	// items is settled as change.StockSettlement says, and the order's
+	// adjustments and promotion uses as change.PromotionSettlement says, in
+	// the same transaction. A change that FixesPrice also stores the order's
+	// tax and totals.
+	Transition(ctx context.Context, order *Order, change OrderStatusChange) error
+	ListStatusHistory(ctx context.Context, orderID uuid.UUID) ([]OrderStatusChange, error)
+	// List returns up to limit orders matching filter with their items, newest
+	// first, starting after the order with ID after when it is set. It reads
+	// the page in one query.
+	List(ctx context.Context, filter OrderFilter, after *uuid.UUID, limit int32) (*OrderPage, error)
+	// CreateItem stores the item and, when its product's stock is tracked,
+	// reserves the item's quantity under a lock on the product's inventory,
+	// setting ReservedQuantity. It returns an invariant error if not enough
+	// stock is available, and a conflict error if the order got a line the
+	// item merges into since it was read.
+	//
+	// CreateItem, UpdateItemQuantity and DeleteItem lock the order while they
+	// run and return an invariant error once it is no longer open, or while a
+	// payment step has it claimed.
 	CreateItem(ctx context.Context, item *OrderItem) error
+	// UpdateItemQuantity changes the item to quantity units, reserving or
+	// releasing the difference, and updates item. It returns a conflict error
+	// if the item changed since it was read.
+	UpdateItemQuantity(ctx context.Context, item *OrderItem, quantity int32, at time.Time) error
+	// DeleteItem removes the item and releases the stock it holds. It
+	// returns a not-found error if the item no longer exists.
+	DeleteItem(ctx context.Context, item *OrderItem, at time.Time) error
 	ListItemsByOrderID(ctx context.Context, orderID uuid.UUID) ([]OrderItem, error)
 }
+
+type InventoryRepository interface {
+	// FindByProductID returns the product's inventory, or a not-found error
+	// when its stock is not tracked.
+	FindByProductID(ctx context.Context, productID uuid.UUID) (*Inventory, error)
+	// Adjust applies the adjustment to its product's inventory under a row
+	// lock, starting to track the product's stock if it was not, and records
+	// it. It returns the inventory as it is afterwards.
+	Adjust(ctx context.Context, adjustment *StockAdjustment) (*Inventory, error)
+	// ListAdjustments returns the product's most recent adjustments, newest
+	// first.
+	ListAdjustments(ctx context.Context, productID uuid.UUID, limit int32) ([]StockAdjustment, error)
+}
+
+type PromotionRepository interface {
+	FindByID(ctx context.Context, id uuid.UUID) (*Promotion, error)
+	List(ctx context.Context) ([]*Promotion, error)
+	// ListActive returns the promotions that can be applied at the given time.
+	ListActive(ctx context.Context, at time.Time) ([]Promotion, error)
+	Create(ctx context.Context, promotion *Promotion) error
+	Update(ctx context.Context, promotion *Promotion) error
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+type TaxProfileRepository interface {
+	// Find returns the organization's tax profile, or a not-found error if it
+	// has not set one.
+	Find(ctx context.Context, organizationID uuid.UUID) (*TaxProfile, error)
+	// Save creates or replaces the organization's tax profile and its rates.
+	Save(ctx context.Context, profile *TaxProfile) error
+}
+
+type PaymentRepository interface {
+	Create(ctx context.Context, payment *Payment) error
+	ListByOrderID(ctx context.Context, orderID uuid.UUID) ([]Payment, error)
+	// Claim keeps other payment steps and item changes off the order until
+	// until, unless a claim on it with another token is held past now. It
+	// reports whether the claim was taken; claiming again with the same
+	// token extends it.
+	Claim(ctx context.Context, order *Order, token uuid.UUID, now, until time.Time) (bool, error)
+	// Release ends the order's claim made with token, if it is still held.
+	Release(ctx context.Context, orderID, token uuid.UUID) error
+}
+
+// PaymentGateway is a payment provider. Authorize reserves an amount and
+// returns the provider's reference for it; Capture, Void and Refund act on
+// that reference. An operation the provider refuses fails with a
+// *PaymentDeclinedError.
+type PaymentGateway interface {
+	Provider() string
+	Authorize(ctx context.Context, orderID uuid.UUID, amount datatype.Money) (string, error)
+	Capture(ctx context.Context, reference string, amount datatype.Money) error
+	// Void releases an authorization that was not captured.
+	Void(ctx context.Context, reference string) error
+	Refund(ctx context.Context, reference string, amount datatype.Money) error
+}
+
+type OrderEventRepository interface {
+	ListAfter(ctx context.Context, afterID uuid.UUID, limit int32) ([]OrderEvent, error)
+}
+
+// OrderEventBus delivers order events as they are recorded.
+type OrderEventBus interface {
+	// Subscribe returns a channel of events for the organization in ctx and a
+	// function that cancels the subscription.
+	Subscribe(ctx context.Context) (<-chan OrderEvent, func(), error)
+}
+
+// OrderBroadcaster pushes the current state of an order to the live clients
+// of its organization.
+type OrderBroadcaster interface {
+	BroadcastOrder(ctx context.Context, order *Order) error
+}
+
+type WebhookEndpointRepository interface {
+	FindByID(ctx context.Context, id uuid.UUID) (*WebhookEndpoint, error)
+	List(ctx context.Context) ([]*WebhookEndpoint, error)
+	Create(ctx context.Context, endpoint *WebhookEndpoint) error
+	Update(ctx context.Context, endpoint *WebhookEndpoint) error
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+type WebhookDeliveryRepository interface {
+	// ListByEndpoint returns the endpoint's most recent deliveries, newest first.
+	ListByEndpoint(ctx context.Context, endpointID uuid.UUID, limit int32) ([]WebhookDelivery, error)
+	// ClaimDue locks up to limit pending deliveries of enabled endpoints that
+	// are due at now and defers them to leaseUntil, so a delivery is not sent
+	// twice while its attempt is in flight.
+	ClaimDue(ctx context.Context, now, leaseUntil time.Time, limit int32) ([]PendingWebhookDelivery, error)
+	// RecordAttempt stores the delivery's latest attempt and updates its
+	// endpoint's failure count, disabling the endpoint once disableAfter
+	// consecutive attempts have failed. It reports whether the endpoint is
+	// disabled afterwards.
+	RecordAttempt(ctx context.Context, delivery *WebhookDelivery, disableAfter int32) (bool, error)
+}
+
+// WebhookSender posts a delivery to its endpoint. Failures are reported in the
+// returned attempt rather than as an error.
+type WebhookSender interface {
+	Send(ctx context.Context, delivery PendingWebhookDelivery) WebhookAttempt
+}
+
+// SalesReportRepository keeps the daily sales aggregates that reports are
+// read from.
+type SalesReportRepository interface {
+	// Refresh recomputes the organization's daily sales from the day before
+	// its last refresh, or from its first sale if it has never been refreshed,
+	// and records now as its last refresh.
+	Refresh(ctx context.Context, organizationID uuid.UUID, now time.Time) error
+	// ListSales sums the organization's daily sales into periods, oldest
+	// first, with one period per currency sold in.
+	ListSales(ctx context.Context, organizationID uuid.UUID, query SalesQuery) ([]SalesPeriod, error)
+	// ListTopProducts returns the organization's best-selling products, best first.
+	ListTopProducts(ctx context.Context, organizationID uuid.UUID, query TopProductsQuery) ([]ProductSales, error)
+}
@@ -0,0 +1,216 @@
+package domain
+
+import (
+	"math/big"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/bbsbb/go-edge/core/datatype"
+	coredomain "github.com/bbsbb/go-edge/core/domain"
+)
+
+// TaxPricing says whether prices include tax.
+type TaxPricing string
+
+const (
+	// TaxPricingExclusive adds tax on top of prices.
+	TaxPricingExclusive TaxPricing = "exclusive"
+	// TaxPricingInclusive treats prices as including tax; the tax is the part
+	// of the price it accounts for.
+	TaxPricingInclusive TaxPricing = "inclusive"
+)
+
+func (p TaxPricing) IsValid() bool {
+	return p == TaxPricingExclusive || p == TaxPricingInclusive
+}
+
+// TaxRounding says where tax is rounded to the currency's minor unit.
+type TaxRounding string
+
+const (
+	// TaxRoundingPerLine rounds the tax of each item and adds up the results.
+	TaxRoundingPerLine TaxRounding = "per_line"
+	// TaxRoundingPerOrder adds up the items taxed at the same rate and rounds
+	// their tax once.
+	TaxRoundingPerOrder TaxRounding = "per_order"
+)
+
+func (r TaxRounding) IsValid() bool {
+	return r == TaxRoundingPerLine || r == TaxRoundingPerOrder
+}
+
+// MaxTaxRate is a rate of 100%. Rates are in basis points: 2000 is 20%.
+const MaxTaxRate = 10000
+
+// TaxProfile is how an organization taxes its orders. Items of a category in
+// Rates are taxed at its rate, the others at DefaultRate.
+type TaxProfile struct {
+	OrganizationID uuid.UUID
+	UpdatedAt      time.Time
+	Pricing        TaxPricing
+	Rounding       TaxRounding
+	DefaultRate    int32
+	Rates          map[ProductCategory]int32
+}
+
+// DefaultTaxProfile is the profile of an organization that has not set one:
+// no tax on top of its prices.
+func DefaultTaxProfile(organizationID uuid.UUID) *TaxProfile {
+	return &TaxProfile{
+		OrganizationID: organizationID,
+		Pricing:        TaxPricingExclusive,
+		Rounding:       TaxRoundingPerLine,
+		Rates:          map[ProductCategory]int32{},
+	}
+}
+
+func (p *TaxProfile) Validate() error {
+	invalid := func(msg string) error {
+		return coredomain.NewError(coredomain.CodeValidation, msg)
+	}
+	if !p.Pricing.IsValid() {
+		return invalid("invalid tax pricing")
+	}
+	if !p.Rounding.IsValid() {
+		return invalid("invalid tax rounding")
+	}
+	if p.DefaultRate < 0 || p.DefaultRate > MaxTaxRate {
+		return invalid("default_rate must be between 0 and 10000 basis points")
+	}
+	for category, rate := range p.Rates {
+		if !category.IsWellFormed() {
+			return invalid("invalid product category")
+		}
+		if rate < 0 || rate > MaxTaxRate {
+			return invalid("rates must be between 0 and 10000 basis points")
+		}
+	}
+	return nil
+}
+
+// RateFor returns the rate items of the category are taxed at.
+func (p *TaxProfile) RateFor(category ProductCategory) int32 {
+	if rate, ok := p.Rates[category]; ok {
+		return rate
+	}
+	return p.DefaultRate
+}
+
+// OrderTax is the tax on an order, one line per rate its items are taxed at.
+type OrderTax struct {
+	Pricing  TaxPricing
+	Rounding TaxRounding
+	Lines    []OrderTaxLine
+}
+
+// OrderTaxLine is the tax at one rate: Net is the price of the items taxed
+// at it, after adjustments and without tax, and Tax the tax on them.
+type OrderTaxLine struct {
+	OrganizationID uuid.UUID
+	OrderID        uuid.UUID
+	Rate           int32
+	Net            datatype.Money
+	Tax            datatype.Money
+}
+
+// ApplyTax replaces the order's tax with the one profile charges on its
+// items, after their adjustments. Apply promotions first.
+func (o *Order) ApplyTax(profile *TaxProfile) error {
+	lines := map[int32]*OrderTaxLine{}
+	for i := range o.Items {
+		item := &o.Items[i]
+		gross, err := item.LineTotal()
+		if err != nil {
+			return err
+		}
+		for _, a := range o.ItemAdjustments(item.ID) {
+			if gross, err = gross.Add(a.Amount); err != nil {
+				return err
+			}
+		}
+
+		rate := profile.RateFor(item.ProductCategory)
+		line, ok := lines[rate]
+		if !ok {
+			line = &OrderTaxLine{
+				OrganizationID: o.OrganizationID,
+				OrderID:        o.ID,
+				Rate:           rate,
+				Net:            gross.Zero(),
+				Tax:            gross.Zero(),
+			}
+			lines[rate] = line
+		}
+		if line.Net, err = line.Net.Add(gross); err != nil {
+			return err
+		}
+		if profile.Rounding == TaxRoundingPerLine {
+			tax, err := taxOn(gross, rate, profile.Pricing)
+			if err != nil {
+				return err
+			}
+			if line.Tax, err = line.Tax.Add(tax); err != nil {
+				return err
+			}
+		}
+	}
+
+	tax := &OrderTax{Pricing: profile.Pricing, Rounding: profile.Rounding, Lines: []OrderTaxLine{}}
+	for _, line := range lines {
+		var err error
+		if profile.Rounding == TaxRoundingPerOrder {
+			if line.Tax, err = taxOn(line.Net, line.Rate, profile.Pricing); err != nil {
+				return err
+			}
+		}
+		// Net has been the gross amount so far; an inclusive price has the
+		// tax in it.
+		if profile.Pricing == TaxPricingInclusive {
+			if line.Net, err = line.Net.Sub(line.Tax); err != nil {
+				return err
+			}
+		}
+		tax.Lines = append(tax.Lines, *line)
+	}
+	slices.SortFunc(tax.Lines, func(a, b OrderTaxLine) int { return int(a.Rate - b.Rate) })
+	o.Tax = tax
+	return nil
+}
+
+// taxOn returns the tax at rate on amount, rounded half up: rate of amount
+// for exclusive pricing, the part of amount that is tax for inclusive
+// pricing. amount must not be negative.
+func taxOn(amount datatype.Money, rate int32, pricing TaxPricing) (datatype.Money, error) {
+	denominator := int64(MaxTaxRate)
+	if pricing == TaxPricingInclusive {
+		denominator += int64(rate)
+	}
+	// round(a*r/d) = floor((2*a*r + d) / 2d) for a non-negative a*r/d.
+	n := new(big.Int).Mul(big.NewInt(amount.Amount()), big.NewInt(int64(rate)*2))
+	n.Add(n, big.NewInt(denominator))
+	n.Quo(n, big.NewInt(denominator*2))
+	return datatype.NewMoney(n.Int64(), amount.Currency())
+}
+
+// TaxTotal is the tax on the order, zero until tax is applied.
+func (o *Order) TaxTotal() (datatype.Money, error) {
+	total, err := datatype.NewMoney(0, o.Currency)
+	if err != nil || o.Tax == nil {
+		return total, err
+	}
+	for _, line := range o.Tax.Lines {
+		if total, err = total.Add(line.Tax); err != nil {
+			return datatype.Money{}, err
+		}
+	}
+	return total, nil
+}
+
+// FixesPrice reports whether the change fixes the order's price: a submitted
+// order keeps the adjustments, tax and totals it had then, whatever happens
+// to the promotions and tax profile afterwards.
+func (c OrderStatusChange) FixesPrice() bool {
+	return c.To == OrderStatusSubmitted
+}
@@ -0,0 +1,165 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+type TaxSuite struct {
+	suite.Suite
+}
+
+func (s *TaxSuite) order(items ...OrderItem) *Order {
+	o := &Order{ID: uuid.Must(uuid.NewV7()), Status: OrderStatusOpen, Currency: "EUR"}
+	for _, item := range items {
+		item.ID = uuid.Must(uuid.NewV7())
+		item.ProductID = uuid.Must(uuid.NewV7())
+		item.OrderID = o.ID
+		o.Items = append(o.Items, item)
+	}
+	return o
+}
+
+func iceCreams(quantity int32, unitPrice int64) OrderItem {
+	return OrderItem{ProductCategory: categoryIceCream, Quantity: quantity, UnitPrice: eur(unitPrice)}
+}
+
+func marshmallows(quantity int32, unitPrice int64) OrderItem {
+	return OrderItem{ProductCategory: categoryMarshmallow, Quantity: quantity, UnitPrice: eur(unitPrice)}
+}
+
+func profile(pricing TaxPricing, rounding TaxRounding) *TaxProfile {
+	return &TaxProfile{
+		Pricing:     pricing,
+		Rounding:    rounding,
+		DefaultRate: 2000,
+		Rates:       map[ProductCategory]int32{categoryIceCream: 700},
+	}
+}
+
+// lines returns each tax line as rate, net and tax.
+func lines(o *Order) [][3]int64 {
+	out := [][3]int64{}
+	for _, l := range o.Tax.Lines {
+		out = append(out, [3]int64{int64(l.Rate), l.Net.Amount(), l.Tax.Amount()})
+	}
+	return out
+}
+
+func (s *TaxSuite) amounts(o *Order) (tax, total int64) {
+	t, err := o.TaxTotal()
+	s.Require().NoError(err)
+	g, err := o.Total()
+	s.Require().NoError(err)
+	return t.Amount(), g.Amount()
+}
+
+func (s *TaxSuite) TestExclusive_AddsTaxPerCategoryRate() {
+	o := s.order(iceCreams(2, 350), marshmallows(1, 200))
+	s.Require().NoError(o.ApplyTax(profile(TaxPricingExclusive, TaxRoundingPerLine)))
+
+	s.Assert().Equal([][3]int64{{700, 700, 49}, {2000, 200, 40}}, lines(o))
+	tax, total := s.amounts(o)
+	s.Assert().Equal(int64(89), tax)
+	s.Assert().Equal(int64(989), total)
+}
+
+func (s *TaxSuite) TestInclusive_TaxIsPartOfThePrice() {
+	o := s.order(iceCreams(1, 107), marshmallows(1, 120))
+	s.Require().NoError(o.ApplyTax(profile(TaxPricingInclusive, TaxRoundingPerLine)))
+
+	s.Assert().Equal([][3]int64{{700, 100, 7}, {2000, 100, 20}}, lines(o))
+	tax, total := s.amounts(o)
+	s.Assert().Equal(int64(27), tax)
+	s.Assert().Equal(int64(227), total, "the total is the prices")
+}
+
+func (s *TaxSuite) TestRounding() {
+	// Three lines of 0.05 at 7% carry 0.0035 tax each.
+	items := []OrderItem{iceCreams(1, 5), iceCreams(1, 5), iceCreams(1, 5)}
+
+	cases := []struct {
+		name     string
+		rounding TaxRounding
+		tax      int64
+	}{
+		{"per line rounds each item", TaxRoundingPerLine, 0},
+		{"per order rounds the sum", TaxRoundingPerOrder, 1},
+	}
+	for _, tc := range cases {
+		s.Run(tc.name, func() {
+			o := s.order(items...)
+			s.Require().NoError(o.ApplyTax(profile(TaxPricingExclusive, tc.rounding)))
+			tax, _ := s.amounts(o)
+			s.Assert().Equal(tc.tax, tax)
+		})
+	}
+}
+
+func (s *TaxSuite) TestRoundsHalfUp() {
+	o := s.order(marshmallows(1, 5), marshmallows(1, 2))
+	s.Require().NoError(o.ApplyTax(&TaxProfile{
+		Pricing: TaxPricingExclusive, Rounding: TaxRoundingPerLine, DefaultRate: 1000,
+	}))
+
+	s.Assert().Equal([][3]int64{{1000, 7, 1}}, lines(o), "0.5 rounds up, 0.2 down")
+}
+
+func (s *TaxSuite) TestTaxesDiscountedPrice() {
+	o := s.order(iceCreams(2, 500))
+	s.Require().NoError(o.ApplyPromotions([]Promotion{
+		promotion(Promotion{Kind: PromotionKindPercentage, PercentOff: 50}),
+	}, time.Now()))
+	s.Require().NoError(o.ApplyTax(profile(TaxPricingExclusive, TaxRoundingPerLine)))
+
+	s.Assert().Equal([][3]int64{{700, 500, 35}}, lines(o))
+	_, total := s.amounts(o)
+	s.Assert().Equal(int64(535), total)
+}
+
+func (s *TaxSuite) TestDefaultProfile_ChargesNothing() {
+	o := s.order(iceCreams(2, 350))
+	s.Require().NoError(o.ApplyTax(DefaultTaxProfile(uuid.Nil)))
+
+	s.Assert().Equal([][3]int64{{0, 700, 0}}, lines(o))
+	_, total := s.amounts(o)
+	s.Assert().Equal(int64(700), total)
+}
+
+func (s *TaxSuite) TestEmptyOrder() {
+	o := s.order()
+	s.Require().NoError(o.ApplyTax(profile(TaxPricingExclusive, TaxRoundingPerOrder)))
+
+	s.Assert().Empty(o.Tax.Lines)
+	tax, total := s.amounts(o)
+	s.Assert().Zero(tax)
+	s.Assert().Zero(total)
+}
+
+func (s *TaxSuite) TestValidate() {
+	cases := []struct {
+		name   string
+		mutate func(*TaxProfile)
+	}{
+		{"pricing", func(p *TaxProfile) { p.Pricing = "gross" }},
+		{"rounding", func(p *TaxProfile) { p.Rounding = "per_item" }},
+		{"negative default rate", func(p *TaxProfile) { p.DefaultRate = -1 }},
+		{"rate above 100%", func(p *TaxProfile) { p.Rates[categoryMarshmallow] = MaxTaxRate + 1 }},
+		{"category", func(p *TaxProfile) { p.Rates["Fudge"] = 100 }},
+	}
+	s.Require().NoError(profile(TaxPricingInclusive, TaxRoundingPerOrder).Validate())
+	for _, tc := range cases {
+		s.Run(tc.name, func() {
+			p := profile(TaxPricingExclusive, TaxRoundingPerLine)
+			tc.mutate(p)
+			s.Assert().Error(p.Validate())
+		})
+	}
+}
+
+func TestTaxSuite(t *testing.T) {
+	suite.Run(t, new(TaxSuite))
+}
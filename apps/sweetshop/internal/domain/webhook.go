@@ -0,0 +1,180 @@
+package domain
+
+import (
+	cryptorand "crypto/rand"
+	"encoding/hex"
+	"math/rand/v2"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+
+	coredomain "github.com/bbsbb/go-edge/core/domain"
+)
+
+// WebhookEventType names the event an endpoint subscribes to. Order events are
+// named after the order's new state.
+type WebhookEventType string
+
+const (
+	WebhookEventOrderOpened    WebhookEventType = "order.opened"
+	WebhookEventOrderSubmitted WebhookEventType = "order.submitted"
+	WebhookEventOrderPaid      WebhookEventType = "order.paid"
+	WebhookEventOrderFulfilled WebhookEventType = "order.fulfilled"
+	WebhookEventOrderCancelled WebhookEventType = "order.cancelled"
+)
+
+func (t WebhookEventType) IsValid() bool {
+	return slices.Contains([]WebhookEventType{
+		WebhookEventOrderOpened, WebhookEventOrderSubmitted, WebhookEventOrderPaid,
+		WebhookEventOrderFulfilled, WebhookEventOrderCancelled,
+	}, t)
+}
+
+// WebhookEndpoint is a URL an organization registered to receive events.
+// Payloads sent to it are signed with Secret.
+type WebhookEndpoint struct {
+	ID                  uuid.UUID
+	OrganizationID      uuid.UUID
+	CreatedAt           time.Time
+	UpdatedAt           time.Time
+	URL                 string
+	Secret              string
+	EventTypes          []WebhookEventType
+	Enabled             bool
+	ConsecutiveFailures int32
+	DisabledAt          *time.Time
+}
+
+// NewWebhookSecret returns a random signing secret.
+func NewWebhookSecret() string {
+	b := make([]byte, 32)
+	_, _ = cryptorand.Read(b)
+	return "whsec_" + hex.EncodeToString(b)
+}
+
+// SetEventTypes replaces the subscriptions, dropping duplicates.
+func (e *WebhookEndpoint) SetEventTypes(types []WebhookEventType) error {
+	if len(types) == 0 {
+		return coredomain.NewError(coredomain.CodeValidation, "at least one event type is required")
+	}
+	for _, t := range types {
+		if !t.IsValid() {
+			return coredomain.NewError(coredomain.CodeValidation, "invalid webhook event type")
+		}
+	}
+	types = slices.Clone(types)
+	slices.Sort(types)
+	e.EventTypes = slices.Compact(types)
+	return nil
+}
+
+// Enable turns delivery back on and forgets past failures. Pending deliveries
+// queued before the endpoint was disabled are sent again.
+func (e *WebhookEndpoint) Enable() {
+	e.Enabled = true
+	e.ConsecutiveFailures = 0
+	e.DisabledAt = nil
+}
+
+func (e *WebhookEndpoint) Disable(at time.Time) {
+	if !e.Enabled {
+		return
+	}
+	e.Enabled = false
+	e.DisabledAt = &at
+}
+
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "pending"
+	WebhookDeliverySucceeded WebhookDeliveryStatus = "succeeded"
+	WebhookDeliveryFailed    WebhookDeliveryStatus = "failed"
+)
+
+// WebhookDelivery is one event queued for one endpoint, along with the outcome
+// of its latest attempt. Payload is the JSON body sent to the endpoint.
+type WebhookDelivery struct {
+	ID             uuid.UUID
+	OrganizationID uuid.UUID
+	EndpointID     uuid.UUID
+	EventID        uuid.UUID
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	EventType      WebhookEventType
+	Payload        []byte
+	Status         WebhookDeliveryStatus
+	Attempts       int32
+	NextAttemptAt  time.Time
+	LastAttemptAt  *time.Time
+	ResponseStatus *int32
+	LastError      *string
+}
+
+// PendingWebhookDelivery is a delivery claimed for sending, together with the
+// endpoint it goes to.
+type PendingWebhookDelivery struct {
+	WebhookDelivery
+	URL    string
+	Secret string
+}
+
+// WebhookAttempt is the outcome of sending a delivery once. StatusCode is zero
+// when no response was received.
+type WebhookAttempt struct {
+	StatusCode int
+	Error      string
+}
+
+// Succeeded reports whether the endpoint acknowledged the delivery with a 2xx.
+func (a WebhookAttempt) Succeeded() bool {
+	return a.Error == "" && a.StatusCode >= 200 && a.StatusCode < 300
+}
+
+// WebhookRetryPolicy decides when a failed delivery is tried again.
+type WebhookRetryPolicy struct {
+	MaxAttempts int32
+	MinDelay    time.Duration
+	MaxDelay    time.Duration
+}
+
+// Backoff returns the delay after the given number of failed attempts. It
+// doubles from MinDelay up to MaxDelay, with jitter in the upper half.
+func (p WebhookRetryPolicy) Backoff(attempts int32) time.Duration {
+	d := p.MinDelay
+	for i := int32(1); i < attempts && d < p.MaxDelay; i++ {
+		d *= 2
+	}
+	d = min(d, p.MaxDelay)
+	return d/2 + rand.N(d/2+1) //nolint:gosec // jitter only
+}
+
+// RecordAttempt applies the outcome of an attempt made at the given time. A
+// failed delivery is rescheduled until MaxAttempts is reached, then marked
+// failed for good.
+func (d *WebhookDelivery) RecordAttempt(a WebhookAttempt, at time.Time, policy WebhookRetryPolicy) {
+	d.Attempts++
+	d.UpdatedAt = at
+	d.LastAttemptAt = &at
+	d.ResponseStatus = nil
+	if a.StatusCode != 0 {
+		status := int32(a.StatusCode) //nolint:gosec // HTTP status codes fit in int32
+		d.ResponseStatus = &status
+	}
+	d.LastError = nil
+	if a.Error != "" {
+		msg := a.Error
+		d.LastError = &msg
+	}
+
+	switch {
+	case a.Succeeded():
+		d.Status = WebhookDeliverySucceeded
+	case d.Attempts >= policy.MaxAttempts:
+		d.Status = WebhookDeliveryFailed
+	default:
+		d.Status = WebhookDeliveryPending
+		d.NextAttemptAt = at.Add(policy.Backoff(d.Attempts))
+	}
+}
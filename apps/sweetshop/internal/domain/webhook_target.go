@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"fmt"
+	"net/netip"
+	"net/url"
+	"slices"
+	"strings"
+
+	coredomain "github.com/bbsbb/go-edge/core/domain"
+)
+
+// WebhookTargetPolicy decides where deliveries may be sent, so that an
+// endpoint cannot point the service at its own network. Loopback, private,
+// link-local and unspecified addresses, and those in deniedNetworks, are
+// refused unless they fall in one of AllowedNetworks, and plain http is
+// refused unless AllowHTTP is set.
+type WebhookTargetPolicy struct {
+	AllowHTTP       bool
+	AllowedNetworks []netip.Prefix
+}
+
+// deniedNetworks are special-purpose ranges that netip does not classify as
+// internal but that can still reach the service's own network.
+var deniedNetworks = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),      // "this network"
+	netip.MustParsePrefix("100.64.0.0/10"),  // carrier-grade NAT, incl. cloud metadata at 100.100.100.200
+	netip.MustParsePrefix("192.0.0.0/24"),   // IETF protocol assignments
+	netip.MustParsePrefix("198.18.0.0/15"),  // benchmarking
+	netip.MustParsePrefix("64:ff9b::/96"),   // NAT64, which would reach any embedded IPv4 address
+	netip.MustParsePrefix("64:ff9b:1::/48"), // local-use NAT64
+}
+
+// CheckURL vets an endpoint URL when it is registered. A host name is only
+// checked once it resolves, by CheckAddr at dial time.
+func (p WebhookTargetPolicy) CheckURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return coredomain.NewError(coredomain.CodeValidation, "webhook URL is invalid")
+	}
+	switch {
+	case u.Scheme == "https":
+	case u.Scheme == "http" && p.AllowHTTP:
+	default:
+		return coredomain.NewError(coredomain.CodeValidation, "webhook URL must use https")
+	}
+
+	host := strings.ToLower(strings.TrimSuffix(u.Hostname(), "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return p.CheckAddr(netip.IPv6Loopback())
+	}
+	if addr, err := netip.ParseAddr(host); err == nil {
+		return p.CheckAddr(addr)
+	}
+	return nil
+}
+
+// CheckAddr vets an address a delivery is about to be sent to.
+func (p WebhookTargetPolicy) CheckAddr(addr netip.Addr) error {
+	addr = addr.Unmap()
+	internal := addr.IsLoopback() || addr.IsPrivate() || addr.IsLinkLocalUnicast() ||
+		addr.IsLinkLocalMulticast() || addr.IsInterfaceLocalMulticast() || addr.IsUnspecified() ||
+		slices.ContainsFunc(deniedNetworks, func(n netip.Prefix) bool { return n.Contains(addr) })
+	if !internal || slices.ContainsFunc(p.AllowedNetworks, func(n netip.Prefix) bool { return n.Contains(addr) }) {
+		return nil
+	}
+	return coredomain.NewError(coredomain.CodeValidation, fmt.Sprintf("webhook address %s is not public", addr))
+}
@@ -0,0 +1,79 @@
+package domain
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type WebhookTargetPolicySuite struct {
+	suite.Suite
+}
+
+func (s *WebhookTargetPolicySuite) TestCheckURL() {
+	strict := WebhookTargetPolicy{}
+	local := WebhookTargetPolicy{
+		AllowHTTP:       true,
+		AllowedNetworks: []netip.Prefix{netip.MustParsePrefix("127.0.0.0/8"), netip.MustParsePrefix("::1/128")},
+	}
+	cases := []struct {
+		url    string
+		strict bool
+		local  bool
+	}{
+		{"https://shop.example/hooks", true, true},
+		{"http://shop.example/hooks", false, true},
+		{"ftp://shop.example/hooks", false, false},
+		{"https://127.0.0.1:8443/hooks", false, true},
+		{"https://[::1]/hooks", false, true},
+		{"https://localhost/hooks", false, true},
+		{"https://api.localhost./hooks", false, true},
+		{"https://[::ffff:10.0.0.1]/hooks", false, false},
+		{"https://169.254.169.254/latest/meta-data", false, false},
+		{"https://192.168.1.10/hooks", false, false},
+		{"https://0.0.0.0/hooks", false, false},
+		{"https://93.184.215.14/hooks", true, true},
+		{"https:///hooks", false, false},
+	}
+	for _, tc := range cases {
+		s.Run(tc.url, func() {
+			s.Assert().Equal(tc.strict, strict.CheckURL(tc.url) == nil, "strict")
+			s.Assert().Equal(tc.local, local.CheckURL(tc.url) == nil, "local")
+		})
+	}
+}
+
+func (s *WebhookTargetPolicySuite) TestCheckAddr_SpecialPurposeRanges() {
+	strict := WebhookTargetPolicy{}
+	for _, addr := range []string{
+		"100.64.0.1",
+		"100.100.100.200",
+		"100.127.255.254",
+		"0.1.2.3",
+		"192.0.0.8",
+		"198.18.0.1",
+		"198.19.255.254",
+		"64:ff9b::a9fe:a9fe",
+		"64:ff9b::7f00:1",
+		"64:ff9b:1::a00:1",
+		"::ffff:100.100.100.200",
+	} {
+		s.Run(addr, func() {
+			s.Assert().Error(strict.CheckAddr(netip.MustParseAddr(addr)))
+		})
+	}
+
+	for _, addr := range []string{"100.63.255.255", "100.128.0.1", "192.0.1.1", "198.20.0.1", "64:ff9b:2::1"} {
+		s.Run(addr, func() {
+			s.Assert().NoError(strict.CheckAddr(netip.MustParseAddr(addr)))
+		})
+	}
+
+	allowed := WebhookTargetPolicy{AllowedNetworks: []netip.Prefix{netip.MustParsePrefix("100.64.0.0/10")}}
+	s.Assert().NoError(allowed.CheckAddr(netip.MustParseAddr("100.100.100.200")), "an allowed network overrides the deny list")
+}
+
+func TestWebhookTargetPolicySuite(t *testing.T) {
+	suite.Run(t, new(WebhookTargetPolicySuite))
+}
@@ -0,0 +1,74 @@
+package outbound
+
+import (
+	"fmt"
+	"net/http"
+	"net/netip"
+	"syscall"
+
+	"go.uber.org/fx"
+
+	"github.com/bbsbb/go-edge/core/fx/httpclientfx"
+	"github.com/bbsbb/go-edge/sweetshop/internal/config"
+	"github.com/bbsbb/go-edge/sweetshop/internal/domain"
+)
+
+// WebhookClient is the httpclientfx client deliveries are sent with.
+const WebhookClient = "webhooks"
+
+func provideWebhookSender(registry *httpclientfx.Registry) (domain.WebhookSender, error) {
+	client, err := registry.Client(WebhookClient)
+	if err != nil {
+		return nil, err
+	}
+	return NewWebhookSender(noRedirects(client)), nil
+}
+
+type webhookDialControlResult struct {
+	fx.Out
+	Control httpclientfx.DialControl `group:"httpclient_dial_controls"`
+}
+
+// provideWebhookDialControl refuses the webhooks client's connections to
+// addresses the target policy does not allow. Endpoints are vetted when they
+// are registered too, but only the dialed address settles where a host name
+// points at the time of sending.
+func provideWebhookDialControl(cfg *config.AppConfiguration) webhookDialControlResult {
+	wh := cfg.WebhookConfiguration()
+	policy := domain.WebhookTargetPolicy{AllowHTTP: wh.AllowHTTP, AllowedNetworks: wh.AllowedPrefixes()}
+	return webhookDialControlResult{
+		Control: httpclientfx.DialControl{Client: WebhookClient, Control: dialControl(policy)},
+	}
+}
+
+func dialControl(policy domain.WebhookTargetPolicy) func(string, string, syscall.RawConn) error {
+	return func(_, address string, _ syscall.RawConn) error {
+		addrPort, err := netip.ParseAddrPort(address)
+		if err != nil {
+			return err
+		}
+		return policy.CheckAddr(addrPort.Addr())
+	}
+}
+
+// noRedirects returns a copy of client that hands 3xx responses back instead
+// of following them, so a delivery is only ever posted to its registered URL.
+func noRedirects(client *http.Client) *http.Client {
+	c := *client
+	c.CheckRedirect = func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }
+	return &c
+}
+
+func providePaymentGateway(cfg *config.AppConfiguration) (domain.PaymentGateway, error) {
+	switch provider := cfg.PaymentConfiguration().ProviderOrDefault(); provider {
+	case config.PaymentProviderFake:
+		return NewFakePaymentGateway(), nil
+	default:
+		return nil, fmt.Errorf("unknown payment provider %q", provider)
+	}
+}
+
+var Module = fx.Module(
+	"sweetshop/outbound",
+	fx.Provide(provideWebhookSender, provideWebhookDialControl, providePaymentGateway),
+)
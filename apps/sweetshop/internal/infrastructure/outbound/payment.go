@@ -0,0 +1,192 @@
+package outbound
+
+import (
+	"context"
+	"sync"
+
+	"github.com/google/uuid"
+
+	"github.com/bbsbb/go-edge/core/datatype"
+	"github.com/bbsbb/go-edge/sweetshop/internal/config"
+	"github.com/bbsbb/go-edge/sweetshop/internal/domain"
+)
+
+// FakePaymentGateway is an in-memory payment provider. It keeps the rules a
+// real provider enforces: a capture stays within its authorization and
+// happens once, only uncaptured authorizations can be voided, and refunds
+// never exceed what was captured. Authorizations do not survive a restart.
+type FakePaymentGateway struct {
+	mu             sync.Mutex
+	authorizations map[string]*fakeAuthorization
+	declines       map[domain.PaymentOperation][]string
+	holds          map[domain.PaymentOperation][]chan struct{}
+}
+
+// fakeAuthorization tracks what was captured and refunded against an
+// authorized amount; all three share its currency.
+type fakeAuthorization struct {
+	amount   datatype.Money
+	captured datatype.Money
+	refunded datatype.Money
+	voided   bool
+}
+
+var _ domain.PaymentGateway = (*FakePaymentGateway)(nil)
+
+func NewFakePaymentGateway() *FakePaymentGateway {
+	return &FakePaymentGateway{
+		authorizations: make(map[string]*fakeAuthorization),
+		declines:       make(map[domain.PaymentOperation][]string),
+		holds:          make(map[domain.PaymentOperation][]chan struct{}),
+	}
+}
+
+func (g *FakePaymentGateway) Provider() string {
+	return config.PaymentProviderFake
+}
+
+// DeclineNext makes the next call of op fail with reason, as if the
+// provider refused it.
+func (g *FakePaymentGateway) DeclineNext(op domain.PaymentOperation, reason string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.declines[op] = append(g.declines[op], reason)
+}
+
+// HoldNext makes the next call of op wait, as a slow provider would, until
+// release is called or the call's context ends.
+func (g *FakePaymentGateway) HoldNext(op domain.PaymentOperation) (release func()) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	ch := make(chan struct{})
+	g.holds[op] = append(g.holds[op], ch)
+	var once sync.Once
+	return func() { once.Do(func() { close(ch) }) }
+}
+
+func (g *FakePaymentGateway) Authorize(ctx context.Context, _ uuid.UUID, amount datatype.Money) (string, error) {
+	if err := g.held(ctx, domain.PaymentOperationAuthorize); err != nil {
+		return "", err
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if err := g.declined(domain.PaymentOperationAuthorize); err != nil {
+		return "", err
+	}
+	if !amount.IsPositive() {
+		return "", &domain.PaymentDeclinedError{Reason: "amount must be positive"}
+	}
+
+	reference := "fake_auth_" + uuid.NewString()
+	g.authorizations[reference] = &fakeAuthorization{amount: amount, captured: amount.Zero(), refunded: amount.Zero()}
+	return reference, nil
+}
+
+func (g *FakePaymentGateway) Capture(ctx context.Context, reference string, amount datatype.Money) error {
+	if err := g.held(ctx, domain.PaymentOperationCapture); err != nil {
+		return err
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if err := g.declined(domain.PaymentOperationCapture); err != nil {
+		return err
+	}
+	auth, ok := g.authorizations[reference]
+	switch {
+	case !ok:
+		return &domain.PaymentDeclinedError{Reason: "unknown authorization"}
+	case auth.voided:
+		return &domain.PaymentDeclinedError{Reason: "authorization voided"}
+	case auth.captured.IsPositive():
+		return &domain.PaymentDeclinedError{Reason: "authorization already captured"}
+	case amount.Currency() != auth.amount.Currency():
+		return &domain.PaymentDeclinedError{Reason: "currency does not match authorization"}
+	}
+	if cmp, _ := amount.Cmp(auth.amount); !amount.IsPositive() || cmp > 0 {
+		return &domain.PaymentDeclinedError{Reason: "amount exceeds authorization"}
+	}
+	auth.captured = amount
+	return nil
+}
+
+func (g *FakePaymentGateway) Void(ctx context.Context, reference string) error {
+	if err := g.held(ctx, domain.PaymentOperationVoid); err != nil {
+		return err
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if err := g.declined(domain.PaymentOperationVoid); err != nil {
+		return err
+	}
+	auth, ok := g.authorizations[reference]
+	switch {
+	case !ok:
+		return &domain.PaymentDeclinedError{Reason: "unknown authorization"}
+	case auth.captured.IsPositive():
+		return &domain.PaymentDeclinedError{Reason: "authorization already captured"}
+	case auth.voided:
+		return &domain.PaymentDeclinedError{Reason: "authorization voided"}
+	}
+	auth.voided = true
+	return nil
+}
+
+func (g *FakePaymentGateway) Refund(ctx context.Context, reference string, amount datatype.Money) error {
+	if err := g.held(ctx, domain.PaymentOperationRefund); err != nil {
+		return err
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if err := g.declined(domain.PaymentOperationRefund); err != nil {
+		return err
+	}
+	auth, ok := g.authorizations[reference]
+	switch {
+	case !ok:
+		return &domain.PaymentDeclinedError{Reason: "unknown authorization"}
+	case amount.Currency() != auth.amount.Currency():
+		return &domain.PaymentDeclinedError{Reason: "currency does not match authorization"}
+	}
+	refunded, err := auth.refunded.Add(amount)
+	if err != nil {
+		return err
+	}
+	if cmp, _ := refunded.Cmp(auth.captured); !amount.IsPositive() || cmp > 0 {
+		return &domain.PaymentDeclinedError{Reason: "amount exceeds captured amount"}
+	}
+	auth.refunded = refunded
+	return nil
+}
+
+// declined pops the next queued decline for op. The caller holds g.mu.
+func (g *FakePaymentGateway) declined(op domain.PaymentOperation) error {
+	queue := g.declines[op]
+	if len(queue) == 0 {
+		return nil
+	}
+	g.declines[op] = queue[1:]
+	return &domain.PaymentDeclinedError{Reason: queue[0]}
+}
+
+// held waits out the next queued hold for op, if there is one.
+func (g *FakePaymentGateway) held(ctx context.Context, op domain.PaymentOperation) error {
+	g.mu.Lock()
+	queue := g.holds[op]
+	if len(queue) == 0 {
+		g.mu.Unlock()
+		return nil
+	}
+	g.holds[op] = queue[1:]
+	g.mu.Unlock()
+
+	select {
+	case <-queue[0]:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
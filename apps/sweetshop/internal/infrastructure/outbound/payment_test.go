@@ -0,0 +1,120 @@
+package outbound
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bbsbb/go-edge/core/datatype"
+	"github.com/bbsbb/go-edge/sweetshop/internal/domain"
+)
+
+type FakePaymentGatewaySuite struct {
+	suite.Suite
+	gateway *FakePaymentGateway
+	ctx     context.Context
+}
+
+func (s *FakePaymentGatewaySuite) SetupTest() {
+	s.gateway = NewFakePaymentGateway()
+	s.ctx = context.Background()
+}
+
+func eur(amount int64) datatype.Money {
+	return datatype.MustMoney(amount, "EUR")
+}
+
+func (s *FakePaymentGatewaySuite) authorize(amount int64) string {
+	ref, err := s.gateway.Authorize(s.ctx, uuid.Must(uuid.NewV7()), eur(amount))
+	s.Require().NoError(err)
+	return ref
+}
+
+func (s *FakePaymentGatewaySuite) assertDeclined(err error, reason string) {
+	var declined *domain.PaymentDeclinedError
+	s.Require().ErrorAs(err, &declined)
+	s.Assert().Equal(reason, declined.Reason)
+}
+
+func (s *FakePaymentGatewaySuite) TestAuthorizeCaptureRefund() {
+	ref := s.authorize(700)
+	s.Assert().NotEqual(ref, s.authorize(700), "references are unique")
+
+	s.Require().NoError(s.gateway.Capture(s.ctx, ref, eur(700)))
+	s.Require().NoError(s.gateway.Refund(s.ctx, ref, eur(200)))
+	s.Require().NoError(s.gateway.Refund(s.ctx, ref, eur(500)))
+	s.assertDeclined(s.gateway.Refund(s.ctx, ref, eur(1)), "amount exceeds captured amount")
+}
+
+func (s *FakePaymentGatewaySuite) TestAuthorize_NonPositiveAmount() {
+	_, err := s.gateway.Authorize(s.ctx, uuid.Must(uuid.NewV7()), eur(0))
+	s.assertDeclined(err, "amount must be positive")
+}
+
+func (s *FakePaymentGatewaySuite) TestCapture_Rules() {
+	ref := s.authorize(700)
+
+	s.assertDeclined(s.gateway.Capture(s.ctx, "fake_auth_unknown", eur(700)), "unknown authorization")
+	s.assertDeclined(s.gateway.Capture(s.ctx, ref, eur(701)), "amount exceeds authorization")
+	s.Require().NoError(s.gateway.Capture(s.ctx, ref, eur(500)))
+	s.assertDeclined(s.gateway.Capture(s.ctx, ref, eur(200)), "authorization already captured")
+}
+
+func (s *FakePaymentGatewaySuite) TestCurrencyMismatch() {
+	ref := s.authorize(700)
+
+	usd := datatype.MustMoney(700, "USD")
+	s.assertDeclined(s.gateway.Capture(s.ctx, ref, usd), "currency does not match authorization")
+	s.Require().NoError(s.gateway.Capture(s.ctx, ref, eur(700)))
+	s.assertDeclined(s.gateway.Refund(s.ctx, ref, usd), "currency does not match authorization")
+}
+
+func (s *FakePaymentGatewaySuite) TestVoid() {
+	ref := s.authorize(700)
+
+	s.Require().NoError(s.gateway.Void(s.ctx, ref))
+	s.assertDeclined(s.gateway.Void(s.ctx, ref), "authorization voided")
+	s.assertDeclined(s.gateway.Capture(s.ctx, ref, eur(700)), "authorization voided")
+
+	captured := s.authorize(700)
+	s.Require().NoError(s.gateway.Capture(s.ctx, captured, eur(700)))
+	s.assertDeclined(s.gateway.Void(s.ctx, captured), "authorization already captured")
+}
+
+func (s *FakePaymentGatewaySuite) TestRefund_BeforeCapture() {
+	s.assertDeclined(s.gateway.Refund(s.ctx, s.authorize(700), eur(700)), "amount exceeds captured amount")
+}
+
+func (s *FakePaymentGatewaySuite) TestDeclineNext() {
+	s.gateway.DeclineNext(domain.PaymentOperationCapture, "do not honor")
+	ref := s.authorize(700)
+
+	s.assertDeclined(s.gateway.Capture(s.ctx, ref, eur(700)), "do not honor")
+	s.Assert().NoError(s.gateway.Capture(s.ctx, ref, eur(700)), "a queued decline applies once")
+}
+
+func (s *FakePaymentGatewaySuite) TestHoldNext() {
+	s.gateway.HoldNext(domain.PaymentOperationAuthorize)
+
+	ctx, cancel := context.WithTimeout(s.ctx, 10*time.Millisecond)
+	defer cancel()
+	_, err := s.gateway.Authorize(ctx, uuid.Must(uuid.NewV7()), eur(700))
+	s.Assert().ErrorIs(err, context.DeadlineExceeded, "a held call ends with its context")
+	s.authorize(700) // a hold applies once
+
+	release := s.gateway.HoldNext(domain.PaymentOperationAuthorize)
+	done := make(chan error, 1)
+	go func() {
+		_, err := s.gateway.Authorize(s.ctx, uuid.Must(uuid.NewV7()), eur(700))
+		done <- err
+	}()
+	release()
+	s.Assert().NoError(<-done)
+}
+
+func TestFakePaymentGatewaySuite(t *testing.T) {
+	suite.Run(t, new(FakePaymentGatewaySuite))
+}
@@ -0,0 +1,122 @@
+// Package outbound provides adapters that call out to third-party HTTP
+// services on behalf of the application.
+package outbound
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bbsbb/go-edge/sweetshop/internal/domain"
+)
+
+// Headers sent with every webhook delivery.
+const (
+	HeaderEvent     = "X-Sweetshop-Event"
+	HeaderDelivery  = "X-Sweetshop-Delivery"
+	HeaderTimestamp = "X-Sweetshop-Timestamp"
+	HeaderSignature = "X-Sweetshop-Signature"
+)
+
+const (
+	signatureVersion = "v1"
+	maxErrorLength   = 512
+)
+
+var (
+	ErrMissingSignature = errors.New("outbound: missing webhook signature")
+	ErrInvalidSignature = errors.New("outbound: invalid webhook signature")
+	ErrStaleTimestamp   = errors.New("outbound: webhook timestamp outside tolerance")
+)
+
+// Sign returns the signature header value for a payload sent at timestamp
+// (Unix seconds): "v1=" followed by the hex HMAC-SHA256 of
+// "<timestamp>.<payload>" under secret. Binding the timestamp into the
+// signature lets receivers reject replays.
+func Sign(secret string, timestamp int64, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte{'.'})
+	mac.Write(payload)
+	return signatureVersion + "=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks the signature headers of a received delivery, the way a
+// receiver is expected to: the timestamp must be within tolerance of now and
+// one of the comma-separated signatures must match.
+func Verify(secret string, header http.Header, payload []byte, tolerance time.Duration, now time.Time) error {
+	sigs, ts := header.Get(HeaderSignature), header.Get(HeaderTimestamp)
+	if sigs == "" || ts == "" {
+		return ErrMissingSignature
+	}
+	timestamp, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	if d := now.Sub(time.Unix(timestamp, 0)); d > tolerance || d < -tolerance {
+		return ErrStaleTimestamp
+	}
+
+	want := Sign(secret, timestamp, payload)
+	for sig := range strings.SplitSeq(sigs, ",") {
+		if hmac.Equal([]byte(strings.TrimSpace(sig)), []byte(want)) {
+			return nil
+		}
+	}
+	return ErrInvalidSignature
+}
+
+// WebhookSender posts signed deliveries with the "webhooks" HTTP client.
+type WebhookSender struct {
+	client *http.Client
+	now    func() time.Time
+}
+
+func NewWebhookSender(client *http.Client) *WebhookSender {
+	return &WebhookSender{client: client, now: time.Now}
+}
+
+// Send posts the delivery's payload to its endpoint. Any 2xx response counts
+// as received; redirects are not followed.
+func (s *WebhookSender) Send(ctx context.Context, d domain.PendingWebhookDelivery) domain.WebhookAttempt {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.URL, bytes.NewReader(d.Payload))
+	if err != nil {
+		return domain.WebhookAttempt{Error: truncate(err.Error())}
+	}
+	timestamp := s.now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "sweetshop-webhooks/1")
+	req.Header.Set(HeaderEvent, string(d.EventType))
+	req.Header.Set(HeaderDelivery, d.ID.String())
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(d.Secret, timestamp, d.Payload))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return domain.WebhookAttempt{Error: truncate(err.Error())}
+	}
+	defer func() { _ = resp.Body.Close() }()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4<<10))
+
+	attempt := domain.WebhookAttempt{StatusCode: resp.StatusCode}
+	if !attempt.Succeeded() {
+		attempt.Error = fmt.Sprintf("unexpected status %d", resp.StatusCode)
+	}
+	return attempt
+}
+
+func truncate(msg string) string {
+	if len(msg) <= maxErrorLength {
+		return msg
+	}
+	return msg[:maxErrorLength]
+}
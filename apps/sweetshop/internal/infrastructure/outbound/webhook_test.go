@@ -0,0 +1,152 @@
+package outbound
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bbsbb/go-edge/sweetshop/internal/domain"
+)
+
+type received struct {
+	header http.Header
+	body   []byte
+}
+
+type WebhookSenderSuite struct {
+	suite.Suite
+	server   *httptest.Server
+	status   int
+	received chan received
+	sender   *WebhookSender
+	now      time.Time
+}
+
+func (s *WebhookSenderSuite) SetupTest() {
+	s.status = http.StatusNoContent
+	s.received = make(chan received, 1)
+	s.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		s.received <- received{header: r.Header.Clone(), body: body}
+		if s.status == http.StatusFound {
+			http.Redirect(w, r, "/elsewhere", s.status)
+			return
+		}
+		w.WriteHeader(s.status)
+	}))
+	s.T().Cleanup(s.server.Close)
+
+	s.now = time.Unix(1_760_000_000, 0)
+	s.sender = NewWebhookSender(noRedirects(s.server.Client()))
+	s.sender.now = func() time.Time { return s.now }
+}
+
+func (s *WebhookSenderSuite) delivery() domain.PendingWebhookDelivery {
+	return domain.PendingWebhookDelivery{
+		WebhookDelivery: domain.WebhookDelivery{
+			ID:        uuid.Must(uuid.NewV7()),
+			EventType: domain.WebhookEventOrderFulfilled,
+			Payload:   []byte(`{"type":"order.fulfilled"}`),
+		},
+		URL:    s.server.URL + "/hooks",
+		Secret: "whsec_test",
+	}
+}
+
+func (s *WebhookSenderSuite) TestSend_SignsPayload() {
+	d := s.delivery()
+
+	attempt := s.sender.Send(context.Background(), d)
+
+	s.Assert().True(attempt.Succeeded())
+	s.Assert().Equal(http.StatusNoContent, attempt.StatusCode)
+	got := <-s.received
+	s.Assert().Equal(d.Payload, got.body)
+	s.Assert().Equal("application/json", got.header.Get("Content-Type"))
+	s.Assert().Equal("order.fulfilled", got.header.Get(HeaderEvent))
+	s.Assert().Equal(d.ID.String(), got.header.Get(HeaderDelivery))
+	s.Assert().Equal("1760000000", got.header.Get(HeaderTimestamp))
+	s.Assert().Equal(Sign(d.Secret, s.now.Unix(), d.Payload), got.header.Get(HeaderSignature))
+	s.Assert().NoError(Verify(d.Secret, got.header, got.body, 5*time.Minute, s.now.Add(time.Minute)))
+}
+
+func (s *WebhookSenderSuite) TestSend_ServerErrorFails() {
+	s.status = http.StatusInternalServerError
+
+	attempt := s.sender.Send(context.Background(), s.delivery())
+
+	s.Assert().False(attempt.Succeeded())
+	s.Assert().Equal(http.StatusInternalServerError, attempt.StatusCode)
+	s.Assert().Equal("unexpected status 500", attempt.Error)
+}
+
+func (s *WebhookSenderSuite) TestSend_RedirectIsNotFollowed() {
+	s.status = http.StatusFound
+
+	attempt := s.sender.Send(context.Background(), s.delivery())
+
+	s.Assert().False(attempt.Succeeded())
+	s.Assert().Equal(http.StatusFound, attempt.StatusCode)
+	s.Assert().Len(s.received, 1)
+}
+
+func (s *WebhookSenderSuite) TestSend_UnreachableFails() {
+	d := s.delivery()
+	s.server.Close()
+
+	attempt := s.sender.Send(context.Background(), d)
+
+	s.Assert().False(attempt.Succeeded())
+	s.Assert().Zero(attempt.StatusCode)
+	s.Assert().NotEmpty(attempt.Error)
+}
+
+func (s *WebhookSenderSuite) TestSend_LoopbackIsRefusedUnlessAllowed() {
+	send := func(policy domain.WebhookTargetPolicy) domain.WebhookAttempt {
+		client := s.server.Client()
+		transport := client.Transport.(*http.Transport).Clone()
+		transport.DialContext = (&net.Dialer{Control: dialControl(policy)}).DialContext
+		client.Transport = transport
+		return NewWebhookSender(noRedirects(client)).Send(context.Background(), s.delivery())
+	}
+
+	attempt := send(domain.WebhookTargetPolicy{AllowHTTP: true})
+
+	s.Assert().False(attempt.Succeeded())
+	s.Assert().Zero(attempt.StatusCode)
+	s.Assert().Contains(attempt.Error, "is not public")
+	s.Assert().Empty(s.received)
+
+	attempt = send(domain.WebhookTargetPolicy{AllowedNetworks: []netip.Prefix{netip.MustParsePrefix("127.0.0.0/8")}})
+
+	s.Assert().True(attempt.Succeeded())
+	s.Assert().Len(s.received, 1)
+}
+
+func (s *WebhookSenderSuite) TestVerify() {
+	payload := []byte(`{"id":"1"}`)
+	signed := func(secret string, at time.Time) http.Header {
+		h := http.Header{}
+		h.Set(HeaderTimestamp, "1760000000")
+		h.Set(HeaderSignature, "v0=deadbeef, "+Sign(secret, at.Unix(), payload))
+		return h
+	}
+
+	s.Assert().NoError(Verify("secret", signed("secret", s.now), payload, time.Minute, s.now))
+	s.Assert().ErrorIs(Verify("other", signed("secret", s.now), payload, time.Minute, s.now), ErrInvalidSignature)
+	s.Assert().ErrorIs(Verify("secret", signed("secret", s.now), []byte(`{"id":"2"}`), time.Minute, s.now), ErrInvalidSignature)
+	s.Assert().ErrorIs(Verify("secret", signed("secret", s.now), payload, time.Minute, s.now.Add(2*time.Minute)), ErrStaleTimestamp)
+	s.Assert().ErrorIs(Verify("secret", http.Header{}, payload, time.Minute, s.now), ErrMissingSignature)
+}
+
+func TestWebhookSenderSuite(t *testing.T) {
+	suite.Run(t, new(WebhookSenderSuite))
+}
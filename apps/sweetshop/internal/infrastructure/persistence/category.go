@@ -0,0 +1,119 @@
+package persistence
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	coredomain "github.com/bbsbb/go-edge/core/domain"
+	"github.com/bbsbb/go-edge/core/fx/rlsfx"
+	"github.com/bbsbb/go-edge/sweetshop/internal/domain"
+	"github.com/bbsbb/go-edge/sweetshop/internal/infrastructure/persistence/sqlcgen"
+)
+
+type CategoryRepo struct {
+	db *rlsfx.DB
+}
+
+func NewCategoryRepo(db *rlsfx.DB) *CategoryRepo {
+	return &CategoryRepo{db: db}
+}
+
+func (r *CategoryRepo) FindByID(ctx context.Context, id uuid.UUID) (*domain.Category, error) {
+	return rlsfx.Query(r.db, ctx, func(ctx context.Context, tx pgx.Tx) (*domain.Category, error) {
+		m, err := sqlcgen.New(tx).FindCategoryByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		return categoryToDomain(m), nil
+	})
+}
+
+func (r *CategoryRepo) FindBySlug(ctx context.Context, slug domain.ProductCategory) (*domain.Category, error) {
+	return rlsfx.Query(r.db, ctx, func(ctx context.Context, tx pgx.Tx) (*domain.Category, error) {
+		m, err := sqlcgen.New(tx).FindCategoryBySlug(ctx, string(slug))
+		if err != nil {
+			return nil, err
+		}
+		return categoryToDomain(m), nil
+	})
+}
+
+func (r *CategoryRepo) List(ctx context.Context) ([]*domain.Category, error) {
+	return rlsfx.Query(r.db, ctx, func(ctx context.Context, tx pgx.Tx) ([]*domain.Category, error) {
+		rows, err := sqlcgen.New(tx).ListCategories(ctx)
+		if err != nil {
+			return nil, err
+		}
+		categories := make([]*domain.Category, len(rows))
+		for i, m := range rows {
+			categories[i] = categoryToDomain(m)
+		}
+		return categories, nil
+	})
+}
+
+func (r *CategoryRepo) Create(ctx context.Context, category *domain.Category) error {
+	return rlsfx.Exec(r.db, ctx, func(ctx context.Context, tx pgx.Tx) error {
+		return sqlcgen.New(tx).CreateCategory(ctx, sqlcgen.CreateCategoryParams{
+			ID:              category.ID,
+			OrganizationID:  category.OrganizationID,
+			SystemCreatedAt: category.CreatedAt,
+			SystemUpdatedAt: category.UpdatedAt,
+			Slug:            string(category.Slug),
+			Name:            category.Name,
+			SortOrder:       category.SortOrder,
+		})
+	})
+}
+
+func (r *CategoryRepo) Update(ctx context.Context, category *domain.Category) error {
+	return rlsfx.Exec(r.db, ctx, func(ctx context.Context, tx pgx.Tx) error {
+		n, err := sqlcgen.New(tx).UpdateCategory(ctx, sqlcgen.UpdateCategoryParams{
+			ID:              category.ID,
+			SystemUpdatedAt: category.UpdatedAt,
+			Slug:            string(category.Slug),
+			Name:            category.Name,
+			SortOrder:       category.SortOrder,
+		})
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return pgx.ErrNoRows
+		}
+		return nil
+	})
+}
+
+// Delete removes the category and its tax rate. A category that is still in
+// use is kept and a conflict error returned.
+func (r *CategoryRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	return rlsfx.Exec(r.db, ctx, func(ctx context.Context, tx pgx.Tx) error {
+		q := sqlcgen.New(tx)
+		if _, err := q.FindCategoryByID(ctx, id); err != nil {
+			return err
+		}
+		n, err := q.DeleteCategory(ctx, id)
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return coredomain.NewError(coredomain.CodeConflict, "category is used by products or promotions")
+		}
+		return nil
+	})
+}
+
+func categoryToDomain(m sqlcgen.Category) *domain.Category {
+	return &domain.Category{
+		ID:             m.ID,
+		OrganizationID: m.OrganizationID,
+		CreatedAt:      m.SystemCreatedAt,
+		UpdatedAt:      m.SystemUpdatedAt,
+		Slug:           domain.ProductCategory(m.Slug),
+		Name:           m.Name,
+		SortOrder:      m.SortOrder,
+	}
+}
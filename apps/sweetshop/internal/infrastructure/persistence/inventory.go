@@ -0,0 +1,122 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/bbsbb/go-edge/core/fx/rlsfx"
+	"github.com/bbsbb/go-edge/sweetshop/internal/domain"
+	"github.com/bbsbb/go-edge/sweetshop/internal/infrastructure/persistence/sqlcgen"
+)
+
+type InventoryRepo struct {
+	db *rlsfx.DB
+}
+
+func NewInventoryRepo(db *rlsfx.DB) *InventoryRepo {
+	return &InventoryRepo{db: db}
+}
+
+func (r *InventoryRepo) FindByProductID(ctx context.Context, productID uuid.UUID) (*domain.Inventory, error) {
+	return rlsfx.Query(r.db, ctx, func(ctx context.Context, tx pgx.Tx) (*domain.Inventory, error) {
+		m, err := sqlcgen.New(tx).FindInventoryByProductID(ctx, productID)
+		if err != nil {
+			return nil, err
+		}
+		return inventoryToDomain(m), nil
+	})
+}
+
+func (r *InventoryRepo) Adjust(ctx context.Context, adjustment *domain.StockAdjustment) (*domain.Inventory, error) {
+	return rlsfx.Query(r.db, ctx, func(ctx context.Context, tx pgx.Tx) (*domain.Inventory, error) {
+		q := sqlcgen.New(tx)
+		if err := q.EnsureInventory(ctx, sqlcgen.EnsureInventoryParams{
+			ProductID:       adjustment.ProductID,
+			OrganizationID:  adjustment.OrganizationID,
+			SystemCreatedAt: adjustment.CreatedAt,
+		}); err != nil {
+			return nil, err
+		}
+		m, err := q.LockInventory(ctx, adjustment.ProductID)
+		if err != nil {
+			return nil, err
+		}
+
+		inventory := inventoryToDomain(m)
+		if err := inventory.Apply(adjustment, adjustment.CreatedAt); err != nil {
+			return nil, err
+		}
+		if err := q.UpdateInventory(ctx, inventoryUpdateParams(inventory)); err != nil {
+			return nil, err
+		}
+		if err := q.CreateStockAdjustment(ctx, stockAdjustmentCreateParams(adjustment)); err != nil {
+			return nil, err
+		}
+		return inventory, nil
+	})
+}
+
+func (r *InventoryRepo) ListAdjustments(ctx context.Context, productID uuid.UUID, limit int32) ([]domain.StockAdjustment, error) {
+	return rlsfx.Query(r.db, ctx, func(ctx context.Context, tx pgx.Tx) ([]domain.StockAdjustment, error) {
+		rows, err := sqlcgen.New(tx).ListStockAdjustmentsByProductID(ctx, sqlcgen.ListStockAdjustmentsByProductIDParams{
+			ProductID: productID,
+			Limit:     limit,
+		})
+		if err != nil {
+			return nil, err
+		}
+		adjustments := make([]domain.StockAdjustment, len(rows))
+		for i, row := range rows {
+			adjustments[i] = stockAdjustmentToDomain(row)
+		}
+		return adjustments, nil
+	})
+}
+
+// reserveStock changes the stock the item holds from its product's inventory
+// to quantity units within tx, releasing what it held before. Items of
+// products whose stock is not tracked reserve nothing.
+func reserveStock(ctx context.Context, q *sqlcgen.Queries, item *domain.OrderItem, quantity int32, at time.Time) error {
+	m, err := q.LockInventory(ctx, item.ProductID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		item.ReservedQuantity = 0
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	inventory := inventoryToDomain(m)
+	inventory.Release(item.ReservedQuantity)
+	if err := inventory.Reserve(quantity); err != nil {
+		return err
+	}
+	inventory.UpdatedAt = at
+	if err := q.UpdateInventory(ctx, inventoryUpdateParams(inventory)); err != nil {
+		return err
+	}
+	item.ReservedQuantity = quantity
+	return nil
+}
+
+// settleStock releases or commits the stock reserved by the order's items.
+func settleStock(ctx context.Context, q *sqlcgen.Queries, change domain.OrderStatusChange) error {
+	switch change.StockSettlement() {
+	case domain.StockReleased:
+		return q.ReleaseOrderReservations(ctx, sqlcgen.ReleaseOrderReservationsParams{
+			SystemUpdatedAt: change.At,
+			OrderID:         change.OrderID,
+		})
+	case domain.StockCommitted:
+		return q.CommitOrderReservations(ctx, sqlcgen.CommitOrderReservationsParams{
+			SystemUpdatedAt: change.At,
+			OrderID:         change.OrderID,
+		})
+	default:
+		return nil
+	}
+}
@@ -1,10 +1,12 @@
 package persistence
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
 
+	"github.com/bbsbb/go-edge/core/datatype"
 	coredomain "github.com/bbsbb/go-edge/core/domain"
 	"github.com/bbsbb/go-edge/sweetshop/internal/domain"
 	"github.com/bbsbb/go-edge/sweetshop/internal/infrastructure/persistence/sqlcgen"
@@ -27,6 +29,22 @@ func organizationCreateParams(org *coredomain.Organization, now time.Time) sqlcg
 	}
 }
 
+func organizationSettingsToDomain(m sqlcgen.Organization) *domain.OrganizationSettings {
+	return &domain.OrganizationSettings{
+		OrganizationID: m.ID,
+		UpdatedAt:      m.SystemUpdatedAt,
+		Currency:       datatype.Currency(m.Currency),
+	}
+}
+
+func organizationSettingsUpdateParams(s *domain.OrganizationSettings) sqlcgen.UpdateOrganizationSettingsParams {
+	return sqlcgen.UpdateOrganizationSettingsParams{
+		ID:              s.OrganizationID,
+		SystemUpdatedAt: s.UpdatedAt,
+		Currency:        string(s.Currency),
+	}
+}
+
 func productToDomain(m sqlcgen.Product) *domain.Product {
 	return &domain.Product{
 		ID:             m.ID,
@@ -35,7 +53,12 @@ func productToDomain(m sqlcgen.Product) *domain.Product {
 		UpdatedAt:      m.SystemUpdatedAt,
 		Name:           m.Name,
 		Category:       domain.ProductCategory(m.Category),
-		PriceCents:     m.PriceCents,
+		Price:          m.Price,
+		SKU:            m.Sku,
+		Description:    m.Description,
+		Active:         m.Active,
+		SortOrder:      m.SortOrder,
+		DeletedAt:      m.DeletedAt,
 	}
 }
 
@@ -47,7 +70,11 @@ func productCreateParams(p *domain.Product) sqlcgen.CreateProductParams {
 		SystemUpdatedAt: p.UpdatedAt,
 		Name:            p.Name,
 		Category:        string(p.Category),
-		PriceCents:      p.PriceCents,
+		Price:           p.Price,
+		Sku:             p.SKU,
+		Description:     p.Description,
+		Active:          p.Active,
+		SortOrder:       p.SortOrder,
 	}
 }
 
@@ -57,18 +84,36 @@ func productUpdateParams(p *domain.Product) sqlcgen.UpdateProductParams {
 		SystemUpdatedAt: p.UpdatedAt,
 		Name:            p.Name,
 		Category:        string(p.Category),
-		PriceCents:      p.PriceCents,
+		Price:           p.Price,
+		Sku:             p.SKU,
+		Description:     p.Description,
+		Active:          p.Active,
+		SortOrder:       p.SortOrder,
 	}
 }
 
+// orderToDomain sets the order's Tax, without its lines, once the order has
+// been submitted.
 func orderToDomain(m sqlcgen.Order) *domain.Order {
-	return &domain.Order{
+	order := &domain.Order{
 		ID:             m.ID,
 		OrganizationID: m.OrganizationID,
 		CreatedAt:      m.SystemCreatedAt,
 		UpdatedAt:      m.SystemUpdatedAt,
 		Status:         domain.OrderStatus(m.Status),
+		SubmittedAt:    m.SubmittedAt,
+		PaidAt:         m.PaidAt,
+		FulfilledAt:    m.FulfilledAt,
+		CancelledAt:    m.CancelledAt,
+		Currency:       datatype.Currency(m.Currency),
+	}
+	if m.TaxPricing != nil && m.TaxRounding != nil {
+		order.Tax = &domain.OrderTax{
+			Pricing:  domain.TaxPricing(*m.TaxPricing),
+			Rounding: domain.TaxRounding(*m.TaxRounding),
+		}
 	}
+	return order
 }
 
 func orderCreateParams(o *domain.Order) sqlcgen.CreateOrderParams {
@@ -78,36 +123,305 @@ func orderCreateParams(o *domain.Order) sqlcgen.CreateOrderParams {
 		SystemCreatedAt: o.CreatedAt,
 		SystemUpdatedAt: o.UpdatedAt,
 		Status:          string(o.Status),
+		Currency:        string(o.Currency),
 	}
 }
 
-func orderCloseParams(id uuid.UUID, updatedAt time.Time) sqlcgen.CloseOrderParams {
-	return sqlcgen.CloseOrderParams{
-		ID:              id,
-		SystemUpdatedAt: updatedAt,
+func orderTransitionParams(o *domain.Order, from domain.OrderStatus) sqlcgen.TransitionOrderParams {
+	return sqlcgen.TransitionOrderParams{
+		SystemUpdatedAt: o.UpdatedAt,
+		ToStatus:        string(o.Status),
+		SubmittedAt:     o.SubmittedAt,
+		PaidAt:          o.PaidAt,
+		FulfilledAt:     o.FulfilledAt,
+		CancelledAt:     o.CancelledAt,
+		ID:              o.ID,
+		FromStatus:      string(from),
 	}
 }
 
-func orderItemToDomain(m sqlcgen.OrderItem) domain.OrderItem {
-	return domain.OrderItem{
+func orderStatusChangeToDomain(m sqlcgen.OrderStatusHistory) domain.OrderStatusChange {
+	var from domain.OrderStatus
+	if m.FromStatus != nil {
+		from = domain.OrderStatus(*m.FromStatus)
+	}
+	return domain.OrderStatusChange{
 		ID:             m.ID,
 		OrganizationID: m.OrganizationID,
 		OrderID:        m.OrderID,
+		At:             m.SystemCreatedAt,
+		From:           from,
+		To:             domain.OrderStatus(m.ToStatus),
+	}
+}
+
+func orderStatusChangeCreateParams(c domain.OrderStatusChange) sqlcgen.CreateOrderStatusChangeParams {
+	var from *string
+	if c.From != "" {
+		s := string(c.From)
+		from = &s
+	}
+	return sqlcgen.CreateOrderStatusChangeParams{
+		ID:              c.ID,
+		OrganizationID:  c.OrganizationID,
+		OrderID:         c.OrderID,
+		SystemCreatedAt: c.At,
+		FromStatus:      from,
+		ToStatus:        string(c.To),
+	}
+}
+
+func productSoftDeleteParams(id uuid.UUID, deletedAt time.Time) sqlcgen.SoftDeleteProductParams {
+	return sqlcgen.SoftDeleteProductParams{
+		ID:              id,
+		SystemUpdatedAt: deletedAt,
+	}
+}
+
+func productRestoreParams(id uuid.UUID, restoredAt time.Time) sqlcgen.RestoreProductParams {
+	return sqlcgen.RestoreProductParams{
+		ID:              id,
+		SystemUpdatedAt: restoredAt,
+	}
+}
+
+func orderItemToDomain(m sqlcgen.ListOrderItemsByOrderIDRow) (domain.OrderItem, error) {
+	options, err := decodeOrderItemOptions(m.Options)
+	if err != nil {
+		return domain.OrderItem{}, fmt.Errorf("decoding options of order item %s: %w", m.ID, err)
+	}
+	return domain.OrderItem{
+		ID:               m.ID,
+		OrganizationID:   m.OrganizationID,
+		OrderID:          m.OrderID,
+		ProductID:        m.ProductID,
+		ProductName:      m.ProductName,
+		ProductCategory:  domain.ProductCategory(m.ProductCategory),
+		CreatedAt:        m.SystemCreatedAt,
+		Quantity:         m.Quantity,
+		UnitPrice:        m.UnitPrice,
+		ReservedQuantity: m.ReservedQuantity,
+		Options:          options,
+	}, nil
+}
+
+func orderItemCreateParams(i *domain.OrderItem) (sqlcgen.CreateOrderItemParams, error) {
+	options, err := encodeOrderItemOptions(i.Options)
+	if err != nil {
+		return sqlcgen.CreateOrderItemParams{}, err
+	}
+	return sqlcgen.CreateOrderItemParams{
+		ID:               i.ID,
+		OrganizationID:   i.OrganizationID,
+		OrderID:          i.OrderID,
+		ProductID:        i.ProductID,
+		SystemCreatedAt:  i.CreatedAt,
+		Quantity:         i.Quantity,
+		UnitPrice:        i.UnitPrice,
+		ReservedQuantity: i.ReservedQuantity,
+		Options:          options,
+	}, nil
+}
+
+func inventoryToDomain(m sqlcgen.Inventory) *domain.Inventory {
+	return &domain.Inventory{
 		ProductID:      m.ProductID,
+		OrganizationID: m.OrganizationID,
 		CreatedAt:      m.SystemCreatedAt,
-		Quantity:       m.Quantity,
-		PriceCents:     m.PriceCents,
+		UpdatedAt:      m.SystemUpdatedAt,
+		Tracked:        true,
+		OnHand:         m.OnHand,
+		Reserved:       m.Reserved,
 	}
 }
 
-func orderItemCreateParams(i *domain.OrderItem) sqlcgen.CreateOrderItemParams {
-	return sqlcgen.CreateOrderItemParams{
-		ID:              i.ID,
-		OrganizationID:  i.OrganizationID,
-		OrderID:         i.OrderID,
+func inventoryUpdateParams(i *domain.Inventory) sqlcgen.UpdateInventoryParams {
+	return sqlcgen.UpdateInventoryParams{
 		ProductID:       i.ProductID,
-		SystemCreatedAt: i.CreatedAt,
-		Quantity:        i.Quantity,
-		PriceCents:      i.PriceCents,
+		SystemUpdatedAt: i.UpdatedAt,
+		OnHand:          i.OnHand,
+		Reserved:        i.Reserved,
+	}
+}
+
+func stockAdjustmentToDomain(m sqlcgen.StockAdjustment) domain.StockAdjustment {
+	return domain.StockAdjustment{
+		ID:             m.ID,
+		OrganizationID: m.OrganizationID,
+		ProductID:      m.ProductID,
+		CreatedAt:      m.SystemCreatedAt,
+		QuantityDelta:  m.QuantityDelta,
+		Reason:         domain.StockAdjustmentReason(m.Reason),
+		Note:           m.Note,
+		OnHandAfter:    m.OnHandAfter,
+	}
+}
+
+func stockAdjustmentCreateParams(a *domain.StockAdjustment) sqlcgen.CreateStockAdjustmentParams {
+	return sqlcgen.CreateStockAdjustmentParams{
+		ID:              a.ID,
+		OrganizationID:  a.OrganizationID,
+		ProductID:       a.ProductID,
+		SystemCreatedAt: a.CreatedAt,
+		QuantityDelta:   a.QuantityDelta,
+		Reason:          string(a.Reason),
+		Note:            a.Note,
+		OnHandAfter:     a.OnHandAfter,
+	}
+}
+
+func orderEventToDomain(m sqlcgen.OrderEvent) domain.OrderEvent {
+	return domain.OrderEvent{
+		ID:             m.ID,
+		OrganizationID: m.OrganizationID,
+		OrderID:        m.OrderID,
+		OccurredAt:     m.SystemCreatedAt,
+		Type:           domain.OrderEventType(m.Type),
+		Status:         domain.OrderStatus(m.Status),
+	}
+}
+
+func webhookEndpointToDomain(m sqlcgen.WebhookEndpoint) *domain.WebhookEndpoint {
+	types := make([]domain.WebhookEventType, len(m.EventTypes))
+	for i, t := range m.EventTypes {
+		types[i] = domain.WebhookEventType(t)
+	}
+	return &domain.WebhookEndpoint{
+		ID:                  m.ID,
+		OrganizationID:      m.OrganizationID,
+		CreatedAt:           m.SystemCreatedAt,
+		UpdatedAt:           m.SystemUpdatedAt,
+		URL:                 m.Url,
+		Secret:              m.Secret,
+		EventTypes:          types,
+		Enabled:             m.Enabled,
+		ConsecutiveFailures: m.ConsecutiveFailures,
+		DisabledAt:          m.DisabledAt,
+	}
+}
+
+func webhookEventTypeStrings(types []domain.WebhookEventType) []string {
+	s := make([]string, len(types))
+	for i, t := range types {
+		s[i] = string(t)
+	}
+	return s
+}
+
+func webhookEndpointCreateParams(e *domain.WebhookEndpoint) sqlcgen.CreateWebhookEndpointParams {
+	return sqlcgen.CreateWebhookEndpointParams{
+		ID:              e.ID,
+		OrganizationID:  e.OrganizationID,
+		SystemCreatedAt: e.CreatedAt,
+		SystemUpdatedAt: e.UpdatedAt,
+		Url:             e.URL,
+		Secret:          e.Secret,
+		EventTypes:      webhookEventTypeStrings(e.EventTypes),
+		Enabled:         e.Enabled,
+	}
+}
+
+func webhookEndpointUpdateParams(e *domain.WebhookEndpoint) sqlcgen.UpdateWebhookEndpointParams {
+	return sqlcgen.UpdateWebhookEndpointParams{
+		ID:                  e.ID,
+		SystemUpdatedAt:     e.UpdatedAt,
+		Url:                 e.URL,
+		EventTypes:          webhookEventTypeStrings(e.EventTypes),
+		Enabled:             e.Enabled,
+		ConsecutiveFailures: e.ConsecutiveFailures,
+		DisabledAt:          e.DisabledAt,
+	}
+}
+
+func webhookDeliveryToDomain(m sqlcgen.WebhookDelivery) domain.WebhookDelivery {
+	return domain.WebhookDelivery{
+		ID:             m.ID,
+		OrganizationID: m.OrganizationID,
+		EndpointID:     m.EndpointID,
+		EventID:        m.EventID,
+		CreatedAt:      m.SystemCreatedAt,
+		UpdatedAt:      m.SystemUpdatedAt,
+		EventType:      domain.WebhookEventType(m.EventType),
+		Payload:        m.Payload,
+		Status:         domain.WebhookDeliveryStatus(m.Status),
+		Attempts:       m.Attempts,
+		NextAttemptAt:  m.NextAttemptAt,
+		LastAttemptAt:  m.LastAttemptAt,
+		ResponseStatus: m.ResponseStatus,
+		LastError:      m.LastError,
+	}
+}
+
+func pendingWebhookDeliveryToDomain(m sqlcgen.ClaimDueWebhookDeliveriesRow) domain.PendingWebhookDelivery {
+	return domain.PendingWebhookDelivery{
+		WebhookDelivery: webhookDeliveryToDomain(sqlcgen.WebhookDelivery{
+			ID:              m.ID,
+			OrganizationID:  m.OrganizationID,
+			EndpointID:      m.EndpointID,
+			EventID:         m.EventID,
+			SystemCreatedAt: m.SystemCreatedAt,
+			SystemUpdatedAt: m.SystemUpdatedAt,
+			EventType:       m.EventType,
+			Payload:         m.Payload,
+			Status:          m.Status,
+			Attempts:        m.Attempts,
+			NextAttemptAt:   m.NextAttemptAt,
+			LastAttemptAt:   m.LastAttemptAt,
+			ResponseStatus:  m.ResponseStatus,
+			LastError:       m.LastError,
+		}),
+		URL:    m.Url,
+		Secret: m.Secret,
+	}
+}
+
+func webhookDeliveryAttemptParams(d *domain.WebhookDelivery) sqlcgen.UpdateWebhookDeliveryAttemptParams {
+	return sqlcgen.UpdateWebhookDeliveryAttemptParams{
+		ID:              d.ID,
+		SystemUpdatedAt: d.UpdatedAt,
+		Status:          string(d.Status),
+		Attempts:        d.Attempts,
+		NextAttemptAt:   d.NextAttemptAt,
+		LastAttemptAt:   d.LastAttemptAt,
+		ResponseStatus:  d.ResponseStatus,
+		LastError:       d.LastError,
+	}
+}
+
+func paymentToDomain(m sqlcgen.Payment) domain.Payment {
+	var reference string
+	if m.ProviderReference != nil {
+		reference = *m.ProviderReference
+	}
+	return domain.Payment{
+		ID:             m.ID,
+		OrganizationID: m.OrganizationID,
+		OrderID:        m.OrderID,
+		CreatedAt:      m.SystemCreatedAt,
+		Provider:       m.Provider,
+		Operation:      domain.PaymentOperation(m.Operation),
+		Status:         domain.PaymentAttemptStatus(m.Status),
+		Amount:         m.Amount,
+		Reference:      reference,
+		Error:          m.Error,
+	}
+}
+
+func paymentCreateParams(p *domain.Payment) sqlcgen.CreatePaymentParams {
+	var reference *string
+	if p.Reference != "" {
+		reference = &p.Reference
+	}
+	return sqlcgen.CreatePaymentParams{
+		ID:                p.ID,
+		OrganizationID:    p.OrganizationID,
+		OrderID:           p.OrderID,
+		SystemCreatedAt:   p.CreatedAt,
+		Provider:          p.Provider,
+		Operation:         string(p.Operation),
+		Status:            string(p.Status),
+		Amount:            p.Amount,
+		ProviderReference: reference,
+		Error:             p.Error,
 	}
 }
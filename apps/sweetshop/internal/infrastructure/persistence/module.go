@@ -1,9 +1,12 @@
 package persistence
 
 import (
+	"log/slog"
+
 	"github.com/jackc/pgx/v5/pgxpool"
 	"go.uber.org/fx"
 
+	"github.com/bbsbb/go-edge/core/fx/psqlfx"
 	"github.com/bbsbb/go-edge/core/fx/rlsfx"
 	coremiddleware "github.com/bbsbb/go-edge/core/transport/http/middleware"
 	"github.com/bbsbb/go-edge/sweetshop/internal/domain"
@@ -21,16 +24,76 @@ func provideProductRepo(db *rlsfx.DB) domain.ProductRepository {
 	return NewProductRepo(db)
 }
 
+func provideCategoryRepo(db *rlsfx.DB) domain.CategoryRepository {
+	return NewCategoryRepo(db)
+}
+
+func provideProductOptionRepo(db *rlsfx.DB) domain.ProductOptionRepository {
+	return NewProductOptionRepo(db)
+}
+
 func provideOrderRepo(db *rlsfx.DB) domain.OrderRepository {
 	return NewOrderRepo(db)
 }
 
+func provideInventoryRepo(db *rlsfx.DB) domain.InventoryRepository {
+	return NewInventoryRepo(db)
+}
+
+func providePaymentRepo(db *rlsfx.DB) domain.PaymentRepository {
+	return NewPaymentRepo(db)
+}
+
+func provideOrderEventRepo(db *rlsfx.DB) domain.OrderEventRepository {
+	return NewOrderEventRepo(db)
+}
+
+func providePromotionRepo(db *rlsfx.DB) domain.PromotionRepository {
+	return NewPromotionRepo(db)
+}
+
+func provideTaxProfileRepo(db *rlsfx.DB) domain.TaxProfileRepository {
+	return NewTaxProfileRepo(db)
+}
+
+func provideWebhookEndpointRepo(db *rlsfx.DB) domain.WebhookEndpointRepository {
+	return NewWebhookEndpointRepo(db)
+}
+
+func provideWebhookDeliveryRepo(db *rlsfx.DB) domain.WebhookDeliveryRepository {
+	return NewWebhookDeliveryRepo(db)
+}
+
+func provideSalesReportRepo(db *rlsfx.DB) domain.SalesReportRepository {
+	return NewSalesReportRepo(db)
+}
+
+func provideOrderEventBus(lc fx.Lifecycle, listener *psqlfx.Listener, logger *slog.Logger) domain.OrderEventBus {
+	bus := NewOrderEventBus(func() (<-chan orderEventNotification, func(), error) {
+		return psqlfx.Subscribe[orderEventNotification](listener, orderEventsChannel, orderEventBuffer)
+	}, logger)
+	listener.OnReconnect(bus.Reset)
+	lc.Append(fx.Hook{OnStart: bus.Start, OnStop: bus.Stop})
+	return bus
+}
+
 var Module = fx.Module(
 	"sweetshop/persistence",
 	fx.Provide(
 		provideOrganizationRepo,
 		provideOrganizationLoader,
 		provideProductRepo,
+		provideCategoryRepo,
+		provideProductOptionRepo,
 		provideOrderRepo,
+		provideInventoryRepo,
+		providePaymentRepo,
+		providePromotionRepo,
+		provideTaxProfileRepo,
+		provideOrderEventRepo,
+		provideOrderEventBus,
+		provideWebhookEndpointRepo,
+		provideWebhookDeliveryRepo,
+		provideSalesReportRepo,
 	),
 )
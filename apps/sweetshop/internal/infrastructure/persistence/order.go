@@ -2,11 +2,15 @@ package persistence
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 
+	"github.com/bbsbb/go-edge/core/datatype"
+	coredomain "github.com/bbsbb/go-edge/core/domain"
 	"github.com/bbsbb/go-edge/core/fx/rlsfx"
 	"github.com/bbsbb/go-edge/sweetshop/internal/domain"
 	"github.com/bbsbb/go-edge/sweetshop/internal/infrastructure/persistence/sqlcgen"
@@ -34,31 +38,192 @@ func (r *OrderRepo) FindByID(ctx context.Context, id uuid.UUID) (*domain.Order,
 		}
 		order.Items = make([]domain.OrderItem, len(items))
 		for i, item := range items {
-			order.Items[i] = orderItemToDomain(item)
+			if order.Items[i], err = orderItemToDomain(item); err != nil {
+				return nil, err
+			}
+		}
+
+		payments, err := sqlcgen.New(tx).ListPaymentsByOrderID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		order.Payments = paymentsToDomain(payments)
+
+		adjustments, err := sqlcgen.New(tx).ListOrderAdjustmentsByOrderID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range adjustments {
+			order.Adjustments = append(order.Adjustments, orderAdjustmentToDomain(a))
+		}
+
+		if order.Tax != nil {
+			lines, err := sqlcgen.New(tx).ListOrderTaxLinesByOrderID(ctx, id)
+			if err != nil {
+				return nil, err
+			}
+			order.Tax.Lines = make([]domain.OrderTaxLine, len(lines))
+			for i, line := range lines {
+				order.Tax.Lines[i] = orderTaxLineToDomain(line)
+			}
 		}
 		return order, nil
 	})
 }
 
+// Create stores the order along with the history entry for its opening.
 func (r *OrderRepo) Create(ctx context.Context, order *domain.Order) error {
 	return rlsfx.Exec(r.db, ctx, func(ctx context.Context, tx pgx.Tx) error {
-		return sqlcgen.New(tx).CreateOrder(ctx, orderCreateParams(order))
+		q := sqlcgen.New(tx)
+		if err := q.CreateOrder(ctx, orderCreateParams(order)); err != nil {
+			return err
+		}
+		return q.CreateOrderStatusChange(ctx, orderStatusChangeCreateParams(order.Opened()))
 	})
 }
 
-func (r *OrderRepo) Close(ctx context.Context, id uuid.UUID, updatedAt time.Time) (*domain.Order, error) {
-	return rlsfx.Query(r.db, ctx, func(ctx context.Context, tx pgx.Tx) (*domain.Order, error) {
-		m, err := sqlcgen.New(tx).CloseOrder(ctx, orderCloseParams(id, updatedAt))
+func (r *OrderRepo) Transition(ctx context.Context, order *domain.Order, change domain.OrderStatusChange) error {
+	return rlsfx.Exec(r.db, ctx, func(ctx context.Context, tx pgx.Tx) error {
+		q := sqlcgen.New(tx)
+		params := orderTransitionParams(order, change.From)
+		if change.FixesPrice() {
+			if err := setOrderPrice(&params, order); err != nil {
+				return err
+			}
+		}
+		n, err := q.TransitionOrder(ctx, params)
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return coredomain.NewError(coredomain.CodeConflict, "order status changed concurrently")
+		}
+		if err := q.CreateOrderStatusChange(ctx, orderStatusChangeCreateParams(change)); err != nil {
+			return err
+		}
+		if err := settlePromotions(ctx, q, order, change); err != nil {
+			return err
+		}
+		if err := settleTax(ctx, q, order, change); err != nil {
+			return err
+		}
+		return settleStock(ctx, q, change)
+	})
+}
+
+func (r *OrderRepo) ListStatusHistory(ctx context.Context, orderID uuid.UUID) ([]domain.OrderStatusChange, error) {
+	return rlsfx.Query(r.db, ctx, func(ctx context.Context, tx pgx.Tx) ([]domain.OrderStatusChange, error) {
+		rows, err := sqlcgen.New(tx).ListOrderStatusHistory(ctx, orderID)
+		if err != nil {
+			return nil, err
+		}
+		history := make([]domain.OrderStatusChange, len(rows))
+		for i, row := range rows {
+			history[i] = orderStatusChangeToDomain(row)
+		}
+		return history, nil
+	})
+}
+
+func (r *OrderRepo) List(ctx context.Context, filter domain.OrderFilter, after *uuid.UUID, limit int32) (*domain.OrderPage, error) {
+	return rlsfx.Query(r.db, ctx, func(ctx context.Context, tx pgx.Tx) (*domain.OrderPage, error) {
+		// One row more than the page tells whether another page follows.
+		rows, err := sqlcgen.New(tx).ListOrders(ctx, orderListParams(filter, after, limit+1))
 		if err != nil {
 			return nil, err
 		}
-		return orderToDomain(m), nil
+		page := &domain.OrderPage{Orders: make([]domain.OrderSummary, 0, min(len(rows), int(limit)))}
+		for i, row := range rows {
+			if i == int(limit) {
+				next := page.Orders[i-1].Order.ID
+				page.Next = &next
+				break
+			}
+			summary, err := orderSummaryToDomain(row)
+			if err != nil {
+				return nil, err
+			}
+			page.Orders = append(page.Orders, summary)
+		}
+		return page, nil
+	})
+}
+
+func (r *OrderRepo) Lock(ctx context.Context, id uuid.UUID, fn func(ctx context.Context) error) error {
+	return rlsfx.Exec(r.db, ctx, func(ctx context.Context, tx pgx.Tx) error {
+		if _, err := sqlcgen.New(tx).LockOrderStatus(ctx, id); err != nil {
+			return err
+		}
+		return fn(ctx)
 	})
 }
 
 func (r *OrderRepo) CreateItem(ctx context.Context, item *domain.OrderItem) error {
 	return rlsfx.Exec(r.db, ctx, func(ctx context.Context, tx pgx.Tx) error {
-		return sqlcgen.New(tx).CreateOrderItem(ctx, orderItemCreateParams(item))
+		q := sqlcgen.New(tx)
+		if err := lockOpenOrder(ctx, q, item.OrderID, item.CreatedAt); err != nil {
+			return err
+		}
+		if err := reserveStock(ctx, q, item, item.Quantity, item.CreatedAt); err != nil {
+			return err
+		}
+		params, err := orderItemCreateParams(item)
+		if err != nil {
+			return err
+		}
+		n, err := q.CreateOrderItem(ctx, params)
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return coredomain.NewError(coredomain.CodeConflict, "order items changed concurrently")
+		}
+		return nil
+	})
+}
+
+func (r *OrderRepo) UpdateItemQuantity(ctx context.Context, item *domain.OrderItem, quantity int32, at time.Time) error {
+	return rlsfx.Exec(r.db, ctx, func(ctx context.Context, tx pgx.Tx) error {
+		q := sqlcgen.New(tx)
+		if err := lockOpenOrder(ctx, q, item.OrderID, at); err != nil {
+			return err
+		}
+		updated := *item
+		updated.Quantity = quantity
+		if err := reserveStock(ctx, q, &updated, quantity, at); err != nil {
+			return err
+		}
+		n, err := q.UpdateOrderItemQuantity(ctx, sqlcgen.UpdateOrderItemQuantityParams{
+			Quantity:             updated.Quantity,
+			ReservedQuantity:     updated.ReservedQuantity,
+			ID:                   item.ID,
+			FromQuantity:         item.Quantity,
+			FromReservedQuantity: item.ReservedQuantity,
+		})
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return coredomain.NewError(coredomain.CodeConflict, "order item changed concurrently")
+		}
+		*item = updated
+		return nil
+	})
+}
+
+func (r *OrderRepo) DeleteItem(ctx context.Context, item *domain.OrderItem, at time.Time) error {
+	return rlsfx.Exec(r.db, ctx, func(ctx context.Context, tx pgx.Tx) error {
+		q := sqlcgen.New(tx)
+		if err := lockOpenOrder(ctx, q, item.OrderID, at); err != nil {
+			return err
+		}
+		reserved, err := q.DeleteOrderItem(ctx, item.ID)
+		if err != nil {
+			return err
+		}
+		deleted := *item
+		deleted.ReservedQuantity = reserved
+		return reserveStock(ctx, q, &deleted, 0, at)
 	})
 }
 
@@ -70,8 +235,91 @@ func (r *OrderRepo) ListItemsByOrderID(ctx context.Context, orderID uuid.UUID) (
 		}
 		items := make([]domain.OrderItem, len(rows))
 		for i, row := range rows {
-			items[i] = orderItemToDomain(row)
+			if items[i], err = orderItemToDomain(row); err != nil {
+				return nil, err
+			}
 		}
 		return items, nil
 	})
 }
+
+// lockOpenOrder locks the order's row within tx and checks that its items
+// can still be changed at at: the order is open and no payment step has it
+// claimed.
+func lockOpenOrder(ctx context.Context, q *sqlcgen.Queries, orderID uuid.UUID, at time.Time) error {
+	status, err := q.LockOrderStatus(ctx, orderID)
+	if err != nil {
+		return err
+	}
+	order := domain.Order{Status: domain.OrderStatus(status)}
+	if err := order.CanChangeItems(); err != nil {
+		return err
+	}
+	claimed, err := q.IsOrderPaymentClaimed(ctx, sqlcgen.IsOrderPaymentClaimedParams{OrderID: orderID, Now: at})
+	if err != nil {
+		return err
+	}
+	if claimed {
+		return domain.NewPaymentInProgressError()
+	}
+	return nil
+}
+
+func orderListParams(f domain.OrderFilter, after *uuid.UUID, pageSize int32) sqlcgen.ListOrdersParams {
+	params := sqlcgen.ListOrdersParams{
+		After:       after,
+		CreatedFrom: f.CreatedFrom,
+		CreatedTo:   f.CreatedTo,
+		ProductID:   f.ProductID,
+		PageSize:    pageSize,
+	}
+	if f.Status != nil {
+		status := string(*f.Status)
+		params.Status = &status
+	}
+	if f.HasTotal() {
+		currency := string(f.TotalCurrency)
+		params.TotalCurrency = &currency
+		params.MinTotal = f.MinTotal
+		params.MaxTotal = f.MaxTotal
+	}
+	return params
+}
+
+// orderListItem is an item as ListOrders aggregates it.
+type orderListItem struct {
+	ID               uuid.UUID         `json:"id"`
+	ProductID        uuid.UUID         `json:"product_id"`
+	ProductName      string            `json:"product_name"`
+	ProductCategory  string            `json:"product_category"`
+	SystemCreatedAt  time.Time         `json:"system_created_at"`
+	Quantity         int32             `json:"quantity"`
+	ReservedQuantity int32             `json:"reserved_quantity"`
+	UnitPrice        datatype.Money    `json:"unit_price"`
+	Options          []orderItemOption `json:"options"`
+}
+
+func orderSummaryToDomain(row sqlcgen.ListOrdersRow) (domain.OrderSummary, error) {
+	var items []orderListItem
+	if err := json.Unmarshal(row.Items, &items); err != nil {
+		return domain.OrderSummary{}, fmt.Errorf("decoding items of order %s: %w", row.Order.ID, err)
+	}
+	order := orderToDomain(row.Order)
+	order.Items = make([]domain.OrderItem, len(items))
+	for i, item := range items {
+		order.Items[i] = domain.OrderItem{
+			ID:               item.ID,
+			OrganizationID:   order.OrganizationID,
+			OrderID:          order.ID,
+			ProductID:        item.ProductID,
+			ProductName:      item.ProductName,
+			ProductCategory:  domain.ProductCategory(item.ProductCategory),
+			CreatedAt:        item.SystemCreatedAt,
+			Quantity:         item.Quantity,
+			UnitPrice:        item.UnitPrice,
+			ReservedQuantity: item.ReservedQuantity,
+			Options:          orderItemOptionsToDomain(item.Options),
+		}
+	}
+	return domain.OrderSummary{Order: *order, Total: row.Order.Total}, nil
+}
@@ -0,0 +1,221 @@
+package persistence
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	coredomain "github.com/bbsbb/go-edge/core/domain"
+	"github.com/bbsbb/go-edge/core/fx/rlsfx"
+	"github.com/bbsbb/go-edge/sweetshop/internal/domain"
+	"github.com/bbsbb/go-edge/sweetshop/internal/infrastructure/persistence/sqlcgen"
+)
+
+// orderEventsChannel is the NOTIFY channel written by the orders_record_*_event triggers.
+const orderEventsChannel = "app_sweetshop_order_events"
+
+const (
+	orderEventBuffer      = 64
+	orderEventRetryPeriod = time.Second
+)
+
+type OrderEventRepo struct {
+	db *rlsfx.DB
+}
+
+func NewOrderEventRepo(db *rlsfx.DB) *OrderEventRepo {
+	return &OrderEventRepo{db: db}
+}
+
+func (r *OrderEventRepo) ListAfter(ctx context.Context, afterID uuid.UUID, limit int32) ([]domain.OrderEvent, error) {
+	return rlsfx.Query(r.db, ctx, func(ctx context.Context, tx pgx.Tx) ([]domain.OrderEvent, error) {
+		rows, err := sqlcgen.New(tx).ListOrderEventsAfter(ctx, sqlcgen.ListOrderEventsAfterParams{
+			ID:    afterID,
+			Limit: limit,
+		})
+		if err != nil {
+			return nil, err
+		}
+		events := make([]domain.OrderEvent, len(rows))
+		for i, row := range rows {
+			events[i] = orderEventToDomain(row)
+		}
+		return events, nil
+	})
+}
+
+// OrderEventBus fans order event notifications out to per-organization
+// subscribers. NOTIFY payloads are not subject to RLS, so each event is routed
+// by the organization_id it carries.
+type OrderEventBus struct {
+	subscribe func() (<-chan orderEventNotification, func(), error)
+	logger    *slog.Logger
+
+	mu   sync.Mutex
+	subs map[uuid.UUID]map[chan domain.OrderEvent]struct{}
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewOrderEventBus takes the notification source as a subscribe function so
+// the bus can subscribe again when the source drops it.
+func NewOrderEventBus(subscribe func() (<-chan orderEventNotification, func(), error), logger *slog.Logger) *OrderEventBus {
+	return &OrderEventBus{
+		subscribe: subscribe,
+		logger:    logger,
+		subs:      make(map[uuid.UUID]map[chan domain.OrderEvent]struct{}),
+	}
+}
+
+// Subscribe registers a subscriber for the organization in ctx. A subscriber
+// that lets its buffer fill up is dropped and its channel closed.
+func (b *OrderEventBus) Subscribe(ctx context.Context) (<-chan domain.OrderEvent, func(), error) {
+	org, err := coredomain.OrganizationFromContext(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ch := make(chan domain.OrderEvent, orderEventBuffer)
+	b.mu.Lock()
+	if b.subs[org.ID] == nil {
+		b.subs[org.ID] = make(map[chan domain.OrderEvent]struct{})
+	}
+	b.subs[org.ID][ch] = struct{}{}
+	b.mu.Unlock()
+
+	return ch, func() { b.remove(org.ID, ch) }, nil
+}
+
+// Reset closes every subscriber channel. It runs after the listener
+// reconnects, since events sent meanwhile were lost; clients reconnect and
+// replay them from their Last-Event-ID.
+func (b *OrderEventBus) Reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for orgID, subs := range b.subs {
+		for ch := range subs {
+			b.removeLocked(orgID, ch)
+		}
+	}
+}
+
+func (b *OrderEventBus) remove(orgID uuid.UUID, ch chan domain.OrderEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.removeLocked(orgID, ch)
+}
+
+func (b *OrderEventBus) removeLocked(orgID uuid.UUID, ch chan domain.OrderEvent) {
+	subs, ok := b.subs[orgID]
+	if !ok {
+		return
+	}
+	if _, ok := subs[ch]; !ok {
+		return
+	}
+	delete(subs, ch)
+	close(ch)
+	if len(subs) == 0 {
+		delete(b.subs, orgID)
+	}
+}
+
+// Start begins consuming notifications in the background.
+func (b *OrderEventBus) Start(_ context.Context) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	b.cancel = cancel
+	b.done = make(chan struct{})
+	go b.run(ctx)
+	return nil
+}
+
+// Stop stops consuming and closes every subscriber channel.
+func (b *OrderEventBus) Stop(ctx context.Context) error {
+	if b.cancel == nil {
+		return nil
+	}
+	b.cancel()
+	select {
+	case <-b.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	b.Reset()
+	return nil
+}
+
+func (b *OrderEventBus) run(ctx context.Context) {
+	defer close(b.done)
+	for {
+		err := b.consume(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		b.logger.Warn("order event source closed, resubscribing", "error", err, "retry_in", orderEventRetryPeriod)
+		b.Reset()
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(orderEventRetryPeriod):
+		}
+	}
+}
+
+func (b *OrderEventBus) consume(ctx context.Context) error {
+	events, closeEvents, err := b.subscribe()
+	if err != nil {
+		return err
+	}
+	defer closeEvents()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case n, ok := <-events:
+			if !ok {
+				return nil
+			}
+			b.dispatch(ctx, n.toDomain())
+		}
+	}
+}
+
+type orderEventNotification struct {
+	ID             uuid.UUID `json:"id"`
+	OrganizationID uuid.UUID `json:"organization_id"`
+	OrderID        uuid.UUID `json:"order_id"`
+	OccurredAt     time.Time `json:"occurred_at"`
+	Type           string    `json:"type"`
+	Status         string    `json:"status"`
+}
+
+func (n orderEventNotification) toDomain() domain.OrderEvent {
+	return domain.OrderEvent{
+		ID:             n.ID,
+		OrganizationID: n.OrganizationID,
+		OrderID:        n.OrderID,
+		OccurredAt:     n.OccurredAt,
+		Type:           domain.OrderEventType(n.Type),
+		Status:         domain.OrderStatus(n.Status),
+	}
+}
+
+func (b *OrderEventBus) dispatch(ctx context.Context, event domain.OrderEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for ch := range b.subs[event.OrganizationID] {
+		select {
+		case ch <- event:
+		default:
+			b.logger.WarnContext(ctx, "dropping slow order event subscriber",
+				"organization_id", event.OrganizationID)
+			b.removeLocked(event.OrganizationID, ch)
+		}
+	}
+}
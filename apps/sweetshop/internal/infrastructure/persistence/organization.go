@@ -5,10 +5,12 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 
 	coredomain "github.com/bbsbb/go-edge/core/domain"
 	"github.com/bbsbb/go-edge/core/fx/psqlfx"
+	"github.com/bbsbb/go-edge/sweetshop/internal/domain"
 	"github.com/bbsbb/go-edge/sweetshop/internal/infrastructure/persistence/sqlcgen"
 )
 
@@ -54,3 +56,34 @@ func (r *OrganizationRepo) Create(ctx context.Context, org *coredomain.Organizat
 	err := sqlcgen.New(r.conn(ctx)).CreateOrganization(ctx, organizationCreateParams(org, time.Now()))
 	return psqlfx.TranslateError(err)
 }
+
+func (r *OrganizationRepo) List(ctx context.Context) ([]*coredomain.Organization, error) {
+	rows, err := sqlcgen.New(r.conn(ctx)).ListOrganizations(ctx)
+	if err != nil {
+		return nil, psqlfx.TranslateError(err)
+	}
+	orgs := make([]*coredomain.Organization, len(rows))
+	for i, m := range rows {
+		orgs[i] = organizationToDomain(m)
+	}
+	return orgs, nil
+}
+
+func (r *OrganizationRepo) FindSettings(ctx context.Context, id uuid.UUID) (*domain.OrganizationSettings, error) {
+	m, err := sqlcgen.New(r.conn(ctx)).FindOrganizationByID(ctx, id)
+	if err != nil {
+		return nil, psqlfx.TranslateError(err)
+	}
+	return organizationSettingsToDomain(m), nil
+}
+
+func (r *OrganizationRepo) UpdateSettings(ctx context.Context, settings *domain.OrganizationSettings) error {
+	n, err := sqlcgen.New(r.conn(ctx)).UpdateOrganizationSettings(ctx, organizationSettingsUpdateParams(settings))
+	if err != nil {
+		return psqlfx.TranslateError(err)
+	}
+	if n == 0 {
+		return psqlfx.TranslateError(pgx.ErrNoRows)
+	}
+	return nil
+}
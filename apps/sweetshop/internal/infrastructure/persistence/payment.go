@@ -0,0 +1,64 @@
+package persistence
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/bbsbb/go-edge/core/fx/rlsfx"
+	"github.com/bbsbb/go-edge/sweetshop/internal/domain"
+	"github.com/bbsbb/go-edge/sweetshop/internal/infrastructure/persistence/sqlcgen"
+)
+
+type PaymentRepo struct {
+	db *rlsfx.DB
+}
+
+func NewPaymentRepo(db *rlsfx.DB) *PaymentRepo {
+	return &PaymentRepo{db: db}
+}
+
+func (r *PaymentRepo) Create(ctx context.Context, payment *domain.Payment) error {
+	return rlsfx.Exec(r.db, ctx, func(ctx context.Context, tx pgx.Tx) error {
+		return sqlcgen.New(tx).CreatePayment(ctx, paymentCreateParams(payment))
+	})
+}
+
+func (r *PaymentRepo) ListByOrderID(ctx context.Context, orderID uuid.UUID) ([]domain.Payment, error) {
+	return rlsfx.Query(r.db, ctx, func(ctx context.Context, tx pgx.Tx) ([]domain.Payment, error) {
+		rows, err := sqlcgen.New(tx).ListPaymentsByOrderID(ctx, orderID)
+		if err != nil {
+			return nil, err
+		}
+		return paymentsToDomain(rows), nil
+	})
+}
+
+func (r *PaymentRepo) Claim(ctx context.Context, order *domain.Order, token uuid.UUID, now, until time.Time) (bool, error) {
+	return rlsfx.Query(r.db, ctx, func(ctx context.Context, tx pgx.Tx) (bool, error) {
+		n, err := sqlcgen.New(tx).ClaimOrderPayment(ctx, sqlcgen.ClaimOrderPaymentParams{
+			OrderID:        order.ID,
+			OrganizationID: order.OrganizationID,
+			Token:          token,
+			ClaimedUntil:   until,
+			Now:            now,
+		})
+		return n == 1, err
+	})
+}
+
+func (r *PaymentRepo) Release(ctx context.Context, orderID, token uuid.UUID) error {
+	return rlsfx.Exec(r.db, ctx, func(ctx context.Context, tx pgx.Tx) error {
+		return sqlcgen.New(tx).ReleaseOrderPayment(ctx, sqlcgen.ReleaseOrderPaymentParams{OrderID: orderID, Token: token})
+	})
+}
+
+func paymentsToDomain(rows []sqlcgen.Payment) []domain.Payment {
+	payments := make([]domain.Payment, len(rows))
+	for i, row := range rows {
+		payments[i] = paymentToDomain(row)
+	}
+	return payments
+}
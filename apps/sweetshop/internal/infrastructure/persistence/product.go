@@ -2,6 +2,8 @@ package persistence
 
 import (
 	"context"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
@@ -62,9 +64,9 @@ func (r *ProductRepo) Update(ctx context.Context, product *domain.Product) error
 	})
 }
 
-func (r *ProductRepo) Delete(ctx context.Context, id uuid.UUID) error {
+func (r *ProductRepo) Delete(ctx context.Context, id uuid.UUID, deletedAt time.Time) error {
 	return rlsfx.Exec(r.db, ctx, func(ctx context.Context, tx pgx.Tx) error {
-		n, err := sqlcgen.New(tx).DeleteProduct(ctx, id)
+		n, err := sqlcgen.New(tx).SoftDeleteProduct(ctx, productSoftDeleteParams(id, deletedAt))
 		if err != nil {
 			return err
 		}
@@ -74,3 +76,57 @@ func (r *ProductRepo) Delete(ctx context.Context, id uuid.UUID) error {
 		return nil
 	})
 }
+
+func (r *ProductRepo) Restore(ctx context.Context, id uuid.UUID, restoredAt time.Time) (*domain.Product, error) {
+	return rlsfx.Query(r.db, ctx, func(ctx context.Context, tx pgx.Tx) (*domain.Product, error) {
+		m, err := sqlcgen.New(tx).RestoreProduct(ctx, productRestoreParams(id, restoredAt))
+		if err != nil {
+			return nil, err
+		}
+		return productToDomain(m), nil
+	})
+}
+
+// PurgeDeleted hard-deletes products soft-deleted before deletedBefore.
+// Products still referenced by order items are kept so order history resolves.
+func (r *ProductRepo) PurgeDeleted(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	return rlsfx.Query(r.db, ctx, func(ctx context.Context, tx pgx.Tx) (int64, error) {
+		return sqlcgen.New(tx).PurgeDeletedProducts(ctx, &deletedBefore)
+	})
+}
+
+func (r *ProductRepo) Search(ctx context.Context, query domain.ProductSearch) ([]domain.ProductMatch, error) {
+	terms := query.Terms()
+	return rlsfx.Query(r.db, ctx, func(ctx context.Context, tx pgx.Tx) ([]domain.ProductMatch, error) {
+		rows, err := sqlcgen.New(tx).SearchProducts(ctx, sqlcgen.SearchProductsParams{
+			Term:        strings.Join(terms, " "),
+			PrefixQuery: prefixQuery(terms),
+			PageSize:    query.Limit,
+		})
+		if err != nil {
+			return nil, err
+		}
+		matches := make([]domain.ProductMatch, len(rows))
+		for i, m := range rows {
+			matches[i] = domain.ProductMatch{
+				Product:              productToDomain(m.Product),
+				Rank:                 m.Rank,
+				Similarity:           m.Similarity,
+				NameHighlight:        m.NameHighlight,
+				DescriptionHighlight: m.DescriptionHighlight,
+			}
+		}
+		return matches, nil
+	})
+}
+
+// prefixQuery returns a tsquery matching documents with a word starting with
+// each of terms. Terms are letters and digits only, so none of them can be
+// read as tsquery syntax.
+func prefixQuery(terms []string) string {
+	prefixes := make([]string, len(terms))
+	for i, t := range terms {
+		prefixes[i] = t + ":*"
+	}
+	return strings.Join(prefixes, " & ")
+}
@@ -0,0 +1,132 @@
+package persistence
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	coredomain "github.com/bbsbb/go-edge/core/domain"
+	"github.com/bbsbb/go-edge/core/fx/rlsfx"
+	"github.com/bbsbb/go-edge/sweetshop/internal/domain"
+	"github.com/bbsbb/go-edge/sweetshop/internal/infrastructure/persistence/sqlcgen"
+)
+
+// Imports are copied into a temporary table and merged into products from
+// there. sqlc cannot see a temporary table, so these statements are kept here
+// rather than in queries/. Prices are copied as their two fields since COPY
+// cannot encode the money composite type.
+const (
+	createProductImportTable = `CREATE TEMPORARY TABLE product_imports (
+    id UUID NOT NULL,
+    organization_id UUID NOT NULL,
+    system_created_at TIMESTAMPTZ NOT NULL,
+    system_updated_at TIMESTAMPTZ NOT NULL,
+    name TEXT NOT NULL,
+    category TEXT NOT NULL,
+    price_amount BIGINT NOT NULL,
+    price_currency TEXT NOT NULL,
+    sku TEXT,
+    description TEXT NOT NULL,
+    active BOOLEAN NOT NULL,
+    sort_order INTEGER NOT NULL
+) ON COMMIT DROP`
+
+	// A product deleted since it was read is left alone, so fewer rows than
+	// were copied means the import is out of date.
+	mergeProductImports = `INSERT INTO app_sweetshop.products AS p
+    (id, organization_id, system_created_at, system_updated_at, name, category, price, sku, description, active, sort_order)
+SELECT i.id, i.organization_id, i.system_created_at, i.system_updated_at, i.name, i.category,
+    ROW(i.price_amount, i.price_currency)::app_sweetshop.money, i.sku, i.description, i.active, i.sort_order
+FROM product_imports i
+ORDER BY i.id
+ON CONFLICT (id) DO UPDATE
+SET system_updated_at = EXCLUDED.system_updated_at, name = EXCLUDED.name, category = EXCLUDED.category,
+    price = EXCLUDED.price, sku = EXCLUDED.sku, description = EXCLUDED.description,
+    active = EXCLUDED.active, sort_order = EXCLUDED.sort_order
+WHERE p.deleted_at IS NULL`
+
+	// Dropped explicitly as well, since inside an outer transaction the
+	// import's own commit only releases a savepoint.
+	dropProductImportTable = `DROP TABLE product_imports`
+
+	// The query exports are read through, also kept here: Each reads it
+	// through a cursor, which sqlc's :many queries cannot do, as they collect
+	// every row before returning. The order is ListProducts'.
+	listProductRows = `SELECT id, organization_id, system_created_at, system_updated_at, name, category,
+    deleted_at, price, sku, description, active, sort_order
+FROM app_sweetshop.products
+WHERE deleted_at IS NULL
+ORDER BY sort_order, name`
+)
+
+var productImportColumns = []string{
+	"id", "organization_id", "system_created_at", "system_updated_at", "name", "category",
+	"price_amount", "price_currency", "sku", "description", "active", "sort_order",
+}
+
+// Upsert copies products into a temporary table and merges them into
+// products with one statement, in the same transaction.
+func (r *ProductRepo) Upsert(ctx context.Context, products []*domain.Product) error {
+	return rlsfx.Exec(r.db, ctx, func(ctx context.Context, tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, createProductImportTable); err != nil {
+			return err
+		}
+		rows := make([][]any, len(products))
+		for i, p := range products {
+			rows[i] = []any{
+				p.ID, p.OrganizationID, p.CreatedAt, p.UpdatedAt, p.Name, string(p.Category),
+				p.Price.Amount(), string(p.Price.Currency()), p.SKU, p.Description, p.Active, p.SortOrder,
+			}
+		}
+		if _, err := tx.CopyFrom(ctx, pgx.Identifier{"product_imports"}, productImportColumns, pgx.CopyFromRows(rows)); err != nil {
+			return err
+		}
+
+		tag, err := tx.Exec(ctx, mergeProductImports)
+		if err != nil {
+			return err
+		}
+		if n := tag.RowsAffected(); n != int64(len(products)) {
+			return coredomain.NewError(coredomain.CodeConflict,
+				fmt.Sprintf("%d of the imported products were deleted meanwhile", int64(len(products))-n))
+		}
+		_, err = tx.Exec(ctx, dropProductImportTable)
+		return err
+	})
+}
+
+// Each reads the live products through one cursor, in the transaction that
+// scopes them to the organization, and calls fn with each as it is read.
+func (r *ProductRepo) Each(ctx context.Context, fn func(*domain.Product) error) error {
+	return rlsfx.Exec(r.db, ctx, func(ctx context.Context, tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, listProductRows)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var m sqlcgen.Product
+			if err := rows.Scan(
+				&m.ID,
+				&m.OrganizationID,
+				&m.SystemCreatedAt,
+				&m.SystemUpdatedAt,
+				&m.Name,
+				&m.Category,
+				&m.DeletedAt,
+				&m.Price,
+				&m.Sku,
+				&m.Description,
+				&m.Active,
+				&m.SortOrder,
+			); err != nil {
+				return err
+			}
+			if err := fn(productToDomain(m)); err != nil {
+				return err
+			}
+		}
+		return rows.Err()
+	})
+}
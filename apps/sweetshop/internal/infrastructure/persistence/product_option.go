@@ -0,0 +1,152 @@
+package persistence
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/bbsbb/go-edge/core/datatype"
+	"github.com/bbsbb/go-edge/core/fx/rlsfx"
+	"github.com/bbsbb/go-edge/sweetshop/internal/domain"
+	"github.com/bbsbb/go-edge/sweetshop/internal/infrastructure/persistence/sqlcgen"
+)
+
+type ProductOptionRepo struct {
+	db *rlsfx.DB
+}
+
+func NewProductOptionRepo(db *rlsfx.DB) *ProductOptionRepo {
+	return &ProductOptionRepo{db: db}
+}
+
+func (r *ProductOptionRepo) ListByProductID(ctx context.Context, productID uuid.UUID) ([]domain.OptionGroup, error) {
+	return rlsfx.Query(r.db, ctx, func(ctx context.Context, tx pgx.Tx) ([]domain.OptionGroup, error) {
+		q := sqlcgen.New(tx)
+		groups, err := q.ListProductOptionGroups(ctx, productID)
+		if err != nil {
+			return nil, err
+		}
+		options, err := q.ListProductOptions(ctx, productID)
+		if err != nil {
+			return nil, err
+		}
+		return optionGroupsToDomain(groups, options), nil
+	})
+}
+
+// Replace deletes the product's groups and creates groups in their place, so
+// groups and options keep their IDs when they are passed again.
+func (r *ProductOptionRepo) Replace(ctx context.Context, productID uuid.UUID, groups []domain.OptionGroup) error {
+	return rlsfx.Exec(r.db, ctx, func(ctx context.Context, tx pgx.Tx) error {
+		q := sqlcgen.New(tx)
+		if err := q.DeleteProductOptionGroups(ctx, productID); err != nil {
+			return err
+		}
+		for _, g := range groups {
+			if err := q.CreateProductOptionGroup(ctx, optionGroupCreateParams(&g)); err != nil {
+				return err
+			}
+			for _, o := range g.Options {
+				if err := q.CreateProductOption(ctx, optionCreateParams(&g, &o)); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// optionGroupsToDomain returns groups with their options, keeping the order
+// both were listed in.
+func optionGroupsToDomain(groups []sqlcgen.ProductOptionGroup, options []sqlcgen.ProductOption) []domain.OptionGroup {
+	out := make([]domain.OptionGroup, len(groups))
+	index := make(map[uuid.UUID]int, len(groups))
+	for i, m := range groups {
+		index[m.ID] = i
+		out[i] = domain.OptionGroup{
+			ID:             m.ID,
+			OrganizationID: m.OrganizationID,
+			ProductID:      m.ProductID,
+			Name:           m.Name,
+			Kind:           domain.OptionGroupKind(m.Kind),
+			MinSelections:  m.MinSelections,
+			MaxSelections:  m.MaxSelections,
+			SortOrder:      m.SortOrder,
+		}
+	}
+	for _, m := range options {
+		g := &out[index[m.GroupID]]
+		g.Options = append(g.Options, domain.ProductOption{
+			ID:         m.ID,
+			GroupID:    m.GroupID,
+			Name:       m.Name,
+			PriceDelta: m.PriceDelta,
+			SortOrder:  m.SortOrder,
+		})
+	}
+	return out
+}
+
+func optionGroupCreateParams(g *domain.OptionGroup) sqlcgen.CreateProductOptionGroupParams {
+	return sqlcgen.CreateProductOptionGroupParams{
+		ID:             g.ID,
+		OrganizationID: g.OrganizationID,
+		ProductID:      g.ProductID,
+		Name:           g.Name,
+		Kind:           string(g.Kind),
+		MinSelections:  g.MinSelections,
+		MaxSelections:  g.MaxSelections,
+		SortOrder:      g.SortOrder,
+	}
+}
+
+func optionCreateParams(g *domain.OptionGroup, o *domain.ProductOption) sqlcgen.CreateProductOptionParams {
+	return sqlcgen.CreateProductOptionParams{
+		ID:             o.ID,
+		OrganizationID: g.OrganizationID,
+		GroupID:        g.ID,
+		Name:           o.Name,
+		PriceDelta:     o.PriceDelta,
+		SortOrder:      o.SortOrder,
+	}
+}
+
+// orderItemOption is an option chosen for an order item as order_items.options
+// stores it.
+type orderItemOption struct {
+	OptionID   uuid.UUID      `json:"option_id"`
+	Group      string         `json:"group"`
+	Name       string         `json:"name"`
+	PriceDelta datatype.Money `json:"price_delta"`
+}
+
+// encodeOrderItemOptions returns options as order_items.options stores them,
+// an empty array when there are none.
+func encodeOrderItemOptions(options []domain.OrderItemOption) ([]byte, error) {
+	stored := make([]orderItemOption, len(options))
+	for i, o := range options {
+		stored[i] = orderItemOption(o)
+	}
+	return json.Marshal(stored)
+}
+
+func decodeOrderItemOptions(data []byte) ([]domain.OrderItemOption, error) {
+	var stored []orderItemOption
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return nil, err
+	}
+	return orderItemOptionsToDomain(stored), nil
+}
+
+func orderItemOptionsToDomain(stored []orderItemOption) []domain.OrderItemOption {
+	if len(stored) == 0 {
+		return nil
+	}
+	options := make([]domain.OrderItemOption, len(stored))
+	for i, o := range stored {
+		options[i] = domain.OrderItemOption(o)
+	}
+	return options
+}
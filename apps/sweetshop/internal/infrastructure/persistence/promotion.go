@@ -0,0 +1,242 @@
+package persistence
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	coredomain "github.com/bbsbb/go-edge/core/domain"
+	"github.com/bbsbb/go-edge/core/fx/rlsfx"
+	"github.com/bbsbb/go-edge/sweetshop/internal/domain"
+	"github.com/bbsbb/go-edge/sweetshop/internal/infrastructure/persistence/sqlcgen"
+)
+
+type PromotionRepo struct {
+	db *rlsfx.DB
+}
+
+func NewPromotionRepo(db *rlsfx.DB) *PromotionRepo {
+	return &PromotionRepo{db: db}
+}
+
+func (r *PromotionRepo) FindByID(ctx context.Context, id uuid.UUID) (*domain.Promotion, error) {
+	return rlsfx.Query(r.db, ctx, func(ctx context.Context, tx pgx.Tx) (*domain.Promotion, error) {
+		m, err := sqlcgen.New(tx).FindPromotionByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		return promotionToDomain(m), nil
+	})
+}
+
+func (r *PromotionRepo) List(ctx context.Context) ([]*domain.Promotion, error) {
+	return rlsfx.Query(r.db, ctx, func(ctx context.Context, tx pgx.Tx) ([]*domain.Promotion, error) {
+		rows, err := sqlcgen.New(tx).ListPromotions(ctx)
+		if err != nil {
+			return nil, err
+		}
+		promotions := make([]*domain.Promotion, len(rows))
+		for i, m := range rows {
+			promotions[i] = promotionToDomain(m)
+		}
+		return promotions, nil
+	})
+}
+
+func (r *PromotionRepo) ListActive(ctx context.Context, at time.Time) ([]domain.Promotion, error) {
+	return rlsfx.Query(r.db, ctx, func(ctx context.Context, tx pgx.Tx) ([]domain.Promotion, error) {
+		rows, err := sqlcgen.New(tx).ListActivePromotions(ctx, at)
+		if err != nil {
+			return nil, err
+		}
+		promotions := make([]domain.Promotion, len(rows))
+		for i, m := range rows {
+			promotions[i] = *promotionToDomain(m)
+		}
+		return promotions, nil
+	})
+}
+
+func (r *PromotionRepo) Create(ctx context.Context, promotion *domain.Promotion) error {
+	return rlsfx.Exec(r.db, ctx, func(ctx context.Context, tx pgx.Tx) error {
+		return sqlcgen.New(tx).CreatePromotion(ctx, promotionCreateParams(promotion))
+	})
+}
+
+func (r *PromotionRepo) Update(ctx context.Context, promotion *domain.Promotion) error {
+	return rlsfx.Exec(r.db, ctx, func(ctx context.Context, tx pgx.Tx) error {
+		n, err := sqlcgen.New(tx).UpdatePromotion(ctx, promotionUpdateParams(promotion))
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return pgx.ErrNoRows
+		}
+		return nil
+	})
+}
+
+// Delete removes the promotion. Adjustments it made to submitted orders are
+// kept.
+func (r *PromotionRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	return rlsfx.Exec(r.db, ctx, func(ctx context.Context, tx pgx.Tx) error {
+		n, err := sqlcgen.New(tx).DeletePromotion(ctx, id)
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return pgx.ErrNoRows
+		}
+		return nil
+	})
+}
+
+// settlePromotions stores the order's adjustments and counts the uses of
+// their promotions, or gives the uses back, as the change says. It fails
+// with an invariant error if a promotion ran out of uses after the order was
+// priced.
+func settlePromotions(ctx context.Context, q *sqlcgen.Queries, order *domain.Order, change domain.OrderStatusChange) error {
+	switch change.PromotionSettlement() {
+	case domain.PromotionsRedeemed:
+		for i := range order.Adjustments {
+			if err := q.CreateOrderAdjustment(ctx, orderAdjustmentCreateParams(&order.Adjustments[i])); err != nil {
+				return err
+			}
+		}
+		redeemed := order.RedeemedPromotions()
+		if len(redeemed) == 0 {
+			return nil
+		}
+		n, err := q.RedeemOrderPromotions(ctx, sqlcgen.RedeemOrderPromotionsParams{
+			SystemUpdatedAt: change.At,
+			OrderID:         change.OrderID,
+		})
+		if err != nil {
+			return err
+		}
+		if n != int64(len(redeemed)) {
+			return coredomain.NewError(coredomain.CodeInvariant, "a promotion applied to the order is no longer available")
+		}
+		return nil
+	case domain.PromotionsReleased:
+		return q.ReleaseOrderPromotions(ctx, sqlcgen.ReleaseOrderPromotionsParams{
+			SystemUpdatedAt: change.At,
+			OrderID:         change.OrderID,
+		})
+	default:
+		return nil
+	}
+}
+
+func promotionToDomain(m sqlcgen.Promotion) *domain.Promotion {
+	var category *domain.ProductCategory
+	if m.Category != nil {
+		c := domain.ProductCategory(*m.Category)
+		category = &c
+	}
+	return &domain.Promotion{
+		ID:             m.ID,
+		OrganizationID: m.OrganizationID,
+		CreatedAt:      m.SystemCreatedAt,
+		UpdatedAt:      m.SystemUpdatedAt,
+		Name:           m.Name,
+		Kind:           domain.PromotionKind(m.Kind),
+		Category:       category,
+		PercentOff:     valueOrZero(m.PercentOff),
+		AmountOff:      m.AmountOff,
+		BuyQuantity:    valueOrZero(m.BuyQuantity),
+		GetQuantity:    valueOrZero(m.GetQuantity),
+		StartsAt:       m.StartsAt,
+		EndsAt:         m.EndsAt,
+		UsageLimit:     m.UsageLimit,
+		UsageCount:     m.UsageCount,
+	}
+}
+
+func promotionCategory(p *domain.Promotion) *string {
+	if p.Category == nil {
+		return nil
+	}
+	c := string(*p.Category)
+	return &c
+}
+
+func promotionCreateParams(p *domain.Promotion) sqlcgen.CreatePromotionParams {
+	return sqlcgen.CreatePromotionParams{
+		ID:              p.ID,
+		OrganizationID:  p.OrganizationID,
+		SystemCreatedAt: p.CreatedAt,
+		SystemUpdatedAt: p.UpdatedAt,
+		Name:            p.Name,
+		Kind:            string(p.Kind),
+		Category:        promotionCategory(p),
+		PercentOff:      nilIfZero(p.PercentOff),
+		AmountOff:       p.AmountOff,
+		BuyQuantity:     nilIfZero(p.BuyQuantity),
+		GetQuantity:     nilIfZero(p.GetQuantity),
+		StartsAt:        p.StartsAt,
+		EndsAt:          p.EndsAt,
+		UsageLimit:      p.UsageLimit,
+	}
+}
+
+func promotionUpdateParams(p *domain.Promotion) sqlcgen.UpdatePromotionParams {
+	return sqlcgen.UpdatePromotionParams{
+		ID:              p.ID,
+		SystemUpdatedAt: p.UpdatedAt,
+		Name:            p.Name,
+		Kind:            string(p.Kind),
+		Category:        promotionCategory(p),
+		PercentOff:      nilIfZero(p.PercentOff),
+		AmountOff:       p.AmountOff,
+		BuyQuantity:     nilIfZero(p.BuyQuantity),
+		GetQuantity:     nilIfZero(p.GetQuantity),
+		StartsAt:        p.StartsAt,
+		EndsAt:          p.EndsAt,
+		UsageLimit:      p.UsageLimit,
+	}
+}
+
+func orderAdjustmentToDomain(m sqlcgen.OrderAdjustment) domain.OrderAdjustment {
+	return domain.OrderAdjustment{
+		ID:             m.ID,
+		OrganizationID: m.OrganizationID,
+		OrderID:        m.OrderID,
+		OrderItemID:    m.OrderItemID,
+		PromotionID:    m.PromotionID,
+		CreatedAt:      m.SystemCreatedAt,
+		Description:    m.Description,
+		Amount:         m.Amount,
+	}
+}
+
+func orderAdjustmentCreateParams(a *domain.OrderAdjustment) sqlcgen.CreateOrderAdjustmentParams {
+	return sqlcgen.CreateOrderAdjustmentParams{
+		ID:              a.ID,
+		OrganizationID:  a.OrganizationID,
+		OrderID:         a.OrderID,
+		OrderItemID:     a.OrderItemID,
+		PromotionID:     a.PromotionID,
+		SystemCreatedAt: a.CreatedAt,
+		Description:     a.Description,
+		Amount:          a.Amount,
+	}
+}
+
+// nilIfZero and valueOrZero map the optional integer columns of a promotion,
+// which are NULL for kinds that do not use them.
+func nilIfZero(n int32) *int32 {
+	if n == 0 {
+		return nil
+	}
+	return &n
+}
+
+func valueOrZero(n *int32) int32 {
+	if n == nil {
+		return 0
+	}
+	return *n
+}
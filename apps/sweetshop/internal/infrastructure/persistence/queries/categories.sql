@@ -0,0 +1,33 @@
+-- name: FindCategoryByID :one
+SELECT * FROM app_sweetshop.categories WHERE id = $1;
+
+-- name: FindCategoryBySlug :one
+SELECT * FROM app_sweetshop.categories WHERE slug = $1;
+
+-- name: ListCategories :many
+SELECT * FROM app_sweetshop.categories ORDER BY sort_order, name;
+
+-- name: CreateCategory :exec
+INSERT INTO app_sweetshop.categories (id, organization_id, system_created_at, system_updated_at, slug, name, sort_order)
+VALUES ($1, $2, $3, $4, $5, $6, $7);
+
+-- name: UpdateCategory :execrows
+-- A new slug carries over to the products, promotions and tax rates of the
+-- category.
+UPDATE app_sweetshop.categories
+SET system_updated_at = $2, slug = $3, name = $4, sort_order = $5
+WHERE id = $1;
+
+-- name: DeleteCategory :execrows
+-- Deletes nothing while products, soft-deleted or not, or promotions still
+-- use the category.
+DELETE FROM app_sweetshop.categories c
+WHERE c.id = $1
+  AND NOT EXISTS (
+      SELECT 1 FROM app_sweetshop.products p
+      WHERE p.organization_id = c.organization_id AND p.category = c.slug
+  )
+  AND NOT EXISTS (
+      SELECT 1 FROM app_sweetshop.promotions r
+      WHERE r.organization_id = c.organization_id AND r.category = c.slug
+  );
@@ -0,0 +1,27 @@
+-- name: FindInventoryByProductID :one
+SELECT * FROM app_sweetshop.inventory WHERE product_id = $1;
+
+-- name: LockInventory :one
+-- Holds the row until the transaction ends, so reservations and adjustments
+-- of the same product are applied one at a time.
+SELECT * FROM app_sweetshop.inventory WHERE product_id = $1 FOR UPDATE;
+
+-- name: EnsureInventory :exec
+INSERT INTO app_sweetshop.inventory (product_id, organization_id, system_created_at, system_updated_at)
+VALUES (sqlc.arg(product_id), sqlc.arg(organization_id), sqlc.arg(system_created_at), sqlc.arg(system_created_at))
+ON CONFLICT (product_id) DO NOTHING;
+
+-- name: UpdateInventory :exec
+UPDATE app_sweetshop.inventory
+SET system_updated_at = $2, on_hand = $3, reserved = $4
+WHERE product_id = $1;
+
+-- name: CreateStockAdjustment :exec
+INSERT INTO app_sweetshop.stock_adjustments (id, organization_id, product_id, system_created_at, quantity_delta, reason, note, on_hand_after)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8);
+
+-- name: ListStockAdjustmentsByProductID :many
+SELECT * FROM app_sweetshop.stock_adjustments
+WHERE product_id = $1
+ORDER BY id DESC
+LIMIT $2;
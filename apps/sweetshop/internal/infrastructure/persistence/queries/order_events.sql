@@ -0,0 +1,5 @@
+-- name: ListOrderEventsAfter :many
+SELECT * FROM app_sweetshop.order_events
+WHERE id > $1
+ORDER BY id
+LIMIT $2;
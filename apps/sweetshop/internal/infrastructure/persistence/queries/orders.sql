@@ -1,19 +1,150 @@
 -- name: FindOrderByID :one
 SELECT * FROM app_sweetshop.orders WHERE id = $1;
 
+-- name: LockOrderStatus :one
+-- Holds the order until the transaction ends, so changes to its items and
+-- its transitions are applied one at a time.
+SELECT status FROM app_sweetshop.orders WHERE id = $1 FOR UPDATE;
+
+-- name: ListOrders :many
+-- A page of orders, newest first, each with its items aggregated into a JSON
+-- array so the page is read in one query. A filter left null matches every
+-- order; the total filters only match orders whose total was fixed when they
+-- were submitted.
+SELECT sqlc.embed(o), i.items
+FROM app_sweetshop.orders o
+CROSS JOIN LATERAL (
+    SELECT COALESCE(jsonb_agg(jsonb_build_object(
+        'id', oi.id,
+        'product_id', oi.product_id,
+        'product_name', p.name,
+        'product_category', p.category,
+        'system_created_at', oi.system_created_at,
+        'quantity', oi.quantity,
+        'reserved_quantity', oi.reserved_quantity,
+        'unit_price', oi.unit_price,
+        'options', oi.options
+    ) ORDER BY oi.system_created_at), '[]')::JSONB AS items
+    FROM app_sweetshop.order_items oi
+    JOIN app_sweetshop.products p ON p.id = oi.product_id
+    WHERE oi.order_id = o.id
+) i
+WHERE (sqlc.narg(after)::UUID IS NULL OR o.id < sqlc.narg(after))
+    AND (sqlc.narg(status)::TEXT IS NULL OR o.status = sqlc.narg(status))
+    AND (sqlc.narg(created_from)::TIMESTAMPTZ IS NULL OR o.system_created_at >= sqlc.narg(created_from))
+    AND (sqlc.narg(created_to)::TIMESTAMPTZ IS NULL OR o.system_created_at < sqlc.narg(created_to))
+    AND (sqlc.narg(total_currency)::TEXT IS NULL OR (o.total).currency = sqlc.narg(total_currency))
+    AND (sqlc.narg(min_total)::BIGINT IS NULL OR (o.total).amount >= sqlc.narg(min_total))
+    AND (sqlc.narg(max_total)::BIGINT IS NULL OR (o.total).amount <= sqlc.narg(max_total))
+    AND (sqlc.narg(product_id)::UUID IS NULL OR EXISTS (
+        SELECT 1 FROM app_sweetshop.order_items c
+        WHERE c.order_id = o.id AND c.product_id = sqlc.narg(product_id)
+    ))
+ORDER BY o.id DESC
+LIMIT sqlc.arg(page_size);
+
 -- name: CreateOrder :exec
-INSERT INTO app_sweetshop.orders (id, organization_id, system_created_at, system_updated_at, status)
-VALUES ($1, $2, $3, $4, $5);
+INSERT INTO app_sweetshop.orders (id, organization_id, system_created_at, system_updated_at, status, currency)
+VALUES ($1, $2, $3, $4, $5, $6);
 
--- name: CloseOrder :one
+-- name: TransitionOrder :execrows
+-- Matching on from_status makes concurrent transitions of the same order
+-- race safely: only the first one updates a row. The price is only passed
+-- when the order is submitted and kept afterwards.
 UPDATE app_sweetshop.orders
-SET system_updated_at = $2, status = 'closed'
-WHERE id = $1
-RETURNING *;
+SET system_updated_at = sqlc.arg(system_updated_at),
+    status = sqlc.arg(to_status),
+    submitted_at = sqlc.arg(submitted_at),
+    paid_at = sqlc.arg(paid_at),
+    fulfilled_at = sqlc.arg(fulfilled_at),
+    cancelled_at = sqlc.arg(cancelled_at),
+    subtotal = COALESCE(sqlc.narg(subtotal), subtotal),
+    discount = COALESCE(sqlc.narg(discount), discount),
+    tax = COALESCE(sqlc.narg(tax), tax),
+    total = COALESCE(sqlc.narg(total), total),
+    tax_pricing = COALESCE(sqlc.narg(tax_pricing), tax_pricing),
+    tax_rounding = COALESCE(sqlc.narg(tax_rounding), tax_rounding)
+WHERE id = sqlc.arg(id) AND status = sqlc.arg(from_status);
+
+-- name: CreateOrderStatusChange :exec
+INSERT INTO app_sweetshop.order_status_history (id, organization_id, order_id, system_created_at, from_status, to_status)
+VALUES ($1, $2, $3, $4, $5, $6);
+
+-- name: ListOrderStatusHistory :many
+SELECT * FROM app_sweetshop.order_status_history WHERE order_id = $1 ORDER BY id;
+
+-- name: CreateOrderItem :execrows
+-- Inserts nothing when the order already has a line of the product with the
+-- same options at the same price, which the item should have been merged
+-- into.
+INSERT INTO app_sweetshop.order_items (id, organization_id, order_id, product_id, system_created_at, quantity, unit_price, reserved_quantity, options)
+SELECT $1, $2, $3, $4, $5, $6, $7, $8, $9
+WHERE NOT EXISTS (
+    SELECT 1 FROM app_sweetshop.order_items
+    WHERE order_id = $3 AND product_id = $4 AND unit_price = $7 AND options = $9
+);
+
+-- name: UpdateOrderItemQuantity :execrows
+-- Matching on the previous quantities makes the update fail if the item
+-- changed since it was read.
+UPDATE app_sweetshop.order_items
+SET quantity = sqlc.arg(quantity),
+    reserved_quantity = sqlc.arg(reserved_quantity)
+WHERE id = sqlc.arg(id)
+    AND quantity = sqlc.arg(from_quantity)
+    AND reserved_quantity = sqlc.arg(from_reserved_quantity);
 
--- name: CreateOrderItem :exec
-INSERT INTO app_sweetshop.order_items (id, organization_id, order_id, product_id, system_created_at, quantity, price_cents)
-VALUES ($1, $2, $3, $4, $5, $6, $7);
+-- name: DeleteOrderItem :one
+DELETE FROM app_sweetshop.order_items WHERE id = $1 RETURNING reserved_quantity;
 
 -- name: ListOrderItemsByOrderID :many
-SELECT * FROM app_sweetshop.order_items WHERE order_id = $1 ORDER BY system_created_at;
+-- Joins products without a deleted_at filter so items keep resolving their
+-- product after it has been soft-deleted.
+SELECT oi.id, oi.organization_id, oi.order_id, oi.product_id, oi.system_created_at, oi.quantity, oi.unit_price, oi.reserved_quantity, oi.options, p.name AS product_name, p.category AS product_category
+FROM app_sweetshop.order_items oi
+JOIN app_sweetshop.products p ON p.id = oi.product_id
+WHERE oi.order_id = $1
+ORDER BY oi.system_created_at;
+
+-- name: CreateOrderAdjustment :exec
+INSERT INTO app_sweetshop.order_adjustments (id, organization_id, order_id, order_item_id, promotion_id, system_created_at, description, amount)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8);
+
+-- name: ListOrderAdjustmentsByOrderID :many
+SELECT * FROM app_sweetshop.order_adjustments WHERE order_id = $1 ORDER BY id;
+
+-- name: CreateOrderTaxLine :exec
+INSERT INTO app_sweetshop.order_tax_lines (order_id, organization_id, rate, net, tax)
+VALUES ($1, $2, $3, $4, $5);
+
+-- name: ListOrderTaxLinesByOrderID :many
+SELECT * FROM app_sweetshop.order_tax_lines WHERE order_id = $1 ORDER BY rate;
+
+-- name: ReleaseOrderReservations :exec
+-- Sums per product so an order with several lines of one product updates its
+-- inventory row once.
+UPDATE app_sweetshop.inventory i
+SET system_updated_at = sqlc.arg(system_updated_at),
+    reserved = i.reserved - r.quantity
+FROM (
+    SELECT product_id, SUM(reserved_quantity)::INTEGER AS quantity
+    FROM app_sweetshop.order_items
+    WHERE order_id = sqlc.arg(order_id) AND reserved_quantity > 0
+    GROUP BY product_id
+) r
+WHERE i.product_id = r.product_id;
+
+-- name: CommitOrderReservations :exec
+-- Sums per product so an order with several lines of one product updates its
+-- inventory row once.
+UPDATE app_sweetshop.inventory i
+SET system_updated_at = sqlc.arg(system_updated_at),
+    on_hand = i.on_hand - r.quantity,
+    reserved = i.reserved - r.quantity
+FROM (
+    SELECT product_id, SUM(reserved_quantity)::INTEGER AS quantity
+    FROM app_sweetshop.order_items
+    WHERE order_id = sqlc.arg(order_id) AND reserved_quantity > 0
+    GROUP BY product_id
+) r
+WHERE i.product_id = r.product_id;
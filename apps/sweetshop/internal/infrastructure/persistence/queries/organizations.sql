@@ -7,3 +7,11 @@ SELECT * FROM app_sweetshop.organizations WHERE slug = $1;
 -- name: CreateOrganization :exec
 INSERT INTO app_sweetshop.organizations (id, system_created_at, system_updated_at, name, slug)
 VALUES ($1, $2, $3, $4, $5);
+
+-- name: ListOrganizations :many
+SELECT * FROM app_sweetshop.organizations ORDER BY id;
+
+-- name: UpdateOrganizationSettings :execrows
+UPDATE app_sweetshop.organizations
+SET system_updated_at = $2, currency = $3
+WHERE id = $1;
@@ -0,0 +1,25 @@
+-- name: CreatePayment :exec
+INSERT INTO app_sweetshop.payments (id, organization_id, order_id, system_created_at, provider, operation, status, amount, provider_reference, error)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10);
+
+-- name: ListPaymentsByOrderID :many
+SELECT * FROM app_sweetshop.payments WHERE order_id = $1 ORDER BY id;
+
+-- name: ClaimOrderPayment :execrows
+-- Takes the claim unless another token holds it past now. Claiming again
+-- with the same token extends it, so a step can check it still holds it.
+INSERT INTO app_sweetshop.order_payment_claims (order_id, organization_id, token, claimed_until)
+VALUES (sqlc.arg(order_id), sqlc.arg(organization_id), sqlc.arg(token), sqlc.arg(claimed_until))
+ON CONFLICT (order_id) DO UPDATE
+SET token = EXCLUDED.token, claimed_until = EXCLUDED.claimed_until
+WHERE order_payment_claims.token = EXCLUDED.token
+    OR order_payment_claims.claimed_until <= sqlc.arg(now)::TIMESTAMPTZ;
+
+-- name: ReleaseOrderPayment :exec
+DELETE FROM app_sweetshop.order_payment_claims WHERE order_id = $1 AND token = $2;
+
+-- name: IsOrderPaymentClaimed :one
+SELECT EXISTS (
+    SELECT 1 FROM app_sweetshop.order_payment_claims
+    WHERE order_id = sqlc.arg(order_id) AND claimed_until > sqlc.arg(now)::TIMESTAMPTZ
+);
@@ -0,0 +1,24 @@
+-- name: ListProductOptionGroups :many
+SELECT * FROM app_sweetshop.product_option_groups
+WHERE product_id = $1
+ORDER BY sort_order, id;
+
+-- name: ListProductOptions :many
+-- The options of all of the product's groups.
+SELECT o.*
+FROM app_sweetshop.product_options o
+JOIN app_sweetshop.product_option_groups g ON g.id = o.group_id
+WHERE g.product_id = $1
+ORDER BY o.sort_order, o.id;
+
+-- name: DeleteProductOptionGroups :exec
+-- Deletes the product's options with their groups.
+DELETE FROM app_sweetshop.product_option_groups WHERE product_id = $1;
+
+-- name: CreateProductOptionGroup :exec
+INSERT INTO app_sweetshop.product_option_groups (id, organization_id, product_id, name, kind, min_selections, max_selections, sort_order)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8);
+
+-- name: CreateProductOption :exec
+INSERT INTO app_sweetshop.product_options (id, organization_id, group_id, name, price_delta, sort_order)
+VALUES ($1, $2, $3, $4, $5, $6);
@@ -0,0 +1,22 @@
+-- name: SearchProducts :many
+-- Live products whose search_vector matches prefix_query, or whose name has a
+-- word within the pg_trgm word similarity threshold of term. Full-text matches
+-- rank above trigram-only ones, which rank by similarity. The highlights are
+-- the HTML escaped name and description, e, with the matched words wrapped in
+-- <mark> tags; the text search parser reads the escapes as entities, not words.
+SELECT sqlc.embed(p),
+    (CASE WHEN p.search_vector @@ q.query THEN ts_rank(p.search_vector, q.query) ELSE 0 END)::REAL AS rank,
+    word_similarity(sqlc.arg(term)::TEXT, p.name)::REAL AS similarity,
+    ts_headline('simple', e.name, q.query,
+        'HighlightAll=true, StartSel=<mark>, StopSel=</mark>')::TEXT AS name_highlight,
+    ts_headline('simple', e.description, q.query,
+        'MaxFragments=2, MaxWords=20, MinWords=5, StartSel=<mark>, StopSel=</mark>')::TEXT AS description_highlight
+FROM app_sweetshop.products p
+CROSS JOIN (SELECT to_tsquery('simple', sqlc.arg(prefix_query)::TEXT) AS query) q
+CROSS JOIN LATERAL (SELECT
+    replace(replace(replace(replace(p.name, '&', '&amp;'), '<', '&lt;'), '>', '&gt;'), '"', '&quot;') AS name,
+    replace(replace(replace(replace(p.description, '&', '&amp;'), '<', '&lt;'), '>', '&gt;'), '"', '&quot;') AS description) e
+WHERE p.deleted_at IS NULL
+    AND (p.search_vector @@ q.query OR sqlc.arg(term)::TEXT <% p.name)
+ORDER BY rank DESC, similarity DESC, p.sort_order, p.name
+LIMIT sqlc.arg(page_size);
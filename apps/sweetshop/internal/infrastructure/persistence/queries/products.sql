@@ -1,17 +1,31 @@
 -- name: FindProductByID :one
-SELECT * FROM app_sweetshop.products WHERE id = $1;
+SELECT * FROM app_sweetshop.products WHERE id = $1 AND deleted_at IS NULL;
 
 -- name: ListProducts :many
-SELECT * FROM app_sweetshop.products ORDER BY name;
+SELECT * FROM app_sweetshop.products WHERE deleted_at IS NULL ORDER BY sort_order, name;
 
 -- name: CreateProduct :exec
-INSERT INTO app_sweetshop.products (id, organization_id, system_created_at, system_updated_at, name, category, price_cents)
-VALUES ($1, $2, $3, $4, $5, $6, $7);
+INSERT INTO app_sweetshop.products (id, organization_id, system_created_at, system_updated_at, name, category, price, sku, description, active, sort_order)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11);
 
 -- name: UpdateProduct :execrows
 UPDATE app_sweetshop.products
-SET system_updated_at = $2, name = $3, category = $4, price_cents = $5
-WHERE id = $1;
+SET system_updated_at = $2, name = $3, category = $4, price = $5, sku = $6, description = $7,
+    active = $8, sort_order = $9
+WHERE id = $1 AND deleted_at IS NULL;
 
--- name: DeleteProduct :execrows
-DELETE FROM app_sweetshop.products WHERE id = $1;
+-- name: SoftDeleteProduct :execrows
+UPDATE app_sweetshop.products
+SET system_updated_at = $2, deleted_at = $2
+WHERE id = $1 AND deleted_at IS NULL;
+
+-- name: RestoreProduct :one
+UPDATE app_sweetshop.products
+SET system_updated_at = $2, deleted_at = NULL
+WHERE id = $1 AND deleted_at IS NOT NULL
+RETURNING *;
+
+-- name: PurgeDeletedProducts :execrows
+DELETE FROM app_sweetshop.products p
+WHERE p.deleted_at < $1
+  AND NOT EXISTS (SELECT 1 FROM app_sweetshop.order_items oi WHERE oi.product_id = p.id);
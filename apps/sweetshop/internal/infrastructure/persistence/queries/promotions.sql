@@ -0,0 +1,41 @@
+-- name: FindPromotionByID :one
+SELECT * FROM app_sweetshop.promotions WHERE id = $1;
+
+-- name: ListPromotions :many
+SELECT * FROM app_sweetshop.promotions ORDER BY id;
+
+-- name: ListActivePromotions :many
+SELECT * FROM app_sweetshop.promotions
+WHERE (starts_at IS NULL OR starts_at <= sqlc.arg(at)::TIMESTAMPTZ)
+  AND (ends_at IS NULL OR ends_at > sqlc.arg(at)::TIMESTAMPTZ)
+  AND (usage_limit IS NULL OR usage_count < usage_limit)
+ORDER BY id;
+
+-- name: CreatePromotion :exec
+INSERT INTO app_sweetshop.promotions (id, organization_id, system_created_at, system_updated_at, name, kind, category, percent_off, amount_off, buy_quantity, get_quantity, starts_at, ends_at, usage_limit)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14);
+
+-- name: UpdatePromotion :execrows
+UPDATE app_sweetshop.promotions
+SET system_updated_at = $2, name = $3, kind = $4, category = $5, percent_off = $6, amount_off = $7,
+    buy_quantity = $8, get_quantity = $9, starts_at = $10, ends_at = $11, usage_limit = $12
+WHERE id = $1;
+
+-- name: DeletePromotion :execrows
+DELETE FROM app_sweetshop.promotions WHERE id = $1;
+
+-- name: RedeemOrderPromotions :execrows
+-- Counts one use of each promotion the order's adjustments come from. A
+-- promotion that has no uses left is not updated, so fewer rows than
+-- promotions means the order cannot be submitted as priced.
+UPDATE app_sweetshop.promotions
+SET system_updated_at = sqlc.arg(system_updated_at),
+    usage_count = usage_count + 1
+WHERE id IN (SELECT promotion_id FROM app_sweetshop.order_adjustments WHERE order_id = sqlc.arg(order_id))
+  AND (usage_limit IS NULL OR usage_count < usage_limit);
+
+-- name: ReleaseOrderPromotions :exec
+UPDATE app_sweetshop.promotions
+SET system_updated_at = sqlc.arg(system_updated_at),
+    usage_count = usage_count - 1
+WHERE id IN (SELECT promotion_id FROM app_sweetshop.order_adjustments WHERE order_id = sqlc.arg(order_id));
@@ -0,0 +1,86 @@
+-- name: FindSalesReportRefresh :one
+SELECT refreshed_at FROM app_sweetshop.sales_report_refreshes WHERE organization_id = $1;
+
+-- name: UpsertSalesReportRefresh :exec
+INSERT INTO app_sweetshop.sales_report_refreshes (organization_id, refreshed_at)
+VALUES ($1, $2)
+ON CONFLICT (organization_id) DO UPDATE
+SET refreshed_at = EXCLUDED.refreshed_at;
+
+-- name: DeleteDailySalesFrom :exec
+DELETE FROM app_sweetshop.daily_sales
+WHERE organization_id = sqlc.arg(organization_id) AND day >= sqlc.arg(from_day)::DATE;
+
+-- name: DeleteDailyProductSalesFrom :exec
+DELETE FROM app_sweetshop.daily_product_sales
+WHERE organization_id = sqlc.arg(organization_id) AND day >= sqlc.arg(from_day)::DATE;
+
+-- name: CreateDailySalesFrom :exec
+-- Orders are counted on the UTC day they were fulfilled at the price fixed
+-- when they were submitted, and refunds on the UTC day they were made.
+INSERT INTO app_sweetshop.daily_sales (organization_id, day, currency, system_updated_at, orders, units, subtotal, discount, tax, revenue, refunded)
+SELECT sqlc.arg(organization_id)::UUID, s.day, s.currency, sqlc.arg(now)::TIMESTAMPTZ,
+    SUM(s.orders)::INTEGER, SUM(s.units)::BIGINT, SUM(s.subtotal)::BIGINT, SUM(s.discount)::BIGINT,
+    SUM(s.tax)::BIGINT, SUM(s.revenue)::BIGINT, SUM(s.refunded)::BIGINT
+FROM (
+    SELECT (o.fulfilled_at AT TIME ZONE 'UTC')::DATE AS day, o.currency, 1 AS orders, u.units,
+        (o.subtotal).amount AS subtotal, (o.discount).amount AS discount, (o.tax).amount AS tax,
+        (o.total).amount AS revenue, 0::BIGINT AS refunded
+    FROM app_sweetshop.orders o
+    CROSS JOIN LATERAL (
+        SELECT COALESCE(SUM(i.quantity), 0)::BIGINT AS units
+        FROM app_sweetshop.order_items i
+        WHERE i.order_id = o.id
+    ) u
+    WHERE o.organization_id = sqlc.arg(organization_id) AND o.fulfilled_at IS NOT NULL
+        AND (o.fulfilled_at AT TIME ZONE 'UTC')::DATE >= sqlc.arg(from_day)::DATE
+    UNION ALL
+    SELECT (p.system_created_at AT TIME ZONE 'UTC')::DATE, (p.amount).currency, 0, 0, 0, 0, 0, 0, (p.amount).amount
+    FROM app_sweetshop.payments p
+    JOIN app_sweetshop.orders o ON o.id = p.order_id
+    WHERE p.organization_id = sqlc.arg(organization_id) AND p.operation = 'refund' AND p.status = 'succeeded'
+        AND o.fulfilled_at IS NOT NULL
+        AND (p.system_created_at AT TIME ZONE 'UTC')::DATE >= sqlc.arg(from_day)::DATE
+) s
+GROUP BY s.day, s.currency;
+
+-- name: CreateDailyProductSalesFrom :exec
+-- A line's revenue is its price less the adjustments on it.
+INSERT INTO app_sweetshop.daily_product_sales (organization_id, day, product_id, currency, system_updated_at, units, revenue)
+SELECT sqlc.arg(organization_id)::UUID, (o.fulfilled_at AT TIME ZONE 'UTC')::DATE, i.product_id, o.currency,
+    sqlc.arg(now)::TIMESTAMPTZ, SUM(i.quantity)::BIGINT, SUM((i.unit_price).amount * i.quantity + a.amount)::BIGINT
+FROM app_sweetshop.orders o
+JOIN app_sweetshop.order_items i ON i.order_id = o.id
+CROSS JOIN LATERAL (
+    SELECT COALESCE(SUM((adj.amount).amount), 0)::BIGINT AS amount
+    FROM app_sweetshop.order_adjustments adj
+    WHERE adj.order_item_id = i.id
+) a
+WHERE o.organization_id = sqlc.arg(organization_id) AND o.fulfilled_at IS NOT NULL
+    AND (o.fulfilled_at AT TIME ZONE 'UTC')::DATE >= sqlc.arg(from_day)::DATE
+GROUP BY (o.fulfilled_at AT TIME ZONE 'UTC')::DATE, i.product_id, o.currency;
+
+-- name: ListSales :many
+-- Periods are named by their first day; weeks start on Monday.
+SELECT date_trunc(sqlc.arg(granularity)::TEXT, day::TIMESTAMP)::DATE AS period, currency,
+    SUM(orders)::BIGINT AS orders, SUM(units)::BIGINT AS units, SUM(subtotal)::BIGINT AS subtotal,
+    SUM(discount)::BIGINT AS discount, SUM(tax)::BIGINT AS tax, SUM(revenue)::BIGINT AS revenue,
+    SUM(refunded)::BIGINT AS refunded
+FROM app_sweetshop.daily_sales
+WHERE organization_id = sqlc.arg(organization_id)
+    AND day BETWEEN sqlc.arg(from_day)::DATE AND sqlc.arg(to_day)::DATE
+GROUP BY period, currency
+ORDER BY period, currency;
+
+-- name: ListTopProducts :many
+-- Soft-deleted products are included; they were sold all the same.
+SELECT s.product_id, p.name, SUM(s.units)::BIGINT AS units, SUM(s.revenue)::BIGINT AS revenue
+FROM app_sweetshop.daily_product_sales s
+JOIN app_sweetshop.products p ON p.id = s.product_id
+WHERE s.organization_id = sqlc.arg(organization_id) AND s.currency = sqlc.arg(currency)
+    AND s.day BETWEEN sqlc.arg(from_day)::DATE AND sqlc.arg(to_day)::DATE
+GROUP BY s.product_id, p.name
+ORDER BY CASE WHEN sqlc.arg(by_revenue)::BOOLEAN THEN SUM(s.revenue) ELSE SUM(s.units) END DESC,
+    CASE WHEN sqlc.arg(by_revenue)::BOOLEAN THEN SUM(s.units) ELSE SUM(s.revenue) END DESC,
+    s.product_id
+LIMIT sqlc.arg(product_limit);
@@ -0,0 +1,21 @@
+-- name: FindTaxProfile :one
+SELECT * FROM app_sweetshop.tax_profiles WHERE organization_id = $1;
+
+-- name: ListTaxRates :many
+SELECT * FROM app_sweetshop.tax_rates WHERE organization_id = $1 ORDER BY category;
+
+-- name: UpsertTaxProfile :exec
+INSERT INTO app_sweetshop.tax_profiles (organization_id, system_created_at, system_updated_at, pricing, rounding, default_rate)
+VALUES ($1, $2, $2, $3, $4, $5)
+ON CONFLICT (organization_id) DO UPDATE
+SET system_updated_at = EXCLUDED.system_updated_at,
+    pricing = EXCLUDED.pricing,
+    rounding = EXCLUDED.rounding,
+    default_rate = EXCLUDED.default_rate;
+
+-- name: DeleteTaxRates :exec
+DELETE FROM app_sweetshop.tax_rates WHERE organization_id = $1;
+
+-- name: CreateTaxRate :exec
+INSERT INTO app_sweetshop.tax_rates (organization_id, category, rate)
+VALUES ($1, $2, $3);
@@ -0,0 +1,68 @@
+-- name: FindWebhookEndpointByID :one
+SELECT * FROM app_sweetshop.webhook_endpoints WHERE id = $1;
+
+-- name: ListWebhookEndpoints :many
+SELECT * FROM app_sweetshop.webhook_endpoints ORDER BY id;
+
+-- name: CreateWebhookEndpoint :exec
+INSERT INTO app_sweetshop.webhook_endpoints (id, organization_id, system_created_at, system_updated_at, url, secret, event_types, enabled)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8);
+
+-- name: UpdateWebhookEndpoint :execrows
+UPDATE app_sweetshop.webhook_endpoints
+SET system_updated_at = $2, url = $3, event_types = $4, enabled = $5, consecutive_failures = $6, disabled_at = $7
+WHERE id = $1;
+
+-- name: DeleteWebhookEndpoint :execrows
+DELETE FROM app_sweetshop.webhook_endpoints WHERE id = $1;
+
+-- name: ResetWebhookEndpointFailures :exec
+UPDATE app_sweetshop.webhook_endpoints
+SET consecutive_failures = 0
+WHERE id = $1 AND consecutive_failures <> 0;
+
+-- name: RecordWebhookEndpointFailure :one
+-- The right-hand sides read the row as it was before the update, so the
+-- endpoint is disabled by the attempt that reaches disable_after.
+UPDATE app_sweetshop.webhook_endpoints
+SET consecutive_failures = consecutive_failures + 1,
+    enabled = enabled AND consecutive_failures + 1 < sqlc.arg(disable_after)::INTEGER,
+    disabled_at = CASE
+        WHEN enabled AND consecutive_failures + 1 >= sqlc.arg(disable_after)::INTEGER THEN sqlc.arg(failed_at)::TIMESTAMPTZ
+        ELSE disabled_at
+    END
+WHERE id = sqlc.arg(id)
+RETURNING enabled;
+
+-- name: ListWebhookDeliveriesByEndpoint :many
+SELECT * FROM app_sweetshop.webhook_deliveries
+WHERE endpoint_id = $1
+ORDER BY id DESC
+LIMIT $2;
+
+-- name: ClaimDueWebhookDeliveries :many
+-- SKIP LOCKED lets concurrent dispatchers share the queue; moving
+-- next_attempt_at to the lease keeps a claimed delivery out of later claims
+-- until its attempt is recorded.
+WITH due AS (
+    SELECT d.id
+    FROM app_sweetshop.webhook_deliveries d
+    JOIN app_sweetshop.webhook_endpoints e ON e.id = d.endpoint_id
+    WHERE d.status = 'pending' AND d.next_attempt_at <= sqlc.arg(now)::TIMESTAMPTZ AND e.enabled
+    ORDER BY d.next_attempt_at
+    LIMIT sqlc.arg(batch_size)
+    FOR UPDATE OF d SKIP LOCKED
+)
+UPDATE app_sweetshop.webhook_deliveries d
+SET next_attempt_at = sqlc.arg(lease_until)::TIMESTAMPTZ
+FROM due, app_sweetshop.webhook_endpoints e
+WHERE d.id = due.id AND e.id = d.endpoint_id
+RETURNING d.id, d.organization_id, d.endpoint_id, d.event_id, d.system_created_at, d.system_updated_at,
+    d.event_type, d.payload, d.status, d.attempts, d.next_attempt_at, d.last_attempt_at, d.response_status,
+    d.last_error, e.url, e.secret;
+
+-- name: UpdateWebhookDeliveryAttempt :exec
+UPDATE app_sweetshop.webhook_deliveries
+SET system_updated_at = $2, status = $3, attempts = $4, next_attempt_at = $5, last_attempt_at = $6,
+    response_status = $7, last_error = $8
+WHERE id = $1;
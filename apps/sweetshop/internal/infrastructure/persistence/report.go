@@ -0,0 +1,130 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/bbsbb/go-edge/core/datatype"
+	"github.com/bbsbb/go-edge/core/fx/rlsfx"
+	"github.com/bbsbb/go-edge/sweetshop/internal/domain"
+	"github.com/bbsbb/go-edge/sweetshop/internal/infrastructure/persistence/sqlcgen"
+)
+
+// salesHistoryStart is where the first refresh of an organization starts.
+var salesHistoryStart = time.Unix(0, 0).UTC()
+
+type SalesReportRepo struct {
+	db *rlsfx.DB
+}
+
+func NewSalesReportRepo(db *rlsfx.DB) *SalesReportRepo {
+	return &SalesReportRepo{db: db}
+}
+
+// Refresh replaces the aggregates in one transaction, so reports never see a
+// day half computed. Starting a day before the last refresh picks up sales
+// that were committed after it read them.
+func (r *SalesReportRepo) Refresh(ctx context.Context, organizationID uuid.UUID, now time.Time) error {
+	return rlsfx.Exec(r.db, ctx, func(ctx context.Context, tx pgx.Tx) error {
+		q := sqlcgen.New(tx)
+		from := salesHistoryStart
+		last, err := q.FindSalesReportRefresh(ctx, organizationID)
+		switch {
+		case err == nil:
+			from = domain.SalesDay(last).AddDate(0, 0, -1)
+		case !errors.Is(err, pgx.ErrNoRows):
+			return err
+		}
+
+		if err := q.DeleteDailySalesFrom(ctx, sqlcgen.DeleteDailySalesFromParams{OrganizationID: organizationID, FromDay: from}); err != nil {
+			return err
+		}
+		if err := q.DeleteDailyProductSalesFrom(ctx, sqlcgen.DeleteDailyProductSalesFromParams{OrganizationID: organizationID, FromDay: from}); err != nil {
+			return err
+		}
+		if err := q.CreateDailySalesFrom(ctx, sqlcgen.CreateDailySalesFromParams{OrganizationID: organizationID, Now: now, FromDay: from}); err != nil {
+			return err
+		}
+		if err := q.CreateDailyProductSalesFrom(ctx, sqlcgen.CreateDailyProductSalesFromParams{OrganizationID: organizationID, Now: now, FromDay: from}); err != nil {
+			return err
+		}
+		return q.UpsertSalesReportRefresh(ctx, sqlcgen.UpsertSalesReportRefreshParams{OrganizationID: organizationID, RefreshedAt: now})
+	})
+}
+
+func (r *SalesReportRepo) ListSales(ctx context.Context, organizationID uuid.UUID, query domain.SalesQuery) ([]domain.SalesPeriod, error) {
+	return rlsfx.Query(r.db, ctx, func(ctx context.Context, tx pgx.Tx) ([]domain.SalesPeriod, error) {
+		rows, err := sqlcgen.New(tx).ListSales(ctx, sqlcgen.ListSalesParams{
+			Granularity:    string(query.Granularity),
+			OrganizationID: organizationID,
+			FromDay:        query.From,
+			ToDay:          query.To,
+		})
+		if err != nil {
+			return nil, err
+		}
+		periods := make([]domain.SalesPeriod, len(rows))
+		for i, m := range rows {
+			if periods[i], err = salesPeriodToDomain(m); err != nil {
+				return nil, err
+			}
+		}
+		return periods, nil
+	})
+}
+
+func (r *SalesReportRepo) ListTopProducts(ctx context.Context, organizationID uuid.UUID, query domain.TopProductsQuery) ([]domain.ProductSales, error) {
+	return rlsfx.Query(r.db, ctx, func(ctx context.Context, tx pgx.Tx) ([]domain.ProductSales, error) {
+		rows, err := sqlcgen.New(tx).ListTopProducts(ctx, sqlcgen.ListTopProductsParams{
+			OrganizationID: organizationID,
+			Currency:       string(query.Currency),
+			FromDay:        query.From,
+			ToDay:          query.To,
+			ByRevenue:      query.By == domain.TopProductsByRevenue,
+			ProductLimit:   query.Limit,
+		})
+		if err != nil {
+			return nil, err
+		}
+		products := make([]domain.ProductSales, len(rows))
+		for i, m := range rows {
+			revenue, err := datatype.NewMoney(m.Revenue, query.Currency)
+			if err != nil {
+				return nil, err
+			}
+			products[i] = domain.ProductSales{
+				ProductID:   m.ProductID,
+				ProductName: m.Name,
+				Units:       m.Units,
+				Revenue:     revenue,
+			}
+		}
+		return products, nil
+	})
+}
+
+func salesPeriodToDomain(m sqlcgen.ListSalesRow) (domain.SalesPeriod, error) {
+	currency := datatype.Currency(m.Currency)
+	amounts := []int64{m.Subtotal, m.Discount, m.Tax, m.Revenue, m.Refunded}
+	money := make([]datatype.Money, len(amounts))
+	for i, amount := range amounts {
+		var err error
+		if money[i], err = datatype.NewMoney(amount, currency); err != nil {
+			return domain.SalesPeriod{}, err
+		}
+	}
+	return domain.SalesPeriod{
+		Start:    m.Period,
+		Orders:   m.Orders,
+		Units:    m.Units,
+		Subtotal: money[0],
+		Discount: money[1],
+		Tax:      money[2],
+		Revenue:  money[3],
+		Refunded: money[4],
+	}, nil
+}
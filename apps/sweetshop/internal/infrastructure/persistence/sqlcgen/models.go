@@ -7,25 +7,126 @@ package sqlcgen
 import (
 	"time"
 
+	"github.com/bbsbb/go-edge/core/datatype"
 	"github.com/google/uuid"
 )
 
+type Category struct {
+	ID              uuid.UUID
+	OrganizationID  uuid.UUID
+	SystemCreatedAt time.Time
+	SystemUpdatedAt time.Time
+	Slug            string
+	Name            string
+	SortOrder       int32
+}
+
+type DailyProductSale struct {
+	OrganizationID  uuid.UUID
+	Day             time.Time
+	ProductID       uuid.UUID
+	Currency        string
+	SystemUpdatedAt time.Time
+	Units           int64
+	Revenue         int64
+}
+
+type DailySale struct {
+	OrganizationID  uuid.UUID
+	Day             time.Time
+	Currency        string
+	SystemUpdatedAt time.Time
+	Orders          int32
+	Units           int64
+	Subtotal        int64
+	Discount        int64
+	Tax             int64
+	Revenue         int64
+	Refunded        int64
+}
+
+type Inventory struct {
+	ProductID       uuid.UUID
+	OrganizationID  uuid.UUID
+	SystemCreatedAt time.Time
+	SystemUpdatedAt time.Time
+	OnHand          int32
+	Reserved        int32
+}
+
 type Order struct {
 	ID              uuid.UUID
 	OrganizationID  uuid.UUID
 	SystemCreatedAt time.Time
 	SystemUpdatedAt time.Time
 	Status          string
+	SubmittedAt     *time.Time
+	PaidAt          *time.Time
+	FulfilledAt     *time.Time
+	CancelledAt     *time.Time
+	Currency        string
+	Subtotal        *datatype.Money
+	Discount        *datatype.Money
+	Tax             *datatype.Money
+	Total           *datatype.Money
+	TaxPricing      *string
+	TaxRounding     *string
+}
+
+type OrderAdjustment struct {
+	ID              uuid.UUID
+	OrganizationID  uuid.UUID
+	OrderID         uuid.UUID
+	OrderItemID     uuid.UUID
+	PromotionID     *uuid.UUID
+	SystemCreatedAt time.Time
+	Description     string
+	Amount          datatype.Money
+}
+
+type OrderEvent struct {
+	ID              uuid.UUID
+	OrganizationID  uuid.UUID
+	OrderID         uuid.UUID
+	SystemCreatedAt time.Time
+	Type            string
+	Status          string
 }
 
 type OrderItem struct {
+	ID               uuid.UUID
+	OrganizationID   uuid.UUID
+	OrderID          uuid.UUID
+	ProductID        uuid.UUID
+	SystemCreatedAt  time.Time
+	Quantity         int32
+	ReservedQuantity int32
+	UnitPrice        datatype.Money
+	Options          []byte
+}
+
+type OrderPaymentClaim struct {
+	OrderID        uuid.UUID
+	OrganizationID uuid.UUID
+	Token          uuid.UUID
+	ClaimedUntil   time.Time
+}
+
+type OrderStatusHistory struct {
 	ID              uuid.UUID
 	OrganizationID  uuid.UUID
 	OrderID         uuid.UUID
-	ProductID       uuid.UUID
 	SystemCreatedAt time.Time
-	Quantity        int32
-	PriceCents      int32
+	FromStatus      *string
+	ToStatus        string
+}
+
+type OrderTaxLine struct {
+	OrderID        uuid.UUID
+	OrganizationID uuid.UUID
+	Rate           int32
+	Net            datatype.Money
+	Tax            datatype.Money
 }
 
 type Organization struct {
@@ -34,6 +135,20 @@ type Organization struct {
 	SystemUpdatedAt time.Time
 	Name            string
 	Slug            string
+	Currency        string
+}
+
+type Payment struct {
+	ID                uuid.UUID
+	OrganizationID    uuid.UUID
+	OrderID           uuid.UUID
+	SystemCreatedAt   time.Time
+	Provider          string
+	Operation         string
+	Status            string
+	ProviderReference *string
+	Error             *string
+	Amount            datatype.Money
 }
 
 type Product struct {
@@ -43,5 +158,110 @@ type Product struct {
 	SystemUpdatedAt time.Time
 	Name            string
 	Category        string
-	PriceCents      int32
+	DeletedAt       *time.Time
+	Price           datatype.Money
+	Sku             *string
+	Description     string
+	Active          bool
+	SortOrder       int32
+	SearchVector    interface{}
+}
+
+type ProductOption struct {
+	ID             uuid.UUID
+	OrganizationID uuid.UUID
+	GroupID        uuid.UUID
+	Name           string
+	PriceDelta     datatype.Money
+	SortOrder      int32
+}
+
+type ProductOptionGroup struct {
+	ID             uuid.UUID
+	OrganizationID uuid.UUID
+	ProductID      uuid.UUID
+	Name           string
+	Kind           string
+	MinSelections  int32
+	MaxSelections  int32
+	SortOrder      int32
+}
+
+type Promotion struct {
+	ID              uuid.UUID
+	OrganizationID  uuid.UUID
+	SystemCreatedAt time.Time
+	SystemUpdatedAt time.Time
+	Name            string
+	Kind            string
+	Category        *string
+	PercentOff      *int32
+	AmountOff       *datatype.Money
+	BuyQuantity     *int32
+	GetQuantity     *int32
+	StartsAt        *time.Time
+	EndsAt          *time.Time
+	UsageLimit      *int32
+	UsageCount      int32
+}
+
+type SalesReportRefresh struct {
+	OrganizationID uuid.UUID
+	RefreshedAt    time.Time
+}
+
+type StockAdjustment struct {
+	ID              uuid.UUID
+	OrganizationID  uuid.UUID
+	ProductID       uuid.UUID
+	SystemCreatedAt time.Time
+	QuantityDelta   int32
+	Reason          string
+	Note            *string
+	OnHandAfter     int32
+}
+
+type TaxProfile struct {
+	OrganizationID  uuid.UUID
+	SystemCreatedAt time.Time
+	SystemUpdatedAt time.Time
+	Pricing         string
+	Rounding        string
+	DefaultRate     int32
+}
+
+type TaxRate struct {
+	OrganizationID uuid.UUID
+	Category       string
+	Rate           int32
+}
+
+type WebhookDelivery struct {
+	ID              uuid.UUID
+	OrganizationID  uuid.UUID
+	EndpointID      uuid.UUID
+	EventID         uuid.UUID
+	SystemCreatedAt time.Time
+	SystemUpdatedAt time.Time
+	EventType       string
+	Payload         []byte
+	Status          string
+	Attempts        int32
+	NextAttemptAt   time.Time
+	LastAttemptAt   *time.Time
+	ResponseStatus  *int32
+	LastError       *string
+}
+
+type WebhookEndpoint struct {
+	ID                  uuid.UUID
+	OrganizationID      uuid.UUID
+	SystemCreatedAt     time.Time
+	SystemUpdatedAt     time.Time
+	Url                 string
+	Secret              string
+	EventTypes          []string
+	Enabled             bool
+	ConsecutiveFailures int32
+	DisabledAt          *time.Time
 }
@@ -9,37 +9,39 @@ import (
 	"context"
 	"time"
 
+	"github.com/bbsbb/go-edge/core/datatype"
 	"github.com/google/uuid"
 )
 
-const closeOrder = `-- name: CloseOrder :one
-UPDATE app_sweetshop.orders
-SET system_updated_at = $2, status = 'closed'
-WHERE id = $1
-RETURNING id, organization_id, system_created_at, system_updated_at, status
+const commitOrderReservations = `-- name: CommitOrderReservations :exec
+UPDATE app_sweetshop.inventory i
+SET system_updated_at = $1,
+    on_hand = i.on_hand - r.quantity,
+    reserved = i.reserved - r.quantity
+FROM (
+    SELECT product_id, SUM(reserved_quantity)::INTEGER AS quantity
+    FROM app_sweetshop.order_items
+    WHERE order_id = $2 AND reserved_quantity > 0
+    GROUP BY product_id
+) r
+WHERE i.product_id = r.product_id
 `
 
-type CloseOrderParams struct {
-	ID              uuid.UUID
+type CommitOrderReservationsParams struct {
 	SystemUpdatedAt time.Time
+	OrderID         uuid.UUID
 }
 
-func (q *Queries) CloseOrder(ctx context.Context, arg CloseOrderParams) (Order, error) {
-	row := q.db.QueryRow(ctx, closeOrder, arg.ID, arg.SystemUpdatedAt)
-	var i Order
-	err := row.Scan(
-		&i.ID,
-		&i.OrganizationID,
-		&i.SystemCreatedAt,
-		&i.SystemUpdatedAt,
-		&i.Status,
-	)
-	return i, err
+// Sums per product so an order with several lines of one product updates its
+// inventory row once.
+func (q *Queries) CommitOrderReservations(ctx context.Context, arg CommitOrderReservationsParams) error {
+	_, err := q.db.Exec(ctx, commitOrderReservations, arg.SystemUpdatedAt, arg.OrderID)
+	return err
 }
 
 const createOrder = `-- name: CreateOrder :exec
-INSERT INTO app_sweetshop.orders (id, organization_id, system_created_at, system_updated_at, status)
-VALUES ($1, $2, $3, $4, $5)
+INSERT INTO app_sweetshop.orders (id, organization_id, system_created_at, system_updated_at, status, currency)
+VALUES ($1, $2, $3, $4, $5, $6)
 `
 
 type CreateOrderParams struct {
@@ -48,6 +50,7 @@ type CreateOrderParams struct {
 	SystemCreatedAt time.Time
 	SystemUpdatedAt time.Time
 	Status          string
+	Currency        string
 }
 
 func (q *Queries) CreateOrder(ctx context.Context, arg CreateOrderParams) error {
@@ -57,40 +60,146 @@ func (q *Queries) CreateOrder(ctx context.Context, arg CreateOrderParams) error
 		arg.SystemCreatedAt,
 		arg.SystemUpdatedAt,
 		arg.Status,
+		arg.Currency,
 	)
 	return err
 }
 
-const createOrderItem = `-- name: CreateOrderItem :exec
-INSERT INTO app_sweetshop.order_items (id, organization_id, order_id, product_id, system_created_at, quantity, price_cents)
-VALUES ($1, $2, $3, $4, $5, $6, $7)
+const createOrderAdjustment = `-- name: CreateOrderAdjustment :exec
+INSERT INTO app_sweetshop.order_adjustments (id, organization_id, order_id, order_item_id, promotion_id, system_created_at, description, amount)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 `
 
-type CreateOrderItemParams struct {
+type CreateOrderAdjustmentParams struct {
 	ID              uuid.UUID
 	OrganizationID  uuid.UUID
 	OrderID         uuid.UUID
-	ProductID       uuid.UUID
+	OrderItemID     uuid.UUID
+	PromotionID     *uuid.UUID
 	SystemCreatedAt time.Time
-	Quantity        int32
-	PriceCents      int32
+	Description     string
+	Amount          datatype.Money
 }
 
-func (q *Queries) CreateOrderItem(ctx context.Context, arg CreateOrderItemParams) error {
-	_, err := q.db.Exec(ctx, createOrderItem,
+func (q *Queries) CreateOrderAdjustment(ctx context.Context, arg CreateOrderAdjustmentParams) error {
+	_, err := q.db.Exec(ctx, createOrderAdjustment,
+		arg.ID,
+		arg.OrganizationID,
+		arg.OrderID,
+		arg.OrderItemID,
+		arg.PromotionID,
+		arg.SystemCreatedAt,
+		arg.Description,
+		arg.Amount,
+	)
+	return err
+}
+
+const createOrderItem = `-- name: CreateOrderItem :execrows
+INSERT INTO app_sweetshop.order_items (id, organization_id, order_id, product_id, system_created_at, quantity, unit_price, reserved_quantity, options)
+SELECT $1, $2, $3, $4, $5, $6, $7, $8, $9
+WHERE NOT EXISTS (
+    SELECT 1 FROM app_sweetshop.order_items
+    WHERE order_id = $3 AND product_id = $4 AND unit_price = $7 AND options = $9
+)
+`
+
+type CreateOrderItemParams struct {
+	ID               uuid.UUID
+	OrganizationID   uuid.UUID
+	OrderID          uuid.UUID
+	ProductID        uuid.UUID
+	SystemCreatedAt  time.Time
+	Quantity         int32
+	UnitPrice        datatype.Money
+	ReservedQuantity int32
+	Options          []byte
+}
+
+// Inserts nothing when the order already has a line of the product with the
+// same options at the same price, which the item should have been merged
+// into.
+func (q *Queries) CreateOrderItem(ctx context.Context, arg CreateOrderItemParams) (int64, error) {
+	result, err := q.db.Exec(ctx, createOrderItem,
 		arg.ID,
 		arg.OrganizationID,
 		arg.OrderID,
 		arg.ProductID,
 		arg.SystemCreatedAt,
 		arg.Quantity,
-		arg.PriceCents,
+		arg.UnitPrice,
+		arg.ReservedQuantity,
+		arg.Options,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const createOrderStatusChange = `-- name: CreateOrderStatusChange :exec
+INSERT INTO app_sweetshop.order_status_history (id, organization_id, order_id, system_created_at, from_status, to_status)
+VALUES ($1, $2, $3, $4, $5, $6)
+`
+
+type CreateOrderStatusChangeParams struct {
+	ID              uuid.UUID
+	OrganizationID  uuid.UUID
+	OrderID         uuid.UUID
+	SystemCreatedAt time.Time
+	FromStatus      *string
+	ToStatus        string
+}
+
+func (q *Queries) CreateOrderStatusChange(ctx context.Context, arg CreateOrderStatusChangeParams) error {
+	_, err := q.db.Exec(ctx, createOrderStatusChange,
+		arg.ID,
+		arg.OrganizationID,
+		arg.OrderID,
+		arg.SystemCreatedAt,
+		arg.FromStatus,
+		arg.ToStatus,
 	)
 	return err
 }
 
+const createOrderTaxLine = `-- name: CreateOrderTaxLine :exec
+INSERT INTO app_sweetshop.order_tax_lines (order_id, organization_id, rate, net, tax)
+VALUES ($1, $2, $3, $4, $5)
+`
+
+type CreateOrderTaxLineParams struct {
+	OrderID        uuid.UUID
+	OrganizationID uuid.UUID
+	Rate           int32
+	Net            datatype.Money
+	Tax            datatype.Money
+}
+
+func (q *Queries) CreateOrderTaxLine(ctx context.Context, arg CreateOrderTaxLineParams) error {
+	_, err := q.db.Exec(ctx, createOrderTaxLine,
+		arg.OrderID,
+		arg.OrganizationID,
+		arg.Rate,
+		arg.Net,
+		arg.Tax,
+	)
+	return err
+}
+
+const deleteOrderItem = `-- name: DeleteOrderItem :one
+DELETE FROM app_sweetshop.order_items WHERE id = $1 RETURNING reserved_quantity
+`
+
+func (q *Queries) DeleteOrderItem(ctx context.Context, id uuid.UUID) (int32, error) {
+	row := q.db.QueryRow(ctx, deleteOrderItem, id)
+	var reserved_quantity int32
+	err := row.Scan(&reserved_quantity)
+	return reserved_quantity, err
+}
+
 const findOrderByID = `-- name: FindOrderByID :one
-SELECT id, organization_id, system_created_at, system_updated_at, status FROM app_sweetshop.orders WHERE id = $1
+SELECT id, organization_id, system_created_at, system_updated_at, status, submitted_at, paid_at, fulfilled_at, cancelled_at, currency, subtotal, discount, tax, total, tax_pricing, tax_rounding FROM app_sweetshop.orders WHERE id = $1
 `
 
 func (q *Queries) FindOrderByID(ctx context.Context, id uuid.UUID) (Order, error) {
@@ -102,23 +211,87 @@ func (q *Queries) FindOrderByID(ctx context.Context, id uuid.UUID) (Order, error
 		&i.SystemCreatedAt,
 		&i.SystemUpdatedAt,
 		&i.Status,
+		&i.SubmittedAt,
+		&i.PaidAt,
+		&i.FulfilledAt,
+		&i.CancelledAt,
+		&i.Currency,
+		&i.Subtotal,
+		&i.Discount,
+		&i.Tax,
+		&i.Total,
+		&i.TaxPricing,
+		&i.TaxRounding,
 	)
 	return i, err
 }
 
+const listOrderAdjustmentsByOrderID = `-- name: ListOrderAdjustmentsByOrderID :many
+SELECT id, organization_id, order_id, order_item_id, promotion_id, system_created_at, description, amount FROM app_sweetshop.order_adjustments WHERE order_id = $1 ORDER BY id
+`
+
+func (q *Queries) ListOrderAdjustmentsByOrderID(ctx context.Context, orderID uuid.UUID) ([]OrderAdjustment, error) {
+	rows, err := q.db.Query(ctx, listOrderAdjustmentsByOrderID, orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []OrderAdjustment{}
+	for rows.Next() {
+		var i OrderAdjustment
+		if err := rows.Scan(
+			&i.ID,
+			&i.OrganizationID,
+			&i.OrderID,
+			&i.OrderItemID,
+			&i.PromotionID,
+			&i.SystemCreatedAt,
+			&i.Description,
+			&i.Amount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listOrderItemsByOrderID = `-- name: ListOrderItemsByOrderID :many
-SELECT id, organization_id, order_id, product_id, system_created_at, quantity, price_cents FROM app_sweetshop.order_items WHERE order_id = $1 ORDER BY system_created_at
+SELECT oi.id, oi.organization_id, oi.order_id, oi.product_id, oi.system_created_at, oi.quantity, oi.unit_price, oi.reserved_quantity, oi.options, p.name AS product_name, p.category AS product_category
+FROM app_sweetshop.order_items oi
+JOIN app_sweetshop.products p ON p.id = oi.product_id
+WHERE oi.order_id = $1
+ORDER BY oi.system_created_at
 `
 
-func (q *Queries) ListOrderItemsByOrderID(ctx context.Context, orderID uuid.UUID) ([]OrderItem, error) {
+type ListOrderItemsByOrderIDRow struct {
+	ID               uuid.UUID
+	OrganizationID   uuid.UUID
+	OrderID          uuid.UUID
+	ProductID        uuid.UUID
+	SystemCreatedAt  time.Time
+	Quantity         int32
+	UnitPrice        datatype.Money
+	ReservedQuantity int32
+	Options          []byte
+	ProductName      string
+	ProductCategory  string
+}
+
+// Joins products without a deleted_at filter so items keep resolving their
+// product after it has been soft-deleted.
+func (q *Queries) ListOrderItemsByOrderID(ctx context.Context, orderID uuid.UUID) ([]ListOrderItemsByOrderIDRow, error) {
 	rows, err := q.db.Query(ctx, listOrderItemsByOrderID, orderID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	items := []OrderItem{}
+	items := []ListOrderItemsByOrderIDRow{}
 	for rows.Next() {
-		var i OrderItem
+		var i ListOrderItemsByOrderIDRow
 		if err := rows.Scan(
 			&i.ID,
 			&i.OrganizationID,
@@ -126,7 +299,42 @@ func (q *Queries) ListOrderItemsByOrderID(ctx context.Context, orderID uuid.UUID
 			&i.ProductID,
 			&i.SystemCreatedAt,
 			&i.Quantity,
-			&i.PriceCents,
+			&i.UnitPrice,
+			&i.ReservedQuantity,
+			&i.Options,
+			&i.ProductName,
+			&i.ProductCategory,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listOrderStatusHistory = `-- name: ListOrderStatusHistory :many
+SELECT id, organization_id, order_id, system_created_at, from_status, to_status FROM app_sweetshop.order_status_history WHERE order_id = $1 ORDER BY id
+`
+
+func (q *Queries) ListOrderStatusHistory(ctx context.Context, orderID uuid.UUID) ([]OrderStatusHistory, error) {
+	rows, err := q.db.Query(ctx, listOrderStatusHistory, orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []OrderStatusHistory{}
+	for rows.Next() {
+		var i OrderStatusHistory
+		if err := rows.Scan(
+			&i.ID,
+			&i.OrganizationID,
+			&i.OrderID,
+			&i.SystemCreatedAt,
+			&i.FromStatus,
+			&i.ToStatus,
 		); err != nil {
 			return nil, err
 		}
@@ -137,3 +345,267 @@ func (q *Queries) ListOrderItemsByOrderID(ctx context.Context, orderID uuid.UUID
 	}
 	return items, nil
 }
+
+const listOrderTaxLinesByOrderID = `-- name: ListOrderTaxLinesByOrderID :many
+SELECT order_id, organization_id, rate, net, tax FROM app_sweetshop.order_tax_lines WHERE order_id = $1 ORDER BY rate
+`
+
+func (q *Queries) ListOrderTaxLinesByOrderID(ctx context.Context, orderID uuid.UUID) ([]OrderTaxLine, error) {
+	rows, err := q.db.Query(ctx, listOrderTaxLinesByOrderID, orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []OrderTaxLine{}
+	for rows.Next() {
+		var i OrderTaxLine
+		if err := rows.Scan(
+			&i.OrderID,
+			&i.OrganizationID,
+			&i.Rate,
+			&i.Net,
+			&i.Tax,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listOrders = `-- name: ListOrders :many
+SELECT o.id, o.organization_id, o.system_created_at, o.system_updated_at, o.status, o.submitted_at, o.paid_at, o.fulfilled_at, o.cancelled_at, o.currency, o.subtotal, o.discount, o.tax, o.total, o.tax_pricing, o.tax_rounding, i.items
+FROM app_sweetshop.orders o
+CROSS JOIN LATERAL (
+    SELECT COALESCE(jsonb_agg(jsonb_build_object(
+        'id', oi.id,
+        'product_id', oi.product_id,
+        'product_name', p.name,
+        'product_category', p.category,
+        'system_created_at', oi.system_created_at,
+        'quantity', oi.quantity,
+        'reserved_quantity', oi.reserved_quantity,
+        'unit_price', oi.unit_price,
+        'options', oi.options
+    ) ORDER BY oi.system_created_at), '[]')::JSONB AS items
+    FROM app_sweetshop.order_items oi
+    JOIN app_sweetshop.products p ON p.id = oi.product_id
+    WHERE oi.order_id = o.id
+) i
+WHERE ($1::UUID IS NULL OR o.id < $1)
+    AND ($2::TEXT IS NULL OR o.status = $2)
+    AND ($3::TIMESTAMPTZ IS NULL OR o.system_created_at >= $3)
+    AND ($4::TIMESTAMPTZ IS NULL OR o.system_created_at < $4)
+    AND ($5::TEXT IS NULL OR (o.total).currency = $5)
+    AND ($6::BIGINT IS NULL OR (o.total).amount >= $6)
+    AND ($7::BIGINT IS NULL OR (o.total).amount <= $7)
+    AND ($8::UUID IS NULL OR EXISTS (
+        SELECT 1 FROM app_sweetshop.order_items c
+        WHERE c.order_id = o.id AND c.product_id = $8
+    ))
+ORDER BY o.id DESC
+LIMIT $9
+`
+
+type ListOrdersParams struct {
+	After         *uuid.UUID
+	Status        *string
+	CreatedFrom   *time.Time
+	CreatedTo     *time.Time
+	TotalCurrency *string
+	MinTotal      *int64
+	MaxTotal      *int64
+	ProductID     *uuid.UUID
+	PageSize      int32
+}
+
+type ListOrdersRow struct {
+	Order Order
+	Items []byte
+}
+
+// A page of orders, newest first, each with its items aggregated into a JSON
+// array so the page is read in one query. A filter left null matches every
+// order; the total filters only match orders whose total was fixed when they
+// were submitted.
+func (q *Queries) ListOrders(ctx context.Context, arg ListOrdersParams) ([]ListOrdersRow, error) {
+	rows, err := q.db.Query(ctx, listOrders,
+		arg.After,
+		arg.Status,
+		arg.CreatedFrom,
+		arg.CreatedTo,
+		arg.TotalCurrency,
+		arg.MinTotal,
+		arg.MaxTotal,
+		arg.ProductID,
+		arg.PageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListOrdersRow
+	for rows.Next() {
+		var i ListOrdersRow
+		if err := rows.Scan(
+			&i.Order.ID,
+			&i.Order.OrganizationID,
+			&i.Order.SystemCreatedAt,
+			&i.Order.SystemUpdatedAt,
+			&i.Order.Status,
+			&i.Order.SubmittedAt,
+			&i.Order.PaidAt,
+			&i.Order.FulfilledAt,
+			&i.Order.CancelledAt,
+			&i.Order.Currency,
+			&i.Order.Subtotal,
+			&i.Order.Discount,
+			&i.Order.Tax,
+			&i.Order.Total,
+			&i.Order.TaxPricing,
+			&i.Order.TaxRounding,
+			&i.Items,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockOrderStatus = `-- name: LockOrderStatus :one
+SELECT status FROM app_sweetshop.orders WHERE id = $1 FOR UPDATE
+`
+
+// Holds the order until the transaction ends, so changes to its items and
+// its transitions are applied one at a time.
+func (q *Queries) LockOrderStatus(ctx context.Context, id uuid.UUID) (string, error) {
+	row := q.db.QueryRow(ctx, lockOrderStatus, id)
+	var status string
+	err := row.Scan(&status)
+	return status, err
+}
+
+const releaseOrderReservations = `-- name: ReleaseOrderReservations :exec
+UPDATE app_sweetshop.inventory i
+SET system_updated_at = $1,
+    reserved = i.reserved - r.quantity
+FROM (
+    SELECT product_id, SUM(reserved_quantity)::INTEGER AS quantity
+    FROM app_sweetshop.order_items
+    WHERE order_id = $2 AND reserved_quantity > 0
+    GROUP BY product_id
+) r
+WHERE i.product_id = r.product_id
+`
+
+type ReleaseOrderReservationsParams struct {
+	SystemUpdatedAt time.Time
+	OrderID         uuid.UUID
+}
+
+// Sums per product so an order with several lines of one product updates its
+// inventory row once.
+func (q *Queries) ReleaseOrderReservations(ctx context.Context, arg ReleaseOrderReservationsParams) error {
+	_, err := q.db.Exec(ctx, releaseOrderReservations, arg.SystemUpdatedAt, arg.OrderID)
+	return err
+}
+
+const transitionOrder = `-- name: TransitionOrder :execrows
+UPDATE app_sweetshop.orders
+SET system_updated_at = $1,
+    status = $2,
+    submitted_at = $3,
+    paid_at = $4,
+    fulfilled_at = $5,
+    cancelled_at = $6,
+    subtotal = COALESCE($7, subtotal),
+    discount = COALESCE($8, discount),
+    tax = COALESCE($9, tax),
+    total = COALESCE($10, total),
+    tax_pricing = COALESCE($11, tax_pricing),
+    tax_rounding = COALESCE($12, tax_rounding)
+WHERE id = $13 AND status = $14
+`
+
+type TransitionOrderParams struct {
+	SystemUpdatedAt time.Time
+	ToStatus        string
+	SubmittedAt     *time.Time
+	PaidAt          *time.Time
+	FulfilledAt     *time.Time
+	CancelledAt     *time.Time
+	Subtotal        *datatype.Money
+	Discount        *datatype.Money
+	Tax             *datatype.Money
+	Total           *datatype.Money
+	TaxPricing      *string
+	TaxRounding     *string
+	ID              uuid.UUID
+	FromStatus      string
+}
+
+// Matching on from_status makes concurrent transitions of the same order
+// race safely: only the first one updates a row. The price is only passed
+// when the order is submitted and kept afterwards.
+func (q *Queries) TransitionOrder(ctx context.Context, arg TransitionOrderParams) (int64, error) {
+	result, err := q.db.Exec(ctx, transitionOrder,
+		arg.SystemUpdatedAt,
+		arg.ToStatus,
+		arg.SubmittedAt,
+		arg.PaidAt,
+		arg.FulfilledAt,
+		arg.CancelledAt,
+		arg.Subtotal,
+		arg.Discount,
+		arg.Tax,
+		arg.Total,
+		arg.TaxPricing,
+		arg.TaxRounding,
+		arg.ID,
+		arg.FromStatus,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const updateOrderItemQuantity = `-- name: UpdateOrderItemQuantity :execrows
+UPDATE app_sweetshop.order_items
+SET quantity = $1,
+    reserved_quantity = $2
+WHERE id = $3
+    AND quantity = $4
+    AND reserved_quantity = $5
+`
+
+type UpdateOrderItemQuantityParams struct {
+	Quantity             int32
+	ReservedQuantity     int32
+	ID                   uuid.UUID
+	FromQuantity         int32
+	FromReservedQuantity int32
+}
+
+// Matching on the previous quantities makes the update fail if the item
+// changed since it was read.
+func (q *Queries) UpdateOrderItemQuantity(ctx context.Context, arg UpdateOrderItemQuantityParams) (int64, error) {
+	result, err := q.db.Exec(ctx, updateOrderItemQuantity,
+		arg.Quantity,
+		arg.ReservedQuantity,
+		arg.ID,
+		arg.FromQuantity,
+		arg.FromReservedQuantity,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
@@ -37,7 +37,7 @@ func (q *Queries) CreateOrganization(ctx context.Context, arg CreateOrganization
 }
 
 const findOrganizationByID = `-- name: FindOrganizationByID :one
-SELECT id, system_created_at, system_updated_at, name, slug FROM app_sweetshop.organizations WHERE id = $1
+SELECT id, system_created_at, system_updated_at, name, slug, currency FROM app_sweetshop.organizations WHERE id = $1
 `
 
 func (q *Queries) FindOrganizationByID(ctx context.Context, id uuid.UUID) (Organization, error) {
@@ -49,12 +49,13 @@ func (q *Queries) FindOrganizationByID(ctx context.Context, id uuid.UUID) (Organ
 		&i.SystemUpdatedAt,
 		&i.Name,
 		&i.Slug,
+		&i.Currency,
 	)
 	return i, err
 }
 
 const findOrganizationBySlug = `-- name: FindOrganizationBySlug :one
-SELECT id, system_created_at, system_updated_at, name, slug FROM app_sweetshop.organizations WHERE slug = $1
+SELECT id, system_created_at, system_updated_at, name, slug, currency FROM app_sweetshop.organizations WHERE slug = $1
 `
 
 func (q *Queries) FindOrganizationBySlug(ctx context.Context, slug string) (Organization, error) {
@@ -66,6 +67,58 @@ func (q *Queries) FindOrganizationBySlug(ctx context.Context, slug string) (Orga
 		&i.SystemUpdatedAt,
 		&i.Name,
 		&i.Slug,
+		&i.Currency,
 	)
 	return i, err
 }
+
+const listOrganizations = `-- name: ListOrganizations :many
+SELECT id, system_created_at, system_updated_at, name, slug, currency FROM app_sweetshop.organizations ORDER BY id
+`
+
+func (q *Queries) ListOrganizations(ctx context.Context) ([]Organization, error) {
+	rows, err := q.db.Query(ctx, listOrganizations)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Organization{}
+	for rows.Next() {
+		var i Organization
+		if err := rows.Scan(
+			&i.ID,
+			&i.SystemCreatedAt,
+			&i.SystemUpdatedAt,
+			&i.Name,
+			&i.Slug,
+			&i.Currency,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateOrganizationSettings = `-- name: UpdateOrganizationSettings :execrows
+UPDATE app_sweetshop.organizations
+SET system_updated_at = $2, currency = $3
+WHERE id = $1
+`
+
+type UpdateOrganizationSettingsParams struct {
+	ID              uuid.UUID
+	SystemUpdatedAt time.Time
+	Currency        string
+}
+
+func (q *Queries) UpdateOrganizationSettings(ctx context.Context, arg UpdateOrganizationSettingsParams) (int64, error) {
+	result, err := q.db.Exec(ctx, updateOrganizationSettings, arg.ID, arg.SystemUpdatedAt, arg.Currency)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
@@ -9,12 +9,13 @@ import (
 	"context"
 	"time"
 
+	"github.com/bbsbb/go-edge/core/datatype"
 	"github.com/google/uuid"
 )
 
 const createProduct = `-- name: CreateProduct :exec
-INSERT INTO app_sweetshop.products (id, organization_id, system_created_at, system_updated_at, name, category, price_cents)
-VALUES ($1, $2, $3, $4, $5, $6, $7)
+INSERT INTO app_sweetshop.products (id, organization_id, system_created_at, system_updated_at, name, category, price, sku, description, active, sort_order)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
 `
 
 type CreateProductParams struct {
@@ -24,7 +25,11 @@ type CreateProductParams struct {
 	SystemUpdatedAt time.Time
 	Name            string
 	Category        string
-	PriceCents      int32
+	Price           datatype.Money
+	Sku             *string
+	Description     string
+	Active          bool
+	SortOrder       int32
 }
 
 func (q *Queries) CreateProduct(ctx context.Context, arg CreateProductParams) error {
@@ -35,25 +40,17 @@ func (q *Queries) CreateProduct(ctx context.Context, arg CreateProductParams) er
 		arg.SystemUpdatedAt,
 		arg.Name,
 		arg.Category,
-		arg.PriceCents,
+		arg.Price,
+		arg.Sku,
+		arg.Description,
+		arg.Active,
+		arg.SortOrder,
 	)
 	return err
 }
 
-const deleteProduct = `-- name: DeleteProduct :execrows
-DELETE FROM app_sweetshop.products WHERE id = $1
-`
-
-func (q *Queries) DeleteProduct(ctx context.Context, id uuid.UUID) (int64, error) {
-	result, err := q.db.Exec(ctx, deleteProduct, id)
-	if err != nil {
-		return 0, err
-	}
-	return result.RowsAffected(), nil
-}
-
 const findProductByID = `-- name: FindProductByID :one
-SELECT id, organization_id, system_created_at, system_updated_at, name, category, price_cents FROM app_sweetshop.products WHERE id = $1
+SELECT id, organization_id, system_created_at, system_updated_at, name, category, deleted_at, price, sku, description, active, sort_order, search_vector FROM app_sweetshop.products WHERE id = $1 AND deleted_at IS NULL
 `
 
 func (q *Queries) FindProductByID(ctx context.Context, id uuid.UUID) (Product, error) {
@@ -66,13 +63,19 @@ func (q *Queries) FindProductByID(ctx context.Context, id uuid.UUID) (Product, e
 		&i.SystemUpdatedAt,
 		&i.Name,
 		&i.Category,
-		&i.PriceCents,
+		&i.DeletedAt,
+		&i.Price,
+		&i.Sku,
+		&i.Description,
+		&i.Active,
+		&i.SortOrder,
+		&i.SearchVector,
 	)
 	return i, err
 }
 
 const listProducts = `-- name: ListProducts :many
-SELECT id, organization_id, system_created_at, system_updated_at, name, category, price_cents FROM app_sweetshop.products ORDER BY name
+SELECT id, organization_id, system_created_at, system_updated_at, name, category, deleted_at, price, sku, description, active, sort_order, search_vector FROM app_sweetshop.products WHERE deleted_at IS NULL ORDER BY sort_order, name
 `
 
 func (q *Queries) ListProducts(ctx context.Context) ([]Product, error) {
@@ -91,7 +94,13 @@ func (q *Queries) ListProducts(ctx context.Context) ([]Product, error) {
 			&i.SystemUpdatedAt,
 			&i.Name,
 			&i.Category,
-			&i.PriceCents,
+			&i.DeletedAt,
+			&i.Price,
+			&i.Sku,
+			&i.Description,
+			&i.Active,
+			&i.SortOrder,
+			&i.SearchVector,
 		); err != nil {
 			return nil, err
 		}
@@ -103,10 +112,77 @@ func (q *Queries) ListProducts(ctx context.Context) ([]Product, error) {
 	return items, nil
 }
 
+const purgeDeletedProducts = `-- name: PurgeDeletedProducts :execrows
+DELETE FROM app_sweetshop.products p
+WHERE p.deleted_at < $1
+  AND NOT EXISTS (SELECT 1 FROM app_sweetshop.order_items oi WHERE oi.product_id = p.id)
+`
+
+func (q *Queries) PurgeDeletedProducts(ctx context.Context, deletedAt *time.Time) (int64, error) {
+	result, err := q.db.Exec(ctx, purgeDeletedProducts, deletedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const restoreProduct = `-- name: RestoreProduct :one
+UPDATE app_sweetshop.products
+SET system_updated_at = $2, deleted_at = NULL
+WHERE id = $1 AND deleted_at IS NOT NULL
+RETURNING id, organization_id, system_created_at, system_updated_at, name, category, deleted_at, price, sku, description, active, sort_order, search_vector
+`
+
+type RestoreProductParams struct {
+	ID              uuid.UUID
+	SystemUpdatedAt time.Time
+}
+
+func (q *Queries) RestoreProduct(ctx context.Context, arg RestoreProductParams) (Product, error) {
+	row := q.db.QueryRow(ctx, restoreProduct, arg.ID, arg.SystemUpdatedAt)
+	var i Product
+	err := row.Scan(
+		&i.ID,
+		&i.OrganizationID,
+		&i.SystemCreatedAt,
+		&i.SystemUpdatedAt,
+		&i.Name,
+		&i.Category,
+		&i.DeletedAt,
+		&i.Price,
+		&i.Sku,
+		&i.Description,
+		&i.Active,
+		&i.SortOrder,
+		&i.SearchVector,
+	)
+	return i, err
+}
+
+const softDeleteProduct = `-- name: SoftDeleteProduct :execrows
+UPDATE app_sweetshop.products
+SET system_updated_at = $2, deleted_at = $2
+WHERE id = $1 AND deleted_at IS NULL
+`
+
+type SoftDeleteProductParams struct {
+	ID              uuid.UUID
+	SystemUpdatedAt time.Time
+}
+
+func (q *Queries) SoftDeleteProduct(ctx context.Context, arg SoftDeleteProductParams) (int64, error) {
+	result, err := q.db.Exec(ctx, softDeleteProduct, arg.ID, arg.SystemUpdatedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const updateProduct = `-- name: UpdateProduct :execrows
 UPDATE app_sweetshop.products
-SET system_updated_at = $2, name = $3, category = $4, price_cents = $5
-WHERE id = $1
+SET system_updated_at = $2, name = $3, category = $4, price = $5, sku = $6, description = $7,
+    active = $8, sort_order = $9
+WHERE id = $1 AND deleted_at IS NULL
 `
 
 type UpdateProductParams struct {
@@ -114,7 +190,11 @@ type UpdateProductParams struct {
 	SystemUpdatedAt time.Time
 	Name            string
 	Category        string
-	PriceCents      int32
+	Price           datatype.Money
+	Sku             *string
+	Description     string
+	Active          bool
+	SortOrder       int32
 }
 
 func (q *Queries) UpdateProduct(ctx context.Context, arg UpdateProductParams) (int64, error) {
@@ -123,7 +203,11 @@ func (q *Queries) UpdateProduct(ctx context.Context, arg UpdateProductParams) (i
 		arg.SystemUpdatedAt,
 		arg.Name,
 		arg.Category,
-		arg.PriceCents,
+		arg.Price,
+		arg.Sku,
+		arg.Description,
+		arg.Active,
+		arg.SortOrder,
 	)
 	if err != nil {
 		return 0, err
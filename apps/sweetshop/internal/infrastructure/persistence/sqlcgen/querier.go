@@ -6,24 +6,143 @@ package sqlcgen
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 )
 
 type Querier interface {
-	CloseOrder(ctx context.Context, arg CloseOrderParams) (Order, error)
+	// SKIP LOCKED lets concurrent dispatchers share the queue; moving
+	// next_attempt_at to the lease keeps a claimed delivery out of later claims
+	// until its attempt is recorded.
+	ClaimDueWebhookDeliveries(ctx context.Context, arg ClaimDueWebhookDeliveriesParams) ([]ClaimDueWebhookDeliveriesRow, error)
+	// Takes the claim unless another token holds it past now. Claiming again
+	// with the same token extends it, so a step can check it still holds it.
+	ClaimOrderPayment(ctx context.Context, arg ClaimOrderPaymentParams) (int64, error)
+	// Sums per product so an order with several lines of one product updates its
+	// inventory row once.
+	CommitOrderReservations(ctx context.Context, arg CommitOrderReservationsParams) error
+	CreateCategory(ctx context.Context, arg CreateCategoryParams) error
+	// A line's revenue is its price less the adjustments on it.
+	CreateDailyProductSalesFrom(ctx context.Context, arg CreateDailyProductSalesFromParams) error
+	// Orders are counted on the UTC day they were fulfilled at the price fixed
+	// when they were submitted, and refunds on the UTC day they were made.
+	CreateDailySalesFrom(ctx context.Context, arg CreateDailySalesFromParams) error
 	CreateOrder(ctx context.Context, arg CreateOrderParams) error
-	CreateOrderItem(ctx context.Context, arg CreateOrderItemParams) error
+	CreateOrderAdjustment(ctx context.Context, arg CreateOrderAdjustmentParams) error
+	// Inserts nothing when the order already has a line of the product with the
+	// same options at the same price, which the item should have been merged
+	// into.
+	CreateOrderItem(ctx context.Context, arg CreateOrderItemParams) (int64, error)
+	CreateOrderStatusChange(ctx context.Context, arg CreateOrderStatusChangeParams) error
+	CreateOrderTaxLine(ctx context.Context, arg CreateOrderTaxLineParams) error
 	CreateOrganization(ctx context.Context, arg CreateOrganizationParams) error
+	CreatePayment(ctx context.Context, arg CreatePaymentParams) error
 	CreateProduct(ctx context.Context, arg CreateProductParams) error
-	DeleteProduct(ctx context.Context, id uuid.UUID) (int64, error)
+	CreateProductOption(ctx context.Context, arg CreateProductOptionParams) error
+	CreateProductOptionGroup(ctx context.Context, arg CreateProductOptionGroupParams) error
+	CreatePromotion(ctx context.Context, arg CreatePromotionParams) error
+	CreateStockAdjustment(ctx context.Context, arg CreateStockAdjustmentParams) error
+	CreateTaxRate(ctx context.Context, arg CreateTaxRateParams) error
+	CreateWebhookEndpoint(ctx context.Context, arg CreateWebhookEndpointParams) error
+	// Deletes nothing while products, soft-deleted or not, or promotions still
+	// use the category.
+	DeleteCategory(ctx context.Context, id uuid.UUID) (int64, error)
+	DeleteDailyProductSalesFrom(ctx context.Context, arg DeleteDailyProductSalesFromParams) error
+	DeleteDailySalesFrom(ctx context.Context, arg DeleteDailySalesFromParams) error
+	DeleteOrderItem(ctx context.Context, id uuid.UUID) (int32, error)
+	// Deletes the product's options with their groups.
+	DeleteProductOptionGroups(ctx context.Context, productID uuid.UUID) error
+	DeletePromotion(ctx context.Context, id uuid.UUID) (int64, error)
+	DeleteTaxRates(ctx context.Context, organizationID uuid.UUID) error
+	DeleteWebhookEndpoint(ctx context.Context, id uuid.UUID) (int64, error)
+	EnsureInventory(ctx context.Context, arg EnsureInventoryParams) error
+	FindCategoryByID(ctx context.Context, id uuid.UUID) (Category, error)
+	FindCategoryBySlug(ctx context.Context, slug string) (Category, error)
+	FindInventoryByProductID(ctx context.Context, productID uuid.UUID) (Inventory, error)
 	FindOrderByID(ctx context.Context, id uuid.UUID) (Order, error)
 	FindOrganizationByID(ctx context.Context, id uuid.UUID) (Organization, error)
 	FindOrganizationBySlug(ctx context.Context, slug string) (Organization, error)
 	FindProductByID(ctx context.Context, id uuid.UUID) (Product, error)
-	ListOrderItemsByOrderID(ctx context.Context, orderID uuid.UUID) ([]OrderItem, error)
+	FindPromotionByID(ctx context.Context, id uuid.UUID) (Promotion, error)
+	FindSalesReportRefresh(ctx context.Context, organizationID uuid.UUID) (time.Time, error)
+	FindTaxProfile(ctx context.Context, organizationID uuid.UUID) (TaxProfile, error)
+	FindWebhookEndpointByID(ctx context.Context, id uuid.UUID) (WebhookEndpoint, error)
+	IsOrderPaymentClaimed(ctx context.Context, arg IsOrderPaymentClaimedParams) (bool, error)
+	ListActivePromotions(ctx context.Context, at time.Time) ([]Promotion, error)
+	ListCategories(ctx context.Context) ([]Category, error)
+	ListOrderAdjustmentsByOrderID(ctx context.Context, orderID uuid.UUID) ([]OrderAdjustment, error)
+	// Joins products without a deleted_at filter so items keep resolving their
+	// product after it has been soft-deleted.
+	ListOrderItemsByOrderID(ctx context.Context, orderID uuid.UUID) ([]ListOrderItemsByOrderIDRow, error)
+	ListOrderEventsAfter(ctx context.Context, arg ListOrderEventsAfterParams) ([]OrderEvent, error)
+	ListOrderStatusHistory(ctx context.Context, orderID uuid.UUID) ([]OrderStatusHistory, error)
+	ListOrderTaxLinesByOrderID(ctx context.Context, orderID uuid.UUID) ([]OrderTaxLine, error)
+	// A page of orders, newest first, each with its items aggregated into a JSON
+	// array so the page is read in one query. A filter left null matches every
+	// order; the total filters only match orders whose total was fixed when they
+	// were submitted.
+	ListOrders(ctx context.Context, arg ListOrdersParams) ([]ListOrdersRow, error)
+	ListOrganizations(ctx context.Context) ([]Organization, error)
+	ListPaymentsByOrderID(ctx context.Context, orderID uuid.UUID) ([]Payment, error)
+	ListProductOptionGroups(ctx context.Context, productID uuid.UUID) ([]ProductOptionGroup, error)
+	// The options of all of the product's groups.
+	ListProductOptions(ctx context.Context, productID uuid.UUID) ([]ProductOption, error)
 	ListProducts(ctx context.Context) ([]Product, error)
+	ListPromotions(ctx context.Context) ([]Promotion, error)
+	// Periods are named by their first day; weeks start on Monday.
+	ListSales(ctx context.Context, arg ListSalesParams) ([]ListSalesRow, error)
+	ListStockAdjustmentsByProductID(ctx context.Context, arg ListStockAdjustmentsByProductIDParams) ([]StockAdjustment, error)
+	ListTaxRates(ctx context.Context, organizationID uuid.UUID) ([]TaxRate, error)
+	// Soft-deleted products are included; they were sold all the same.
+	ListTopProducts(ctx context.Context, arg ListTopProductsParams) ([]ListTopProductsRow, error)
+	ListWebhookDeliveriesByEndpoint(ctx context.Context, arg ListWebhookDeliveriesByEndpointParams) ([]WebhookDelivery, error)
+	ListWebhookEndpoints(ctx context.Context) ([]WebhookEndpoint, error)
+	// Holds the row until the transaction ends, so reservations and adjustments
+	// of the same product are applied one at a time.
+	LockInventory(ctx context.Context, productID uuid.UUID) (Inventory, error)
+	// Holds the order until the transaction ends, so changes to its items are
+	// applied one at a time.
+	LockOrderStatus(ctx context.Context, id uuid.UUID) (string, error)
+	PurgeDeletedProducts(ctx context.Context, deletedAt *time.Time) (int64, error)
+	// The right-hand sides read the row as it was before the update, so the
+	// endpoint is disabled by the attempt that reaches disable_after.
+	RecordWebhookEndpointFailure(ctx context.Context, arg RecordWebhookEndpointFailureParams) (bool, error)
+	// Counts one use of each promotion the order's adjustments come from. A
+	// promotion that has no uses left is not updated, so fewer rows than
+	// promotions means the order cannot be submitted as priced.
+	RedeemOrderPromotions(ctx context.Context, arg RedeemOrderPromotionsParams) (int64, error)
+	ReleaseOrderPayment(ctx context.Context, arg ReleaseOrderPaymentParams) error
+	ReleaseOrderPromotions(ctx context.Context, arg ReleaseOrderPromotionsParams) error
+	// Sums per product so an order with several lines of one product updates its
+	// inventory row once.
+	ReleaseOrderReservations(ctx context.Context, arg ReleaseOrderReservationsParams) error
+	ResetWebhookEndpointFailures(ctx context.Context, id uuid.UUID) error
+	RestoreProduct(ctx context.Context, arg RestoreProductParams) (Product, error)
+	// Live products whose search_vector matches prefix_query, or whose name has a
+	// word within the pg_trgm word similarity threshold of term. Full-text matches
+	// rank above trigram-only ones, which rank by similarity. The highlights wrap
+	// the matched words in <mark> tags and leave the rest of the text as stored.
+	SearchProducts(ctx context.Context, arg SearchProductsParams) ([]SearchProductsRow, error)
+	SoftDeleteProduct(ctx context.Context, arg SoftDeleteProductParams) (int64, error)
+	// Matching on from_status makes concurrent transitions of the same order
+	// race safely: only the first one updates a row. The price is only passed
+	// when the order is submitted and kept afterwards.
+	TransitionOrder(ctx context.Context, arg TransitionOrderParams) (int64, error)
+	// A new slug carries over to the products, promotions and tax rates of the
+	// category.
+	UpdateCategory(ctx context.Context, arg UpdateCategoryParams) (int64, error)
+	UpdateInventory(ctx context.Context, arg UpdateInventoryParams) error
+	// Matching on the previous quantities makes the update fail if the item
+	// changed since it was read.
+	UpdateOrderItemQuantity(ctx context.Context, arg UpdateOrderItemQuantityParams) (int64, error)
+	UpdateOrganizationSettings(ctx context.Context, arg UpdateOrganizationSettingsParams) (int64, error)
 	UpdateProduct(ctx context.Context, arg UpdateProductParams) (int64, error)
+	UpdatePromotion(ctx context.Context, arg UpdatePromotionParams) (int64, error)
+	UpdateWebhookDeliveryAttempt(ctx context.Context, arg UpdateWebhookDeliveryAttemptParams) error
+	UpdateWebhookEndpoint(ctx context.Context, arg UpdateWebhookEndpointParams) (int64, error)
+	UpsertSalesReportRefresh(ctx context.Context, arg UpsertSalesReportRefreshParams) error
+	UpsertTaxProfile(ctx context.Context, arg UpsertTaxProfileParams) error
 }
 
 var _ Querier = (*Queries)(nil)
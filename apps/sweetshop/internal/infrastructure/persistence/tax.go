@@ -0,0 +1,163 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/bbsbb/go-edge/core/fx/rlsfx"
+	"github.com/bbsbb/go-edge/sweetshop/internal/domain"
+	"github.com/bbsbb/go-edge/sweetshop/internal/infrastructure/persistence/sqlcgen"
+)
+
+type TaxProfileRepo struct {
+	db *rlsfx.DB
+}
+
+func NewTaxProfileRepo(db *rlsfx.DB) *TaxProfileRepo {
+	return &TaxProfileRepo{db: db}
+}
+
+func (r *TaxProfileRepo) Find(ctx context.Context, organizationID uuid.UUID) (*domain.TaxProfile, error) {
+	return rlsfx.Query(r.db, ctx, func(ctx context.Context, tx pgx.Tx) (*domain.TaxProfile, error) {
+		q := sqlcgen.New(tx)
+		m, err := q.FindTaxProfile(ctx, organizationID)
+		if err != nil {
+			return nil, err
+		}
+		rates, err := q.ListTaxRates(ctx, organizationID)
+		if err != nil {
+			return nil, err
+		}
+		return taxProfileToDomain(m, rates), nil
+	})
+}
+
+// Save replaces the profile's rates with the ones it has now.
+func (r *TaxProfileRepo) Save(ctx context.Context, profile *domain.TaxProfile) error {
+	return rlsfx.Exec(r.db, ctx, func(ctx context.Context, tx pgx.Tx) error {
+		q := sqlcgen.New(tx)
+		if err := q.UpsertTaxProfile(ctx, taxProfileUpsertParams(profile)); err != nil {
+			return err
+		}
+		if err := q.DeleteTaxRates(ctx, profile.OrganizationID); err != nil {
+			return err
+		}
+		for _, params := range taxRateCreateParams(profile) {
+			if err := q.CreateTaxRate(ctx, params); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// settleTax stores the tax lines of an order whose price the change fixes.
+func settleTax(ctx context.Context, q *sqlcgen.Queries, order *domain.Order, change domain.OrderStatusChange) error {
+	if !change.FixesPrice() {
+		return nil
+	}
+	for _, line := range order.Tax.Lines {
+		if err := q.CreateOrderTaxLine(ctx, orderTaxLineCreateParams(line)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setOrderPrice adds the order's price to the transition that fixes it. The
+// order must have been taxed.
+func setOrderPrice(params *sqlcgen.TransitionOrderParams, o *domain.Order) error {
+	if o.Tax == nil {
+		return errors.New("order has not been taxed")
+	}
+	subtotal, err := o.Subtotal()
+	if err != nil {
+		return err
+	}
+	discount, err := o.Discount()
+	if err != nil {
+		return err
+	}
+	tax, err := o.TaxTotal()
+	if err != nil {
+		return err
+	}
+	total, err := o.Total()
+	if err != nil {
+		return err
+	}
+	pricing, rounding := string(o.Tax.Pricing), string(o.Tax.Rounding)
+	params.Subtotal = &subtotal
+	params.Discount = &discount
+	params.Tax = &tax
+	params.Total = &total
+	params.TaxPricing = &pricing
+	params.TaxRounding = &rounding
+	return nil
+}
+
+func taxProfileToDomain(m sqlcgen.TaxProfile, rates []sqlcgen.TaxRate) *domain.TaxProfile {
+	profile := &domain.TaxProfile{
+		OrganizationID: m.OrganizationID,
+		UpdatedAt:      m.SystemUpdatedAt,
+		Pricing:        domain.TaxPricing(m.Pricing),
+		Rounding:       domain.TaxRounding(m.Rounding),
+		DefaultRate:    m.DefaultRate,
+		Rates:          make(map[domain.ProductCategory]int32, len(rates)),
+	}
+	for _, rate := range rates {
+		profile.Rates[domain.ProductCategory(rate.Category)] = rate.Rate
+	}
+	return profile
+}
+
+func taxProfileUpsertParams(p *domain.TaxProfile) sqlcgen.UpsertTaxProfileParams {
+	return sqlcgen.UpsertTaxProfileParams{
+		OrganizationID:  p.OrganizationID,
+		SystemCreatedAt: p.UpdatedAt,
+		Pricing:         string(p.Pricing),
+		Rounding:        string(p.Rounding),
+		DefaultRate:     p.DefaultRate,
+	}
+}
+
+// taxRateCreateParams returns the profile's rates ordered by category.
+func taxRateCreateParams(p *domain.TaxProfile) []sqlcgen.CreateTaxRateParams {
+	params := make([]sqlcgen.CreateTaxRateParams, 0, len(p.Rates))
+	for category, rate := range p.Rates {
+		params = append(params, sqlcgen.CreateTaxRateParams{
+			OrganizationID: p.OrganizationID,
+			Category:       string(category),
+			Rate:           rate,
+		})
+	}
+	slices.SortFunc(params, func(a, b sqlcgen.CreateTaxRateParams) int {
+		return strings.Compare(a.Category, b.Category)
+	})
+	return params
+}
+
+func orderTaxLineToDomain(m sqlcgen.OrderTaxLine) domain.OrderTaxLine {
+	return domain.OrderTaxLine{
+		OrganizationID: m.OrganizationID,
+		OrderID:        m.OrderID,
+		Rate:           m.Rate,
+		Net:            m.Net,
+		Tax:            m.Tax,
+	}
+}
+
+func orderTaxLineCreateParams(l domain.OrderTaxLine) sqlcgen.CreateOrderTaxLineParams {
+	return sqlcgen.CreateOrderTaxLineParams{
+		OrderID:        l.OrderID,
+		OrganizationID: l.OrganizationID,
+		Rate:           l.Rate,
+		Net:            l.Net,
+		Tax:            l.Tax,
+	}
+}
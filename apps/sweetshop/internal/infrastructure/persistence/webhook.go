@@ -0,0 +1,144 @@
+package persistence
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/bbsbb/go-edge/core/fx/rlsfx"
+	"github.com/bbsbb/go-edge/sweetshop/internal/domain"
+	"github.com/bbsbb/go-edge/sweetshop/internal/infrastructure/persistence/sqlcgen"
+)
+
+type WebhookEndpointRepo struct {
+	db *rlsfx.DB
+}
+
+func NewWebhookEndpointRepo(db *rlsfx.DB) *WebhookEndpointRepo {
+	return &WebhookEndpointRepo{db: db}
+}
+
+func (r *WebhookEndpointRepo) FindByID(ctx context.Context, id uuid.UUID) (*domain.WebhookEndpoint, error) {
+	return rlsfx.Query(r.db, ctx, func(ctx context.Context, tx pgx.Tx) (*domain.WebhookEndpoint, error) {
+		m, err := sqlcgen.New(tx).FindWebhookEndpointByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		return webhookEndpointToDomain(m), nil
+	})
+}
+
+func (r *WebhookEndpointRepo) List(ctx context.Context) ([]*domain.WebhookEndpoint, error) {
+	return rlsfx.Query(r.db, ctx, func(ctx context.Context, tx pgx.Tx) ([]*domain.WebhookEndpoint, error) {
+		rows, err := sqlcgen.New(tx).ListWebhookEndpoints(ctx)
+		if err != nil {
+			return nil, err
+		}
+		endpoints := make([]*domain.WebhookEndpoint, len(rows))
+		for i, m := range rows {
+			endpoints[i] = webhookEndpointToDomain(m)
+		}
+		return endpoints, nil
+	})
+}
+
+func (r *WebhookEndpointRepo) Create(ctx context.Context, endpoint *domain.WebhookEndpoint) error {
+	return rlsfx.Exec(r.db, ctx, func(ctx context.Context, tx pgx.Tx) error {
+		return sqlcgen.New(tx).CreateWebhookEndpoint(ctx, webhookEndpointCreateParams(endpoint))
+	})
+}
+
+func (r *WebhookEndpointRepo) Update(ctx context.Context, endpoint *domain.WebhookEndpoint) error {
+	return rlsfx.Exec(r.db, ctx, func(ctx context.Context, tx pgx.Tx) error {
+		n, err := sqlcgen.New(tx).UpdateWebhookEndpoint(ctx, webhookEndpointUpdateParams(endpoint))
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return pgx.ErrNoRows
+		}
+		return nil
+	})
+}
+
+// Delete removes the endpoint together with its delivery log.
+func (r *WebhookEndpointRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	return rlsfx.Exec(r.db, ctx, func(ctx context.Context, tx pgx.Tx) error {
+		n, err := sqlcgen.New(tx).DeleteWebhookEndpoint(ctx, id)
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return pgx.ErrNoRows
+		}
+		return nil
+	})
+}
+
+type WebhookDeliveryRepo struct {
+	db *rlsfx.DB
+}
+
+func NewWebhookDeliveryRepo(db *rlsfx.DB) *WebhookDeliveryRepo {
+	return &WebhookDeliveryRepo{db: db}
+}
+
+func (r *WebhookDeliveryRepo) ListByEndpoint(ctx context.Context, endpointID uuid.UUID, limit int32) ([]domain.WebhookDelivery, error) {
+	return rlsfx.Query(r.db, ctx, func(ctx context.Context, tx pgx.Tx) ([]domain.WebhookDelivery, error) {
+		rows, err := sqlcgen.New(tx).ListWebhookDeliveriesByEndpoint(ctx, sqlcgen.ListWebhookDeliveriesByEndpointParams{
+			EndpointID: endpointID,
+			Limit:      limit,
+		})
+		if err != nil {
+			return nil, err
+		}
+		deliveries := make([]domain.WebhookDelivery, len(rows))
+		for i, m := range rows {
+			deliveries[i] = webhookDeliveryToDomain(m)
+		}
+		return deliveries, nil
+	})
+}
+
+// ClaimDue commits the lease before returning, so the claimed deliveries stay
+// out of other dispatchers' claims while they are being sent.
+func (r *WebhookDeliveryRepo) ClaimDue(ctx context.Context, now, leaseUntil time.Time, limit int32) ([]domain.PendingWebhookDelivery, error) {
+	return rlsfx.Query(r.db, ctx, func(ctx context.Context, tx pgx.Tx) ([]domain.PendingWebhookDelivery, error) {
+		rows, err := sqlcgen.New(tx).ClaimDueWebhookDeliveries(ctx, sqlcgen.ClaimDueWebhookDeliveriesParams{
+			Now:        now,
+			BatchSize:  limit,
+			LeaseUntil: leaseUntil,
+		})
+		if err != nil {
+			return nil, err
+		}
+		deliveries := make([]domain.PendingWebhookDelivery, len(rows))
+		for i, m := range rows {
+			deliveries[i] = pendingWebhookDeliveryToDomain(m)
+		}
+		return deliveries, nil
+	})
+}
+
+func (r *WebhookDeliveryRepo) RecordAttempt(ctx context.Context, delivery *domain.WebhookDelivery, disableAfter int32) (bool, error) {
+	return rlsfx.Query(r.db, ctx, func(ctx context.Context, tx pgx.Tx) (bool, error) {
+		q := sqlcgen.New(tx)
+		if err := q.UpdateWebhookDeliveryAttempt(ctx, webhookDeliveryAttemptParams(delivery)); err != nil {
+			return false, err
+		}
+		if delivery.Status == domain.WebhookDeliverySucceeded {
+			return false, q.ResetWebhookEndpointFailures(ctx, delivery.EndpointID)
+		}
+		enabled, err := q.RecordWebhookEndpointFailure(ctx, sqlcgen.RecordWebhookEndpointFailureParams{
+			DisableAfter: disableAfter,
+			FailedAt:     delivery.UpdatedAt,
+			ID:           delivery.EndpointID,
+		})
+		if err != nil {
+			return false, err
+		}
+		return !enabled, nil
+	})
+}
@@ -0,0 +1,25 @@
+-- +goose Up
+ALTER TABLE app_sweetshop.products ADD COLUMN deleted_at TIMESTAMPTZ;
+
+-- A soft-deleted product must not block re-creating a product with the same
+-- name, so uniqueness only applies to live rows.
+ALTER TABLE app_sweetshop.products DROP CONSTRAINT IF EXISTS products_organization_id_name_key;
+
+CREATE UNIQUE INDEX IF NOT EXISTS products_organization_id_name_live_key
+    ON app_sweetshop.products (organization_id, name)
+    WHERE deleted_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS products_deleted_at_idx
+    ON app_sweetshop.products (deleted_at)
+    WHERE deleted_at IS NOT NULL;
+
+-- The existing organization_isolation_policy has no deleted_at predicate, so
+-- soft-deleted rows stay visible only to their own tenant. Scoping deleted rows
+-- out of default reads is done in queries, not in the policy, so restore and
+-- purge can still reach them.
+
+-- +goose Down
+DROP INDEX IF EXISTS app_sweetshop.products_deleted_at_idx;
+DROP INDEX IF EXISTS app_sweetshop.products_organization_id_name_live_key;
+ALTER TABLE app_sweetshop.products ADD CONSTRAINT products_organization_id_name_key UNIQUE (organization_id, name);
+ALTER TABLE app_sweetshop.products DROP COLUMN IF EXISTS deleted_at;
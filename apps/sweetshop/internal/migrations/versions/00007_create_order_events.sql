@@ -0,0 +1,65 @@
+-- +goose Up
+-- Append-only log of order lifecycle changes. It backs the order event stream:
+-- live delivery goes through NOTIFY, and reconnecting clients replay the rows
+-- after their Last-Event-ID.
+CREATE TABLE IF NOT EXISTS app_sweetshop.order_events (
+    id UUID PRIMARY KEY DEFAULT uuidv7(),
+    organization_id UUID NOT NULL REFERENCES app_sweetshop.organizations(id),
+    order_id UUID NOT NULL REFERENCES app_sweetshop.orders(id),
+    system_created_at TIMESTAMPTZ NOT NULL DEFAULT CLOCK_TIMESTAMP(),
+    type TEXT NOT NULL,
+    status TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS order_events_organization_id_id_idx
+    ON app_sweetshop.order_events (organization_id, id);
+
+ALTER TABLE app_sweetshop.order_events ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY organization_isolation_policy ON app_sweetshop.order_events
+    USING (organization_id = current_setting('app_sweetshop.current_organization')::UUID);
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION app_sweetshop.record_order_event() RETURNS TRIGGER AS $$
+DECLARE
+    event app_sweetshop.order_events;
+BEGIN
+    INSERT INTO app_sweetshop.order_events (organization_id, order_id, type, status)
+    VALUES (
+        NEW.organization_id,
+        NEW.id,
+        CASE TG_OP WHEN 'INSERT' THEN 'order.opened' ELSE 'order.status_changed' END,
+        NEW.status
+    )
+    RETURNING * INTO event;
+
+    -- Delivered on commit only, so listeners never see rolled-back changes.
+    PERFORM pg_notify('app_sweetshop_order_events', json_build_object(
+        'id', event.id,
+        'organization_id', event.organization_id,
+        'order_id', event.order_id,
+        'occurred_at', event.system_created_at,
+        'type', event.type,
+        'status', event.status
+    )::TEXT);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER orders_record_opened_event
+    AFTER INSERT ON app_sweetshop.orders
+    FOR EACH ROW
+    EXECUTE FUNCTION app_sweetshop.record_order_event();
+
+CREATE TRIGGER orders_record_status_event
+    AFTER UPDATE OF status ON app_sweetshop.orders
+    FOR EACH ROW
+    WHEN (OLD.status IS DISTINCT FROM NEW.status)
+    EXECUTE FUNCTION app_sweetshop.record_order_event();
+
+-- +goose Down
+DROP TRIGGER IF EXISTS orders_record_status_event ON app_sweetshop.orders;
+DROP TRIGGER IF EXISTS orders_record_opened_event ON app_sweetshop.orders;
+DROP FUNCTION IF EXISTS app_sweetshop.record_order_event();
+DROP TABLE IF EXISTS app_sweetshop.order_events;
@@ -0,0 +1,93 @@
+-- +goose Up
+-- Outbound webhooks. Endpoints subscribe to event types; a trigger on
+-- order_events queues one delivery per subscribed endpoint in the transaction
+-- that recorded the event, so a delivery exists exactly when the change
+-- committed. The webhook delivery job sends them and records every attempt.
+CREATE TABLE IF NOT EXISTS app_sweetshop.webhook_endpoints (
+    id UUID PRIMARY KEY,
+    organization_id UUID NOT NULL REFERENCES app_sweetshop.organizations(id),
+    system_created_at TIMESTAMPTZ NOT NULL,
+    system_updated_at TIMESTAMPTZ NOT NULL,
+    url TEXT NOT NULL,
+    secret TEXT NOT NULL,
+    event_types TEXT[] NOT NULL,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    consecutive_failures INTEGER NOT NULL DEFAULT 0,
+    disabled_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS webhook_endpoints_organization_id_idx
+    ON app_sweetshop.webhook_endpoints (organization_id);
+
+ALTER TABLE app_sweetshop.webhook_endpoints ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY organization_isolation_policy ON app_sweetshop.webhook_endpoints
+    USING (organization_id = current_setting('app_sweetshop.current_organization')::UUID);
+
+CREATE TABLE IF NOT EXISTS app_sweetshop.webhook_deliveries (
+    id UUID PRIMARY KEY DEFAULT uuidv7(),
+    organization_id UUID NOT NULL REFERENCES app_sweetshop.organizations(id),
+    endpoint_id UUID NOT NULL REFERENCES app_sweetshop.webhook_endpoints(id) ON DELETE CASCADE,
+    event_id UUID NOT NULL,
+    system_created_at TIMESTAMPTZ NOT NULL DEFAULT CLOCK_TIMESTAMP(),
+    system_updated_at TIMESTAMPTZ NOT NULL DEFAULT CLOCK_TIMESTAMP(),
+    event_type TEXT NOT NULL,
+    payload JSONB NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'succeeded', 'failed')),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT CLOCK_TIMESTAMP(),
+    last_attempt_at TIMESTAMPTZ,
+    response_status INTEGER,
+    last_error TEXT
+);
+
+CREATE INDEX IF NOT EXISTS webhook_deliveries_due_idx
+    ON app_sweetshop.webhook_deliveries (organization_id, next_attempt_at)
+    WHERE status = 'pending';
+
+CREATE INDEX IF NOT EXISTS webhook_deliveries_endpoint_id_id_idx
+    ON app_sweetshop.webhook_deliveries (endpoint_id, id);
+
+ALTER TABLE app_sweetshop.webhook_deliveries ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY organization_isolation_policy ON app_sweetshop.webhook_deliveries
+    USING (organization_id = current_setting('app_sweetshop.current_organization')::UUID);
+
+-- Webhook event types are named after the order's new state: order.opened,
+-- order.closed, and so on for statuses added later.
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION app_sweetshop.enqueue_webhook_deliveries() RETURNS TRIGGER AS $$
+DECLARE
+    webhook_type TEXT := CASE NEW.type WHEN 'order.opened' THEN 'order.opened' ELSE 'order.' || NEW.status END;
+BEGIN
+    INSERT INTO app_sweetshop.webhook_deliveries (organization_id, endpoint_id, event_id, event_type, payload)
+    SELECT
+        NEW.organization_id,
+        e.id,
+        NEW.id,
+        webhook_type,
+        jsonb_build_object(
+            'id', NEW.id,
+            'type', webhook_type,
+            'created_at', NEW.system_created_at,
+            'data', jsonb_build_object('order_id', NEW.order_id, 'status', NEW.status)
+        )
+    FROM app_sweetshop.webhook_endpoints e
+    WHERE e.organization_id = NEW.organization_id
+      AND e.enabled
+      AND webhook_type = ANY (e.event_types);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER order_events_enqueue_webhook_deliveries
+    AFTER INSERT ON app_sweetshop.order_events
+    FOR EACH ROW
+    EXECUTE FUNCTION app_sweetshop.enqueue_webhook_deliveries();
+
+-- +goose Down
+DROP TRIGGER IF EXISTS order_events_enqueue_webhook_deliveries ON app_sweetshop.order_events;
+DROP FUNCTION IF EXISTS app_sweetshop.enqueue_webhook_deliveries();
+DROP TABLE IF EXISTS app_sweetshop.webhook_deliveries;
+DROP TABLE IF EXISTS app_sweetshop.webhook_endpoints;
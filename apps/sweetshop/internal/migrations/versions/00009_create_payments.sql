@@ -0,0 +1,26 @@
+-- +goose Up
+-- One row per call to the payment gateway, successful or not. An order's
+-- payment state is derived from its successful rows in order.
+CREATE TABLE IF NOT EXISTS app_sweetshop.payments (
+    id UUID PRIMARY KEY,
+    organization_id UUID NOT NULL REFERENCES app_sweetshop.organizations(id),
+    order_id UUID NOT NULL REFERENCES app_sweetshop.orders(id),
+    system_created_at TIMESTAMPTZ NOT NULL DEFAULT TRANSACTION_TIMESTAMP(),
+    provider TEXT NOT NULL,
+    operation TEXT NOT NULL CHECK (operation IN ('authorize', 'capture', 'refund')),
+    status TEXT NOT NULL CHECK (status IN ('succeeded', 'failed')),
+    amount_cents INTEGER NOT NULL CHECK (amount_cents > 0),
+    provider_reference TEXT,
+    error TEXT
+);
+
+CREATE INDEX IF NOT EXISTS payments_order_id_id_idx
+    ON app_sweetshop.payments (order_id, id);
+
+ALTER TABLE app_sweetshop.payments ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY organization_isolation_policy ON app_sweetshop.payments
+    USING (organization_id = current_setting('app_sweetshop.current_organization')::UUID);
+
+-- +goose Down
+DROP TABLE IF EXISTS app_sweetshop.payments;
@@ -0,0 +1,121 @@
+-- +goose Up
+-- Orders move open -> submitted -> paid -> fulfilled, and can be cancelled
+-- from any state before fulfilment. Each state records when it was entered.
+ALTER TABLE app_sweetshop.orders
+    ADD COLUMN submitted_at TIMESTAMPTZ,
+    ADD COLUMN paid_at TIMESTAMPTZ,
+    ADD COLUMN fulfilled_at TIMESTAMPTZ,
+    ADD COLUMN cancelled_at TIMESTAMPTZ;
+
+ALTER TABLE app_sweetshop.orders DROP CONSTRAINT IF EXISTS orders_status_check;
+
+-- Closed orders were paid and handed over. The status triggers are held off
+-- so the rewrite does not emit order events or webhook deliveries.
+ALTER TABLE app_sweetshop.orders DISABLE TRIGGER orders_record_status_event;
+
+UPDATE app_sweetshop.orders
+SET status = 'fulfilled',
+    submitted_at = system_updated_at,
+    paid_at = system_updated_at,
+    fulfilled_at = system_updated_at
+WHERE status = 'closed';
+
+ALTER TABLE app_sweetshop.orders ENABLE TRIGGER orders_record_status_event;
+
+ALTER TABLE app_sweetshop.orders
+    ADD CONSTRAINT orders_status_check
+        CHECK (status IN ('open', 'submitted', 'paid', 'fulfilled', 'cancelled')),
+    ADD CONSTRAINT orders_submitted_at_check
+        CHECK (status NOT IN ('submitted', 'paid', 'fulfilled') OR submitted_at IS NOT NULL),
+    ADD CONSTRAINT orders_paid_at_check
+        CHECK (status NOT IN ('paid', 'fulfilled') OR paid_at IS NOT NULL),
+    ADD CONSTRAINT orders_fulfilled_at_check
+        CHECK ((status = 'fulfilled') = (fulfilled_at IS NOT NULL)),
+    ADD CONSTRAINT orders_cancelled_at_check
+        CHECK ((status = 'cancelled') = (cancelled_at IS NOT NULL));
+
+UPDATE app_sweetshop.webhook_endpoints
+SET event_types = array_replace(event_types, 'order.closed', 'order.fulfilled')
+WHERE 'order.closed' = ANY(event_types);
+
+-- A cancelled order's authorization is voided with the provider.
+ALTER TABLE app_sweetshop.payments DROP CONSTRAINT IF EXISTS payments_operation_check;
+ALTER TABLE app_sweetshop.payments ADD CONSTRAINT payments_operation_check
+    CHECK (operation IN ('authorize', 'capture', 'void', 'refund'));
+
+-- One row per status an order entered; from_status is NULL for the row
+-- recording the order being opened. The pairs allowed mirror the state
+-- machine in the domain package.
+CREATE TABLE IF NOT EXISTS app_sweetshop.order_status_history (
+    id UUID PRIMARY KEY,
+    organization_id UUID NOT NULL REFERENCES app_sweetshop.organizations(id),
+    order_id UUID NOT NULL REFERENCES app_sweetshop.orders(id),
+    system_created_at TIMESTAMPTZ NOT NULL DEFAULT TRANSACTION_TIMESTAMP(),
+    from_status TEXT,
+    to_status TEXT NOT NULL,
+    CONSTRAINT order_status_history_transition_check CHECK (
+        (from_status IS NULL AND to_status = 'open')
+        OR (from_status, to_status) IN (
+            ('open', 'submitted'),
+            ('submitted', 'paid'),
+            ('paid', 'fulfilled'),
+            ('open', 'cancelled'),
+            ('submitted', 'cancelled'),
+            ('paid', 'cancelled')
+        )
+    )
+);
+
+CREATE INDEX IF NOT EXISTS order_status_history_order_id_id_idx
+    ON app_sweetshop.order_status_history (order_id, id);
+
+ALTER TABLE app_sweetshop.order_status_history ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY organization_isolation_policy ON app_sweetshop.order_status_history
+    USING (organization_id = current_setting('app_sweetshop.current_organization')::UUID);
+
+-- Existing orders get their opening, and fulfilled ones the path they are now
+-- recorded as having taken. uuidv7() is monotonic within a session, so each
+-- order's rows keep their step order.
+INSERT INTO app_sweetshop.order_status_history (id, organization_id, order_id, system_created_at, from_status, to_status)
+SELECT uuidv7(), o.organization_id, o.id, t.at, t.from_status, t.to_status
+FROM app_sweetshop.orders o
+CROSS JOIN LATERAL (VALUES
+    (o.system_created_at, NULL, 'open'),
+    (o.submitted_at, 'open', 'submitted'),
+    (o.paid_at, 'submitted', 'paid'),
+    (o.fulfilled_at, 'paid', 'fulfilled')
+) AS t(at, from_status, to_status)
+WHERE t.at IS NOT NULL;
+
+-- +goose Down
+DROP TABLE IF EXISTS app_sweetshop.order_status_history;
+
+ALTER TABLE app_sweetshop.payments DROP CONSTRAINT IF EXISTS payments_operation_check;
+ALTER TABLE app_sweetshop.payments ADD CONSTRAINT payments_operation_check
+    CHECK (operation IN ('authorize', 'capture', 'refund'));
+
+UPDATE app_sweetshop.webhook_endpoints
+SET event_types = array_replace(event_types, 'order.fulfilled', 'order.closed')
+WHERE 'order.fulfilled' = ANY(event_types);
+
+ALTER TABLE app_sweetshop.orders
+    DROP CONSTRAINT IF EXISTS orders_cancelled_at_check,
+    DROP CONSTRAINT IF EXISTS orders_fulfilled_at_check,
+    DROP CONSTRAINT IF EXISTS orders_paid_at_check,
+    DROP CONSTRAINT IF EXISTS orders_submitted_at_check,
+    DROP CONSTRAINT IF EXISTS orders_status_check;
+
+ALTER TABLE app_sweetshop.orders DISABLE TRIGGER orders_record_status_event;
+
+UPDATE app_sweetshop.orders SET status = 'closed' WHERE status IN ('submitted', 'paid', 'fulfilled', 'cancelled');
+
+ALTER TABLE app_sweetshop.orders ENABLE TRIGGER orders_record_status_event;
+
+ALTER TABLE app_sweetshop.orders ADD CONSTRAINT orders_status_check CHECK (status IN ('open', 'closed'));
+
+ALTER TABLE app_sweetshop.orders
+    DROP COLUMN IF EXISTS cancelled_at,
+    DROP COLUMN IF EXISTS fulfilled_at,
+    DROP COLUMN IF EXISTS paid_at,
+    DROP COLUMN IF EXISTS submitted_at;
@@ -0,0 +1,50 @@
+-- +goose Up
+-- A product's stock is tracked once it has an inventory row; products
+-- without one can be ordered without limit. reserved counts the units held
+-- by items of orders that are neither fulfilled nor cancelled.
+CREATE TABLE IF NOT EXISTS app_sweetshop.inventory (
+    product_id UUID PRIMARY KEY REFERENCES app_sweetshop.products(id) ON DELETE CASCADE,
+    organization_id UUID NOT NULL REFERENCES app_sweetshop.organizations(id),
+    system_created_at TIMESTAMPTZ NOT NULL DEFAULT TRANSACTION_TIMESTAMP(),
+    system_updated_at TIMESTAMPTZ NOT NULL DEFAULT TRANSACTION_TIMESTAMP(),
+    on_hand INTEGER NOT NULL DEFAULT 0,
+    reserved INTEGER NOT NULL DEFAULT 0,
+    CONSTRAINT inventory_reserved_check CHECK (reserved >= 0 AND reserved <= on_hand)
+);
+
+ALTER TABLE app_sweetshop.inventory ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY organization_isolation_policy ON app_sweetshop.inventory
+    USING (organization_id = current_setting('app_sweetshop.current_organization')::UUID);
+
+-- Manual changes to on-hand stock. Reservations are not logged here; they
+-- follow from order items.
+CREATE TABLE IF NOT EXISTS app_sweetshop.stock_adjustments (
+    id UUID PRIMARY KEY,
+    organization_id UUID NOT NULL REFERENCES app_sweetshop.organizations(id),
+    product_id UUID NOT NULL REFERENCES app_sweetshop.products(id) ON DELETE CASCADE,
+    system_created_at TIMESTAMPTZ NOT NULL DEFAULT TRANSACTION_TIMESTAMP(),
+    quantity_delta INTEGER NOT NULL CHECK (quantity_delta <> 0),
+    reason TEXT NOT NULL CHECK (reason IN ('received', 'returned', 'damaged', 'expired', 'correction')),
+    note TEXT,
+    on_hand_after INTEGER NOT NULL CHECK (on_hand_after >= 0)
+);
+
+CREATE INDEX IF NOT EXISTS stock_adjustments_product_id_id_idx
+    ON app_sweetshop.stock_adjustments (product_id, id);
+
+ALTER TABLE app_sweetshop.stock_adjustments ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY organization_isolation_policy ON app_sweetshop.stock_adjustments
+    USING (organization_id = current_setting('app_sweetshop.current_organization')::UUID);
+
+-- The units of an item taken from tracked stock when it was added. Items of
+-- untracked products reserve nothing.
+ALTER TABLE app_sweetshop.order_items
+    ADD COLUMN IF NOT EXISTS reserved_quantity INTEGER NOT NULL DEFAULT 0
+        CONSTRAINT order_items_reserved_quantity_check CHECK (reserved_quantity >= 0);
+
+-- +goose Down
+ALTER TABLE app_sweetshop.order_items DROP COLUMN IF EXISTS reserved_quantity;
+DROP TABLE IF EXISTS app_sweetshop.stock_adjustments;
+DROP TABLE IF EXISTS app_sweetshop.inventory;
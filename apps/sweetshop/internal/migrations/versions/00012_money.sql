@@ -0,0 +1,85 @@
+-- +goose Up
+-- Each organization prices in one ISO 4217 currency. Amounts are stored as
+-- app_sweetshop.money, an amount in the currency's minor unit paired with
+-- the currency, which scans into datatype.Money.
+ALTER TABLE app_sweetshop.organizations
+    ADD COLUMN IF NOT EXISTS currency TEXT NOT NULL DEFAULT 'EUR'
+        CONSTRAINT organizations_currency_check CHECK (currency ~ '^[A-Z]{3}$');
+
+CREATE TYPE app_sweetshop.money AS (amount BIGINT, currency TEXT);
+
+ALTER TABLE app_sweetshop.products ADD COLUMN price app_sweetshop.money;
+
+UPDATE app_sweetshop.products p
+SET price = ROW(p.price_cents, o.currency)::app_sweetshop.money
+FROM app_sweetshop.organizations o
+WHERE o.id = p.organization_id;
+
+ALTER TABLE app_sweetshop.products
+    ALTER COLUMN price SET NOT NULL,
+    ADD CONSTRAINT products_price_check CHECK ((price).amount > 0 AND (price).currency IS NOT NULL),
+    DROP COLUMN price_cents;
+
+-- An order is priced in its organization's currency at the time it was
+-- opened; its items and payments share it.
+ALTER TABLE app_sweetshop.orders ADD COLUMN currency TEXT;
+
+UPDATE app_sweetshop.orders r
+SET currency = o.currency
+FROM app_sweetshop.organizations o
+WHERE o.id = r.organization_id;
+
+ALTER TABLE app_sweetshop.orders ALTER COLUMN currency SET NOT NULL;
+
+ALTER TABLE app_sweetshop.order_items ADD COLUMN unit_price app_sweetshop.money;
+
+UPDATE app_sweetshop.order_items i
+SET unit_price = ROW(i.price_cents, r.currency)::app_sweetshop.money
+FROM app_sweetshop.orders r
+WHERE r.id = i.order_id;
+
+ALTER TABLE app_sweetshop.order_items
+    ALTER COLUMN unit_price SET NOT NULL,
+    ADD CONSTRAINT order_items_unit_price_check CHECK ((unit_price).amount > 0 AND (unit_price).currency IS NOT NULL),
+    DROP COLUMN price_cents;
+
+ALTER TABLE app_sweetshop.payments ADD COLUMN amount app_sweetshop.money;
+
+UPDATE app_sweetshop.payments p
+SET amount = ROW(p.amount_cents, r.currency)::app_sweetshop.money
+FROM app_sweetshop.orders r
+WHERE r.id = p.order_id;
+
+ALTER TABLE app_sweetshop.payments
+    ALTER COLUMN amount SET NOT NULL,
+    ADD CONSTRAINT payments_amount_check CHECK ((amount).amount > 0 AND (amount).currency IS NOT NULL),
+    DROP COLUMN amount_cents;
+
+-- +goose Down
+-- Amounts that no longer fit the INTEGER cents columns fail the cast.
+ALTER TABLE app_sweetshop.payments ADD COLUMN amount_cents INTEGER;
+UPDATE app_sweetshop.payments SET amount_cents = (amount).amount::INTEGER;
+ALTER TABLE app_sweetshop.payments
+    ALTER COLUMN amount_cents SET NOT NULL,
+    ADD CONSTRAINT payments_amount_cents_check CHECK (amount_cents > 0),
+    DROP COLUMN amount;
+
+ALTER TABLE app_sweetshop.order_items ADD COLUMN price_cents INTEGER;
+UPDATE app_sweetshop.order_items SET price_cents = (unit_price).amount::INTEGER;
+ALTER TABLE app_sweetshop.order_items
+    ALTER COLUMN price_cents SET NOT NULL,
+    ADD CONSTRAINT order_items_price_cents_check CHECK (price_cents > 0),
+    DROP COLUMN unit_price;
+
+ALTER TABLE app_sweetshop.orders DROP COLUMN IF EXISTS currency;
+
+ALTER TABLE app_sweetshop.products ADD COLUMN price_cents INTEGER;
+UPDATE app_sweetshop.products SET price_cents = (price).amount::INTEGER;
+ALTER TABLE app_sweetshop.products
+    ALTER COLUMN price_cents SET NOT NULL,
+    ADD CONSTRAINT products_price_cents_check CHECK (price_cents > 0),
+    DROP COLUMN price;
+
+DROP TYPE IF EXISTS app_sweetshop.money;
+
+ALTER TABLE app_sweetshop.organizations DROP COLUMN IF EXISTS currency;
@@ -0,0 +1,69 @@
+-- +goose Up
+-- Discount rules. Only the columns of a promotion's kind are set; category
+-- limits it to one product category. usage_count is the number of submitted
+-- orders it was applied to and never exceeds usage_limit.
+CREATE TABLE IF NOT EXISTS app_sweetshop.promotions (
+    id UUID PRIMARY KEY,
+    organization_id UUID NOT NULL REFERENCES app_sweetshop.organizations(id),
+    system_created_at TIMESTAMPTZ NOT NULL DEFAULT TRANSACTION_TIMESTAMP(),
+    system_updated_at TIMESTAMPTZ NOT NULL DEFAULT TRANSACTION_TIMESTAMP(),
+    name TEXT NOT NULL,
+    kind TEXT NOT NULL CHECK (kind IN ('percentage', 'fixed_amount', 'buy_x_get_y')),
+    category TEXT CHECK (category IN ('ice_cream', 'marshmallow')),
+    percent_off INTEGER,
+    amount_off app_sweetshop.money,
+    buy_quantity INTEGER,
+    get_quantity INTEGER,
+    starts_at TIMESTAMPTZ,
+    ends_at TIMESTAMPTZ,
+    usage_limit INTEGER CHECK (usage_limit > 0),
+    usage_count INTEGER NOT NULL DEFAULT 0,
+    CONSTRAINT promotions_kind_fields_check CHECK (
+        (kind = 'percentage' AND percent_off BETWEEN 1 AND 100
+            AND amount_off IS NULL AND buy_quantity IS NULL AND get_quantity IS NULL)
+        OR (kind = 'fixed_amount' AND (amount_off).amount > 0
+            AND percent_off IS NULL AND buy_quantity IS NULL AND get_quantity IS NULL)
+        OR (kind = 'buy_x_get_y' AND buy_quantity > 0 AND get_quantity > 0
+            AND percent_off IS NULL AND amount_off IS NULL)
+    ),
+    CONSTRAINT promotions_window_check CHECK (ends_at > starts_at),
+    CONSTRAINT promotions_usage_check CHECK (usage_count >= 0 AND usage_count <= usage_limit)
+);
+
+CREATE INDEX IF NOT EXISTS promotions_organization_id_idx
+    ON app_sweetshop.promotions (organization_id);
+
+ALTER TABLE app_sweetshop.promotions ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY organization_isolation_policy ON app_sweetshop.promotions
+    USING (organization_id = current_setting('app_sweetshop.current_organization')::UUID);
+
+-- The discounts given to an order's items, stored when the order is
+-- submitted. Open orders are priced from the active promotions instead.
+-- Deleting a promotion keeps the adjustments it made, with its name as their
+-- description.
+CREATE TABLE IF NOT EXISTS app_sweetshop.order_adjustments (
+    id UUID PRIMARY KEY,
+    organization_id UUID NOT NULL REFERENCES app_sweetshop.organizations(id),
+    order_id UUID NOT NULL REFERENCES app_sweetshop.orders(id) ON DELETE CASCADE,
+    order_item_id UUID NOT NULL REFERENCES app_sweetshop.order_items(id) ON DELETE CASCADE,
+    promotion_id UUID REFERENCES app_sweetshop.promotions(id) ON DELETE SET NULL,
+    system_created_at TIMESTAMPTZ NOT NULL DEFAULT TRANSACTION_TIMESTAMP(),
+    description TEXT NOT NULL,
+    amount app_sweetshop.money NOT NULL CHECK ((amount).amount < 0)
+);
+
+CREATE INDEX IF NOT EXISTS order_adjustments_order_id_id_idx
+    ON app_sweetshop.order_adjustments (order_id, id);
+
+CREATE INDEX IF NOT EXISTS order_adjustments_promotion_id_idx
+    ON app_sweetshop.order_adjustments (promotion_id);
+
+ALTER TABLE app_sweetshop.order_adjustments ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY organization_isolation_policy ON app_sweetshop.order_adjustments
+    USING (organization_id = current_setting('app_sweetshop.current_organization')::UUID);
+
+-- +goose Down
+DROP TABLE IF EXISTS app_sweetshop.order_adjustments;
+DROP TABLE IF EXISTS app_sweetshop.promotions;
@@ -0,0 +1,104 @@
+-- +goose Up
+-- How an organization taxes its orders. Rates are in basis points (2000 is
+-- 20%); items of a category without a row in tax_rates are taxed at
+-- default_rate. Organizations without a profile charge no tax.
+CREATE TABLE IF NOT EXISTS app_sweetshop.tax_profiles (
+    organization_id UUID PRIMARY KEY REFERENCES app_sweetshop.organizations(id),
+    system_created_at TIMESTAMPTZ NOT NULL DEFAULT TRANSACTION_TIMESTAMP(),
+    system_updated_at TIMESTAMPTZ NOT NULL DEFAULT TRANSACTION_TIMESTAMP(),
+    pricing TEXT NOT NULL CHECK (pricing IN ('exclusive', 'inclusive')),
+    rounding TEXT NOT NULL CHECK (rounding IN ('per_line', 'per_order')),
+    default_rate INTEGER NOT NULL CHECK (default_rate BETWEEN 0 AND 10000)
+);
+
+ALTER TABLE app_sweetshop.tax_profiles ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY organization_isolation_policy ON app_sweetshop.tax_profiles
+    USING (organization_id = current_setting('app_sweetshop.current_organization')::UUID);
+
+CREATE TABLE IF NOT EXISTS app_sweetshop.tax_rates (
+    organization_id UUID NOT NULL REFERENCES app_sweetshop.tax_profiles(organization_id) ON DELETE CASCADE,
+    category TEXT NOT NULL CHECK (category IN ('ice_cream', 'marshmallow')),
+    rate INTEGER NOT NULL CHECK (rate BETWEEN 0 AND 10000),
+    PRIMARY KEY (organization_id, category)
+);
+
+ALTER TABLE app_sweetshop.tax_rates ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY organization_isolation_policy ON app_sweetshop.tax_rates
+    USING (organization_id = current_setting('app_sweetshop.current_organization')::UUID);
+
+-- An order's price, stored when it is submitted: subtotal is the sum of its
+-- lines, discount the sum of its adjustments and total what the customer
+-- pays. Open orders are priced from the current promotions and tax profile
+-- instead, and orders cancelled while open never get a price.
+ALTER TABLE app_sweetshop.orders
+    ADD COLUMN subtotal app_sweetshop.money,
+    ADD COLUMN discount app_sweetshop.money,
+    ADD COLUMN tax app_sweetshop.money,
+    ADD COLUMN total app_sweetshop.money,
+    ADD COLUMN tax_pricing TEXT CHECK (tax_pricing IN ('exclusive', 'inclusive')),
+    ADD COLUMN tax_rounding TEXT CHECK (tax_rounding IN ('per_line', 'per_order'));
+
+-- Orders submitted before taxes were charged none.
+UPDATE app_sweetshop.orders r
+SET subtotal = ROW(COALESCE(i.amount, 0), r.currency)::app_sweetshop.money,
+    discount = ROW(COALESCE(a.amount, 0), r.currency)::app_sweetshop.money,
+    tax = ROW(0, r.currency)::app_sweetshop.money,
+    total = ROW(COALESCE(i.amount, 0) + COALESCE(a.amount, 0), r.currency)::app_sweetshop.money,
+    tax_pricing = 'exclusive',
+    tax_rounding = 'per_line'
+FROM app_sweetshop.orders o
+LEFT JOIN (
+    SELECT order_id, SUM((unit_price).amount * quantity)::BIGINT AS amount
+    FROM app_sweetshop.order_items
+    GROUP BY order_id
+) i ON i.order_id = o.id
+LEFT JOIN (
+    SELECT order_id, SUM((amount).amount)::BIGINT AS amount
+    FROM app_sweetshop.order_adjustments
+    GROUP BY order_id
+) a ON a.order_id = o.id
+WHERE o.id = r.id AND r.submitted_at IS NOT NULL;
+
+ALTER TABLE app_sweetshop.orders
+    ADD CONSTRAINT orders_price_check
+        CHECK ((subtotal IS NULL) = (total IS NULL)
+            AND (discount IS NULL) = (total IS NULL)
+            AND (tax IS NULL) = (total IS NULL)
+            AND (tax_pricing IS NULL) = (total IS NULL)
+            AND (tax_rounding IS NULL) = (total IS NULL)),
+    ADD CONSTRAINT orders_submitted_price_check
+        CHECK ((submitted_at IS NOT NULL) = (total IS NOT NULL));
+
+-- The tax on a submitted order, one row per rate its items were taxed at.
+-- net is the price of those items after adjustments and without tax.
+CREATE TABLE IF NOT EXISTS app_sweetshop.order_tax_lines (
+    order_id UUID NOT NULL REFERENCES app_sweetshop.orders(id) ON DELETE CASCADE,
+    organization_id UUID NOT NULL REFERENCES app_sweetshop.organizations(id),
+    rate INTEGER NOT NULL CHECK (rate BETWEEN 0 AND 10000),
+    net app_sweetshop.money NOT NULL CHECK ((net).amount >= 0),
+    tax app_sweetshop.money NOT NULL CHECK ((tax).amount >= 0),
+    PRIMARY KEY (order_id, rate)
+);
+
+ALTER TABLE app_sweetshop.order_tax_lines ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY organization_isolation_policy ON app_sweetshop.order_tax_lines
+    USING (organization_id = current_setting('app_sweetshop.current_organization')::UUID);
+
+-- +goose Down
+DROP TABLE IF EXISTS app_sweetshop.order_tax_lines;
+
+ALTER TABLE app_sweetshop.orders
+    DROP CONSTRAINT IF EXISTS orders_submitted_price_check,
+    DROP CONSTRAINT IF EXISTS orders_price_check,
+    DROP COLUMN IF EXISTS tax_rounding,
+    DROP COLUMN IF EXISTS tax_pricing,
+    DROP COLUMN IF EXISTS total,
+    DROP COLUMN IF EXISTS tax,
+    DROP COLUMN IF EXISTS discount,
+    DROP COLUMN IF EXISTS subtotal;
+
+DROP TABLE IF EXISTS app_sweetshop.tax_rates;
+DROP TABLE IF EXISTS app_sweetshop.tax_profiles;
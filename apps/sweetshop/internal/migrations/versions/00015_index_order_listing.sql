@@ -0,0 +1,17 @@
+-- +goose Up
+-- Order listings page through an organization's orders newest first by ID.
+CREATE INDEX IF NOT EXISTS orders_organization_id_id_idx
+    ON app_sweetshop.orders (organization_id, id);
+
+-- Items are read per order, and orders are filtered on the products they
+-- contain.
+CREATE INDEX IF NOT EXISTS order_items_order_id_idx
+    ON app_sweetshop.order_items (order_id);
+
+CREATE INDEX IF NOT EXISTS order_items_product_id_order_id_idx
+    ON app_sweetshop.order_items (product_id, order_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS app_sweetshop.order_items_product_id_order_id_idx;
+DROP INDEX IF EXISTS app_sweetshop.order_items_order_id_idx;
+DROP INDEX IF EXISTS app_sweetshop.orders_organization_id_id_idx;
@@ -0,0 +1,90 @@
+-- +goose Up
+-- Product categories, managed by each organization. Products, promotions and
+-- tax rates refer to a category by its slug, so renaming one carries over to
+-- them.
+CREATE TABLE IF NOT EXISTS app_sweetshop.categories (
+    id UUID PRIMARY KEY,
+    organization_id UUID NOT NULL REFERENCES app_sweetshop.organizations(id),
+    system_created_at TIMESTAMPTZ NOT NULL DEFAULT TRANSACTION_TIMESTAMP(),
+    system_updated_at TIMESTAMPTZ NOT NULL DEFAULT TRANSACTION_TIMESTAMP(),
+    slug TEXT NOT NULL CHECK (slug ~ '^[a-z0-9]+(_[a-z0-9]+)*$' AND length(slug) <= 64),
+    name TEXT NOT NULL,
+    sort_order INTEGER NOT NULL DEFAULT 0,
+    UNIQUE (organization_id, slug)
+);
+
+ALTER TABLE app_sweetshop.categories ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY organization_isolation_policy ON app_sweetshop.categories
+    USING (organization_id = current_setting('app_sweetshop.current_organization')::UUID);
+
+-- Every organization gets the two categories that used to be built in.
+INSERT INTO app_sweetshop.categories (id, organization_id, slug, name, sort_order)
+SELECT gen_random_uuid(), o.id, c.slug, c.name, c.sort_order
+FROM app_sweetshop.organizations o
+CROSS JOIN (VALUES ('ice_cream', 'Ice cream', 0), ('marshmallow', 'Marshmallow', 1)) AS c (slug, name, sort_order)
+ON CONFLICT (organization_id, slug) DO NOTHING;
+
+-- A category cannot be deleted while products, including soft-deleted ones,
+-- or promotions use it; its tax rate goes with it.
+ALTER TABLE app_sweetshop.products
+    DROP CONSTRAINT IF EXISTS products_category_check,
+    ADD CONSTRAINT products_category_fkey FOREIGN KEY (organization_id, category)
+        REFERENCES app_sweetshop.categories (organization_id, slug) ON UPDATE CASCADE;
+
+ALTER TABLE app_sweetshop.promotions
+    DROP CONSTRAINT IF EXISTS promotions_category_check,
+    ADD CONSTRAINT promotions_category_fkey FOREIGN KEY (organization_id, category)
+        REFERENCES app_sweetshop.categories (organization_id, slug) ON UPDATE CASCADE;
+
+ALTER TABLE app_sweetshop.tax_rates
+    DROP CONSTRAINT IF EXISTS tax_rates_category_check,
+    ADD CONSTRAINT tax_rates_category_fkey FOREIGN KEY (organization_id, category)
+        REFERENCES app_sweetshop.categories (organization_id, slug) ON UPDATE CASCADE ON DELETE CASCADE;
+
+CREATE INDEX IF NOT EXISTS products_organization_id_category_idx
+    ON app_sweetshop.products (organization_id, category);
+
+CREATE INDEX IF NOT EXISTS promotions_organization_id_category_idx
+    ON app_sweetshop.promotions (organization_id, category)
+    WHERE category IS NOT NULL;
+
+-- sku is optional and, like name, unique among an organization's live
+-- products. Inactive products stay listed but cannot be added to orders.
+-- Listings order products by sort_order, then name.
+ALTER TABLE app_sweetshop.products
+    ADD COLUMN sku TEXT CHECK (sku <> ''),
+    ADD COLUMN description TEXT NOT NULL DEFAULT '',
+    ADD COLUMN active BOOLEAN NOT NULL DEFAULT TRUE,
+    ADD COLUMN sort_order INTEGER NOT NULL DEFAULT 0;
+
+CREATE UNIQUE INDEX IF NOT EXISTS products_organization_id_sku_live_key
+    ON app_sweetshop.products (organization_id, sku)
+    WHERE deleted_at IS NULL AND sku IS NOT NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS app_sweetshop.products_organization_id_sku_live_key;
+
+ALTER TABLE app_sweetshop.products
+    DROP COLUMN IF EXISTS sort_order,
+    DROP COLUMN IF EXISTS active,
+    DROP COLUMN IF EXISTS description,
+    DROP COLUMN IF EXISTS sku;
+
+DROP INDEX IF EXISTS app_sweetshop.promotions_organization_id_category_idx;
+DROP INDEX IF EXISTS app_sweetshop.products_organization_id_category_idx;
+
+-- Rows in categories other than the two built-in ones block the rollback.
+ALTER TABLE app_sweetshop.tax_rates
+    DROP CONSTRAINT IF EXISTS tax_rates_category_fkey,
+    ADD CONSTRAINT tax_rates_category_check CHECK (category IN ('ice_cream', 'marshmallow'));
+
+ALTER TABLE app_sweetshop.promotions
+    DROP CONSTRAINT IF EXISTS promotions_category_fkey,
+    ADD CONSTRAINT promotions_category_check CHECK (category IN ('ice_cream', 'marshmallow'));
+
+ALTER TABLE app_sweetshop.products
+    DROP CONSTRAINT IF EXISTS products_category_fkey,
+    ADD CONSTRAINT products_category_check CHECK (category IN ('ice_cream', 'marshmallow'));
+
+DROP TABLE IF EXISTS app_sweetshop.categories;
@@ -0,0 +1,54 @@
+-- +goose Up
+-- Option groups of a product, such as sizes or toppings, chosen when it is
+-- ordered. A variant group takes exactly one of its options; a modifier group
+-- between min_selections and max_selections of them. A product's groups are
+-- replaced as a whole, keeping the IDs of the ones that stay.
+CREATE TABLE IF NOT EXISTS app_sweetshop.product_option_groups (
+    id UUID PRIMARY KEY,
+    organization_id UUID NOT NULL REFERENCES app_sweetshop.organizations(id),
+    product_id UUID NOT NULL REFERENCES app_sweetshop.products(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    kind TEXT NOT NULL CHECK (kind IN ('variant', 'modifier')),
+    min_selections INTEGER NOT NULL,
+    max_selections INTEGER NOT NULL,
+    sort_order INTEGER NOT NULL DEFAULT 0,
+    UNIQUE (product_id, name),
+    CONSTRAINT product_option_groups_selections_check CHECK (
+        min_selections >= 0 AND max_selections >= 1 AND min_selections <= max_selections
+        AND (kind <> 'variant' OR (min_selections = 1 AND max_selections = 1))
+    )
+);
+
+ALTER TABLE app_sweetshop.product_option_groups ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY organization_isolation_policy ON app_sweetshop.product_option_groups
+    USING (organization_id = current_setting('app_sweetshop.current_organization')::UUID);
+
+-- price_delta is added to the product's price when the option is chosen.
+CREATE TABLE IF NOT EXISTS app_sweetshop.product_options (
+    id UUID PRIMARY KEY,
+    organization_id UUID NOT NULL REFERENCES app_sweetshop.organizations(id),
+    group_id UUID NOT NULL REFERENCES app_sweetshop.product_option_groups(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    price_delta app_sweetshop.money NOT NULL,
+    sort_order INTEGER NOT NULL DEFAULT 0,
+    UNIQUE (group_id, name)
+);
+
+ALTER TABLE app_sweetshop.product_options ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY organization_isolation_policy ON app_sweetshop.product_options
+    USING (organization_id = current_setting('app_sweetshop.current_organization')::UUID);
+
+-- The options chosen for an item, as they were when it was added: an array of
+-- {option_id, group, name, price_delta}. unit_price already includes their
+-- price deltas.
+ALTER TABLE app_sweetshop.order_items
+    ADD COLUMN options JSONB NOT NULL DEFAULT '[]';
+
+-- +goose Down
+ALTER TABLE app_sweetshop.order_items
+    DROP COLUMN IF EXISTS options;
+
+DROP TABLE IF EXISTS app_sweetshop.product_options;
+DROP TABLE IF EXISTS app_sweetshop.product_option_groups;
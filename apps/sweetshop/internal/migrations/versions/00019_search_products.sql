@@ -0,0 +1,27 @@
+-- +goose Up
+-- Product search matches the words of a name, SKU or description by prefix,
+-- and falls back to trigram similarity on the name so misspellings still find
+-- a product. The 'simple' configuration neither stems nor drops stop words,
+-- since product names are rarely English prose.
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+ALTER TABLE app_sweetshop.products
+    ADD COLUMN search_vector TSVECTOR GENERATED ALWAYS AS (
+        setweight(to_tsvector('simple', name), 'A')
+        || setweight(to_tsvector('simple', COALESCE(sku, '')), 'A')
+        || setweight(to_tsvector('simple', description), 'B')
+    ) STORED;
+
+CREATE INDEX IF NOT EXISTS products_search_vector_idx
+    ON app_sweetshop.products USING GIN (search_vector);
+
+CREATE INDEX IF NOT EXISTS products_name_trgm_idx
+    ON app_sweetshop.products USING GIN (name gin_trgm_ops);
+
+-- +goose Down
+-- pg_trgm stays installed, as other schemas in the database may use it.
+DROP INDEX IF EXISTS app_sweetshop.products_name_trgm_idx;
+DROP INDEX IF EXISTS app_sweetshop.products_search_vector_idx;
+
+ALTER TABLE app_sweetshop.products
+    DROP COLUMN IF EXISTS search_vector;
@@ -0,0 +1,10 @@
+-- +goose Up
+-- An order is refunded in full at most once. RefundOrder serializes refunds
+-- on the order's row lock; the index keeps a second succeeded refund out
+-- should anything else record one.
+CREATE UNIQUE INDEX IF NOT EXISTS payments_order_id_refund_idx
+    ON app_sweetshop.payments (order_id)
+    WHERE operation = 'refund' AND status = 'succeeded';
+
+-- +goose Down
+DROP INDEX IF EXISTS app_sweetshop.payments_order_id_refund_idx;
@@ -0,0 +1,19 @@
+-- +goose Up
+-- The gateway calls of an order's transition or refund are made outside the
+-- order's row lock, so a slow provider holds neither a connection nor the
+-- lock. The order is claimed first, and other transitions, refunds and item
+-- changes are refused until the claim is released or claimed_until passes.
+CREATE TABLE IF NOT EXISTS app_sweetshop.order_payment_claims (
+    order_id UUID PRIMARY KEY REFERENCES app_sweetshop.orders(id),
+    organization_id UUID NOT NULL REFERENCES app_sweetshop.organizations(id),
+    token UUID NOT NULL,
+    claimed_until TIMESTAMPTZ NOT NULL
+);
+
+ALTER TABLE app_sweetshop.order_payment_claims ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY organization_isolation_policy ON app_sweetshop.order_payment_claims
+    USING (organization_id = current_setting('app_sweetshop.current_organization')::UUID);
+
+-- +goose Down
+DROP TABLE IF EXISTS app_sweetshop.order_payment_claims;
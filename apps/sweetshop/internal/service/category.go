@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+
+	coredomain "github.com/bbsbb/go-edge/core/domain"
+	"github.com/bbsbb/go-edge/sweetshop/internal/domain"
+)
+
+// CategoryService manages the categories an organization sorts its products
+// into.
+type CategoryService struct {
+	categories domain.CategoryRepository
+	logger     *slog.Logger
+}
+
+func NewCategoryService(categories domain.CategoryRepository, logger *slog.Logger) *CategoryService {
+	return &CategoryService{categories: categories, logger: logger}
+}
+
+func (s *CategoryService) Create(ctx context.Context, slug domain.ProductCategory, name string, sortOrder int32) (*domain.Category, error) {
+	org, err := coredomain.OrganizationFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	category := &domain.Category{
+		ID:             uuid.Must(uuid.NewV7()),
+		OrganizationID: org.ID,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+		Slug:           slug,
+		Name:           name,
+		SortOrder:      sortOrder,
+	}
+	if err := category.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.categories.Create(ctx, category); err != nil {
+		s.logger.Error("failed to create category", "error", err, "slug", slug)
+		return nil, err
+	}
+
+	s.logger.Info("category created", "category_id", category.ID, "slug", slug)
+	return category, nil
+}
+
+func (s *CategoryService) Get(ctx context.Context, id uuid.UUID) (*domain.Category, error) {
+	category, err := s.categories.FindByID(ctx, id)
+	if err != nil {
+		s.logger.Debug("category lookup failed", "error", err, "category_id", id)
+		return nil, err
+	}
+	return category, nil
+}
+
+func (s *CategoryService) List(ctx context.Context) ([]*domain.Category, error) {
+	categories, err := s.categories.List(ctx)
+	if err != nil {
+		s.logger.Error("failed to list categories", "error", err)
+		return nil, err
+	}
+	return categories, nil
+}
+
+// Update renames the category and moves it in the sort order. A new slug
+// carries over to the category's products, promotions and tax rate.
+func (s *CategoryService) Update(ctx context.Context, id uuid.UUID, slug domain.ProductCategory, name string, sortOrder int32) (*domain.Category, error) {
+	category, err := s.categories.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	category.Slug = slug
+	category.Name = name
+	category.SortOrder = sortOrder
+	category.UpdatedAt = time.Now()
+	if err := category.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.categories.Update(ctx, category); err != nil {
+		s.logger.Error("failed to update category", "error", err, "category_id", id)
+		return nil, err
+	}
+
+	s.logger.Info("category updated", "category_id", id, "slug", slug)
+	return category, nil
+}
+
+// Delete removes a category no product or promotion uses, together with its
+// tax rate.
+func (s *CategoryService) Delete(ctx context.Context, id uuid.UUID) error {
+	if err := s.categories.Delete(ctx, id); err != nil {
+		s.logger.Warn("failed to delete category", "error", err, "category_id", id)
+		return err
+	}
+	s.logger.Info("category deleted", "category_id", id)
+	return nil
+}
+
+// checkCategory returns a validation error if the organization has no
+// category with the slug.
+func checkCategory(ctx context.Context, categories domain.CategoryRepository, slug domain.ProductCategory) error {
+	_, err := categories.FindBySlug(ctx, slug)
+	if errors.Is(err, coredomain.ErrNotFound) {
+		return coredomain.NewError(coredomain.CodeValidation, fmt.Sprintf("category %q does not exist", slug))
+	}
+	return err
+}
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+
+	coredomain "github.com/bbsbb/go-edge/core/domain"
+	"github.com/bbsbb/go-edge/sweetshop/internal/domain"
+)
+
+const (
+	DefaultStockAdjustmentLimit = 50
+	MaxStockAdjustmentLimit     = 200
+)
+
+// InventoryService reads and adjusts product stock. Reservations are made
+// and settled by OrderService.
+type InventoryService struct {
+	inventory domain.InventoryRepository
+	products  domain.ProductRepository
+	logger    *slog.Logger
+}
+
+func NewInventoryService(inventory domain.InventoryRepository, products domain.ProductRepository, logger *slog.Logger) *InventoryService {
+	return &InventoryService{inventory: inventory, products: products, logger: logger}
+}
+
+// GetInventory returns the product's stock. A product whose stock is not
+// tracked yet has an untracked, empty inventory.
+func (s *InventoryService) GetInventory(ctx context.Context, productID uuid.UUID) (*domain.Inventory, error) {
+	product, err := s.products.FindByID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	inventory, err := s.inventory.FindByProductID(ctx, productID)
+	if errors.Is(err, coredomain.ErrNotFound) {
+		return &domain.Inventory{ProductID: product.ID, OrganizationID: product.OrganizationID}, nil
+	}
+	if err != nil {
+		s.logger.Error("failed to get inventory", "error", err, "product_id", productID)
+		return nil, err
+	}
+	return inventory, nil
+}
+
+// AdjustStock changes the product's on-hand stock by delta, recording why.
+// The first adjustment of a product starts tracking its stock.
+func (s *InventoryService) AdjustStock(
+	ctx context.Context,
+	productID uuid.UUID,
+	delta int32,
+	reason domain.StockAdjustmentReason,
+	note *string,
+) (*domain.Inventory, error) {
+	if delta == 0 {
+		return nil, coredomain.NewError(coredomain.CodeValidation, "quantity_delta must not be zero")
+	}
+	if !reason.IsValid() {
+		return nil, coredomain.NewError(coredomain.CodeValidation, "unknown adjustment reason")
+	}
+
+	product, err := s.products.FindByID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	adjustment := &domain.StockAdjustment{
+		ID:             uuid.Must(uuid.NewV7()),
+		OrganizationID: product.OrganizationID,
+		ProductID:      productID,
+		CreatedAt:      time.Now(),
+		QuantityDelta:  delta,
+		Reason:         reason,
+		Note:           note,
+	}
+	inventory, err := s.inventory.Adjust(ctx, adjustment)
+	if err != nil {
+		if errors.Is(err, coredomain.ErrInvariant) {
+			s.logger.Warn("stock adjustment rejected", "error", err, "product_id", productID, "quantity_delta", delta)
+			return nil, err
+		}
+		s.logger.Error("failed to adjust stock", "error", err, "product_id", productID)
+		return nil, err
+	}
+
+	s.logger.Info("stock adjusted", "product_id", productID, "quantity_delta", delta,
+		"reason", reason, "on_hand", inventory.OnHand, "reserved", inventory.Reserved)
+	return inventory, nil
+}
+
+// ListAdjustments returns the product's adjustment log, newest first.
+func (s *InventoryService) ListAdjustments(ctx context.Context, productID uuid.UUID, limit int32) ([]domain.StockAdjustment, error) {
+	if limit <= 0 || limit > MaxStockAdjustmentLimit {
+		return nil, coredomain.NewError(coredomain.CodeValidation, "limit must be between 1 and 200")
+	}
+	if _, err := s.products.FindByID(ctx, productID); err != nil {
+		return nil, err
+	}
+
+	adjustments, err := s.inventory.ListAdjustments(ctx, productID, limit)
+	if err != nil {
+		s.logger.Error("failed to list stock adjustments", "error", err, "product_id", productID)
+		return nil, err
+	}
+	return adjustments, nil
+}
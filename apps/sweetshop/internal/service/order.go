@@ -2,30 +2,121 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log/slog"
+	"slices"
 	"time"
 
 	"github.com/google/uuid"
 
+	"github.com/bbsbb/go-edge/core/datatype"
 	coredomain "github.com/bbsbb/go-edge/core/domain"
+	"github.com/bbsbb/go-edge/sweetshop/internal/config"
 	"github.com/bbsbb/go-edge/sweetshop/internal/domain"
 )
 
 type OrderService struct {
-	orders   domain.OrderRepository
-	products domain.ProductRepository
-	logger   *slog.Logger
+	orgs        domain.OrganizationRepository
+	orders      domain.OrderRepository
+	products    domain.ProductRepository
+	options     domain.ProductOptionRepository
+	payments    domain.PaymentRepository
+	promotions  domain.PromotionRepository
+	taxes       domain.TaxProfileRepository
+	gateway     domain.PaymentGateway
+	broadcaster domain.OrderBroadcaster
+	// callTimeout bounds each gateway call, and lease how long an order
+	// stays claimed for its gateway calls if the claim is not released.
+	callTimeout time.Duration
+	lease       time.Duration
+	logger      *slog.Logger
 }
 
-func NewOrderService(orders domain.OrderRepository, products domain.ProductRepository, logger *slog.Logger) *OrderService {
-	return &OrderService{orders: orders, products: products, logger: logger}
+func NewOrderService(
+	orgs domain.OrganizationRepository,
+	orders domain.OrderRepository,
+	products domain.ProductRepository,
+	options domain.ProductOptionRepository,
+	payments domain.PaymentRepository,
+	promotions domain.PromotionRepository,
+	taxes domain.TaxProfileRepository,
+	gateway domain.PaymentGateway,
+	broadcaster domain.OrderBroadcaster,
+	cfg *config.AppConfiguration,
+	logger *slog.Logger,
+) *OrderService {
+	pc := cfg.PaymentConfiguration()
+	return &OrderService{
+		orgs:        orgs,
+		orders:      orders,
+		products:    products,
+		options:     options,
+		payments:    payments,
+		promotions:  promotions,
+		taxes:       taxes,
+		gateway:     gateway,
+		broadcaster: broadcaster,
+		callTimeout: pc.CallTimeoutOrDefault(),
+		lease:       pc.LeaseOrDefault(),
+		logger:      logger,
+	}
 }
 
+// broadcast pushes order to live clients. The change is already committed,
+// so a failed broadcast is logged rather than returned.
+func (s *OrderService) broadcast(ctx context.Context, order *domain.Order) {
+	if err := s.broadcaster.BroadcastOrder(ctx, order); err != nil {
+		s.logger.Warn("failed to broadcast order", "error", err, "order_id", order.ID)
+	}
+}
+
+// load finds the order and prices it; see price.
+func (s *OrderService) load(ctx context.Context, id uuid.UUID) (*domain.Order, error) {
+	order, err := s.orders.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.price(ctx, order); err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+// price applies the promotions active now and the organization's tax
+// profile to an open order. Orders past open keep the adjustments and tax
+// stored when they were submitted.
+func (s *OrderService) price(ctx context.Context, order *domain.Order) error {
+	if order.Status != domain.OrderStatusOpen {
+		return nil
+	}
+	now := time.Now()
+	promotions, err := s.promotions.ListActive(ctx, now)
+	if err != nil {
+		s.logger.Error("failed to list active promotions", "error", err, "order_id", order.ID)
+		return err
+	}
+	if err := order.ApplyPromotions(promotions, now); err != nil {
+		return err
+	}
+	profile, err := organizationTaxProfile(ctx, s.taxes)
+	if err != nil {
+		s.logger.Error("failed to get tax profile", "error", err, "order_id", order.ID)
+		return err
+	}
+	return order.ApplyTax(profile)
+}
+
+// OpenOrder opens an order in the organization's current currency.
 func (s *OrderService) OpenOrder(ctx context.Context) (*domain.Order, error) {
 	org, err := coredomain.OrganizationFromContext(ctx)
 	if err != nil {
 		return nil, err
 	}
+	currency, err := organizationCurrency(ctx, s.orgs)
+	if err != nil {
+		return nil, err
+	}
 
 	now := time.Now()
 	order := &domain.Order{
@@ -34,6 +125,10 @@ func (s *OrderService) OpenOrder(ctx context.Context) (*domain.Order, error) {
 		CreatedAt:      now,
 		UpdatedAt:      now,
 		Status:         domain.OrderStatusOpen,
+		Currency:       currency,
+	}
+	if err := s.price(ctx, order); err != nil {
+		return nil, err
 	}
 
 	if err := s.orders.Create(ctx, order); err != nil {
@@ -42,11 +137,12 @@ func (s *OrderService) OpenOrder(ctx context.Context) (*domain.Order, error) {
 	}
 
 	s.logger.Info("order opened", "order_id", order.ID)
+	s.broadcast(ctx, order)
 	return order, nil
 }
 
 func (s *OrderService) GetOrder(ctx context.Context, id uuid.UUID) (*domain.Order, error) {
-	order, err := s.orders.FindByID(ctx, id)
+	order, err := s.load(ctx, id)
 	if err != nil {
 		s.logger.Debug("order lookup failed", "error", err, "order_id", id)
 		return nil, err
@@ -54,63 +150,309 @@ func (s *OrderService) GetOrder(ctx context.Context, id uuid.UUID) (*domain.Orde
 	return order, nil
 }
 
-func (s *OrderService) AddItem(ctx context.Context, orderID uuid.UUID, productID uuid.UUID, quantity int32) (*domain.OrderItem, error) {
-	if quantity <= 0 {
-		return nil, coredomain.NewError(coredomain.CodeValidation, "quantity must be positive")
-	}
+// SubmitOrder authorizes payment for the order's total and submits it,
+// fixing the discounts its promotions give, its tax and its totals. An order whose authorization is
+// declined stays open; a free order needs no authorization.
+func (s *OrderService) SubmitOrder(ctx context.Context, id uuid.UUID) (*domain.Order, error) {
+	return s.transition(ctx, id, domain.OrderActionSubmit, func(ctx context.Context, order *domain.Order) error {
+		total, err := order.Total()
+		if err != nil {
+			return err
+		}
+		switch order.PaymentStatus() {
+		case domain.PaymentStatusAuthorized:
+			auth := order.Authorization()
+			if cmp, err := auth.Amount.Cmp(total); err != nil || cmp == 0 {
+				return err
+			}
+			// The total changed since an earlier attempt authorized it, for
+			// instance because a promotion ran out, so the authorization is
+			// replaced.
+			if _, err := s.pay(ctx, order, domain.PaymentOperationVoid, auth.Amount, auth.Reference); err != nil {
+				return err
+			}
+		case domain.PaymentStatusUnpaid, domain.PaymentStatusVoided:
+		default:
+			return nil
+		}
+		if total.IsZero() {
+			return nil
+		}
+		_, err = s.pay(ctx, order, domain.PaymentOperationAuthorize, total, "")
+		return err
+	})
+}
+
+// PayOrder captures the submitted order's authorization. An order whose
+// capture is declined stays submitted and can be paid again.
+func (s *OrderService) PayOrder(ctx context.Context, id uuid.UUID) (*domain.Order, error) {
+	return s.transition(ctx, id, domain.OrderActionPay, func(ctx context.Context, order *domain.Order) error {
+		if order.PaymentStatus() != domain.PaymentStatusAuthorized {
+			return nil
+		}
+		auth := order.Authorization()
+		_, err := s.pay(ctx, order, domain.PaymentOperationCapture, auth.Amount, auth.Reference)
+		return err
+	})
+}
+
+// FulfilOrder fulfils a paid order, taking the stock reserved by its items
+// off the shelf.
+func (s *OrderService) FulfilOrder(ctx context.Context, id uuid.UUID) (*domain.Order, error) {
+	return s.transition(ctx, id, domain.OrderActionFulfil, nil)
+}
+
+// CancelOrder cancels an order that has not been fulfilled, voiding its
+// authorization or refunding its capture first. The stock reserved by its
+// items becomes available again.
+func (s *OrderService) CancelOrder(ctx context.Context, id uuid.UUID) (*domain.Order, error) {
+	return s.transition(ctx, id, domain.OrderActionCancel, func(ctx context.Context, order *domain.Order) error {
+		auth := order.Authorization()
+		switch order.PaymentStatus() {
+		case domain.PaymentStatusAuthorized:
+			_, err := s.pay(ctx, order, domain.PaymentOperationVoid, auth.Amount, auth.Reference)
+			return err
+		case domain.PaymentStatusCaptured:
+			captured, err := order.Captured()
+			if err != nil {
+				return err
+			}
+			_, err = s.pay(ctx, order, domain.PaymentOperationRefund, captured, auth.Reference)
+			return err
+		}
+		return nil
+	})
+}
+
+// RefundOrder refunds everything captured for a fulfilled order. The order
+// stays fulfilled. It is claimed for the refund, which is made outside its
+// row lock, so of two concurrent refunds the later one is refused, or finds
+// the order already refunded.
+func (s *OrderService) RefundOrder(ctx context.Context, id uuid.UUID) (*domain.Order, error) {
+	token := uuid.Must(uuid.NewV7())
+	var (
+		order    *domain.Order
+		captured datatype.Money
+	)
+	err := s.orders.Lock(ctx, id, func(ctx context.Context) error {
+		var err error
+		if order, err = s.orders.FindByID(ctx, id); err != nil {
+			return err
+		}
 
-	order, err := s.orders.FindByID(ctx, orderID)
+		if err := order.CanRefund(); err != nil {
+			s.logger.Warn("attempted to refund order", "error", err, "order_id", id)
+			return err
+		}
+
+		if captured, err = order.Captured(); err != nil {
+			return err
+		}
+		return s.claim(ctx, order, token)
+	})
 	if err != nil {
 		return nil, err
 	}
+	defer s.release(ctx, id, token)
 
-	if err := order.CanAddItem(); err != nil {
-		s.logger.Warn("attempted to add item to closed order", "order_id", orderID)
+	auth := order.Authorization()
+	if _, err := s.pay(ctx, order, domain.PaymentOperationRefund, captured, auth.Reference); err != nil {
 		return nil, err
 	}
 
-	product, err := s.products.FindByID(ctx, productID)
+	s.logger.Info("order refunded", "order_id", id, "amount", captured)
+	s.broadcast(ctx, order)
+	return order, nil
+}
+
+func (s *OrderService) ListStatusHistory(ctx context.Context, id uuid.UUID) ([]domain.OrderStatusChange, error) {
+	if _, err := s.orders.FindByID(ctx, id); err != nil {
+		return nil, err
+	}
+	history, err := s.orders.ListStatusHistory(ctx, id)
 	if err != nil {
+		s.logger.Error("failed to list order status history", "error", err, "order_id", id)
 		return nil, err
 	}
+	return history, nil
+}
 
-	item := &domain.OrderItem{
-		ID:             uuid.Must(uuid.NewV7()),
-		OrganizationID: order.OrganizationID,
-		OrderID:        orderID,
-		ProductID:      productID,
-		CreatedAt:      time.Now(),
-		Quantity:       quantity,
-		PriceCents:     product.PriceCents,
-	}
+// transition applies action to the order without holding a transaction or
+// row lock while the payment gateway is called:
+//
+//   - under the order's row lock, it checks that the action is allowed and
+//     claims the order (see domain.PaymentRepository.Claim);
+//   - without a lock, settle makes the payment calls the action needs; a
+//     payment that is already settled from an earlier, interrupted attempt is
+//     not repeated;
+//   - under the lock again, it checks that the claim is still held and stores
+//     the transition, which the repository only applies if the stored status
+//     is still the one the action was allowed from, and releases the claim.
+//
+// While the order is claimed, other transitions, refunds and item changes are
+// refused, so concurrent transitions of one order run one at a time. Payment
+// attempts are kept even when the transition then fails, and an authorization
+// made for a transition that could not be stored is voided before the claim
+// is released, unless the claim's lease ran out during the gateway calls.
+func (s *OrderService) transition(
+	ctx context.Context,
+	id uuid.UUID,
+	action domain.OrderAction,
+	settle func(context.Context, *domain.Order) error,
+) (*domain.Order, error) {
+	token := uuid.Must(uuid.NewV7())
+	var order *domain.Order
+	err := s.orders.Lock(ctx, id, func(ctx context.Context) error {
+		var err error
+		if order, err = s.load(ctx, id); err != nil {
+			return err
+		}
 
-	if err := s.orders.CreateItem(ctx, item); err != nil {
-		s.logger.Error("failed to add order item", "error", err, "order_id", orderID, "product_id", productID)
+		if err := order.CanTransition(action); err != nil {
+			s.logger.Warn("order transition rejected", "error", err, "order_id", id, "action", action)
+			return err
+		}
+		return s.claim(ctx, order, token)
+	})
+	if err != nil {
 		return nil, err
 	}
 
-	s.logger.Info("item added to order",
-		"order_id", orderID, "product_id", productID,
-		"quantity", quantity, "price_cents", product.PriceCents)
-	return item, nil
+	var (
+		change  domain.OrderStatusChange
+		failure error
+		lost    bool
+	)
+	settled := len(order.Payments)
+	if settle != nil {
+		failure = settle(ctx, order)
+	}
+	if failure == nil {
+		err := s.orders.Lock(ctx, id, func(ctx context.Context) error {
+			if err := s.claim(ctx, order, token); err != nil {
+				lost = true
+				return err
+			}
+			change, failure = order.Transition(action, time.Now())
+			if failure != nil {
+				s.logger.Warn("order transition rejected", "error", failure, "order_id", id, "action", action)
+				return nil
+			}
+			if failure = s.orders.Transition(ctx, order, change); failure != nil {
+				s.logger.Error("failed to transition order", "error", failure, "order_id", id, "action", action)
+				return nil
+			}
+			return s.payments.Release(ctx, id, token)
+		})
+		if failure == nil {
+			failure = err
+		}
+	}
+	if failure != nil {
+		// Once the lease ran out, a later attempt may already be relying on
+		// the authorizations made here, so they are only voided while the
+		// claim is still held.
+		if !lost {
+			s.voidAuthorizations(ctx, order, slices.Clone(order.Payments[settled:]))
+		}
+		s.release(ctx, id, token)
+		return nil, failure
+	}
+
+	s.logger.Info("order transitioned", "order_id", id, "action", action, "from", change.From, "to", change.To)
+	s.broadcast(ctx, order)
+	return order, nil
 }
 
-func (s *OrderService) CloseOrder(ctx context.Context, id uuid.UUID) (*domain.Order, error) {
-	order, err := s.orders.FindByID(ctx, id)
+// claim claims the order for the payment step token identifies, for the
+// configured lease.
+func (s *OrderService) claim(ctx context.Context, order *domain.Order, token uuid.UUID) error {
+	now := time.Now()
+	claimed, err := s.payments.Claim(ctx, order, token, now, now.Add(s.lease))
 	if err != nil {
-		return nil, err
+		s.logger.Error("failed to claim order for payment", "error", err, "order_id", order.ID)
+		return err
+	}
+	if !claimed {
+		return domain.NewPaymentInProgressError()
 	}
+	return nil
+}
 
-	if err := order.CanClose(); err != nil {
-		s.logger.Warn("attempted to close already-closed order", "order_id", id)
-		return nil, err
+// release ends token's claim on the order, even once the request is
+// cancelled. A claim that is not released runs out with its lease, so a
+// failure is only logged.
+func (s *OrderService) release(ctx context.Context, id, token uuid.UUID) {
+	if err := s.payments.Release(context.WithoutCancel(ctx), id, token); err != nil {
+		s.logger.Warn("failed to release order payment claim", "error", err, "order_id", id)
+	}
+}
+
+// voidAuthorizations releases the successful authorizations among payments.
+// A void that fails is recorded and logged by pay.
+func (s *OrderService) voidAuthorizations(ctx context.Context, order *domain.Order, payments []domain.Payment) {
+	for _, p := range payments {
+		if p.Succeeded() && p.Operation == domain.PaymentOperationAuthorize {
+			_, _ = s.pay(ctx, order, domain.PaymentOperationVoid, p.Amount, p.Reference)
+		}
+	}
+}
+
+// pay makes one gateway call for the order, bounded by the configured call
+// timeout, and records it, whatever its outcome. A declined call is returned
+// as an invariant violation.
+func (s *OrderService) pay(
+	ctx context.Context,
+	order *domain.Order,
+	op domain.PaymentOperation,
+	amount datatype.Money,
+	reference string,
+) (*domain.Payment, error) {
+	payment := &domain.Payment{
+		ID:             uuid.Must(uuid.NewV7()),
+		OrganizationID: order.OrganizationID,
+		OrderID:        order.ID,
+		CreatedAt:      time.Now(),
+		Provider:       s.gateway.Provider(),
+		Operation:      op,
+		Amount:         amount,
+		Reference:      reference,
+	}
+
+	callCtx, cancel := context.WithTimeout(ctx, s.callTimeout)
+	defer cancel()
+	var err error
+	switch op {
+	case domain.PaymentOperationAuthorize:
+		payment.Reference, err = s.gateway.Authorize(callCtx, order.ID, amount)
+	case domain.PaymentOperationCapture:
+		err = s.gateway.Capture(callCtx, reference, amount)
+	case domain.PaymentOperationVoid:
+		err = s.gateway.Void(callCtx, reference)
+	case domain.PaymentOperationRefund:
+		err = s.gateway.Refund(callCtx, reference, amount)
+	default:
+		return nil, fmt.Errorf("unknown payment operation %q", op)
+	}
+	payment.RecordOutcome(err)
+
+	if recordErr := s.payments.Create(ctx, payment); recordErr != nil {
+		s.logger.Error("failed to record payment", "error", recordErr, "order_id", order.ID,
+			"operation", op, "status", payment.Status, "reference", payment.Reference)
+		return nil, recordErr
 	}
+	order.Payments = append(order.Payments, *payment)
 
-	if _, err := s.orders.Close(ctx, id, time.Now()); err != nil {
-		s.logger.Error("failed to close order", "error", err, "order_id", id)
+	var declined *domain.PaymentDeclinedError
+	switch {
+	case errors.As(err, &declined):
+		s.logger.Warn("payment declined", "order_id", order.ID, "operation", op, "reason", declined.Reason)
+		return nil, coredomain.WrapError(coredomain.CodeInvariant, declined.Error(), err)
+	case err != nil:
+		s.logger.Error("payment gateway call failed", "error", err, "order_id", order.ID, "operation", op)
 		return nil, err
 	}
 
-	s.logger.Info("order closed", "order_id", id)
-	return s.orders.FindByID(ctx, id)
+	s.logger.Info("payment succeeded", "order_id", order.ID, "operation", op, "amount", amount)
+	return payment, nil
 }
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/google/uuid"
+
+	"github.com/bbsbb/go-edge/sweetshop/internal/domain"
+)
+
+const (
+	// orderEventReplayPage is how many missed events are read at a time when
+	// a subscriber resumes.
+	orderEventReplayPage = 500
+	// orderEventReplayLimit is the most missed events replayed to a resuming
+	// subscriber. One that missed more refetches its orders instead, rather
+	// than have a stale or made-up resume point replay the whole history.
+	orderEventReplayLimit = 2000
+)
+
+type OrderEventService struct {
+	events domain.OrderEventRepository
+	bus    domain.OrderEventBus
+	logger *slog.Logger
+}
+
+func NewOrderEventService(events domain.OrderEventRepository, bus domain.OrderEventBus, logger *slog.Logger) *OrderEventService {
+	return &OrderEventService{events: events, bus: bus, logger: logger}
+}
+
+// Subscribe opens a live feed of the caller's order events. A resuming
+// subscriber opens it before calling Replay, so an event recorded in between
+// may arrive through both; consumers skip live events that were replayed.
+func (s *OrderEventService) Subscribe(ctx context.Context) (*domain.OrderEventSubscription, error) {
+	events, cancel, err := s.bus.Subscribe(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.OrderEventSubscription{Events: events, Close: cancel}, nil
+}
+
+// Replay calls send with each of the caller's events recorded after
+// lastEventID, oldest first, as they are read a page at a time. It reports
+// false if it stopped at orderEventReplayLimit with events left over.
+func (s *OrderEventService) Replay(ctx context.Context, lastEventID uuid.UUID, send func(domain.OrderEvent) error) (bool, error) {
+	for after, sent := lastEventID, 0; ; {
+		page, err := s.events.ListAfter(ctx, after, orderEventReplayPage)
+		if err != nil {
+			s.logger.Error("failed to replay order events", "error", err, "last_event_id", lastEventID)
+			return false, err
+		}
+		for _, e := range page {
+			if sent == orderEventReplayLimit {
+				return false, nil
+			}
+			if err := send(e); err != nil {
+				return false, err
+			}
+			sent++
+		}
+		if len(page) < orderEventReplayPage {
+			return true, nil
+		}
+		after = page[len(page)-1].ID
+	}
+}
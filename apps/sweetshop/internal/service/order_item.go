@@ -0,0 +1,192 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+
+	coredomain "github.com/bbsbb/go-edge/core/domain"
+	"github.com/bbsbb/go-edge/sweetshop/internal/domain"
+)
+
+// AddItem adds quantity units of the product with the options optionIDs to
+// the order, at the product's price plus the options' price deltas. When the
+// order already has a line of the product with the same options at that
+// price, the units are added to that line instead of a new one, also when a
+// concurrent add created it after the order was read.
+func (s *OrderService) AddItem(ctx context.Context, orderID, productID uuid.UUID, quantity int32, optionIDs []uuid.UUID) (*domain.OrderItem, error) {
+	if quantity <= 0 {
+		return nil, coredomain.NewError(coredomain.CodeValidation, "quantity must be positive")
+	}
+
+	order, err := s.orders.FindByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	product, err := s.products.FindByID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	if !product.Active {
+		return nil, coredomain.NewError(coredomain.CodeInvariant, "product is archived")
+	}
+
+	groups, err := s.options.ListByProductID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	options, err := domain.SelectOptions(groups, optionIDs)
+	if err != nil {
+		return nil, err
+	}
+	unitPrice, err := product.PriceWith(options)
+	if err != nil {
+		return nil, err
+	}
+
+	item := &domain.OrderItem{
+		ID:              uuid.Must(uuid.NewV7()),
+		OrganizationID:  order.OrganizationID,
+		OrderID:         orderID,
+		ProductID:       productID,
+		ProductName:     product.Name,
+		ProductCategory: product.Category,
+		CreatedAt:       time.Now(),
+		Quantity:        quantity,
+		UnitPrice:       unitPrice,
+		Options:         options,
+	}
+
+	if line := order.LineFor(item); line != nil {
+		return s.addToLine(ctx, order, line, quantity)
+	}
+
+	if err := order.CanAddItem(item); err != nil {
+		s.logger.Warn("attempted to add item to order", "error", err, "order_id", orderID, "product_id", productID)
+		return nil, err
+	}
+
+	err = s.orders.CreateItem(ctx, item)
+	if errors.Is(err, coredomain.ErrConflict) {
+		// A concurrent add created the line first; the units are added to it
+		// instead, once.
+		reloaded, findErr := s.orders.FindByID(ctx, orderID)
+		if findErr != nil {
+			return nil, findErr
+		}
+		if line := reloaded.LineFor(item); line != nil {
+			s.logger.Info("order item merged into a concurrently added line", "order_id", orderID, "item_id", line.ID)
+			return s.addToLine(ctx, reloaded, line, quantity)
+		}
+	}
+	if err != nil {
+		if errors.Is(err, coredomain.ErrInvariant) || errors.Is(err, coredomain.ErrConflict) {
+			s.logger.Warn("order item rejected", "error", err, "order_id", orderID, "product_id", productID, "quantity", quantity)
+			return nil, err
+		}
+		s.logger.Error("failed to add order item", "error", err, "order_id", orderID, "product_id", productID)
+		return nil, err
+	}
+
+	s.logger.Info("item added to order",
+		"order_id", orderID, "product_id", productID,
+		"quantity", quantity, "unit_price", item.UnitPrice, "reserved_quantity", item.ReservedQuantity)
+
+	order.Items = append(order.Items, *item)
+	s.broadcastItems(ctx, order)
+	return item, nil
+}
+
+// UpdateItemQuantity changes one of an open order's items to quantity units,
+// reserving or releasing the difference in tracked stock.
+func (s *OrderService) UpdateItemQuantity(ctx context.Context, orderID, itemID uuid.UUID, quantity int32) (*domain.OrderItem, error) {
+	order, err := s.orders.FindByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	item, err := order.Item(itemID)
+	if err != nil {
+		return nil, err
+	}
+	return s.setItemQuantity(ctx, order, item, quantity)
+}
+
+// addToLine adds quantity units to line, an existing line of the order.
+func (s *OrderService) addToLine(ctx context.Context, order *domain.Order, line *domain.OrderItem, quantity int32) (*domain.OrderItem, error) {
+	if quantity > math.MaxInt32-line.Quantity {
+		return nil, coredomain.NewError(coredomain.CodeInvariant, "item quantity is out of range")
+	}
+	return s.setItemQuantity(ctx, order, line, line.Quantity+quantity)
+}
+
+func (s *OrderService) setItemQuantity(ctx context.Context, order *domain.Order, item *domain.OrderItem, quantity int32) (*domain.OrderItem, error) {
+	if err := order.CanSetQuantity(item, quantity); err != nil {
+		s.logger.Warn("attempted to change order item", "error", err, "order_id", order.ID, "item_id", item.ID)
+		return nil, err
+	}
+
+	if err := s.orders.UpdateItemQuantity(ctx, item, quantity, time.Now()); err != nil {
+		if errors.Is(err, coredomain.ErrInvariant) || errors.Is(err, coredomain.ErrConflict) {
+			s.logger.Warn("order item change rejected", "error", err, "order_id", order.ID, "item_id", item.ID, "quantity", quantity)
+			return nil, err
+		}
+		s.logger.Error("failed to change order item", "error", err, "order_id", order.ID, "item_id", item.ID)
+		return nil, err
+	}
+
+	s.logger.Info("order item changed",
+		"order_id", order.ID, "item_id", item.ID,
+		"quantity", quantity, "reserved_quantity", item.ReservedQuantity)
+
+	changed := *item
+	s.broadcastItems(ctx, order)
+	return &changed, nil
+}
+
+// RemoveItem removes one of an open order's items, releasing the stock it
+// holds.
+func (s *OrderService) RemoveItem(ctx context.Context, orderID, itemID uuid.UUID) error {
+	order, err := s.orders.FindByID(ctx, orderID)
+	if err != nil {
+		return err
+	}
+	item, err := order.Item(itemID)
+	if err != nil {
+		return err
+	}
+	if err := order.CanChangeItems(); err != nil {
+		s.logger.Warn("attempted to remove order item", "error", err, "order_id", orderID, "item_id", itemID)
+		return err
+	}
+
+	if err := s.orders.DeleteItem(ctx, item, time.Now()); err != nil {
+		if errors.Is(err, coredomain.ErrInvariant) || errors.Is(err, coredomain.ErrNotFound) {
+			s.logger.Warn("order item removal rejected", "error", err, "order_id", orderID, "item_id", itemID)
+			return err
+		}
+		s.logger.Error("failed to remove order item", "error", err, "order_id", orderID, "item_id", itemID)
+		return err
+	}
+
+	s.logger.Info("item removed from order", "order_id", orderID, "item_id", itemID, "product_id", item.ProductID)
+
+	order.Items = slices.DeleteFunc(order.Items, func(i domain.OrderItem) bool { return i.ID == itemID })
+	s.broadcastItems(ctx, order)
+	return nil
+}
+
+// broadcastItems reprices an order whose items changed and broadcasts it.
+// The change is already committed, so an order that cannot be priced is
+// logged and not broadcast.
+func (s *OrderService) broadcastItems(ctx context.Context, order *domain.Order) {
+	if err := s.price(ctx, order); err != nil {
+		s.logger.Warn("failed to price order for broadcast", "error", err, "order_id", order.ID)
+		return
+	}
+	s.broadcast(ctx, order)
+}
@@ -0,0 +1,41 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	coredomain "github.com/bbsbb/go-edge/core/domain"
+	"github.com/bbsbb/go-edge/sweetshop/internal/domain"
+)
+
+const (
+	DefaultOrderListLimit = 50
+	MaxOrderListLimit     = 200
+)
+
+// ListOrders returns a page of the organization's orders matching filter,
+// newest first, continuing after the order with ID after when it is set.
+// Total bounds are taken to be in the organization's current currency.
+func (s *OrderService) ListOrders(ctx context.Context, filter domain.OrderFilter, after *uuid.UUID, limit int32) (*domain.OrderPage, error) {
+	if limit <= 0 || limit > MaxOrderListLimit {
+		return nil, coredomain.NewError(coredomain.CodeValidation, "limit must be between 1 and 200")
+	}
+	if filter.HasTotal() {
+		currency, err := organizationCurrency(ctx, s.orgs)
+		if err != nil {
+			return nil, err
+		}
+		filter.TotalCurrency = currency
+	}
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+
+	page, err := s.orders.List(ctx, filter, after, limit)
+	if err != nil {
+		s.logger.Error("failed to list orders", "error", err)
+		return nil, err
+	}
+	return page, nil
+}
@@ -12,21 +12,34 @@ import (
 	"github.com/bbsbb/go-edge/sweetshop/internal/domain"
 )
 
+const (
+	DefaultProductSearchLimit = 20
+	MaxProductSearchLimit     = 50
+)
+
 type ProductService struct {
-	repo   domain.ProductRepository
-	logger *slog.Logger
+	repo       domain.ProductRepository
+	categories domain.CategoryRepository
+	options    domain.ProductOptionRepository
+	orgs       domain.OrganizationRepository
+	logger     *slog.Logger
 }
 
-func NewProductService(repo domain.ProductRepository, logger *slog.Logger) *ProductService {
-	return &ProductService{repo: repo, logger: logger}
+func NewProductService(
+	repo domain.ProductRepository,
+	categories domain.CategoryRepository,
+	options domain.ProductOptionRepository,
+	orgs domain.OrganizationRepository,
+	logger *slog.Logger,
+) *ProductService {
+	return &ProductService{repo: repo, categories: categories, options: options, orgs: orgs, logger: logger}
 }
 
-func (s *ProductService) Create(ctx context.Context, name string, category domain.ProductCategory, priceCents int32) (*domain.Product, error) {
-	if !category.IsValid() {
-		return nil, coredomain.NewError(coredomain.CodeValidation, "invalid product category")
-	}
-	if priceCents <= 0 {
-		return nil, coredomain.NewError(coredomain.CodeValidation, "price must be positive")
+// Create adds a product with the name, category, price, SKU, description,
+// availability and sort order of fields.
+func (s *ProductService) Create(ctx context.Context, fields *domain.Product) (*domain.Product, error) {
+	if err := s.validate(ctx, fields); err != nil {
+		return nil, err
 	}
 
 	org, err := coredomain.OrganizationFromContext(ctx)
@@ -40,17 +53,15 @@ func (s *ProductService) Create(ctx context.Context, name string, category domai
 		OrganizationID: org.ID,
 		CreatedAt:      now,
 		UpdatedAt:      now,
-		Name:           name,
-		Category:       category,
-		PriceCents:     priceCents,
 	}
+	setProductFields(product, fields)
 
 	if err := s.repo.Create(ctx, product); err != nil {
-		s.logger.Error("failed to create product", "error", err, "name", name)
+		s.logger.Error("failed to create product", "error", err, "name", product.Name)
 		return nil, err
 	}
 
-	s.logger.Info("product created", "product_id", product.ID, "name", name, "category", category)
+	s.logger.Info("product created", "product_id", product.ID, "name", product.Name, "category", product.Category)
 	return product, nil
 }
 
@@ -72,12 +83,30 @@ func (s *ProductService) List(ctx context.Context) ([]*domain.Product, error) {
 	return products, nil
 }
 
-func (s *ProductService) Update(ctx context.Context, id uuid.UUID, name string, category domain.ProductCategory, priceCents int32) (*domain.Product, error) {
-	if !category.IsValid() {
-		return nil, coredomain.NewError(coredomain.CodeValidation, "invalid product category")
+// Search returns the live products best matching query, inactive ones
+// included.
+func (s *ProductService) Search(ctx context.Context, query domain.ProductSearch) ([]domain.ProductMatch, error) {
+	if query.Limit <= 0 || query.Limit > MaxProductSearchLimit {
+		return nil, coredomain.NewError(coredomain.CodeValidation, "limit must be between 1 and 50")
+	}
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+
+	matches, err := s.repo.Search(ctx, query)
+	if err != nil {
+		s.logger.Error("failed to search products", "error", err)
+		return nil, err
 	}
-	if priceCents <= 0 {
-		return nil, coredomain.NewError(coredomain.CodeValidation, "price must be positive")
+	return matches, nil
+}
+
+// Update replaces the product's name, category, price, SKU, description,
+// availability and sort order with those of fields. Items already in orders
+// keep the price they were added at.
+func (s *ProductService) Update(ctx context.Context, id uuid.UUID, fields *domain.Product) (*domain.Product, error) {
+	if err := s.validate(ctx, fields); err != nil {
+		return nil, err
 	}
 
 	product, err := s.repo.FindByID(ctx, id)
@@ -85,9 +114,7 @@ func (s *ProductService) Update(ctx context.Context, id uuid.UUID, name string,
 		return nil, err
 	}
 
-	product.Name = name
-	product.Category = category
-	product.PriceCents = priceCents
+	setProductFields(product, fields)
 	product.UpdatedAt = time.Now()
 
 	if err := s.repo.Update(ctx, product); err != nil {
@@ -95,15 +122,78 @@ func (s *ProductService) Update(ctx context.Context, id uuid.UUID, name string,
 		return nil, err
 	}
 
-	s.logger.Info("product updated", "product_id", id, "name", name, "category", category)
+	s.logger.Info("product updated", "product_id", id, "name", product.Name, "category", product.Category)
 	return product, nil
 }
 
+// validate checks that a product's category exists and that its price is
+// positive and in the organization's currency.
+func (s *ProductService) validate(ctx context.Context, fields *domain.Product) error {
+	currency, err := organizationCurrency(ctx, s.orgs)
+	if err != nil {
+		return err
+	}
+	slugs, err := s.categorySlugs(ctx)
+	if err != nil {
+		return err
+	}
+	return fields.CheckFields(currency, slugs)
+}
+
+// categorySlugs returns the set of the organization's category slugs.
+func (s *ProductService) categorySlugs(ctx context.Context) (map[domain.ProductCategory]bool, error) {
+	categories, err := s.categories.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	slugs := make(map[domain.ProductCategory]bool, len(categories))
+	for _, c := range categories {
+		slugs[c.Slug] = true
+	}
+	return slugs, nil
+}
+
+// Delete soft-deletes a product. The row is kept so historical order items
+// still resolve it; PurgeDeleted removes it once the retention period passes.
 func (s *ProductService) Delete(ctx context.Context, id uuid.UUID) error {
-	if err := s.repo.Delete(ctx, id); err != nil {
+	if err := s.repo.Delete(ctx, id, time.Now()); err != nil {
 		s.logger.Error("failed to delete product", "error", err, "product_id", id)
 		return err
 	}
 	s.logger.Info("product deleted", "product_id", id)
 	return nil
 }
+
+func (s *ProductService) Restore(ctx context.Context, id uuid.UUID) (*domain.Product, error) {
+	product, err := s.repo.Restore(ctx, id, time.Now())
+	if err != nil {
+		s.logger.Warn("failed to restore product", "error", err, "product_id", id)
+		return nil, err
+	}
+	s.logger.Info("product restored", "product_id", id)
+	return product, nil
+}
+
+// PurgeDeleted permanently removes the organization's products that were
+// soft-deleted before deletedBefore and are not referenced by any order item.
+func (s *ProductService) PurgeDeleted(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	n, err := s.repo.PurgeDeleted(ctx, deletedBefore)
+	if err != nil {
+		s.logger.Error("failed to purge deleted products", "error", err)
+		return 0, err
+	}
+	if n > 0 {
+		s.logger.Info("purged deleted products", "count", n, "deleted_before", deletedBefore)
+	}
+	return n, nil
+}
+
+func setProductFields(p, fields *domain.Product) {
+	p.Name = fields.Name
+	p.Category = fields.Category
+	p.Price = fields.Price
+	p.SKU = fields.SKU
+	p.Description = fields.Description
+	p.Active = fields.Active
+	p.SortOrder = fields.SortOrder
+}
@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/bbsbb/go-edge/core/datatype"
+	coredomain "github.com/bbsbb/go-edge/core/domain"
+	"github.com/bbsbb/go-edge/sweetshop/internal/domain"
+)
+
+// Import creates and updates products from rows by the rules of Create and
+// Update, matching each row to a live product as domain.ProductImport does.
+// The valid rows are stored together and the others reported with their
+// line; a dry run only reports. Rows that were not read carry their error.
+func (s *ProductService) Import(ctx context.Context, rows []domain.ProductImportRow, dryRun bool) (*domain.ProductImportReport, error) {
+	if len(rows) > domain.MaxProductImportRows {
+		return nil, coredomain.NewError(coredomain.CodeValidation,
+			fmt.Sprintf("an import has at most %d rows", domain.MaxProductImportRows))
+	}
+
+	org, err := coredomain.OrganizationFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	currency, err := organizationCurrency(ctx, s.orgs)
+	if err != nil {
+		return nil, err
+	}
+	slugs, err := s.categorySlugs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	imp := domain.NewProductImport(existing)
+	report := &domain.ProductImportReport{DryRun: dryRun, Rows: len(rows)}
+	now := time.Now()
+	var products []*domain.Product
+
+	for _, row := range rows {
+		target, err := matchImportRow(imp, row, slugs, currency)
+		if err != nil {
+			report.Errors = append(report.Errors, domain.ProductImportError{Line: row.Line, Err: err})
+			continue
+		}
+		if target == nil {
+			target = &domain.Product{
+				ID:             uuid.Must(uuid.NewV7()),
+				OrganizationID: org.ID,
+				CreatedAt:      now,
+			}
+			report.Created++
+		} else {
+			report.Updated++
+		}
+		setProductFields(target, row.Fields)
+		target.UpdatedAt = now
+		products = append(products, target)
+	}
+
+	if dryRun || len(products) == 0 {
+		return report, nil
+	}
+	if err := s.repo.Upsert(ctx, products); err != nil {
+		s.logger.Error("failed to import products", "error", err, "rows", len(rows))
+		return nil, err
+	}
+
+	s.logger.Info("products imported", "rows", report.Rows, "created", report.Created,
+		"updated", report.Updated, "rejected", len(report.Errors))
+	return report, nil
+}
+
+// matchImportRow checks the row's fields as validate does and returns the
+// product it updates, or nil if it creates one.
+func matchImportRow(
+	imp *domain.ProductImport,
+	row domain.ProductImportRow,
+	slugs map[domain.ProductCategory]bool,
+	currency datatype.Currency,
+) (*domain.Product, error) {
+	if row.Err != nil {
+		return nil, row.Err
+	}
+	if err := row.Fields.CheckFields(currency, slugs); err != nil {
+		return nil, err
+	}
+	return imp.Match(row.Line, row.Fields)
+}
+
+// Export calls fn with every live product, in listing order, as it is read,
+// so that the catalogue is never held in memory.
+func (s *ProductService) Export(ctx context.Context, fn func(*domain.Product) error) error {
+	if err := s.repo.Each(ctx, fn); err != nil {
+		s.logger.Error("failed to export products", "error", err)
+		return err
+	}
+	return nil
+}
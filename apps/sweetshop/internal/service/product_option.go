@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	coredomain "github.com/bbsbb/go-edge/core/domain"
+	"github.com/bbsbb/go-edge/sweetshop/internal/domain"
+)
+
+// ListOptions returns the product's option groups with their options.
+func (s *ProductService) ListOptions(ctx context.Context, productID uuid.UUID) ([]domain.OptionGroup, error) {
+	if _, err := s.repo.FindByID(ctx, productID); err != nil {
+		return nil, err
+	}
+	groups, err := s.options.ListByProductID(ctx, productID)
+	if err != nil {
+		s.logger.Error("failed to list product options", "error", err, "product_id", productID)
+		return nil, err
+	}
+	return groups, nil
+}
+
+// ReplaceOptions replaces the product's option groups with groups. Groups and
+// options with an ID keep it, which must be one the product already has; the
+// others get a new one. Items already in orders keep the options they were
+// added with.
+func (s *ProductService) ReplaceOptions(ctx context.Context, productID uuid.UUID, groups []domain.OptionGroup) ([]domain.OptionGroup, error) {
+	product, err := s.repo.FindByID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	currency, err := organizationCurrency(ctx, s.orgs)
+	if err != nil {
+		return nil, err
+	}
+	current, err := s.options.ListByProductID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	groupIDs, optionIDs := currentOptionIDs(current)
+	names := make(map[string]bool, len(groups))
+	for i := range groups {
+		g := &groups[i]
+		if names[g.Name] {
+			return nil, coredomain.NewError(coredomain.CodeValidation, fmt.Sprintf("option group %q is named twice", g.Name))
+		}
+		names[g.Name] = true
+		if g.ID, err = keepID(g.ID, groupIDs, "option group"); err != nil {
+			return nil, err
+		}
+		g.OrganizationID = product.OrganizationID
+		g.ProductID = productID
+		for j := range g.Options {
+			o := &g.Options[j]
+			if o.ID, err = keepID(o.ID, optionIDs, "option"); err != nil {
+				return nil, err
+			}
+			o.GroupID = g.ID
+		}
+		if err := g.Validate(currency); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.options.Replace(ctx, productID, groups); err != nil {
+		s.logger.Error("failed to replace product options", "error", err, "product_id", productID)
+		return nil, err
+	}
+
+	s.logger.Info("product options replaced", "product_id", productID, "groups", len(groups))
+	return groups, nil
+}
+
+// currentOptionIDs returns the IDs of the product's current groups and
+// options, which a replacement can keep.
+func currentOptionIDs(current []domain.OptionGroup) (groups, options map[uuid.UUID]bool) {
+	groups, options = map[uuid.UUID]bool{}, map[uuid.UUID]bool{}
+	for _, g := range current {
+		groups[g.ID] = true
+		for _, o := range g.Options {
+			options[o.ID] = true
+		}
+	}
+	return groups, options
+}
+
+// keepID returns a new ID when id is unset. Otherwise id must be in
+// available, from which it is removed so it is kept only once.
+func keepID(id uuid.UUID, available map[uuid.UUID]bool, what string) (uuid.UUID, error) {
+	if id == uuid.Nil {
+		return uuid.Must(uuid.NewV7()), nil
+	}
+	if !available[id] {
+		return uuid.Nil, coredomain.NewError(coredomain.CodeValidation, fmt.Sprintf("%s %s is not one of the product's", what, id))
+	}
+	delete(available, id)
+	return id, nil
+}
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+
+	coredomain "github.com/bbsbb/go-edge/core/domain"
+	"github.com/bbsbb/go-edge/sweetshop/internal/domain"
+)
+
+// PromotionService manages an organization's promotions. Orders are priced
+// with them by OrderService.
+type PromotionService struct {
+	promotions domain.PromotionRepository
+	categories domain.CategoryRepository
+	orgs       domain.OrganizationRepository
+	logger     *slog.Logger
+}
+
+func NewPromotionService(promotions domain.PromotionRepository, categories domain.CategoryRepository, orgs domain.OrganizationRepository, logger *slog.Logger) *PromotionService {
+	return &PromotionService{promotions: promotions, categories: categories, orgs: orgs, logger: logger}
+}
+
+// Create adds a promotion with the rule, name, window and usage limit of
+// rule.
+func (s *PromotionService) Create(ctx context.Context, rule *domain.Promotion) (*domain.Promotion, error) {
+	org, err := coredomain.OrganizationFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	promotion := &domain.Promotion{
+		ID:             uuid.Must(uuid.NewV7()),
+		OrganizationID: org.ID,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	setPromotionRule(promotion, rule)
+	if err := s.validate(ctx, promotion); err != nil {
+		return nil, err
+	}
+
+	if err := s.promotions.Create(ctx, promotion); err != nil {
+		s.logger.Error("failed to create promotion", "error", err)
+		return nil, err
+	}
+
+	s.logger.Info("promotion created", "promotion_id", promotion.ID, "kind", promotion.Kind)
+	return promotion, nil
+}
+
+func (s *PromotionService) Get(ctx context.Context, id uuid.UUID) (*domain.Promotion, error) {
+	promotion, err := s.promotions.FindByID(ctx, id)
+	if err != nil {
+		s.logger.Debug("promotion lookup failed", "error", err, "promotion_id", id)
+		return nil, err
+	}
+	return promotion, nil
+}
+
+func (s *PromotionService) List(ctx context.Context) ([]*domain.Promotion, error) {
+	promotions, err := s.promotions.List(ctx)
+	if err != nil {
+		s.logger.Error("failed to list promotions", "error", err)
+		return nil, err
+	}
+	return promotions, nil
+}
+
+// Update replaces the promotion's rule, name, window and usage limit. Orders
+// already submitted keep the discounts they were given.
+func (s *PromotionService) Update(ctx context.Context, id uuid.UUID, rule *domain.Promotion) (*domain.Promotion, error) {
+	promotion, err := s.promotions.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	setPromotionRule(promotion, rule)
+	promotion.UpdatedAt = time.Now()
+	if err := s.validate(ctx, promotion); err != nil {
+		return nil, err
+	}
+	if promotion.UsageLimit != nil && *promotion.UsageLimit < promotion.UsageCount {
+		return nil, coredomain.NewError(coredomain.CodeValidation, "usage_limit is below the promotion's usage count")
+	}
+
+	if err := s.promotions.Update(ctx, promotion); err != nil {
+		s.logger.Error("failed to update promotion", "error", err, "promotion_id", id)
+		return nil, err
+	}
+
+	s.logger.Info("promotion updated", "promotion_id", id)
+	return promotion, nil
+}
+
+func (s *PromotionService) Delete(ctx context.Context, id uuid.UUID) error {
+	if err := s.promotions.Delete(ctx, id); err != nil {
+		s.logger.Error("failed to delete promotion", "error", err, "promotion_id", id)
+		return err
+	}
+	s.logger.Info("promotion deleted", "promotion_id", id)
+	return nil
+}
+
+// validate checks the promotion's rule, that its category exists and that a
+// fixed amount is in the organization's currency.
+func (s *PromotionService) validate(ctx context.Context, promotion *domain.Promotion) error {
+	if err := promotion.Validate(); err != nil {
+		return err
+	}
+	if promotion.Category != nil {
+		if err := checkCategory(ctx, s.categories, *promotion.Category); err != nil {
+			return err
+		}
+	}
+	if promotion.AmountOff == nil {
+		return nil
+	}
+	currency, err := organizationCurrency(ctx, s.orgs)
+	if err != nil {
+		return err
+	}
+	if promotion.AmountOff.Currency() != currency {
+		return coredomain.NewError(coredomain.CodeValidation, "amount_off must be in the organization currency "+string(currency))
+	}
+	return nil
+}
+
+func setPromotionRule(p, rule *domain.Promotion) {
+	p.Name = rule.Name
+	p.Kind = rule.Kind
+	p.Category = rule.Category
+	p.PercentOff = rule.PercentOff
+	p.AmountOff = rule.AmountOff
+	p.BuyQuantity = rule.BuyQuantity
+	p.GetQuantity = rule.GetQuantity
+	p.StartsAt = rule.StartsAt
+	p.EndsAt = rule.EndsAt
+	p.UsageLimit = rule.UsageLimit
+}
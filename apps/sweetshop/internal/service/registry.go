@@ -1,10 +1,37 @@
 package service
 
 type Registry struct {
-	Products *ProductService
-	Orders   *OrderService
+	Settings    *SettingsService
+	Products    *ProductService
+	Categories  *CategoryService
+	Inventory   *InventoryService
+	Orders      *OrderService
+	Promotions  *PromotionService
+	OrderEvents *OrderEventService
+	Webhooks    *WebhookService
+	Reports     *ReportService
 }
 
-func NewRegistry(products *ProductService, orders *OrderService) *Registry {
-	return &Registry{Products: products, Orders: orders}
+func NewRegistry(
+	settings *SettingsService,
+	products *ProductService,
+	categories *CategoryService,
+	inventory *InventoryService,
+	orders *OrderService,
+	promotions *PromotionService,
+	orderEvents *OrderEventService,
+	webhooks *WebhookService,
+	reports *ReportService,
+) *Registry {
+	return &Registry{
+		Settings:    settings,
+		Products:    products,
+		Categories:  categories,
+		Inventory:   inventory,
+		Orders:      orders,
+		Promotions:  promotions,
+		OrderEvents: orderEvents,
+		Webhooks:    webhooks,
+		Reports:     reports,
+	}
 }
@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	coredomain "github.com/bbsbb/go-edge/core/domain"
+	"github.com/bbsbb/go-edge/sweetshop/internal/domain"
+)
+
+const (
+	// DefaultSalesReportDays is how many days, up to today, a report covers
+	// when it is not given a range.
+	DefaultSalesReportDays  = 30
+	DefaultTopProductsLimit = 10
+	MaxTopProductsLimit     = 100
+)
+
+// ReportService reports an organization's sales from the daily aggregates a
+// background job keeps; sales show up in reports once it has run.
+type ReportService struct {
+	reports domain.SalesReportRepository
+	orgs    domain.OrganizationRepository
+	logger  *slog.Logger
+}
+
+func NewReportService(reports domain.SalesReportRepository, orgs domain.OrganizationRepository, logger *slog.Logger) *ReportService {
+	return &ReportService{reports: reports, orgs: orgs, logger: logger}
+}
+
+// Refresh brings the daily aggregates of the organization in context up to
+// date as of now.
+func (s *ReportService) Refresh(ctx context.Context, now time.Time) error {
+	org, err := coredomain.OrganizationFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	if err := s.reports.Refresh(ctx, org.ID, now); err != nil {
+		s.logger.Error("failed to refresh sales reports", "error", err)
+		return err
+	}
+	return nil
+}
+
+// Sales returns the organization's sales per period and currency. A zero
+// From or To defaults to the last DefaultSalesReportDays days and an empty
+// granularity to days.
+func (s *ReportService) Sales(ctx context.Context, query domain.SalesQuery) (*domain.SalesReport, error) {
+	org, err := coredomain.OrganizationFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	query.From, query.To = salesDays(query.From, query.To, time.Now())
+	if query.Granularity == "" {
+		query.Granularity = domain.SalesByDay
+	}
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+
+	periods, err := s.reports.ListSales(ctx, org.ID, query)
+	if err != nil {
+		s.logger.Error("failed to list sales", "error", err)
+		return nil, err
+	}
+	return &domain.SalesReport{SalesQuery: query, Periods: periods}, nil
+}
+
+// TopProducts returns the organization's best-selling products in its current
+// currency. The range defaults as for Sales.
+func (s *ReportService) TopProducts(ctx context.Context, query domain.TopProductsQuery) (*domain.TopProductsReport, error) {
+	if query.Limit <= 0 || query.Limit > MaxTopProductsLimit {
+		return nil, coredomain.NewError(coredomain.CodeValidation, "limit must be between 1 and 100")
+	}
+	org, err := coredomain.OrganizationFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if query.Currency, err = organizationCurrency(ctx, s.orgs); err != nil {
+		return nil, err
+	}
+	query.From, query.To = salesDays(query.From, query.To, time.Now())
+	if query.By == "" {
+		query.By = domain.TopProductsByUnits
+	}
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+
+	products, err := s.reports.ListTopProducts(ctx, org.ID, query)
+	if err != nil {
+		s.logger.Error("failed to list top products", "error", err)
+		return nil, err
+	}
+	return &domain.TopProductsReport{TopProductsQuery: query, Products: products}, nil
+}
+
+// salesDays fills in a report's missing bounds: To defaults to today and From
+// to DefaultSalesReportDays days before To.
+func salesDays(from, to, now time.Time) (time.Time, time.Time) {
+	if to.IsZero() {
+		to = domain.SalesDay(now)
+	}
+	if from.IsZero() {
+		from = domain.SalesDay(to).AddDate(0, 0, 1-DefaultSalesReportDays)
+	}
+	return domain.SalesDay(from), domain.SalesDay(to)
+}
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/bbsbb/go-edge/core/datatype"
+	coredomain "github.com/bbsbb/go-edge/core/domain"
+	"github.com/bbsbb/go-edge/sweetshop/internal/domain"
+)
+
+// SettingsService reads and changes the caller's organization settings and
+// tax profile.
+type SettingsService struct {
+	orgs       domain.OrganizationRepository
+	taxes      domain.TaxProfileRepository
+	categories domain.CategoryRepository
+	logger     *slog.Logger
+}
+
+func NewSettingsService(orgs domain.OrganizationRepository, taxes domain.TaxProfileRepository, categories domain.CategoryRepository, logger *slog.Logger) *SettingsService {
+	return &SettingsService{orgs: orgs, taxes: taxes, categories: categories, logger: logger}
+}
+
+func (s *SettingsService) Get(ctx context.Context) (*domain.OrganizationSettings, error) {
+	org, err := coredomain.OrganizationFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	settings, err := s.orgs.FindSettings(ctx, org.ID)
+	if err != nil {
+		s.logger.Error("failed to get organization settings", "error", err, "organization_id", org.ID)
+		return nil, err
+	}
+	return settings, nil
+}
+
+// UpdateCurrency switches the currency the organization prices in. Products
+// keep their prices until they are updated, and cannot be added to orders
+// opened in the new currency until then.
+func (s *SettingsService) UpdateCurrency(ctx context.Context, currency datatype.Currency) (*domain.OrganizationSettings, error) {
+	if !currency.IsValid() {
+		return nil, coredomain.NewError(coredomain.CodeValidation, "invalid currency")
+	}
+
+	settings, err := s.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	settings.Currency = currency
+	settings.UpdatedAt = time.Now()
+	if err := s.orgs.UpdateSettings(ctx, settings); err != nil {
+		s.logger.Error("failed to update organization settings", "error", err, "organization_id", settings.OrganizationID)
+		return nil, err
+	}
+
+	s.logger.Info("organization currency updated", "organization_id", settings.OrganizationID, "currency", currency)
+	return settings, nil
+}
+
+// GetTaxProfile returns the organization's tax profile; see
+// organizationTaxProfile.
+func (s *SettingsService) GetTaxProfile(ctx context.Context) (*domain.TaxProfile, error) {
+	profile, err := organizationTaxProfile(ctx, s.taxes)
+	if err != nil {
+		s.logger.Error("failed to get tax profile", "error", err)
+		return nil, err
+	}
+	return profile, nil
+}
+
+// UpdateTaxProfile replaces the organization's tax profile with the pricing,
+// rounding and rates of profile. Open orders are taxed with it from now on;
+// submitted orders keep their tax.
+func (s *SettingsService) UpdateTaxProfile(ctx context.Context, profile *domain.TaxProfile) (*domain.TaxProfile, error) {
+	org, err := coredomain.OrganizationFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := profile.Validate(); err != nil {
+		return nil, err
+	}
+	for _, category := range slices.Sorted(maps.Keys(profile.Rates)) {
+		if err := checkCategory(ctx, s.categories, category); err != nil {
+			return nil, err
+		}
+	}
+
+	profile.OrganizationID = org.ID
+	profile.UpdatedAt = time.Now()
+	if err := s.taxes.Save(ctx, profile); err != nil {
+		s.logger.Error("failed to save tax profile", "error", err, "organization_id", org.ID)
+		return nil, err
+	}
+
+	s.logger.Info("tax profile updated", "organization_id", org.ID, "pricing", profile.Pricing, "rounding", profile.Rounding)
+	return profile, nil
+}
+
+// organizationTaxProfile returns the caller's organization's tax profile, or
+// the default one if it has not set one.
+func organizationTaxProfile(ctx context.Context, taxes domain.TaxProfileRepository) (*domain.TaxProfile, error) {
+	org, err := coredomain.OrganizationFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	profile, err := taxes.Find(ctx, org.ID)
+	if errors.Is(err, coredomain.ErrNotFound) {
+		return domain.DefaultTaxProfile(org.ID), nil
+	}
+	return profile, err
+}
+
+// organizationCurrency returns the currency the caller's organization prices in.
+func organizationCurrency(ctx context.Context, orgs domain.OrganizationRepository) (datatype.Currency, error) {
+	org, err := coredomain.OrganizationFromContext(ctx)
+	if err != nil {
+		return "", err
+	}
+	settings, err := orgs.FindSettings(ctx, org.ID)
+	if err != nil {
+		return "", err
+	}
+	return settings.Currency, nil
+}
@@ -0,0 +1,219 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+
+	coredomain "github.com/bbsbb/go-edge/core/domain"
+	"github.com/bbsbb/go-edge/sweetshop/internal/config"
+	"github.com/bbsbb/go-edge/sweetshop/internal/domain"
+)
+
+const (
+	DefaultWebhookDeliveryLimit = 50
+	MaxWebhookDeliveryLimit     = 200
+)
+
+// WebhookService manages an organization's webhook endpoints and sends the
+// deliveries queued for them.
+type WebhookService struct {
+	endpoints    domain.WebhookEndpointRepository
+	deliveries   domain.WebhookDeliveryRepository
+	sender       domain.WebhookSender
+	policy       domain.WebhookRetryPolicy
+	targets      domain.WebhookTargetPolicy
+	batchSize    int32
+	lease        time.Duration
+	disableAfter int32
+	logger       *slog.Logger
+	now          func() time.Time
+}
+
+func NewWebhookService(
+	endpoints domain.WebhookEndpointRepository,
+	deliveries domain.WebhookDeliveryRepository,
+	sender domain.WebhookSender,
+	cfg *config.AppConfiguration,
+	logger *slog.Logger,
+) *WebhookService {
+	wh := cfg.WebhookConfiguration()
+	return &WebhookService{
+		endpoints:  endpoints,
+		deliveries: deliveries,
+		sender:     sender,
+		policy: domain.WebhookRetryPolicy{
+			MaxAttempts: wh.MaxAttemptsOrDefault(),
+			MinDelay:    wh.MinRetryDelayOrDefault(),
+			MaxDelay:    wh.MaxRetryDelayOrDefault(),
+		},
+		targets:      domain.WebhookTargetPolicy{AllowHTTP: wh.AllowHTTP, AllowedNetworks: wh.AllowedPrefixes()},
+		batchSize:    wh.BatchSizeOrDefault(),
+		lease:        wh.LeaseOrDefault(),
+		disableAfter: wh.DisableAfterOrDefault(),
+		logger:       logger,
+		now:          time.Now,
+	}
+}
+
+// CreateEndpoint registers an endpoint with a freshly generated secret. The
+// returned endpoint is the only place the secret is handed out.
+func (s *WebhookService) CreateEndpoint(ctx context.Context, url string, eventTypes []domain.WebhookEventType) (*domain.WebhookEndpoint, error) {
+	org, err := coredomain.OrganizationFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.targets.CheckURL(url); err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	endpoint := &domain.WebhookEndpoint{
+		ID:             uuid.Must(uuid.NewV7()),
+		OrganizationID: org.ID,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+		URL:            url,
+		Secret:         domain.NewWebhookSecret(),
+		Enabled:        true,
+	}
+	if err := endpoint.SetEventTypes(eventTypes); err != nil {
+		return nil, err
+	}
+
+	if err := s.endpoints.Create(ctx, endpoint); err != nil {
+		s.logger.Error("failed to create webhook endpoint", "error", err)
+		return nil, err
+	}
+
+	s.logger.Info("webhook endpoint created", "endpoint_id", endpoint.ID, "event_types", endpoint.EventTypes)
+	return endpoint, nil
+}
+
+func (s *WebhookService) GetEndpoint(ctx context.Context, id uuid.UUID) (*domain.WebhookEndpoint, error) {
+	endpoint, err := s.endpoints.FindByID(ctx, id)
+	if err != nil {
+		s.logger.Debug("webhook endpoint lookup failed", "error", err, "endpoint_id", id)
+		return nil, err
+	}
+	return endpoint, nil
+}
+
+func (s *WebhookService) ListEndpoints(ctx context.Context) ([]*domain.WebhookEndpoint, error) {
+	endpoints, err := s.endpoints.List(ctx)
+	if err != nil {
+		s.logger.Error("failed to list webhook endpoints", "error", err)
+		return nil, err
+	}
+	return endpoints, nil
+}
+
+// UpdateEndpoint replaces the endpoint's URL and subscriptions. Enabling a
+// disabled endpoint resets its failure count and resumes its pending
+// deliveries.
+func (s *WebhookService) UpdateEndpoint(ctx context.Context, id uuid.UUID, url string, eventTypes []domain.WebhookEventType, enabled bool) (*domain.WebhookEndpoint, error) {
+	endpoint, err := s.endpoints.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.targets.CheckURL(url); err != nil {
+		return nil, err
+	}
+	now := s.now()
+	if err := endpoint.SetEventTypes(eventTypes); err != nil {
+		return nil, err
+	}
+	endpoint.URL = url
+	endpoint.UpdatedAt = now
+	switch {
+	case enabled && !endpoint.Enabled:
+		endpoint.Enable()
+	case !enabled:
+		endpoint.Disable(now)
+	}
+
+	if err := s.endpoints.Update(ctx, endpoint); err != nil {
+		s.logger.Error("failed to update webhook endpoint", "error", err, "endpoint_id", id)
+		return nil, err
+	}
+
+	s.logger.Info("webhook endpoint updated", "endpoint_id", id, "enabled", endpoint.Enabled)
+	return endpoint, nil
+}
+
+func (s *WebhookService) DeleteEndpoint(ctx context.Context, id uuid.UUID) error {
+	if err := s.endpoints.Delete(ctx, id); err != nil {
+		s.logger.Error("failed to delete webhook endpoint", "error", err, "endpoint_id", id)
+		return err
+	}
+	s.logger.Info("webhook endpoint deleted", "endpoint_id", id)
+	return nil
+}
+
+// ListDeliveries returns the endpoint's delivery log, newest first.
+func (s *WebhookService) ListDeliveries(ctx context.Context, endpointID uuid.UUID, limit int32) ([]domain.WebhookDelivery, error) {
+	if limit <= 0 || limit > MaxWebhookDeliveryLimit {
+		return nil, coredomain.NewError(coredomain.CodeValidation, "limit must be between 1 and 200")
+	}
+	if _, err := s.endpoints.FindByID(ctx, endpointID); err != nil {
+		return nil, err
+	}
+
+	deliveries, err := s.deliveries.ListByEndpoint(ctx, endpointID, limit)
+	if err != nil {
+		s.logger.Error("failed to list webhook deliveries", "error", err, "endpoint_id", endpointID)
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// DeliverDue sends the organization's due deliveries and records the outcome
+// of each, returning how many succeeded. Failed deliveries are rescheduled
+// with exponential backoff; an endpoint that keeps failing is disabled.
+func (s *WebhookService) DeliverDue(ctx context.Context) (int, error) {
+	now := s.now()
+	pending, err := s.deliveries.ClaimDue(ctx, now, now.Add(s.lease), s.batchSize)
+	if err != nil {
+		s.logger.Error("failed to claim webhook deliveries", "error", err)
+		return 0, err
+	}
+
+	var succeeded int
+	disabledEndpoints := make(map[uuid.UUID]bool)
+	for i := range pending {
+		p := &pending[i]
+		// Deliveries claimed for an endpoint disabled earlier in this batch
+		// stay pending until it is enabled again.
+		if disabledEndpoints[p.EndpointID] {
+			continue
+		}
+		attempt := s.sender.Send(ctx, *p)
+		p.RecordAttempt(attempt, s.now(), s.policy)
+
+		disabled, err := s.deliveries.RecordAttempt(ctx, &p.WebhookDelivery, s.disableAfter)
+		if err != nil {
+			s.logger.Error("failed to record webhook attempt", "error", err, "delivery_id", p.ID)
+			continue
+		}
+
+		switch p.Status {
+		case domain.WebhookDeliverySucceeded:
+			succeeded++
+		case domain.WebhookDeliveryFailed:
+			s.logger.Warn("webhook delivery failed permanently", "delivery_id", p.ID,
+				"endpoint_id", p.EndpointID, "attempts", p.Attempts, "status", attempt.StatusCode, "error", attempt.Error)
+		case domain.WebhookDeliveryPending:
+			s.logger.Info("webhook delivery failed, will retry", "delivery_id", p.ID,
+				"endpoint_id", p.EndpointID, "attempts", p.Attempts, "next_attempt_at", p.NextAttemptAt,
+				"status", attempt.StatusCode, "error", attempt.Error)
+		}
+		if disabled {
+			disabledEndpoints[p.EndpointID] = true
+			s.logger.Warn("webhook endpoint disabled after repeated failures", "endpoint_id", p.EndpointID)
+		}
+	}
+	return succeeded, nil
+}
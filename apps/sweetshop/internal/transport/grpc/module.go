@@ -0,0 +1,50 @@
+// Package grpc wires sweetshop's gRPC transport: application interceptors and,
+// as services are added, their registration on the core gRPC server.
+package grpc
+
+import (
+	"log/slog"
+
+	"go.uber.org/fx"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	reflectionv1alphapb "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+
+	"github.com/bbsbb/go-edge/core/fx/grpcserverfx"
+	transportgrpc "github.com/bbsbb/go-edge/core/transport/grpc"
+	coremiddleware "github.com/bbsbb/go-edge/core/transport/http/middleware"
+)
+
+// organizationSkipMethods are served without tenant context.
+var organizationSkipMethods = []string{
+	healthpb.Health_Check_FullMethodName,
+	healthpb.Health_List_FullMethodName,
+	healthpb.Health_Watch_FullMethodName,
+	reflectionpb.ServerReflection_ServerReflectionInfo_FullMethodName,
+	reflectionv1alphapb.ServerReflection_ServerReflectionInfo_FullMethodName,
+}
+
+type orgInterceptorResult struct {
+	fx.Out
+	Interceptor grpcserverfx.Interceptor `group:"grpc_interceptors"`
+}
+
+func provideOrganizationInterceptor(loader coremiddleware.OrganizationLoader, logger *slog.Logger) orgInterceptorResult {
+	cfg := transportgrpc.WithOrganizationConfig{
+		SkipMethods: organizationSkipMethods,
+		Logger:      logger,
+		Loader:      loader,
+	}
+	return orgInterceptorResult{
+		Interceptor: grpcserverfx.Interceptor{
+			Name:   "organization",
+			Unary:  transportgrpc.UnaryOrganization(cfg),
+			Stream: transportgrpc.StreamOrganization(cfg),
+		},
+	}
+}
+
+var Module = fx.Module(
+	"sweetshop/grpc",
+	fx.Provide(provideOrganizationInterceptor),
+)
@@ -0,0 +1,58 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	coredomain "github.com/bbsbb/go-edge/core/domain"
+	coretesting "github.com/bbsbb/go-edge/core/testing"
+)
+
+type stubLoader struct {
+	calls int
+}
+
+func (l *stubLoader) LoadOrganizationBySlug(context.Context, string) (*coredomain.Organization, error) {
+	l.calls++
+	return nil, errors.New("not found")
+}
+
+type ModuleSuite struct {
+	suite.Suite
+}
+
+func (s *ModuleSuite) call(loader *stubLoader, method string) error {
+	interceptor := provideOrganizationInterceptor(loader, coretesting.NewNoopLogger()).Interceptor
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-organization-slug", "nope"))
+	_, err := interceptor.Unary(ctx, nil, &grpc.UnaryServerInfo{FullMethod: method},
+		func(context.Context, any) (any, error) { return nil, nil })
+	return err
+}
+
+func (s *ModuleSuite) TestOrganizationInterceptor_SkipsHealth() {
+	loader := &stubLoader{}
+
+	s.Require().NoError(s.call(loader, healthpb.Health_Check_FullMethodName))
+	s.Assert().Zero(loader.calls)
+}
+
+func (s *ModuleSuite) TestOrganizationInterceptor_RequiresOrganization() {
+	loader := &stubLoader{}
+
+	err := s.call(loader, "/sweetshop.v1.ProductService/GetProduct")
+
+	s.Assert().Equal(codes.NotFound, status.Code(err))
+	s.Assert().Equal(1, loader.calls)
+}
+
+func TestModuleSuite(t *testing.T) {
+	suite.Run(t, new(ModuleSuite))
+}
@@ -0,0 +1,47 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/go-chi/render"
+
+	transporthttp "github.com/bbsbb/go-edge/core/transport/http"
+	"github.com/bbsbb/go-edge/sweetshop/internal/domain"
+)
+
+// CategoryRequest is the body of POST /categories and PUT /categories/{id}.
+// The slug is what products, promotions and tax rates name the category by:
+// lowercase letters and digits in words joined by underscores.
+type CategoryRequest struct {
+	transporthttp.NoOpBinder
+	Slug      domain.ProductCategory `json:"slug" validate:"required,max=64"`
+	Name      string                 `json:"name" validate:"required,max=200"`
+	SortOrder int32                  `json:"sort_order,omitempty"`
+}
+
+type CategoryResponse struct {
+	transporthttp.NoOpRenderer
+	ID        string    `json:"id"`
+	Slug      string    `json:"slug"`
+	Name      string    `json:"name"`
+	SortOrder int32     `json:"sort_order"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func CategoryToResponse(c *domain.Category) *CategoryResponse {
+	return &CategoryResponse{
+		ID:        c.ID.String(),
+		Slug:      string(c.Slug),
+		Name:      c.Name,
+		SortOrder: c.SortOrder,
+		CreatedAt: c.CreatedAt,
+	}
+}
+
+func CategoryListToResponse(categories []*domain.Category) []render.Renderer {
+	list := make([]render.Renderer, len(categories))
+	for i, c := range categories {
+		list[i] = CategoryToResponse(c)
+	}
+	return list
+}
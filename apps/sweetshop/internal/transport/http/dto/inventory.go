@@ -0,0 +1,72 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/go-chi/render"
+
+	transporthttp "github.com/bbsbb/go-edge/core/transport/http"
+	"github.com/bbsbb/go-edge/sweetshop/internal/domain"
+)
+
+type AdjustStockRequest struct {
+	transporthttp.NoOpBinder
+	QuantityDelta int32                        `json:"quantity_delta" validate:"ne=0"`
+	Reason        domain.StockAdjustmentReason `json:"reason" validate:"stringenum"`
+	Note          *string                      `json:"note,omitempty" validate:"omitempty,max=500"`
+}
+
+// InventoryResponse is a product's stock. Untracked products can be ordered
+// without limit and report zero stock.
+type InventoryResponse struct {
+	transporthttp.NoOpRenderer
+	ProductID string     `json:"product_id"`
+	Tracked   bool       `json:"tracked"`
+	OnHand    int32      `json:"on_hand"`
+	Reserved  int32      `json:"reserved"`
+	Available int32      `json:"available"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+}
+
+func InventoryToResponse(i *domain.Inventory) *InventoryResponse {
+	resp := &InventoryResponse{
+		ProductID: i.ProductID.String(),
+		Tracked:   i.Tracked,
+		OnHand:    i.OnHand,
+		Reserved:  i.Reserved,
+		Available: i.Available(),
+	}
+	if i.Tracked {
+		resp.UpdatedAt = &i.UpdatedAt
+	}
+	return resp
+}
+
+type StockAdjustmentResponse struct {
+	transporthttp.NoOpRenderer
+	ID            string    `json:"id"`
+	QuantityDelta int32     `json:"quantity_delta"`
+	Reason        string    `json:"reason"`
+	Note          *string   `json:"note,omitempty"`
+	OnHandAfter   int32     `json:"on_hand_after"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+func StockAdjustmentToResponse(a domain.StockAdjustment) *StockAdjustmentResponse {
+	return &StockAdjustmentResponse{
+		ID:            a.ID.String(),
+		QuantityDelta: a.QuantityDelta,
+		Reason:        string(a.Reason),
+		Note:          a.Note,
+		OnHandAfter:   a.OnHandAfter,
+		CreatedAt:     a.CreatedAt,
+	}
+}
+
+func StockAdjustmentListToResponse(adjustments []domain.StockAdjustment) []render.Renderer {
+	list := make([]render.Renderer, len(adjustments))
+	for i, a := range adjustments {
+		list[i] = StockAdjustmentToResponse(a)
+	}
+	return list
+}
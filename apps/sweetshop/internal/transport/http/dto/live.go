@@ -0,0 +1,37 @@
+package dto
+
+import (
+	"encoding/json"
+
+	transporthttp "github.com/bbsbb/go-edge/core/transport/http"
+)
+
+// Live message types exchanged on GET /orders/live.
+const (
+	LiveAddOrderItem   = "order.add_item"
+	LiveOrderItemAdded = "order.item_added"
+	LiveOrderUpdated   = "order.updated"
+	LiveError          = "error"
+)
+
+// LiveCommand is a client message on GET /orders/live. ID is echoed on the
+// reply so clients can correlate it; Data is bound according to Type.
+type LiveCommand struct {
+	transporthttp.NoOpBinder
+	Type string          `json:"type" validate:"required"`
+	ID   string          `json:"id,omitempty" validate:"max=64"`
+	Data json.RawMessage `json:"data"`
+}
+
+// LiveMessage is a server message on GET /orders/live.
+type LiveMessage struct {
+	Type string `json:"type"`
+	ID   string `json:"id,omitempty"`
+	Data any    `json:"data,omitempty"`
+}
+
+// LiveAddOrderItemRequest is the data of an order.add_item command.
+type LiveAddOrderItemRequest struct {
+	AddOrderItemRequest
+	OrderID string `json:"order_id" validate:"required,uuid"`
+}
@@ -1,66 +1,303 @@
 package dto
 
 import (
+	"encoding/json"
+	"time"
+
+	"github.com/go-chi/render"
+	"github.com/google/uuid"
+
+	"github.com/bbsbb/go-edge/core/datatype"
+	coredomain "github.com/bbsbb/go-edge/core/domain"
 	transporthttp "github.com/bbsbb/go-edge/core/transport/http"
 	"github.com/bbsbb/go-edge/sweetshop/internal/domain"
 )
 
+// AddOrderItemRequest chooses the item's options by ID: exactly one of each
+// variant group of the product and as many of each modifier group as its
+// bounds allow.
 type AddOrderItemRequest struct {
 	transporthttp.NoOpBinder
-	ProductID string `json:"product_id"`
-	Quantity  int32  `json:"quantity"`
+	ProductID string   `json:"product_id" validate:"required,uuid"`
+	Quantity  int32    `json:"quantity" validate:"gt=0"`
+	OptionIDs []string `json:"option_ids,omitempty" validate:"max=50,dive,uuid"`
+}
+
+func (r *AddOrderItemRequest) Options() ([]uuid.UUID, error) {
+	ids := make([]uuid.UUID, len(r.OptionIDs))
+	for i, s := range r.OptionIDs {
+		id, err := coredomain.ParseID(s)
+		if err != nil {
+			return nil, err
+		}
+		ids[i] = id.UUID()
+	}
+	return ids, nil
+}
+
+type UpdateOrderItemRequest struct {
+	transporthttp.NoOpBinder
+	Quantity int32 `json:"quantity" validate:"gt=0"`
 }
 
+// OrderItemResponse is a line of an order. UnitPrice includes the price
+// deltas of the options chosen for it.
 type OrderItemResponse struct {
-	ID             string `json:"id"`
-	ProductID      string `json:"product_id"`
-	Quantity       int32  `json:"quantity"`
-	PriceCents     int32  `json:"price_cents"`
-	LineTotalCents int32  `json:"line_total_cents"`
+	ID          string                    `json:"id"`
+	ProductID   string                    `json:"product_id"`
+	ProductName string                    `json:"product_name"`
+	Quantity    int32                     `json:"quantity"`
+	Options     []OrderItemOptionResponse `json:"options"`
+	UnitPrice   datatype.Money            `json:"unit_price"`
+	LineTotal   datatype.Money            `json:"line_total"`
+	// Adjustments are the discounts promotions give the item; the order's
+	// total is its subtotal with them applied.
+	Adjustments []OrderAdjustmentResponse `json:"adjustments"`
 }
 
+// OrderAdjustmentResponse is a discount a promotion gives an item. Amount is
+// negative; PromotionID is null once the promotion has been deleted.
+type OrderAdjustmentResponse struct {
+	PromotionID *string        `json:"promotion_id"`
+	Description string         `json:"description"`
+	Amount      datatype.Money `json:"amount"`
+}
+
+// PaymentResponse is one call made to the payment gateway for an order.
+type PaymentResponse struct {
+	ID        string         `json:"id"`
+	Operation string         `json:"operation"`
+	Status    string         `json:"status"`
+	Amount    datatype.Money `json:"amount"`
+	Reference string         `json:"reference,omitempty"`
+	Error     *string        `json:"error,omitempty"`
+	CreatedAt time.Time      `json:"created_at"`
+}
+
+type OrderPaymentResponse struct {
+	Status   string            `json:"status"`
+	Captured datatype.Money    `json:"captured"`
+	Refunded datatype.Money    `json:"refunded"`
+	Attempts []PaymentResponse `json:"attempts"`
+}
+
+// OrderTaxLineResponse is the tax at one rate, in basis points. Net is the
+// price of the items taxed at it, after discounts and without tax.
+type OrderTaxLineResponse struct {
+	Rate int32          `json:"rate"`
+	Net  datatype.Money `json:"net"`
+	Tax  datatype.Money `json:"tax"`
+}
+
+type OrderTaxResponse struct {
+	Pricing  string                 `json:"pricing"`
+	Rounding string                 `json:"rounding"`
+	Lines    []OrderTaxLineResponse `json:"lines"`
+}
+
+// OrderResponse is an order with its price: subtotal is the sum of its
+// lines, discount the sum of their adjustments, tax the tax on them (already
+// in the prices when tax_breakdown.pricing is inclusive) and total what the
+// customer pays. tax_breakdown is null for an order cancelled while open.
 type OrderResponse struct {
 	transporthttp.NoOpRenderer
-	ID         string              `json:"id"`
-	Status     string              `json:"status"`
-	Items      []OrderItemResponse `json:"items"`
-	TotalCents int32               `json:"total_cents"`
+	ID           string               `json:"id"`
+	Status       string               `json:"status"`
+	Currency     string               `json:"currency"`
+	Items        []OrderItemResponse  `json:"items"`
+	Subtotal     datatype.Money       `json:"subtotal"`
+	Discount     datatype.Money       `json:"discount"`
+	Tax          datatype.Money       `json:"tax"`
+	Total        datatype.Money       `json:"total"`
+	TaxBreakdown *OrderTaxResponse    `json:"tax_breakdown"`
+	Payment      OrderPaymentResponse `json:"payment"`
+	CreatedAt    time.Time            `json:"created_at"`
+	SubmittedAt  *time.Time           `json:"submitted_at"`
+	PaidAt       *time.Time           `json:"paid_at"`
+	FulfilledAt  *time.Time           `json:"fulfilled_at"`
+	CancelledAt  *time.Time           `json:"cancelled_at"`
 }
 
-func OrderToResponse(o *domain.Order) *OrderResponse {
+// OrderToResponse fails only if one of the order's amounts is out of range,
+// which CanAddItem prevents for orders built through the domain.
+func OrderToResponse(o *domain.Order) (*OrderResponse, error) {
 	items := make([]OrderItemResponse, len(o.Items))
 	for i, item := range o.Items {
+		lineTotal, err := item.LineTotal()
+		if err != nil {
+			return nil, err
+		}
+		adjustments := []OrderAdjustmentResponse{}
+		for _, a := range o.ItemAdjustments(item.ID) {
+			var promotionID *string
+			if a.PromotionID != nil {
+				id := a.PromotionID.String()
+				promotionID = &id
+			}
+			adjustments = append(adjustments, OrderAdjustmentResponse{
+				PromotionID: promotionID,
+				Description: a.Description,
+				Amount:      a.Amount,
+			})
+		}
 		items[i] = OrderItemResponse{
-			ID:             item.ID.String(),
-			ProductID:      item.ProductID.String(),
-			Quantity:       item.Quantity,
-			PriceCents:     item.PriceCents,
-			LineTotalCents: item.LineTotalCents(),
+			ID:          item.ID.String(),
+			ProductID:   item.ProductID.String(),
+			ProductName: item.ProductName,
+			Quantity:    item.Quantity,
+			Options:     orderItemOptionsToResponse(item.Options),
+			UnitPrice:   item.UnitPrice,
+			LineTotal:   lineTotal,
+			Adjustments: adjustments,
+		}
+	}
+	attempts := make([]PaymentResponse, len(o.Payments))
+	for i, p := range o.Payments {
+		attempts[i] = PaymentResponse{
+			ID:        p.ID.String(),
+			Operation: string(p.Operation),
+			Status:    string(p.Status),
+			Amount:    p.Amount,
+			Reference: p.Reference,
+			Error:     p.Error,
+			CreatedAt: p.CreatedAt,
 		}
 	}
+	subtotal, err := o.Subtotal()
+	if err != nil {
+		return nil, err
+	}
+	discount, err := o.Discount()
+	if err != nil {
+		return nil, err
+	}
+	tax, err := o.TaxTotal()
+	if err != nil {
+		return nil, err
+	}
+	total, err := o.Total()
+	if err != nil {
+		return nil, err
+	}
+	var breakdown *OrderTaxResponse
+	if o.Tax != nil {
+		breakdown = &OrderTaxResponse{
+			Pricing:  string(o.Tax.Pricing),
+			Rounding: string(o.Tax.Rounding),
+			Lines:    make([]OrderTaxLineResponse, len(o.Tax.Lines)),
+		}
+		for i, line := range o.Tax.Lines {
+			breakdown.Lines[i] = OrderTaxLineResponse{Rate: line.Rate, Net: line.Net, Tax: line.Tax}
+		}
+	}
+	captured, err := o.Captured()
+	if err != nil {
+		return nil, err
+	}
+	refunded, err := o.Refunded()
+	if err != nil {
+		return nil, err
+	}
 	return &OrderResponse{
-		ID:         o.ID.String(),
-		Status:     string(o.Status),
-		Items:      items,
-		TotalCents: o.TotalCents(),
+		ID:           o.ID.String(),
+		Status:       string(o.Status),
+		Currency:     string(o.Currency),
+		Items:        items,
+		Subtotal:     subtotal,
+		Discount:     discount,
+		Tax:          tax,
+		Total:        total,
+		TaxBreakdown: breakdown,
+		Payment: OrderPaymentResponse{
+			Status:   string(o.PaymentStatus()),
+			Captured: captured,
+			Refunded: refunded,
+			Attempts: attempts,
+		},
+		CreatedAt:   o.CreatedAt,
+		SubmittedAt: o.SubmittedAt,
+		PaidAt:      o.PaidAt,
+		FulfilledAt: o.FulfilledAt,
+		CancelledAt: o.CancelledAt,
+	}, nil
+}
+
+// OrderStatusChangeResponse is one entry of GET /orders/{id}/history. From is
+// omitted for the entry recording the order being opened.
+type OrderStatusChangeResponse struct {
+	transporthttp.NoOpRenderer
+	From string    `json:"from,omitempty"`
+	To   string    `json:"to"`
+	At   time.Time `json:"at"`
+}
+
+func OrderStatusHistoryToResponse(history []domain.OrderStatusChange) []render.Renderer {
+	list := make([]render.Renderer, len(history))
+	for i, c := range history {
+		list[i] = &OrderStatusChangeResponse{From: string(c.From), To: string(c.To), At: c.At}
 	}
+	return list
 }
 
 type OrderItemCreatedResponse struct {
 	transporthttp.NoOpRenderer
-	ID             string `json:"id"`
-	ProductID      string `json:"product_id"`
-	Quantity       int32  `json:"quantity"`
-	PriceCents     int32  `json:"price_cents"`
-	LineTotalCents int32  `json:"line_total_cents"`
+	ID          string                    `json:"id"`
+	ProductID   string                    `json:"product_id"`
+	ProductName string                    `json:"product_name"`
+	Quantity    int32                     `json:"quantity"`
+	Options     []OrderItemOptionResponse `json:"options"`
+	UnitPrice   datatype.Money            `json:"unit_price"`
+	LineTotal   datatype.Money            `json:"line_total"`
 }
 
-func OrderItemToCreatedResponse(item *domain.OrderItem) *OrderItemCreatedResponse {
+func OrderItemToCreatedResponse(item *domain.OrderItem) (*OrderItemCreatedResponse, error) {
+	lineTotal, err := item.LineTotal()
+	if err != nil {
+		return nil, err
+	}
 	return &OrderItemCreatedResponse{
-		ID:             item.ID.String(),
-		ProductID:      item.ProductID.String(),
-		Quantity:       item.Quantity,
-		PriceCents:     item.PriceCents,
-		LineTotalCents: item.LineTotalCents(),
+		ID:          item.ID.String(),
+		ProductID:   item.ProductID.String(),
+		ProductName: item.ProductName,
+		Quantity:    item.Quantity,
+		Options:     orderItemOptionsToResponse(item.Options),
+		UnitPrice:   item.UnitPrice,
+		LineTotal:   lineTotal,
+	}, nil
+}
+
+// OrderEventResponse is the data of an order event on GET /orders/stream.
+type OrderEventResponse struct {
+	ID         string    `json:"id"`
+	OrderID    string    `json:"order_id"`
+	Type       string    `json:"type"`
+	Status     string    `json:"status"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+// OrderEventToSSE encodes an order event with its ID as the SSE event ID, so
+// a reconnecting client resumes after it, and its type as the event name.
+func OrderEventToSSE(e domain.OrderEvent) (transporthttp.SSEEvent, error) {
+	data, err := json.Marshal(OrderEventResponse{
+		ID:         e.ID.String(),
+		OrderID:    e.OrderID.String(),
+		Type:       string(e.Type),
+		Status:     string(e.Status),
+		OccurredAt: e.OccurredAt,
+	})
+	if err != nil {
+		return transporthttp.SSEEvent{}, err
 	}
+	return transporthttp.SSEEvent{ID: e.ID.String(), Event: string(e.Type), Data: data}, nil
+}
+
+// OrderResyncEvent is the name of the SSE event telling a resuming client it
+// missed more events than are replayed, so it refetches its orders.
+const OrderResyncEvent = "resync"
+
+// OrderResyncSSE encodes the resync event. It has no ID, so a client that
+// reconnects before the next live event resumes where it did before, and its
+// data is an empty object because EventSource drops events without data.
+func OrderResyncSSE() transporthttp.SSEEvent {
+	return transporthttp.SSEEvent{Event: OrderResyncEvent, Data: []byte("{}")}
 }
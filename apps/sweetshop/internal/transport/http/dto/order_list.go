@@ -0,0 +1,92 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/bbsbb/go-edge/core/datatype"
+	transporthttp "github.com/bbsbb/go-edge/core/transport/http"
+	"github.com/bbsbb/go-edge/sweetshop/internal/domain"
+)
+
+// OrderListResponse is a page of orders, newest first. NextCursor is passed
+// as cursor to get the following page; it is null on the last one.
+type OrderListResponse struct {
+	transporthttp.NoOpRenderer
+	Orders     []OrderSummaryResponse `json:"orders"`
+	NextCursor *string                `json:"next_cursor"`
+}
+
+// OrderSummaryResponse is an order as listed. Subtotal is the sum of its
+// lines; total is what the customer pays, fixed when the order was
+// submitted and null while it is open. GET /orders/{id} prices open orders.
+type OrderSummaryResponse struct {
+	ID          string                     `json:"id"`
+	Status      string                     `json:"status"`
+	Currency    string                     `json:"currency"`
+	Items       []OrderSummaryItemResponse `json:"items"`
+	Subtotal    datatype.Money             `json:"subtotal"`
+	Total       *datatype.Money            `json:"total"`
+	CreatedAt   time.Time                  `json:"created_at"`
+	SubmittedAt *time.Time                 `json:"submitted_at"`
+	PaidAt      *time.Time                 `json:"paid_at"`
+	FulfilledAt *time.Time                 `json:"fulfilled_at"`
+	CancelledAt *time.Time                 `json:"cancelled_at"`
+}
+
+type OrderSummaryItemResponse struct {
+	ID          string                    `json:"id"`
+	ProductID   string                    `json:"product_id"`
+	ProductName string                    `json:"product_name"`
+	Quantity    int32                     `json:"quantity"`
+	Options     []OrderItemOptionResponse `json:"options"`
+	UnitPrice   datatype.Money            `json:"unit_price"`
+	LineTotal   datatype.Money            `json:"line_total"`
+}
+
+// OrderPageToResponse fails only if one of the orders' amounts is out of
+// range; see OrderToResponse.
+func OrderPageToResponse(page *domain.OrderPage) (*OrderListResponse, error) {
+	orders := make([]OrderSummaryResponse, len(page.Orders))
+	for i, summary := range page.Orders {
+		o := summary.Order
+		items := make([]OrderSummaryItemResponse, len(o.Items))
+		for j, item := range o.Items {
+			lineTotal, err := item.LineTotal()
+			if err != nil {
+				return nil, err
+			}
+			items[j] = OrderSummaryItemResponse{
+				ID:          item.ID.String(),
+				ProductID:   item.ProductID.String(),
+				ProductName: item.ProductName,
+				Quantity:    item.Quantity,
+				Options:     orderItemOptionsToResponse(item.Options),
+				UnitPrice:   item.UnitPrice,
+				LineTotal:   lineTotal,
+			}
+		}
+		subtotal, err := o.Subtotal()
+		if err != nil {
+			return nil, err
+		}
+		orders[i] = OrderSummaryResponse{
+			ID:          o.ID.String(),
+			Status:      string(o.Status),
+			Currency:    string(o.Currency),
+			Items:       items,
+			Subtotal:    subtotal,
+			Total:       summary.Total,
+			CreatedAt:   o.CreatedAt,
+			SubmittedAt: o.SubmittedAt,
+			PaidAt:      o.PaidAt,
+			FulfilledAt: o.FulfilledAt,
+			CancelledAt: o.CancelledAt,
+		}
+	}
+	var next *string
+	if page.Next != nil {
+		cursor := page.Next.String()
+		next = &cursor
+	}
+	return &OrderListResponse{Orders: orders, NextCursor: next}, nil
+}
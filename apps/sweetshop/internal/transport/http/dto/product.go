@@ -4,38 +4,79 @@ package dto
 import (
 	"github.com/go-chi/render"
 
+	"github.com/bbsbb/go-edge/core/datatype"
 	transporthttp "github.com/bbsbb/go-edge/core/transport/http"
 	"github.com/bbsbb/go-edge/sweetshop/internal/domain"
 )
 
+// CreateProductRequest prices the product in the organization's currency;
+// a price in any other currency is rejected. The category must be one of the
+// organization's; active defaults to true.
 type CreateProductRequest struct {
 	transporthttp.NoOpBinder
-	Name       string `json:"name"`
-	Category   string `json:"category"`
-	PriceCents int32  `json:"price_cents"`
+	Name        string                 `json:"name" validate:"required,max=200"`
+	Category    domain.ProductCategory `json:"category" validate:"required,max=64"`
+	Price       datatype.Money         `json:"price" validate:"gt=0"`
+	SKU         *string                `json:"sku,omitempty" validate:"omitempty,min=1,max=64"`
+	Description string                 `json:"description,omitempty" validate:"max=2000"`
+	Active      *bool                  `json:"active,omitempty"`
+	SortOrder   int32                  `json:"sort_order,omitempty"`
 }
 
+// Fields returns the product the request describes, without identity.
+func (r *CreateProductRequest) Fields() *domain.Product {
+	return &domain.Product{
+		Name:        r.Name,
+		Category:    r.Category,
+		Price:       r.Price,
+		SKU:         r.SKU,
+		Description: r.Description,
+		Active:      r.Active == nil || *r.Active,
+		SortOrder:   r.SortOrder,
+	}
+}
+
+// UpdateProductRequest replaces all of the product's fields; an omitted sku
+// or description is cleared and an omitted active is true.
 type UpdateProductRequest struct {
 	transporthttp.NoOpBinder
-	Name       string `json:"name"`
-	Category   string `json:"category"`
-	PriceCents int32  `json:"price_cents"`
+	Name        string                 `json:"name" validate:"required,max=200"`
+	Category    domain.ProductCategory `json:"category" validate:"required,max=64"`
+	Price       datatype.Money         `json:"price" validate:"gt=0"`
+	SKU         *string                `json:"sku,omitempty" validate:"omitempty,min=1,max=64"`
+	Description string                 `json:"description,omitempty" validate:"max=2000"`
+	Active      *bool                  `json:"active,omitempty"`
+	SortOrder   int32                  `json:"sort_order,omitempty"`
 }
 
+func (r *UpdateProductRequest) Fields() *domain.Product {
+	return (*CreateProductRequest)(r).Fields()
+}
+
+// ProductResponse lists inactive products too; they cannot be added to
+// orders.
 type ProductResponse struct {
 	transporthttp.NoOpRenderer
-	ID         string `json:"id"`
-	Name       string `json:"name"`
-	Category   string `json:"category"`
-	PriceCents int32  `json:"price_cents"`
+	ID          string         `json:"id"`
+	Name        string         `json:"name"`
+	Category    string         `json:"category"`
+	Price       datatype.Money `json:"price"`
+	SKU         *string        `json:"sku"`
+	Description string         `json:"description"`
+	Active      bool           `json:"active"`
+	SortOrder   int32          `json:"sort_order"`
 }
 
 func ProductToResponse(p *domain.Product) *ProductResponse {
 	return &ProductResponse{
-		ID:         p.ID.String(),
-		Name:       p.Name,
-		Category:   string(p.Category),
-		PriceCents: p.PriceCents,
+		ID:          p.ID.String(),
+		Name:        p.Name,
+		Category:    string(p.Category),
+		Price:       p.Price,
+		SKU:         p.SKU,
+		Description: p.Description,
+		Active:      p.Active,
+		SortOrder:   p.SortOrder,
 	}
 }
 
@@ -46,3 +87,28 @@ func ProductListToResponse(products []*domain.Product) []render.Renderer {
 	}
 	return list
 }
+
+// ProductSearchResultResponse is a product found by GET /products/search.
+// The highlights are HTML escaped, with the matched words wrapped in <mark>
+// tags; description_highlight is an excerpt of the description.
+type ProductSearchResultResponse struct {
+	ProductResponse
+	Rank                 float32 `json:"rank"`
+	Similarity           float32 `json:"similarity"`
+	NameHighlight        string  `json:"name_highlight"`
+	DescriptionHighlight string  `json:"description_highlight"`
+}
+
+func ProductMatchesToResponse(matches []domain.ProductMatch) []render.Renderer {
+	list := make([]render.Renderer, len(matches))
+	for i, m := range matches {
+		list[i] = &ProductSearchResultResponse{
+			ProductResponse:      *ProductToResponse(m.Product),
+			Rank:                 m.Rank,
+			Similarity:           m.Similarity,
+			NameHighlight:        m.NameHighlight,
+			DescriptionHighlight: m.DescriptionHighlight,
+		}
+	}
+	return list
+}
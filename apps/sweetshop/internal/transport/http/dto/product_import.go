@@ -0,0 +1,244 @@
+package dto
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+
+	coredomain "github.com/bbsbb/go-edge/core/domain"
+	transporthttp "github.com/bbsbb/go-edge/core/transport/http"
+	"github.com/bbsbb/go-edge/sweetshop/internal/domain"
+)
+
+// ProductRow is a product as POST /products/import reads it and GET
+// /products/export writes it: one NDJSON line, or one CSV row under a header
+// of the field names with price as a JSON cell. It is bound and validated as
+// a CreateProductRequest.
+type ProductRow struct {
+	CreateProductRequest
+	transporthttp.NoOpRenderer
+}
+
+var (
+	productRowColumns         = []string{"name", "category", "price", "sku", "description", "active", "sort_order"}
+	productRowRequiredColumns = []string{"name", "category", "price"}
+)
+
+func ProductRowToResponse(p *domain.Product) *ProductRow {
+	return &ProductRow{CreateProductRequest: CreateProductRequest{
+		Name:        p.Name,
+		Category:    p.Category,
+		Price:       p.Price,
+		SKU:         p.SKU,
+		Description: p.Description,
+		Active:      &p.Active,
+		SortOrder:   p.SortOrder,
+	}}
+}
+
+// ReadProductRowsNDJSON reads one product per line of body, skipping blank
+// lines. A line that is not a valid product becomes a row with its error.
+func ReadProductRowsNDJSON(ctx context.Context, body io.Reader) ([]domain.ProductImportRow, error) {
+	var rows []domain.ProductImportRow
+	sc := bufio.NewScanner(body)
+	sc.Buffer(nil, 64*1024)
+	for line := 1; sc.Scan(); line++ {
+		data := bytes.TrimSpace(sc.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+		rows = append(rows, bindProductRow(ctx, line, data))
+	}
+	if err := sc.Err(); err != nil {
+		return nil, readError(err)
+	}
+	return rows, nil
+}
+
+// ReadProductRowsCSV reads one product per record of body after a header
+// naming its columns, which must include name, category and price. Empty
+// sku, active and sort_order cells are left out. Rows are numbered by the
+// line they start on, the header being line 1.
+func ReadProductRowsCSV(ctx context.Context, body io.Reader) ([]domain.ProductImportRow, error) {
+	cr := csv.NewReader(body)
+	header, err := cr.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, coredomain.NewError(coredomain.CodeValidation, "a CSV import needs a header row")
+	}
+	if err != nil {
+		return nil, readError(err)
+	}
+	if err := checkProductRowHeader(header); err != nil {
+		return nil, err
+	}
+
+	var rows []domain.ProductImportRow
+	for {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			return rows, nil
+		}
+		if err != nil && !errors.Is(err, csv.ErrFieldCount) {
+			return nil, readError(err)
+		}
+		line, _ := cr.FieldPos(0)
+		if err != nil {
+			rows = append(rows, domain.ProductImportRow{Line: line, Err: coredomain.NewError(coredomain.CodeValidation,
+				fmt.Sprintf("row has %d fields, the header has %d", len(record), len(header)))})
+			continue
+		}
+		data, err := productRowJSON(header, record)
+		if err != nil {
+			rows = append(rows, domain.ProductImportRow{Line: line, Err: err})
+			continue
+		}
+		rows = append(rows, bindProductRow(ctx, line, data))
+	}
+}
+
+func checkProductRowHeader(header []string) error {
+	for i, name := range header {
+		if !slices.Contains(productRowColumns, name) {
+			return coredomain.NewError(coredomain.CodeValidation, fmt.Sprintf("unknown CSV column %q", name))
+		}
+		if slices.Contains(header[:i], name) {
+			return coredomain.NewError(coredomain.CodeValidation, fmt.Sprintf("CSV column %q is repeated", name))
+		}
+	}
+	for _, name := range productRowRequiredColumns {
+		if !slices.Contains(header, name) {
+			return coredomain.NewError(coredomain.CodeValidation, fmt.Sprintf("CSV column %q is required", name))
+		}
+	}
+	return nil
+}
+
+// productRowJSON turns a CSV record into the JSON object of its row, so that
+// both formats are checked by the same binding.
+func productRowJSON(header, record []string) ([]byte, error) {
+	obj := make(map[string]any, len(header))
+	for i, name := range header {
+		cell := record[i]
+		switch name {
+		case "price":
+			if cell == "" {
+				continue
+			}
+			if !json.Valid([]byte(cell)) {
+				return nil, cellError(name, "syntax", "must be a JSON object")
+			}
+			obj[name] = json.RawMessage(cell)
+		case "active":
+			if cell == "" {
+				continue
+			}
+			v, err := strconv.ParseBool(cell)
+			if err != nil {
+				return nil, cellError(name, "type", "must be of type boolean")
+			}
+			obj[name] = v
+		case "sort_order":
+			if cell == "" {
+				continue
+			}
+			v, err := strconv.ParseInt(cell, 10, 32)
+			if err != nil {
+				return nil, cellError(name, "type", "must be of type integer")
+			}
+			obj[name] = v
+		case "sku":
+			if cell == "" {
+				continue
+			}
+			obj[name] = cell
+		default:
+			obj[name] = cell
+		}
+	}
+	return json.Marshal(obj)
+}
+
+func bindProductRow(ctx context.Context, line int, data []byte) domain.ProductImportRow {
+	var row ProductRow
+	if err := transporthttp.BindBytes(ctx, data, &row); err != nil {
+		if fieldErrs, ok := coredomain.MetaValue(err, coredomain.MetaFieldErrors); ok {
+			err = rowError(fieldErrs...)
+		}
+		return domain.ProductImportRow{Line: line, Err: err}
+	}
+	return domain.ProductImportRow{Line: line, Fields: row.Fields()}
+}
+
+func cellError(column, rule, message string) error {
+	return rowError(coredomain.FieldError{Pointer: "/" + column, Rule: rule, Message: message})
+}
+
+func rowError(fieldErrs ...coredomain.FieldError) error {
+	err := coredomain.NewError(coredomain.CodeValidation, "invalid row")
+	return coredomain.WithMeta(err, coredomain.MetaFieldErrors, fieldErrs)
+}
+
+// readError reports an import body that cannot be read any further.
+func readError(err error) error {
+	var (
+		maxBytesErr *http.MaxBytesError
+		parseErr    *csv.ParseError
+	)
+	switch {
+	case errors.As(err, &maxBytesErr):
+		return coredomain.NewError(coredomain.CodeValidation, fmt.Sprintf("request body exceeds %d bytes", maxBytesErr.Limit))
+	case errors.As(err, &parseErr):
+		return coredomain.NewError(coredomain.CodeValidation, fmt.Sprintf("malformed CSV on line %d: %v", parseErr.Line, parseErr.Err))
+	case errors.Is(err, bufio.ErrTooLong):
+		return coredomain.NewError(coredomain.CodeValidation, "an NDJSON line exceeds 64 KiB")
+	}
+	return err
+}
+
+// ProductImportReportResponse lists the rows that were not imported by their
+// line; the others were, or would be in a dry run.
+type ProductImportReportResponse struct {
+	transporthttp.NoOpRenderer
+	DryRun  bool                         `json:"dry_run"`
+	Rows    int                          `json:"rows"`
+	Created int                          `json:"created"`
+	Updated int                          `json:"updated"`
+	Errors  []ProductImportErrorResponse `json:"errors"`
+}
+
+// ProductImportErrorResponse carries the invalid fields of the row, pointed
+// to as in a JSON body, when there are any.
+type ProductImportErrorResponse struct {
+	Line    int                     `json:"line"`
+	Message string                  `json:"message"`
+	Errors  []coredomain.FieldError `json:"errors,omitempty"`
+}
+
+func ProductImportReportToResponse(report *domain.ProductImportReport) *ProductImportReportResponse {
+	resp := &ProductImportReportResponse{
+		DryRun:  report.DryRun,
+		Rows:    report.Rows,
+		Created: report.Created,
+		Updated: report.Updated,
+		Errors:  make([]ProductImportErrorResponse, len(report.Errors)),
+	}
+	for i, e := range report.Errors {
+		resp.Errors[i] = ProductImportErrorResponse{Line: e.Line, Message: e.Err.Error()}
+		var domainErr *coredomain.Error
+		if errors.As(e.Err, &domainErr) {
+			resp.Errors[i].Message = domainErr.Message
+		}
+		if fieldErrs, ok := coredomain.MetaValue(e.Err, coredomain.MetaFieldErrors); ok {
+			resp.Errors[i].Errors = fieldErrs
+		}
+	}
+	return resp
+}
@@ -0,0 +1,155 @@
+package dto
+
+import (
+	"github.com/google/uuid"
+
+	"github.com/bbsbb/go-edge/core/datatype"
+	coredomain "github.com/bbsbb/go-edge/core/domain"
+	transporthttp "github.com/bbsbb/go-edge/core/transport/http"
+	"github.com/bbsbb/go-edge/sweetshop/internal/domain"
+)
+
+// ProductOptionsRequest is the body of PUT /products/{id}/options. It
+// replaces all of the product's option groups; groups and options sent with
+// the id of an existing one keep it.
+type ProductOptionsRequest struct {
+	transporthttp.NoOpBinder
+	Groups []OptionGroupRequest `json:"groups" validate:"max=20,dive"`
+}
+
+// OptionGroupRequest leaves the selection bounds of a variant to default to
+// exactly one option.
+type OptionGroupRequest struct {
+	ID            *string                `json:"id,omitempty" validate:"omitempty,uuid"`
+	Name          string                 `json:"name" validate:"required,max=200"`
+	Kind          domain.OptionGroupKind `json:"kind" validate:"stringenum"`
+	MinSelections int32                  `json:"min_selections,omitempty" validate:"min=0"`
+	MaxSelections int32                  `json:"max_selections,omitempty" validate:"min=0"`
+	SortOrder     int32                  `json:"sort_order,omitempty"`
+	Options       []OptionRequest        `json:"options" validate:"max=50,dive"`
+}
+
+// OptionRequest prices the option in the organization's currency. Its
+// price_delta may be zero or negative.
+type OptionRequest struct {
+	ID         *string        `json:"id,omitempty" validate:"omitempty,uuid"`
+	Name       string         `json:"name" validate:"required,max=200"`
+	PriceDelta datatype.Money `json:"price_delta"`
+	SortOrder  int32          `json:"sort_order,omitempty"`
+}
+
+func (r *ProductOptionsRequest) OptionGroups() ([]domain.OptionGroup, error) {
+	groups := make([]domain.OptionGroup, len(r.Groups))
+	for i, g := range r.Groups {
+		id, err := parseOptionalID(g.ID)
+		if err != nil {
+			return nil, err
+		}
+		groups[i] = domain.OptionGroup{
+			ID:            id,
+			Name:          g.Name,
+			Kind:          g.Kind,
+			MinSelections: g.MinSelections,
+			MaxSelections: g.MaxSelections,
+			SortOrder:     g.SortOrder,
+			Options:       make([]domain.ProductOption, len(g.Options)),
+		}
+		if g.Kind == domain.OptionGroupVariant && g.MinSelections == 0 && g.MaxSelections == 0 {
+			groups[i].MinSelections, groups[i].MaxSelections = 1, 1
+		}
+		for j, o := range g.Options {
+			id, err := parseOptionalID(o.ID)
+			if err != nil {
+				return nil, err
+			}
+			groups[i].Options[j] = domain.ProductOption{
+				ID:         id,
+				Name:       o.Name,
+				PriceDelta: o.PriceDelta,
+				SortOrder:  o.SortOrder,
+			}
+		}
+	}
+	return groups, nil
+}
+
+// parseOptionalID returns uuid.Nil when s is nil.
+func parseOptionalID(s *string) (uuid.UUID, error) {
+	if s == nil {
+		return uuid.Nil, nil
+	}
+	id, err := coredomain.ParseID(*s)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return id.UUID(), nil
+}
+
+type ProductOptionsResponse struct {
+	transporthttp.NoOpRenderer
+	Groups []OptionGroupResponse `json:"groups"`
+}
+
+type OptionGroupResponse struct {
+	ID            string           `json:"id"`
+	Name          string           `json:"name"`
+	Kind          string           `json:"kind"`
+	MinSelections int32            `json:"min_selections"`
+	MaxSelections int32            `json:"max_selections"`
+	SortOrder     int32            `json:"sort_order"`
+	Options       []OptionResponse `json:"options"`
+}
+
+type OptionResponse struct {
+	ID         string         `json:"id"`
+	Name       string         `json:"name"`
+	PriceDelta datatype.Money `json:"price_delta"`
+	SortOrder  int32          `json:"sort_order"`
+}
+
+func ProductOptionsToResponse(groups []domain.OptionGroup) *ProductOptionsResponse {
+	resp := &ProductOptionsResponse{Groups: make([]OptionGroupResponse, len(groups))}
+	for i, g := range groups {
+		options := make([]OptionResponse, len(g.Options))
+		for j, o := range g.Options {
+			options[j] = OptionResponse{
+				ID:         o.ID.String(),
+				Name:       o.Name,
+				PriceDelta: o.PriceDelta,
+				SortOrder:  o.SortOrder,
+			}
+		}
+		resp.Groups[i] = OptionGroupResponse{
+			ID:            g.ID.String(),
+			Name:          g.Name,
+			Kind:          string(g.Kind),
+			MinSelections: g.MinSelections,
+			MaxSelections: g.MaxSelections,
+			SortOrder:     g.SortOrder,
+			Options:       options,
+		}
+	}
+	return resp
+}
+
+// OrderItemOptionResponse is an option chosen for an order item, as it was
+// when the item was added.
+type OrderItemOptionResponse struct {
+	OptionID   string         `json:"option_id"`
+	Group      string         `json:"group"`
+	Name       string         `json:"name"`
+	PriceDelta datatype.Money `json:"price_delta"`
+}
+
+func orderItemOptionsToResponse(options []domain.OrderItemOption) []OrderItemOptionResponse {
+	resp := make([]OrderItemOptionResponse, len(options))
+	for i, o := range options {
+		resp[i] = OrderItemOptionResponse{
+			OptionID:   o.OptionID.String(),
+			Group:      o.Group,
+			Name:       o.Name,
+			PriceDelta: o.PriceDelta,
+		}
+	}
+	return resp
+}
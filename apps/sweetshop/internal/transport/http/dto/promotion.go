@@ -0,0 +1,93 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/go-chi/render"
+
+	"github.com/bbsbb/go-edge/core/datatype"
+	transporthttp "github.com/bbsbb/go-edge/core/transport/http"
+	"github.com/bbsbb/go-edge/sweetshop/internal/domain"
+)
+
+// PromotionRequest is the body of POST /promotions and PUT
+// /promotions/{id}. Only the fields of the kind are set: percent_off for
+// percentage, amount_off for fixed_amount, buy_quantity and get_quantity for
+// buy_x_get_y.
+type PromotionRequest struct {
+	transporthttp.NoOpBinder
+	Name        string                  `json:"name" validate:"required,max=200"`
+	Kind        domain.PromotionKind    `json:"kind" validate:"stringenum"`
+	Category    *domain.ProductCategory `json:"category,omitempty" validate:"omitempty,max=64"`
+	PercentOff  int32                   `json:"percent_off,omitempty" validate:"omitempty,min=1,max=100"`
+	AmountOff   *datatype.Money         `json:"amount_off,omitempty" validate:"omitempty,gt=0"`
+	BuyQuantity int32                   `json:"buy_quantity,omitempty" validate:"omitempty,min=1"`
+	GetQuantity int32                   `json:"get_quantity,omitempty" validate:"omitempty,min=1"`
+	StartsAt    *time.Time              `json:"starts_at,omitempty"`
+	EndsAt      *time.Time              `json:"ends_at,omitempty"`
+	UsageLimit  *int32                  `json:"usage_limit,omitempty" validate:"omitempty,min=1"`
+}
+
+// Rule returns the promotion the request describes, without identity.
+func (r *PromotionRequest) Rule() *domain.Promotion {
+	return &domain.Promotion{
+		Name:        r.Name,
+		Kind:        r.Kind,
+		Category:    r.Category,
+		PercentOff:  r.PercentOff,
+		AmountOff:   r.AmountOff,
+		BuyQuantity: r.BuyQuantity,
+		GetQuantity: r.GetQuantity,
+		StartsAt:    r.StartsAt,
+		EndsAt:      r.EndsAt,
+		UsageLimit:  r.UsageLimit,
+	}
+}
+
+type PromotionResponse struct {
+	transporthttp.NoOpRenderer
+	ID          string          `json:"id"`
+	Name        string          `json:"name"`
+	Kind        string          `json:"kind"`
+	Category    *string         `json:"category"`
+	PercentOff  int32           `json:"percent_off,omitempty"`
+	AmountOff   *datatype.Money `json:"amount_off,omitempty"`
+	BuyQuantity int32           `json:"buy_quantity,omitempty"`
+	GetQuantity int32           `json:"get_quantity,omitempty"`
+	StartsAt    *time.Time      `json:"starts_at"`
+	EndsAt      *time.Time      `json:"ends_at"`
+	UsageLimit  *int32          `json:"usage_limit"`
+	UsageCount  int32           `json:"usage_count"`
+	CreatedAt   time.Time       `json:"created_at"`
+}
+
+func PromotionToResponse(p *domain.Promotion) *PromotionResponse {
+	var category *string
+	if p.Category != nil {
+		c := string(*p.Category)
+		category = &c
+	}
+	return &PromotionResponse{
+		ID:          p.ID.String(),
+		Name:        p.Name,
+		Kind:        string(p.Kind),
+		Category:    category,
+		PercentOff:  p.PercentOff,
+		AmountOff:   p.AmountOff,
+		BuyQuantity: p.BuyQuantity,
+		GetQuantity: p.GetQuantity,
+		StartsAt:    p.StartsAt,
+		EndsAt:      p.EndsAt,
+		UsageLimit:  p.UsageLimit,
+		UsageCount:  p.UsageCount,
+		CreatedAt:   p.CreatedAt,
+	}
+}
+
+func PromotionListToResponse(promotions []*domain.Promotion) []render.Renderer {
+	list := make([]render.Renderer, len(promotions))
+	for i, p := range promotions {
+		list[i] = PromotionToResponse(p)
+	}
+	return list
+}
@@ -0,0 +1,108 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/bbsbb/go-edge/core/datatype"
+	transporthttp "github.com/bbsbb/go-edge/core/transport/http"
+	"github.com/bbsbb/go-edge/sweetshop/internal/domain"
+)
+
+// SalesReportResponse is an organization's sales from from to to, both
+// included, oldest period first. Days are UTC dates.
+type SalesReportResponse struct {
+	transporthttp.NoOpRenderer
+	From        string                `json:"from"`
+	To          string                `json:"to"`
+	Granularity string                `json:"granularity"`
+	Periods     []SalesPeriodResponse `json:"periods"`
+}
+
+// SalesPeriodResponse is the sales of one period in one currency. start is
+// the period's first day, which for weeks and months can fall before the
+// report's from. Orders are the orders fulfilled in the period; discount is
+// negative, revenue is what customers paid and refunded what was refunded to
+// them in the period.
+type SalesPeriodResponse struct {
+	Start             string         `json:"start"`
+	Currency          string         `json:"currency"`
+	Orders            int64          `json:"orders"`
+	Units             int64          `json:"units"`
+	Subtotal          datatype.Money `json:"subtotal"`
+	Discount          datatype.Money `json:"discount"`
+	Tax               datatype.Money `json:"tax"`
+	Revenue           datatype.Money `json:"revenue"`
+	Refunded          datatype.Money `json:"refunded"`
+	NetRevenue        datatype.Money `json:"net_revenue"`
+	AverageOrderValue datatype.Money `json:"average_order_value"`
+}
+
+// TopProductsResponse ranks the products that sold the most from from to
+// to, both included, by units or revenue. Revenue is in the organization's
+// currency; sales in other currencies are left out.
+type TopProductsResponse struct {
+	transporthttp.NoOpRenderer
+	From     string                 `json:"from"`
+	To       string                 `json:"to"`
+	By       string                 `json:"by"`
+	Products []ProductSalesResponse `json:"products"`
+}
+
+type ProductSalesResponse struct {
+	ProductID   string         `json:"product_id"`
+	ProductName string         `json:"product_name"`
+	Units       int64          `json:"units"`
+	Revenue     datatype.Money `json:"revenue"`
+}
+
+// SalesReportToResponse fails only if a period's net revenue is out of range.
+func SalesReportToResponse(report *domain.SalesReport) (*SalesReportResponse, error) {
+	periods := make([]SalesPeriodResponse, len(report.Periods))
+	for i, p := range report.Periods {
+		net, err := p.NetRevenue()
+		if err != nil {
+			return nil, err
+		}
+		average, err := p.AverageOrderValue()
+		if err != nil {
+			return nil, err
+		}
+		periods[i] = SalesPeriodResponse{
+			Start:             p.Start.Format(time.DateOnly),
+			Currency:          string(p.Revenue.Currency()),
+			Orders:            p.Orders,
+			Units:             p.Units,
+			Subtotal:          p.Subtotal,
+			Discount:          p.Discount,
+			Tax:               p.Tax,
+			Revenue:           p.Revenue,
+			Refunded:          p.Refunded,
+			NetRevenue:        net,
+			AverageOrderValue: average,
+		}
+	}
+	return &SalesReportResponse{
+		From:        report.From.Format(time.DateOnly),
+		To:          report.To.Format(time.DateOnly),
+		Granularity: string(report.Granularity),
+		Periods:     periods,
+	}, nil
+}
+
+func TopProductsToResponse(report *domain.TopProductsReport) *TopProductsResponse {
+	products := make([]ProductSalesResponse, len(report.Products))
+	for i, p := range report.Products {
+		products[i] = ProductSalesResponse{
+			ProductID:   p.ProductID.String(),
+			ProductName: p.ProductName,
+			Units:       p.Units,
+			Revenue:     p.Revenue,
+		}
+	}
+	return &TopProductsResponse{
+		From:     report.From.Format(time.DateOnly),
+		To:       report.To.Format(time.DateOnly),
+		By:       string(report.By),
+		Products: products,
+	}
+}
@@ -0,0 +1,77 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/bbsbb/go-edge/core/datatype"
+	transporthttp "github.com/bbsbb/go-edge/core/transport/http"
+	"github.com/bbsbb/go-edge/sweetshop/internal/domain"
+)
+
+type UpdateSettingsRequest struct {
+	transporthttp.NoOpBinder
+	Currency datatype.Currency `json:"currency" validate:"stringenum"`
+}
+
+type SettingsResponse struct {
+	transporthttp.NoOpRenderer
+	Currency  string    `json:"currency"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func SettingsToResponse(s *domain.OrganizationSettings) *SettingsResponse {
+	return &SettingsResponse{Currency: string(s.Currency), UpdatedAt: s.UpdatedAt}
+}
+
+// TaxProfileRequest is the body of PUT /settings/tax. Rates are in basis
+// points (2000 is 20%); categories missing from rates are taxed at
+// default_rate.
+type TaxProfileRequest struct {
+	transporthttp.NoOpBinder
+	Pricing     domain.TaxPricing                `json:"pricing" validate:"stringenum"`
+	Rounding    domain.TaxRounding               `json:"rounding" validate:"stringenum"`
+	DefaultRate int32                            `json:"default_rate" validate:"min=0,max=10000"`
+	Rates       map[domain.ProductCategory]int32 `json:"rates" validate:"dive,keys,required,max=64,endkeys,min=0,max=10000"`
+}
+
+func (r *TaxProfileRequest) Profile() *domain.TaxProfile {
+	rates := make(map[domain.ProductCategory]int32, len(r.Rates))
+	for category, rate := range r.Rates {
+		rates[category] = rate
+	}
+	return &domain.TaxProfile{
+		Pricing:     r.Pricing,
+		Rounding:    r.Rounding,
+		DefaultRate: r.DefaultRate,
+		Rates:       rates,
+	}
+}
+
+type TaxProfileResponse struct {
+	transporthttp.NoOpRenderer
+	Pricing     string           `json:"pricing"`
+	Rounding    string           `json:"rounding"`
+	DefaultRate int32            `json:"default_rate"`
+	Rates       map[string]int32 `json:"rates"`
+	UpdatedAt   *time.Time       `json:"updated_at"`
+}
+
+// TaxProfileToResponse leaves updated_at null for the default profile of an
+// organization that has not set one.
+func TaxProfileToResponse(p *domain.TaxProfile) *TaxProfileResponse {
+	rates := make(map[string]int32, len(p.Rates))
+	for category, rate := range p.Rates {
+		rates[string(category)] = rate
+	}
+	var updatedAt *time.Time
+	if !p.UpdatedAt.IsZero() {
+		updatedAt = &p.UpdatedAt
+	}
+	return &TaxProfileResponse{
+		Pricing:     string(p.Pricing),
+		Rounding:    string(p.Rounding),
+		DefaultRate: p.DefaultRate,
+		Rates:       rates,
+		UpdatedAt:   updatedAt,
+	}
+}
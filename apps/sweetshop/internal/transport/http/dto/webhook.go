@@ -0,0 +1,117 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/go-chi/render"
+
+	transporthttp "github.com/bbsbb/go-edge/core/transport/http"
+	"github.com/bbsbb/go-edge/sweetshop/internal/domain"
+)
+
+type CreateWebhookEndpointRequest struct {
+	transporthttp.NoOpBinder
+	URL        string                    `json:"url" validate:"required,http_url,max=2048"`
+	EventTypes []domain.WebhookEventType `json:"event_types" validate:"required,min=1,dive,stringenum"`
+}
+
+type UpdateWebhookEndpointRequest struct {
+	transporthttp.NoOpBinder
+	URL        string                    `json:"url" validate:"required,http_url,max=2048"`
+	EventTypes []domain.WebhookEventType `json:"event_types" validate:"required,min=1,dive,stringenum"`
+	Enabled    *bool                     `json:"enabled" validate:"required"`
+}
+
+type WebhookEndpointResponse struct {
+	transporthttp.NoOpRenderer
+	ID                  string     `json:"id"`
+	URL                 string     `json:"url"`
+	EventTypes          []string   `json:"event_types"`
+	Enabled             bool       `json:"enabled"`
+	ConsecutiveFailures int32      `json:"consecutive_failures"`
+	DisabledAt          *time.Time `json:"disabled_at"`
+	CreatedAt           time.Time  `json:"created_at"`
+}
+
+// WebhookEndpointCreatedResponse is the only response that carries the
+// signing secret.
+type WebhookEndpointCreatedResponse struct {
+	WebhookEndpointResponse
+	Secret string `json:"secret"`
+}
+
+func WebhookEndpointToResponse(e *domain.WebhookEndpoint) *WebhookEndpointResponse {
+	types := make([]string, len(e.EventTypes))
+	for i, t := range e.EventTypes {
+		types[i] = string(t)
+	}
+	return &WebhookEndpointResponse{
+		ID:                  e.ID.String(),
+		URL:                 e.URL,
+		EventTypes:          types,
+		Enabled:             e.Enabled,
+		ConsecutiveFailures: e.ConsecutiveFailures,
+		DisabledAt:          e.DisabledAt,
+		CreatedAt:           e.CreatedAt,
+	}
+}
+
+func WebhookEndpointToCreatedResponse(e *domain.WebhookEndpoint) *WebhookEndpointCreatedResponse {
+	return &WebhookEndpointCreatedResponse{
+		WebhookEndpointResponse: *WebhookEndpointToResponse(e),
+		Secret:                  e.Secret,
+	}
+}
+
+func WebhookEndpointListToResponse(endpoints []*domain.WebhookEndpoint) []render.Renderer {
+	list := make([]render.Renderer, len(endpoints))
+	for i, e := range endpoints {
+		list[i] = WebhookEndpointToResponse(e)
+	}
+	return list
+}
+
+// WebhookDeliveryResponse is one entry of an endpoint's delivery log.
+type WebhookDeliveryResponse struct {
+	transporthttp.NoOpRenderer
+	ID             string          `json:"id"`
+	EventID        string          `json:"event_id"`
+	EventType      string          `json:"event_type"`
+	Status         string          `json:"status"`
+	Attempts       int32           `json:"attempts"`
+	NextAttemptAt  *time.Time      `json:"next_attempt_at"`
+	LastAttemptAt  *time.Time      `json:"last_attempt_at"`
+	ResponseStatus *int32          `json:"response_status"`
+	LastError      *string         `json:"last_error"`
+	Payload        json.RawMessage `json:"payload"`
+	CreatedAt      time.Time       `json:"created_at"`
+}
+
+func WebhookDeliveryToResponse(d domain.WebhookDelivery) *WebhookDeliveryResponse {
+	resp := &WebhookDeliveryResponse{
+		ID:             d.ID.String(),
+		EventID:        d.EventID.String(),
+		EventType:      string(d.EventType),
+		Status:         string(d.Status),
+		Attempts:       d.Attempts,
+		LastAttemptAt:  d.LastAttemptAt,
+		ResponseStatus: d.ResponseStatus,
+		LastError:      d.LastError,
+		Payload:        d.Payload,
+		CreatedAt:      d.CreatedAt,
+	}
+	if d.Status == domain.WebhookDeliveryPending {
+		next := d.NextAttemptAt
+		resp.NextAttemptAt = &next
+	}
+	return resp
+}
+
+func WebhookDeliveryListToResponse(deliveries []domain.WebhookDelivery) []render.Renderer {
+	list := make([]render.Renderer, len(deliveries))
+	for i, d := range deliveries {
+		list[i] = WebhookDeliveryToResponse(d)
+	}
+	return list
+}
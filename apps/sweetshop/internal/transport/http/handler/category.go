@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+
+	coredomain "github.com/bbsbb/go-edge/core/domain"
+	transporthttp "github.com/bbsbb/go-edge/core/transport/http"
+	"github.com/bbsbb/go-edge/sweetshop/internal/service"
+	"github.com/bbsbb/go-edge/sweetshop/internal/transport/http/dto"
+)
+
+type CategoryHandler struct {
+	services *service.Registry
+	logger   *slog.Logger
+}
+
+func NewCategoryHandler(services *service.Registry, logger *slog.Logger) *CategoryHandler {
+	return &CategoryHandler{services: services, logger: logger}
+}
+
+func (h *CategoryHandler) List(w http.ResponseWriter, r *http.Request) {
+	categories, err := h.services.Categories.List(r.Context())
+	if err != nil {
+		transporthttp.WriteError(w, r, err, h.logger)
+		return
+	}
+	transporthttp.RenderListOrLog(w, r, dto.CategoryListToResponse(categories), h.logger)
+}
+
+func (h *CategoryHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, err := coredomain.ParseID(chi.URLParam(r, "id"))
+	if err != nil {
+		transporthttp.WriteError(w, r, err, h.logger)
+		return
+	}
+
+	category, err := h.services.Categories.Get(r.Context(), id.UUID())
+	if err != nil {
+		transporthttp.WriteError(w, r, err, h.logger)
+		return
+	}
+	render.Status(r, http.StatusOK)
+	transporthttp.RenderOrLog(w, r, dto.CategoryToResponse(category), h.logger)
+}
+
+func (h *CategoryHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req dto.CategoryRequest
+	if err := transporthttp.Bind(r, &req); err != nil {
+		transporthttp.WriteError(w, r, err, h.logger)
+		return
+	}
+
+	category, err := h.services.Categories.Create(r.Context(), req.Slug, req.Name, req.SortOrder)
+	if err != nil {
+		transporthttp.WriteError(w, r, err, h.logger)
+		return
+	}
+	render.Status(r, http.StatusCreated)
+	transporthttp.RenderOrLog(w, r, dto.CategoryToResponse(category), h.logger)
+}
+
+func (h *CategoryHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := coredomain.ParseID(chi.URLParam(r, "id"))
+	if err != nil {
+		transporthttp.WriteError(w, r, err, h.logger)
+		return
+	}
+
+	var req dto.CategoryRequest
+	if err := transporthttp.Bind(r, &req); err != nil {
+		transporthttp.WriteError(w, r, err, h.logger)
+		return
+	}
+
+	category, err := h.services.Categories.Update(r.Context(), id.UUID(), req.Slug, req.Name, req.SortOrder)
+	if err != nil {
+		transporthttp.WriteError(w, r, err, h.logger)
+		return
+	}
+	render.Status(r, http.StatusOK)
+	transporthttp.RenderOrLog(w, r, dto.CategoryToResponse(category), h.logger)
+}
+
+func (h *CategoryHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := coredomain.ParseID(chi.URLParam(r, "id"))
+	if err != nil {
+		transporthttp.WriteError(w, r, err, h.logger)
+		return
+	}
+
+	if err := h.services.Categories.Delete(r.Context(), id.UUID()); err != nil {
+		transporthttp.WriteError(w, r, err, h.logger)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,150 @@
+//go:build testing
+
+package handler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	coretesting "github.com/bbsbb/go-edge/core/testing"
+)
+
+type CategorySuite struct {
+	IntegrationSuite
+}
+
+func (s *CategorySuite) CreateCategory(slug, name string, sortOrder int) map[string]any {
+	rec := s.Do(coretesting.JSONRequest(s.T(), http.MethodPost, "/categories", map[string]any{
+		"slug": slug, "name": name, "sort_order": sortOrder,
+	}))
+	s.Require().Equal(http.StatusCreated, rec.Code, rec.Body.String())
+
+	var resp map[string]any
+	coretesting.DecodeJSON(s.T(), rec, &resp)
+	return resp
+}
+
+func (s *CategorySuite) TestCreateAndListCategories() {
+	created := s.CreateCategory("fudge", "Fudge", -1)
+	s.Assert().NotEmpty(created["id"])
+	s.Assert().Equal("fudge", created["slug"])
+	s.Assert().Equal("Fudge", created["name"])
+	s.Assert().Equal(float64(-1), created["sort_order"])
+
+	rec := s.Do(httptest.NewRequest(http.MethodGet, "/categories", nil))
+	s.Require().Equal(http.StatusOK, rec.Code)
+	var resp []map[string]any
+	coretesting.DecodeJSON(s.T(), rec, &resp)
+	var slugs []any
+	for _, c := range resp {
+		slugs = append(slugs, c["slug"])
+	}
+	s.Assert().Equal([]any{"fudge", "ice_cream", "marshmallow"}, slugs)
+
+	rec = s.Do(httptest.NewRequest(http.MethodGet, "/categories/"+created["id"].(string), nil))
+	s.Assert().Equal(http.StatusOK, rec.Code)
+}
+
+func (s *CategorySuite) TestCreateCategory_ValidationErrors() {
+	cases := []struct {
+		name     string
+		body     map[string]any
+		wantCode int
+	}{
+		{"missing slug", map[string]any{"name": "Fudge"}, http.StatusBadRequest},
+		{"missing name", map[string]any{"slug": "fudge"}, http.StatusBadRequest},
+		{"uppercase slug", map[string]any{"slug": "Fudge", "name": "Fudge"}, http.StatusBadRequest},
+		{"slug with spaces", map[string]any{"slug": "hard candy", "name": "Hard candy"}, http.StatusBadRequest},
+		{"slug with double underscore", map[string]any{"slug": "hard__candy", "name": "Hard candy"}, http.StatusBadRequest},
+		{"duplicate slug", map[string]any{"slug": "ice_cream", "name": "Gelato"}, http.StatusConflict},
+	}
+	for _, tc := range cases {
+		s.Run(tc.name, func() {
+			rec := s.Do(coretesting.JSONRequest(s.T(), http.MethodPost, "/categories", tc.body))
+			s.Assert().Equal(tc.wantCode, rec.Code, rec.Body.String())
+		})
+	}
+}
+
+func (s *CategorySuite) TestNewCategoryCanBeUsed() {
+	s.CreateCategory("fudge", "Fudge", 2)
+
+	product := s.CreateProduct("Vanilla Fudge", "fudge", 250)
+	s.Assert().Equal("fudge", product["category"])
+
+	rec := s.Do(coretesting.JSONRequest(s.T(), http.MethodPut, "/settings/tax", map[string]any{
+		"pricing": "exclusive", "rounding": "per_line", "rates": map[string]any{"fudge": 500},
+	}))
+	s.Assert().Equal(http.StatusOK, rec.Code, rec.Body.String())
+}
+
+func (s *CategorySuite) TestUpdateCategory_SlugCarriesOverToProducts() {
+	category := s.CreateCategory("fudge", "Fudge", 2)
+	product := s.CreateProduct("Vanilla Fudge", "fudge", 250)
+
+	rec := s.Do(coretesting.JSONRequest(s.T(), http.MethodPut, "/categories/"+category["id"].(string), map[string]any{
+		"slug": "toffee", "name": "Toffee", "sort_order": 5,
+	}))
+	s.Require().Equal(http.StatusOK, rec.Code, rec.Body.String())
+	var resp map[string]any
+	coretesting.DecodeJSON(s.T(), rec, &resp)
+	s.Assert().Equal("toffee", resp["slug"])
+	s.Assert().Equal(float64(5), resp["sort_order"])
+
+	rec = s.Do(httptest.NewRequest(http.MethodGet, "/products/"+product["id"].(string), nil))
+	s.Require().Equal(http.StatusOK, rec.Code)
+	coretesting.DecodeJSON(s.T(), rec, &resp)
+	s.Assert().Equal("toffee", resp["category"])
+}
+
+func (s *CategorySuite) TestUpdateCategory_NotFound() {
+	rec := s.Do(coretesting.JSONRequest(s.T(), http.MethodPut, "/categories/019505e0-0000-7000-8000-000000000000", map[string]any{
+		"slug": "toffee", "name": "Toffee",
+	}))
+	s.Assert().Equal(http.StatusNotFound, rec.Code)
+}
+
+func (s *CategorySuite) TestDeleteCategory() {
+	category := s.CreateCategory("fudge", "Fudge", 2)
+
+	rec := s.Do(httptest.NewRequest(http.MethodDelete, "/categories/"+category["id"].(string), nil))
+	s.Assert().Equal(http.StatusNoContent, rec.Code)
+
+	rec = s.Do(httptest.NewRequest(http.MethodGet, "/categories/"+category["id"].(string), nil))
+	s.Assert().Equal(http.StatusNotFound, rec.Code)
+
+	rec = s.Do(coretesting.JSONRequest(s.T(), http.MethodPost, "/products", map[string]any{
+		"name": "Vanilla Fudge", "category": "fudge", "price": eur(250),
+	}))
+	s.Assert().Equal(http.StatusBadRequest, rec.Code)
+}
+
+func (s *CategorySuite) TestDeleteCategory_InUse() {
+	category := s.CreateCategory("fudge", "Fudge", 2)
+	product := s.CreateProduct("Vanilla Fudge", "fudge", 250)
+	s.Require().Equal(http.StatusNoContent, s.Do(httptest.NewRequest(http.MethodDelete, "/products/"+product["id"].(string), nil)).Code)
+
+	rec := s.Do(httptest.NewRequest(http.MethodDelete, "/categories/"+category["id"].(string), nil))
+	s.Assert().Equal(http.StatusConflict, rec.Code, "a soft-deleted product still uses its category")
+
+	promoted := s.CreateCategory("toffee", "Toffee", 3)
+	rec = s.Do(coretesting.JSONRequest(s.T(), http.MethodPost, "/promotions", map[string]any{
+		"name": "Toffee week", "kind": "percentage", "percent_off": 10, "category": "toffee",
+	}))
+	s.Require().Equal(http.StatusCreated, rec.Code, rec.Body.String())
+
+	rec = s.Do(httptest.NewRequest(http.MethodDelete, "/categories/"+promoted["id"].(string), nil))
+	s.Assert().Equal(http.StatusConflict, rec.Code)
+}
+
+func (s *CategorySuite) TestDeleteCategory_NotFound() {
+	rec := s.Do(httptest.NewRequest(http.MethodDelete, "/categories/019505e0-0000-7000-8000-000000000000", nil))
+	s.Assert().Equal(http.StatusNotFound, rec.Code)
+}
+
+func TestCategorySuite(t *testing.T) {
+	suite.Run(t, new(CategorySuite))
+}
@@ -19,13 +19,17 @@ import (
 	"go.uber.org/fx/fxtest"
 
 	coredomain "github.com/bbsbb/go-edge/core/domain"
+	"github.com/bbsbb/go-edge/core/fx/httpclientfx"
 	"github.com/bbsbb/go-edge/core/fx/middlewarefx"
 	"github.com/bbsbb/go-edge/core/fx/psqlfx"
 	"github.com/bbsbb/go-edge/core/fx/rlsfx"
 	coretesting "github.com/bbsbb/go-edge/core/testing"
 	transporthttp "github.com/bbsbb/go-edge/core/transport/http"
 	"github.com/bbsbb/go-edge/sweetshop/internal/config"
+	"github.com/bbsbb/go-edge/sweetshop/internal/domain"
+	"github.com/bbsbb/go-edge/sweetshop/internal/infrastructure/outbound"
 	"github.com/bbsbb/go-edge/sweetshop/internal/infrastructure/persistence"
+	"github.com/bbsbb/go-edge/sweetshop/internal/service"
 	transportroutes "github.com/bbsbb/go-edge/sweetshop/internal/transport/http"
 )
 
@@ -36,6 +40,11 @@ type IntegrationSuite struct {
 	Logger *slog.Logger
 	Router *chi.Mux
 	OrgID  uuid.UUID
+	// Services drives application services that have no HTTP route, such as
+	// the ones background jobs call.
+	Services *service.Registry
+	// Payments is the fake payment provider orders are paid through.
+	Payments *outbound.FakePaymentGateway
 
 	orgRepo *persistence.OrganizationRepo
 	tx      pgx.Tx
@@ -72,9 +81,17 @@ func (s *IntegrationSuite) SetupSuite() {
 		fx.Supply(s.DB.Pool),
 		fx.Supply(rlsDB),
 		fx.Supply(s.Logger),
+		fx.Supply(cfg.PSQL),
+		psqlfx.ListenerModule,
 		middlewarefx.Module,
+		httpclientfx.Module,
 		persistence.Module,
+		outbound.Module,
 		transportroutes.RouteModule,
+		fx.Populate(&s.Services),
+		fx.Invoke(func(gateway domain.PaymentGateway) {
+			s.Payments = gateway.(*outbound.FakePaymentGateway)
+		}),
 		fx.Invoke(func() {
 			s.Router.Get("/healthz", transporthttp.LivenessHandler())
 			s.Router.Get("/readyz", transporthttp.ReadinessHandler(s.DB.Pool, 0, s.Logger))
@@ -92,6 +109,19 @@ func (s *IntegrationSuite) BeforeTest(_, _ string) {
 
 		s.Require().NoError(s.orgRepo.Create(ctx, s.org))
 	})
+
+	// The categories organizations had before they could manage their own.
+	for i, slug := range []domain.ProductCategory{"ice_cream", "marshmallow"} {
+		_, err := s.Services.Categories.Create(s.Context(), slug, string(slug), int32(i))
+		s.Require().NoError(err)
+	}
+}
+
+// Context returns a context carrying the test transaction and organization,
+// for calling services directly.
+func (s *IntegrationSuite) Context() context.Context {
+	ctx := psqlfx.ContextWithTx(context.Background(), s.tx)
+	return coredomain.ContextWithOrganization(ctx, s.org)
 }
 
 func (s *IntegrationSuite) Do(req *http.Request) *httptest.ResponseRecorder {
@@ -101,9 +131,11 @@ func (s *IntegrationSuite) Do(req *http.Request) *httptest.ResponseRecorder {
 	return rec
 }
 
-func (s *IntegrationSuite) CreateProduct(name, category string, priceCents int) map[string]any {
+// CreateProduct creates a product priced at amount euro cents, the test
+// organization's default currency.
+func (s *IntegrationSuite) CreateProduct(name, category string, amount float64) map[string]any {
 	req := coretesting.JSONRequest(s.T(), http.MethodPost, "/products", map[string]any{
-		"name": name, "category": category, "price_cents": priceCents,
+		"name": name, "category": category, "price": eur(amount),
 	})
 	rec := s.Do(req)
 	s.Require().Equal(http.StatusCreated, rec.Code)
@@ -122,3 +154,29 @@ func (s *IntegrationSuite) OpenOrder() map[string]any {
 	s.Require().NoError(json.NewDecoder(rec.Body).Decode(&resp))
 	return resp
 }
+
+// OpenOrderWithItems opens an order holding 2 x 3.50 EUR and returns its ID.
+func (s *IntegrationSuite) OpenOrderWithItems() string {
+	product := s.CreateProduct("Vanilla "+uuid.NewString()[:8], "ice_cream", 350)
+	id := s.OpenOrder()["id"].(string)
+
+	rec := s.Do(coretesting.JSONRequest(s.T(), http.MethodPost, "/orders/"+id+"/items", map[string]any{
+		"product_id": product["id"], "quantity": 2,
+	}))
+	s.Require().Equal(http.StatusCreated, rec.Code)
+	return id
+}
+
+// Transition posts an order action (submit, pay, fulfil, cancel or refund)
+// and returns the response with its decoded body.
+func (s *IntegrationSuite) Transition(id, action string) (*httptest.ResponseRecorder, map[string]any) {
+	rec := s.Do(httptest.NewRequest(http.MethodPost, "/orders/"+id+"/"+action, nil))
+	var resp map[string]any
+	_ = json.Unmarshal(rec.Body.Bytes(), &resp)
+	return rec, resp
+}
+
+// eur is the JSON form of amount euro cents, as sent and received.
+func eur(amount float64) map[string]any {
+	return map[string]any{"amount": amount, "currency": "EUR"}
+}
@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+
+	coredomain "github.com/bbsbb/go-edge/core/domain"
+	transporthttp "github.com/bbsbb/go-edge/core/transport/http"
+	"github.com/bbsbb/go-edge/sweetshop/internal/service"
+	"github.com/bbsbb/go-edge/sweetshop/internal/transport/http/dto"
+)
+
+type InventoryHandler struct {
+	services *service.Registry
+	logger   *slog.Logger
+}
+
+func NewInventoryHandler(services *service.Registry, logger *slog.Logger) *InventoryHandler {
+	return &InventoryHandler{services: services, logger: logger}
+}
+
+func (h *InventoryHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, err := coredomain.ParseID(chi.URLParam(r, "id"))
+	if err != nil {
+		transporthttp.WriteError(w, r, err, h.logger)
+		return
+	}
+
+	inventory, err := h.services.Inventory.GetInventory(r.Context(), id.UUID())
+	if err != nil {
+		transporthttp.WriteError(w, r, err, h.logger)
+		return
+	}
+	render.Status(r, http.StatusOK)
+	transporthttp.RenderOrLog(w, r, dto.InventoryToResponse(inventory), h.logger)
+}
+
+func (h *InventoryHandler) Adjust(w http.ResponseWriter, r *http.Request) {
+	id, err := coredomain.ParseID(chi.URLParam(r, "id"))
+	if err != nil {
+		transporthttp.WriteError(w, r, err, h.logger)
+		return
+	}
+
+	var req dto.AdjustStockRequest
+	if err := transporthttp.Bind(r, &req); err != nil {
+		transporthttp.WriteError(w, r, err, h.logger)
+		return
+	}
+
+	inventory, err := h.services.Inventory.AdjustStock(r.Context(), id.UUID(), req.QuantityDelta, req.Reason, req.Note)
+	if err != nil {
+		transporthttp.WriteError(w, r, err, h.logger)
+		return
+	}
+	render.Status(r, http.StatusOK)
+	transporthttp.RenderOrLog(w, r, dto.InventoryToResponse(inventory), h.logger)
+}
+
+// Adjustments serves the product's stock adjustment log, newest first.
+func (h *InventoryHandler) Adjustments(w http.ResponseWriter, r *http.Request) {
+	id, err := coredomain.ParseID(chi.URLParam(r, "id"))
+	if err != nil {
+		transporthttp.WriteError(w, r, err, h.logger)
+		return
+	}
+
+	limit := int32(service.DefaultStockAdjustmentLimit)
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		n, err := strconv.ParseInt(raw, 10, 32)
+		if err != nil {
+			transporthttp.WriteError(w, r, coredomain.NewError(coredomain.CodeValidation, "limit must be an integer"), h.logger)
+			return
+		}
+		limit = int32(n)
+	}
+
+	adjustments, err := h.services.Inventory.ListAdjustments(r.Context(), id.UUID(), limit)
+	if err != nil {
+		transporthttp.WriteError(w, r, err, h.logger)
+		return
+	}
+	transporthttp.RenderListOrLog(w, r, dto.StockAdjustmentListToResponse(adjustments), h.logger)
+}
@@ -0,0 +1,197 @@
+//go:build testing
+
+package handler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	coretesting "github.com/bbsbb/go-edge/core/testing"
+)
+
+type InventorySuite struct {
+	IntegrationSuite
+}
+
+func (s *InventorySuite) Inventory(productID string) map[string]any {
+	rec := s.Do(httptest.NewRequest(http.MethodGet, "/products/"+productID+"/inventory", nil))
+	s.Require().Equal(http.StatusOK, rec.Code, rec.Body.String())
+
+	var resp map[string]any
+	coretesting.DecodeJSON(s.T(), rec, &resp)
+	return resp
+}
+
+func (s *InventorySuite) Adjust(productID string, delta int, reason string) *httptest.ResponseRecorder {
+	return s.Do(coretesting.JSONRequest(s.T(), http.MethodPost, "/products/"+productID+"/inventory/adjustments", map[string]any{
+		"quantity_delta": delta, "reason": reason,
+	}))
+}
+
+// StockedProduct creates a product with onHand units in stock.
+func (s *InventorySuite) StockedProduct(onHand int) string {
+	id := s.CreateProduct("Vanilla", "ice_cream", 350)["id"].(string)
+	rec := s.Adjust(id, onHand, "received")
+	s.Require().Equal(http.StatusOK, rec.Code, rec.Body.String())
+	return id
+}
+
+func (s *InventorySuite) AddItem(orderID, productID string, quantity int) *httptest.ResponseRecorder {
+	return s.Do(coretesting.JSONRequest(s.T(), http.MethodPost, "/orders/"+orderID+"/items", map[string]any{
+		"product_id": productID, "quantity": quantity,
+	}))
+}
+
+func (s *InventorySuite) TestUntrackedProduct() {
+	product := s.CreateProduct("Vanilla", "ice_cream", 350)
+	id := product["id"].(string)
+
+	got := s.Inventory(id)
+	s.Assert().Equal(false, got["tracked"])
+	s.Assert().Equal(float64(0), got["available"])
+
+	order := s.OpenOrder()["id"].(string)
+	s.Assert().Equal(http.StatusCreated, s.AddItem(order, id, 1000).Code, "untracked stock is not limited")
+	s.Assert().Equal(false, s.Inventory(id)["tracked"])
+}
+
+func (s *InventorySuite) TestAdjustStock() {
+	id := s.CreateProduct("Vanilla", "ice_cream", 350)["id"].(string)
+
+	rec := s.Do(coretesting.JSONRequest(s.T(), http.MethodPost, "/products/"+id+"/inventory/adjustments", map[string]any{
+		"quantity_delta": 10, "reason": "received", "note": "morning delivery",
+	}))
+	s.Require().Equal(http.StatusOK, rec.Code, rec.Body.String())
+	var resp map[string]any
+	coretesting.DecodeJSON(s.T(), rec, &resp)
+	s.Assert().Equal(true, resp["tracked"])
+	s.Assert().Equal(float64(10), resp["on_hand"])
+	s.Assert().Equal(float64(10), resp["available"])
+
+	s.Require().Equal(http.StatusOK, s.Adjust(id, -3, "damaged").Code)
+
+	rec = s.Do(httptest.NewRequest(http.MethodGet, "/products/"+id+"/inventory/adjustments", nil))
+	s.Require().Equal(http.StatusOK, rec.Code)
+	var log []map[string]any
+	coretesting.DecodeJSON(s.T(), rec, &log)
+	s.Require().Len(log, 2)
+	s.Assert().Equal("damaged", log[0]["reason"])
+	s.Assert().Equal(float64(-3), log[0]["quantity_delta"])
+	s.Assert().Equal(float64(7), log[0]["on_hand_after"])
+	s.Assert().Equal("morning delivery", log[1]["note"])
+}
+
+func (s *InventorySuite) TestAdjustStock_ValidationErrors() {
+	id := s.CreateProduct("Vanilla", "ice_cream", 350)["id"].(string)
+
+	cases := []struct {
+		name   string
+		delta  int
+		reason string
+	}{
+		{"zero delta", 0, "received"},
+		{"unknown reason", 5, "gift"},
+		{"missing reason", 5, ""},
+	}
+	for _, tc := range cases {
+		s.Run(tc.name, func() {
+			s.Assert().Equal(http.StatusBadRequest, s.Adjust(id, tc.delta, tc.reason).Code)
+		})
+	}
+
+	s.Assert().Equal(http.StatusNotFound, s.Adjust("019505e0-0000-7000-8000-000000000000", 5, "received").Code)
+}
+
+func (s *InventorySuite) TestAdjustStock_BelowReserved() {
+	id := s.StockedProduct(5)
+	order := s.OpenOrder()["id"].(string)
+	s.Require().Equal(http.StatusCreated, s.AddItem(order, id, 4).Code)
+
+	s.Assert().Equal(http.StatusUnprocessableEntity, s.Adjust(id, -2, "expired").Code)
+	s.Assert().Equal(http.StatusOK, s.Adjust(id, -1, "expired").Code)
+	s.Assert().Equal(float64(0), s.Inventory(id)["available"])
+}
+
+func (s *InventorySuite) TestAddItem_ReservesStock() {
+	id := s.StockedProduct(5)
+	order := s.OpenOrder()["id"].(string)
+
+	s.Require().Equal(http.StatusCreated, s.AddItem(order, id, 3).Code)
+
+	got := s.Inventory(id)
+	s.Assert().Equal(float64(5), got["on_hand"])
+	s.Assert().Equal(float64(3), got["reserved"])
+	s.Assert().Equal(float64(2), got["available"])
+}
+
+func (s *InventorySuite) TestAddItem_InsufficientStock() {
+	id := s.StockedProduct(2)
+	order := s.OpenOrder()["id"].(string)
+
+	rec := s.AddItem(order, id, 3)
+	s.Require().Equal(http.StatusUnprocessableEntity, rec.Code)
+	var problem map[string]any
+	coretesting.DecodeJSON(s.T(), rec, &problem)
+	s.Assert().Equal(float64(2), problem["available"])
+
+	s.Assert().Equal(float64(0), s.Inventory(id)["reserved"], "a rejected item reserves nothing")
+	s.Assert().Equal(http.StatusCreated, s.AddItem(order, id, 2).Code)
+	s.Assert().Equal(http.StatusUnprocessableEntity, s.AddItem(s.OpenOrder()["id"].(string), id, 1).Code)
+}
+
+func (s *InventorySuite) TestAddItem_ClosedOrderReservesNothing() {
+	id := s.StockedProduct(5)
+	order := s.OpenOrder()["id"].(string)
+	s.Require().Equal(http.StatusCreated, s.AddItem(order, id, 1).Code)
+	rec, _ := s.Transition(order, "submit")
+	s.Require().Equal(http.StatusOK, rec.Code)
+
+	s.Assert().Equal(http.StatusUnprocessableEntity, s.AddItem(order, id, 2).Code)
+	s.Assert().Equal(float64(1), s.Inventory(id)["reserved"], "a submitted order reserves no more stock")
+
+	rec, _ = s.Transition(order, "cancel")
+	s.Require().Equal(http.StatusOK, rec.Code)
+	s.Assert().Equal(http.StatusUnprocessableEntity, s.AddItem(order, id, 2).Code)
+	s.Assert().Equal(float64(0), s.Inventory(id)["reserved"], "a cancelled order holds no stock")
+}
+
+func (s *InventorySuite) TestCancel_ReleasesReservation() {
+	id := s.StockedProduct(5)
+	order := s.OpenOrder()["id"].(string)
+	s.Require().Equal(http.StatusCreated, s.AddItem(order, id, 2).Code)
+	s.Require().Equal(http.StatusCreated, s.AddItem(order, id, 1).Code)
+
+	rec, _ := s.Transition(order, "cancel")
+	s.Require().Equal(http.StatusOK, rec.Code)
+
+	got := s.Inventory(id)
+	s.Assert().Equal(float64(5), got["on_hand"])
+	s.Assert().Equal(float64(0), got["reserved"])
+}
+
+func (s *InventorySuite) TestFulfil_CommitsReservation() {
+	id := s.StockedProduct(5)
+	order := s.OpenOrder()["id"].(string)
+	s.Require().Equal(http.StatusCreated, s.AddItem(order, id, 2).Code)
+
+	for _, action := range []string{"submit", "pay"} {
+		rec, _ := s.Transition(order, action)
+		s.Require().Equal(http.StatusOK, rec.Code, action)
+		s.Assert().Equal(float64(2), s.Inventory(id)["reserved"], "stock stays reserved after "+action)
+	}
+
+	rec, _ := s.Transition(order, "fulfil")
+	s.Require().Equal(http.StatusOK, rec.Code)
+
+	got := s.Inventory(id)
+	s.Assert().Equal(float64(3), got["on_hand"])
+	s.Assert().Equal(float64(0), got["reserved"])
+	s.Assert().Equal(float64(3), got["available"])
+}
+
+func TestInventorySuite(t *testing.T) {
+	suite.Run(t, new(InventorySuite))
+}
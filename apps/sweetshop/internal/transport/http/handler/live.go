@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	coredomain "github.com/bbsbb/go-edge/core/domain"
+	transporthttp "github.com/bbsbb/go-edge/core/transport/http"
+	"github.com/bbsbb/go-edge/core/transport/http/ws"
+	"github.com/bbsbb/go-edge/sweetshop/internal/domain"
+	"github.com/bbsbb/go-edge/sweetshop/internal/service"
+	"github.com/bbsbb/go-edge/sweetshop/internal/transport/http/dto"
+)
+
+// liveInstance is the problem instance reported for failed live commands.
+const liveInstance = "/orders/live"
+
+// LiveHandler serves order commands and updates over a WebSocket. Clients of
+// one organization share a room, so every change made through the API is
+// pushed to all of them as order.updated.
+type LiveHandler struct {
+	services *service.Registry
+	hub      *ws.Hub
+	logger   *slog.Logger
+}
+
+func NewLiveHandler(services *service.Registry, hub *ws.Hub, logger *slog.Logger) *LiveHandler {
+	return &LiveHandler{services: services, hub: hub, logger: logger}
+}
+
+// Connect upgrades the request and joins the caller's organization room.
+func (h *LiveHandler) Connect(w http.ResponseWriter, r *http.Request) {
+	h.hub.Handler(h.handle).ServeHTTP(w, r)
+}
+
+func (h *LiveHandler) handle(ctx context.Context, c *ws.Client, data []byte) {
+	var cmd dto.LiveCommand
+	if err := transporthttp.BindBytes(ctx, data, &cmd); err != nil {
+		h.reply(ctx, c, h.problem(ctx, "", err))
+		return
+	}
+
+	switch cmd.Type {
+	case dto.LiveAddOrderItem:
+		h.reply(ctx, c, h.addItem(ctx, cmd))
+	default:
+		h.reply(ctx, c, h.problem(ctx, cmd.ID,
+			coredomain.NewError(coredomain.CodeValidation, "unknown command type "+cmd.Type)))
+	}
+}
+
+func (h *LiveHandler) addItem(ctx context.Context, cmd dto.LiveCommand) dto.LiveMessage {
+	var req dto.LiveAddOrderItemRequest
+	if err := transporthttp.BindBytes(ctx, cmd.Data, &req); err != nil {
+		return h.problem(ctx, cmd.ID, err)
+	}
+
+	orderID, err := coredomain.ParseID(req.OrderID)
+	if err != nil {
+		return h.problem(ctx, cmd.ID, err)
+	}
+	productID, err := coredomain.ParseID(req.ProductID)
+	if err != nil {
+		return h.problem(ctx, cmd.ID, err)
+	}
+	optionIDs, err := req.Options()
+	if err != nil {
+		return h.problem(ctx, cmd.ID, err)
+	}
+
+	item, err := h.services.Orders.AddItem(ctx, orderID.UUID(), productID.UUID(), req.Quantity, optionIDs)
+	if err != nil {
+		return h.problem(ctx, cmd.ID, err)
+	}
+	resp, err := dto.OrderItemToCreatedResponse(item)
+	if err != nil {
+		return h.problem(ctx, cmd.ID, err)
+	}
+	return dto.LiveMessage{Type: dto.LiveOrderItemAdded, ID: cmd.ID, Data: resp}
+}
+
+func (h *LiveHandler) problem(ctx context.Context, id string, err error) dto.LiveMessage {
+	if !errors.As(err, new(*coredomain.Error)) {
+		h.logger.ErrorContext(ctx, "live command failed", "error", err)
+	}
+	return dto.LiveMessage{Type: dto.LiveError, ID: id, Data: transporthttp.Problem(ctx, liveInstance, err)}
+}
+
+func (h *LiveHandler) reply(ctx context.Context, c *ws.Client, msg dto.LiveMessage) {
+	if err := c.Send(msg); err != nil {
+		h.logger.DebugContext(ctx, "failed to reply to live client", "error", err)
+	}
+}
+
+// hubOrderBroadcaster publishes order changes to the organization's live room.
+type hubOrderBroadcaster struct {
+	hub *ws.Hub
+}
+
+func NewOrderBroadcaster(hub *ws.Hub) domain.OrderBroadcaster {
+	return hubOrderBroadcaster{hub: hub}
+}
+
+func (b hubOrderBroadcaster) BroadcastOrder(ctx context.Context, order *domain.Order) error {
+	resp, err := dto.OrderToResponse(order)
+	if err != nil {
+		return err
+	}
+	return b.hub.BroadcastTo(order.OrganizationID, dto.LiveMessage{Type: dto.LiveOrderUpdated, Data: resp})
+}
@@ -0,0 +1,119 @@
+//go:build testing
+
+package handler_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/coder/websocket/wsjson"
+	"github.com/stretchr/testify/suite"
+)
+
+type LiveSuite struct {
+	IntegrationSuite
+	srv *httptest.Server
+}
+
+func (s *LiveSuite) SetupTest() {
+	s.srv = httptest.NewServer(s.Router)
+}
+
+func (s *LiveSuite) TearDownTest() {
+	s.srv.Close()
+}
+
+func (s *LiveSuite) dial() *websocket.Conn {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	url := "ws" + strings.TrimPrefix(s.srv.URL, "http") + "/orders/live"
+	conn, _, err := websocket.Dial(ctx, url, &websocket.DialOptions{
+		HTTPHeader: http.Header{"X-Organization-Slug": {s.org.Slug}},
+	})
+	s.Require().NoError(err)
+	s.T().Cleanup(func() { _ = conn.CloseNow() })
+	return conn
+}
+
+func (s *LiveSuite) send(conn *websocket.Conn, msg map[string]any) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	s.Require().NoError(wsjson.Write(ctx, conn, msg))
+}
+
+func (s *LiveSuite) receive(conn *websocket.Conn) map[string]any {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	var msg map[string]any
+	s.Require().NoError(wsjson.Read(ctx, conn, &msg))
+	return msg
+}
+
+func (s *LiveSuite) TestUnknownCommand() {
+	conn := s.dial()
+
+	s.send(conn, map[string]any{"type": "order.teleport", "id": "1"})
+
+	msg := s.receive(conn)
+	s.Assert().Equal("error", msg["type"])
+	s.Assert().Equal("1", msg["id"])
+	s.Assert().Equal("VALIDATION", msg["data"].(map[string]any)["code"])
+}
+
+func (s *LiveSuite) TestAddItemRepliesAndBroadcasts() {
+	product := s.CreateProduct("Vanilla Cone", "ice_cream", 150)
+	conn := s.dial()
+	// A round trip guarantees the client has joined the room before the order opens.
+	s.send(conn, map[string]any{"type": "order.teleport"})
+	s.receive(conn)
+
+	order := s.OpenOrder()
+	opened := s.receive(conn)
+	s.Assert().Equal("order.updated", opened["type"])
+	s.Assert().Equal(order["id"], opened["data"].(map[string]any)["id"])
+
+	s.send(conn, map[string]any{
+		"type": "order.add_item",
+		"id":   "add-1",
+		"data": map[string]any{"order_id": order["id"], "product_id": product["id"], "quantity": 2},
+	})
+
+	// The broadcast is queued while the command runs, before its reply.
+	updated := s.receive(conn)
+	s.Assert().Equal("order.updated", updated["type"])
+	s.Assert().Equal(eur(300), updated["data"].(map[string]any)["total"])
+
+	reply := s.receive(conn)
+	s.Assert().Equal("order.item_added", reply["type"])
+	s.Assert().Equal("add-1", reply["id"])
+	s.Assert().Equal(eur(300), reply["data"].(map[string]any)["line_total"])
+}
+
+func (s *LiveSuite) TestAddItemValidation() {
+	conn := s.dial()
+
+	s.send(conn, map[string]any{"type": "order.add_item", "id": "2", "data": map[string]any{"quantity": 0}})
+
+	msg := s.receive(conn)
+	s.Assert().Equal("error", msg["type"])
+	s.Assert().Equal("2", msg["id"])
+	s.Assert().Equal(float64(http.StatusBadRequest), msg["data"].(map[string]any)["status"])
+}
+
+func (s *LiveSuite) TestRequiresOrganization() {
+	url := "ws" + strings.TrimPrefix(s.srv.URL, "http") + "/orders/live"
+	_, resp, err := websocket.Dial(context.Background(), url, nil)
+
+	s.Require().Error(err)
+	s.Assert().Equal(http.StatusNotFound, resp.StatusCode)
+}
+
+func TestLiveSuite(t *testing.T) {
+	suite.Run(t, new(LiveSuite))
+}
@@ -1,18 +1,25 @@
 package handler
 
 import (
+	"context"
 	"log/slog"
 	"net/http"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/render"
+	"github.com/google/uuid"
 
 	coredomain "github.com/bbsbb/go-edge/core/domain"
 	transporthttp "github.com/bbsbb/go-edge/core/transport/http"
+	"github.com/bbsbb/go-edge/sweetshop/internal/domain"
 	"github.com/bbsbb/go-edge/sweetshop/internal/service"
 	"github.com/bbsbb/go-edge/sweetshop/internal/transport/http/dto"
 )
 
+// orderStreamRetry is the reconnect delay suggested to order stream clients.
+const orderStreamRetry = 3 * time.Second
+
 type OrderHandler struct {
 	services *service.Registry
 	logger   *slog.Logger
@@ -28,8 +35,17 @@ func (h *OrderHandler) Open(w http.ResponseWriter, r *http.Request) {
 		transporthttp.WriteError(w, r, err, h.logger)
 		return
 	}
-	render.Status(r, http.StatusCreated)
-	transporthttp.RenderOrLog(w, r, dto.OrderToResponse(order), h.logger)
+	h.renderOrder(w, r, http.StatusCreated, order)
+}
+
+func (h *OrderHandler) renderOrder(w http.ResponseWriter, r *http.Request, status int, order *domain.Order) {
+	resp, err := dto.OrderToResponse(order)
+	if err != nil {
+		transporthttp.WriteError(w, r, err, h.logger)
+		return
+	}
+	render.Status(r, status)
+	transporthttp.RenderOrLog(w, r, resp, h.logger)
 }
 
 func (h *OrderHandler) Get(w http.ResponseWriter, r *http.Request) {
@@ -44,8 +60,7 @@ func (h *OrderHandler) Get(w http.ResponseWriter, r *http.Request) {
 		transporthttp.WriteError(w, r, err, h.logger)
 		return
 	}
-	render.Status(r, http.StatusOK)
-	transporthttp.RenderOrLog(w, r, dto.OrderToResponse(order), h.logger)
+	h.renderOrder(w, r, http.StatusOK, order)
 }
 
 func (h *OrderHandler) AddItem(w http.ResponseWriter, r *http.Request) {
@@ -56,8 +71,8 @@ func (h *OrderHandler) AddItem(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req dto.AddOrderItemRequest
-	if err := render.Bind(r, &req); err != nil {
-		transporthttp.WriteError(w, r, coredomain.NewError(coredomain.CodeValidation, "invalid request body"), h.logger)
+	if err := transporthttp.Bind(r, &req); err != nil {
+		transporthttp.WriteError(w, r, err, h.logger)
 		return
 	}
 
@@ -66,28 +81,207 @@ func (h *OrderHandler) AddItem(w http.ResponseWriter, r *http.Request) {
 		transporthttp.WriteError(w, r, err, h.logger)
 		return
 	}
+	optionIDs, err := req.Options()
+	if err != nil {
+		transporthttp.WriteError(w, r, err, h.logger)
+		return
+	}
 
-	item, err := h.services.Orders.AddItem(r.Context(), orderID.UUID(), productID.UUID(), req.Quantity)
+	item, err := h.services.Orders.AddItem(r.Context(), orderID.UUID(), productID.UUID(), req.Quantity, optionIDs)
+	if err != nil {
+		transporthttp.WriteError(w, r, err, h.logger)
+		return
+	}
+	resp, err := dto.OrderItemToCreatedResponse(item)
 	if err != nil {
 		transporthttp.WriteError(w, r, err, h.logger)
 		return
 	}
 	render.Status(r, http.StatusCreated)
-	transporthttp.RenderOrLog(w, r, dto.OrderItemToCreatedResponse(item), h.logger)
+	transporthttp.RenderOrLog(w, r, resp, h.logger)
 }
 
-func (h *OrderHandler) Close(w http.ResponseWriter, r *http.Request) {
+func (h *OrderHandler) UpdateItem(w http.ResponseWriter, r *http.Request) {
+	orderID, itemID, err := orderItemIDs(r)
+	if err != nil {
+		transporthttp.WriteError(w, r, err, h.logger)
+		return
+	}
+
+	var req dto.UpdateOrderItemRequest
+	if err := transporthttp.Bind(r, &req); err != nil {
+		transporthttp.WriteError(w, r, err, h.logger)
+		return
+	}
+
+	item, err := h.services.Orders.UpdateItemQuantity(r.Context(), orderID, itemID, req.Quantity)
+	if err != nil {
+		transporthttp.WriteError(w, r, err, h.logger)
+		return
+	}
+	resp, err := dto.OrderItemToCreatedResponse(item)
+	if err != nil {
+		transporthttp.WriteError(w, r, err, h.logger)
+		return
+	}
+	transporthttp.RenderOrLog(w, r, resp, h.logger)
+}
+
+func (h *OrderHandler) RemoveItem(w http.ResponseWriter, r *http.Request) {
+	orderID, itemID, err := orderItemIDs(r)
+	if err != nil {
+		transporthttp.WriteError(w, r, err, h.logger)
+		return
+	}
+
+	if err := h.services.Orders.RemoveItem(r.Context(), orderID, itemID); err != nil {
+		transporthttp.WriteError(w, r, err, h.logger)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// orderItemIDs parses the order and item IDs in the URL.
+func orderItemIDs(r *http.Request) (uuid.UUID, uuid.UUID, error) {
+	orderID, err := coredomain.ParseID(chi.URLParam(r, "id"))
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+	itemID, err := coredomain.ParseID(chi.URLParam(r, "itemID"))
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+	return orderID.UUID(), itemID.UUID(), nil
+}
+
+func (h *OrderHandler) Submit(w http.ResponseWriter, r *http.Request) {
+	h.transition(w, r, h.services.Orders.SubmitOrder)
+}
+
+func (h *OrderHandler) Pay(w http.ResponseWriter, r *http.Request) {
+	h.transition(w, r, h.services.Orders.PayOrder)
+}
+
+func (h *OrderHandler) Fulfil(w http.ResponseWriter, r *http.Request) {
+	h.transition(w, r, h.services.Orders.FulfilOrder)
+}
+
+func (h *OrderHandler) Cancel(w http.ResponseWriter, r *http.Request) {
+	h.transition(w, r, h.services.Orders.CancelOrder)
+}
+
+func (h *OrderHandler) Refund(w http.ResponseWriter, r *http.Request) {
+	h.transition(w, r, h.services.Orders.RefundOrder)
+}
+
+// transition runs an order action on the order in the URL and renders the
+// order it leaves behind.
+func (h *OrderHandler) transition(
+	w http.ResponseWriter,
+	r *http.Request,
+	action func(context.Context, uuid.UUID) (*domain.Order, error),
+) {
+	id, err := coredomain.ParseID(chi.URLParam(r, "id"))
+	if err != nil {
+		transporthttp.WriteError(w, r, err, h.logger)
+		return
+	}
+
+	order, err := action(r.Context(), id.UUID())
+	if err != nil {
+		transporthttp.WriteError(w, r, err, h.logger)
+		return
+	}
+	h.renderOrder(w, r, http.StatusOK, order)
+}
+
+func (h *OrderHandler) History(w http.ResponseWriter, r *http.Request) {
 	id, err := coredomain.ParseID(chi.URLParam(r, "id"))
 	if err != nil {
 		transporthttp.WriteError(w, r, err, h.logger)
 		return
 	}
 
-	order, err := h.services.Orders.CloseOrder(r.Context(), id.UUID())
+	history, err := h.services.Orders.ListStatusHistory(r.Context(), id.UUID())
 	if err != nil {
 		transporthttp.WriteError(w, r, err, h.logger)
 		return
 	}
 	render.Status(r, http.StatusOK)
-	transporthttp.RenderOrLog(w, r, dto.OrderToResponse(order), h.logger)
+	transporthttp.RenderListOrLog(w, r, dto.OrderStatusHistoryToResponse(history), h.logger)
+}
+
+// Stream pushes the caller's order events as Server-Sent Events. A
+// reconnecting client first receives the events recorded after its
+// Last-Event-ID, then the live feed. One that missed too many receives a
+// resync event instead of the rest, and refetches its orders.
+func (h *OrderHandler) Stream(w http.ResponseWriter, r *http.Request) {
+	if !transporthttp.IsEventStreamRequest(r) {
+		transporthttp.WriteError(w, r, coredomain.NewError(transporthttp.CodeNotAcceptable,
+			"this endpoint only serves "+transporthttp.MediaTypeEventStream), h.logger)
+		return
+	}
+
+	last := uuid.Nil
+	if raw := transporthttp.LastEventID(r); raw != "" {
+		id, err := coredomain.ParseID(raw)
+		if err != nil {
+			transporthttp.WriteError(w, r, err, h.logger)
+			return
+		}
+		last = id.UUID()
+	}
+
+	sub, err := h.services.OrderEvents.Subscribe(r.Context())
+	if err != nil {
+		transporthttp.WriteError(w, r, err, h.logger)
+		return
+	}
+	defer sub.Close()
+
+	stream, err := transporthttp.NewSSEStream(w, r, transporthttp.SSEOptions{Retry: orderStreamRetry})
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "failed to start order event stream", "error", err)
+		return
+	}
+
+	encode := func(e domain.OrderEvent) (transporthttp.SSEEvent, bool) {
+		event, err := dto.OrderEventToSSE(e)
+		if err != nil {
+			h.logger.ErrorContext(r.Context(), "failed to encode order event", "error", err, "event_id", e.ID)
+			return transporthttp.SSEEvent{}, false
+		}
+		return event, true
+	}
+
+	// The live feed opens before the replay query runs, so an event recorded
+	// in between arrives through both; live events that were replayed are
+	// skipped.
+	replayed := make(map[uuid.UUID]struct{})
+	if last != uuid.Nil {
+		complete, err := h.services.OrderEvents.Replay(r.Context(), last, func(e domain.OrderEvent) error {
+			replayed[e.ID] = struct{}{}
+			if event, ok := encode(e); ok {
+				return stream.Send(event)
+			}
+			return nil
+		})
+		if err == nil && !complete {
+			err = stream.Send(dto.OrderResyncSSE())
+		}
+		if err != nil {
+			h.logger.DebugContext(r.Context(), "order event stream closed", "error", err)
+			return
+		}
+	}
+	live := func(e domain.OrderEvent) (transporthttp.SSEEvent, bool) {
+		if _, ok := replayed[e.ID]; ok {
+			delete(replayed, e.ID)
+			return transporthttp.SSEEvent{}, false
+		}
+		return encode(e)
+	}
+	if err := transporthttp.RunSSE(r.Context(), stream, sub.Events, live); err != nil {
+		h.logger.DebugContext(r.Context(), "order event stream closed", "error", err)
+	}
 }
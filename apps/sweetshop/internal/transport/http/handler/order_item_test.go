@@ -0,0 +1,216 @@
+//go:build testing
+
+package handler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	coretesting "github.com/bbsbb/go-edge/core/testing"
+)
+
+type OrderItemSuite struct {
+	IntegrationSuite
+}
+
+func (s *OrderItemSuite) AddItem(orderID, productID string, quantity int) map[string]any {
+	rec := s.Do(coretesting.JSONRequest(s.T(), http.MethodPost, "/orders/"+orderID+"/items", map[string]any{
+		"product_id": productID, "quantity": quantity,
+	}))
+	s.Require().Equal(http.StatusCreated, rec.Code, rec.Body.String())
+
+	var resp map[string]any
+	coretesting.DecodeJSON(s.T(), rec, &resp)
+	return resp
+}
+
+func (s *OrderItemSuite) UpdateItem(orderID, itemID string, quantity int) *httptest.ResponseRecorder {
+	return s.Do(coretesting.JSONRequest(s.T(), http.MethodPatch, "/orders/"+orderID+"/items/"+itemID, map[string]any{
+		"quantity": quantity,
+	}))
+}
+
+func (s *OrderItemSuite) RemoveItem(orderID, itemID string) *httptest.ResponseRecorder {
+	return s.Do(httptest.NewRequest(http.MethodDelete, "/orders/"+orderID+"/items/"+itemID, nil))
+}
+
+func (s *OrderItemSuite) GetOrder(id string) map[string]any {
+	rec := s.Do(httptest.NewRequest(http.MethodGet, "/orders/"+id, nil))
+	s.Require().Equal(http.StatusOK, rec.Code)
+
+	var resp map[string]any
+	coretesting.DecodeJSON(s.T(), rec, &resp)
+	return resp
+}
+
+// Available returns the available stock of a product whose stock is tracked.
+func (s *OrderItemSuite) Available(productID string) float64 {
+	rec := s.Do(httptest.NewRequest(http.MethodGet, "/products/"+productID+"/inventory", nil))
+	s.Require().Equal(http.StatusOK, rec.Code)
+
+	var resp map[string]any
+	coretesting.DecodeJSON(s.T(), rec, &resp)
+	return resp["available"].(float64)
+}
+
+// StockedProduct creates a product with onHand units in stock.
+func (s *OrderItemSuite) StockedProduct(onHand int) string {
+	id := s.CreateProduct("Vanilla", "ice_cream", 350)["id"].(string)
+	rec := s.Do(coretesting.JSONRequest(s.T(), http.MethodPost, "/products/"+id+"/inventory/adjustments", map[string]any{
+		"quantity_delta": onHand, "reason": "received",
+	}))
+	s.Require().Equal(http.StatusOK, rec.Code, rec.Body.String())
+	return id
+}
+
+func (s *OrderItemSuite) TestAddItem_MergesSameProduct() {
+	product := s.CreateProduct("Vanilla", "ice_cream", 350)["id"].(string)
+	order := s.OpenOrder()["id"].(string)
+
+	first := s.AddItem(order, product, 2)
+	second := s.AddItem(order, product, 3)
+	s.Assert().Equal(first["id"], second["id"], "the units are added to the existing line")
+	s.Assert().Equal(float64(5), second["quantity"])
+	s.Assert().Equal(eur(1750), second["line_total"])
+
+	got := s.GetOrder(order)
+	s.Assert().Len(got["items"], 1)
+	s.Assert().Equal(eur(1750), got["total"])
+}
+
+func (s *OrderItemSuite) TestAddItem_ConcurrentFirstAddsMerge() {
+	product := s.CreateProduct("Vanilla", "ice_cream", 350)["id"].(string)
+	order := s.OpenOrder()["id"].(string)
+
+	codes := make([]int, 2)
+	var wg sync.WaitGroup
+	for i := range codes {
+		wg.Go(func() {
+			rec := s.Do(coretesting.JSONRequest(s.T(), http.MethodPost, "/orders/"+order+"/items", map[string]any{
+				"product_id": product, "quantity": 1,
+			}))
+			codes[i] = rec.Code
+		})
+	}
+	wg.Wait()
+
+	s.Assert().Equal([]int{http.StatusCreated, http.StatusCreated}, codes, "the later add is merged into the line the earlier one created")
+	got := s.GetOrder(order)
+	s.Require().Len(got["items"], 1)
+	s.Assert().Equal(float64(2), got["items"].([]any)[0].(map[string]any)["quantity"])
+}
+
+func (s *OrderItemSuite) TestAddItem_NewPriceGetsItsOwnLine() {
+	product := s.CreateProduct("Vanilla", "ice_cream", 350)["id"].(string)
+	order := s.OpenOrder()["id"].(string)
+	s.AddItem(order, product, 1)
+
+	rec := s.Do(coretesting.JSONRequest(s.T(), http.MethodPut, "/products/"+product, map[string]any{
+		"name": "Vanilla", "category": "ice_cream", "price": eur(400),
+	}))
+	s.Require().Equal(http.StatusOK, rec.Code, rec.Body.String())
+	s.AddItem(order, product, 1)
+
+	got := s.GetOrder(order)
+	s.Assert().Len(got["items"], 2)
+	s.Assert().Equal(eur(750), got["total"])
+}
+
+func (s *OrderItemSuite) TestUpdateItem() {
+	product := s.CreateProduct("Vanilla", "ice_cream", 350)["id"].(string)
+	order := s.OpenOrder()["id"].(string)
+	item := s.AddItem(order, product, 2)["id"].(string)
+
+	rec := s.UpdateItem(order, item, 4)
+	s.Require().Equal(http.StatusOK, rec.Code, rec.Body.String())
+	var resp map[string]any
+	coretesting.DecodeJSON(s.T(), rec, &resp)
+	s.Assert().Equal(float64(4), resp["quantity"])
+	s.Assert().Equal(eur(1400), resp["line_total"])
+
+	s.Assert().Equal(eur(1400), s.GetOrder(order)["total"])
+}
+
+func (s *OrderItemSuite) TestUpdateItem_Errors() {
+	order := s.OpenOrderWithItems()
+	item := s.GetOrder(order)["items"].([]any)[0].(map[string]any)["id"].(string)
+	unknown := "019505e0-0000-7000-8000-000000000000"
+
+	cases := []struct {
+		name     string
+		order    string
+		item     string
+		body     map[string]any
+		wantCode int
+	}{
+		{"zero quantity", order, item, map[string]any{"quantity": 0}, http.StatusBadRequest},
+		{"unknown field", order, item, map[string]any{"quantity": 1, "note": "x"}, http.StatusBadRequest},
+		{"invalid item ID", order, "not-a-uuid", map[string]any{"quantity": 1}, http.StatusBadRequest},
+		{"item not found", order, unknown, map[string]any{"quantity": 1}, http.StatusNotFound},
+		{"order not found", unknown, item, map[string]any{"quantity": 1}, http.StatusNotFound},
+	}
+	for _, tc := range cases {
+		s.Run(tc.name, func() {
+			rec := s.Do(coretesting.JSONRequest(s.T(), http.MethodPatch, "/orders/"+tc.order+"/items/"+tc.item, tc.body))
+			s.Assert().Equal(tc.wantCode, rec.Code, rec.Body.String())
+		})
+	}
+}
+
+func (s *OrderItemSuite) TestRemoveItem() {
+	vanilla := s.CreateProduct("Vanilla", "ice_cream", 350)["id"].(string)
+	puff := s.CreateProduct("Puff", "marshmallow", 200)["id"].(string)
+	order := s.OpenOrder()["id"].(string)
+	item := s.AddItem(order, vanilla, 2)["id"].(string)
+	s.AddItem(order, puff, 1)
+
+	s.Require().Equal(http.StatusNoContent, s.RemoveItem(order, item).Code)
+
+	got := s.GetOrder(order)
+	s.Assert().Len(got["items"], 1)
+	s.Assert().Equal(eur(200), got["total"])
+
+	s.Assert().Equal(http.StatusNotFound, s.RemoveItem(order, item).Code)
+}
+
+func (s *OrderItemSuite) TestChangeItems_SubmittedOrder() {
+	order := s.OpenOrderWithItems()
+	item := s.GetOrder(order)["items"].([]any)[0].(map[string]any)["id"].(string)
+	rec, _ := s.Transition(order, "submit")
+	s.Require().Equal(http.StatusOK, rec.Code, rec.Body.String())
+
+	s.Assert().Equal(http.StatusUnprocessableEntity, s.UpdateItem(order, item, 5).Code)
+	s.Assert().Equal(http.StatusUnprocessableEntity, s.RemoveItem(order, item).Code)
+	s.Assert().Len(s.GetOrder(order)["items"], 1)
+}
+
+func (s *OrderItemSuite) TestChangeItems_AdjustReservations() {
+	product := s.StockedProduct(10)
+	order := s.OpenOrder()["id"].(string)
+	item := s.AddItem(order, product, 2)["id"].(string)
+	s.Assert().Equal(float64(8), s.Available(product))
+
+	s.AddItem(order, product, 3)
+	s.Assert().Equal(float64(5), s.Available(product), "merged units are reserved")
+
+	s.Require().Equal(http.StatusOK, s.UpdateItem(order, item, 7).Code)
+	s.Assert().Equal(float64(3), s.Available(product))
+
+	s.Require().Equal(http.StatusOK, s.UpdateItem(order, item, 1).Code)
+	s.Assert().Equal(float64(9), s.Available(product), "lowering the quantity releases stock")
+
+	rec := s.UpdateItem(order, item, 11)
+	s.Assert().Equal(http.StatusUnprocessableEntity, rec.Code, rec.Body.String())
+	s.Assert().Equal(float64(9), s.Available(product))
+
+	s.Require().Equal(http.StatusNoContent, s.RemoveItem(order, item).Code)
+	s.Assert().Equal(float64(10), s.Available(product))
+}
+
+func TestOrderItemSuite(t *testing.T) {
+	suite.Run(t, new(OrderItemSuite))
+}
@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+
+	coredomain "github.com/bbsbb/go-edge/core/domain"
+	transporthttp "github.com/bbsbb/go-edge/core/transport/http"
+	"github.com/bbsbb/go-edge/sweetshop/internal/domain"
+	"github.com/bbsbb/go-edge/sweetshop/internal/service"
+	"github.com/bbsbb/go-edge/sweetshop/internal/transport/http/dto"
+)
+
+// List serves a page of the organization's orders, newest first, filtered by
+// the query parameters.
+func (h *OrderHandler) List(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter, err := orderFilter(query)
+	if err != nil {
+		transporthttp.WriteError(w, r, err, h.logger)
+		return
+	}
+	after, err := optionalID(query, "cursor")
+	if err != nil {
+		transporthttp.WriteError(w, r, err, h.logger)
+		return
+	}
+	limit := int32(service.DefaultOrderListLimit)
+	if raw := query.Get("limit"); raw != "" {
+		n, err := strconv.ParseInt(raw, 10, 32)
+		if err != nil {
+			transporthttp.WriteError(w, r, coredomain.NewError(coredomain.CodeValidation, "limit must be an integer"), h.logger)
+			return
+		}
+		limit = int32(n)
+	}
+
+	page, err := h.services.Orders.ListOrders(r.Context(), filter, after, limit)
+	if err != nil {
+		transporthttp.WriteError(w, r, err, h.logger)
+		return
+	}
+	resp, err := dto.OrderPageToResponse(page)
+	if err != nil {
+		transporthttp.WriteError(w, r, err, h.logger)
+		return
+	}
+	transporthttp.RenderOrLog(w, r, resp, h.logger)
+}
+
+func orderFilter(query url.Values) (domain.OrderFilter, error) {
+	var filter domain.OrderFilter
+	if raw := query.Get("status"); raw != "" {
+		status := domain.OrderStatus(raw)
+		filter.Status = &status
+	}
+
+	var err error
+	if filter.CreatedFrom, err = optionalTime(query, "created_from"); err != nil {
+		return filter, err
+	}
+	if filter.CreatedTo, err = optionalTime(query, "created_to"); err != nil {
+		return filter, err
+	}
+	if filter.MinTotal, err = optionalInt(query, "min_total"); err != nil {
+		return filter, err
+	}
+	if filter.MaxTotal, err = optionalInt(query, "max_total"); err != nil {
+		return filter, err
+	}
+	if filter.ProductID, err = optionalID(query, "product_id"); err != nil {
+		return filter, err
+	}
+	return filter, nil
+}
+
+func optionalTime(query url.Values, name string) (*time.Time, error) {
+	raw := query.Get(name)
+	if raw == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return nil, coredomain.NewError(coredomain.CodeValidation, fmt.Sprintf("%s must be an RFC 3339 timestamp", name))
+	}
+	return &t, nil
+}
+
+func optionalInt(query url.Values, name string) (*int64, error) {
+	raw := query.Get(name)
+	if raw == "" {
+		return nil, nil
+	}
+	n, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		return nil, coredomain.NewError(coredomain.CodeValidation, fmt.Sprintf("%s must be an integer", name))
+	}
+	return &n, nil
+}
+
+func optionalID(query url.Values, name string) (*uuid.UUID, error) {
+	raw := query.Get(name)
+	if raw == "" {
+		return nil, nil
+	}
+	id, err := coredomain.ParseID(raw)
+	if err != nil {
+		return nil, coredomain.NewError(coredomain.CodeValidation, fmt.Sprintf("%s must be a UUID", name))
+	}
+	u := id.UUID()
+	return &u, nil
+}
@@ -0,0 +1,150 @@
+//go:build testing
+
+package handler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	coretesting "github.com/bbsbb/go-edge/core/testing"
+)
+
+type OrderListSuite struct {
+	IntegrationSuite
+}
+
+func (s *OrderListSuite) List(query url.Values) map[string]any {
+	rec := s.Do(httptest.NewRequest(http.MethodGet, "/orders?"+query.Encode(), nil))
+	s.Require().Equal(http.StatusOK, rec.Code, rec.Body.String())
+
+	var resp map[string]any
+	coretesting.DecodeJSON(s.T(), rec, &resp)
+	return resp
+}
+
+// IDs returns the IDs of the orders on a page, in order.
+func (s *OrderListSuite) IDs(page map[string]any) []string {
+	var ids []string
+	for _, o := range page["orders"].([]any) {
+		ids = append(ids, o.(map[string]any)["id"].(string))
+	}
+	return ids
+}
+
+func (s *OrderListSuite) TestListOrders_NewestFirstWithItems() {
+	product := s.CreateProduct("Vanilla", "ice_cream", 350)
+	first := s.OpenOrder()["id"].(string)
+	second := s.OpenOrder()["id"].(string)
+	rec := s.Do(coretesting.JSONRequest(s.T(), http.MethodPost, "/orders/"+first+"/items", map[string]any{
+		"product_id": product["id"], "quantity": 2,
+	}))
+	s.Require().Equal(http.StatusCreated, rec.Code)
+
+	page := s.List(nil)
+	s.Assert().Equal([]string{second, first}, s.IDs(page))
+	s.Assert().Nil(page["next_cursor"])
+
+	orders := page["orders"].([]any)
+	s.Assert().Empty(orders[0].(map[string]any)["items"])
+	got := orders[1].(map[string]any)
+	s.Assert().Equal("open", got["status"])
+	s.Assert().Equal(eur(700), got["subtotal"])
+	s.Assert().Nil(got["total"], "open orders have no fixed total")
+	items := got["items"].([]any)
+	s.Require().Len(items, 1)
+	item := items[0].(map[string]any)
+	s.Assert().Equal(product["id"], item["product_id"])
+	s.Assert().Equal("Vanilla", item["product_name"])
+	s.Assert().Equal(float64(2), item["quantity"])
+	s.Assert().Equal(eur(350), item["unit_price"])
+	s.Assert().Equal(eur(700), item["line_total"])
+}
+
+func (s *OrderListSuite) TestListOrders_Pagination() {
+	var ids []string
+	for range 5 {
+		ids = append([]string{s.OpenOrder()["id"].(string)}, ids...)
+	}
+
+	var got []string
+	query := url.Values{"limit": {"2"}}
+	for pages := 0; ; pages++ {
+		s.Require().Less(pages, 3)
+		page := s.List(query)
+		got = append(got, s.IDs(page)...)
+		if page["next_cursor"] == nil {
+			break
+		}
+		query.Set("cursor", page["next_cursor"].(string))
+	}
+	s.Assert().Equal(ids, got)
+}
+
+func (s *OrderListSuite) TestListOrders_Filters() {
+	vanilla := s.CreateProduct("Vanilla", "ice_cream", 350)["id"].(string)
+	submitted := s.OpenOrderWithItems()
+	rec, _ := s.Transition(submitted, "submit")
+	s.Require().Equal(http.StatusOK, rec.Code, rec.Body.String())
+	cancelled := s.OpenOrder()["id"].(string)
+	rec, _ = s.Transition(cancelled, "cancel")
+	s.Require().Equal(http.StatusOK, rec.Code)
+	open := s.OpenOrder()["id"].(string)
+	rec = s.Do(coretesting.JSONRequest(s.T(), http.MethodPost, "/orders/"+open+"/items", map[string]any{
+		"product_id": vanilla, "quantity": 1,
+	}))
+	s.Require().Equal(http.StatusCreated, rec.Code)
+
+	future := time.Now().Add(time.Hour).Format(time.RFC3339)
+	past := time.Now().Add(-time.Hour).Format(time.RFC3339)
+	cases := []struct {
+		name  string
+		query url.Values
+		want  []string
+	}{
+		{"status", url.Values{"status": {"cancelled"}}, []string{cancelled}},
+		{"product", url.Values{"product_id": {vanilla}}, []string{open}},
+		{"min total", url.Values{"min_total": {"700"}}, []string{submitted}},
+		{"max total below", url.Values{"max_total": {"699"}}, nil},
+		{"total range", url.Values{"min_total": {"100"}, "max_total": {"700"}}, []string{submitted}},
+		{"created from", url.Values{"created_from": {future}}, nil},
+		{"created range", url.Values{"created_from": {past}, "created_to": {future}}, []string{open, cancelled, submitted}},
+		{"combined", url.Values{"status": {"open"}, "product_id": {vanilla}}, []string{open}},
+	}
+	for _, tc := range cases {
+		s.Run(tc.name, func() {
+			s.Assert().Equal(tc.want, s.IDs(s.List(tc.query)))
+		})
+	}
+}
+
+func (s *OrderListSuite) TestListOrders_ValidationErrors() {
+	cases := []struct {
+		name  string
+		query url.Values
+	}{
+		{"status", url.Values{"status": {"closed"}}},
+		{"limit", url.Values{"limit": {"0"}}},
+		{"limit above maximum", url.Values{"limit": {"201"}}},
+		{"cursor", url.Values{"cursor": {"not-a-uuid"}}},
+		{"product", url.Values{"product_id": {"not-a-uuid"}}},
+		{"created from", url.Values{"created_from": {"yesterday"}}},
+		{"empty date range", url.Values{"created_from": {"2026-01-02T00:00:00Z"}, "created_to": {"2026-01-01T00:00:00Z"}}},
+		{"total", url.Values{"min_total": {"1.50"}}},
+		{"empty total range", url.Values{"min_total": {"500"}, "max_total": {"100"}}},
+	}
+	for _, tc := range cases {
+		s.Run(tc.name, func() {
+			rec := s.Do(httptest.NewRequest(http.MethodGet, "/orders?"+tc.query.Encode(), nil))
+			s.Assert().Equal(http.StatusBadRequest, rec.Code, rec.Body.String())
+		})
+	}
+}
+
+func TestOrderListSuite(t *testing.T) {
+	suite.Run(t, new(OrderListSuite))
+}
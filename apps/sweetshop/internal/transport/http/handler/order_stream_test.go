@@ -0,0 +1,80 @@
+//go:build testing
+
+package handler_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+// zeroV7 sorts before every real UUIDv7, so resuming from it replays everything.
+const zeroV7 = "00000000-0000-7000-8000-000000000000"
+
+type OrderStreamSuite struct {
+	IntegrationSuite
+}
+
+// stream runs GET /orders/stream until the handler has had time to flush the
+// replayed events, then disconnects.
+func (s *OrderStreamSuite) stream(lastEventID string) *httptest.ResponseRecorder {
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	req := httptest.NewRequestWithContext(ctx, http.MethodGet, "/orders/stream", nil)
+	req.Header.Set("Accept", "text/event-stream")
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+	return s.Do(req)
+}
+
+func (s *OrderStreamSuite) TestStream_ReplaysMissedEvents() {
+	order := s.OpenOrder()
+	rec, _ := s.Transition(order["id"].(string), "cancel")
+	s.Require().Equal(http.StatusOK, rec.Code)
+
+	rec = s.stream(zeroV7)
+
+	s.Assert().Equal(http.StatusOK, rec.Code)
+	s.Assert().Equal("text/event-stream", rec.Header().Get("Content-Type"))
+	body := rec.Body.String()
+	opened := strings.Index(body, "event: order.opened\n")
+	changed := strings.Index(body, "event: order.status_changed\n")
+	s.Require().GreaterOrEqual(opened, 0, body)
+	s.Require().Greater(changed, opened, body)
+	s.Assert().Contains(body, `"order_id":"`+order["id"].(string)+`"`)
+	s.Assert().Contains(body, `"status":"cancelled"`)
+	s.Assert().Contains(body, "retry: 3000\n")
+	s.Assert().NotContains(body, "event: resync\n", "every missed event was replayed")
+}
+
+func (s *OrderStreamSuite) TestStream_WithoutLastEventIDSkipsReplay() {
+	s.OpenOrder()
+
+	rec := s.stream("")
+
+	s.Assert().Equal(http.StatusOK, rec.Code)
+	s.Assert().NotContains(rec.Body.String(), "event: order.opened")
+}
+
+func (s *OrderStreamSuite) TestStream_InvalidLastEventID() {
+	rec := s.stream("not-a-uuid")
+
+	s.Assert().Equal(http.StatusBadRequest, rec.Code)
+}
+
+func (s *OrderStreamSuite) TestStream_RequiresEventStreamAccept() {
+	rec := s.Do(httptest.NewRequest(http.MethodGet, "/orders/stream", nil))
+
+	s.Assert().Equal(http.StatusNotAcceptable, rec.Code)
+}
+
+func TestOrderStreamSuite(t *testing.T) {
+	suite.Run(t, new(OrderStreamSuite))
+}
@@ -22,7 +22,8 @@ func (s *OrderSuite) TestOpenOrder() {
 
 	s.Assert().NotEmpty(resp["id"])
 	s.Assert().Equal("open", resp["status"])
-	s.Assert().Equal(float64(0), resp["total_cents"])
+	s.Assert().Equal("EUR", resp["currency"])
+	s.Assert().Equal(eur(0), resp["total"])
 }
 
 func (s *OrderSuite) TestGetOrder() {
@@ -66,8 +67,8 @@ func (s *OrderSuite) TestAddItem() {
 	s.Require().NoError(json.NewDecoder(rec.Body).Decode(&resp))
 	s.Assert().Equal(product["id"], resp["product_id"])
 	s.Assert().Equal(float64(2), resp["quantity"])
-	s.Assert().Equal(float64(350), resp["price_cents"])
-	s.Assert().Equal(float64(700), resp["line_total_cents"])
+	s.Assert().Equal(eur(350), resp["unit_price"])
+	s.Assert().Equal(eur(700), resp["line_total"])
 }
 
 func (s *OrderSuite) TestAddItem_OrderWithTotal() {
@@ -95,7 +96,7 @@ func (s *OrderSuite) TestAddItem_OrderWithTotal() {
 	s.Assert().Equal(http.StatusOK, rec.Code)
 	var resp map[string]any
 	s.Require().NoError(json.NewDecoder(rec.Body).Decode(&resp))
-	s.Assert().Equal(float64(1300), resp["total_cents"])
+	s.Assert().Equal(eur(1300), resp["total"])
 	items := resp["items"].([]any)
 	s.Assert().Len(items, 2)
 }
@@ -109,6 +110,8 @@ func (s *OrderSuite) TestAddItem_ValidationErrors() {
 		wantCode int
 	}{
 		{"invalid product ID", map[string]any{"product_id": "not-a-uuid", "quantity": 1}, http.StatusBadRequest},
+		{"zero quantity", map[string]any{"product_id": "019505e0-0000-7000-8000-000000000000", "quantity": 0}, http.StatusBadRequest},
+		{"unknown field", map[string]any{"product_id": "019505e0-0000-7000-8000-000000000000", "quantity": 1, "note": "x"}, http.StatusBadRequest},
 		{"product not found", map[string]any{"product_id": "019505e0-0000-7000-8000-000000000000", "quantity": 1}, http.StatusNotFound},
 	}
 	for _, tc := range cases {
@@ -120,57 +123,106 @@ func (s *OrderSuite) TestAddItem_ValidationErrors() {
 	}
 }
 
-func (s *OrderSuite) TestAddItem_ClosedOrder() {
+func (s *OrderSuite) TestAddItem_CancelledOrder() {
 	product := s.CreateProduct("Vanilla", "ice_cream", 350)
 	order := s.OpenOrder()
 
-	closeReq := httptest.NewRequest(http.MethodPost, "/orders/"+order["id"].(string)+"/close", nil)
-	closeRec := s.Do(closeReq)
-	s.Require().Equal(http.StatusOK, closeRec.Code)
+	rec, _ := s.Transition(order["id"].(string), "cancel")
+	s.Require().Equal(http.StatusOK, rec.Code)
 
 	req := coretesting.JSONRequest(s.T(), http.MethodPost, "/orders/"+order["id"].(string)+"/items", map[string]any{
 		"product_id": product["id"], "quantity": 1,
 	})
-	rec := s.Do(req)
+	rec = s.Do(req)
 
 	s.Assert().Equal(http.StatusUnprocessableEntity, rec.Code)
 }
 
-func (s *OrderSuite) TestCloseOrder() {
-	order := s.OpenOrder()
+func (s *OrderSuite) TestLifecycle() {
+	id := s.OpenOrderWithItems()
 
-	req := httptest.NewRequest(http.MethodPost, "/orders/"+order["id"].(string)+"/close", nil)
-	rec := s.Do(req)
+	for _, step := range []struct {
+		action, status, stampedAt string
+	}{
+		{"submit", "submitted", "submitted_at"},
+		{"pay", "paid", "paid_at"},
+		{"fulfil", "fulfilled", "fulfilled_at"},
+	} {
+		rec, resp := s.Transition(id, step.action)
+		s.Require().Equal(http.StatusOK, rec.Code, rec.Body.String())
+		s.Assert().Equal(step.status, resp["status"])
+		s.Assert().NotNil(resp[step.stampedAt])
+	}
 
-	s.Assert().Equal(http.StatusOK, rec.Code)
-	var resp map[string]any
-	s.Require().NoError(json.NewDecoder(rec.Body).Decode(&resp))
-	s.Assert().Equal("closed", resp["status"])
+	rec := s.Do(httptest.NewRequest(http.MethodGet, "/orders/"+id+"/history", nil))
+	s.Require().Equal(http.StatusOK, rec.Code)
+	var history []map[string]any
+	coretesting.DecodeJSON(s.T(), rec, &history)
+	s.Require().Len(history, 4)
+	s.Assert().NotContains(history[0], "from")
+	s.Assert().Equal("open", history[0]["to"])
+	for i, to := range []string{"submitted", "paid", "fulfilled"} {
+		s.Assert().Equal(history[i]["to"], history[i+1]["from"])
+		s.Assert().Equal(to, history[i+1]["to"])
+	}
 }
 
-func (s *OrderSuite) TestCloseOrder_AlreadyClosed() {
-	order := s.OpenOrder()
-
-	req := httptest.NewRequest(http.MethodPost, "/orders/"+order["id"].(string)+"/close", nil)
-	rec := s.Do(req)
-	s.Require().Equal(http.StatusOK, rec.Code)
+func (s *OrderSuite) TestTransition_NotAllowed() {
+	cases := []struct {
+		name    string
+		empty   bool
+		setup   []string
+		action  string
+		message string
+	}{
+		{"submit empty order", true, nil, "submit", "without items"},
+		{"pay open order", false, nil, "pay", "cannot pay an order that is open"},
+		{"fulfil submitted order", false, []string{"submit"}, "fulfil", "cannot fulfil an order that is submitted"},
+		{"submit twice", false, []string{"submit"}, "submit", "cannot submit an order that is submitted"},
+		{"cancel fulfilled order", false, []string{"submit", "pay", "fulfil"}, "cancel", "cannot cancel an order that is fulfilled"},
+		{"submit cancelled order", false, []string{"cancel"}, "submit", "cannot submit an order that is cancelled"},
+	}
+	for _, tc := range cases {
+		s.Run(tc.name, func() {
+			var id string
+			if tc.empty {
+				id = s.OpenOrder()["id"].(string)
+			} else {
+				id = s.OpenOrderWithItems()
+			}
+			for _, action := range tc.setup {
+				rec, _ := s.Transition(id, action)
+				s.Require().Equal(http.StatusOK, rec.Code, rec.Body.String())
+			}
+
+			rec, _ := s.Transition(id, tc.action)
+
+			s.Assert().Equal(http.StatusUnprocessableEntity, rec.Code)
+			s.Assert().Contains(rec.Body.String(), tc.message)
+		})
+	}
+}
 
-	req = httptest.NewRequest(http.MethodPost, "/orders/"+order["id"].(string)+"/close", nil)
-	rec = s.Do(req)
+func (s *OrderSuite) TestCancel_OpenOrder() {
+	rec, resp := s.Transition(s.OpenOrder()["id"].(string), "cancel")
 
-	s.Assert().Equal(http.StatusUnprocessableEntity, rec.Code)
+	s.Require().Equal(http.StatusOK, rec.Code)
+	s.Assert().Equal("cancelled", resp["status"])
+	s.Assert().NotNil(resp["cancelled_at"])
+	s.Assert().Nil(resp["submitted_at"])
 }
 
-func (s *OrderSuite) TestCloseOrder_NotFound() {
-	req := httptest.NewRequest(http.MethodPost, "/orders/019505e0-0000-7000-8000-000000000000/close", nil)
-	rec := s.Do(req)
-
+func (s *OrderSuite) TestTransition_NotFound() {
+	for _, action := range []string{"submit", "pay", "fulfil", "cancel"} {
+		rec, _ := s.Transition("019505e0-0000-7000-8000-000000000000", action)
+		s.Assert().Equal(http.StatusNotFound, rec.Code, action)
+	}
+	rec := s.Do(httptest.NewRequest(http.MethodGet, "/orders/019505e0-0000-7000-8000-000000000000/history", nil))
 	s.Assert().Equal(http.StatusNotFound, rec.Code)
 }
 
-func (s *OrderSuite) TestCloseOrder_InvalidID() {
-	req := httptest.NewRequest(http.MethodPost, "/orders/bad-id/close", nil)
-	rec := s.Do(req)
+func (s *OrderSuite) TestTransition_InvalidID() {
+	rec, _ := s.Transition("bad-id", "submit")
 
 	s.Assert().Equal(http.StatusBadRequest, rec.Code)
 }
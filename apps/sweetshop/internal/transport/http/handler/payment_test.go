@@ -0,0 +1,243 @@
+//go:build testing
+
+package handler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+
+	coretesting "github.com/bbsbb/go-edge/core/testing"
+	"github.com/bbsbb/go-edge/sweetshop/internal/domain"
+)
+
+type PaymentSuite struct {
+	IntegrationSuite
+}
+
+func (s *PaymentSuite) GetOrder(id string) map[string]any {
+	rec := s.Do(httptest.NewRequest(http.MethodGet, "/orders/"+id, nil))
+	s.Require().Equal(http.StatusOK, rec.Code)
+
+	var resp map[string]any
+	coretesting.DecodeJSON(s.T(), rec, &resp)
+	return resp
+}
+
+// Advance runs actions that are expected to succeed.
+func (s *PaymentSuite) Advance(id string, actions ...string) map[string]any {
+	var resp map[string]any
+	for _, action := range actions {
+		var rec *httptest.ResponseRecorder
+		rec, resp = s.Transition(id, action)
+		s.Require().Equal(http.StatusOK, rec.Code, rec.Body.String())
+	}
+	return resp
+}
+
+func payment(order map[string]any) map[string]any {
+	return order["payment"].(map[string]any)
+}
+
+func attempts(order map[string]any) []map[string]any {
+	raw := payment(order)["attempts"].([]any)
+	out := make([]map[string]any, len(raw))
+	for i, a := range raw {
+		out[i] = a.(map[string]any)
+	}
+	return out
+}
+
+func operations(order map[string]any) []any {
+	var ops []any
+	for _, a := range attempts(order) {
+		ops = append(ops, a["operation"])
+	}
+	return ops
+}
+
+func (s *PaymentSuite) TestOpenOrderIsUnpaid() {
+	order := s.GetOrder(s.OpenOrderWithItems())
+
+	s.Assert().Equal(map[string]any{
+		"status": "unpaid", "captured": eur(0), "refunded": eur(0), "attempts": []any{},
+	}, order["payment"])
+}
+
+func (s *PaymentSuite) TestSubmitAuthorizesAndPayCaptures() {
+	id := s.OpenOrderWithItems()
+
+	order := s.Advance(id, "submit")
+	s.Assert().Equal("authorized", payment(order)["status"])
+	s.Assert().Equal([]any{"authorize"}, operations(order))
+
+	order = s.Advance(id, "pay")
+	s.Assert().Equal("captured", payment(order)["status"])
+	s.Assert().Equal(eur(700), payment(order)["captured"])
+
+	got := attempts(order)
+	s.Assert().Equal([]any{"authorize", "capture"}, operations(order))
+	for _, a := range got {
+		s.Assert().Equal("succeeded", a["status"])
+		s.Assert().Equal(eur(700), a["amount"])
+		s.Assert().Equal(got[0]["reference"], a["reference"])
+	}
+}
+
+func (s *PaymentSuite) TestDeclinedAuthorizationKeepsOrderOpen() {
+	id := s.OpenOrderWithItems()
+	s.Payments.DeclineNext(domain.PaymentOperationAuthorize, "insufficient funds")
+
+	rec, _ := s.Transition(id, "submit")
+
+	s.Assert().Equal(http.StatusUnprocessableEntity, rec.Code)
+	s.Assert().Contains(rec.Body.String(), "insufficient funds")
+	order := s.GetOrder(id)
+	s.Assert().Equal("open", order["status"])
+	got := attempts(order)
+	s.Require().Len(got, 1)
+	s.Assert().Equal("failed", got[0]["status"])
+	s.Assert().Equal("payment declined: insufficient funds", got[0]["error"])
+	s.Assert().NotContains(got[0], "reference")
+
+	s.Advance(id, "submit")
+}
+
+func (s *PaymentSuite) TestDeclinedCaptureIsRetriedOnTheSameAuthorization() {
+	id := s.Advance(s.OpenOrderWithItems(), "submit")["id"].(string)
+	s.Payments.DeclineNext(domain.PaymentOperationCapture, "processor unavailable")
+
+	rec, _ := s.Transition(id, "pay")
+	s.Require().Equal(http.StatusUnprocessableEntity, rec.Code)
+	s.Assert().Equal("submitted", s.GetOrder(id)["status"])
+
+	order := s.Advance(id, "pay")
+	s.Assert().Equal("paid", order["status"])
+
+	got := attempts(order)
+	s.Assert().Equal([]any{"authorize", "capture", "capture"}, operations(order))
+	s.Assert().Equal("failed", got[1]["status"])
+	s.Assert().Equal("succeeded", got[2]["status"])
+	s.Assert().Equal(got[0]["reference"], got[2]["reference"])
+}
+
+func (s *PaymentSuite) TestCancelSubmittedOrderVoidsAuthorization() {
+	order := s.Advance(s.OpenOrderWithItems(), "submit", "cancel")
+
+	s.Assert().Equal("cancelled", order["status"])
+	s.Assert().Equal("voided", payment(order)["status"])
+	s.Assert().Equal([]any{"authorize", "void"}, operations(order))
+}
+
+func (s *PaymentSuite) TestDeclinedVoidKeepsOrderSubmitted() {
+	id := s.Advance(s.OpenOrderWithItems(), "submit")["id"].(string)
+	s.Payments.DeclineNext(domain.PaymentOperationVoid, "processor unavailable")
+
+	rec, _ := s.Transition(id, "cancel")
+
+	s.Assert().Equal(http.StatusUnprocessableEntity, rec.Code)
+	order := s.GetOrder(id)
+	s.Assert().Equal("submitted", order["status"])
+	s.Assert().Equal("authorized", payment(order)["status"])
+	s.Assert().Equal([]any{"authorize", "void"}, operations(order))
+	s.Assert().Equal("failed", attempts(order)[1]["status"])
+}
+
+func (s *PaymentSuite) TestCancelPaidOrderRefundsCapture() {
+	order := s.Advance(s.OpenOrderWithItems(), "submit", "pay", "cancel")
+
+	s.Assert().Equal("cancelled", order["status"])
+	s.Assert().Equal("refunded", payment(order)["status"])
+	s.Assert().Equal(eur(700), payment(order)["refunded"])
+	s.Assert().Equal([]any{"authorize", "capture", "refund"}, operations(order))
+}
+
+func (s *PaymentSuite) TestDeclinedRefundKeepsOrderPaid() {
+	id := s.Advance(s.OpenOrderWithItems(), "submit", "pay")["id"].(string)
+	s.Payments.DeclineNext(domain.PaymentOperationRefund, "card expired")
+
+	rec, _ := s.Transition(id, "cancel")
+
+	s.Assert().Equal(http.StatusUnprocessableEntity, rec.Code)
+	s.Assert().Equal("paid", s.GetOrder(id)["status"])
+}
+
+func (s *PaymentSuite) TestRefundFulfilledOrder() {
+	id := s.Advance(s.OpenOrderWithItems(), "submit", "pay", "fulfil")["id"].(string)
+
+	order := s.Advance(id, "refund")
+
+	s.Assert().Equal("fulfilled", order["status"])
+	s.Assert().Equal("refunded", payment(order)["status"])
+	s.Assert().Equal(eur(700), payment(order)["refunded"])
+
+	rec, _ := s.Transition(id, "refund")
+	s.Assert().Equal(http.StatusUnprocessableEntity, rec.Code, "a refunded order cannot be refunded again")
+}
+
+func (s *PaymentSuite) TestRefund_ConcurrentRefundsRefundOnce() {
+	id := s.Advance(s.OpenOrderWithItems(), "submit", "pay", "fulfil")["id"].(string)
+
+	codes := make([]int, 2)
+	var wg sync.WaitGroup
+	for i := range codes {
+		wg.Go(func() {
+			rec, _ := s.Transition(id, "refund")
+			codes[i] = rec.Code
+		})
+	}
+	wg.Wait()
+
+	s.Assert().ElementsMatch([]int{http.StatusOK, http.StatusUnprocessableEntity}, codes)
+	order := s.GetOrder(id)
+	s.Assert().Equal(eur(700), payment(order)["refunded"])
+	s.Assert().Equal([]any{"authorize", "capture", "refund"}, operations(order))
+}
+
+func (s *PaymentSuite) TestSubmit_OrderIsClaimedDuringGatewayCall() {
+	id := s.OpenOrderWithItems()
+	product := s.CreateProduct("Mango "+uuid.NewString()[:8], "ice_cream", 300)
+	release := s.Payments.HoldNext(domain.PaymentOperationAuthorize)
+	defer release()
+
+	codes := make(chan int, 2)
+	for range 2 {
+		go func() {
+			rec, _ := s.Transition(id, "submit")
+			codes <- rec.Code
+		}()
+	}
+
+	s.Require().Equal(http.StatusUnprocessableEntity, <-codes, "the second submit finds the order claimed")
+	s.Assert().Equal("open", s.GetOrder(id)["status"], "reads are not blocked by the gateway call")
+	rec := s.Do(coretesting.JSONRequest(s.T(), http.MethodPost, "/orders/"+id+"/items", map[string]any{
+		"product_id": product["id"], "quantity": 1,
+	}))
+	s.Assert().Equal(http.StatusUnprocessableEntity, rec.Code, "items cannot change while the order is claimed")
+
+	release()
+	s.Require().Equal(http.StatusOK, <-codes)
+	order := s.GetOrder(id)
+	s.Assert().Equal("submitted", order["status"])
+	s.Assert().Equal([]any{"authorize"}, operations(order))
+}
+
+func (s *PaymentSuite) TestRefund_PaidOrderIsCancelledInstead() {
+	rec, _ := s.Transition(s.Advance(s.OpenOrderWithItems(), "submit", "pay")["id"].(string), "refund")
+
+	s.Assert().Equal(http.StatusUnprocessableEntity, rec.Code)
+}
+
+func (s *PaymentSuite) TestRefund_NotFound() {
+	rec, _ := s.Transition("019505e0-0000-7000-8000-000000000000", "refund")
+
+	s.Assert().Equal(http.StatusNotFound, rec.Code)
+}
+
+func TestPaymentSuite(t *testing.T) {
+	suite.Run(t, new(PaymentSuite))
+}
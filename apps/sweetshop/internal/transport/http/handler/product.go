@@ -3,7 +3,9 @@ package handler
 
 import (
 	"log/slog"
+	"mime"
 	"net/http"
+	"strconv"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/render"
@@ -33,6 +35,77 @@ func (h *ProductHandler) List(w http.ResponseWriter, r *http.Request) {
 	transporthttp.RenderListOrLog(w, r, dto.ProductListToResponse(products), h.logger)
 }
 
+// Search serves the live products best matching the q query parameter, best
+// first.
+func (h *ProductHandler) Search(w http.ResponseWriter, r *http.Request) {
+	query := domain.ProductSearch{Text: r.URL.Query().Get("q"), Limit: service.DefaultProductSearchLimit}
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		n, err := strconv.ParseInt(raw, 10, 32)
+		if err != nil {
+			transporthttp.WriteError(w, r, coredomain.NewError(coredomain.CodeValidation, "limit must be an integer"), h.logger)
+			return
+		}
+		query.Limit = int32(n)
+	}
+
+	matches, err := h.services.Products.Search(r.Context(), query)
+	if err != nil {
+		transporthttp.WriteError(w, r, err, h.logger)
+		return
+	}
+	transporthttp.RenderListOrLog(w, r, dto.ProductMatchesToResponse(matches), h.logger)
+}
+
+// Import creates and updates products from a CSV or NDJSON body, told apart by
+// its Content-Type, and serves the report of what it did. With dry_run=true it
+// only validates.
+func (h *ProductHandler) Import(w http.ResponseWriter, r *http.Request) {
+	dryRun := false
+	if raw := r.URL.Query().Get("dry_run"); raw != "" {
+		var err error
+		if dryRun, err = strconv.ParseBool(raw); err != nil {
+			transporthttp.WriteError(w, r, coredomain.NewError(coredomain.CodeValidation, "dry_run must be true or false"), h.logger)
+			return
+		}
+	}
+
+	var (
+		rows []domain.ProductImportRow
+		err  error
+	)
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	switch mediaType {
+	case transporthttp.CSVEncoder{}.MediaType():
+		rows, err = dto.ReadProductRowsCSV(r.Context(), r.Body)
+	case transporthttp.NDJSONEncoder{}.MediaType():
+		rows, err = dto.ReadProductRowsNDJSON(r.Context(), r.Body)
+	default:
+		err = coredomain.NewError(coredomain.CodeValidation, "Content-Type must be text/csv or application/x-ndjson")
+	}
+	if err != nil {
+		transporthttp.WriteError(w, r, err, h.logger)
+		return
+	}
+
+	report, err := h.services.Products.Import(r.Context(), rows, dryRun)
+	if err != nil {
+		transporthttp.WriteError(w, r, err, h.logger)
+		return
+	}
+	render.Status(r, http.StatusOK)
+	transporthttp.RenderOrLog(w, r, dto.ProductImportReportToResponse(report), h.logger)
+}
+
+// Export serves the live products in the shape Import reads, as CSV or NDJSON
+// when the client accepts it, encoding each as it is read.
+func (h *ProductHandler) Export(w http.ResponseWriter, r *http.Request) {
+	transporthttp.StreamListOrLog(w, r, h.logger, func(write func(render.Renderer) error) error {
+		return h.services.Products.Export(r.Context(), func(p *domain.Product) error {
+			return write(dto.ProductRowToResponse(p))
+		})
+	})
+}
+
 func (h *ProductHandler) Get(w http.ResponseWriter, r *http.Request) {
 	id, err := coredomain.ParseID(chi.URLParam(r, "id"))
 	if err != nil {
@@ -51,12 +124,12 @@ func (h *ProductHandler) Get(w http.ResponseWriter, r *http.Request) {
 
 func (h *ProductHandler) Create(w http.ResponseWriter, r *http.Request) {
 	var req dto.CreateProductRequest
-	if err := render.Bind(r, &req); err != nil {
-		transporthttp.WriteError(w, r, coredomain.NewError(coredomain.CodeValidation, "invalid request body"), h.logger)
+	if err := transporthttp.Bind(r, &req); err != nil {
+		transporthttp.WriteError(w, r, err, h.logger)
 		return
 	}
 
-	product, err := h.services.Products.Create(r.Context(), req.Name, domain.ProductCategory(req.Category), req.PriceCents)
+	product, err := h.services.Products.Create(r.Context(), req.Fields())
 	if err != nil {
 		transporthttp.WriteError(w, r, err, h.logger)
 		return
@@ -73,12 +146,12 @@ func (h *ProductHandler) Update(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req dto.UpdateProductRequest
-	if err := render.Bind(r, &req); err != nil {
-		transporthttp.WriteError(w, r, coredomain.NewError(coredomain.CodeValidation, "invalid request body"), h.logger)
+	if err := transporthttp.Bind(r, &req); err != nil {
+		transporthttp.WriteError(w, r, err, h.logger)
 		return
 	}
 
-	product, err := h.services.Products.Update(r.Context(), id.UUID(), req.Name, domain.ProductCategory(req.Category), req.PriceCents)
+	product, err := h.services.Products.Update(r.Context(), id.UUID(), req.Fields())
 	if err != nil {
 		transporthttp.WriteError(w, r, err, h.logger)
 		return
@@ -100,3 +173,62 @@ func (h *ProductHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	}
 	w.WriteHeader(http.StatusNoContent)
 }
+
+func (h *ProductHandler) Restore(w http.ResponseWriter, r *http.Request) {
+	id, err := coredomain.ParseID(chi.URLParam(r, "id"))
+	if err != nil {
+		transporthttp.WriteError(w, r, err, h.logger)
+		return
+	}
+
+	product, err := h.services.Products.Restore(r.Context(), id.UUID())
+	if err != nil {
+		transporthttp.WriteError(w, r, err, h.logger)
+		return
+	}
+	render.Status(r, http.StatusOK)
+	transporthttp.RenderOrLog(w, r, dto.ProductToResponse(product), h.logger)
+}
+
+func (h *ProductHandler) Options(w http.ResponseWriter, r *http.Request) {
+	id, err := coredomain.ParseID(chi.URLParam(r, "id"))
+	if err != nil {
+		transporthttp.WriteError(w, r, err, h.logger)
+		return
+	}
+
+	groups, err := h.services.Products.ListOptions(r.Context(), id.UUID())
+	if err != nil {
+		transporthttp.WriteError(w, r, err, h.logger)
+		return
+	}
+	render.Status(r, http.StatusOK)
+	transporthttp.RenderOrLog(w, r, dto.ProductOptionsToResponse(groups), h.logger)
+}
+
+func (h *ProductHandler) ReplaceOptions(w http.ResponseWriter, r *http.Request) {
+	id, err := coredomain.ParseID(chi.URLParam(r, "id"))
+	if err != nil {
+		transporthttp.WriteError(w, r, err, h.logger)
+		return
+	}
+
+	var req dto.ProductOptionsRequest
+	if err := transporthttp.Bind(r, &req); err != nil {
+		transporthttp.WriteError(w, r, err, h.logger)
+		return
+	}
+	groups, err := req.OptionGroups()
+	if err != nil {
+		transporthttp.WriteError(w, r, err, h.logger)
+		return
+	}
+
+	groups, err = h.services.Products.ReplaceOptions(r.Context(), id.UUID(), groups)
+	if err != nil {
+		transporthttp.WriteError(w, r, err, h.logger)
+		return
+	}
+	render.Status(r, http.StatusOK)
+	transporthttp.RenderOrLog(w, r, dto.ProductOptionsToResponse(groups), h.logger)
+}
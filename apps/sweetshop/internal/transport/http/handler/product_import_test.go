@@ -0,0 +1,145 @@
+//go:build testing
+
+package handler_test
+
+import (
+	"encoding/csv"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	coretesting "github.com/bbsbb/go-edge/core/testing"
+)
+
+type ProductImportSuite struct {
+	IntegrationSuite
+}
+
+func (s *ProductImportSuite) Import(contentType, query, body string) (*httptest.ResponseRecorder, map[string]any) {
+	req := httptest.NewRequest(http.MethodPost, "/products/import"+query, strings.NewReader(body))
+	req.Header.Set("Content-Type", contentType)
+	rec := s.Do(req)
+	var resp map[string]any
+	if rec.Code == http.StatusOK {
+		coretesting.DecodeJSON(s.T(), rec, &resp)
+	}
+	return rec, resp
+}
+
+func (s *ProductImportSuite) ProductNames() []any {
+	rec := s.Do(httptest.NewRequest(http.MethodGet, "/products", nil))
+	s.Require().Equal(http.StatusOK, rec.Code)
+	var resp []map[string]any
+	coretesting.DecodeJSON(s.T(), rec, &resp)
+	names := []any{}
+	for _, p := range resp {
+		names = append(names, p["name"])
+	}
+	return names
+}
+
+func (s *ProductImportSuite) TestImport_CSV() {
+	rec := s.Do(coretesting.JSONRequest(s.T(), http.MethodPost, "/products", map[string]any{
+		"name": "Rocky Road", "category": "ice_cream", "price": eur(350), "sku": "IC-RR",
+	}))
+	s.Require().Equal(http.StatusCreated, rec.Code)
+
+	rec, resp := s.Import("text/csv; charset=utf-8", "", strings.Join([]string{
+		"name,category,price,sku,active,sort_order",
+		`Rocky Road Deluxe,ice_cream,"{""amount"":400,""currency"":""EUR""}",IC-RR,,2`,
+		`Marshmallow Twist,marshmallow,"{""amount"":250,""currency"":""EUR""}",,false,`,
+	}, "\n"))
+
+	s.Require().Equal(http.StatusOK, rec.Code, rec.Body.String())
+	s.Assert().Equal(false, resp["dry_run"])
+	s.Assert().Equal(float64(2), resp["rows"])
+	s.Assert().Equal(float64(1), resp["created"])
+	s.Assert().Equal(float64(1), resp["updated"])
+	s.Assert().Empty(resp["errors"])
+	s.Assert().ElementsMatch([]any{"Rocky Road Deluxe", "Marshmallow Twist"}, s.ProductNames())
+}
+
+func (s *ProductImportSuite) TestImport_ReportsInvalidRows() {
+	rec, resp := s.Import("application/x-ndjson", "", strings.Join([]string{
+		`{"name":"Mint","category":"ice_cream","price":{"amount":300,"currency":"EUR"},"sku":"IC-MI"}`,
+		``,
+		`{"name":"Fudge","category":"fudge","price":{"amount":300,"currency":"EUR"}}`,
+		`{"name":"Sorbet","category":"ice_cream","price":{"amount":300,"currency":"USD"}}`,
+		`{"category":"ice_cream","price":{"amount":300,"currency":"EUR"}}`,
+		`{"name":"Mint Chip","category":"ice_cream","price":{"amount":300,"currency":"EUR"},"sku":"IC-MI"}`,
+		`{"name":`,
+	}, "\n"))
+
+	s.Require().Equal(http.StatusOK, rec.Code, rec.Body.String())
+	s.Assert().Equal(float64(6), resp["rows"])
+	s.Assert().Equal(float64(1), resp["created"])
+	errs := resp["errors"].([]any)
+	s.Require().Len(errs, 5)
+	var lines []any
+	for _, e := range errs {
+		lines = append(lines, e.(map[string]any)["line"])
+	}
+	s.Assert().Equal([]any{float64(3), float64(4), float64(5), float64(6), float64(7)}, lines)
+	s.Assert().Equal(`category "fudge" does not exist`, errs[0].(map[string]any)["message"])
+	s.Assert().Equal("/name", errs[2].(map[string]any)["errors"].([]any)[0].(map[string]any)["pointer"])
+	s.Assert().Equal([]any{"Mint"}, s.ProductNames())
+}
+
+func (s *ProductImportSuite) TestImport_DryRun() {
+	rec, resp := s.Import("application/x-ndjson", "?dry_run=true",
+		`{"name":"Mint","category":"ice_cream","price":{"amount":300,"currency":"EUR"}}`)
+
+	s.Require().Equal(http.StatusOK, rec.Code, rec.Body.String())
+	s.Assert().Equal(true, resp["dry_run"])
+	s.Assert().Equal(float64(1), resp["created"])
+	s.Assert().Empty(s.ProductNames())
+}
+
+func (s *ProductImportSuite) TestImport_RequestErrors() {
+	cases := []struct {
+		name        string
+		contentType string
+		query       string
+		body        string
+	}{
+		{"unsupported content type", "application/json", "", `[]`},
+		{"unknown column", "text/csv", "", "name,category,price,colour\n"},
+		{"missing column", "text/csv", "", "name,price\n"},
+		{"no header", "text/csv", "", ""},
+		{"invalid dry_run", "text/csv", "?dry_run=maybe", "name,category,price\n"},
+	}
+	for _, tc := range cases {
+		s.Run(tc.name, func() {
+			rec, _ := s.Import(tc.contentType, tc.query, tc.body)
+			s.Assert().Equal(http.StatusBadRequest, rec.Code, rec.Body.String())
+		})
+	}
+}
+
+func (s *ProductImportSuite) TestExport_ReimportsAsUpdates() {
+	s.CreateProduct("Vanilla", "ice_cream", 350)
+	s.CreateProduct("Toasted Marshmallow", "marshmallow", 250)
+
+	req := httptest.NewRequest(http.MethodGet, "/products/export", nil)
+	req.Header.Set("Accept", "text/csv")
+	rec := s.Do(req)
+	s.Require().Equal(http.StatusOK, rec.Code)
+	export := rec.Body.String()
+	rows, err := csv.NewReader(strings.NewReader(export)).ReadAll()
+	s.Require().NoError(err)
+	s.Require().Len(rows, 3)
+	s.Assert().Equal([]string{"name", "category", "price", "sku", "description", "active", "sort_order"}, rows[0])
+
+	rec, resp := s.Import("text/csv", "", export)
+	s.Require().Equal(http.StatusOK, rec.Code, rec.Body.String())
+	s.Assert().Equal(float64(0), resp["created"])
+	s.Assert().Equal(float64(2), resp["updated"])
+	s.Assert().Empty(resp["errors"])
+}
+
+func TestProductImportSuite(t *testing.T) {
+	suite.Run(t, new(ProductImportSuite))
+}
@@ -0,0 +1,234 @@
+//go:build testing
+
+package handler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	coretesting "github.com/bbsbb/go-edge/core/testing"
+)
+
+type ProductOptionSuite struct {
+	IntegrationSuite
+}
+
+// sundaeOptions are a size variant and up to two toppings.
+func sundaeOptions() map[string]any {
+	return map[string]any{"groups": []any{
+		map[string]any{"name": "Size", "kind": "variant", "options": []any{
+			map[string]any{"name": "Small", "price_delta": eur(-50)},
+			map[string]any{"name": "Medium", "price_delta": eur(0), "sort_order": 1},
+			map[string]any{"name": "Large", "price_delta": eur(100), "sort_order": 2},
+		}},
+		map[string]any{"name": "Toppings", "kind": "modifier", "max_selections": 2, "sort_order": 1, "options": []any{
+			map[string]any{"name": "Sprinkles", "price_delta": eur(30)},
+			map[string]any{"name": "Fudge", "price_delta": eur(60), "sort_order": 1},
+		}},
+	}}
+}
+
+func (s *ProductOptionSuite) ReplaceOptions(productID string, body map[string]any) *httptest.ResponseRecorder {
+	return s.Do(coretesting.JSONRequest(s.T(), http.MethodPut, "/products/"+productID+"/options", body))
+}
+
+// Sundae creates a product priced at 3.50 EUR with sundaeOptions and returns
+// its ID and the IDs of its options by name.
+func (s *ProductOptionSuite) Sundae(name string) (string, map[string]string) {
+	product := s.CreateProduct(name, "ice_cream", 350)["id"].(string)
+	rec := s.ReplaceOptions(product, sundaeOptions())
+	s.Require().Equal(http.StatusOK, rec.Code, rec.Body.String())
+
+	var resp map[string]any
+	coretesting.DecodeJSON(s.T(), rec, &resp)
+	ids := map[string]string{}
+	for _, g := range resp["groups"].([]any) {
+		for _, o := range g.(map[string]any)["options"].([]any) {
+			o := o.(map[string]any)
+			ids[o["name"].(string)] = o["id"].(string)
+		}
+	}
+	return product, ids
+}
+
+func (s *ProductOptionSuite) AddItem(orderID, productID string, optionIDs ...string) *httptest.ResponseRecorder {
+	return s.Do(coretesting.JSONRequest(s.T(), http.MethodPost, "/orders/"+orderID+"/items", map[string]any{
+		"product_id": productID, "quantity": 1, "option_ids": optionIDs,
+	}))
+}
+
+func (s *ProductOptionSuite) TestReplaceAndListOptions() {
+	product, ids := s.Sundae("Sundae")
+
+	rec := s.Do(httptest.NewRequest(http.MethodGet, "/products/"+product+"/options", nil))
+	s.Require().Equal(http.StatusOK, rec.Code)
+	var resp map[string]any
+	coretesting.DecodeJSON(s.T(), rec, &resp)
+	groups := resp["groups"].([]any)
+	s.Require().Len(groups, 2)
+	size, toppings := groups[0].(map[string]any), groups[1].(map[string]any)
+	s.Assert().Equal("Size", size["name"])
+	s.Assert().Equal("variant", size["kind"])
+	s.Assert().Equal(float64(1), size["min_selections"], "a variant takes exactly one option")
+	s.Assert().Equal(float64(1), size["max_selections"])
+	s.Assert().Equal(float64(0), toppings["min_selections"])
+	s.Assert().Equal(float64(2), toppings["max_selections"])
+	small := size["options"].([]any)[0].(map[string]any)
+	s.Assert().Equal("Small", small["name"])
+	s.Assert().Equal(eur(-50), small["price_delta"])
+
+	rec = s.ReplaceOptions(product, map[string]any{"groups": []any{
+		map[string]any{"id": size["id"], "name": "Size", "kind": "variant", "options": []any{
+			map[string]any{"id": ids["Small"], "name": "Kids", "price_delta": eur(-100)},
+			map[string]any{"name": "Regular", "price_delta": eur(0)},
+		}},
+	}})
+	s.Require().Equal(http.StatusOK, rec.Code, rec.Body.String())
+	coretesting.DecodeJSON(s.T(), rec, &resp)
+	groups = resp["groups"].([]any)
+	s.Require().Len(groups, 1, "the toppings are removed")
+	s.Assert().Equal(size["id"], groups[0].(map[string]any)["id"])
+	kids := groups[0].(map[string]any)["options"].([]any)[0].(map[string]any)
+	s.Assert().Equal(ids["Small"], kids["id"])
+	s.Assert().Equal("Kids", kids["name"])
+}
+
+func (s *ProductOptionSuite) TestReplaceOptions_Errors() {
+	product := s.CreateProduct("Sundae", "ice_cream", 350)["id"].(string)
+	group := func(fields map[string]any) map[string]any {
+		g := map[string]any{"name": "Toppings", "kind": "modifier", "max_selections": 1, "options": []any{
+			map[string]any{"name": "Sprinkles", "price_delta": eur(30)},
+		}}
+		for k, v := range fields {
+			g[k] = v
+		}
+		return map[string]any{"groups": []any{g}}
+	}
+	cases := []struct {
+		name string
+		body map[string]any
+	}{
+		{"missing name", group(map[string]any{"name": ""})},
+		{"unknown kind", group(map[string]any{"kind": "extra"})},
+		{"no options", group(map[string]any{"options": []any{}})},
+		{"max above options", group(map[string]any{"max_selections": 2})},
+		{"variant of two", group(map[string]any{"kind": "variant", "min_selections": 2, "max_selections": 2})},
+		{"other currency", group(map[string]any{"options": []any{
+			map[string]any{"name": "Sprinkles", "price_delta": map[string]any{"amount": 30, "currency": "USD"}},
+		}})},
+		{"unknown id", group(map[string]any{"id": "019505e0-0000-7000-8000-000000000000"})},
+		{"malformed id", group(map[string]any{"id": "sprinkles"})},
+		{"group named twice", map[string]any{"groups": []any{group(nil)["groups"].([]any)[0], group(nil)["groups"].([]any)[0]}}},
+	}
+	for _, tc := range cases {
+		s.Run(tc.name, func() {
+			rec := s.ReplaceOptions(product, tc.body)
+			s.Assert().Equal(http.StatusBadRequest, rec.Code, rec.Body.String())
+		})
+	}
+
+	rec := s.ReplaceOptions("019505e0-0000-7000-8000-000000000000", group(nil))
+	s.Assert().Equal(http.StatusNotFound, rec.Code)
+}
+
+func (s *ProductOptionSuite) TestAddItem_PricesChosenOptions() {
+	product, ids := s.Sundae("Sundae")
+	order := s.OpenOrder()["id"].(string)
+
+	rec := s.AddItem(order, product, ids["Fudge"], ids["Large"], ids["Sprinkles"])
+	s.Require().Equal(http.StatusCreated, rec.Code, rec.Body.String())
+	var item map[string]any
+	coretesting.DecodeJSON(s.T(), rec, &item)
+	s.Assert().Equal(eur(540), item["unit_price"])
+	s.Assert().Equal([]any{
+		map[string]any{"option_id": ids["Large"], "group": "Size", "name": "Large", "price_delta": eur(100)},
+		map[string]any{"option_id": ids["Sprinkles"], "group": "Toppings", "name": "Sprinkles", "price_delta": eur(30)},
+		map[string]any{"option_id": ids["Fudge"], "group": "Toppings", "name": "Fudge", "price_delta": eur(60)},
+	}, item["options"])
+
+	rec = s.AddItem(order, product, ids["Large"], ids["Sprinkles"], ids["Fudge"])
+	s.Require().Equal(http.StatusCreated, rec.Code, rec.Body.String())
+	var merged map[string]any
+	coretesting.DecodeJSON(s.T(), rec, &merged)
+	s.Assert().Equal(item["id"], merged["id"], "the same options merge into one line")
+	s.Assert().Equal(float64(2), merged["quantity"])
+
+	rec = s.AddItem(order, product, ids["Small"])
+	s.Require().Equal(http.StatusCreated, rec.Code, rec.Body.String())
+
+	rec = s.Do(httptest.NewRequest(http.MethodGet, "/orders/"+order, nil))
+	s.Require().Equal(http.StatusOK, rec.Code)
+	var got map[string]any
+	coretesting.DecodeJSON(s.T(), rec, &got)
+	s.Require().Len(got["items"], 2)
+	s.Assert().Equal(eur(1380), got["total"])
+
+	rec = s.Do(httptest.NewRequest(http.MethodGet, "/orders", nil))
+	s.Require().Equal(http.StatusOK, rec.Code)
+	var list map[string]any
+	coretesting.DecodeJSON(s.T(), rec, &list)
+	listed := list["orders"].([]any)[0].(map[string]any)["items"].([]any)
+	s.Assert().Equal(item["options"], listed[0].(map[string]any)["options"])
+}
+
+func (s *ProductOptionSuite) TestAddItem_InvalidSelection() {
+	product, ids := s.Sundae("Sundae")
+	other, otherIDs := s.Sundae("Banana split")
+	order := s.OpenOrder()["id"].(string)
+
+	cases := []struct {
+		name    string
+		options []string
+	}{
+		{"no size", []string{ids["Sprinkles"]}},
+		{"two sizes", []string{ids["Small"], ids["Large"]}},
+		{"chosen twice", []string{ids["Small"], ids["Fudge"], ids["Fudge"]}},
+		{"option of another product", []string{otherIDs["Small"]}},
+		{"malformed id", []string{"large"}},
+	}
+	for _, tc := range cases {
+		s.Run(tc.name, func() {
+			rec := s.AddItem(order, product, tc.options...)
+			s.Assert().Equal(http.StatusBadRequest, rec.Code, rec.Body.String())
+		})
+	}
+	s.Assert().Equal(http.StatusBadRequest, s.AddItem(order, other).Code, "a product with a variant needs one")
+}
+
+func (s *ProductOptionSuite) TestAddItem_PriceMustStayPositive() {
+	product := s.CreateProduct("Cone", "ice_cream", 50)["id"].(string)
+	rec := s.ReplaceOptions(product, sundaeOptions())
+	s.Require().Equal(http.StatusOK, rec.Code, rec.Body.String())
+	var resp map[string]any
+	coretesting.DecodeJSON(s.T(), rec, &resp)
+	small := resp["groups"].([]any)[0].(map[string]any)["options"].([]any)[0].(map[string]any)["id"].(string)
+
+	rec = s.AddItem(s.OpenOrder()["id"].(string), product, small)
+	s.Assert().Equal(http.StatusUnprocessableEntity, rec.Code, rec.Body.String())
+}
+
+func (s *ProductOptionSuite) TestOrderKeepsOptionsAfterReplace() {
+	product, ids := s.Sundae("Sundae")
+	order := s.OpenOrder()["id"].(string)
+	s.Require().Equal(http.StatusCreated, s.AddItem(order, product, ids["Large"]).Code)
+
+	rec := s.ReplaceOptions(product, map[string]any{"groups": []any{}})
+	s.Require().Equal(http.StatusOK, rec.Code, rec.Body.String())
+
+	rec = s.Do(httptest.NewRequest(http.MethodGet, "/orders/"+order, nil))
+	s.Require().Equal(http.StatusOK, rec.Code)
+	var got map[string]any
+	coretesting.DecodeJSON(s.T(), rec, &got)
+	line := got["items"].([]any)[0].(map[string]any)
+	s.Assert().Equal(eur(450), line["unit_price"])
+	s.Assert().Equal("Large", line["options"].([]any)[0].(map[string]any)["name"])
+
+	s.Assert().Equal(http.StatusCreated, s.AddItem(order, product).Code, "a product without groups takes no options")
+}
+
+func TestProductOptionSuite(t *testing.T) {
+	suite.Run(t, new(ProductOptionSuite))
+}
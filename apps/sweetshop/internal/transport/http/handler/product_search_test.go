@@ -0,0 +1,146 @@
+//go:build testing
+
+package handler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bbsbb/go-edge/core/datatype"
+	coredomain "github.com/bbsbb/go-edge/core/domain"
+	coretesting "github.com/bbsbb/go-edge/core/testing"
+	"github.com/bbsbb/go-edge/sweetshop/internal/domain"
+)
+
+type ProductSearchSuite struct {
+	IntegrationSuite
+}
+
+func (s *ProductSearchSuite) Create(body map[string]any) string {
+	body["price"] = eur(350)
+	rec := s.Do(coretesting.JSONRequest(s.T(), http.MethodPost, "/products", body))
+	s.Require().Equal(http.StatusCreated, rec.Code, rec.Body.String())
+	var resp map[string]any
+	coretesting.DecodeJSON(s.T(), rec, &resp)
+	return resp["id"].(string)
+}
+
+func (s *ProductSearchSuite) Search(q string) []map[string]any {
+	rec := s.Do(httptest.NewRequest(http.MethodGet, "/products/search?q="+url.QueryEscape(q), nil))
+	s.Require().Equal(http.StatusOK, rec.Code, rec.Body.String())
+	var resp []map[string]any
+	coretesting.DecodeJSON(s.T(), rec, &resp)
+	return resp
+}
+
+func names(results []map[string]any) []string {
+	out := make([]string, len(results))
+	for i, r := range results {
+		out[i] = r["name"].(string)
+	}
+	return out
+}
+
+func (s *ProductSearchSuite) TestSearch_MatchesWordPrefixes() {
+	s.Create(map[string]any{"name": "Rocky Road", "category": "ice_cream", "description": "Chocolate with marshmallows"})
+	s.Create(map[string]any{"name": "Toasted Marshmallow", "category": "marshmallow"})
+	s.Create(map[string]any{"name": "Vanilla Scoop", "category": "ice_cream"})
+
+	results := s.Search("marsh")
+	s.Require().Equal([]string{"Toasted Marshmallow", "Rocky Road"}, names(results), "a name match ranks above a description match")
+	s.Assert().Equal("Toasted <mark>Marshmallow</mark>", results[0]["name_highlight"])
+	s.Assert().Contains(results[1]["description_highlight"], "<mark>marshmallows</mark>")
+	s.Assert().Greater(results[0]["rank"], results[1]["rank"])
+	s.Assert().Equal(eur(350), results[0]["price"])
+
+	s.Assert().Equal([]string{"Rocky Road"}, names(s.Search("ROCK choc")), "every word must match")
+	s.Assert().Empty(s.Search("pistachio"))
+}
+
+func (s *ProductSearchSuite) TestSearch_EscapesHighlights() {
+	s.Create(map[string]any{
+		"name": "Fudge <script>alert(1)</script>", "category": "marshmallow",
+		"description": `Fudge & "toffee" <img src=x onerror=alert(1)>`,
+	})
+
+	results := s.Search("fudge")
+	s.Require().Len(results, 1)
+	s.Assert().Equal("<mark>Fudge</mark> &lt;script&gt;alert(1)&lt;/script&gt;", results[0]["name_highlight"])
+	s.Assert().Equal("<mark>Fudge</mark> &amp; &quot;toffee&quot; &lt;img src=x onerror=alert(1)&gt;", results[0]["description_highlight"])
+}
+
+func (s *ProductSearchSuite) TestSearch_MatchesSKU() {
+	s.Create(map[string]any{"name": "Rocky Road", "category": "ice_cream", "sku": "IC-RR-01"})
+
+	s.Assert().Equal([]string{"Rocky Road"}, names(s.Search("ic-rr")))
+}
+
+func (s *ProductSearchSuite) TestSearch_ToleratesTypos() {
+	s.Create(map[string]any{"name": "Toasted Marshmallow", "category": "marshmallow"})
+
+	results := s.Search("marshmalow")
+	s.Require().Equal([]string{"Toasted Marshmallow"}, names(results))
+	s.Assert().Equal(float64(0), results[0]["rank"], "only the trigram similarity matched")
+	s.Assert().Greater(results[0]["similarity"], 0.6)
+	s.Assert().Equal("Toasted Marshmallow", results[0]["name_highlight"])
+}
+
+func (s *ProductSearchSuite) TestSearch_LiveProductsOfTheOrganizationOnly() {
+	deleted := s.Create(map[string]any{"name": "Marshmallow Fluff", "category": "marshmallow"})
+	s.Require().Equal(http.StatusNoContent, s.Do(httptest.NewRequest(http.MethodDelete, "/products/"+deleted, nil)).Code)
+	s.Create(map[string]any{"name": "Inactive Marshmallow", "category": "marshmallow", "active": false})
+
+	other := &coredomain.Organization{ID: uuid.Must(uuid.NewV7()), Slug: "other-shop"}
+	s.Require().NoError(s.orgRepo.Create(s.Context(), other))
+	ctx := coredomain.ContextWithOrganization(s.Context(), other)
+	_, err := s.Services.Categories.Create(ctx, "marshmallow", "Marshmallow", 0)
+	s.Require().NoError(err)
+	_, err = s.Services.Products.Create(ctx, &domain.Product{
+		Name: "Marshmallow Twist", Category: "marshmallow", Price: datatype.MustMoney(350, "EUR"), Active: true,
+	})
+	s.Require().NoError(err)
+
+	s.Assert().Equal([]string{"Inactive Marshmallow"}, names(s.Search("marshmallow")))
+}
+
+func (s *ProductSearchSuite) TestSearch_Limit() {
+	for _, name := range []string{"Mint", "Mint Chip", "Mint Choc"} {
+		s.Create(map[string]any{"name": name, "category": "ice_cream"})
+	}
+
+	rec := s.Do(httptest.NewRequest(http.MethodGet, "/products/search?q=mint&limit=2", nil))
+	s.Require().Equal(http.StatusOK, rec.Code)
+	var resp []map[string]any
+	coretesting.DecodeJSON(s.T(), rec, &resp)
+	s.Assert().Len(resp, 2)
+}
+
+func (s *ProductSearchSuite) TestSearch_ValidationErrors() {
+	cases := []struct {
+		name  string
+		query string
+	}{
+		{"missing q", ""},
+		{"no words", "q=" + url.QueryEscape("&*!")},
+		{"q too long", "q=" + strings.Repeat("a", 201)},
+		{"zero limit", "q=mint&limit=0"},
+		{"limit too high", "q=mint&limit=51"},
+		{"malformed limit", "q=mint&limit=ten"},
+	}
+	for _, tc := range cases {
+		s.Run(tc.name, func() {
+			rec := s.Do(httptest.NewRequest(http.MethodGet, "/products/search?"+tc.query, nil))
+			s.Assert().Equal(http.StatusBadRequest, rec.Code, rec.Body.String())
+		})
+	}
+}
+
+func TestProductSearchSuite(t *testing.T) {
+	suite.Run(t, new(ProductSearchSuite))
+}
@@ -3,6 +3,7 @@
 package handler_test
 
 import (
+	"encoding/csv"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -11,6 +12,7 @@ import (
 	"github.com/stretchr/testify/suite"
 
 	coretesting "github.com/bbsbb/go-edge/core/testing"
+	transporthttp "github.com/bbsbb/go-edge/core/transport/http"
 )
 
 type ProductSuite struct {
@@ -23,7 +25,48 @@ func (s *ProductSuite) TestCreateProduct() {
 	s.Assert().NotEmpty(resp["id"])
 	s.Assert().Equal("Vanilla Scoop", resp["name"])
 	s.Assert().Equal("ice_cream", resp["category"])
-	s.Assert().Equal(float64(350), resp["price_cents"])
+	s.Assert().Equal(eur(350), resp["price"])
+	s.Assert().Nil(resp["sku"])
+	s.Assert().Equal("", resp["description"])
+	s.Assert().Equal(true, resp["active"])
+	s.Assert().Equal(float64(0), resp["sort_order"])
+}
+
+func (s *ProductSuite) TestCreateProduct_CatalogFields() {
+	rec := s.Do(coretesting.JSONRequest(s.T(), http.MethodPost, "/products", map[string]any{
+		"name": "Rocky Road", "category": "ice_cream", "price": eur(450),
+		"sku": "IC-RR-01", "description": "Chocolate with marshmallows", "active": false, "sort_order": 3,
+	}))
+	s.Require().Equal(http.StatusCreated, rec.Code, rec.Body.String())
+
+	var resp map[string]any
+	coretesting.DecodeJSON(s.T(), rec, &resp)
+	s.Assert().Equal("IC-RR-01", resp["sku"])
+	s.Assert().Equal("Chocolate with marshmallows", resp["description"])
+	s.Assert().Equal(false, resp["active"])
+	s.Assert().Equal(float64(3), resp["sort_order"])
+}
+
+func (s *ProductSuite) TestCreateProduct_DuplicateSKU() {
+	body := map[string]any{"name": "First", "category": "ice_cream", "price": eur(100), "sku": "DUP"}
+	s.Require().Equal(http.StatusCreated, s.Do(coretesting.JSONRequest(s.T(), http.MethodPost, "/products", body)).Code)
+
+	body["name"] = "Second"
+	rec := s.Do(coretesting.JSONRequest(s.T(), http.MethodPost, "/products", body))
+	s.Assert().Equal(http.StatusConflict, rec.Code)
+}
+
+func (s *ProductSuite) TestCreateProduct_SKUOfDeletedProductCanBeReused() {
+	body := map[string]any{"name": "First", "category": "ice_cream", "price": eur(100), "sku": "REUSED"}
+	rec := s.Do(coretesting.JSONRequest(s.T(), http.MethodPost, "/products", body))
+	s.Require().Equal(http.StatusCreated, rec.Code)
+	var first map[string]any
+	coretesting.DecodeJSON(s.T(), rec, &first)
+	s.Require().Equal(http.StatusNoContent, s.Do(httptest.NewRequest(http.MethodDelete, "/products/"+first["id"].(string), nil)).Code)
+
+	body["name"] = "Second"
+	rec = s.Do(coretesting.JSONRequest(s.T(), http.MethodPost, "/products", body))
+	s.Assert().Equal(http.StatusCreated, rec.Code)
 }
 
 func (s *ProductSuite) TestCreateProduct_ValidationErrors() {
@@ -32,9 +75,15 @@ func (s *ProductSuite) TestCreateProduct_ValidationErrors() {
 		body     map[string]any
 		wantCode int
 	}{
-		{"invalid category", map[string]any{"name": "Bad", "category": "candy", "price_cents": 100}, http.StatusBadRequest},
-		{"zero price", map[string]any{"name": "Free", "category": "ice_cream", "price_cents": 0}, http.StatusBadRequest},
-		{"negative price", map[string]any{"name": "Neg", "category": "ice_cream", "price_cents": -1}, http.StatusBadRequest},
+		{"unknown category", map[string]any{"name": "Bad", "category": "candy", "price": eur(100)}, http.StatusBadRequest},
+		{"empty sku", map[string]any{"name": "Bad", "category": "ice_cream", "price": eur(100), "sku": ""}, http.StatusBadRequest},
+		{"zero price", map[string]any{"name": "Free", "category": "ice_cream", "price": eur(0)}, http.StatusBadRequest},
+		{"negative price", map[string]any{"name": "Neg", "category": "ice_cream", "price": eur(-1)}, http.StatusBadRequest},
+		{"missing name", map[string]any{"category": "ice_cream", "price": eur(100)}, http.StatusBadRequest},
+		{"missing price", map[string]any{"name": "Free", "category": "ice_cream"}, http.StatusBadRequest},
+		{"unknown currency", map[string]any{"name": "Odd", "category": "ice_cream", "price": map[string]any{"amount": 100, "currency": "XYZ"}}, http.StatusBadRequest},
+		{"other currency", map[string]any{"name": "Dollar", "category": "ice_cream", "price": map[string]any{"amount": 100, "currency": "USD"}}, http.StatusBadRequest},
+		{"unknown field", map[string]any{"name": "Extra", "category": "ice_cream", "price": eur(100), "colour": "red"}, http.StatusBadRequest},
 	}
 	for _, tc := range cases {
 		s.Run(tc.name, func() {
@@ -45,6 +94,24 @@ func (s *ProductSuite) TestCreateProduct_ValidationErrors() {
 	}
 }
 
+func (s *ProductSuite) TestCreateProduct_FieldErrors() {
+	body := map[string]any{"category": "", "price": eur(0)}
+	req := coretesting.JSONRequest(s.T(), http.MethodPost, "/products", body)
+	rec := s.Do(req)
+
+	s.Require().Equal(http.StatusBadRequest, rec.Code)
+	s.Assert().Equal("application/problem+json", rec.Header().Get("Content-Type"))
+
+	var resp transporthttp.ErrorShape
+	s.Require().NoError(json.NewDecoder(rec.Body).Decode(&resp))
+	s.Assert().Equal("VALIDATION", resp.Code)
+	s.Assert().ElementsMatch([]transporthttp.FieldError{
+		{Pointer: "/name", Rule: "required", Message: "is required"},
+		{Pointer: "/category", Rule: "required", Message: "is required"},
+		{Pointer: "/price", Rule: "gt", Message: "must be greater than 0"},
+	}, resp.Errors)
+}
+
 func (s *ProductSuite) TestGetProduct() {
 	created := s.CreateProduct("Chocolate Scoop", "ice_cream", 400)
 
@@ -85,6 +152,54 @@ func (s *ProductSuite) TestListProducts() {
 	s.Assert().Len(resp, 2)
 }
 
+func (s *ProductSuite) TestListProducts_SortOrder() {
+	for _, p := range []map[string]any{
+		{"name": "Banana", "sort_order": 1},
+		{"name": "Cherry", "sort_order": 0},
+		{"name": "Apple", "sort_order": 1},
+	} {
+		p["category"] = "ice_cream"
+		p["price"] = eur(100)
+		s.Require().Equal(http.StatusCreated, s.Do(coretesting.JSONRequest(s.T(), http.MethodPost, "/products", p)).Code)
+	}
+
+	rec := s.Do(httptest.NewRequest(http.MethodGet, "/products", nil))
+	s.Require().Equal(http.StatusOK, rec.Code)
+	var resp []map[string]any
+	coretesting.DecodeJSON(s.T(), rec, &resp)
+	var names []any
+	for _, p := range resp {
+		names = append(names, p["name"])
+	}
+	s.Assert().Equal([]any{"Cherry", "Apple", "Banana"}, names)
+}
+
+func (s *ProductSuite) TestListProducts_CSV() {
+	s.CreateProduct("Vanilla", "ice_cream", 350)
+
+	req := httptest.NewRequest(http.MethodGet, "/products", nil)
+	req.Header.Set("Accept", "text/csv")
+	rec := s.Do(req)
+
+	s.Assert().Equal(http.StatusOK, rec.Code)
+	s.Assert().Equal("text/csv", rec.Header().Get("Content-Type"))
+	rows, err := csv.NewReader(rec.Body).ReadAll()
+	s.Require().NoError(err)
+	s.Require().Len(rows, 2)
+	s.Assert().Equal([]string{"id", "name", "category", "price", "sku", "description", "active", "sort_order"}, rows[0])
+	s.Assert().Equal("Vanilla", rows[1][1])
+	s.Assert().JSONEq(`{"amount":350,"currency":"EUR"}`, rows[1][3])
+}
+
+func (s *ProductSuite) TestListProducts_NotAcceptable() {
+	req := httptest.NewRequest(http.MethodGet, "/products", nil)
+	req.Header.Set("Accept", "application/xml")
+	rec := s.Do(req)
+
+	s.Assert().Equal(http.StatusNotAcceptable, rec.Code)
+	s.Assert().Equal("application/problem+json", rec.Header().Get("Content-Type"))
+}
+
 func (s *ProductSuite) TestListProducts_Empty() {
 	req := httptest.NewRequest(http.MethodGet, "/products", nil)
 	rec := s.Do(req)
@@ -99,7 +214,7 @@ func (s *ProductSuite) TestUpdateProduct() {
 	created := s.CreateProduct("Old Name", "ice_cream", 300)
 
 	req := coretesting.JSONRequest(s.T(), http.MethodPut, "/products/"+created["id"].(string), map[string]any{
-		"name": "New Name", "category": "marshmallow", "price_cents": 500,
+		"name": "New Name", "category": "marshmallow", "price": eur(500),
 	})
 	rec := s.Do(req)
 
@@ -108,12 +223,33 @@ func (s *ProductSuite) TestUpdateProduct() {
 	s.Require().NoError(json.NewDecoder(rec.Body).Decode(&resp))
 	s.Assert().Equal("New Name", resp["name"])
 	s.Assert().Equal("marshmallow", resp["category"])
-	s.Assert().Equal(float64(500), resp["price_cents"])
+	s.Assert().Equal(eur(500), resp["price"])
+}
+
+func (s *ProductSuite) TestUpdateProduct_Archive() {
+	product := s.CreateProduct("Seasonal", "ice_cream", 300)
+	order := s.OpenOrder()["id"].(string)
+
+	rec := s.Do(coretesting.JSONRequest(s.T(), http.MethodPut, "/products/"+product["id"].(string), map[string]any{
+		"name": "Seasonal", "category": "ice_cream", "price": eur(300), "active": false,
+	}))
+	s.Require().Equal(http.StatusOK, rec.Code, rec.Body.String())
+
+	rec = s.Do(httptest.NewRequest(http.MethodGet, "/products/"+product["id"].(string), nil))
+	s.Require().Equal(http.StatusOK, rec.Code)
+	var resp map[string]any
+	coretesting.DecodeJSON(s.T(), rec, &resp)
+	s.Assert().Equal(false, resp["active"])
+
+	rec = s.Do(coretesting.JSONRequest(s.T(), http.MethodPost, "/orders/"+order+"/items", map[string]any{
+		"product_id": product["id"], "quantity": 1,
+	}))
+	s.Assert().Equal(http.StatusUnprocessableEntity, rec.Code, rec.Body.String())
 }
 
 func (s *ProductSuite) TestUpdateProduct_NotFound() {
 	req := coretesting.JSONRequest(s.T(), http.MethodPut, "/products/019505e0-0000-7000-8000-000000000000", map[string]any{
-		"name": "X", "category": "ice_cream", "price_cents": 100,
+		"name": "X", "category": "ice_cream", "price": eur(100),
 	})
 	rec := s.Do(req)
 
@@ -122,7 +258,7 @@ func (s *ProductSuite) TestUpdateProduct_NotFound() {
 
 func (s *ProductSuite) TestUpdateProduct_InvalidID() {
 	req := coretesting.JSONRequest(s.T(), http.MethodPut, "/products/not-a-uuid", map[string]any{
-		"name": "X", "category": "ice_cream", "price_cents": 100,
+		"name": "X", "category": "ice_cream", "price": eur(100),
 	})
 	rec := s.Do(req)
 
@@ -155,6 +291,96 @@ func (s *ProductSuite) TestDeleteProduct_InvalidID() {
 	s.Assert().Equal(http.StatusBadRequest, rec.Code)
 }
 
+func (s *ProductSuite) TestDeleteProduct_ExcludedFromList() {
+	kept := s.CreateProduct("Kept", "ice_cream", 100)
+	deleted := s.CreateProduct("Deleted", "ice_cream", 100)
+
+	rec := s.Do(httptest.NewRequest(http.MethodDelete, "/products/"+deleted["id"].(string), nil))
+	s.Require().Equal(http.StatusNoContent, rec.Code)
+
+	rec = s.Do(httptest.NewRequest(http.MethodGet, "/products", nil))
+	s.Assert().Equal(http.StatusOK, rec.Code)
+	var resp []map[string]any
+	s.Require().NoError(json.NewDecoder(rec.Body).Decode(&resp))
+	s.Require().Len(resp, 1)
+	s.Assert().Equal(kept["id"], resp[0]["id"])
+}
+
+func (s *ProductSuite) TestDeleteProduct_AlreadyDeleted() {
+	created := s.CreateProduct("Twice", "ice_cream", 100)
+
+	rec := s.Do(httptest.NewRequest(http.MethodDelete, "/products/"+created["id"].(string), nil))
+	s.Require().Equal(http.StatusNoContent, rec.Code)
+
+	rec = s.Do(httptest.NewRequest(http.MethodDelete, "/products/"+created["id"].(string), nil))
+	s.Assert().Equal(http.StatusNotFound, rec.Code)
+}
+
+func (s *ProductSuite) TestDeleteProduct_ReferencedByOrder() {
+	product := s.CreateProduct("Sold", "ice_cream", 250)
+	order := s.OpenOrder()
+
+	req := coretesting.JSONRequest(s.T(), http.MethodPost, "/orders/"+order["id"].(string)+"/items", map[string]any{
+		"product_id": product["id"], "quantity": 1,
+	})
+	s.Require().Equal(http.StatusCreated, s.Do(req).Code)
+
+	rec := s.Do(httptest.NewRequest(http.MethodDelete, "/products/"+product["id"].(string), nil))
+	s.Require().Equal(http.StatusNoContent, rec.Code)
+
+	rec = s.Do(httptest.NewRequest(http.MethodGet, "/orders/"+order["id"].(string), nil))
+	s.Assert().Equal(http.StatusOK, rec.Code)
+	var resp map[string]any
+	s.Require().NoError(json.NewDecoder(rec.Body).Decode(&resp))
+	items := resp["items"].([]any)
+	s.Require().Len(items, 1)
+	s.Assert().Equal("Sold", items[0].(map[string]any)["product_name"])
+}
+
+func (s *ProductSuite) TestDeleteProduct_NameCanBeReused() {
+	created := s.CreateProduct("Reused", "ice_cream", 100)
+
+	rec := s.Do(httptest.NewRequest(http.MethodDelete, "/products/"+created["id"].(string), nil))
+	s.Require().Equal(http.StatusNoContent, rec.Code)
+
+	recreated := s.CreateProduct("Reused", "ice_cream", 150)
+	s.Assert().NotEqual(created["id"], recreated["id"])
+}
+
+func (s *ProductSuite) TestRestoreProduct() {
+	created := s.CreateProduct("Comeback", "marshmallow", 200)
+
+	rec := s.Do(httptest.NewRequest(http.MethodDelete, "/products/"+created["id"].(string), nil))
+	s.Require().Equal(http.StatusNoContent, rec.Code)
+
+	rec = s.Do(httptest.NewRequest(http.MethodPost, "/products/"+created["id"].(string)+"/restore", nil))
+	s.Assert().Equal(http.StatusOK, rec.Code)
+	var resp map[string]any
+	s.Require().NoError(json.NewDecoder(rec.Body).Decode(&resp))
+	s.Assert().Equal(created["id"], resp["id"])
+
+	rec = s.Do(httptest.NewRequest(http.MethodGet, "/products/"+created["id"].(string), nil))
+	s.Assert().Equal(http.StatusOK, rec.Code)
+}
+
+func (s *ProductSuite) TestRestoreProduct_NotDeleted() {
+	created := s.CreateProduct("Alive", "ice_cream", 100)
+
+	rec := s.Do(httptest.NewRequest(http.MethodPost, "/products/"+created["id"].(string)+"/restore", nil))
+	s.Assert().Equal(http.StatusNotFound, rec.Code)
+}
+
+func (s *ProductSuite) TestRestoreProduct_NameTaken() {
+	created := s.CreateProduct("Taken", "ice_cream", 100)
+
+	rec := s.Do(httptest.NewRequest(http.MethodDelete, "/products/"+created["id"].(string), nil))
+	s.Require().Equal(http.StatusNoContent, rec.Code)
+	s.CreateProduct("Taken", "ice_cream", 120)
+
+	rec = s.Do(httptest.NewRequest(http.MethodPost, "/products/"+created["id"].(string)+"/restore", nil))
+	s.Assert().Equal(http.StatusConflict, rec.Code)
+}
+
 func TestProductSuite(t *testing.T) {
 	suite.Run(t, new(ProductSuite))
 }
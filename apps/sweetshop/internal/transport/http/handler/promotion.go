@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+
+	coredomain "github.com/bbsbb/go-edge/core/domain"
+	transporthttp "github.com/bbsbb/go-edge/core/transport/http"
+	"github.com/bbsbb/go-edge/sweetshop/internal/service"
+	"github.com/bbsbb/go-edge/sweetshop/internal/transport/http/dto"
+)
+
+type PromotionHandler struct {
+	services *service.Registry
+	logger   *slog.Logger
+}
+
+func NewPromotionHandler(services *service.Registry, logger *slog.Logger) *PromotionHandler {
+	return &PromotionHandler{services: services, logger: logger}
+}
+
+func (h *PromotionHandler) List(w http.ResponseWriter, r *http.Request) {
+	promotions, err := h.services.Promotions.List(r.Context())
+	if err != nil {
+		transporthttp.WriteError(w, r, err, h.logger)
+		return
+	}
+	transporthttp.RenderListOrLog(w, r, dto.PromotionListToResponse(promotions), h.logger)
+}
+
+func (h *PromotionHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, err := coredomain.ParseID(chi.URLParam(r, "id"))
+	if err != nil {
+		transporthttp.WriteError(w, r, err, h.logger)
+		return
+	}
+
+	promotion, err := h.services.Promotions.Get(r.Context(), id.UUID())
+	if err != nil {
+		transporthttp.WriteError(w, r, err, h.logger)
+		return
+	}
+	render.Status(r, http.StatusOK)
+	transporthttp.RenderOrLog(w, r, dto.PromotionToResponse(promotion), h.logger)
+}
+
+func (h *PromotionHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req dto.PromotionRequest
+	if err := transporthttp.Bind(r, &req); err != nil {
+		transporthttp.WriteError(w, r, err, h.logger)
+		return
+	}
+
+	promotion, err := h.services.Promotions.Create(r.Context(), req.Rule())
+	if err != nil {
+		transporthttp.WriteError(w, r, err, h.logger)
+		return
+	}
+	render.Status(r, http.StatusCreated)
+	transporthttp.RenderOrLog(w, r, dto.PromotionToResponse(promotion), h.logger)
+}
+
+func (h *PromotionHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := coredomain.ParseID(chi.URLParam(r, "id"))
+	if err != nil {
+		transporthttp.WriteError(w, r, err, h.logger)
+		return
+	}
+
+	var req dto.PromotionRequest
+	if err := transporthttp.Bind(r, &req); err != nil {
+		transporthttp.WriteError(w, r, err, h.logger)
+		return
+	}
+
+	promotion, err := h.services.Promotions.Update(r.Context(), id.UUID(), req.Rule())
+	if err != nil {
+		transporthttp.WriteError(w, r, err, h.logger)
+		return
+	}
+	render.Status(r, http.StatusOK)
+	transporthttp.RenderOrLog(w, r, dto.PromotionToResponse(promotion), h.logger)
+}
+
+func (h *PromotionHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := coredomain.ParseID(chi.URLParam(r, "id"))
+	if err != nil {
+		transporthttp.WriteError(w, r, err, h.logger)
+		return
+	}
+
+	if err := h.services.Promotions.Delete(r.Context(), id.UUID()); err != nil {
+		transporthttp.WriteError(w, r, err, h.logger)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
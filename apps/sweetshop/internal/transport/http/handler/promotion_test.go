@@ -0,0 +1,178 @@
+//go:build testing
+
+package handler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	coretesting "github.com/bbsbb/go-edge/core/testing"
+)
+
+type PromotionSuite struct {
+	IntegrationSuite
+}
+
+func (s *PromotionSuite) CreatePromotion(body map[string]any) map[string]any {
+	rec := s.Do(coretesting.JSONRequest(s.T(), http.MethodPost, "/promotions", body))
+	s.Require().Equal(http.StatusCreated, rec.Code, rec.Body.String())
+
+	var resp map[string]any
+	coretesting.DecodeJSON(s.T(), rec, &resp)
+	return resp
+}
+
+func (s *PromotionSuite) GetOrder(id string) map[string]any {
+	rec := s.Do(httptest.NewRequest(http.MethodGet, "/orders/"+id, nil))
+	s.Require().Equal(http.StatusOK, rec.Code)
+
+	var resp map[string]any
+	coretesting.DecodeJSON(s.T(), rec, &resp)
+	return resp
+}
+
+func (s *PromotionSuite) AddItem(orderID, productID string, quantity int) {
+	rec := s.Do(coretesting.JSONRequest(s.T(), http.MethodPost, "/orders/"+orderID+"/items", map[string]any{
+		"product_id": productID, "quantity": quantity,
+	}))
+	s.Require().Equal(http.StatusCreated, rec.Code, rec.Body.String())
+}
+
+func (s *PromotionSuite) TestCreatePromotion() {
+	resp := s.CreatePromotion(map[string]any{
+		"name": "Summer", "kind": "percentage", "percent_off": 10, "category": "ice_cream", "usage_limit": 5,
+	})
+
+	s.Assert().NotEmpty(resp["id"])
+	s.Assert().Equal("percentage", resp["kind"])
+	s.Assert().Equal(float64(10), resp["percent_off"])
+	s.Assert().Equal("ice_cream", resp["category"])
+	s.Assert().Equal(float64(5), resp["usage_limit"])
+	s.Assert().Equal(float64(0), resp["usage_count"])
+
+	rec := s.Do(httptest.NewRequest(http.MethodGet, "/promotions", nil))
+	s.Require().Equal(http.StatusOK, rec.Code)
+	var list []map[string]any
+	coretesting.DecodeJSON(s.T(), rec, &list)
+	s.Assert().Len(list, 1)
+}
+
+func (s *PromotionSuite) TestCreatePromotion_ValidationErrors() {
+	cases := []struct {
+		name string
+		body map[string]any
+	}{
+		{"unknown kind", map[string]any{"name": "X", "kind": "bogo"}},
+		{"percent out of range", map[string]any{"name": "X", "kind": "percentage", "percent_off": 150}},
+		{"missing percent", map[string]any{"name": "X", "kind": "percentage"}},
+		{"unknown category", map[string]any{"name": "X", "kind": "percentage", "percent_off": 10, "category": "fudge"}},
+		{"fields of another kind", map[string]any{"name": "X", "kind": "percentage", "percent_off": 10, "buy_quantity": 1}},
+		{"amount in other currency", map[string]any{"name": "X", "kind": "fixed_amount", "amount_off": map[string]any{"amount": 100, "currency": "USD"}}},
+		{"missing get quantity", map[string]any{"name": "X", "kind": "buy_x_get_y", "buy_quantity": 1}},
+		{"window reversed", map[string]any{"name": "X", "kind": "percentage", "percent_off": 10, "starts_at": "2026-02-01T00:00:00Z", "ends_at": "2026-01-01T00:00:00Z"}},
+	}
+	for _, tc := range cases {
+		s.Run(tc.name, func() {
+			rec := s.Do(coretesting.JSONRequest(s.T(), http.MethodPost, "/promotions", tc.body))
+			s.Assert().Equal(http.StatusBadRequest, rec.Code, rec.Body.String())
+		})
+	}
+}
+
+func (s *PromotionSuite) TestUpdateAndDeletePromotion() {
+	id := s.CreatePromotion(map[string]any{"name": "Ten", "kind": "percentage", "percent_off": 10})["id"].(string)
+
+	rec := s.Do(coretesting.JSONRequest(s.T(), http.MethodPut, "/promotions/"+id, map[string]any{
+		"name": "Euro off", "kind": "fixed_amount", "amount_off": eur(100),
+	}))
+	s.Require().Equal(http.StatusOK, rec.Code, rec.Body.String())
+	var resp map[string]any
+	coretesting.DecodeJSON(s.T(), rec, &resp)
+	s.Assert().Equal("fixed_amount", resp["kind"])
+	s.Assert().Equal(eur(100), resp["amount_off"])
+	s.Assert().Nil(resp["percent_off"])
+
+	s.Assert().Equal(http.StatusNoContent, s.Do(httptest.NewRequest(http.MethodDelete, "/promotions/"+id, nil)).Code)
+	s.Assert().Equal(http.StatusNotFound, s.Do(httptest.NewRequest(http.MethodGet, "/promotions/"+id, nil)).Code)
+}
+
+func (s *PromotionSuite) TestOrderShowsAdjustments() {
+	s.CreatePromotion(map[string]any{"name": "Two for one", "kind": "buy_x_get_y", "buy_quantity": 1, "get_quantity": 1, "category": "marshmallow"})
+	marshmallow := s.CreateProduct("Puff", "marshmallow", 200)["id"].(string)
+	vanilla := s.CreateProduct("Vanilla", "ice_cream", 350)["id"].(string)
+
+	order := s.OpenOrder()["id"].(string)
+	s.AddItem(order, marshmallow, 2)
+	s.AddItem(order, vanilla, 1)
+
+	resp := s.GetOrder(order)
+	s.Assert().Equal(eur(750), resp["subtotal"])
+	s.Assert().Equal(eur(550), resp["total"])
+
+	items := resp["items"].([]any)
+	puffs := items[0].(map[string]any)
+	s.Assert().Equal(eur(400), puffs["line_total"])
+	s.Require().Len(puffs["adjustments"], 1)
+	adjustment := puffs["adjustments"].([]any)[0].(map[string]any)
+	s.Assert().Equal("Two for one", adjustment["description"])
+	s.Assert().Equal(eur(-200), adjustment["amount"])
+	s.Assert().Empty(items[1].(map[string]any)["adjustments"])
+}
+
+func (s *PromotionSuite) TestSubmitRedeemsAndCancelReleases() {
+	promotion := s.CreatePromotion(map[string]any{"name": "Once", "kind": "percentage", "percent_off": 50, "usage_limit": 1})
+	id := promotion["id"].(string)
+
+	first := s.OpenOrderWithItems()
+	rec, resp := s.Transition(first, "submit")
+	s.Require().Equal(http.StatusOK, rec.Code, rec.Body.String())
+	s.Assert().Equal(eur(350), resp["total"])
+	s.Assert().Equal(eur(350), attempts(resp)[0]["amount"], "the discounted total is authorized")
+
+	rec = s.Do(httptest.NewRequest(http.MethodGet, "/promotions/"+id, nil))
+	var got map[string]any
+	coretesting.DecodeJSON(s.T(), rec, &got)
+	s.Assert().Equal(float64(1), got["usage_count"])
+
+	second := s.OpenOrderWithItems()
+	s.Assert().Equal(eur(700), s.GetOrder(second)["total"], "a used up promotion no longer applies")
+
+	rec, _ = s.Transition(first, "cancel")
+	s.Require().Equal(http.StatusOK, rec.Code)
+	s.Assert().Equal(eur(350), s.GetOrder(second)["total"], "cancelling gives the use back")
+}
+
+func (s *PromotionSuite) TestSubmittedOrderKeepsDiscount() {
+	id := s.CreatePromotion(map[string]any{"name": "Half", "kind": "percentage", "percent_off": 50})["id"].(string)
+	order := s.OpenOrderWithItems()
+	rec, _ := s.Transition(order, "submit")
+	s.Require().Equal(http.StatusOK, rec.Code)
+
+	s.Require().Equal(http.StatusNoContent, s.Do(httptest.NewRequest(http.MethodDelete, "/promotions/"+id, nil)).Code)
+
+	resp := s.GetOrder(order)
+	s.Assert().Equal(eur(350), resp["total"])
+	adjustment := resp["items"].([]any)[0].(map[string]any)["adjustments"].([]any)[0].(map[string]any)
+	s.Assert().Equal("Half", adjustment["description"])
+	s.Assert().Nil(adjustment["promotion_id"])
+}
+
+func (s *PromotionSuite) TestFreeOrderSkipsPayment() {
+	s.CreatePromotion(map[string]any{"name": "On the house", "kind": "percentage", "percent_off": 100})
+	order := s.OpenOrderWithItems()
+
+	for _, action := range []string{"submit", "pay", "fulfil"} {
+		rec, _ := s.Transition(order, action)
+		s.Require().Equal(http.StatusOK, rec.Code, action+": "+rec.Body.String())
+	}
+	resp := s.GetOrder(order)
+	s.Assert().Equal(eur(0), resp["total"])
+	s.Assert().Empty(resp["payment"].(map[string]any)["attempts"])
+}
+
+func TestPromotionSuite(t *testing.T) {
+	suite.Run(t, new(PromotionSuite))
+}
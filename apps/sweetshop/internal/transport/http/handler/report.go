@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	coredomain "github.com/bbsbb/go-edge/core/domain"
+	transporthttp "github.com/bbsbb/go-edge/core/transport/http"
+	"github.com/bbsbb/go-edge/sweetshop/internal/domain"
+	"github.com/bbsbb/go-edge/sweetshop/internal/service"
+	"github.com/bbsbb/go-edge/sweetshop/internal/transport/http/dto"
+)
+
+type ReportHandler struct {
+	services *service.Registry
+	logger   *slog.Logger
+}
+
+func NewReportHandler(services *service.Registry, logger *slog.Logger) *ReportHandler {
+	return &ReportHandler{services: services, logger: logger}
+}
+
+// Sales serves the organization's sales per period between the from and to
+// dates.
+func (h *ReportHandler) Sales(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	from, to, err := reportDays(query)
+	if err != nil {
+		transporthttp.WriteError(w, r, err, h.logger)
+		return
+	}
+
+	report, err := h.services.Reports.Sales(r.Context(), domain.SalesQuery{
+		From:        from,
+		To:          to,
+		Granularity: domain.SalesGranularity(query.Get("granularity")),
+	})
+	if err != nil {
+		transporthttp.WriteError(w, r, err, h.logger)
+		return
+	}
+	resp, err := dto.SalesReportToResponse(report)
+	if err != nil {
+		transporthttp.WriteError(w, r, err, h.logger)
+		return
+	}
+	transporthttp.RenderOrLog(w, r, resp, h.logger)
+}
+
+// TopProducts serves the products that sold the most between the from and to
+// dates.
+func (h *ReportHandler) TopProducts(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	from, to, err := reportDays(query)
+	if err != nil {
+		transporthttp.WriteError(w, r, err, h.logger)
+		return
+	}
+	limit := int32(service.DefaultTopProductsLimit)
+	if raw := query.Get("limit"); raw != "" {
+		n, err := strconv.ParseInt(raw, 10, 32)
+		if err != nil {
+			transporthttp.WriteError(w, r, coredomain.NewError(coredomain.CodeValidation, "limit must be an integer"), h.logger)
+			return
+		}
+		limit = int32(n)
+	}
+
+	report, err := h.services.Reports.TopProducts(r.Context(), domain.TopProductsQuery{
+		From:  from,
+		To:    to,
+		By:    domain.TopProductsMetric(query.Get("by")),
+		Limit: limit,
+	})
+	if err != nil {
+		transporthttp.WriteError(w, r, err, h.logger)
+		return
+	}
+	transporthttp.RenderOrLog(w, r, dto.TopProductsToResponse(report), h.logger)
+}
+
+// reportDays parses the from and to dates, leaving a missing one zero for the
+// service to default.
+func reportDays(query url.Values) (time.Time, time.Time, error) {
+	from, err := optionalDate(query, "from")
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	to, err := optionalDate(query, "to")
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	return from, to, nil
+}
+
+func optionalDate(query url.Values, name string) (time.Time, error) {
+	raw := query.Get(name)
+	if raw == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.DateOnly, raw)
+	if err != nil {
+		return time.Time{}, coredomain.NewError(coredomain.CodeValidation, fmt.Sprintf("%s must be a date (YYYY-MM-DD)", name))
+	}
+	return t, nil
+}
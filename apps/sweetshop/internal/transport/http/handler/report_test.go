@@ -0,0 +1,183 @@
+//go:build testing
+
+package handler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	coretesting "github.com/bbsbb/go-edge/core/testing"
+)
+
+type ReportSuite struct {
+	IntegrationSuite
+}
+
+// Refresh runs what the sales report job does for the test organization.
+func (s *ReportSuite) Refresh() {
+	s.Require().NoError(s.Services.Reports.Refresh(s.Context(), time.Now()))
+}
+
+func (s *ReportSuite) Get(path string, query url.Values) map[string]any {
+	rec := s.Do(httptest.NewRequest(http.MethodGet, path+"?"+query.Encode(), nil))
+	s.Require().Equal(http.StatusOK, rec.Code, rec.Body.String())
+
+	var resp map[string]any
+	coretesting.DecodeJSON(s.T(), rec, &resp)
+	return resp
+}
+
+// Fulfil takes an order through to fulfilled.
+func (s *ReportSuite) Fulfil(id string) {
+	for _, action := range []string{"submit", "pay", "fulfil"} {
+		rec, _ := s.Transition(id, action)
+		s.Require().Equal(http.StatusOK, rec.Code, rec.Body.String())
+	}
+}
+
+// OrderOf opens an order for quantity of product.
+func (s *ReportSuite) OrderOf(product map[string]any, quantity int) string {
+	id := s.OpenOrder()["id"].(string)
+	rec := s.Do(coretesting.JSONRequest(s.T(), http.MethodPost, "/orders/"+id+"/items", map[string]any{
+		"product_id": product["id"], "quantity": quantity,
+	}))
+	s.Require().Equal(http.StatusCreated, rec.Code, rec.Body.String())
+	return id
+}
+
+func today() string {
+	return time.Now().UTC().Format(time.DateOnly)
+}
+
+func (s *ReportSuite) TestSales_CountsFulfilledOrdersAndRefunds() {
+	refunded := s.OpenOrderWithItems()
+	s.Fulfil(refunded)
+	s.Fulfil(s.OpenOrderWithItems())
+	rec, _ := s.Transition(s.OpenOrderWithItems(), "submit")
+	s.Require().Equal(http.StatusOK, rec.Code)
+	s.OpenOrderWithItems()
+
+	s.Assert().Empty(s.Get("/reports/sales", nil)["periods"], "sales show up once the aggregates are refreshed")
+
+	rec, _ = s.Transition(refunded, "refund")
+	s.Require().Equal(http.StatusOK, rec.Code, rec.Body.String())
+	s.Refresh()
+
+	report := s.Get("/reports/sales", nil)
+	s.Assert().Equal(today(), report["to"])
+	s.Assert().Equal(time.Now().UTC().AddDate(0, 0, -29).Format(time.DateOnly), report["from"])
+	s.Assert().Equal("day", report["granularity"])
+	periods := report["periods"].([]any)
+	s.Require().Len(periods, 1)
+	s.Assert().Equal(map[string]any{
+		"start":               today(),
+		"currency":            "EUR",
+		"orders":              float64(2),
+		"units":               float64(4),
+		"subtotal":            eur(1400),
+		"discount":            eur(0),
+		"tax":                 eur(0),
+		"revenue":             eur(1400),
+		"refunded":            eur(700),
+		"net_revenue":         eur(700),
+		"average_order_value": eur(700),
+	}, periods[0])
+}
+
+func (s *ReportSuite) TestSales_RefreshPicksUpLaterSales() {
+	s.Fulfil(s.OpenOrderWithItems())
+	s.Refresh()
+	s.Fulfil(s.OpenOrderWithItems())
+	s.Refresh()
+
+	periods := s.Get("/reports/sales", nil)["periods"].([]any)
+	s.Require().Len(periods, 1)
+	s.Assert().Equal(float64(2), periods[0].(map[string]any)["orders"])
+}
+
+func (s *ReportSuite) TestSales_Granularity() {
+	s.Fulfil(s.OpenOrderWithItems())
+	s.Refresh()
+
+	now := time.Now().UTC()
+	weekday := (int(now.Weekday()) + 6) % 7
+	cases := []struct {
+		granularity string
+		start       string
+	}{
+		{"day", now.Format(time.DateOnly)},
+		{"week", now.AddDate(0, 0, -weekday).Format(time.DateOnly)},
+		{"month", time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC).Format(time.DateOnly)},
+	}
+	for _, tc := range cases {
+		s.Run(tc.granularity, func() {
+			periods := s.Get("/reports/sales", url.Values{"granularity": {tc.granularity}})["periods"].([]any)
+			s.Require().Len(periods, 1)
+			s.Assert().Equal(tc.start, periods[0].(map[string]any)["start"])
+		})
+	}
+}
+
+func (s *ReportSuite) TestSales_OutsideRange() {
+	s.Fulfil(s.OpenOrderWithItems())
+	s.Refresh()
+
+	lastYear := time.Now().UTC().AddDate(-1, 0, 0).Format(time.DateOnly)
+	yesterday := time.Now().UTC().AddDate(0, 0, -1).Format(time.DateOnly)
+	report := s.Get("/reports/sales", url.Values{"from": {lastYear}, "to": {yesterday}})
+	s.Assert().Equal(lastYear, report["from"])
+	s.Assert().Empty(report["periods"])
+}
+
+func (s *ReportSuite) TestTopProducts_RanksByUnitsOrRevenue() {
+	cheap := s.CreateProduct("Marshmallow", "marshmallow", 100)
+	dear := s.CreateProduct("Sundae", "ice_cream", 900)
+	s.Fulfil(s.OrderOf(cheap, 5))
+	s.Fulfil(s.OrderOf(dear, 1))
+	s.OrderOf(dear, 10)
+	s.Refresh()
+
+	byUnits := s.Get("/reports/top-products", nil)
+	s.Assert().Equal("units", byUnits["by"])
+	s.Assert().Equal([]any{
+		map[string]any{"product_id": cheap["id"], "product_name": "Marshmallow", "units": float64(5), "revenue": eur(500)},
+		map[string]any{"product_id": dear["id"], "product_name": "Sundae", "units": float64(1), "revenue": eur(900)},
+	}, byUnits["products"])
+
+	byRevenue := s.Get("/reports/top-products", url.Values{"by": {"revenue"}, "limit": {"1"}})["products"].([]any)
+	s.Require().Len(byRevenue, 1)
+	s.Assert().Equal(dear["id"], byRevenue[0].(map[string]any)["product_id"])
+}
+
+func (s *ReportSuite) TestValidationErrors() {
+	cases := []struct {
+		name  string
+		path  string
+		query url.Values
+	}{
+		{"granularity", "/reports/sales", url.Values{"granularity": {"year"}}},
+		{"date", "/reports/sales", url.Values{"from": {"2026-13-01"}}},
+		{"timestamp", "/reports/sales", url.Values{"to": {"2026-01-01T00:00:00Z"}}},
+		{"empty range", "/reports/sales", url.Values{"from": {"2026-01-02"}, "to": {"2026-01-01"}}},
+		{"range too long", "/reports/sales", url.Values{"from": {"2020-01-01"}, "to": {"2026-01-01"}}},
+		{"by", "/reports/top-products", url.Values{"by": {"margin"}}},
+		{"limit", "/reports/top-products", url.Values{"limit": {"0"}}},
+		{"limit above maximum", "/reports/top-products", url.Values{"limit": {"101"}}},
+		{"top products range", "/reports/top-products", url.Values{"from": {"2026-01-02"}, "to": {"2026-01-01"}}},
+	}
+	for _, tc := range cases {
+		s.Run(tc.name, func() {
+			rec := s.Do(httptest.NewRequest(http.MethodGet, tc.path+"?"+tc.query.Encode(), nil))
+			s.Assert().Equal(http.StatusBadRequest, rec.Code, rec.Body.String())
+		})
+	}
+}
+
+func TestReportSuite(t *testing.T) {
+	suite.Run(t, new(ReportSuite))
+}
@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/render"
+
+	transporthttp "github.com/bbsbb/go-edge/core/transport/http"
+	"github.com/bbsbb/go-edge/sweetshop/internal/service"
+	"github.com/bbsbb/go-edge/sweetshop/internal/transport/http/dto"
+)
+
+type SettingsHandler struct {
+	services *service.Registry
+	logger   *slog.Logger
+}
+
+func NewSettingsHandler(services *service.Registry, logger *slog.Logger) *SettingsHandler {
+	return &SettingsHandler{services: services, logger: logger}
+}
+
+func (h *SettingsHandler) Get(w http.ResponseWriter, r *http.Request) {
+	settings, err := h.services.Settings.Get(r.Context())
+	if err != nil {
+		transporthttp.WriteError(w, r, err, h.logger)
+		return
+	}
+	render.Status(r, http.StatusOK)
+	transporthttp.RenderOrLog(w, r, dto.SettingsToResponse(settings), h.logger)
+}
+
+func (h *SettingsHandler) Update(w http.ResponseWriter, r *http.Request) {
+	var req dto.UpdateSettingsRequest
+	if err := transporthttp.Bind(r, &req); err != nil {
+		transporthttp.WriteError(w, r, err, h.logger)
+		return
+	}
+
+	settings, err := h.services.Settings.UpdateCurrency(r.Context(), req.Currency)
+	if err != nil {
+		transporthttp.WriteError(w, r, err, h.logger)
+		return
+	}
+	render.Status(r, http.StatusOK)
+	transporthttp.RenderOrLog(w, r, dto.SettingsToResponse(settings), h.logger)
+}
+
+func (h *SettingsHandler) GetTax(w http.ResponseWriter, r *http.Request) {
+	profile, err := h.services.Settings.GetTaxProfile(r.Context())
+	if err != nil {
+		transporthttp.WriteError(w, r, err, h.logger)
+		return
+	}
+	render.Status(r, http.StatusOK)
+	transporthttp.RenderOrLog(w, r, dto.TaxProfileToResponse(profile), h.logger)
+}
+
+func (h *SettingsHandler) UpdateTax(w http.ResponseWriter, r *http.Request) {
+	var req dto.TaxProfileRequest
+	if err := transporthttp.Bind(r, &req); err != nil {
+		transporthttp.WriteError(w, r, err, h.logger)
+		return
+	}
+
+	profile, err := h.services.Settings.UpdateTaxProfile(r.Context(), req.Profile())
+	if err != nil {
+		transporthttp.WriteError(w, r, err, h.logger)
+		return
+	}
+	render.Status(r, http.StatusOK)
+	transporthttp.RenderOrLog(w, r, dto.TaxProfileToResponse(profile), h.logger)
+}
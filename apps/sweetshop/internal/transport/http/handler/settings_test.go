@@ -0,0 +1,73 @@
+//go:build testing
+
+package handler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	coretesting "github.com/bbsbb/go-edge/core/testing"
+)
+
+type SettingsSuite struct {
+	IntegrationSuite
+}
+
+func (s *SettingsSuite) UpdateCurrency(currency string) *httptest.ResponseRecorder {
+	return s.Do(coretesting.JSONRequest(s.T(), http.MethodPut, "/settings", map[string]any{"currency": currency}))
+}
+
+func (s *SettingsSuite) TestDefaultCurrency() {
+	rec := s.Do(httptest.NewRequest(http.MethodGet, "/settings", nil))
+	s.Require().Equal(http.StatusOK, rec.Code)
+
+	var resp map[string]any
+	coretesting.DecodeJSON(s.T(), rec, &resp)
+	s.Assert().Equal("EUR", resp["currency"])
+}
+
+func (s *SettingsSuite) TestUpdateCurrency() {
+	rec := s.UpdateCurrency("JPY")
+	s.Require().Equal(http.StatusOK, rec.Code, rec.Body.String())
+
+	var resp map[string]any
+	coretesting.DecodeJSON(s.T(), rec, &resp)
+	s.Assert().Equal("JPY", resp["currency"])
+	s.Assert().NotEmpty(resp["updated_at"])
+
+	s.Assert().Equal("JPY", s.OpenOrder()["currency"])
+}
+
+func (s *SettingsSuite) TestUpdateCurrency_Invalid() {
+	for _, currency := range []string{"", "XYZ", "eur"} {
+		s.Assert().Equal(http.StatusBadRequest, s.UpdateCurrency(currency).Code, currency)
+	}
+}
+
+func (s *SettingsSuite) TestPricesFollowCurrency() {
+	euro := s.CreateProduct("Vanilla", "ice_cream", 350)["id"].(string)
+	s.Require().Equal(http.StatusOK, s.UpdateCurrency("JPY").Code)
+
+	rec := s.Do(coretesting.JSONRequest(s.T(), http.MethodPost, "/products", map[string]any{
+		"name": "Matcha", "category": "ice_cream", "price": eur(350),
+	}))
+	s.Assert().Equal(http.StatusBadRequest, rec.Code, "new prices must be in the organization's currency")
+
+	rec = s.Do(coretesting.JSONRequest(s.T(), http.MethodPost, "/products", map[string]any{
+		"name": "Matcha", "category": "ice_cream", "price": map[string]any{"amount": 400, "currency": "JPY"},
+	}))
+	s.Require().Equal(http.StatusCreated, rec.Code, rec.Body.String())
+
+	order := s.OpenOrder()["id"].(string)
+	rec = s.Do(coretesting.JSONRequest(s.T(), http.MethodPost, "/orders/"+order+"/items", map[string]any{
+		"product_id": euro, "quantity": 1,
+	}))
+	s.Assert().Equal(http.StatusUnprocessableEntity, rec.Code, "a product priced in another currency cannot be ordered")
+}
+
+func TestSettingsSuite(t *testing.T) {
+	suite.Run(t, new(SettingsSuite))
+}
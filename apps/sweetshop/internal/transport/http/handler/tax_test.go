@@ -0,0 +1,166 @@
+//go:build testing
+
+package handler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	coretesting "github.com/bbsbb/go-edge/core/testing"
+)
+
+type TaxSuite struct {
+	IntegrationSuite
+}
+
+func (s *TaxSuite) PutTaxProfile(body map[string]any) map[string]any {
+	rec := s.Do(coretesting.JSONRequest(s.T(), http.MethodPut, "/settings/tax", body))
+	s.Require().Equal(http.StatusOK, rec.Code, rec.Body.String())
+
+	var resp map[string]any
+	coretesting.DecodeJSON(s.T(), rec, &resp)
+	return resp
+}
+
+func (s *TaxSuite) GetOrder(id string) map[string]any {
+	rec := s.Do(httptest.NewRequest(http.MethodGet, "/orders/"+id, nil))
+	s.Require().Equal(http.StatusOK, rec.Code)
+
+	var resp map[string]any
+	coretesting.DecodeJSON(s.T(), rec, &resp)
+	return resp
+}
+
+// OpenMixedOrder opens an order with two 3.50 ice creams and one 2.00
+// marshmallow.
+func (s *TaxSuite) OpenMixedOrder() string {
+	order := s.OpenOrderWithItems()
+	marshmallow := s.CreateProduct("Puff", "marshmallow", 200)["id"].(string)
+	rec := s.Do(coretesting.JSONRequest(s.T(), http.MethodPost, "/orders/"+order+"/items", map[string]any{
+		"product_id": marshmallow, "quantity": 1,
+	}))
+	s.Require().Equal(http.StatusCreated, rec.Code, rec.Body.String())
+	return order
+}
+
+func taxLine(rate int, net, tax float64) map[string]any {
+	return map[string]any{"rate": float64(rate), "net": eur(net), "tax": eur(tax)}
+}
+
+func (s *TaxSuite) TestGetTaxProfile_DefaultChargesNothing() {
+	rec := s.Do(httptest.NewRequest(http.MethodGet, "/settings/tax", nil))
+	s.Require().Equal(http.StatusOK, rec.Code)
+
+	var resp map[string]any
+	coretesting.DecodeJSON(s.T(), rec, &resp)
+	s.Assert().Equal("exclusive", resp["pricing"])
+	s.Assert().Equal("per_line", resp["rounding"])
+	s.Assert().Equal(float64(0), resp["default_rate"])
+	s.Assert().Empty(resp["rates"])
+	s.Assert().Nil(resp["updated_at"])
+}
+
+func (s *TaxSuite) TestUpdateTaxProfile() {
+	s.PutTaxProfile(map[string]any{
+		"pricing": "exclusive", "rounding": "per_line", "default_rate": 2000, "rates": map[string]any{"ice_cream": 700},
+	})
+	resp := s.PutTaxProfile(map[string]any{
+		"pricing": "inclusive", "rounding": "per_order", "default_rate": 1900, "rates": map[string]any{"marshmallow": 500},
+	})
+	s.Assert().NotNil(resp["updated_at"])
+
+	rec := s.Do(httptest.NewRequest(http.MethodGet, "/settings/tax", nil))
+	var got map[string]any
+	coretesting.DecodeJSON(s.T(), rec, &got)
+	s.Assert().Equal("inclusive", got["pricing"])
+	s.Assert().Equal("per_order", got["rounding"])
+	s.Assert().Equal(float64(1900), got["default_rate"])
+	s.Assert().Equal(map[string]any{"marshmallow": float64(500)}, got["rates"], "rates are replaced")
+}
+
+func (s *TaxSuite) TestUpdateTaxProfile_ValidationErrors() {
+	cases := []struct {
+		name string
+		body map[string]any
+	}{
+		{"pricing", map[string]any{"pricing": "gross", "rounding": "per_line"}},
+		{"rounding", map[string]any{"pricing": "exclusive", "rounding": "per_item"}},
+		{"default rate above 100%", map[string]any{"pricing": "exclusive", "rounding": "per_line", "default_rate": 10001}},
+		{"negative rate", map[string]any{"pricing": "exclusive", "rounding": "per_line", "rates": map[string]any{"ice_cream": -1}}},
+		{"category", map[string]any{"pricing": "exclusive", "rounding": "per_line", "rates": map[string]any{"fudge": 100}}},
+	}
+	for _, tc := range cases {
+		s.Run(tc.name, func() {
+			rec := s.Do(coretesting.JSONRequest(s.T(), http.MethodPut, "/settings/tax", tc.body))
+			s.Assert().Equal(http.StatusBadRequest, rec.Code, rec.Body.String())
+		})
+	}
+}
+
+func (s *TaxSuite) TestExclusiveTaxIsAddedToTotal() {
+	s.PutTaxProfile(map[string]any{
+		"pricing": "exclusive", "rounding": "per_line", "default_rate": 2000, "rates": map[string]any{"ice_cream": 700},
+	})
+
+	order := s.GetOrder(s.OpenMixedOrder())
+	s.Assert().Equal(eur(900), order["subtotal"])
+	s.Assert().Equal(eur(0), order["discount"])
+	s.Assert().Equal(eur(89), order["tax"])
+	s.Assert().Equal(eur(989), order["total"])
+	s.Assert().Equal(map[string]any{
+		"pricing":  "exclusive",
+		"rounding": "per_line",
+		"lines":    []any{taxLine(700, 700, 49), taxLine(2000, 200, 40)},
+	}, order["tax_breakdown"])
+}
+
+func (s *TaxSuite) TestInclusiveTaxIsPartOfTotal() {
+	s.PutTaxProfile(map[string]any{"pricing": "inclusive", "rounding": "per_order", "default_rate": 2000})
+
+	order := s.GetOrder(s.OpenMixedOrder())
+	s.Assert().Equal(eur(900), order["subtotal"])
+	s.Assert().Equal(eur(150), order["tax"])
+	s.Assert().Equal(eur(900), order["total"])
+	s.Assert().Equal([]any{taxLine(2000, 750, 150)}, order["tax_breakdown"].(map[string]any)["lines"])
+}
+
+func (s *TaxSuite) TestSubmitStoresTax() {
+	s.PutTaxProfile(map[string]any{"pricing": "exclusive", "rounding": "per_line", "default_rate": 1000})
+	order := s.OpenOrderWithItems()
+
+	rec, resp := s.Transition(order, "submit")
+	s.Require().Equal(http.StatusOK, rec.Code, rec.Body.String())
+	s.Assert().Equal(eur(770), resp["total"])
+	s.Assert().Equal(eur(770), attempts(resp)[0]["amount"], "the total with tax is authorized")
+
+	s.PutTaxProfile(map[string]any{"pricing": "exclusive", "rounding": "per_line", "default_rate": 2500})
+
+	got := s.GetOrder(order)
+	s.Assert().Equal(eur(70), got["tax"], "a submitted order keeps its tax")
+	s.Assert().Equal(eur(770), got["total"])
+	s.Assert().Equal([]any{taxLine(1000, 700, 70)}, got["tax_breakdown"].(map[string]any)["lines"])
+
+	open := s.GetOrder(s.OpenOrderWithItems())
+	s.Assert().Equal(eur(875), open["total"], "open orders follow the profile")
+}
+
+func (s *TaxSuite) TestTaxesDiscountedPrice() {
+	s.PutTaxProfile(map[string]any{"pricing": "exclusive", "rounding": "per_order", "default_rate": 1000})
+	rec := s.Do(coretesting.JSONRequest(s.T(), http.MethodPost, "/promotions", map[string]any{
+		"name": "Half", "kind": "percentage", "percent_off": 50,
+	}))
+	s.Require().Equal(http.StatusCreated, rec.Code)
+
+	order := s.GetOrder(s.OpenOrderWithItems())
+	s.Assert().Equal(eur(700), order["subtotal"])
+	s.Assert().Equal(eur(-350), order["discount"])
+	s.Assert().Equal(eur(35), order["tax"])
+	s.Assert().Equal(eur(385), order["total"])
+}
+
+func TestTaxSuite(t *testing.T) {
+	suite.Run(t, new(TaxSuite))
+}
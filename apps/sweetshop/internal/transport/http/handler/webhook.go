@@ -0,0 +1,127 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+
+	coredomain "github.com/bbsbb/go-edge/core/domain"
+	transporthttp "github.com/bbsbb/go-edge/core/transport/http"
+	"github.com/bbsbb/go-edge/sweetshop/internal/service"
+	"github.com/bbsbb/go-edge/sweetshop/internal/transport/http/dto"
+)
+
+type WebhookHandler struct {
+	services *service.Registry
+	logger   *slog.Logger
+}
+
+func NewWebhookHandler(services *service.Registry, logger *slog.Logger) *WebhookHandler {
+	return &WebhookHandler{services: services, logger: logger}
+}
+
+func (h *WebhookHandler) List(w http.ResponseWriter, r *http.Request) {
+	endpoints, err := h.services.Webhooks.ListEndpoints(r.Context())
+	if err != nil {
+		transporthttp.WriteError(w, r, err, h.logger)
+		return
+	}
+	transporthttp.RenderListOrLog(w, r, dto.WebhookEndpointListToResponse(endpoints), h.logger)
+}
+
+func (h *WebhookHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, err := coredomain.ParseID(chi.URLParam(r, "id"))
+	if err != nil {
+		transporthttp.WriteError(w, r, err, h.logger)
+		return
+	}
+
+	endpoint, err := h.services.Webhooks.GetEndpoint(r.Context(), id.UUID())
+	if err != nil {
+		transporthttp.WriteError(w, r, err, h.logger)
+		return
+	}
+	render.Status(r, http.StatusOK)
+	transporthttp.RenderOrLog(w, r, dto.WebhookEndpointToResponse(endpoint), h.logger)
+}
+
+func (h *WebhookHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req dto.CreateWebhookEndpointRequest
+	if err := transporthttp.Bind(r, &req); err != nil {
+		transporthttp.WriteError(w, r, err, h.logger)
+		return
+	}
+
+	endpoint, err := h.services.Webhooks.CreateEndpoint(r.Context(), req.URL, req.EventTypes)
+	if err != nil {
+		transporthttp.WriteError(w, r, err, h.logger)
+		return
+	}
+	render.Status(r, http.StatusCreated)
+	transporthttp.RenderOrLog(w, r, dto.WebhookEndpointToCreatedResponse(endpoint), h.logger)
+}
+
+func (h *WebhookHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := coredomain.ParseID(chi.URLParam(r, "id"))
+	if err != nil {
+		transporthttp.WriteError(w, r, err, h.logger)
+		return
+	}
+
+	var req dto.UpdateWebhookEndpointRequest
+	if err := transporthttp.Bind(r, &req); err != nil {
+		transporthttp.WriteError(w, r, err, h.logger)
+		return
+	}
+
+	endpoint, err := h.services.Webhooks.UpdateEndpoint(r.Context(), id.UUID(), req.URL, req.EventTypes, *req.Enabled)
+	if err != nil {
+		transporthttp.WriteError(w, r, err, h.logger)
+		return
+	}
+	render.Status(r, http.StatusOK)
+	transporthttp.RenderOrLog(w, r, dto.WebhookEndpointToResponse(endpoint), h.logger)
+}
+
+func (h *WebhookHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := coredomain.ParseID(chi.URLParam(r, "id"))
+	if err != nil {
+		transporthttp.WriteError(w, r, err, h.logger)
+		return
+	}
+
+	if err := h.services.Webhooks.DeleteEndpoint(r.Context(), id.UUID()); err != nil {
+		transporthttp.WriteError(w, r, err, h.logger)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Deliveries serves the endpoint's delivery log, newest first.
+func (h *WebhookHandler) Deliveries(w http.ResponseWriter, r *http.Request) {
+	id, err := coredomain.ParseID(chi.URLParam(r, "id"))
+	if err != nil {
+		transporthttp.WriteError(w, r, err, h.logger)
+		return
+	}
+
+	limit := int32(service.DefaultWebhookDeliveryLimit)
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		n, err := strconv.ParseInt(raw, 10, 32)
+		if err != nil {
+			transporthttp.WriteError(w, r, coredomain.NewError(coredomain.CodeValidation, "limit must be an integer"), h.logger)
+			return
+		}
+		limit = int32(n)
+	}
+
+	deliveries, err := h.services.Webhooks.ListDeliveries(r.Context(), id.UUID(), limit)
+	if err != nil {
+		transporthttp.WriteError(w, r, err, h.logger)
+		return
+	}
+	transporthttp.RenderListOrLog(w, r, dto.WebhookDeliveryListToResponse(deliveries), h.logger)
+}
@@ -0,0 +1,278 @@
+//go:build testing
+
+package handler_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	coretesting "github.com/bbsbb/go-edge/core/testing"
+	"github.com/bbsbb/go-edge/sweetshop/internal/infrastructure/outbound"
+)
+
+type receivedWebhook struct {
+	header http.Header
+	body   []byte
+}
+
+// webhookReceiver is a local endpoint that records what it is sent and
+// answers with a configurable status.
+type webhookReceiver struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	status   int
+	received []receivedWebhook
+}
+
+func newWebhookReceiver(t *testing.T, status int) *webhookReceiver {
+	rcv := &webhookReceiver{status: status}
+	rcv.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		rcv.mu.Lock()
+		defer rcv.mu.Unlock()
+		rcv.received = append(rcv.received, receivedWebhook{header: r.Header.Clone(), body: body})
+		w.WriteHeader(rcv.status)
+	}))
+	t.Cleanup(rcv.Close)
+	return rcv
+}
+
+func (r *webhookReceiver) setStatus(status int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.status = status
+}
+
+func (r *webhookReceiver) deliveries() []receivedWebhook {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]receivedWebhook(nil), r.received...)
+}
+
+type WebhookSuite struct {
+	IntegrationSuite
+}
+
+func (s *WebhookSuite) CreateEndpoint(url string, eventTypes ...string) map[string]any {
+	req := coretesting.JSONRequest(s.T(), http.MethodPost, "/webhooks", map[string]any{
+		"url": url, "event_types": eventTypes,
+	})
+	rec := s.Do(req)
+	s.Require().Equal(http.StatusCreated, rec.Code, rec.Body.String())
+
+	var resp map[string]any
+	coretesting.DecodeJSON(s.T(), rec, &resp)
+	return resp
+}
+
+func (s *WebhookSuite) GetEndpoint(id string) map[string]any {
+	rec := s.Do(httptest.NewRequest(http.MethodGet, "/webhooks/"+id, nil))
+	s.Require().Equal(http.StatusOK, rec.Code)
+
+	var resp map[string]any
+	coretesting.DecodeJSON(s.T(), rec, &resp)
+	return resp
+}
+
+func (s *WebhookSuite) Deliveries(endpointID string) []map[string]any {
+	rec := s.Do(httptest.NewRequest(http.MethodGet, "/webhooks/"+endpointID+"/deliveries", nil))
+	s.Require().Equal(http.StatusOK, rec.Code)
+
+	var resp []map[string]any
+	coretesting.DecodeJSON(s.T(), rec, &resp)
+	return resp
+}
+
+// DeliverDue runs the delivery job's work for the test organization once,
+// past the retry delay of testing.yaml.
+func (s *WebhookSuite) DeliverDue() int {
+	time.Sleep(5 * time.Millisecond)
+	n, err := s.Services.Webhooks.DeliverDue(s.Context())
+	s.Require().NoError(err)
+	return n
+}
+
+func (s *WebhookSuite) TestCreateEndpoint() {
+	resp := s.CreateEndpoint("https://shop.example/hooks", "order.paid", "order.opened", "order.paid")
+
+	s.Assert().NotEmpty(resp["id"])
+	s.Assert().Equal("https://shop.example/hooks", resp["url"])
+	s.Assert().Equal([]any{"order.opened", "order.paid"}, resp["event_types"])
+	s.Assert().Equal(true, resp["enabled"])
+	s.Assert().Regexp(`^whsec_[0-9a-f]{64}$`, resp["secret"])
+
+	got := s.GetEndpoint(resp["id"].(string))
+	s.Assert().NotContains(got, "secret")
+}
+
+func (s *WebhookSuite) TestCreateEndpoint_ValidationErrors() {
+	cases := []struct {
+		name string
+		body map[string]any
+	}{
+		{"missing url", map[string]any{"event_types": []string{"order.cancelled"}}},
+		{"relative url", map[string]any{"url": "/hooks", "event_types": []string{"order.cancelled"}}},
+		{"no event types", map[string]any{"url": "https://shop.example/hooks", "event_types": []string{}}},
+		{"unknown event type", map[string]any{"url": "https://shop.example/hooks", "event_types": []string{"order.eaten"}}},
+		{"metadata address", map[string]any{"url": "http://169.254.169.254/latest", "event_types": []string{"order.cancelled"}}},
+		{"private address", map[string]any{"url": "https://10.0.0.1/hooks", "event_types": []string{"order.cancelled"}}},
+	}
+	for _, tc := range cases {
+		s.Run(tc.name, func() {
+			rec := s.Do(coretesting.JSONRequest(s.T(), http.MethodPost, "/webhooks", tc.body))
+			s.Assert().Equal(http.StatusBadRequest, rec.Code)
+		})
+	}
+}
+
+func (s *WebhookSuite) TestUpdateAndDeleteEndpoint() {
+	created := s.CreateEndpoint("https://shop.example/hooks", "order.cancelled")
+	id := created["id"].(string)
+
+	rec := s.Do(coretesting.JSONRequest(s.T(), http.MethodPut, "/webhooks/"+id, map[string]any{
+		"url": "https://shop.example/v2/hooks", "event_types": []string{"order.opened"}, "enabled": false,
+	}))
+	s.Require().Equal(http.StatusOK, rec.Code)
+	var updated map[string]any
+	coretesting.DecodeJSON(s.T(), rec, &updated)
+	s.Assert().Equal("https://shop.example/v2/hooks", updated["url"])
+	s.Assert().Equal([]any{"order.opened"}, updated["event_types"])
+	s.Assert().Equal(false, updated["enabled"])
+	s.Assert().NotNil(updated["disabled_at"])
+
+	rec = s.Do(httptest.NewRequest(http.MethodGet, "/webhooks", nil))
+	s.Require().Equal(http.StatusOK, rec.Code)
+	var list []map[string]any
+	coretesting.DecodeJSON(s.T(), rec, &list)
+	s.Assert().Len(list, 1)
+
+	rec = s.Do(httptest.NewRequest(http.MethodDelete, "/webhooks/"+id, nil))
+	s.Assert().Equal(http.StatusNoContent, rec.Code)
+	rec = s.Do(httptest.NewRequest(http.MethodGet, "/webhooks/"+id, nil))
+	s.Assert().Equal(http.StatusNotFound, rec.Code)
+}
+
+func (s *WebhookSuite) TestDeliversSignedOrderCancelled() {
+	rcv := newWebhookReceiver(s.T(), http.StatusOK)
+	endpoint := s.CreateEndpoint(rcv.URL, "order.cancelled")
+	order := s.OpenOrder()
+	rec, _ := s.Transition(order["id"].(string), "cancel")
+	s.Require().Equal(http.StatusOK, rec.Code)
+
+	s.Assert().Equal(1, s.DeliverDue())
+
+	got := rcv.deliveries()
+	s.Require().Len(got, 1)
+	s.Assert().Equal("order.cancelled", got[0].header.Get(outbound.HeaderEvent))
+	s.Assert().NoError(outbound.Verify(endpoint["secret"].(string), got[0].header, got[0].body, time.Minute, time.Now()))
+
+	var payload struct {
+		ID   string `json:"id"`
+		Type string `json:"type"`
+		Data struct {
+			OrderID string `json:"order_id"`
+			Status  string `json:"status"`
+		} `json:"data"`
+	}
+	s.Require().NoError(json.Unmarshal(got[0].body, &payload))
+	s.Assert().NotEmpty(payload.ID)
+	s.Assert().Equal("order.cancelled", payload.Type)
+	s.Assert().Equal(order["id"], payload.Data.OrderID)
+	s.Assert().Equal("cancelled", payload.Data.Status)
+
+	log := s.Deliveries(endpoint["id"].(string))
+	s.Require().Len(log, 1)
+	s.Assert().Equal(got[0].header.Get(outbound.HeaderDelivery), log[0]["id"])
+	s.Assert().Equal("succeeded", log[0]["status"])
+	s.Assert().Equal(float64(1), log[0]["attempts"])
+	s.Assert().Equal(float64(http.StatusOK), log[0]["response_status"])
+	s.Assert().Nil(log[0]["next_attempt_at"])
+
+	s.Assert().Zero(s.DeliverDue(), "a delivered event is not sent again")
+}
+
+func (s *WebhookSuite) TestOnlySubscribedEventsAreQueued() {
+	rcv := newWebhookReceiver(s.T(), http.StatusOK)
+	endpoint := s.CreateEndpoint(rcv.URL, "order.cancelled")
+	s.OpenOrder()
+
+	s.Assert().Zero(s.DeliverDue())
+	s.Assert().Empty(s.Deliveries(endpoint["id"].(string)))
+}
+
+func (s *WebhookSuite) TestFailedDeliveryIsRetried() {
+	rcv := newWebhookReceiver(s.T(), http.StatusServiceUnavailable)
+	endpoint := s.CreateEndpoint(rcv.URL, "order.opened")
+	s.OpenOrder()
+
+	s.Assert().Zero(s.DeliverDue())
+	log := s.Deliveries(endpoint["id"].(string))
+	s.Require().Len(log, 1)
+	s.Assert().Equal("pending", log[0]["status"])
+	s.Assert().Equal(float64(1), log[0]["attempts"])
+	s.Assert().Equal(float64(http.StatusServiceUnavailable), log[0]["response_status"])
+	s.Assert().Equal("unexpected status 503", log[0]["last_error"])
+	s.Assert().NotNil(log[0]["next_attempt_at"])
+
+	rcv.setStatus(http.StatusAccepted)
+	s.Assert().Equal(1, s.DeliverDue())
+
+	log = s.Deliveries(endpoint["id"].(string))
+	s.Assert().Equal("succeeded", log[0]["status"])
+	s.Assert().Equal(float64(2), log[0]["attempts"])
+	s.Assert().Nil(log[0]["last_error"])
+	s.Assert().Len(rcv.deliveries(), 2)
+	s.Assert().Equal(float64(0), s.GetEndpoint(endpoint["id"].(string))["consecutive_failures"])
+}
+
+func (s *WebhookSuite) TestEndpointIsDisabledAfterRepeatedFailures() {
+	rcv := newWebhookReceiver(s.T(), http.StatusInternalServerError)
+	endpoint := s.CreateEndpoint(rcv.URL, "order.opened")
+	id := endpoint["id"].(string)
+	s.OpenOrder()
+
+	// testing.yaml disables an endpoint after 3 consecutive failures.
+	for range 3 {
+		s.DeliverDue()
+	}
+
+	got := s.GetEndpoint(id)
+	s.Assert().Equal(false, got["enabled"])
+	s.Assert().Equal(float64(3), got["consecutive_failures"])
+	s.Assert().NotNil(got["disabled_at"])
+
+	s.DeliverDue()
+	s.Assert().Len(rcv.deliveries(), 3, "a disabled endpoint is not called")
+	s.Assert().Equal("pending", s.Deliveries(id)[0]["status"])
+
+	rcv.setStatus(http.StatusOK)
+	rec := s.Do(coretesting.JSONRequest(s.T(), http.MethodPut, "/webhooks/"+id, map[string]any{
+		"url": rcv.URL, "event_types": []string{"order.opened"}, "enabled": true,
+	}))
+	s.Require().Equal(http.StatusOK, rec.Code)
+
+	s.Assert().Equal(1, s.DeliverDue(), "re-enabling resumes pending deliveries")
+	s.Assert().Equal(float64(0), s.GetEndpoint(id)["consecutive_failures"])
+}
+
+func (s *WebhookSuite) TestDeliveries_Validation() {
+	endpoint := s.CreateEndpoint("https://shop.example/hooks", "order.cancelled")
+
+	rec := s.Do(httptest.NewRequest(http.MethodGet, "/webhooks/"+endpoint["id"].(string)+"/deliveries?limit=0", nil))
+	s.Assert().Equal(http.StatusBadRequest, rec.Code)
+
+	rec = s.Do(httptest.NewRequest(http.MethodGet, "/webhooks/019505e0-0000-7000-8000-000000000000/deliveries", nil))
+	s.Assert().Equal(http.StatusNotFound, rec.Code)
+}
+
+func TestWebhookSuite(t *testing.T) {
+	suite.Run(t, new(WebhookSuite))
+}
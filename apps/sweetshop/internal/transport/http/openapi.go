@@ -0,0 +1,337 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	transporthttp "github.com/bbsbb/go-edge/core/transport/http"
+	"github.com/bbsbb/go-edge/core/transport/http/openapi"
+	"github.com/bbsbb/go-edge/sweetshop/internal/transport/http/dto"
+	"github.com/bbsbb/go-edge/sweetshop/internal/transport/http/handler"
+)
+
+const openAPIPath = "/openapi.json"
+
+// apiSpec documents every route registered by registerAPIRoutes. Build fails
+// when an operation here has no matching route.
+func apiSpec() *openapi.Spec {
+	id := openapi.PathParam("id", "uuid")
+	itemID := openapi.PathParam("itemID", "uuid")
+
+	spec := openapi.NewSpec(openapi.Info{
+		Title:       "Sweetshop API",
+		Version:     "1.0.0",
+		Description: "Multi-tenant product catalog and ordering. Every request is scoped by the X-Organization-Slug header.",
+	}).
+		Operation(http.MethodGet, "/settings", openapi.Operation{
+			ID:        "getSettings",
+			Summary:   "Get the organization's settings",
+			Tags:      []string{"settings"},
+			Responses: []openapi.Response{{Status: http.StatusOK, Body: dto.SettingsResponse{}}},
+		}).
+		Operation(http.MethodPut, "/settings", openapi.Operation{
+			ID:        "updateSettings",
+			Summary:   "Change the currency the organization prices in",
+			Tags:      []string{"settings"},
+			Request:   dto.UpdateSettingsRequest{},
+			Responses: []openapi.Response{{Status: http.StatusOK, Body: dto.SettingsResponse{}}},
+			Errors:    []int{http.StatusBadRequest},
+		}).
+		Operation(http.MethodGet, "/settings/tax", openapi.Operation{
+			ID:        "getTaxProfile",
+			Summary:   "Get the organization's tax profile; organizations that have not set one charge no tax",
+			Tags:      []string{"settings"},
+			Responses: []openapi.Response{{Status: http.StatusOK, Body: dto.TaxProfileResponse{}}},
+		}).
+		Operation(http.MethodPut, "/settings/tax", openapi.Operation{
+			ID:        "updateTaxProfile",
+			Summary:   "Replace the organization's tax profile; submitted orders keep their tax",
+			Tags:      []string{"settings"},
+			Request:   dto.TaxProfileRequest{},
+			Responses: []openapi.Response{{Status: http.StatusOK, Body: dto.TaxProfileResponse{}}},
+			Errors:    []int{http.StatusBadRequest},
+		})
+
+	return catalogOperations(spec).
+		Operation(http.MethodGet, "/orders", openapi.Operation{
+			ID:      "listOrders",
+			Summary: "List orders with their items, newest first",
+			Tags:    []string{"orders"},
+			Parameters: []openapi.Parameter{
+				openapi.QueryParam("status", "string", "Only orders in this status"),
+				openapi.QueryParam("created_from", "string", "Only orders created at or after this RFC 3339 time"),
+				openapi.QueryParam("created_to", "string", "Only orders created before this RFC 3339 time"),
+				openapi.QueryParam("min_total", "integer", "Only submitted orders with at least this total, in minor units of the organization's currency"),
+				openapi.QueryParam("max_total", "integer", "Only submitted orders with at most this total, in minor units of the organization's currency"),
+				openapi.QueryParam("product_id", "string", "Only orders with an item of this product"),
+				openapi.QueryParam("cursor", "string", "next_cursor of the previous page"),
+				openapi.QueryParam("limit", "integer", "Maximum number of orders (1-200, default 50)"),
+			},
+			Responses: []openapi.Response{{Status: http.StatusOK, Body: dto.OrderListResponse{}}},
+			Errors:    []int{http.StatusBadRequest},
+		}).
+		Operation(http.MethodPost, "/orders", openapi.Operation{
+			ID:        "openOrder",
+			Summary:   "Open an order",
+			Tags:      []string{"orders"},
+			Responses: []openapi.Response{{Status: http.StatusCreated, Body: dto.OrderResponse{}}},
+		}).
+		Operation(http.MethodGet, "/orders/stream", openapi.Operation{
+			ID:      "streamOrderEvents",
+			Summary: "Stream order events (Server-Sent Events)",
+			Tags:    []string{"orders"},
+			Parameters: []openapi.Parameter{
+				{
+					Name:        "Last-Event-ID",
+					In:          "header",
+					Description: "Resume after this event ID; missed events are replayed first, or a resync event is sent if there are too many",
+					Schema:      &openapi.Schema{Type: "string", Format: "uuid"},
+				},
+			},
+			Responses: []openapi.Response{{
+				Status:     http.StatusOK,
+				Body:       dto.OrderEventResponse{},
+				MediaTypes: []string{transporthttp.MediaTypeEventStream},
+			}},
+			Errors: []int{http.StatusBadRequest, http.StatusNotAcceptable},
+		}).
+		Operation(http.MethodGet, "/orders/live", openapi.Operation{
+			ID:      "liveOrders",
+			Summary: "Exchange order commands and updates over a WebSocket",
+			Tags:    []string{"orders"},
+			Responses: []openapi.Response{{
+				Status: http.StatusSwitchingProtocols,
+				Description: "WebSocket joined to the caller's organization. Clients send {type, id, data} " +
+					"commands (order.add_item) and receive replies with the same id (order.item_added or " +
+					"error), plus order.updated whenever an order of the organization changes.",
+			}},
+			Errors: []int{http.StatusBadRequest},
+		}).
+		Operation(http.MethodGet, "/orders/{id}", openapi.Operation{
+			ID:         "getOrder",
+			Summary:    "Get an order",
+			Tags:       []string{"orders"},
+			Parameters: []openapi.Parameter{id},
+			Responses:  []openapi.Response{{Status: http.StatusOK, Body: dto.OrderResponse{}}},
+			Errors:     []int{http.StatusBadRequest, http.StatusNotFound},
+		}).
+		Operation(http.MethodPost, "/orders/{id}/items", openapi.Operation{
+			ID:         "addOrderItem",
+			Summary:    "Add an item of an active product with its chosen options to an open order, reserving tracked stock",
+			Tags:       []string{"orders"},
+			Parameters: []openapi.Parameter{id},
+			Request:    dto.AddOrderItemRequest{},
+			Responses: []openapi.Response{{
+				Status:      http.StatusCreated,
+				Body:        dto.OrderItemCreatedResponse{},
+				Description: "The line holding the units. A product the order already has a line for with the same options at the same price is added to that line.",
+			}},
+			Errors: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict, http.StatusUnprocessableEntity},
+		}).
+		Operation(http.MethodPatch, "/orders/{id}/items/{itemID}", openapi.Operation{
+			ID:         "updateOrderItem",
+			Summary:    "Change the quantity of an open order's item, reserving or releasing tracked stock",
+			Tags:       []string{"orders"},
+			Parameters: []openapi.Parameter{id, itemID},
+			Request:    dto.UpdateOrderItemRequest{},
+			Responses:  []openapi.Response{{Status: http.StatusOK, Body: dto.OrderItemCreatedResponse{}}},
+			Errors:     []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict, http.StatusUnprocessableEntity},
+		}).
+		Operation(http.MethodDelete, "/orders/{id}/items/{itemID}", openapi.Operation{
+			ID:         "removeOrderItem",
+			Summary:    "Remove an item from an open order, releasing its tracked stock",
+			Tags:       []string{"orders"},
+			Parameters: []openapi.Parameter{id, itemID},
+			Responses:  []openapi.Response{{Status: http.StatusNoContent}},
+			Errors:     []int{http.StatusBadRequest, http.StatusNotFound, http.StatusUnprocessableEntity},
+		}).
+		Operation(http.MethodGet, "/orders/{id}/history", openapi.Operation{
+			ID:         "getOrderStatusHistory",
+			Summary:    "List the statuses an order went through, oldest first",
+			Tags:       []string{"orders"},
+			Parameters: []openapi.Parameter{id},
+			Responses:  []openapi.Response{{Status: http.StatusOK, Body: []dto.OrderStatusChangeResponse{}}},
+			Errors:     []int{http.StatusBadRequest, http.StatusNotFound},
+		}).
+		Operation(http.MethodPost, "/orders/{id}/submit", openapi.Operation{
+			ID:         "submitOrder",
+			Summary:    "Authorize payment for an open order and submit it",
+			Tags:       []string{"orders"},
+			Parameters: []openapi.Parameter{id},
+			Responses: []openapi.Response{{
+				Status:      http.StatusOK,
+				Body:        dto.OrderResponse{},
+				Description: "Order submitted; its items, promotion discounts, tax and totals can no longer change. A declined authorization, or a promotion that ran out of uses, answers 422 and leaves the order open.",
+			}},
+			Errors: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict, http.StatusUnprocessableEntity},
+		}).
+		Operation(http.MethodPost, "/orders/{id}/pay", openapi.Operation{
+			ID:         "payOrder",
+			Summary:    "Capture payment for a submitted order",
+			Tags:       []string{"orders"},
+			Parameters: []openapi.Parameter{id},
+			Responses: []openapi.Response{{
+				Status:      http.StatusOK,
+				Body:        dto.OrderResponse{},
+				Description: "Order paid. A declined capture answers 422 and leaves the order submitted.",
+			}},
+			Errors: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict, http.StatusUnprocessableEntity},
+		}).
+		Operation(http.MethodPost, "/orders/{id}/fulfil", openapi.Operation{
+			ID:         "fulfilOrder",
+			Summary:    "Mark a paid order as handed over",
+			Tags:       []string{"orders"},
+			Parameters: []openapi.Parameter{id},
+			Responses:  []openapi.Response{{Status: http.StatusOK, Body: dto.OrderResponse{}}},
+			Errors:     []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict, http.StatusUnprocessableEntity},
+		}).
+		Operation(http.MethodPost, "/orders/{id}/cancel", openapi.Operation{
+			ID:         "cancelOrder",
+			Summary:    "Cancel an order that has not been fulfilled",
+			Tags:       []string{"orders"},
+			Parameters: []openapi.Parameter{id},
+			Responses: []openapi.Response{{
+				Status:      http.StatusOK,
+				Body:        dto.OrderResponse{},
+				Description: "Order cancelled. A submitted order's authorization is voided and a paid order's capture refunded first.",
+			}},
+			Errors: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict, http.StatusUnprocessableEntity},
+		}).
+		Operation(http.MethodPost, "/orders/{id}/refund", openapi.Operation{
+			ID:         "refundOrder",
+			Summary:    "Refund the captured payment of a fulfilled order",
+			Tags:       []string{"orders"},
+			Parameters: []openapi.Parameter{id},
+			Responses:  []openapi.Response{{Status: http.StatusOK, Body: dto.OrderResponse{}}},
+			Errors:     []int{http.StatusBadRequest, http.StatusNotFound, http.StatusUnprocessableEntity},
+		}).
+		Operation(http.MethodGet, "/promotions", openapi.Operation{
+			ID:        "listPromotions",
+			Summary:   "List promotions",
+			Tags:      []string{"promotions"},
+			Responses: []openapi.Response{{Status: http.StatusOK, Body: []dto.PromotionResponse{}}},
+		}).
+		Operation(http.MethodPost, "/promotions", openapi.Operation{
+			ID:        "createPromotion",
+			Summary:   "Create a percentage, fixed amount or buy X get Y promotion, optionally limited to a category",
+			Tags:      []string{"promotions"},
+			Request:   dto.PromotionRequest{},
+			Responses: []openapi.Response{{Status: http.StatusCreated, Body: dto.PromotionResponse{}}},
+			Errors:    []int{http.StatusBadRequest},
+		}).
+		Operation(http.MethodGet, "/promotions/{id}", openapi.Operation{
+			ID:         "getPromotion",
+			Summary:    "Get a promotion",
+			Tags:       []string{"promotions"},
+			Parameters: []openapi.Parameter{id},
+			Responses:  []openapi.Response{{Status: http.StatusOK, Body: dto.PromotionResponse{}}},
+			Errors:     []int{http.StatusBadRequest, http.StatusNotFound},
+		}).
+		Operation(http.MethodPut, "/promotions/{id}", openapi.Operation{
+			ID:         "updatePromotion",
+			Summary:    "Replace a promotion's rule; submitted orders keep their discounts",
+			Tags:       []string{"promotions"},
+			Parameters: []openapi.Parameter{id},
+			Request:    dto.PromotionRequest{},
+			Responses:  []openapi.Response{{Status: http.StatusOK, Body: dto.PromotionResponse{}}},
+			Errors:     []int{http.StatusBadRequest, http.StatusNotFound},
+		}).
+		Operation(http.MethodDelete, "/promotions/{id}", openapi.Operation{
+			ID:         "deletePromotion",
+			Summary:    "Delete a promotion; submitted orders keep their discounts",
+			Tags:       []string{"promotions"},
+			Parameters: []openapi.Parameter{id},
+			Responses:  []openapi.Response{{Status: http.StatusNoContent}},
+			Errors:     []int{http.StatusBadRequest, http.StatusNotFound},
+		}).
+		Operation(http.MethodGet, "/webhooks", openapi.Operation{
+			ID:        "listWebhookEndpoints",
+			Summary:   "List webhook endpoints",
+			Tags:      []string{"webhooks"},
+			Responses: []openapi.Response{{Status: http.StatusOK, Body: []dto.WebhookEndpointResponse{}}},
+		}).
+		Operation(http.MethodPost, "/webhooks", openapi.Operation{
+			ID:      "createWebhookEndpoint",
+			Summary: "Register a webhook endpoint",
+			Tags:    []string{"webhooks"},
+			Request: dto.CreateWebhookEndpointRequest{},
+			Responses: []openapi.Response{{
+				Status: http.StatusCreated,
+				Body:   dto.WebhookEndpointCreatedResponse{},
+				Description: "Endpoint registered. The secret is only returned here; deliveries carry " +
+					"X-Sweetshop-Timestamp and X-Sweetshop-Signature: v1=hex(HMAC-SHA256(secret, timestamp + \".\" + body)).",
+			}},
+			Errors: []int{http.StatusBadRequest},
+		}).
+		Operation(http.MethodGet, "/webhooks/{id}", openapi.Operation{
+			ID:         "getWebhookEndpoint",
+			Summary:    "Get a webhook endpoint",
+			Tags:       []string{"webhooks"},
+			Parameters: []openapi.Parameter{id},
+			Responses:  []openapi.Response{{Status: http.StatusOK, Body: dto.WebhookEndpointResponse{}}},
+			Errors:     []int{http.StatusBadRequest, http.StatusNotFound},
+		}).
+		Operation(http.MethodPut, "/webhooks/{id}", openapi.Operation{
+			ID:         "updateWebhookEndpoint",
+			Summary:    "Update a webhook endpoint; enabling it resumes its pending deliveries",
+			Tags:       []string{"webhooks"},
+			Parameters: []openapi.Parameter{id},
+			Request:    dto.UpdateWebhookEndpointRequest{},
+			Responses:  []openapi.Response{{Status: http.StatusOK, Body: dto.WebhookEndpointResponse{}}},
+			Errors:     []int{http.StatusBadRequest, http.StatusNotFound},
+		}).
+		Operation(http.MethodDelete, "/webhooks/{id}", openapi.Operation{
+			ID:         "deleteWebhookEndpoint",
+			Summary:    "Delete a webhook endpoint and its delivery log",
+			Tags:       []string{"webhooks"},
+			Parameters: []openapi.Parameter{id},
+			Responses:  []openapi.Response{{Status: http.StatusNoContent}},
+			Errors:     []int{http.StatusBadRequest, http.StatusNotFound},
+		}).
+		Operation(http.MethodGet, "/webhooks/{id}/deliveries", openapi.Operation{
+			ID:      "listWebhookDeliveries",
+			Summary: "List an endpoint's deliveries, newest first",
+			Tags:    []string{"webhooks"},
+			Parameters: []openapi.Parameter{
+				id,
+				openapi.QueryParam("limit", "integer", "Maximum number of deliveries (1-200, default 50)"),
+			},
+			Responses: []openapi.Response{{Status: http.StatusOK, Body: []dto.WebhookDeliveryResponse{}}},
+			Errors:    []int{http.StatusBadRequest, http.StatusNotFound},
+		}).
+		Operation(http.MethodGet, "/reports/sales", openapi.Operation{
+			ID:      "getSalesReport",
+			Summary: "Report fulfilled orders and refunds per period and currency, as of the last refresh of the daily aggregates",
+			Tags:    []string{"reports"},
+			Parameters: []openapi.Parameter{
+				openapi.QueryParam("from", "string", "First UTC day, YYYY-MM-DD (default 29 days before to)"),
+				openapi.QueryParam("to", "string", "Last UTC day, YYYY-MM-DD (default today)"),
+				openapi.QueryParam("granularity", "string", "day, week or month (default day)"),
+			},
+			Responses: []openapi.Response{{Status: http.StatusOK, Body: dto.SalesReportResponse{}}},
+			Errors:    []int{http.StatusBadRequest},
+		}).
+		Operation(http.MethodGet, "/reports/top-products", openapi.Operation{
+			ID:      "getTopProducts",
+			Summary: "Rank the best-selling products in the organization's currency",
+			Tags:    []string{"reports"},
+			Parameters: []openapi.Parameter{
+				openapi.QueryParam("from", "string", "First UTC day, YYYY-MM-DD (default 29 days before to)"),
+				openapi.QueryParam("to", "string", "Last UTC day, YYYY-MM-DD (default today)"),
+				openapi.QueryParam("by", "string", "units or revenue (default units)"),
+				openapi.QueryParam("limit", "integer", "Maximum number of products (1-100, default 10)"),
+			},
+			Responses: []openapi.Response{{Status: http.StatusOK, Body: dto.TopProductsResponse{}}},
+			Errors:    []int{http.StatusBadRequest},
+		})
+}
+
+// OpenAPIDocument builds the API description from a throwaway router carrying
+// the same routes as the server. Handlers are never invoked, so they need no
+// dependencies; the result is deterministic and safe to diff in CI.
+func OpenAPIDocument() (*openapi.Document, error) {
+	r := chi.NewRouter()
+	registerAPIRoutes(r, &handler.SettingsHandler{}, &handler.ProductHandler{}, &handler.CategoryHandler{}, &handler.InventoryHandler{}, &handler.OrderHandler{}, &handler.PromotionHandler{}, &handler.LiveHandler{}, &handler.WebhookHandler{}, &handler.ReportHandler{})
+	return apiSpec().Build(r)
+}
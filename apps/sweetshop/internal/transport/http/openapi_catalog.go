@@ -0,0 +1,176 @@
+package http
+
+import (
+	"net/http"
+
+	transporthttp "github.com/bbsbb/go-edge/core/transport/http"
+	"github.com/bbsbb/go-edge/core/transport/http/openapi"
+	"github.com/bbsbb/go-edge/sweetshop/internal/transport/http/dto"
+)
+
+// catalogOperations documents the product, inventory and category routes.
+func catalogOperations(spec *openapi.Spec) *openapi.Spec {
+	id := openapi.PathParam("id", "uuid")
+
+	return spec.
+		Operation(http.MethodGet, "/products", openapi.Operation{
+			ID:        "listProducts",
+			Summary:   "List products",
+			Tags:      []string{"products"},
+			Responses: []openapi.Response{{Status: http.StatusOK, Body: []dto.ProductResponse{}}},
+		}).
+		Operation(http.MethodPost, "/products", openapi.Operation{
+			ID:        "createProduct",
+			Summary:   "Create a product",
+			Tags:      []string{"products"},
+			Request:   dto.CreateProductRequest{},
+			Responses: []openapi.Response{{Status: http.StatusCreated, Body: dto.ProductResponse{}}},
+			Errors:    []int{http.StatusBadRequest, http.StatusConflict},
+		}).
+		Operation(http.MethodGet, "/products/search", openapi.Operation{
+			ID:      "searchProducts",
+			Summary: "Search products by the start of the words of their name, SKU or description, tolerating typos in the name",
+			Tags:    []string{"products"},
+			Parameters: []openapi.Parameter{
+				openapi.QueryParam("q", "string", "Words to search for, up to 200 characters"),
+				openapi.QueryParam("limit", "integer", "Maximum number of products (1-50, default 20)"),
+			},
+			Responses: []openapi.Response{{Status: http.StatusOK, Body: []dto.ProductSearchResultResponse{}}},
+			Errors:    []int{http.StatusBadRequest},
+		}).
+		Operation(http.MethodPost, "/products/import", openapi.Operation{
+			ID:      "importProducts",
+			Summary: "Create and update products from CSV or NDJSON rows, matched to products by SKU, then name; invalid rows are reported by line",
+			Tags:    []string{"products"},
+			Parameters: []openapi.Parameter{
+				openapi.QueryParam("dry_run", "boolean", "Validate the rows without storing them (default false)"),
+			},
+			Request:           dto.ProductRow{},
+			RequestMediaTypes: []string{transporthttp.CSVEncoder{}.MediaType(), transporthttp.NDJSONEncoder{}.MediaType()},
+			Responses:         []openapi.Response{{Status: http.StatusOK, Body: dto.ProductImportReportResponse{}}},
+			Errors:            []int{http.StatusBadRequest, http.StatusConflict},
+		}).
+		Operation(http.MethodGet, "/products/export", openapi.Operation{
+			ID:        "exportProducts",
+			Summary:   "Export the products as rows that can be imported, as CSV or NDJSON when accepted",
+			Tags:      []string{"products"},
+			Responses: []openapi.Response{{Status: http.StatusOK, Body: []dto.ProductRow{}}},
+		}).
+		Operation(http.MethodGet, "/products/{id}", openapi.Operation{
+			ID:         "getProduct",
+			Summary:    "Get a product",
+			Tags:       []string{"products"},
+			Parameters: []openapi.Parameter{id},
+			Responses:  []openapi.Response{{Status: http.StatusOK, Body: dto.ProductResponse{}}},
+			Errors:     []int{http.StatusBadRequest, http.StatusNotFound},
+		}).
+		Operation(http.MethodPut, "/products/{id}", openapi.Operation{
+			ID:         "updateProduct",
+			Summary:    "Update a product",
+			Tags:       []string{"products"},
+			Parameters: []openapi.Parameter{id},
+			Request:    dto.UpdateProductRequest{},
+			Responses:  []openapi.Response{{Status: http.StatusOK, Body: dto.ProductResponse{}}},
+			Errors:     []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict},
+		}).
+		Operation(http.MethodDelete, "/products/{id}", openapi.Operation{
+			ID:         "deleteProduct",
+			Summary:    "Soft-delete a product",
+			Tags:       []string{"products"},
+			Parameters: []openapi.Parameter{id},
+			Responses:  []openapi.Response{{Status: http.StatusNoContent}},
+			Errors:     []int{http.StatusBadRequest, http.StatusNotFound},
+		}).
+		Operation(http.MethodPost, "/products/{id}/restore", openapi.Operation{
+			ID:         "restoreProduct",
+			Summary:    "Restore a soft-deleted product",
+			Tags:       []string{"products"},
+			Parameters: []openapi.Parameter{id},
+			Responses:  []openapi.Response{{Status: http.StatusOK, Body: dto.ProductResponse{}}},
+			Errors:     []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict},
+		}).
+		Operation(http.MethodGet, "/products/{id}/options", openapi.Operation{
+			ID:         "listProductOptions",
+			Summary:    "List a product's variant and modifier groups with their options",
+			Tags:       []string{"products"},
+			Parameters: []openapi.Parameter{id},
+			Responses:  []openapi.Response{{Status: http.StatusOK, Body: dto.ProductOptionsResponse{}}},
+			Errors:     []int{http.StatusBadRequest, http.StatusNotFound},
+		}).
+		Operation(http.MethodPut, "/products/{id}/options", openapi.Operation{
+			ID:         "replaceProductOptions",
+			Summary:    "Replace a product's option groups; items already in orders keep their options",
+			Tags:       []string{"products"},
+			Parameters: []openapi.Parameter{id},
+			Request:    dto.ProductOptionsRequest{},
+			Responses:  []openapi.Response{{Status: http.StatusOK, Body: dto.ProductOptionsResponse{}}},
+			Errors:     []int{http.StatusBadRequest, http.StatusNotFound},
+		}).
+		Operation(http.MethodGet, "/products/{id}/inventory", openapi.Operation{
+			ID:         "getInventory",
+			Summary:    "Get a product's stock",
+			Tags:       []string{"inventory"},
+			Parameters: []openapi.Parameter{id},
+			Responses:  []openapi.Response{{Status: http.StatusOK, Body: dto.InventoryResponse{}}},
+			Errors:     []int{http.StatusBadRequest, http.StatusNotFound},
+		}).
+		Operation(http.MethodGet, "/products/{id}/inventory/adjustments", openapi.Operation{
+			ID:      "listStockAdjustments",
+			Summary: "List a product's stock adjustments, newest first",
+			Tags:    []string{"inventory"},
+			Parameters: []openapi.Parameter{
+				id,
+				openapi.QueryParam("limit", "integer", "Maximum number of adjustments (1-200, default 50)"),
+			},
+			Responses: []openapi.Response{{Status: http.StatusOK, Body: []dto.StockAdjustmentResponse{}}},
+			Errors:    []int{http.StatusBadRequest, http.StatusNotFound},
+		}).
+		Operation(http.MethodPost, "/products/{id}/inventory/adjustments", openapi.Operation{
+			ID:         "adjustStock",
+			Summary:    "Adjust a product's on-hand stock; the first adjustment starts tracking it",
+			Tags:       []string{"inventory"},
+			Parameters: []openapi.Parameter{id},
+			Request:    dto.AdjustStockRequest{},
+			Responses:  []openapi.Response{{Status: http.StatusOK, Body: dto.InventoryResponse{}}},
+			Errors:     []int{http.StatusBadRequest, http.StatusNotFound, http.StatusUnprocessableEntity},
+		}).
+		Operation(http.MethodGet, "/categories", openapi.Operation{
+			ID:        "listCategories",
+			Summary:   "List product categories by sort order, then name",
+			Tags:      []string{"categories"},
+			Responses: []openapi.Response{{Status: http.StatusOK, Body: []dto.CategoryResponse{}}},
+		}).
+		Operation(http.MethodPost, "/categories", openapi.Operation{
+			ID:        "createCategory",
+			Summary:   "Create a product category",
+			Tags:      []string{"categories"},
+			Request:   dto.CategoryRequest{},
+			Responses: []openapi.Response{{Status: http.StatusCreated, Body: dto.CategoryResponse{}}},
+			Errors:    []int{http.StatusBadRequest, http.StatusConflict},
+		}).
+		Operation(http.MethodGet, "/categories/{id}", openapi.Operation{
+			ID:         "getCategory",
+			Summary:    "Get a product category",
+			Tags:       []string{"categories"},
+			Parameters: []openapi.Parameter{id},
+			Responses:  []openapi.Response{{Status: http.StatusOK, Body: dto.CategoryResponse{}}},
+			Errors:     []int{http.StatusBadRequest, http.StatusNotFound},
+		}).
+		Operation(http.MethodPut, "/categories/{id}", openapi.Operation{
+			ID:         "updateCategory",
+			Summary:    "Update a product category; a new slug carries over to its products, promotions and tax rate",
+			Tags:       []string{"categories"},
+			Parameters: []openapi.Parameter{id},
+			Request:    dto.CategoryRequest{},
+			Responses:  []openapi.Response{{Status: http.StatusOK, Body: dto.CategoryResponse{}}},
+			Errors:     []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict},
+		}).
+		Operation(http.MethodDelete, "/categories/{id}", openapi.Operation{
+			ID:         "deleteCategory",
+			Summary:    "Delete a product category and its tax rate; fails while products or promotions use it",
+			Tags:       []string{"categories"},
+			Parameters: []openapi.Parameter{id},
+			Responses:  []openapi.Response{{Status: http.StatusNoContent}},
+			Errors:     []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict},
+		})
+}
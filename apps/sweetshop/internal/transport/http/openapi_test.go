@@ -0,0 +1,43 @@
+package http
+
+import (
+	"encoding/json"
+	"os"
+	"path"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type OpenAPISuite struct {
+	suite.Suite
+}
+
+func (s *OpenAPISuite) TestOpenAPIDocument_Builds() {
+	doc, err := OpenAPIDocument()
+	s.Require().NoError(err)
+
+	s.Assert().Contains(doc.Paths, "/products/{id}")
+	s.Assert().Contains(doc.Components.Schemas, "ErrorShape")
+	s.Assert().Contains(doc.Components.Schemas, "OrderResponse")
+}
+
+// The committed document is the contract client SDKs are generated from.
+// Regenerate it with `make openapi` after changing routes or DTOs.
+func (s *OpenAPISuite) TestOpenAPIDocument_MatchesCommittedSpec() {
+	_, filename, _, _ := runtime.Caller(0)
+	committed, err := os.ReadFile(path.Join(path.Dir(filename), "../../..", "resources", "openapi.json"))
+	s.Require().NoError(err)
+
+	doc, err := OpenAPIDocument()
+	s.Require().NoError(err)
+	generated, err := json.Marshal(doc)
+	s.Require().NoError(err)
+
+	s.Assert().JSONEq(string(committed), string(generated), "resources/openapi.json is stale; run `make openapi`")
+}
+
+func TestOpenAPISuite(t *testing.T) {
+	suite.Run(t, new(OpenAPISuite))
+}
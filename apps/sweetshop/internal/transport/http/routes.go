@@ -6,34 +6,129 @@ import (
 	"github.com/go-chi/chi/v5"
 	"go.uber.org/fx"
 
+	"github.com/bbsbb/go-edge/core/fx/httpserverfx"
 	"github.com/bbsbb/go-edge/core/fx/middlewarefx"
 	coremiddleware "github.com/bbsbb/go-edge/core/transport/http/middleware"
+	"github.com/bbsbb/go-edge/core/transport/http/openapi"
+	"github.com/bbsbb/go-edge/core/transport/http/ws"
 	"github.com/bbsbb/go-edge/sweetshop/internal/service"
 	"github.com/bbsbb/go-edge/sweetshop/internal/transport/http/handler"
 )
 
 type routeParams struct {
 	fx.In
-	Mux            *chi.Mux
-	ProductHandler *handler.ProductHandler
-	OrderHandler   *handler.OrderHandler
+	Mux              *chi.Mux
+	SettingsHandler  *handler.SettingsHandler
+	ProductHandler   *handler.ProductHandler
+	CategoryHandler  *handler.CategoryHandler
+	InventoryHandler *handler.InventoryHandler
+	OrderHandler     *handler.OrderHandler
+	PromotionHandler *handler.PromotionHandler
+	LiveHandler      *handler.LiveHandler
+	WebhookHandler   *handler.WebhookHandler
+	ReportHandler    *handler.ReportHandler
+	Logger           *slog.Logger
 }
 
-func registerRoutes(p routeParams) {
-	p.Mux.Route("/products", func(r chi.Router) {
-		r.Get("/", p.ProductHandler.List)
-		r.Post("/", p.ProductHandler.Create)
-		r.Get("/{id}", p.ProductHandler.Get)
-		r.Put("/{id}", p.ProductHandler.Update)
-		r.Delete("/{id}", p.ProductHandler.Delete)
+func registerRoutes(p routeParams) error {
+	registerAPIRoutes(p.Mux, p.SettingsHandler, p.ProductHandler, p.CategoryHandler, p.InventoryHandler, p.OrderHandler, p.PromotionHandler, p.LiveHandler, p.WebhookHandler, p.ReportHandler)
+
+	doc, err := OpenAPIDocument()
+	if err != nil {
+		return err
+	}
+	p.Mux.Get(openAPIPath, openapi.Handler(doc, p.Logger))
+	return nil
+}
+
+func registerAPIRoutes(
+	mux chi.Router,
+	settings *handler.SettingsHandler,
+	products *handler.ProductHandler,
+	categories *handler.CategoryHandler,
+	inventory *handler.InventoryHandler,
+	orders *handler.OrderHandler,
+	promotions *handler.PromotionHandler,
+	live *handler.LiveHandler,
+	webhooks *handler.WebhookHandler,
+	reports *handler.ReportHandler,
+) {
+	mux.Get("/settings", settings.Get)
+	mux.Put("/settings", settings.Update)
+	mux.Get("/settings/tax", settings.GetTax)
+	mux.Put("/settings/tax", settings.UpdateTax)
+
+	mux.Route("/products", func(r chi.Router) {
+		r.Get("/", products.List)
+		r.Post("/", products.Create)
+		r.Get("/search", products.Search)
+		r.Post("/import", products.Import)
+		r.Get("/export", products.Export)
+		r.Get("/{id}", products.Get)
+		r.Put("/{id}", products.Update)
+		r.Delete("/{id}", products.Delete)
+		r.Post("/{id}/restore", products.Restore)
+		r.Get("/{id}/options", products.Options)
+		r.Put("/{id}/options", products.ReplaceOptions)
+		r.Get("/{id}/inventory", inventory.Get)
+		r.Get("/{id}/inventory/adjustments", inventory.Adjustments)
+		r.Post("/{id}/inventory/adjustments", inventory.Adjust)
+	})
+
+	mux.Route("/categories", func(r chi.Router) {
+		r.Get("/", categories.List)
+		r.Post("/", categories.Create)
+		r.Get("/{id}", categories.Get)
+		r.Put("/{id}", categories.Update)
+		r.Delete("/{id}", categories.Delete)
 	})
 
-	p.Mux.Route("/orders", func(r chi.Router) {
-		r.Post("/", p.OrderHandler.Open)
-		r.Get("/{id}", p.OrderHandler.Get)
-		r.Post("/{id}/items", p.OrderHandler.AddItem)
-		r.Post("/{id}/close", p.OrderHandler.Close)
+	mux.Route("/orders", func(r chi.Router) {
+		r.Get("/", orders.List)
+		r.Post("/", orders.Open)
+		r.With(httpserverfx.NoTimeout).Get("/stream", orders.Stream)
+		r.With(httpserverfx.NoTimeout).Get("/live", live.Connect)
+		r.Get("/{id}", orders.Get)
+		r.Post("/{id}/items", orders.AddItem)
+		r.Patch("/{id}/items/{itemID}", orders.UpdateItem)
+		r.Delete("/{id}/items/{itemID}", orders.RemoveItem)
+		r.Get("/{id}/history", orders.History)
+		r.Post("/{id}/submit", orders.Submit)
+		r.Post("/{id}/pay", orders.Pay)
+		r.Post("/{id}/fulfil", orders.Fulfil)
+		r.Post("/{id}/cancel", orders.Cancel)
+		r.Post("/{id}/refund", orders.Refund)
 	})
+
+	mux.Route("/promotions", func(r chi.Router) {
+		r.Get("/", promotions.List)
+		r.Post("/", promotions.Create)
+		r.Get("/{id}", promotions.Get)
+		r.Put("/{id}", promotions.Update)
+		r.Delete("/{id}", promotions.Delete)
+	})
+
+	mux.Route("/webhooks", func(r chi.Router) {
+		r.Get("/", webhooks.List)
+		r.Post("/", webhooks.Create)
+		r.Get("/{id}", webhooks.Get)
+		r.Put("/{id}", webhooks.Update)
+		r.Delete("/{id}", webhooks.Delete)
+		r.Get("/{id}/deliveries", webhooks.Deliveries)
+	})
+
+	mux.Route("/reports", func(r chi.Router) {
+		r.Get("/sales", reports.Sales)
+		r.Get("/top-products", reports.TopProducts)
+	})
+}
+
+// provideHub caps live messages at the request body limit and disconnects
+// live clients on shutdown, which http.Server.Shutdown does not do.
+func provideHub(lc fx.Lifecycle, cfg *middlewarefx.Configuration, logger *slog.Logger) *ws.Hub {
+	hub := ws.NewHub(ws.Options{ReadLimit: cfg.MaxBytes.Limit()}, logger)
+	lc.Append(fx.StopHook(hub.Close))
+	return hub
 }
 
 type orgMiddlewareResult struct {
@@ -46,7 +141,7 @@ func provideOrganizationMiddleware(loader coremiddleware.OrganizationLoader, log
 		Middleware: middlewarefx.Middleware{
 			Name: "organization",
 			Handler: coremiddleware.WithOrganization(coremiddleware.WithOrganizationConfig{
-				SkipPaths: []string{"/healthz", "/readyz"},
+				SkipPaths: []string{"/healthz", "/readyz", openAPIPath},
 				Logger:    logger,
 				Loader:    loader,
 			}),
@@ -57,11 +152,27 @@ func provideOrganizationMiddleware(loader coremiddleware.OrganizationLoader, log
 var RouteModule = fx.Module(
 	"sweetshop/routes",
 	fx.Provide(
+		service.NewSettingsService,
 		service.NewProductService,
+		service.NewCategoryService,
+		service.NewInventoryService,
 		service.NewOrderService,
+		service.NewPromotionService,
+		service.NewOrderEventService,
+		service.NewWebhookService,
+		service.NewReportService,
 		service.NewRegistry,
+		handler.NewSettingsHandler,
 		handler.NewProductHandler,
+		handler.NewCategoryHandler,
+		handler.NewInventoryHandler,
 		handler.NewOrderHandler,
+		handler.NewPromotionHandler,
+		handler.NewLiveHandler,
+		handler.NewWebhookHandler,
+		handler.NewReportHandler,
+		handler.NewOrderBroadcaster,
+		provideHub,
 		provideOrganizationMiddleware,
 	),
 	fx.Invoke(registerRoutes),
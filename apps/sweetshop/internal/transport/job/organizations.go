@@ -0,0 +1,36 @@
+package job
+
+import (
+	"context"
+	"log/slog"
+
+	coredomain "github.com/bbsbb/go-edge/core/domain"
+	"github.com/bbsbb/go-edge/sweetshop/internal/domain"
+)
+
+// forEachOrganization calls fn once per organization, with ctx carrying that
+// organization so fn's work runs inside its RLS transaction, and returns how
+// many calls succeeded. A failure for one organization is logged as failed and
+// does not stop the others; only listing the organizations fails the run.
+func forEachOrganization(
+	ctx context.Context,
+	orgs domain.OrganizationRepository,
+	logger *slog.Logger,
+	failed string,
+	fn func(ctx context.Context) error,
+) (int, error) {
+	list, err := orgs.List(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var succeeded int
+	for _, org := range list {
+		if err := fn(coredomain.ContextWithOrganization(ctx, org)); err != nil {
+			logger.ErrorContext(ctx, failed, "error", err, "organization_id", org.ID)
+			continue
+		}
+		succeeded++
+	}
+	return succeeded, nil
+}
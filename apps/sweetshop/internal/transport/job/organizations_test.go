@@ -0,0 +1,84 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+
+	coredomain "github.com/bbsbb/go-edge/core/domain"
+	coretesting "github.com/bbsbb/go-edge/core/testing"
+	"github.com/bbsbb/go-edge/sweetshop/internal/domain"
+)
+
+type fakeOrganizations struct {
+	orgs []*coredomain.Organization
+	err  error
+}
+
+func (f *fakeOrganizations) FindByID(context.Context, uuid.UUID) (*coredomain.Organization, error) {
+	return nil, coredomain.ErrNotFound
+}
+
+func (f *fakeOrganizations) FindBySlug(context.Context, string) (*coredomain.Organization, error) {
+	return nil, coredomain.ErrNotFound
+}
+
+func (f *fakeOrganizations) Create(context.Context, *coredomain.Organization) error { return nil }
+
+func (f *fakeOrganizations) List(context.Context) ([]*coredomain.Organization, error) {
+	return f.orgs, f.err
+}
+
+func (f *fakeOrganizations) FindSettings(context.Context, uuid.UUID) (*domain.OrganizationSettings, error) {
+	return nil, coredomain.ErrNotFound
+}
+
+func (f *fakeOrganizations) UpdateSettings(context.Context, *domain.OrganizationSettings) error {
+	return nil
+}
+
+type ForEachOrganizationSuite struct {
+	suite.Suite
+}
+
+func (s *ForEachOrganizationSuite) TestRunsEachOrganizationInItsOwnContext() {
+	org1 := &coredomain.Organization{ID: uuid.Must(uuid.NewV7()), Slug: "one"}
+	org2 := &coredomain.Organization{ID: uuid.Must(uuid.NewV7()), Slug: "two"}
+	org3 := &coredomain.Organization{ID: uuid.Must(uuid.NewV7()), Slug: "three"}
+	orgs := &fakeOrganizations{orgs: []*coredomain.Organization{org1, org2, org3}}
+	var called []uuid.UUID
+
+	n, err := forEachOrganization(context.Background(), orgs, coretesting.NewNoopLogger(), "failed", func(ctx context.Context) error {
+		org, err := coredomain.OrganizationFromContext(ctx)
+		s.Require().NoError(err)
+		called = append(called, org.ID)
+		if org.ID == org2.ID {
+			return errors.New("failed")
+		}
+		return nil
+	})
+
+	s.Require().NoError(err)
+	s.Assert().Equal(2, n, "a failed organization is not counted")
+	s.Assert().Equal([]uuid.UUID{org1.ID, org2.ID, org3.ID}, called, "a failure does not stop the others")
+}
+
+func (s *ForEachOrganizationSuite) TestOrganizationListError() {
+	called := false
+
+	_, err := forEachOrganization(context.Background(), &fakeOrganizations{err: errors.New("db down")}, coretesting.NewNoopLogger(), "failed",
+		func(context.Context) error {
+			called = true
+			return nil
+		})
+
+	s.Require().Error(err)
+	s.Assert().False(called)
+}
+
+func TestForEachOrganizationSuite(t *testing.T) {
+	suite.Run(t, new(ForEachOrganizationSuite))
+}
@@ -0,0 +1,116 @@
+// Package job provides background jobs that drive application services on a schedule.
+package job
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"go.uber.org/fx"
+
+	"github.com/bbsbb/go-edge/sweetshop/internal/config"
+	"github.com/bbsbb/go-edge/sweetshop/internal/domain"
+	"github.com/bbsbb/go-edge/sweetshop/internal/service"
+)
+
+// ProductPurger removes soft-deleted products for the organization in context.
+type ProductPurger interface {
+	PurgeDeleted(ctx context.Context, deletedBefore time.Time) (int64, error)
+}
+
+// ProductPurgeJob periodically hard-deletes soft-deleted products past their
+// retention period. It runs once per organization so every purge executes
+// inside that organization's RLS transaction.
+type ProductPurgeJob struct {
+	orgs      domain.OrganizationRepository
+	purger    ProductPurger
+	retention time.Duration
+	logger    *slog.Logger
+	now       func() time.Time
+}
+
+func NewProductPurgeJob(orgs domain.OrganizationRepository, purger ProductPurger, retention time.Duration, logger *slog.Logger) *ProductPurgeJob {
+	return &ProductPurgeJob{orgs: orgs, purger: purger, retention: retention, logger: logger, now: time.Now}
+}
+
+// RunOnce purges every organization and returns the total number of removed products.
+func (j *ProductPurgeJob) RunOnce(ctx context.Context) (int64, error) {
+	cutoff := j.now().Add(-j.retention)
+	var total int64
+	_, err := forEachOrganization(ctx, j.orgs, j.logger, "product purge failed", func(ctx context.Context) error {
+		n, err := j.purger.PurgeDeleted(ctx, cutoff)
+		if err != nil {
+			return err
+		}
+		total += n
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
+func (j *ProductPurgeJob) run(ctx context.Context) {
+	n, err := j.RunOnce(ctx)
+	if err != nil {
+		j.logger.ErrorContext(ctx, "product purge run failed", "error", err)
+		return
+	}
+	j.logger.InfoContext(ctx, "product purge run completed", "purged", n)
+}
+
+type purgeParams struct {
+	fx.In
+	Lifecycle fx.Lifecycle
+	Config    *config.AppConfiguration
+	Orgs      domain.OrganizationRepository
+	Services  *service.Registry
+	Logger    *slog.Logger
+}
+
+func registerProductPurge(p purgeParams) {
+	cfg := p.Config.ProductPurgeConfiguration()
+	if !cfg.Enabled {
+		return
+	}
+
+	job := NewProductPurgeJob(p.Orgs, p.Services.Products, cfg.RetentionOrDefault(), p.Logger)
+	schedule(p.Lifecycle, cfg.IntervalOrDefault(), job.run)
+}
+
+// schedule calls run every interval while the application is running. Stop
+// cancels the context passed to run and waits for it to return.
+func schedule(lc fx.Lifecycle, interval time.Duration, run func(ctx context.Context)) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+
+	lc.Append(fx.Hook{
+		OnStart: func(_ context.Context) error {
+			wg.Go(func() {
+				ticker := time.NewTicker(interval)
+				defer ticker.Stop()
+				for {
+					select {
+					case <-ctx.Done():
+						return
+					case <-ticker.C:
+						run(ctx)
+					}
+				}
+			})
+			return nil
+		},
+		OnStop: func(_ context.Context) error {
+			cancel()
+			wg.Wait()
+			return nil
+		},
+	})
+}
+
+var Module = fx.Module(
+	"sweetshop/jobs",
+	fx.Invoke(registerProductPurge, registerWebhookDelivery, registerSalesReport),
+)
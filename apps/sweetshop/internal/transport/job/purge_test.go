@@ -0,0 +1,61 @@
+package job
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+
+	coredomain "github.com/bbsbb/go-edge/core/domain"
+	coretesting "github.com/bbsbb/go-edge/core/testing"
+)
+
+type purgeCall struct {
+	orgID         uuid.UUID
+	deletedBefore time.Time
+}
+
+type fakePurger struct {
+	calls []purgeCall
+}
+
+func (f *fakePurger) PurgeDeleted(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	org, err := coredomain.OrganizationFromContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+	f.calls = append(f.calls, purgeCall{orgID: org.ID, deletedBefore: deletedBefore})
+	return 2, nil
+}
+
+type ProductPurgeJobSuite struct {
+	suite.Suite
+}
+
+func (s *ProductPurgeJobSuite) newJob(orgs *fakeOrganizations, purger *fakePurger, now time.Time) *ProductPurgeJob {
+	job := NewProductPurgeJob(orgs, purger, 24*time.Hour, coretesting.NewNoopLogger())
+	job.now = func() time.Time { return now }
+	return job
+}
+
+func (s *ProductPurgeJobSuite) TestRunOnce_PurgesPastRetentionAndSumsRemoved() {
+	org1 := &coredomain.Organization{ID: uuid.Must(uuid.NewV7()), Slug: "one"}
+	org2 := &coredomain.Organization{ID: uuid.Must(uuid.NewV7()), Slug: "two"}
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	purger := &fakePurger{}
+
+	total, err := s.newJob(&fakeOrganizations{orgs: []*coredomain.Organization{org1, org2}}, purger, now).RunOnce(context.Background())
+
+	s.Require().NoError(err)
+	s.Assert().Equal(int64(4), total)
+	s.Require().Len(purger.calls, 2)
+	s.Assert().Equal(org1.ID, purger.calls[0].orgID)
+	s.Assert().Equal(org2.ID, purger.calls[1].orgID)
+	s.Assert().Equal(now.Add(-24*time.Hour), purger.calls[0].deletedBefore)
+}
+
+func TestProductPurgeJobSuite(t *testing.T) {
+	suite.Run(t, new(ProductPurgeJobSuite))
+}
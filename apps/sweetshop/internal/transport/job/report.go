@@ -0,0 +1,68 @@
+package job
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"go.uber.org/fx"
+
+	"github.com/bbsbb/go-edge/sweetshop/internal/config"
+	"github.com/bbsbb/go-edge/sweetshop/internal/domain"
+	"github.com/bbsbb/go-edge/sweetshop/internal/service"
+)
+
+// SalesReportRefresher refreshes the daily sales of the organization in context.
+type SalesReportRefresher interface {
+	Refresh(ctx context.Context, now time.Time) error
+}
+
+// SalesReportJob keeps every organization's daily sales aggregates up to
+// date. Each refresh recomputes the days since the previous one inside the
+// organization's RLS transaction, so a run that fails is made up by the next.
+type SalesReportJob struct {
+	orgs      domain.OrganizationRepository
+	refresher SalesReportRefresher
+	logger    *slog.Logger
+	now       func() time.Time
+}
+
+func NewSalesReportJob(orgs domain.OrganizationRepository, refresher SalesReportRefresher, logger *slog.Logger) *SalesReportJob {
+	return &SalesReportJob{orgs: orgs, refresher: refresher, logger: logger, now: time.Now}
+}
+
+// RunOnce refreshes every organization and returns how many were refreshed.
+func (j *SalesReportJob) RunOnce(ctx context.Context) (int, error) {
+	now := j.now()
+	return forEachOrganization(ctx, j.orgs, j.logger, "sales report refresh failed", func(ctx context.Context) error {
+		return j.refresher.Refresh(ctx, now)
+	})
+}
+
+func (j *SalesReportJob) run(ctx context.Context) {
+	n, err := j.RunOnce(ctx)
+	if err != nil {
+		j.logger.ErrorContext(ctx, "sales report run failed", "error", err)
+		return
+	}
+	j.logger.DebugContext(ctx, "sales report run completed", "refreshed", n)
+}
+
+type salesReportParams struct {
+	fx.In
+	Lifecycle fx.Lifecycle
+	Config    *config.AppConfiguration
+	Orgs      domain.OrganizationRepository
+	Services  *service.Registry
+	Logger    *slog.Logger
+}
+
+func registerSalesReport(p salesReportParams) {
+	cfg := p.Config.SalesReportConfiguration()
+	if !cfg.Enabled {
+		return
+	}
+
+	job := NewSalesReportJob(p.Orgs, p.Services.Reports, p.Logger)
+	schedule(p.Lifecycle, cfg.IntervalOrDefault(), job.run)
+}
@@ -0,0 +1,58 @@
+package job
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+
+	coredomain "github.com/bbsbb/go-edge/core/domain"
+	coretesting "github.com/bbsbb/go-edge/core/testing"
+)
+
+type refreshCall struct {
+	orgID uuid.UUID
+	now   time.Time
+}
+
+type fakeRefresher struct {
+	calls []refreshCall
+}
+
+func (f *fakeRefresher) Refresh(ctx context.Context, now time.Time) error {
+	org, err := coredomain.OrganizationFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	f.calls = append(f.calls, refreshCall{orgID: org.ID, now: now})
+	return nil
+}
+
+type SalesReportJobSuite struct {
+	suite.Suite
+}
+
+func (s *SalesReportJobSuite) newJob(orgs *fakeOrganizations, refresher *fakeRefresher, now time.Time) *SalesReportJob {
+	job := NewSalesReportJob(orgs, refresher, coretesting.NewNoopLogger())
+	job.now = func() time.Time { return now }
+	return job
+}
+
+func (s *SalesReportJobSuite) TestRunOnce_RefreshesEveryOrganizationAtTheSameTime() {
+	org1 := &coredomain.Organization{ID: uuid.Must(uuid.NewV7()), Slug: "one"}
+	org2 := &coredomain.Organization{ID: uuid.Must(uuid.NewV7()), Slug: "two"}
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	refresher := &fakeRefresher{}
+
+	n, err := s.newJob(&fakeOrganizations{orgs: []*coredomain.Organization{org1, org2}}, refresher, now).RunOnce(context.Background())
+
+	s.Require().NoError(err)
+	s.Assert().Equal(2, n)
+	s.Assert().Equal([]refreshCall{{orgID: org1.ID, now: now}, {orgID: org2.ID, now: now}}, refresher.calls)
+}
+
+func TestSalesReportJobSuite(t *testing.T) {
+	suite.Run(t, new(SalesReportJobSuite))
+}
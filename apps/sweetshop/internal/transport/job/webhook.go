@@ -0,0 +1,78 @@
+package job
+
+import (
+	"context"
+	"log/slog"
+
+	"go.uber.org/fx"
+
+	"github.com/bbsbb/go-edge/sweetshop/internal/config"
+	"github.com/bbsbb/go-edge/sweetshop/internal/domain"
+	"github.com/bbsbb/go-edge/sweetshop/internal/service"
+)
+
+// WebhookDeliverer sends the due webhook deliveries of the organization in context.
+type WebhookDeliverer interface {
+	DeliverDue(ctx context.Context) (int, error)
+}
+
+// WebhookDeliveryJob polls every organization's webhook queue. Deliveries are
+// claimed and recorded inside the organization's RLS transactions, and several
+// instances can run side by side since claims skip locked rows.
+type WebhookDeliveryJob struct {
+	orgs      domain.OrganizationRepository
+	deliverer WebhookDeliverer
+	logger    *slog.Logger
+}
+
+func NewWebhookDeliveryJob(orgs domain.OrganizationRepository, deliverer WebhookDeliverer, logger *slog.Logger) *WebhookDeliveryJob {
+	return &WebhookDeliveryJob{orgs: orgs, deliverer: deliverer, logger: logger}
+}
+
+// RunOnce delivers for every organization and returns the number of
+// successful deliveries.
+func (j *WebhookDeliveryJob) RunOnce(ctx context.Context) (int, error) {
+	var total int
+	_, err := forEachOrganization(ctx, j.orgs, j.logger, "webhook delivery failed", func(ctx context.Context) error {
+		n, err := j.deliverer.DeliverDue(ctx)
+		if err != nil {
+			return err
+		}
+		total += n
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
+func (j *WebhookDeliveryJob) run(ctx context.Context) {
+	n, err := j.RunOnce(ctx)
+	if err != nil {
+		j.logger.ErrorContext(ctx, "webhook delivery run failed", "error", err)
+		return
+	}
+	if n > 0 {
+		j.logger.DebugContext(ctx, "webhook delivery run completed", "delivered", n)
+	}
+}
+
+type webhookParams struct {
+	fx.In
+	Lifecycle fx.Lifecycle
+	Config    *config.AppConfiguration
+	Orgs      domain.OrganizationRepository
+	Services  *service.Registry
+	Logger    *slog.Logger
+}
+
+func registerWebhookDelivery(p webhookParams) {
+	cfg := p.Config.WebhookConfiguration()
+	if !cfg.Enabled {
+		return
+	}
+
+	job := NewWebhookDeliveryJob(p.Orgs, p.Services.Webhooks, p.Logger)
+	schedule(p.Lifecycle, cfg.PollIntervalOrDefault(), job.run)
+}
@@ -0,0 +1,46 @@
+package job
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+
+	coredomain "github.com/bbsbb/go-edge/core/domain"
+	coretesting "github.com/bbsbb/go-edge/core/testing"
+)
+
+type fakeDeliverer struct {
+	orgIDs []uuid.UUID
+}
+
+func (f *fakeDeliverer) DeliverDue(ctx context.Context) (int, error) {
+	org, err := coredomain.OrganizationFromContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+	f.orgIDs = append(f.orgIDs, org.ID)
+	return 3, nil
+}
+
+type WebhookDeliveryJobSuite struct {
+	suite.Suite
+}
+
+func (s *WebhookDeliveryJobSuite) TestRunOnce_SumsDeliveries() {
+	org1 := &coredomain.Organization{ID: uuid.Must(uuid.NewV7()), Slug: "one"}
+	org2 := &coredomain.Organization{ID: uuid.Must(uuid.NewV7()), Slug: "two"}
+	deliverer := &fakeDeliverer{}
+	job := NewWebhookDeliveryJob(&fakeOrganizations{orgs: []*coredomain.Organization{org1, org2}}, deliverer, coretesting.NewNoopLogger())
+
+	total, err := job.RunOnce(context.Background())
+
+	s.Require().NoError(err)
+	s.Assert().Equal(6, total)
+	s.Assert().Equal([]uuid.UUID{org1.ID, org2.ID}, deliverer.orgIDs)
+}
+
+func TestWebhookDeliveryJobSuite(t *testing.T) {
+	suite.Run(t, new(WebhookDeliveryJobSuite))
+}
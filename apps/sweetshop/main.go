@@ -24,8 +24,14 @@ func main() {
 		err = cmd.RunServer(configPath)
 	case "migrate":
 		err = runMigrate(configPath)
+	case "openapi":
+		outPath := ""
+		if len(os.Args) > 2 {
+			outPath = os.Args[2]
+		}
+		err = cmd.RunOpenAPI(outPath)
 	default:
-		err = fmt.Errorf("unknown command: %s (expected: server, migrate)", command)
+		err = fmt.Errorf("unknown command: %s (expected: server, migrate, openapi)", command)
 	}
 
 	if err != nil {
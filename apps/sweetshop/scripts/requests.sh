@@ -12,7 +12,7 @@ header=(-H "X-Organization-Slug: $ORG" -H "Content-Type: application/json")
 echo "=== Create product ==="
 product=$(curl -s -w "\n%{http_code}" -X POST "$BASE_URL/products" \
   "${header[@]}" \
-  -d '{"name":"Chocolate Cake","category":"ice_cream","price_cents":1500}')
+  -d '{"name":"Chocolate Cake","category":"ice_cream","price":{"amount":1500,"currency":"EUR"}}')
 body=$(echo "$product" | sed '$d')
 code=$(echo "$product" | tail -1)
 echo "Status: $code"
@@ -23,7 +23,7 @@ echo ""
 echo "=== Create second product (for delete test) ==="
 product2=$(curl -s -X POST "$BASE_URL/products" \
   "${header[@]}" \
-  -d '{"name":"Marshmallow Puff","category":"marshmallow","price_cents":500}')
+  -d '{"name":"Marshmallow Puff","category":"marshmallow","price":{"amount":500,"currency":"EUR"}}')
 echo "$product2" | jq .
 product2_id=$(echo "$product2" | jq -r '.id')
 
@@ -39,7 +39,7 @@ echo ""
 echo "=== Update product ==="
 curl -s -X PUT "$BASE_URL/products/$product_id" \
   "${header[@]}" \
-  -d '{"name":"Chocolate Cake Deluxe","category":"ice_cream","price_cents":1800}' | jq .
+  -d '{"name":"Chocolate Cake Deluxe","category":"ice_cream","price":{"amount":1800,"currency":"EUR"}}' | jq .
 
 echo ""
 echo "=== Open order ==="
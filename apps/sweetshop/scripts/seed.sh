@@ -1,7 +1,8 @@
 #!/usr/bin/env bash
 set -euo pipefail
 
-# Seeds a development organization into the database.
+# Seeds a development organization and its product categories into the
+# database.
 # Usage: ./scripts/seed.sh
 
 CONTAINER="${CONTAINER:-development-postgres-1}"
@@ -10,6 +11,11 @@ docker exec "$CONTAINER" psql -U root -d app_sweetshop -v ON_ERROR_STOP=1 -c "
 INSERT INTO app_sweetshop.organizations (id, name, slug)
 VALUES ('01961a1a-0000-7000-8000-000000000001', 'Dev Shop', 'dev-shop')
 ON CONFLICT (slug) DO NOTHING;
+
+INSERT INTO app_sweetshop.categories (id, organization_id, slug, name, sort_order)
+VALUES ('01961a1a-0000-7000-8000-000000000011', '01961a1a-0000-7000-8000-000000000001', 'ice_cream', 'Ice cream', 0),
+       ('01961a1a-0000-7000-8000-000000000012', '01961a1a-0000-7000-8000-000000000001', 'marshmallow', 'Marshmallow', 1)
+ON CONFLICT (organization_id, slug) DO NOTHING;
 "
 
 echo "Seeded organization: dev-shop"
@@ -2,8 +2,11 @@ package configuration
 
 import (
 	"fmt"
+	"reflect"
 
 	"github.com/go-playground/validator/v10"
+
+	"github.com/bbsbb/go-edge/core/datatype"
 )
 
 var Validate = validator.New(validator.WithRequiredStructEnabled())
@@ -12,6 +15,12 @@ func init() {
 	if err := Validate.RegisterValidation("stringenum", validateStringEnum); err != nil {
 		panic(fmt.Sprintf("configuration: register stringenum validator: %v", err))
 	}
+	// Money fields validate as their amount, so `validate:"gt=0"` bounds a price.
+	Validate.RegisterCustomTypeFunc(moneyAmount, datatype.Money{})
+}
+
+func moneyAmount(v reflect.Value) any {
+	return v.Interface().(datatype.Money).Amount()
 }
 
 func validateStringEnum(fl validator.FieldLevel) bool {
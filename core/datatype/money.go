@@ -0,0 +1,313 @@
+package datatype
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+var (
+	ErrInvalidCurrency  = errors.New("datatype: invalid currency")
+	ErrCurrencyMismatch = errors.New("datatype: currency mismatch")
+	ErrMoneyOverflow    = errors.New("datatype: money overflow")
+	ErrInvalidRatios    = errors.New("datatype: invalid allocation ratios")
+)
+
+// Money is an amount in the minor unit of its currency (cents for EUR, yen
+// for JPY). Arithmetic is checked: operands must share a currency and results
+// that do not fit in an int64 fail with ErrMoneyOverflow rather than wrap.
+//
+// The zero value has no currency and is only useful as a placeholder; build
+// amounts with NewMoney.
+type Money struct {
+	amount   int64
+	currency Currency
+}
+
+// NewMoney returns amount minor units of currency.
+func NewMoney(amount int64, currency Currency) (Money, error) {
+	if !currency.IsValid() {
+		return Money{}, fmt.Errorf("%w: %q", ErrInvalidCurrency, currency)
+	}
+	return Money{amount: amount, currency: currency}, nil
+}
+
+// MustMoney is like NewMoney but panics on an invalid currency. It is meant
+// for constants and tests.
+func MustMoney(amount int64, currency Currency) Money {
+	m, err := NewMoney(amount, currency)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+// Amount is the amount in minor units.
+func (m Money) Amount() int64 {
+	return m.amount
+}
+
+func (m Money) Currency() Currency {
+	return m.currency
+}
+
+func (m Money) IsZero() bool {
+	return m.amount == 0
+}
+
+func (m Money) IsPositive() bool {
+	return m.amount > 0
+}
+
+func (m Money) IsNegative() bool {
+	return m.amount < 0
+}
+
+// Zero returns no money in m's currency.
+func (m Money) Zero() Money {
+	return Money{currency: m.currency}
+}
+
+// Cmp compares m and o, returning -1, 0 or +1.
+func (m Money) Cmp(o Money) (int, error) {
+	if err := m.sameCurrency(o); err != nil {
+		return 0, err
+	}
+	switch {
+	case m.amount < o.amount:
+		return -1, nil
+	case m.amount > o.amount:
+		return 1, nil
+	default:
+		return 0, nil
+	}
+}
+
+func (m Money) Add(o Money) (Money, error) {
+	if err := m.sameCurrency(o); err != nil {
+		return Money{}, err
+	}
+	if (o.amount > 0 && m.amount > math.MaxInt64-o.amount) || (o.amount < 0 && m.amount < math.MinInt64-o.amount) {
+		return Money{}, fmt.Errorf("%w: %s + %s", ErrMoneyOverflow, m, o)
+	}
+	return Money{amount: m.amount + o.amount, currency: m.currency}, nil
+}
+
+func (m Money) Sub(o Money) (Money, error) {
+	if err := m.sameCurrency(o); err != nil {
+		return Money{}, err
+	}
+	if (o.amount < 0 && m.amount > math.MaxInt64+o.amount) || (o.amount > 0 && m.amount < math.MinInt64+o.amount) {
+		return Money{}, fmt.Errorf("%w: %s - %s", ErrMoneyOverflow, m, o)
+	}
+	return Money{amount: m.amount - o.amount, currency: m.currency}, nil
+}
+
+// Mul multiplies m by n, e.g. a unit price by a quantity.
+func (m Money) Mul(n int64) (Money, error) {
+	if m.amount == 0 || n == 0 {
+		return m.Zero(), nil
+	}
+	product := m.amount * n
+	if product/n != m.amount || (m.amount == -1 && n == math.MinInt64) || (n == -1 && m.amount == math.MinInt64) {
+		return Money{}, fmt.Errorf("%w: %s * %d", ErrMoneyOverflow, m, n)
+	}
+	return Money{amount: product, currency: m.currency}, nil
+}
+
+// Allocate splits m into parts proportional to ratios without losing a minor
+// unit: each part is rounded toward zero and the remainder is handed out one
+// unit at a time to the parts with a non-zero ratio, in order. Ratios must be
+// non-negative and at least one must be positive.
+func (m Money) Allocate(ratios ...int64) ([]Money, error) {
+	total := new(big.Int)
+	for _, r := range ratios {
+		if r < 0 {
+			return nil, fmt.Errorf("%w: negative ratio %d", ErrInvalidRatios, r)
+		}
+		total.Add(total, big.NewInt(r))
+	}
+	if total.Sign() == 0 {
+		return nil, fmt.Errorf("%w: ratios sum to zero", ErrInvalidRatios)
+	}
+
+	// Quo truncates toward zero, so for a negative amount every share and the
+	// remainder are negative too and the remainder is handed out as -1s.
+	amount := big.NewInt(m.amount)
+	unit := int64(1)
+	if m.amount < 0 {
+		unit = -1
+	}
+
+	parts := make([]Money, len(ratios))
+	remainder := new(big.Int).Set(amount)
+	share := new(big.Int)
+	for i, r := range ratios {
+		share.Mul(amount, big.NewInt(r))
+		share.Quo(share, total)
+		remainder.Sub(remainder, share)
+		parts[i] = Money{amount: share.Int64(), currency: m.currency}
+	}
+	for i, left := 0, remainder.Int64(); left != 0; i++ {
+		if ratios[i] == 0 {
+			continue
+		}
+		parts[i].amount += unit
+		left -= unit
+	}
+	return parts, nil
+}
+
+// Split divides m into n parts that differ by at most one minor unit, the
+// larger ones first.
+func (m Money) Split(n int) ([]Money, error) {
+	if n <= 0 {
+		return nil, fmt.Errorf("%w: cannot split into %d parts", ErrInvalidRatios, n)
+	}
+	ratios := make([]int64, n)
+	for i := range ratios {
+		ratios[i] = 1
+	}
+	return m.Allocate(ratios...)
+}
+
+// String formats m in major units followed by its currency, e.g. "12.34 EUR".
+func (m Money) String() string {
+	digits := m.currency.MinorUnits()
+	abs := strconv.FormatUint(absUint64(m.amount), 10)
+	sign := ""
+	if m.amount < 0 {
+		sign = "-"
+	}
+	if digits > 0 {
+		if len(abs) <= digits {
+			abs = strings.Repeat("0", digits-len(abs)+1) + abs
+		}
+		abs = abs[:len(abs)-digits] + "." + abs[len(abs)-digits:]
+	}
+	return sign + abs + " " + string(m.currency)
+}
+
+// moneyFields is the encoded form of Money in JSON, CBOR and MessagePack.
+type moneyFields struct {
+	Amount   *int64   `json:"amount" msgpack:"amount"`
+	Currency Currency `json:"currency" msgpack:"currency"`
+}
+
+func (m Money) fields() moneyFields {
+	return moneyFields{Amount: &m.amount, Currency: m.currency}
+}
+
+func (m *Money) setFields(v moneyFields) error {
+	if v.Amount == nil {
+		return errors.New("datatype: money amount is required")
+	}
+	parsed, err := NewMoney(*v.Amount, v.Currency)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// MarshalJSON encodes m as {"amount": <minor units>, "currency": "<code>"}.
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(m.fields())
+}
+
+func (m *Money) UnmarshalJSON(data []byte) error {
+	var v moneyFields
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	return m.setFields(v)
+}
+
+// MarshalCBOR encodes m as a map with the same keys as its JSON form.
+func (m Money) MarshalCBOR() ([]byte, error) {
+	return cbor.Marshal(m.fields())
+}
+
+func (m *Money) UnmarshalCBOR(data []byte) error {
+	var v moneyFields
+	if err := cbor.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	return m.setFields(v)
+}
+
+// EncodeMsgpack encodes m as a map with the same keys as its JSON form.
+func (m Money) EncodeMsgpack(enc *msgpack.Encoder) error {
+	return enc.Encode(m.fields())
+}
+
+func (m *Money) DecodeMsgpack(dec *msgpack.Decoder) error {
+	var v moneyFields
+	if err := dec.Decode(&v); err != nil {
+		return err
+	}
+	return m.setFields(v)
+}
+
+// Value encodes m as a PostgreSQL composite literal "(amount,currency)", the
+// text form of a composite type declared as (amount BIGINT, currency TEXT).
+func (m Money) Value() (driver.Value, error) {
+	if !m.currency.IsValid() {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidCurrency, m.currency)
+	}
+	return "(" + strconv.FormatInt(m.amount, 10) + "," + string(m.currency) + ")", nil
+}
+
+// Scan decodes the composite literal written by Value.
+func (m *Money) Scan(value any) error {
+	var s string
+	switch v := value.(type) {
+	case string:
+		s = v
+	case []byte:
+		s = string(v)
+	default:
+		return fmt.Errorf("invalid value type '%T' for Money scan", value)
+	}
+
+	fields, ok := strings.CutPrefix(strings.TrimSpace(s), "(")
+	if ok {
+		fields, ok = strings.CutSuffix(fields, ")")
+	}
+	amount, currency, found := strings.Cut(fields, ",")
+	if !ok || !found {
+		return fmt.Errorf("invalid Money literal %q", s)
+	}
+	n, err := strconv.ParseInt(strings.Trim(amount, `" `), 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid Money amount in %q: %w", s, err)
+	}
+	parsed, err := NewMoney(n, Currency(strings.Trim(currency, `" `)))
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+func (m Money) sameCurrency(o Money) error {
+	if m.currency != o.currency {
+		return fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.currency, o.currency)
+	}
+	return nil
+}
+
+func absUint64(n int64) uint64 {
+	if n < 0 {
+		return uint64(-(n + 1)) + 1
+	}
+	return uint64(n)
+}
@@ -0,0 +1,238 @@
+package datatype
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/stretchr/testify/suite"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+type MoneySuite struct {
+	suite.Suite
+}
+
+func (s *MoneySuite) amounts(parts []Money) []int64 {
+	out := make([]int64, len(parts))
+	for i, p := range parts {
+		out[i] = p.Amount()
+	}
+	return out
+}
+
+func (s *MoneySuite) TestCurrency() {
+	s.Assert().True(Currency("EUR").IsValid())
+	s.Assert().False(Currency("eur").IsValid())
+	s.Assert().False(Currency("XYZ").IsValid())
+	s.Assert().Equal(2, Currency("EUR").MinorUnits())
+	s.Assert().Equal(0, Currency("JPY").MinorUnits())
+	s.Assert().Equal(3, Currency("KWD").MinorUnits())
+
+	_, err := NewMoney(100, "XYZ")
+	s.Assert().ErrorIs(err, ErrInvalidCurrency)
+}
+
+func (s *MoneySuite) TestArithmetic() {
+	a := MustMoney(150, "EUR")
+	b := MustMoney(75, "EUR")
+
+	sum, err := a.Add(b)
+	s.Require().NoError(err)
+	s.Assert().Equal(MustMoney(225, "EUR"), sum)
+
+	diff, err := b.Sub(a)
+	s.Require().NoError(err)
+	s.Assert().Equal(MustMoney(-75, "EUR"), diff)
+
+	product, err := a.Mul(3)
+	s.Require().NoError(err)
+	s.Assert().Equal(MustMoney(450, "EUR"), product)
+
+	cmp, err := a.Cmp(b)
+	s.Require().NoError(err)
+	s.Assert().Equal(1, cmp)
+}
+
+func (s *MoneySuite) TestArithmetic_Errors() {
+	eur := MustMoney(100, "EUR")
+	usd := MustMoney(100, "USD")
+	largest := MustMoney(math.MaxInt64, "EUR")
+	smallest := MustMoney(math.MinInt64, "EUR")
+
+	tests := []struct {
+		name string
+		op   func() (Money, error)
+		want error
+	}{
+		{"add currency mismatch", func() (Money, error) { return eur.Add(usd) }, ErrCurrencyMismatch},
+		{"sub currency mismatch", func() (Money, error) { return eur.Sub(usd) }, ErrCurrencyMismatch},
+		{"add overflow", func() (Money, error) { return largest.Add(eur) }, ErrMoneyOverflow},
+		{"add underflow", func() (Money, error) { return smallest.Add(MustMoney(-1, "EUR")) }, ErrMoneyOverflow},
+		{"sub underflow", func() (Money, error) { return smallest.Sub(eur) }, ErrMoneyOverflow},
+		{"mul overflow", func() (Money, error) { return largest.Mul(2) }, ErrMoneyOverflow},
+		{"mul min by -1", func() (Money, error) { return smallest.Mul(-1) }, ErrMoneyOverflow},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			_, err := tt.op()
+			s.Assert().ErrorIs(err, tt.want)
+		})
+	}
+}
+
+func (s *MoneySuite) TestAllocate() {
+	tests := []struct {
+		name   string
+		amount int64
+		ratios []int64
+		want   []int64
+	}{
+		{"even", 100, []int64{1, 1}, []int64{50, 50}},
+		{"remainder to first parts", 100, []int64{1, 1, 1}, []int64{34, 33, 33}},
+		{"weighted", 1000, []int64{70, 20, 10}, []int64{700, 200, 100}},
+		{"weighted remainder", 5, []int64{3, 7}, []int64{2, 3}},
+		{"zero ratio gets nothing", 10, []int64{0, 1, 1}, []int64{0, 5, 5}},
+		{"remainder skips zero ratio", 11, []int64{0, 1, 1}, []int64{0, 6, 5}},
+		{"negative", -100, []int64{1, 1, 1}, []int64{-34, -33, -33}},
+		{"no overflow on large amounts", math.MaxInt64, []int64{math.MaxInt64, 1}, []int64{math.MaxInt64, 0}},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			parts, err := MustMoney(tt.amount, "EUR").Allocate(tt.ratios...)
+			s.Require().NoError(err)
+			s.Assert().Equal(tt.want, s.amounts(parts))
+			for _, p := range parts {
+				s.Assert().Equal(Currency("EUR"), p.Currency())
+			}
+		})
+	}
+}
+
+func (s *MoneySuite) TestAllocate_InvalidRatios() {
+	m := MustMoney(100, "EUR")
+
+	_, err := m.Allocate()
+	s.Assert().ErrorIs(err, ErrInvalidRatios)
+	_, err = m.Allocate(0, 0)
+	s.Assert().ErrorIs(err, ErrInvalidRatios)
+	_, err = m.Allocate(2, -1)
+	s.Assert().ErrorIs(err, ErrInvalidRatios)
+	_, err = m.Split(0)
+	s.Assert().ErrorIs(err, ErrInvalidRatios)
+}
+
+func (s *MoneySuite) TestSplit() {
+	parts, err := MustMoney(1000, "JPY").Split(3)
+	s.Require().NoError(err)
+	s.Assert().Equal([]int64{334, 333, 333}, s.amounts(parts))
+}
+
+func (s *MoneySuite) TestString() {
+	tests := []struct {
+		money Money
+		want  string
+	}{
+		{MustMoney(1234, "EUR"), "12.34 EUR"},
+		{MustMoney(5, "EUR"), "0.05 EUR"},
+		{MustMoney(-250, "EUR"), "-2.50 EUR"},
+		{MustMoney(1234, "JPY"), "1234 JPY"},
+		{MustMoney(1234, "KWD"), "1.234 KWD"},
+		{MustMoney(math.MinInt64, "JPY"), "-9223372036854775808 JPY"},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.want, func() {
+			s.Assert().Equal(tt.want, tt.money.String())
+		})
+	}
+}
+
+func (s *MoneySuite) TestJSON() {
+	data, err := json.Marshal(MustMoney(350, "EUR"))
+	s.Require().NoError(err)
+	s.Assert().JSONEq(`{"amount":350,"currency":"EUR"}`, string(data))
+
+	var got Money
+	s.Require().NoError(json.Unmarshal(data, &got))
+	s.Assert().Equal(MustMoney(350, "EUR"), got)
+
+	for _, input := range []string{
+		`{"currency":"EUR"}`,
+		`{"amount":350}`,
+		`{"amount":350,"currency":"XYZ"}`,
+		`{"amount":"350","currency":"EUR"}`,
+	} {
+		s.Assert().Error(json.Unmarshal([]byte(input), &got), input)
+	}
+}
+
+func (s *MoneySuite) TestBinaryEncodings() {
+	type product struct {
+		Price Money `json:"price" msgpack:"price"`
+	}
+	want := product{Price: MustMoney(350, "JPY")}
+
+	data, err := cbor.Marshal(want)
+	s.Require().NoError(err)
+	var fromCBOR map[string]map[string]any
+	s.Require().NoError(cbor.Unmarshal(data, &fromCBOR))
+	s.Assert().Equal(map[string]any{"amount": uint64(350), "currency": "JPY"}, fromCBOR["price"])
+	var gotCBOR product
+	s.Require().NoError(cbor.Unmarshal(data, &gotCBOR))
+	s.Assert().Equal(want, gotCBOR)
+
+	data, err = msgpack.Marshal(want)
+	s.Require().NoError(err)
+	var gotMsgpack product
+	s.Require().NoError(msgpack.Unmarshal(data, &gotMsgpack))
+	s.Assert().Equal(want, gotMsgpack)
+
+	data, err = msgpack.Marshal(map[string]any{"currency": "JPY"})
+	s.Require().NoError(err)
+	s.Assert().Error(msgpack.Unmarshal(data, &gotMsgpack.Price), "amount is required")
+}
+
+func (s *MoneySuite) TestValueScan() {
+	value, err := MustMoney(-1234, "EUR").Value()
+	s.Require().NoError(err)
+	s.Assert().Equal("(-1234,EUR)", value)
+
+	tests := []struct {
+		name    string
+		input   any
+		want    Money
+		wantErr bool
+	}{
+		{"string", "(-1234,EUR)", MustMoney(-1234, "EUR"), false},
+		{"bytes", []byte("(350,JPY)"), MustMoney(350, "JPY"), false},
+		{"quoted", `(350,"EUR")`, MustMoney(350, "EUR"), false},
+		{"not a composite", "350", Money{}, true},
+		{"bad amount", "(abc,EUR)", Money{}, true},
+		{"bad currency", "(350,XYZ)", Money{}, true},
+		{"wrong type", int64(350), Money{}, true},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			var got Money
+			err := got.Scan(tt.input)
+			if tt.wantErr {
+				s.Require().Error(err)
+			} else {
+				s.Require().NoError(err)
+			}
+			s.Assert().Equal(tt.want, got)
+		})
+	}
+
+	_, err = Money{}.Value()
+	s.Assert().ErrorIs(err, ErrInvalidCurrency)
+}
+
+func TestMoneySuite(t *testing.T) {
+	suite.Run(t, new(MoneySuite))
+}
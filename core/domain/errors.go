@@ -15,11 +15,14 @@ const (
 
 // Error is the domain error type used across all layers.
 // It carries a classification code, a human-readable message,
-// and an optional wrapped error for chain compatibility.
+// an optional wrapped error for chain compatibility, and optional
+// typed metadata (see WithMeta).
 type Error struct {
 	Code    Code
 	Message string
 	Err     error
+
+	meta map[string]any
 }
 
 func (e *Error) Error() string {
@@ -0,0 +1,79 @@
+package domain
+
+import (
+	"errors"
+	"maps"
+	"time"
+)
+
+// MetaKey identifies a typed metadata entry attached to an Error. The name is
+// the key transports render the value under (a problem details extension
+// member for HTTP), so it should be snake_case and stable.
+type MetaKey[T any] struct {
+	name string
+}
+
+// NewMetaKey declares a metadata key. Declare keys once as package-level vars.
+func NewMetaKey[T any](name string) MetaKey[T] {
+	return MetaKey[T]{name: name}
+}
+
+func (k MetaKey[T]) Name() string {
+	return k.name
+}
+
+// Well-known metadata keys understood by the core transports.
+var (
+	// MetaFieldErrors lists the individual invalid inputs behind a CodeValidation error.
+	MetaFieldErrors = NewMetaKey[[]FieldError]("errors")
+	// MetaRetryAfter hints when a failed operation may be retried.
+	MetaRetryAfter = NewMetaKey[time.Duration]("retry_after")
+	// MetaConflictingID identifies the existing resource behind a CodeConflict error.
+	MetaConflictingID = NewMetaKey[string]("conflicting_id")
+)
+
+// FieldError describes one invalid part of a request. Pointer is an RFC 6901
+// JSON pointer into the request body ("" refers to the whole body) and Rule
+// names the failed check.
+type FieldError struct {
+	Pointer string `json:"pointer"`
+	Rule    string `json:"rule,omitempty"`
+	Message string `json:"message"`
+}
+
+func (e *FieldError) Error() string {
+	if e.Pointer == "" {
+		return e.Message
+	}
+	return e.Pointer + ": " + e.Message
+}
+
+// WithMeta attaches value under key and returns e for chaining. It must not be
+// called on the shared sentinel errors.
+func WithMeta[T any](e *Error, key MetaKey[T], value T) *Error {
+	if e.meta == nil {
+		e.meta = make(map[string]any)
+	}
+	e.meta[key.name] = value
+	return e
+}
+
+// MetaValue returns the value stored under key on the first domain Error in
+// err's chain.
+func MetaValue[T any](err error, key MetaKey[T]) (T, bool) {
+	var zero T
+	var domainErr *Error
+	if !errors.As(err, &domainErr) {
+		return zero, false
+	}
+	v, ok := domainErr.meta[key.name].(T)
+	if !ok {
+		return zero, false
+	}
+	return v, true
+}
+
+// Meta returns a copy of all metadata attached to e, keyed by MetaKey name.
+func (e *Error) Meta() map[string]any {
+	return maps.Clone(e.meta)
+}
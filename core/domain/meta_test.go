@@ -0,0 +1,65 @@
+package domain
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type MetaSuite struct {
+	suite.Suite
+}
+
+func (s *MetaSuite) TestMetaValue_RoundTrip() {
+	err := WithMeta(NewError(CodeConflict, "name taken"), MetaConflictingID, "abc")
+
+	id, ok := MetaValue(err, MetaConflictingID)
+	s.Require().True(ok)
+	s.Assert().Equal("abc", id)
+}
+
+func (s *MetaSuite) TestMetaValue_ThroughWrappedChain() {
+	inner := WithMeta(NewError(CodeInvariant, "busy"), MetaRetryAfter, 5*time.Second)
+	err := fmt.Errorf("service: %w", inner)
+
+	after, ok := MetaValue(err, MetaRetryAfter)
+	s.Require().True(ok)
+	s.Assert().Equal(5*time.Second, after)
+}
+
+func (s *MetaSuite) TestMetaValue_Missing() {
+	_, ok := MetaValue(NewError(CodeNotFound, "gone"), MetaRetryAfter)
+	s.Assert().False(ok)
+
+	_, ok = MetaValue(fmt.Errorf("plain"), MetaRetryAfter)
+	s.Assert().False(ok)
+}
+
+func (s *MetaSuite) TestMetaValue_TypeMismatch() {
+	other := NewMetaKey[int]("conflicting_id")
+	err := WithMeta(NewError(CodeConflict, "name taken"), MetaConflictingID, "abc")
+
+	_, ok := MetaValue(err, other)
+	s.Assert().False(ok)
+}
+
+func (s *MetaSuite) TestMeta_ReturnsCopy() {
+	err := WithMeta(NewError(CodeConflict, "name taken"), MetaConflictingID, "abc")
+
+	meta := err.Meta()
+	meta["conflicting_id"] = "changed"
+
+	id, _ := MetaValue(err, MetaConflictingID)
+	s.Assert().Equal("abc", id)
+}
+
+func (s *MetaSuite) TestFieldError_Error() {
+	s.Assert().Equal("/name: is required", (&FieldError{Pointer: "/name", Message: "is required"}).Error())
+	s.Assert().Equal("body is required", (&FieldError{Message: "body is required"}).Error())
+}
+
+func TestMetaSuite(t *testing.T) {
+	suite.Run(t, new(MetaSuite))
+}
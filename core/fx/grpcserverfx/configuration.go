@@ -0,0 +1,67 @@
+package grpcserverfx
+
+import (
+	"time"
+
+	"github.com/go-playground/validator/v10"
+
+	"github.com/bbsbb/go-edge/core/configuration"
+)
+
+var validate = validator.New(validator.WithRequiredStructEnabled())
+
+var _ configuration.WithValidation = (*Configuration)(nil)
+
+// InterceptorsConfiguration toggles the core interceptor chain, mirroring
+// middlewarefx.Configuration for HTTP.
+type InterceptorsConfiguration struct {
+	Recovery              bool   `yaml:"recovery" env:"ENABLE_RECOVERY,overwrite"`
+	RequestID             bool   `yaml:"request_id" env:"ENABLE_REQUEST_ID,overwrite"`
+	CorrelationID         bool   `yaml:"correlation_id" env:"ENABLE_CORRELATION_ID,overwrite"`
+	CorrelationIDMetadata string `yaml:"correlation_metadata" env:"CORRELATION_ID_METADATA,overwrite"`
+	OTel                  bool   `yaml:"otel" env:"ENABLE_OTEL,overwrite"`
+	RequestLog            bool   `yaml:"request_log" env:"ENABLE_REQUEST_LOGGING,overwrite"`
+}
+
+// DefaultInterceptors returns an InterceptorsConfiguration with every interceptor enabled.
+func DefaultInterceptors() InterceptorsConfiguration {
+	return InterceptorsConfiguration{
+		Recovery:      true,
+		RequestID:     true,
+		CorrelationID: true,
+		OTel:          true,
+		RequestLog:    true,
+	}
+}
+
+type Configuration struct {
+	Port uint16 `yaml:"port" env:"PORT,overwrite" validate:"required,gte=1,lte=65535"`
+	// RequestTimeout bounds unary calls whose client sent no deadline, in seconds.
+	RequestTimeout uint8 `yaml:"request_timeout" env:"REQUEST_TIMEOUT,overwrite" validate:"required,gte=1,lte=120"`
+	// ShutdownTimeout bounds graceful stop before in-flight calls are cancelled, in seconds.
+	ShutdownTimeout uint8 `yaml:"shutdown_timeout" env:"SHUTDOWN_TIMEOUT,overwrite" validate:"lte=120"`
+	MaxRecvMsgBytes int   `yaml:"max_recv_msg_bytes" env:"MAX_RECV_MSG_BYTES,overwrite" validate:"gte=0"`
+	// Reflection registers the server reflection service for tools like grpcurl.
+	Reflection   bool                      `yaml:"reflection" env:"ENABLE_REFLECTION,overwrite"`
+	Interceptors InterceptorsConfiguration `yaml:"interceptors"`
+}
+
+func (c *Configuration) Validate() error {
+	return validate.Struct(c)
+}
+
+func (c *Configuration) shutdownTimeoutOrDefault() time.Duration {
+	if c.ShutdownTimeout > 0 {
+		return time.Duration(c.ShutdownTimeout) * time.Second
+	}
+	return 10 * time.Second
+}
+
+// WithGRPCServer is implemented by application configurations that run a gRPC server.
+type WithGRPCServer interface {
+	GRPCServerConfiguration() *Configuration
+}
+
+func provideConfiguration(cfg WithGRPCServer) *Configuration {
+	return cfg.GRPCServerConfiguration()
+}
@@ -0,0 +1,139 @@
+// Package grpcserverfx provides an fx module for a gRPC server with a core
+// interceptor chain mirroring middlewarefx.
+package grpcserverfx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"time"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.uber.org/fx"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+
+	"github.com/bbsbb/go-edge/core/fx/otelfx"
+)
+
+type Params struct {
+	fx.In
+	Lifecycle  fx.Lifecycle
+	Config     *Configuration
+	Logger     *slog.Logger
+	OTelConfig *otelfx.Configuration `optional:"true"`
+	Extra      []Interceptor         `group:"grpc_interceptors"`
+}
+
+type Result struct {
+	fx.Out
+	Server *grpc.Server
+	Health *health.Server
+}
+
+// chain assembles the interceptor chain, outermost first: recovery, request ID,
+// correlation ID, logging, error translation, default unary deadline, then
+// the application's interceptors.
+func chain(p Params) []Interceptor {
+	cfg := p.Config.Interceptors
+	var chain []Interceptor
+	if cfg.Recovery {
+		chain = append(chain, Recovery(p.Logger))
+	}
+	if cfg.RequestID {
+		chain = append(chain, RequestID())
+	}
+	if cfg.CorrelationID {
+		chain = append(chain, CorrelationID(cfg.CorrelationIDMetadata))
+	}
+	if cfg.RequestLog {
+		chain = append(chain, RequestLogger(p.Logger))
+	}
+	chain = append(chain, Errors(p.Logger), Timeout(time.Duration(p.Config.RequestTimeout)*time.Second))
+	return append(chain, p.Extra...)
+}
+
+func NewGRPCServer(p Params) (Result, error) {
+	var unary []grpc.UnaryServerInterceptor
+	var stream []grpc.StreamServerInterceptor
+	for _, i := range chain(p) {
+		if i.Unary != nil {
+			unary = append(unary, i.Unary)
+		}
+		if i.Stream != nil {
+			stream = append(stream, i.Stream)
+		}
+	}
+
+	opts := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(unary...),
+		grpc.ChainStreamInterceptor(stream...),
+	}
+	if p.Config.MaxRecvMsgBytes > 0 {
+		opts = append(opts, grpc.MaxRecvMsgSize(p.Config.MaxRecvMsgBytes))
+	}
+	if p.Config.Interceptors.OTel && p.OTelConfig != nil {
+		opts = append(opts, grpc.StatsHandler(otelgrpc.NewServerHandler()))
+	}
+
+	srv := grpc.NewServer(opts...)
+	healthSrv := health.NewServer()
+	healthpb.RegisterHealthServer(srv, healthSrv)
+	if p.Config.Reflection {
+		reflection.Register(srv)
+	}
+
+	p.Lifecycle.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			var lc net.ListenConfig
+			lis, err := lc.Listen(ctx, "tcp", fmt.Sprintf(":%d", p.Config.Port))
+			if err != nil {
+				return fmt.Errorf("grpcserverfx: listen: %w", err)
+			}
+			go func() {
+				if err := srv.Serve(lis); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+					p.Logger.Error("gRPC server failed", "error", err)
+				}
+			}()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			healthSrv.Shutdown()
+			return gracefulStop(ctx, srv, p.Config.shutdownTimeoutOrDefault())
+		},
+	})
+
+	return Result{Server: srv, Health: healthSrv}, nil
+}
+
+// gracefulStop waits for in-flight calls to finish, then forcibly closes
+// remaining connections once ctx or the shutdown timeout expires.
+func gracefulStop(ctx context.Context, srv *grpc.Server, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	done := make(chan struct{})
+	go func() {
+		srv.GracefulStop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		srv.Stop()
+		return fmt.Errorf("grpcserverfx: graceful stop: %w", ctx.Err())
+	}
+}
+
+var Module = fx.Module(
+	"grpcserverfx",
+	fx.Provide(provideConfiguration, NewGRPCServer),
+	// Forces server instantiation so lifecycle hooks register even if nothing else depends on *grpc.Server.
+	fx.Invoke(func(_ *grpc.Server) {}),
+)
@@ -0,0 +1,278 @@
+package grpcserverfx
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	chimw "github.com/go-chi/chi/v5/middleware"
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxevent"
+	"go.uber.org/fx/fxtest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/bbsbb/go-edge/core/domain"
+	"github.com/bbsbb/go-edge/core/fx/middlewarefx"
+	coretesting "github.com/bbsbb/go-edge/core/testing"
+)
+
+type GRPCServerSuite struct {
+	suite.Suite
+}
+
+func (s *GRPCServerSuite) TestConfiguration_Validate() {
+	tests := []struct {
+		name       string
+		config     Configuration
+		wantErr    bool
+		wantFields []string
+	}{
+		{
+			name:    "valid config",
+			config:  Configuration{Port: 9090, RequestTimeout: 30},
+			wantErr: false,
+		},
+		{
+			name:       "invalid port zero",
+			config:     Configuration{Port: 0, RequestTimeout: 30},
+			wantErr:    true,
+			wantFields: []string{"Port"},
+		},
+		{
+			name:       "invalid request timeout zero",
+			config:     Configuration{Port: 9090, RequestTimeout: 0},
+			wantErr:    true,
+			wantFields: []string{"RequestTimeout"},
+		},
+		{
+			name:       "invalid shutdown timeout too high",
+			config:     Configuration{Port: 9090, RequestTimeout: 30, ShutdownTimeout: 121},
+			wantErr:    true,
+			wantFields: []string{"ShutdownTimeout"},
+		},
+		{
+			name:       "invalid negative max message size",
+			config:     Configuration{Port: 9090, RequestTimeout: 30, MaxRecvMsgBytes: -1},
+			wantErr:    true,
+			wantFields: []string{"MaxRecvMsgBytes"},
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			err := tt.config.Validate()
+			if tt.wantErr {
+				s.Require().Error(err)
+				var validationErrs validator.ValidationErrors
+				s.Require().ErrorAs(err, &validationErrs)
+
+				fields := make([]string, len(validationErrs))
+				for i, fe := range validationErrs {
+					fields[i] = fe.Field()
+				}
+				s.Assert().ElementsMatch(tt.wantFields, fields)
+			} else {
+				s.Assert().NoError(err)
+			}
+		})
+	}
+}
+
+type testAppConfig struct {
+	grpcConfig *Configuration
+}
+
+func (c *testAppConfig) GRPCServerConfiguration() *Configuration {
+	return c.grpcConfig
+}
+
+// probeServer is a hand-written service so tests need no generated code.
+type probeServer interface {
+	call(ctx context.Context) error
+}
+
+type probe struct {
+	fn func(ctx context.Context) error
+}
+
+func (p *probe) call(ctx context.Context) error { return p.fn(ctx) }
+
+const (
+	probeMethod       = "/test.Probe/Call"
+	probeStreamMethod = "/test.Probe/Watch"
+)
+
+var probeServiceDesc = grpc.ServiceDesc{
+	ServiceName: "test.Probe",
+	HandlerType: (*probeServer)(nil),
+	Methods: []grpc.MethodDesc{{
+		MethodName: "Call",
+		Handler: func(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+			in := new(emptypb.Empty)
+			if err := dec(in); err != nil {
+				return nil, err
+			}
+			handler := func(ctx context.Context, _ any) (any, error) {
+				return &emptypb.Empty{}, srv.(probeServer).call(ctx)
+			}
+			return interceptor(ctx, in, &grpc.UnaryServerInfo{Server: srv, FullMethod: probeMethod}, handler)
+		},
+	}},
+	Streams: []grpc.StreamDesc{{
+		StreamName:    "Watch",
+		ServerStreams: true,
+		Handler: func(srv any, stream grpc.ServerStream) error {
+			return srv.(probeServer).call(stream.Context())
+		},
+	}},
+}
+
+type IntegrationSuite struct {
+	suite.Suite
+	probe   *probe
+	conn    *grpc.ClientConn
+	testApp *fxtest.App
+	extra   []string
+}
+
+func (s *IntegrationSuite) SetupTest() {
+	s.probe = &probe{fn: func(context.Context) error { return nil }}
+	s.extra = nil
+
+	s.testApp = fxtest.New(
+		s.T(),
+		fx.Supply(
+			coretesting.NewNoopLogger(),
+			fx.Annotate(
+				&testAppConfig{
+					grpcConfig: &Configuration{
+						Port:           15125,
+						RequestTimeout: 1,
+						Interceptors:   DefaultInterceptors(),
+					},
+				},
+				fx.As(new(WithGRPCServer)),
+			),
+		),
+		fx.Provide(fx.Annotate(
+			func() Interceptor {
+				return around("app", func(ctx context.Context, method string, call func(context.Context) error) error {
+					s.extra = append(s.extra, method)
+					return call(ctx)
+				})
+			},
+			fx.ResultTags(`group:"grpc_interceptors"`),
+		)),
+		Module,
+		fx.Invoke(func(srv *grpc.Server) { srv.RegisterService(&probeServiceDesc, s.probe) }),
+		fx.WithLogger(func() fxevent.Logger {
+			return fxevent.NopLogger
+		}),
+	)
+	s.testApp.RequireStart()
+
+	conn, err := grpc.NewClient("localhost:15125", grpc.WithTransportCredentials(insecure.NewCredentials()))
+	s.Require().NoError(err)
+	s.conn = conn
+}
+
+func (s *IntegrationSuite) TearDownTest() {
+	s.Require().NoError(s.conn.Close())
+	s.testApp.RequireStop()
+}
+
+func (s *IntegrationSuite) invoke(ctx context.Context, opts ...grpc.CallOption) error {
+	return s.conn.Invoke(ctx, probeMethod, &emptypb.Empty{}, &emptypb.Empty{}, opts...)
+}
+
+func (s *IntegrationSuite) TestHealthCheck() {
+	resp, err := healthpb.NewHealthClient(s.conn).Check(s.T().Context(), &healthpb.HealthCheckRequest{})
+
+	s.Require().NoError(err)
+	s.Assert().Equal(healthpb.HealthCheckResponse_SERVING, resp.GetStatus())
+}
+
+func (s *IntegrationSuite) TestRequestAndCorrelationIDs() {
+	var reqID, corrID string
+	s.probe.fn = func(ctx context.Context) error {
+		reqID = chimw.GetReqID(ctx)
+		corrID = middlewarefx.CorrelationIDFromContext(ctx)
+		return nil
+	}
+
+	ctx := metadata.AppendToOutgoingContext(s.T().Context(), CorrelationIDMetadataKey, "corr-1")
+	var header metadata.MD
+	s.Require().NoError(s.invoke(ctx, grpc.Header(&header)))
+
+	s.Assert().NotEmpty(reqID)
+	s.Assert().Equal([]string{reqID}, header.Get(RequestIDMetadataKey))
+	s.Assert().Equal("corr-1", corrID)
+	s.Assert().Equal([]string{"corr-1"}, header.Get(CorrelationIDMetadataKey))
+	s.Assert().Equal([]string{probeMethod}, s.extra)
+}
+
+func (s *IntegrationSuite) TestDomainErrorTranslated() {
+	s.probe.fn = func(context.Context) error {
+		return domain.NewError(domain.CodeNotFound, "product not found")
+	}
+
+	st := status.Convert(s.invoke(s.T().Context()))
+
+	s.Assert().Equal(codes.NotFound, st.Code())
+	s.Assert().Equal("product not found", st.Message())
+}
+
+func (s *IntegrationSuite) TestPanicRecovered() {
+	s.probe.fn = func(context.Context) error { panic("boom") }
+
+	st := status.Convert(s.invoke(s.T().Context()))
+
+	s.Assert().Equal(codes.Internal, st.Code())
+	s.Assert().Equal("internal error", st.Message())
+}
+
+func (s *IntegrationSuite) TestDefaultDeadline() {
+	var deadline time.Time
+	s.probe.fn = func(ctx context.Context) error {
+		deadline, _ = ctx.Deadline()
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	st := status.Convert(s.invoke(s.T().Context()))
+
+	s.Assert().Equal(codes.DeadlineExceeded, st.Code())
+	s.Assert().WithinDuration(time.Now(), deadline, 2*time.Second)
+}
+
+func (s *IntegrationSuite) TestStreamHasNoDefaultDeadline() {
+	hasDeadline := true
+	s.probe.fn = func(ctx context.Context) error {
+		_, hasDeadline = ctx.Deadline()
+		return nil
+	}
+
+	stream, err := s.conn.NewStream(s.T().Context(), &probeServiceDesc.Streams[0], probeStreamMethod)
+	s.Require().NoError(err)
+	s.Require().NoError(stream.CloseSend())
+
+	s.Assert().ErrorIs(stream.RecvMsg(&emptypb.Empty{}), io.EOF)
+	s.Assert().False(hasDeadline)
+}
+
+func TestGRPCServerSuite(t *testing.T) {
+	suite.Run(t, new(GRPCServerSuite))
+}
+
+func TestIntegrationSuite(t *testing.T) {
+	suite.Run(t, new(IntegrationSuite))
+}
@@ -0,0 +1,166 @@
+package grpcserverfx
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+	"time"
+
+	chimw "github.com/go-chi/chi/v5/middleware"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/bbsbb/go-edge/core/fx/middlewarefx"
+	transportgrpc "github.com/bbsbb/go-edge/core/transport/grpc"
+)
+
+const (
+	RequestIDMetadataKey     = "x-request-id"
+	CorrelationIDMetadataKey = "x-correlation-id"
+)
+
+// Interceptor is an application-provided unary/stream interceptor pair that
+// gets appended to the core chain. Apps supply instances via the
+// "grpc_interceptors" FX value group. Either function may be nil.
+type Interceptor struct {
+	Name   string
+	Unary  grpc.UnaryServerInterceptor
+	Stream grpc.StreamServerInterceptor
+}
+
+// around builds an Interceptor from a function that wraps a call. It lets each
+// core interceptor be written once for both unary and streaming RPCs.
+func around(name string, fn func(ctx context.Context, method string, call func(context.Context) error) error) Interceptor {
+	return Interceptor{
+		Name: name,
+		Unary: func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+			var resp any
+			err := fn(ctx, info.FullMethod, func(ctx context.Context) error {
+				var err error
+				resp, err = handler(ctx, req)
+				return err
+			})
+			return resp, err
+		},
+		Stream: func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			return fn(ss.Context(), info.FullMethod, func(ctx context.Context) error {
+				return handler(srv, &transportgrpc.ServerStream{ServerStream: ss, Ctx: ctx})
+			})
+		},
+	}
+}
+
+// Recovery catches panics in downstream handlers, logs the stack trace, and
+// returns an Internal status.
+func Recovery(logger *slog.Logger) Interceptor {
+	return around("recovery", func(ctx context.Context, method string, call func(context.Context) error) (err error) {
+		defer func() {
+			if rv := recover(); rv != nil {
+				logger.ErrorContext(ctx, "panic recovered",
+					"method", method,
+					"panic", fmt.Sprint(rv),
+					"stack", string(debug.Stack()),
+				)
+				err = status.Error(codes.Internal, "internal error")
+			}
+		}()
+		return call(ctx)
+	})
+}
+
+// RequestID reads x-request-id metadata or generates an ID, stores it where
+// chimw.GetReqID finds it, echoes it in the response header and sets the
+// request.id span attribute.
+func RequestID() Interceptor {
+	return around("request_id", func(ctx context.Context, _ string, call func(context.Context) error) error {
+		id := firstMetadata(ctx, RequestIDMetadataKey)
+		if id == "" {
+			id = uuid.Must(uuid.NewV7()).String()
+		}
+
+		ctx = context.WithValue(ctx, chimw.RequestIDKey, id)
+		if span := trace.SpanFromContext(ctx); span.IsRecording() {
+			span.SetAttributes(attribute.String("request.id", id))
+		}
+		_ = grpc.SetHeader(ctx, metadata.Pairs(RequestIDMetadataKey, id))
+		return call(ctx)
+	})
+}
+
+// CorrelationID reads the correlation ID from the given metadata key
+// (CorrelationIDMetadataKey when empty) or generates one, and stores it for
+// middlewarefx.CorrelationIDFromContext.
+func CorrelationID(key string) Interceptor {
+	if key == "" {
+		key = CorrelationIDMetadataKey
+	}
+	return around("correlation_id", func(ctx context.Context, _ string, call func(context.Context) error) error {
+		id := firstMetadata(ctx, key)
+		if id == "" {
+			id = uuid.Must(uuid.NewV7()).String()
+		}
+
+		ctx = middlewarefx.ContextWithCorrelationID(ctx, id)
+		if span := trace.SpanFromContext(ctx); span.IsRecording() {
+			span.SetAttributes(attribute.String("correlation.id", id))
+		}
+		_ = grpc.SetHeader(ctx, metadata.Pairs(key, id))
+		return call(ctx)
+	})
+}
+
+// RequestLogger logs each call with request ID, correlation ID, method,
+// status code, and duration.
+func RequestLogger(logger *slog.Logger) Interceptor {
+	return around("request_log", func(ctx context.Context, method string, call func(context.Context) error) error {
+		start := time.Now()
+		err := call(ctx)
+
+		logger.InfoContext(ctx, "grpc request",
+			"request_id", chimw.GetReqID(ctx),
+			"correlation_id", middlewarefx.CorrelationIDFromContext(ctx),
+			"method", method,
+			"code", status.Code(err).String(),
+			"duration_ms", time.Since(start).Milliseconds(),
+		)
+		return err
+	})
+}
+
+// Errors translates errors returned by handlers into gRPC statuses with
+// transportgrpc.ToStatus, so services can return domain errors unchanged.
+func Errors(logger *slog.Logger) Interceptor {
+	return around("errors", func(ctx context.Context, _ string, call func(context.Context) error) error {
+		return transportgrpc.ToStatus(ctx, call(ctx), logger)
+	})
+}
+
+// Timeout applies a default deadline to unary calls whose client sent none.
+// Streams are left unbounded: a subscription lasts as long as its client.
+func Timeout(d time.Duration) Interceptor {
+	return Interceptor{
+		Name: "timeout",
+		Unary: func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+			if _, ok := ctx.Deadline(); ok {
+				return handler(ctx, req)
+			}
+			ctx, cancel := context.WithTimeout(ctx, d)
+			defer cancel()
+			return handler(ctx, req)
+		},
+	}
+}
+
+func firstMetadata(ctx context.Context, key string) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
@@ -0,0 +1,140 @@
+package httpclientfx
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned, wrapped in a *url.Error, for requests rejected
+// by an open circuit. It is never retried.
+var ErrCircuitOpen = errors.New("httpclientfx: circuit open")
+
+type breakerState int
+
+const (
+	stateClosed breakerState = iota
+	stateOpen
+	stateHalfOpen
+)
+
+func (s breakerState) String() string {
+	switch s {
+	case stateOpen:
+		return "open"
+	case stateHalfOpen:
+		return "half_open"
+	default:
+		return "closed"
+	}
+}
+
+type outcome int
+
+const (
+	outcomeSuccess outcome = iota
+	outcomeFailure
+	// outcomeIgnored is a call abandoned by its caller, which says nothing
+	// about the upstream's health.
+	outcomeIgnored
+)
+
+// breaker is a consecutive-failure circuit breaker for one upstream host.
+// After FailureThreshold failures in a row it opens and rejects calls for
+// OpenTimeout, then admits up to HalfOpenRequests probes: the first probe to
+// succeed closes it, a failing probe opens it again.
+type breaker struct {
+	cfg      BreakerConfiguration
+	now      func() time.Time
+	onChange func(to breakerState)
+
+	mu       sync.Mutex
+	state    breakerState
+	failures int
+	openedAt time.Time
+	probes   int
+}
+
+// allow reports whether a call may proceed and whether it is a probe, which
+// must be passed back to record.
+func (b *breaker) allow() (probe bool, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == stateOpen {
+		if b.now().Sub(b.openedAt) < b.cfg.OpenTimeout {
+			return false, ErrCircuitOpen
+		}
+		b.transition(stateHalfOpen)
+	}
+	if b.state == stateHalfOpen {
+		if b.probes >= b.cfg.HalfOpenRequests {
+			return false, ErrCircuitOpen
+		}
+		b.probes++
+		return true, nil
+	}
+	return false, nil
+}
+
+func (b *breaker) record(probe bool, o outcome) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if probe {
+		b.probes--
+		switch o {
+		case outcomeSuccess:
+			b.transition(stateClosed)
+		case outcomeFailure:
+			b.transition(stateOpen)
+		case outcomeIgnored:
+		}
+		return
+	}
+
+	// Results of calls admitted before the circuit opened do not count.
+	if b.state != stateClosed {
+		return
+	}
+	switch o {
+	case outcomeSuccess:
+		b.failures = 0
+	case outcomeFailure:
+		b.failures++
+		if b.failures >= b.cfg.FailureThreshold {
+			b.transition(stateOpen)
+		}
+	case outcomeIgnored:
+	}
+}
+
+func (b *breaker) transition(to breakerState) {
+	if b.state == to {
+		return
+	}
+	b.state = to
+	b.failures = 0
+	if to == stateOpen {
+		b.openedAt = b.now()
+	}
+	if b.onChange != nil {
+		b.onChange(to)
+	}
+}
+
+// classify maps a round trip result to a breaker outcome. Server errors and
+// transport failures count against the upstream; client errors do not.
+func classify(req *http.Request, resp *http.Response, err error) outcome {
+	switch {
+	case req.Context().Err() != nil:
+		return outcomeIgnored
+	case err != nil:
+		return outcomeFailure
+	case resp.StatusCode >= http.StatusInternalServerError:
+		return outcomeFailure
+	default:
+		return outcomeSuccess
+	}
+}
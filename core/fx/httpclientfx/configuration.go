@@ -0,0 +1,113 @@
+package httpclientfx
+
+import (
+	"math/rand/v2"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+
+	"github.com/bbsbb/go-edge/core/configuration"
+)
+
+var validate = validator.New(validator.WithRequiredStructEnabled())
+
+var (
+	_ configuration.WithValidation = (*ClientConfiguration)(nil)
+	_ configuration.WithValidation = (*Configuration)(nil)
+)
+
+// RetryConfiguration controls retries of idempotent requests. Zero values
+// select the defaults; MaxAttempts of 1 disables retries.
+type RetryConfiguration struct {
+	// MaxAttempts counts the first attempt. Defaults to 3.
+	MaxAttempts int           `yaml:"max_attempts" validate:"gte=0,lte=10"`
+	MinDelay    time.Duration `yaml:"min_delay" validate:"gte=0"`
+	MaxDelay    time.Duration `yaml:"max_delay" validate:"gte=0"`
+}
+
+// BreakerConfiguration controls the per-host circuit breaker. Zero values
+// select the defaults; a negative FailureThreshold disables the breaker.
+type BreakerConfiguration struct {
+	// FailureThreshold is the number of consecutive failures that opens the
+	// circuit. Defaults to 5.
+	FailureThreshold int `yaml:"failure_threshold"`
+	// OpenTimeout is how long an open circuit rejects requests before letting
+	// probes through. Defaults to 30s.
+	OpenTimeout time.Duration `yaml:"open_timeout" validate:"gte=0"`
+	// HalfOpenRequests is the number of concurrent probes allowed while half
+	// open. Defaults to 1.
+	HalfOpenRequests int `yaml:"half_open_requests" validate:"gte=0"`
+}
+
+// ClientConfiguration tunes one named client.
+type ClientConfiguration struct {
+	// Timeout bounds a whole call, retries included. Defaults to 30s.
+	Timeout             time.Duration        `yaml:"timeout" validate:"gte=0"`
+	MaxIdleConnsPerHost int                  `yaml:"max_idle_conns_per_host" validate:"gte=0"`
+	Retry               RetryConfiguration   `yaml:"retry"`
+	Breaker             BreakerConfiguration `yaml:"breaker"`
+}
+
+func (c *ClientConfiguration) Validate() error {
+	return validate.Struct(c)
+}
+
+func (c ClientConfiguration) withDefaults() ClientConfiguration {
+	if c.Timeout <= 0 {
+		c.Timeout = 30 * time.Second
+	}
+	if c.Retry.MaxAttempts <= 0 {
+		c.Retry.MaxAttempts = 3
+	}
+	if c.Retry.MinDelay <= 0 {
+		c.Retry.MinDelay = 100 * time.Millisecond
+	}
+	if c.Retry.MaxDelay < c.Retry.MinDelay {
+		c.Retry.MaxDelay = max(2*time.Second, c.Retry.MinDelay)
+	}
+	if c.Breaker.FailureThreshold == 0 {
+		c.Breaker.FailureThreshold = 5
+	}
+	if c.Breaker.OpenTimeout <= 0 {
+		c.Breaker.OpenTimeout = 30 * time.Second
+	}
+	if c.Breaker.HalfOpenRequests <= 0 {
+		c.Breaker.HalfOpenRequests = 1
+	}
+	return c
+}
+
+// backoff returns the delay before retry number attempt (0-based): it doubles
+// from MinDelay up to MaxDelay, with jitter in the upper half so concurrent
+// callers spread out.
+func (r RetryConfiguration) backoff(attempt int) time.Duration {
+	d := r.MinDelay
+	for i := 0; i < attempt && d < r.MaxDelay; i++ {
+		d *= 2
+	}
+	d = min(d, r.MaxDelay)
+	return d/2 + rand.N(d/2+1) //nolint:gosec // jitter only
+}
+
+type Configuration struct {
+	// CorrelationHeader carries the correlation ID of the inbound request.
+	// Defaults to middlewarefx.CorrelationIDHeader.
+	CorrelationHeader string `yaml:"correlation_header" env:"CORRELATION_HEADER,overwrite"`
+	// Clients maps client names to their settings. Names not listed here are
+	// unknown to Registry.Client.
+	Clients map[string]ClientConfiguration `yaml:"clients" validate:"dive"`
+}
+
+func (c *Configuration) Validate() error {
+	return validate.Struct(c)
+}
+
+// WithHTTPClient is implemented by application configurations that make
+// outbound HTTP calls.
+type WithHTTPClient interface {
+	HTTPClientConfiguration() *Configuration
+}
+
+func provideConfiguration(cfg WithHTTPClient) *Configuration {
+	return cfg.HTTPClientConfiguration()
+}
@@ -0,0 +1,191 @@
+// Package httpclientfx provides an fx module for named outbound HTTP clients
+// with OTel instrumentation, correlation ID propagation, retries and per-host
+// circuit breakers.
+package httpclientfx
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.uber.org/fx"
+
+	"github.com/bbsbb/go-edge/core/fx/middlewarefx"
+	"github.com/bbsbb/go-edge/core/fx/otelfx"
+)
+
+const instrumentationName = "github.com/bbsbb/go-edge/core/fx/httpclientfx"
+
+var ErrUnknownClient = errors.New("httpclientfx: unknown client")
+
+// Registry holds the clients declared in Configuration.Clients.
+type Registry struct {
+	clients map[string]*http.Client
+}
+
+// Client returns the named client. Clients are safe for concurrent use and
+// share nothing with each other, breakers included.
+func (r *Registry) Client(name string) (*http.Client, error) {
+	c, ok := r.clients[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownClient, name)
+	}
+	return c, nil
+}
+
+func (r *Registry) closeIdleConnections() {
+	for _, c := range r.clients {
+		c.CloseIdleConnections()
+	}
+}
+
+// DialControl vets the connections of the client called Client: Control is
+// called with the network and resolved address of each one before it is
+// dialed, and an error refuses it. Checking the address rather than the
+// request's host holds however the host name resolves. Provide one in the
+// "httpclient_dial_controls" group.
+type DialControl struct {
+	Client  string
+	Control func(network, address string, c syscall.RawConn) error
+}
+
+// options carries the dependencies a client is built from; tests replace the
+// base transport, meter provider and clock.
+type options struct {
+	header   string
+	tracing  bool
+	meter    metric.MeterProvider
+	base     func(ClientConfiguration, *DialControl) http.RoundTripper
+	controls []DialControl
+	now      func() time.Time
+	logger   *slog.Logger
+}
+
+func (o options) control(name string) *DialControl {
+	for i := range o.controls {
+		if o.controls[i].Client == name {
+			return &o.controls[i]
+		}
+	}
+	return nil
+}
+
+// newClient assembles the transport chain, outermost first: correlation ID,
+// retries, otelhttp (one span per attempt), circuit breaker, base transport.
+// Rejections by an open circuit therefore show up as failed spans and are
+// never retried.
+func newClient(name string, cfg ClientConfiguration, opts options) (*http.Client, error) {
+	cfg = cfg.withDefaults()
+	metrics, err := newInstruments(opts.meter.Meter(instrumentationName))
+	if err != nil {
+		return nil, fmt.Errorf("httpclientfx: client %q metrics: %w", name, err)
+	}
+
+	var rt http.RoundTripper = opts.base(cfg, opts.control(name))
+	if cfg.Breaker.FailureThreshold > 0 {
+		rt = &breakerTransport{
+			name: name, cfg: cfg.Breaker, next: rt, metrics: metrics, logger: opts.logger,
+			now: opts.now, breakers: make(map[string]*breaker),
+		}
+	}
+	if opts.tracing {
+		rt = otelhttp.NewTransport(rt,
+			otelhttp.WithMeterProvider(opts.meter),
+			otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string {
+				return name + " " + r.Method
+			}),
+			otelhttp.WithMetricAttributesFn(func(*http.Request) []attribute.KeyValue {
+				return []attribute.KeyValue{attribute.String("http.client.name", name)}
+			}),
+		)
+	}
+	rt = &retryTransport{name: name, cfg: cfg.Retry, next: rt, metrics: metrics, logger: opts.logger}
+	rt = &correlationTransport{header: opts.header, next: rt}
+
+	return &http.Client{Transport: rt, Timeout: cfg.Timeout}, nil
+}
+
+// defaultBase clones http.DefaultTransport, dialing with the same settings it
+// does plus control when there is one.
+func defaultBase(cfg ClientConfiguration, control *DialControl) http.RoundTripper {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	if cfg.MaxIdleConnsPerHost > 0 {
+		t.MaxIdleConnsPerHost = cfg.MaxIdleConnsPerHost
+	}
+	if control != nil {
+		dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second, Control: control.Control}
+		t.DialContext = dialer.DialContext
+	}
+	return t
+}
+
+func newRegistry(cfg *Configuration, opts options) (*Registry, error) {
+	if opts.header == "" {
+		opts.header = middlewarefx.CorrelationIDHeader
+	}
+	for _, c := range opts.controls {
+		if _, ok := cfg.Clients[c.Client]; !ok {
+			return nil, fmt.Errorf("%w: dial control for %q", ErrUnknownClient, c.Client)
+		}
+	}
+	r := &Registry{clients: make(map[string]*http.Client, len(cfg.Clients))}
+	for name, clientCfg := range cfg.Clients {
+		c, err := newClient(name, clientCfg, opts)
+		if err != nil {
+			return nil, err
+		}
+		r.clients[name] = c
+	}
+	return r, nil
+}
+
+type Params struct {
+	fx.In
+	Lifecycle  fx.Lifecycle
+	Config     *Configuration
+	Logger     *slog.Logger
+	OTelConfig *otelfx.Configuration `optional:"true"`
+	Controls   []DialControl         `group:"httpclient_dial_controls"`
+}
+
+// NewRegistry builds every configured client. Spans are only recorded when
+// otelfx is present; metrics go to the global MeterProvider, which is a no-op
+// until otelfx installs one.
+func NewRegistry(p Params) (*Registry, error) {
+	r, err := newRegistry(p.Config, options{
+		header:   p.Config.CorrelationHeader,
+		tracing:  p.OTelConfig != nil,
+		meter:    otel.GetMeterProvider(),
+		base:     defaultBase,
+		controls: p.Controls,
+		now:      time.Now,
+		logger:   p.Logger,
+	})
+	if err != nil {
+		return nil, err
+	}
+	p.Lifecycle.Append(fx.StopHook(r.closeIdleConnections))
+	return r, nil
+}
+
+// Named provides the client called name as a `name:"<name>"` tagged
+// *http.Client, so consumers can depend on it directly.
+func Named(name string) fx.Option {
+	return fx.Provide(fx.Annotate(
+		func(r *Registry) (*http.Client, error) { return r.Client(name) },
+		fx.ResultTags(`name:"`+name+`"`),
+	))
+}
+
+var Module = fx.Module(
+	"httpclientfx",
+	fx.Provide(provideConfiguration, NewRegistry),
+)
@@ -0,0 +1,374 @@
+package httpclientfx
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/suite"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxevent"
+	"go.uber.org/fx/fxtest"
+
+	"github.com/bbsbb/go-edge/core/fx/middlewarefx"
+	coretesting "github.com/bbsbb/go-edge/core/testing"
+)
+
+type HTTPClientSuite struct {
+	suite.Suite
+	reader *sdkmetric.ManualReader
+	calls  atomic.Int32
+	now    time.Time
+}
+
+func (s *HTTPClientSuite) SetupTest() {
+	s.reader = sdkmetric.NewManualReader()
+	s.calls.Store(0)
+	s.now = time.Now()
+}
+
+// server counts calls and answers each with handler.
+func (s *HTTPClientSuite) server(handler http.HandlerFunc) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.calls.Add(1)
+		handler(w, r)
+	}))
+	s.T().Cleanup(srv.Close)
+	return srv
+}
+
+func (s *HTTPClientSuite) client(cfg ClientConfiguration) *http.Client {
+	c, err := newClient("upstream", cfg, options{
+		header: middlewarefx.CorrelationIDHeader,
+		meter:  sdkmetric.NewMeterProvider(sdkmetric.WithReader(s.reader)),
+		base:   defaultBase,
+		now:    func() time.Time { return s.now },
+		logger: coretesting.NewNoopLogger(),
+	})
+	s.Require().NoError(err)
+	return c
+}
+
+func (s *HTTPClientSuite) do(c *http.Client, method, url string, body io.Reader, header ...string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(context.Background(), method, url, body)
+	s.Require().NoError(err)
+	for i := 0; i+1 < len(header); i += 2 {
+		req.Header.Set(header[i], header[i+1])
+	}
+	resp, err := c.Do(req)
+	if err == nil {
+		s.T().Cleanup(func() { _ = resp.Body.Close() })
+	}
+	return resp, err
+}
+
+// counter returns the summed value of the named counter across attribute sets.
+func (s *HTTPClientSuite) counter(name string) int64 {
+	var rm metricdata.ResourceMetrics
+	s.Require().NoError(s.reader.Collect(context.Background(), &rm))
+	var total int64
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if sum, ok := m.Data.(metricdata.Sum[int64]); ok && m.Name == name {
+				for _, dp := range sum.DataPoints {
+					total += dp.Value
+				}
+			}
+		}
+	}
+	return total
+}
+
+func noRetry() RetryConfiguration { return RetryConfiguration{MaxAttempts: 1} }
+
+func (s *HTTPClientSuite) TestConfiguration_Validate() {
+	valid := &Configuration{Clients: map[string]ClientConfiguration{"payments": {Timeout: time.Second}}}
+	s.Assert().NoError(valid.Validate())
+
+	invalid := &Configuration{Clients: map[string]ClientConfiguration{
+		"payments": {Retry: RetryConfiguration{MaxAttempts: 11}, Timeout: -time.Second},
+	}}
+	err := invalid.Validate()
+	var validationErrs validator.ValidationErrors
+	s.Require().ErrorAs(err, &validationErrs)
+	fields := make([]string, len(validationErrs))
+	for i, fe := range validationErrs {
+		fields[i] = fe.Field()
+	}
+	s.Assert().ElementsMatch([]string{"MaxAttempts", "Timeout"}, fields)
+}
+
+func (s *HTTPClientSuite) TestDefaults() {
+	cfg := ClientConfiguration{Breaker: BreakerConfiguration{FailureThreshold: -1}}.withDefaults()
+
+	s.Assert().Equal(30*time.Second, cfg.Timeout)
+	s.Assert().Equal(3, cfg.Retry.MaxAttempts)
+	s.Assert().Equal(100*time.Millisecond, cfg.Retry.MinDelay)
+	s.Assert().Equal(2*time.Second, cfg.Retry.MaxDelay)
+	s.Assert().Equal(-1, cfg.Breaker.FailureThreshold, "a disabled breaker stays disabled")
+	s.Assert().Equal(30*time.Second, cfg.Breaker.OpenTimeout)
+	s.Assert().Equal(1, cfg.Breaker.HalfOpenRequests)
+}
+
+func (s *HTTPClientSuite) TestBackoff() {
+	r := RetryConfiguration{MinDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+
+	for attempt, ceiling := range []time.Duration{
+		100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond,
+		800 * time.Millisecond, time.Second, time.Second,
+	} {
+		for range 20 {
+			d := r.backoff(attempt)
+			s.Assert().GreaterOrEqual(d, ceiling/2)
+			s.Assert().LessOrEqual(d, ceiling)
+		}
+	}
+}
+
+func (s *HTTPClientSuite) TestPropagatesCorrelationID() {
+	var got []string
+	srv := s.server(func(_ http.ResponseWriter, r *http.Request) {
+		got = append(got, r.Header.Get(middlewarefx.CorrelationIDHeader))
+	})
+	c := s.client(ClientConfiguration{})
+
+	ctx := middlewarefx.ContextWithCorrelationID(context.Background(), "corr-1")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+	s.Require().NoError(err)
+	resp, err := c.Do(req)
+	s.Require().NoError(err)
+	s.Require().NoError(resp.Body.Close())
+
+	req.Header.Set(middlewarefx.CorrelationIDHeader, "explicit")
+	resp, err = c.Do(req)
+	s.Require().NoError(err)
+	s.Require().NoError(resp.Body.Close())
+
+	s.Assert().Equal([]string{"corr-1", "explicit"}, got)
+}
+
+func (s *HTTPClientSuite) TestRetriesIdempotentRequests() {
+	srv := s.server(func(w http.ResponseWriter, _ *http.Request) {
+		if s.calls.Load() < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = io.WriteString(w, "ok")
+	})
+	c := s.client(ClientConfiguration{Retry: RetryConfiguration{MinDelay: time.Millisecond, MaxDelay: time.Millisecond}})
+
+	resp, err := s.do(c, http.MethodGet, srv.URL, nil)
+
+	s.Require().NoError(err)
+	s.Assert().Equal(http.StatusOK, resp.StatusCode)
+	s.Assert().Equal(int32(3), s.calls.Load())
+	s.Assert().Equal(int64(2), s.counter("http.client.retries"))
+}
+
+func (s *HTTPClientSuite) TestGivesUpAfterMaxAttempts() {
+	srv := s.server(func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusBadGateway) })
+	c := s.client(ClientConfiguration{Retry: RetryConfiguration{MaxAttempts: 2, MinDelay: time.Millisecond}})
+
+	resp, err := s.do(c, http.MethodGet, srv.URL, nil)
+
+	s.Require().NoError(err)
+	s.Assert().Equal(http.StatusBadGateway, resp.StatusCode)
+	s.Assert().Equal(int32(2), s.calls.Load())
+}
+
+func (s *HTTPClientSuite) TestDoesNotRetryNonIdempotentRequests() {
+	srv := s.server(func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusServiceUnavailable) })
+	c := s.client(ClientConfiguration{Retry: RetryConfiguration{MinDelay: time.Millisecond}})
+
+	resp, err := s.do(c, http.MethodPost, srv.URL, strings.NewReader("{}"))
+
+	s.Require().NoError(err)
+	s.Assert().Equal(http.StatusServiceUnavailable, resp.StatusCode)
+	s.Assert().Equal(int32(1), s.calls.Load())
+}
+
+func (s *HTTPClientSuite) TestRetriesPostWithIdempotencyKeyReplayingBody() {
+	var bodies []string
+	srv := s.server(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(b))
+		if s.calls.Load() == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+		}
+	})
+	c := s.client(ClientConfiguration{Retry: RetryConfiguration{MinDelay: time.Millisecond, MaxDelay: time.Millisecond}})
+
+	resp, err := s.do(c, http.MethodPost, srv.URL, strings.NewReader(`{"amount":1}`), "Idempotency-Key", "k1")
+
+	s.Require().NoError(err)
+	s.Assert().Equal(http.StatusOK, resp.StatusCode)
+	s.Assert().Equal([]string{`{"amount":1}`, `{"amount":1}`}, bodies)
+}
+
+func (s *HTTPClientSuite) TestDoesNotRetryClientErrors() {
+	srv := s.server(func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusBadRequest) })
+	c := s.client(ClientConfiguration{})
+
+	resp, err := s.do(c, http.MethodGet, srv.URL, nil)
+
+	s.Require().NoError(err)
+	s.Assert().Equal(http.StatusBadRequest, resp.StatusCode)
+	s.Assert().Equal(int32(1), s.calls.Load())
+}
+
+func (s *HTTPClientSuite) TestTimeout() {
+	srv := s.server(func(_ http.ResponseWriter, r *http.Request) { <-r.Context().Done() })
+	c := s.client(ClientConfiguration{Timeout: 50 * time.Millisecond, Retry: noRetry()})
+
+	_, err := s.do(c, http.MethodGet, srv.URL, nil)
+
+	s.Assert().ErrorIs(err, context.DeadlineExceeded)
+}
+
+func (s *HTTPClientSuite) TestCircuitOpensAndProbes() {
+	healthy := atomic.Bool{}
+	srv := s.server(func(w http.ResponseWriter, _ *http.Request) {
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	})
+	c := s.client(ClientConfiguration{
+		Retry:   noRetry(),
+		Breaker: BreakerConfiguration{FailureThreshold: 2, OpenTimeout: time.Minute},
+	})
+
+	for range 2 {
+		resp, err := s.do(c, http.MethodGet, srv.URL, nil)
+		s.Require().NoError(err)
+		s.Require().Equal(http.StatusInternalServerError, resp.StatusCode)
+	}
+
+	_, err := s.do(c, http.MethodGet, srv.URL, nil)
+	s.Require().ErrorIs(err, ErrCircuitOpen)
+	s.Assert().Equal(int32(2), s.calls.Load(), "an open circuit must not reach the upstream")
+	s.Assert().Equal(int64(1), s.counter("http.client.circuit.rejections"))
+
+	// A failing probe reopens the circuit.
+	s.now = s.now.Add(time.Minute)
+	_, err = s.do(c, http.MethodGet, srv.URL, nil)
+	s.Require().NoError(err)
+	_, err = s.do(c, http.MethodGet, srv.URL, nil)
+	s.Require().ErrorIs(err, ErrCircuitOpen)
+
+	// A successful probe closes it.
+	healthy.Store(true)
+	s.now = s.now.Add(time.Minute)
+	for range 3 {
+		resp, err := s.do(c, http.MethodGet, srv.URL, nil)
+		s.Require().NoError(err)
+		s.Assert().Equal(http.StatusOK, resp.StatusCode)
+	}
+	s.Assert().Equal(int64(5), s.counter("http.client.circuit.transitions"))
+}
+
+func (s *HTTPClientSuite) TestCircuitIsPerHost() {
+	failing := s.server(func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusBadGateway) })
+	other := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	s.T().Cleanup(other.Close)
+	c := s.client(ClientConfiguration{Retry: noRetry(), Breaker: BreakerConfiguration{FailureThreshold: 1}})
+
+	_, err := s.do(c, http.MethodGet, failing.URL, nil)
+	s.Require().NoError(err)
+	_, err = s.do(c, http.MethodGet, failing.URL, nil)
+	s.Require().ErrorIs(err, ErrCircuitOpen)
+
+	resp, err := s.do(c, http.MethodGet, other.URL, nil)
+	s.Require().NoError(err)
+	s.Assert().Equal(http.StatusOK, resp.StatusCode)
+}
+
+func (s *HTTPClientSuite) TestOpenCircuitIsNotRetried() {
+	srv := s.server(func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusServiceUnavailable) })
+	c := s.client(ClientConfiguration{
+		Retry:   RetryConfiguration{MaxAttempts: 5, MinDelay: time.Millisecond, MaxDelay: time.Millisecond},
+		Breaker: BreakerConfiguration{FailureThreshold: 2},
+	})
+
+	_, err := s.do(c, http.MethodGet, srv.URL, nil)
+
+	s.Require().ErrorIs(err, ErrCircuitOpen)
+	s.Assert().Equal(int32(2), s.calls.Load())
+}
+
+type testAppConfig struct {
+	cfg *Configuration
+}
+
+func (c *testAppConfig) HTTPClientConfiguration() *Configuration { return c.cfg }
+
+func (s *HTTPClientSuite) TestModule() {
+	srv := s.server(func(http.ResponseWriter, *http.Request) {})
+	var client *http.Client
+
+	app := fxtest.New(s.T(),
+		fx.Supply(
+			coretesting.NewNoopLogger(),
+			fx.Annotate(
+				&testAppConfig{cfg: &Configuration{Clients: map[string]ClientConfiguration{"payments": {}}}},
+				fx.As(new(WithHTTPClient)),
+			),
+		),
+		Module,
+		Named("payments"),
+		fx.Invoke(fx.Annotate(func(c *http.Client) { client = c }, fx.ParamTags(`name:"payments"`))),
+		fx.Invoke(func(r *Registry) {
+			_, err := r.Client("unknown")
+			s.Assert().ErrorIs(err, ErrUnknownClient)
+		}),
+		fx.WithLogger(func() fxevent.Logger { return fxevent.NopLogger }),
+	)
+	app.RequireStart()
+	defer app.RequireStop()
+
+	resp, err := s.do(client, http.MethodGet, srv.URL, nil)
+	s.Require().NoError(err)
+	s.Assert().Equal(http.StatusOK, resp.StatusCode)
+}
+
+func (s *HTTPClientSuite) TestDialControlRefusesConnections() {
+	srv := s.server(func(http.ResponseWriter, *http.Request) {})
+	refused := errors.New("refused")
+	var dialed string
+	c, err := newClient("upstream", ClientConfiguration{Retry: noRetry()}, options{
+		meter: sdkmetric.NewMeterProvider(sdkmetric.WithReader(s.reader)),
+		base:  defaultBase,
+		controls: []DialControl{{Client: "upstream", Control: func(_, address string, _ syscall.RawConn) error {
+			dialed = address
+			return refused
+		}}},
+		now:    time.Now,
+		logger: coretesting.NewNoopLogger(),
+	})
+	s.Require().NoError(err)
+
+	_, err = s.do(c, http.MethodGet, srv.URL, nil)
+
+	s.Assert().ErrorIs(err, refused)
+	s.Assert().Equal(srv.Listener.Addr().String(), dialed)
+	s.Assert().Zero(s.calls.Load())
+}
+
+func (s *HTTPClientSuite) TestDialControlForUnknownClient() {
+	_, err := newRegistry(&Configuration{}, options{controls: []DialControl{{Client: "payments"}}})
+
+	s.Assert().ErrorIs(err, ErrUnknownClient)
+}
+
+func TestHTTPClientSuite(t *testing.T) {
+	suite.Run(t, new(HTTPClientSuite))
+}
@@ -0,0 +1,219 @@
+package httpclientfx
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	"github.com/bbsbb/go-edge/core/fx/middlewarefx"
+)
+
+// instruments are the metrics recorded alongside otelhttp's, which already
+// cover request duration and body sizes per server.address.
+type instruments struct {
+	retries     metric.Int64Counter
+	rejections  metric.Int64Counter
+	transitions metric.Int64Counter
+}
+
+func newInstruments(meter metric.Meter) (*instruments, error) {
+	retries, err := meter.Int64Counter("http.client.retries",
+		metric.WithDescription("Retried outbound HTTP requests"))
+	if err != nil {
+		return nil, err
+	}
+	rejections, err := meter.Int64Counter("http.client.circuit.rejections",
+		metric.WithDescription("Outbound HTTP requests rejected by an open circuit"))
+	if err != nil {
+		return nil, err
+	}
+	transitions, err := meter.Int64Counter("http.client.circuit.transitions",
+		metric.WithDescription("Circuit breaker state changes"))
+	if err != nil {
+		return nil, err
+	}
+	return &instruments{retries: retries, rejections: rejections, transitions: transitions}, nil
+}
+
+func hostAttributes(client, host string) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.String("http.client.name", client),
+		attribute.String("server.address", host),
+	}
+}
+
+// correlationTransport forwards the correlation ID of the inbound request.
+type correlationTransport struct {
+	header string
+	next   http.RoundTripper
+}
+
+func (t *correlationTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	id := middlewarefx.CorrelationIDFromContext(req.Context())
+	if id == "" || req.Header.Get(t.header) != "" {
+		return t.next.RoundTrip(req)
+	}
+	req = req.Clone(req.Context())
+	req.Header.Set(t.header, id)
+	return t.next.RoundTrip(req)
+}
+
+// retryTransport retries idempotent requests that failed in transit or were
+// answered with 429, 502, 503 or 504.
+type retryTransport struct {
+	name    string
+	cfg     RetryConfiguration
+	next    http.RoundTripper
+	metrics *instruments
+	logger  *slog.Logger
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.cfg.MaxAttempts <= 1 || !isRetryable(req) {
+		return t.next.RoundTrip(req)
+	}
+
+	ctx := req.Context()
+	for attempt := 0; ; attempt++ {
+		attemptReq := req
+		if attempt > 0 {
+			var err error
+			if attemptReq, err = rewind(req); err != nil {
+				return nil, err
+			}
+		}
+
+		resp, err := t.next.RoundTrip(attemptReq)
+		if attempt+1 >= t.cfg.MaxAttempts || ctx.Err() != nil || !shouldRetry(resp, err) {
+			return resp, err
+		}
+
+		delay := t.cfg.backoff(attempt)
+		if resp != nil {
+			delay = max(delay, min(retryAfter(resp), t.cfg.MaxDelay))
+			discard(resp)
+		}
+		t.metrics.retries.Add(ctx, 1, metric.WithAttributes(hostAttributes(t.name, req.URL.Host)...))
+		t.logger.DebugContext(ctx, "retrying outbound request",
+			"client", t.name, "host", req.URL.Host, "method", req.Method,
+			"attempt", attempt+1, "delay", delay, "status", statusOf(resp), "error", err)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// isRetryable follows net/http's own rule: idempotent methods, or any request
+// carrying an Idempotency-Key, provided the body can be replayed.
+func isRetryable(req *http.Request) bool {
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return req.Header.Get("Idempotency-Key") != "" || req.Header.Get("X-Idempotency-Key") != ""
+}
+
+func shouldRetry(resp *http.Response, err error) bool {
+	if err != nil {
+		return !errors.Is(err, ErrCircuitOpen)
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+func rewind(req *http.Request) (*http.Request, error) {
+	clone := req.Clone(req.Context())
+	if req.Body != nil && req.Body != http.NoBody {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		clone.Body = body
+	}
+	return clone, nil
+}
+
+// retryAfter parses a Retry-After header given in seconds.
+func retryAfter(resp *http.Response) time.Duration {
+	secs, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+	if err != nil || secs < 0 {
+		return 0
+	}
+	return time.Duration(secs) * time.Second
+}
+
+// discard drains a bounded amount of the body so the connection can be reused.
+func discard(resp *http.Response) {
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4<<10))
+	_ = resp.Body.Close()
+}
+
+func statusOf(resp *http.Response) int {
+	if resp == nil {
+		return 0
+	}
+	return resp.StatusCode
+}
+
+// breakerTransport keeps one breaker per upstream host.
+type breakerTransport struct {
+	name    string
+	cfg     BreakerConfiguration
+	next    http.RoundTripper
+	metrics *instruments
+	logger  *slog.Logger
+	now     func() time.Time
+
+	mu       sync.Mutex
+	breakers map[string]*breaker
+}
+
+func (t *breakerTransport) breaker(host string) *breaker {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if b, ok := t.breakers[host]; ok {
+		return b
+	}
+	b := &breaker{cfg: t.cfg, now: t.now, onChange: func(to breakerState) {
+		t.metrics.transitions.Add(context.Background(), 1, metric.WithAttributes(
+			append(hostAttributes(t.name, host), attribute.String("state", to.String()))...))
+		t.logger.Warn("circuit breaker state changed", "client", t.name, "host", host, "state", to.String())
+	}}
+	t.breakers[host] = b
+	return b
+}
+
+func (t *breakerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	b := t.breaker(req.URL.Host)
+	probe, err := b.allow()
+	if err != nil {
+		t.metrics.rejections.Add(req.Context(), 1, metric.WithAttributes(hostAttributes(t.name, req.URL.Host)...))
+		if req.Body != nil {
+			_ = req.Body.Close()
+		}
+		return nil, err
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	b.record(probe, classify(req, resp, err))
+	return resp, err
+}
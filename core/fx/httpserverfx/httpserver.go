@@ -10,7 +10,6 @@ import (
 	"time"
 
 	"github.com/go-chi/chi/v5"
-	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/cors"
 	"github.com/go-playground/validator/v10"
 	"go.uber.org/fx"
@@ -94,7 +93,7 @@ func NewHTTPServer(p Params) (Result, error) {
 		}))
 	}
 
-	mux.Use(middleware.Timeout(time.Duration(p.Config.RequestTimeout) * time.Second))
+	mux.Use(Timeout(time.Duration(p.Config.RequestTimeout) * time.Second))
 
 	readHeaderTimeout := time.Duration(p.Config.ReadHeaderTimeout) * time.Second
 	if readHeaderTimeout == 0 {
@@ -138,6 +137,60 @@ func NewHTTPServer(p Params) (Result, error) {
 	return Result{Server: srv, Mux: mux}, nil
 }
 
+// Timeout bounds every request to d, answering 504 Gateway Timeout if the
+// handler is still running when it expires, like chi's middleware.Timeout.
+// Routes wrapped in NoTimeout are exempt.
+func Timeout(d time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, cancel := context.WithTimeout(r.Context(), d)
+			defer func() {
+				cancel()
+				if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+					w.WriteHeader(http.StatusGatewayTimeout)
+				}
+			}()
+			ctx = context.WithValue(ctx, untimedKey{}, untimed{parent: r.Context(), cancel: cancel})
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+type untimedKey struct{}
+
+// untimed is what NoTimeout needs to undo Timeout: the request's context
+// before the timeout was applied, and the timeout's cancel function.
+type untimed struct {
+	parent context.Context
+	cancel context.CancelFunc
+}
+
+// NoTimeout exempts the route it wraps from Timeout, for long-lived streams
+// that manage their own per-write deadlines. The request keeps the values of
+// its context and is still cancelled when the client goes away.
+func NoTimeout(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		u, ok := r.Context().Value(untimedKey{}).(untimed)
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+		u.cancel()
+		next.ServeHTTP(w, r.WithContext(untimedContext{Context: r.Context(), parent: u.parent}))
+	})
+}
+
+// untimedContext looks up values in the request's context but takes its
+// deadline and cancellation from the context Timeout started from.
+type untimedContext struct {
+	context.Context
+	parent context.Context
+}
+
+func (c untimedContext) Deadline() (time.Time, bool) { return c.parent.Deadline() }
+func (c untimedContext) Done() <-chan struct{}       { return c.parent.Done() }
+func (c untimedContext) Err() error                  { return c.parent.Err() }
+
 func provideConfiguration(cfg WithHTTPServer) *Configuration {
 	return cfg.HTTPServerConfiguration()
 }
@@ -1,12 +1,16 @@
 package httpserverfx
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/coder/websocket"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-playground/validator/v10"
 	"github.com/stretchr/testify/suite"
@@ -15,6 +19,7 @@ import (
 	"go.uber.org/fx/fxtest"
 
 	coretesting "github.com/bbsbb/go-edge/core/testing"
+	transporthttp "github.com/bbsbb/go-edge/core/transport/http"
 )
 
 type HTTPServerSuite struct {
@@ -203,6 +208,134 @@ func (s *IntegrationSuite) TestRequestTimeout() {
 	s.Assert().Equal(http.StatusGatewayTimeout, rr.Code)
 }
 
+func (s *IntegrationSuite) TestNoTimeoutRouteIsExempt() {
+	s.mux.With(NoTimeout).Get("/stream", func(w http.ResponseWriter, r *http.Request) {
+		stream, err := transporthttp.NewSSEStream(w, r, transporthttp.SSEOptions{})
+		if !s.Assert().NoError(err) {
+			return
+		}
+		select {
+		case <-r.Context().Done():
+			return
+		case <-time.After(1500 * time.Millisecond):
+		}
+		_ = stream.Send(transporthttp.SSEEvent{ID: "1", Data: []byte("late")})
+	})
+
+	srv := httptest.NewServer(s.mux)
+	defer srv.Close()
+
+	req, err := http.NewRequest(http.MethodGet, srv.URL+"/stream", nil)
+	s.Require().NoError(err)
+	req.Header.Set("Accept", transporthttp.MediaTypeEventStream)
+
+	resp, err := http.DefaultClient.Do(req)
+	s.Require().NoError(err)
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(resp.Body)
+	s.Require().NoError(err)
+	s.Assert().Equal(http.StatusOK, resp.StatusCode)
+	s.Assert().Equal("id: 1\ndata: late\n\n", string(body))
+}
+
+func (s *IntegrationSuite) TestEventStreamOnTimedRouteTimesOut() {
+	s.mux.Get("/slow", func(_ http.ResponseWriter, _ *http.Request) {
+		time.Sleep(2 * time.Second)
+	})
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/slow", nil)
+	req.Header.Set("Accept", transporthttp.MediaTypeEventStream)
+	s.mux.ServeHTTP(rr, req)
+
+	s.Assert().Equal(http.StatusGatewayTimeout, rr.Code)
+}
+
+func (s *IntegrationSuite) TestNoTimeoutKeepsValuesAndCancellation() {
+	type key struct{}
+	var (
+		value    any
+		deadline bool
+	)
+	s.mux.With(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), key{}, "set")))
+		})
+	}, NoTimeout).Get("/stream", func(_ http.ResponseWriter, r *http.Request) {
+		value = r.Context().Value(key{})
+		_, deadline = r.Context().Deadline()
+		<-r.Context().Done()
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequestWithContext(ctx, http.MethodGet, "/stream", nil)
+	time.AfterFunc(50*time.Millisecond, cancel)
+	s.mux.ServeHTTP(rr, req)
+
+	s.Assert().Equal("set", value)
+	s.Assert().False(deadline)
+	s.Assert().Equal(http.StatusOK, rr.Code)
+}
+
+func (s *IntegrationSuite) TestNoTimeoutWebSocketRouteIsExempt() {
+	s.mux.With(NoTimeout).Get("/ws", func(w http.ResponseWriter, r *http.Request) {
+		conn, err := websocket.Accept(w, r, nil)
+		if !s.Assert().NoError(err) {
+			return
+		}
+		defer func() { _ = conn.CloseNow() }()
+		select {
+		case <-r.Context().Done():
+			return
+		case <-time.After(1500 * time.Millisecond):
+		}
+		_ = conn.Write(r.Context(), websocket.MessageText, []byte("late"))
+	})
+
+	srv := httptest.NewServer(s.mux)
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	conn, _, err := websocket.Dial(ctx, "ws"+strings.TrimPrefix(srv.URL, "http")+"/ws", nil)
+	s.Require().NoError(err)
+	defer func() { _ = conn.CloseNow() }()
+
+	_, data, err := conn.Read(ctx)
+	s.Require().NoError(err)
+	s.Assert().Equal("late", string(data))
+}
+
+func (s *IntegrationSuite) TestWebSocketOnTimedRouteTimesOut() {
+	s.mux.Get("/ws", func(w http.ResponseWriter, r *http.Request) {
+		conn, err := websocket.Accept(w, r, nil)
+		if !s.Assert().NoError(err) {
+			return
+		}
+		defer func() { _ = conn.CloseNow() }()
+		select {
+		case <-r.Context().Done():
+			return
+		case <-time.After(1500 * time.Millisecond):
+		}
+		_ = conn.Write(r.Context(), websocket.MessageText, []byte("late"))
+	})
+
+	srv := httptest.NewServer(s.mux)
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	conn, _, err := websocket.Dial(ctx, "ws"+strings.TrimPrefix(srv.URL, "http")+"/ws", nil)
+	s.Require().NoError(err)
+	defer func() { _ = conn.CloseNow() }()
+
+	_, _, err = conn.Read(ctx)
+	s.Assert().Error(err)
+}
+
 type CorsIntegrationSuite struct {
 	suite.Suite
 	mux     *chi.Mux
@@ -24,6 +24,15 @@ type MaxBytesConfig struct {
 	MaxBytes int64 `yaml:"max_bytes" env:"MAX_REQUEST_BODY_BYTES,overwrite" validate:"gte=0"`
 }
 
+// Limit returns the effective body size limit, which also bounds inbound
+// WebSocket messages (see transport/http/ws.Options).
+func (c MaxBytesConfig) Limit() int64 {
+	if c.MaxBytes <= 0 {
+		return defaultMaxBytes
+	}
+	return c.MaxBytes
+}
+
 type RequestIDConfig struct {
 	Enabled bool `yaml:"enabled" env:"ENABLE_REQUEST_ID,overwrite"`
 }
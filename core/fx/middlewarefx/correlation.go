@@ -31,7 +31,7 @@ func CorrelationID(cfg CorrelationIDConfig) func(http.Handler) http.Handler {
 				id = uuid.Must(uuid.NewV7()).String()
 			}
 
-			ctx := context.WithValue(r.Context(), correlationIDKey{}, id)
+			ctx := ContextWithCorrelationID(r.Context(), id)
 			if span := trace.SpanFromContext(ctx); span.IsRecording() {
 				span.SetAttributes(attribute.String("correlation.id", id))
 			}
@@ -41,6 +41,12 @@ func CorrelationID(cfg CorrelationIDConfig) func(http.Handler) http.Handler {
 	}
 }
 
+// ContextWithCorrelationID stores a correlation ID in context. Non-HTTP
+// transports use it so CorrelationIDFromContext works regardless of entry point.
+func ContextWithCorrelationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationIDKey{}, id)
+}
+
 // CorrelationIDFromContext extracts the correlation ID from context.
 func CorrelationIDFromContext(ctx context.Context) string {
 	if id, ok := ctx.Value(correlationIDKey{}).(string); ok {
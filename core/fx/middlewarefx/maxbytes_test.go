@@ -65,6 +65,11 @@ func (s *MaxBytesSuite) TestDefaultsToOneMBWhenZero() {
 	s.Assert().Equal(http.StatusOK, rec.Code)
 }
 
+func (s *MaxBytesSuite) TestConfigLimit() {
+	s.Assert().Equal(int64(1<<20), MaxBytesConfig{}.Limit())
+	s.Assert().Equal(int64(512), MaxBytesConfig{MaxBytes: 512}.Limit())
+}
+
 func TestMaxBytesSuite(t *testing.T) {
 	suite.Run(t, new(MaxBytesSuite))
 }
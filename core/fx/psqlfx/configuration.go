@@ -18,6 +18,7 @@ var validate = validator.New(validator.WithRequiredStructEnabled())
 var (
 	_ configuration.WithValidation = (*Credentials)(nil)
 	_ configuration.WithValidation = (*PoolConfiguration)(nil)
+	_ configuration.WithValidation = (*ListenerConfiguration)(nil)
 	_ configuration.WithValidation = (*Configuration)(nil)
 )
 
@@ -56,13 +57,39 @@ func DefaultPoolConfiguration() *PoolConfiguration {
 	}
 }
 
+// ListenerConfiguration tunes the dedicated LISTEN connection held by a Listener.
+type ListenerConfiguration struct {
+	ReconnectMinDelay time.Duration `yaml:"reconnect_min_delay" env:"RECONNECT_MIN_DELAY,overwrite" validate:"gte=0"`
+	ReconnectMaxDelay time.Duration `yaml:"reconnect_max_delay" env:"RECONNECT_MAX_DELAY,overwrite" validate:"gte=0"`
+	PingInterval      time.Duration `yaml:"ping_interval" env:"PING_INTERVAL,overwrite" validate:"gte=0"`
+	// PayloadTable is the qualified name of the table holding payloads above
+	// MaxNotifyPayload, which are sent by reference. Empty disables it. See
+	// Listener.Notify for the expected columns.
+	PayloadTable     string        `yaml:"payload_table" env:"PAYLOAD_TABLE,overwrite"`
+	PayloadRetention time.Duration `yaml:"payload_retention" env:"PAYLOAD_RETENTION,overwrite" validate:"gte=0"`
+}
+
+func (l *ListenerConfiguration) Validate() error {
+	return validate.Struct(l)
+}
+
+func DefaultListenerConfiguration() *ListenerConfiguration {
+	return &ListenerConfiguration{
+		ReconnectMinDelay: 500 * time.Millisecond,
+		ReconnectMaxDelay: 30 * time.Second,
+		PingInterval:      30 * time.Second,
+		PayloadRetention:  time.Hour,
+	}
+}
+
 type Configuration struct {
-	Host        string             `yaml:"host" env:"HOST,overwrite" validate:"required,hostname"`
-	Port        uint16             `yaml:"port" env:"PORT,overwrite" validate:"required,gte=1,lte=65535"`
-	Database    string             `yaml:"database" env:"DATABASE,overwrite" validate:"required"`
-	Credentials *Credentials       `yaml:"credentials" env:"CREDENTIALS,overwrite,noinit" validate:"required"`
-	DisableSSL  bool               `yaml:"disable_ssl" env:"DISABLE_SSL,overwrite"`
-	Pool        *PoolConfiguration `yaml:"pool" env:"POOL,overwrite"`
+	Host        string                 `yaml:"host" env:"HOST,overwrite" validate:"required,hostname"`
+	Port        uint16                 `yaml:"port" env:"PORT,overwrite" validate:"required,gte=1,lte=65535"`
+	Database    string                 `yaml:"database" env:"DATABASE,overwrite" validate:"required"`
+	Credentials *Credentials           `yaml:"credentials" env:"CREDENTIALS,overwrite,noinit" validate:"required"`
+	DisableSSL  bool                   `yaml:"disable_ssl" env:"DISABLE_SSL,overwrite"`
+	Pool        *PoolConfiguration     `yaml:"pool" env:"POOL,overwrite"`
+	Listener    *ListenerConfiguration `yaml:"listener" env:"LISTENER,overwrite"`
 }
 
 func (c *Configuration) Validate() error {
@@ -254,6 +254,23 @@ func (s *ConfigurationSuite) TestDefaultPoolConfiguration() {
 	s.Assert().Equal(time.Hour, pool.ConnMaxLifetime)
 }
 
+func (s *ConfigurationSuite) TestDefaultListenerConfiguration() {
+	listener := DefaultListenerConfiguration()
+
+	s.Assert().Equal(500*time.Millisecond, listener.ReconnectMinDelay)
+	s.Assert().Equal(30*time.Second, listener.ReconnectMaxDelay)
+	s.Assert().Equal(30*time.Second, listener.PingInterval)
+	s.Assert().Empty(listener.PayloadTable)
+	s.Assert().Equal(time.Hour, listener.PayloadRetention)
+}
+
+func (s *ConfigurationSuite) TestListenerConfiguration_Validate() {
+	s.Assert().NoError((&ListenerConfiguration{}).Validate())
+	s.Assert().NoError(DefaultListenerConfiguration().Validate())
+	s.Assert().Error((&ListenerConfiguration{ReconnectMinDelay: -1}).Validate())
+	s.Assert().Error((&ListenerConfiguration{PingInterval: -1}).Validate())
+}
+
 func (s *ConfigurationSuite) TestPoolConfiguration_Validate() {
 	tests := []struct {
 		name    string
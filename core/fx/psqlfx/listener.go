@@ -0,0 +1,498 @@
+package psqlfx
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.uber.org/fx"
+)
+
+// MaxNotifyPayload is the largest payload, in bytes, Postgres accepts for NOTIFY.
+const MaxNotifyPayload = 7999
+
+// PayloadRefPrefix marks a NOTIFY payload that carries the ID of a row in the
+// payload table instead of the payload itself. Triggers can send references in
+// the same format: pg_notify(channel, 'psqlfx:ref:' || id).
+const PayloadRefPrefix = "psqlfx:ref:"
+
+const listenerQueryTimeout = 5 * time.Second
+
+var (
+	ErrListenerNotConnected = errors.New("psqlfx: listener not connected")
+	ErrPayloadTooLarge      = errors.New("psqlfx: notification payload too large and no payload table configured")
+)
+
+// DBTX is satisfied by pgx.Tx, *pgx.Conn and *pgxpool.Pool.
+type DBTX interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+}
+
+// Notification is a payload received on a channel. Payloads sent by reference
+// are resolved before delivery.
+type Notification struct {
+	Channel string
+	Payload []byte
+}
+
+// Subscription receives the notifications of one channel on C. C is closed
+// when the subscription is closed, when the Listener stops, or when the
+// subscriber lets its buffer fill up.
+type Subscription struct {
+	C <-chan Notification
+
+	listener *Listener
+	channel  string
+	ch       chan Notification
+}
+
+// Close unsubscribes and closes C. It is safe to call more than once.
+func (s *Subscription) Close() {
+	s.listener.remove(s)
+}
+
+// Listener holds a dedicated connection outside the pool, LISTENs on every
+// channel that has a subscriber and fans notifications out to Go channels.
+// A lost connection is re-established with jittered exponential backoff and
+// every channel is LISTENed again.
+//
+// Delivery is at most once: notifications sent while disconnected are lost.
+// Subscribers that cache or replay state should resync from OnReconnect.
+type Listener struct {
+	connect func(ctx context.Context) (*pgx.Conn, error)
+	cfg     *ListenerConfiguration
+	logger  *slog.Logger
+
+	mu          sync.Mutex
+	subs        map[string]map[*Subscription]struct{}
+	dirty       bool
+	interrupt   context.CancelFunc
+	onReconnect []func()
+
+	// listening is owned by the goroutine holding the connection.
+	listening map[string]bool
+	connected atomic.Bool
+	cancel    context.CancelFunc
+	done      chan struct{}
+}
+
+type ListenerParams struct {
+	fx.In
+	Lifecycle fx.Lifecycle
+	Config    *Configuration
+	Defaults  *ConnectionDefaults `optional:"true"`
+	Logger    *slog.Logger
+}
+
+func NewListener(p ListenerParams) (*Listener, error) {
+	dsn, err := connString(p.Config, p.Defaults)
+	if err != nil {
+		return nil, err
+	}
+	connConfig, err := pgx.ParseConfig(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("psqlfx: parse listener config: %w", err)
+	}
+
+	l := newListener(func(ctx context.Context) (*pgx.Conn, error) {
+		return pgx.ConnectConfig(ctx, connConfig)
+	}, p.Config.Listener, p.Logger)
+
+	p.Lifecycle.Append(fx.Hook{OnStart: l.Start, OnStop: l.Stop})
+	return l, nil
+}
+
+func newListener(connect func(ctx context.Context) (*pgx.Conn, error), cfg *ListenerConfiguration, logger *slog.Logger) *Listener {
+	return &Listener{
+		connect: connect,
+		cfg:     withListenerDefaults(cfg),
+		logger:  logger,
+		subs:    make(map[string]map[*Subscription]struct{}),
+	}
+}
+
+func withListenerDefaults(cfg *ListenerConfiguration) *ListenerConfiguration {
+	def := DefaultListenerConfiguration()
+	if cfg == nil {
+		return def
+	}
+	c := *cfg
+	if c.ReconnectMinDelay <= 0 {
+		c.ReconnectMinDelay = def.ReconnectMinDelay
+	}
+	if c.ReconnectMaxDelay < c.ReconnectMinDelay {
+		c.ReconnectMaxDelay = max(def.ReconnectMaxDelay, c.ReconnectMinDelay)
+	}
+	if c.PingInterval <= 0 {
+		c.PingInterval = def.PingInterval
+	}
+	if c.PayloadRetention <= 0 {
+		c.PayloadRetention = def.PayloadRetention
+	}
+	return &c
+}
+
+// Listen subscribes to channel with a buffer of the given size. It may be
+// called before or after Start.
+func (l *Listener) Listen(channel string, buffer int) (*Subscription, error) {
+	if channel == "" {
+		return nil, errors.New("psqlfx: listen: empty channel name")
+	}
+	ch := make(chan Notification, max(buffer, 1))
+	sub := &Subscription{C: ch, listener: l, channel: channel, ch: ch}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.subs[channel] == nil {
+		l.subs[channel] = make(map[*Subscription]struct{})
+		l.wakeLocked()
+	}
+	l.subs[channel][sub] = struct{}{}
+	return sub, nil
+}
+
+// Subscribe listens on channel and decodes each JSON payload into T.
+// Undecodable payloads are logged and skipped. The returned channel closes
+// with the underlying subscription; call the returned function to close it.
+func Subscribe[T any](l *Listener, channel string, buffer int) (<-chan T, func(), error) {
+	sub, err := l.Listen(channel, buffer)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		for n := range sub.C {
+			var v T
+			if err := json.Unmarshal(n.Payload, &v); err != nil {
+				l.logger.Error("invalid notification payload", "channel", channel, "error", err)
+				continue
+			}
+			out <- v
+		}
+	}()
+	return out, func() {
+		sub.Close()
+		// Unblock the decoder in case nobody reads out anymore.
+		for range out {
+		}
+	}, nil
+}
+
+// OnReconnect registers fn to run after the connection was re-established and
+// every channel is LISTENed again.
+func (l *Listener) OnReconnect(fn func()) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.onReconnect = append(l.onReconnect, fn)
+}
+
+// Check reports whether the listener is connected, for readiness probes.
+func (l *Listener) Check(context.Context) error {
+	if !l.connected.Load() {
+		return ErrListenerNotConnected
+	}
+	return nil
+}
+
+// Notify sends payload on channel through db, typically the transaction that
+// made the change so the notification is only delivered on commit. A payload
+// above MaxNotifyPayload is written to the configured payload table and sent as
+// a PayloadRefPrefix reference. The table needs the columns
+// id UUID PRIMARY KEY, channel TEXT, payload BYTEA and
+// created_at TIMESTAMPTZ DEFAULT now(); rows older than PayloadRetention are
+// pruned on every write.
+func (l *Listener) Notify(ctx context.Context, db DBTX, channel string, payload []byte) error {
+	msg := string(payload)
+	if len(payload) > MaxNotifyPayload {
+		if l.cfg.PayloadTable == "" {
+			return ErrPayloadTooLarge
+		}
+		id, err := uuid.NewV7()
+		if err != nil {
+			return fmt.Errorf("psqlfx: notify: %w", err)
+		}
+		table := payloadTableIdentifier(l.cfg.PayloadTable)
+		query := fmt.Sprintf( //nolint:gosec // identifier from trusted config, properly quoted
+			"WITH pruned AS (DELETE FROM %s WHERE created_at < now() - make_interval(secs => $4)) "+
+				"INSERT INTO %s (id, channel, payload) VALUES ($1, $2, $3)",
+			table, table,
+		)
+		if _, err := db.Exec(ctx, query, id, channel, payload, l.cfg.PayloadRetention.Seconds()); err != nil {
+			return fmt.Errorf("psqlfx: store notification payload: %w", err)
+		}
+		msg = PayloadRefPrefix + id.String()
+	}
+
+	if _, err := db.Exec(ctx, "SELECT pg_notify($1, $2)", channel, msg); err != nil {
+		return fmt.Errorf("psqlfx: notify: %w", err)
+	}
+	return nil
+}
+
+// Start connects and LISTENs on the subscribed channels, failing fast when
+// the database is unreachable, then keeps the connection alive in the background.
+func (l *Listener) Start(ctx context.Context) error {
+	conn, err := l.open(ctx)
+	if err != nil {
+		return fmt.Errorf("psqlfx: start listener: %w", err)
+	}
+
+	runCtx, cancel := context.WithCancel(context.Background())
+	l.cancel = cancel
+	l.done = make(chan struct{})
+	go l.run(runCtx, conn)
+	return nil
+}
+
+// Stop closes the connection and every subscription.
+func (l *Listener) Stop(ctx context.Context) error {
+	if l.cancel == nil {
+		return nil
+	}
+	l.cancel()
+	select {
+	case <-l.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, subs := range l.subs {
+		for sub := range subs {
+			l.removeLocked(sub)
+		}
+	}
+	return nil
+}
+
+func (l *Listener) run(ctx context.Context, conn *pgx.Conn) {
+	defer close(l.done)
+	attempt := 0
+	for {
+		if conn != nil {
+			err := l.serve(ctx, conn)
+			l.connected.Store(false)
+			closeListenerConn(conn)
+			conn = nil
+			if ctx.Err() != nil {
+				return
+			}
+			l.logger.Warn("postgres listener disconnected", "error", err)
+			attempt = 0
+		}
+
+		delay := l.backoff(attempt)
+		attempt++
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+
+		c, err := l.open(ctx)
+		if err != nil {
+			l.logger.Warn("postgres listener reconnect failed", "error", err, "attempt", attempt)
+			continue
+		}
+		conn = c
+		l.logger.Info("postgres listener reconnected", "attempts", attempt)
+		l.mu.Lock()
+		hooks := append([]func(){}, l.onReconnect...)
+		l.mu.Unlock()
+		for _, fn := range hooks {
+			fn()
+		}
+	}
+}
+
+// backoff doubles the delay per attempt up to the maximum and picks a random
+// point in its upper half, so replicas do not reconnect in lockstep.
+func (l *Listener) backoff(attempt int) time.Duration {
+	d := l.cfg.ReconnectMinDelay
+	for i := 0; i < attempt && d < l.cfg.ReconnectMaxDelay; i++ {
+		d *= 2
+	}
+	d = min(d, l.cfg.ReconnectMaxDelay)
+	return d/2 + rand.N(d/2+1) //nolint:gosec // jitter only
+}
+
+func (l *Listener) open(ctx context.Context) (*pgx.Conn, error) {
+	conn, err := l.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	l.listening = make(map[string]bool)
+	if err := l.sync(ctx, conn); err != nil {
+		closeListenerConn(conn)
+		return nil, err
+	}
+	l.connected.Store(true)
+	return conn, nil
+}
+
+// serve waits for notifications until the connection fails or ctx is done.
+// The wait is interrupted to LISTEN on new channels and, when idle, to ping.
+func (l *Listener) serve(ctx context.Context, conn *pgx.Conn) error {
+	for {
+		if err := l.sync(ctx, conn); err != nil {
+			return err
+		}
+
+		waitCtx, cancel := context.WithTimeout(ctx, l.cfg.PingInterval)
+		l.mu.Lock()
+		if l.dirty {
+			l.mu.Unlock()
+			cancel()
+			continue
+		}
+		l.interrupt = cancel
+		l.mu.Unlock()
+
+		n, err := conn.WaitForNotification(waitCtx)
+		cancel()
+		if err == nil {
+			l.dispatch(ctx, conn, n)
+			continue
+		}
+		if ctx.Err() != nil || conn.IsClosed() || waitCtx.Err() == nil {
+			return err
+		}
+		if errors.Is(waitCtx.Err(), context.DeadlineExceeded) {
+			pingCtx, cancel := context.WithTimeout(ctx, listenerQueryTimeout)
+			err := conn.Ping(pingCtx)
+			cancel()
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// sync brings the LISTENed channels in line with the subscriptions.
+func (l *Listener) sync(ctx context.Context, conn *pgx.Conn) error {
+	l.mu.Lock()
+	l.dirty = false
+	wanted := make(map[string]bool, len(l.subs))
+	for channel := range l.subs {
+		wanted[channel] = true
+	}
+	l.mu.Unlock()
+
+	for channel := range wanted {
+		if l.listening[channel] {
+			continue
+		}
+		if _, err := conn.Exec(ctx, "LISTEN "+QuoteIdentifier([]string{channel})); err != nil {
+			return fmt.Errorf("psqlfx: listen %q: %w", channel, err)
+		}
+		l.listening[channel] = true
+	}
+	for channel := range l.listening {
+		if wanted[channel] {
+			continue
+		}
+		if _, err := conn.Exec(ctx, "UNLISTEN "+QuoteIdentifier([]string{channel})); err != nil {
+			return fmt.Errorf("psqlfx: unlisten %q: %w", channel, err)
+		}
+		delete(l.listening, channel)
+	}
+	return nil
+}
+
+func (l *Listener) dispatch(ctx context.Context, conn *pgx.Conn, n *pgconn.Notification) {
+	payload := []byte(n.Payload)
+	if ref, ok := strings.CutPrefix(n.Payload, PayloadRefPrefix); ok {
+		resolved, err := l.resolve(ctx, conn, ref)
+		if err != nil {
+			l.logger.Error("failed to resolve notification payload", "channel", n.Channel, "ref", ref, "error", err)
+			return
+		}
+		payload = resolved
+	}
+	l.deliver(Notification{Channel: n.Channel, Payload: payload})
+}
+
+func (l *Listener) resolve(ctx context.Context, conn *pgx.Conn, ref string) ([]byte, error) {
+	if l.cfg.PayloadTable == "" {
+		return nil, errors.New("no payload table configured")
+	}
+	id, err := uuid.Parse(ref)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, listenerQueryTimeout)
+	defer cancel()
+
+	var payload []byte
+	query := "SELECT payload FROM " + payloadTableIdentifier(l.cfg.PayloadTable) + " WHERE id = $1" //nolint:gosec // identifier from trusted config, properly quoted
+	if err := conn.QueryRow(ctx, query, id).Scan(&payload); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
+
+func (l *Listener) deliver(n Notification) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for sub := range l.subs[n.Channel] {
+		select {
+		case sub.ch <- n:
+		default:
+			l.logger.Warn("dropping slow notification subscriber", "channel", n.Channel)
+			l.removeLocked(sub)
+		}
+	}
+}
+
+func (l *Listener) remove(sub *Subscription) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.removeLocked(sub)
+}
+
+func (l *Listener) removeLocked(sub *Subscription) {
+	subs, ok := l.subs[sub.channel]
+	if !ok {
+		return
+	}
+	if _, ok := subs[sub]; !ok {
+		return
+	}
+	delete(subs, sub)
+	close(sub.ch)
+	if len(subs) == 0 {
+		delete(l.subs, sub.channel)
+		l.wakeLocked()
+	}
+}
+
+// wakeLocked makes the serving goroutine re-sync its LISTENed channels.
+func (l *Listener) wakeLocked() {
+	l.dirty = true
+	if l.interrupt != nil {
+		l.interrupt()
+	}
+}
+
+func payloadTableIdentifier(table string) string {
+	return QuoteIdentifier(strings.Split(table, "."))
+}
+
+func closeListenerConn(conn *pgx.Conn) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	_ = conn.Close(ctx)
+}
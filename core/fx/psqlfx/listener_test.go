@@ -0,0 +1,308 @@
+package psqlfx
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/suite"
+)
+
+// core/testing imports psqlfx, so its NewNoopLogger is out of reach here.
+var noopLogger = slog.New(slog.DiscardHandler) //nolint:forbidigo // see above
+
+type recordingDB struct {
+	queries []string
+	args    [][]any
+}
+
+func (d *recordingDB) Exec(_ context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	d.queries = append(d.queries, sql)
+	d.args = append(d.args, args)
+	return pgconn.CommandTag{}, nil
+}
+
+type ListenerSuite struct {
+	suite.Suite
+	listener *Listener
+}
+
+func (s *ListenerSuite) SetupTest() {
+	s.listener = newListener(nil, nil, noopLogger)
+}
+
+func (s *ListenerSuite) TestDefaults() {
+	l := newListener(nil, &ListenerConfiguration{ReconnectMinDelay: time.Minute, PayloadTable: "app.payloads"}, nil)
+
+	s.Assert().Equal(time.Minute, l.cfg.ReconnectMinDelay)
+	s.Assert().Equal(time.Minute, l.cfg.ReconnectMaxDelay)
+	s.Assert().Equal(30*time.Second, l.cfg.PingInterval)
+	s.Assert().Equal(time.Hour, l.cfg.PayloadRetention)
+	s.Assert().Equal("app.payloads", l.cfg.PayloadTable)
+}
+
+func (s *ListenerSuite) TestBackoff() {
+	l := newListener(nil, &ListenerConfiguration{
+		ReconnectMinDelay: 100 * time.Millisecond,
+		ReconnectMaxDelay: time.Second,
+	}, nil)
+
+	for attempt, ceiling := range []time.Duration{
+		100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond,
+		800 * time.Millisecond, time.Second, time.Second,
+	} {
+		for range 20 {
+			d := l.backoff(attempt)
+			s.Assert().GreaterOrEqual(d, ceiling/2)
+			s.Assert().LessOrEqual(d, ceiling)
+		}
+	}
+	s.Assert().LessOrEqual(l.backoff(1000), time.Second)
+}
+
+func (s *ListenerSuite) TestDeliver_FansOutPerChannel() {
+	a, err := s.listener.Listen("orders", 1)
+	s.Require().NoError(err)
+	b, err := s.listener.Listen("orders", 1)
+	s.Require().NoError(err)
+	other, err := s.listener.Listen("products", 1)
+	s.Require().NoError(err)
+
+	s.listener.deliver(Notification{Channel: "orders", Payload: []byte("1")})
+
+	s.Assert().Equal("1", string((<-a.C).Payload))
+	s.Assert().Equal("1", string((<-b.C).Payload))
+	s.Assert().Empty(other.C)
+}
+
+func (s *ListenerSuite) TestDeliver_DropsSlowSubscriber() {
+	slow, err := s.listener.Listen("orders", 1)
+	s.Require().NoError(err)
+
+	s.listener.deliver(Notification{Channel: "orders", Payload: []byte("1")})
+	s.listener.deliver(Notification{Channel: "orders", Payload: []byte("2")})
+
+	s.Assert().Equal("1", string((<-slow.C).Payload))
+	_, open := <-slow.C
+	s.Assert().False(open)
+	s.Assert().NotContains(s.listener.subs, "orders")
+}
+
+func (s *ListenerSuite) TestSubscriptionClose() {
+	sub, err := s.listener.Listen("orders", 1)
+	s.Require().NoError(err)
+	s.listener.dirty = false
+
+	sub.Close()
+	sub.Close()
+
+	_, open := <-sub.C
+	s.Assert().False(open)
+	s.Assert().True(s.listener.dirty, "last subscriber leaving should trigger UNLISTEN")
+}
+
+func (s *ListenerSuite) TestListen_EmptyChannel() {
+	_, err := s.listener.Listen("", 1)
+	s.Assert().Error(err)
+}
+
+func (s *ListenerSuite) TestSubscribe_DecodesJSON() {
+	type event struct {
+		ID int `json:"id"`
+	}
+	events, closeEvents, err := Subscribe[event](s.listener, "orders", 4)
+	s.Require().NoError(err)
+
+	s.listener.deliver(Notification{Channel: "orders", Payload: []byte(`{"id":1}`)})
+	s.listener.deliver(Notification{Channel: "orders", Payload: []byte(`not json`)})
+	s.listener.deliver(Notification{Channel: "orders", Payload: []byte(`{"id":2}`)})
+
+	s.Assert().Equal(event{ID: 1}, <-events)
+	s.Assert().Equal(event{ID: 2}, <-events)
+
+	closeEvents()
+	_, open := <-events
+	s.Assert().False(open)
+}
+
+func (s *ListenerSuite) TestCheck_NotConnected() {
+	s.Assert().ErrorIs(s.listener.Check(context.Background()), ErrListenerNotConnected)
+}
+
+func (s *ListenerSuite) TestNotify_Inline() {
+	db := &recordingDB{}
+
+	s.Require().NoError(s.listener.Notify(context.Background(), db, "orders", []byte(`{"id":1}`)))
+
+	s.Require().Len(db.queries, 1)
+	s.Assert().Equal("SELECT pg_notify($1, $2)", db.queries[0])
+	s.Assert().Equal([]any{"orders", `{"id":1}`}, db.args[0])
+}
+
+func (s *ListenerSuite) TestNotify_TooLargeWithoutPayloadTable() {
+	db := &recordingDB{}
+
+	err := s.listener.Notify(context.Background(), db, "orders", make([]byte, MaxNotifyPayload+1))
+
+	s.Assert().ErrorIs(err, ErrPayloadTooLarge)
+	s.Assert().Empty(db.queries)
+}
+
+func (s *ListenerSuite) TestNotify_ByReference() {
+	l := newListener(nil, &ListenerConfiguration{PayloadTable: "app.notification_payloads"}, nil)
+	db := &recordingDB{}
+
+	s.Require().NoError(l.Notify(context.Background(), db, "orders", make([]byte, MaxNotifyPayload+1)))
+
+	s.Require().Len(db.queries, 2)
+	s.Assert().Contains(db.queries[0], `INSERT INTO "app"."notification_payloads"`)
+	s.Assert().True(strings.HasPrefix(db.args[1][1].(string), PayloadRefPrefix))
+}
+
+func TestListenerSuite(t *testing.T) {
+	suite.Run(t, new(ListenerSuite))
+}
+
+// ListenerDBSuite exercises the Listener against Postgres.
+type ListenerDBSuite struct {
+	suite.Suite
+	pool     *pgxpool.Pool
+	listener *Listener
+}
+
+const listenerTestDSN = "host=localhost port=5432 user=root password=root dbname=test_core sslmode=disable"
+
+func (s *ListenerDBSuite) SetupSuite() {
+	pool, err := pgxpool.New(context.Background(), listenerTestDSN)
+	s.Require().NoError(err)
+	s.pool = pool
+	s.Require().NoError(pool.Ping(context.Background()))
+
+	_, err = pool.Exec(context.Background(), `CREATE TABLE IF NOT EXISTS public.psqlfx_notification_payloads (
+		id UUID PRIMARY KEY, channel TEXT NOT NULL, payload BYTEA NOT NULL,
+		created_at TIMESTAMPTZ NOT NULL DEFAULT now())`)
+	s.Require().NoError(err)
+}
+
+func (s *ListenerDBSuite) TearDownSuite() {
+	_, _ = s.pool.Exec(context.Background(), "DROP TABLE IF EXISTS public.psqlfx_notification_payloads")
+	s.pool.Close()
+}
+
+func (s *ListenerDBSuite) SetupTest() {
+	s.listener = newListener(func(ctx context.Context) (*pgx.Conn, error) {
+		return pgx.Connect(ctx, listenerTestDSN+" application_name=psqlfx_listener_test")
+	}, &ListenerConfiguration{
+		ReconnectMinDelay: 10 * time.Millisecond,
+		ReconnectMaxDelay: 50 * time.Millisecond,
+		PayloadTable:      "public.psqlfx_notification_payloads",
+	}, noopLogger)
+}
+
+func (s *ListenerDBSuite) TearDownTest() {
+	s.Require().NoError(s.listener.Stop(context.Background()))
+}
+
+// listen subscribes to channel and starts the listener, which LISTENs on the
+// channels subscribed so far before Start returns.
+func (s *ListenerDBSuite) listen(channel string) *Subscription {
+	sub, err := s.listener.Listen(channel, 8)
+	s.Require().NoError(err)
+	s.Require().NoError(s.listener.Start(context.Background()))
+	s.Require().NoError(s.listener.Check(context.Background()))
+	return sub
+}
+
+func (s *ListenerDBSuite) receive(sub *Subscription) Notification {
+	select {
+	case n, ok := <-sub.C:
+		s.Require().True(ok, "subscription closed")
+		return n
+	case <-time.After(5 * time.Second):
+		s.FailNow("no notification received")
+		return Notification{}
+	}
+}
+
+func (s *ListenerDBSuite) TestNotify() {
+	sub := s.listen("psqlfx_test_events")
+
+	s.Require().NoError(s.listener.Notify(context.Background(), s.pool, "psqlfx_test_events", []byte("hello")))
+
+	n := s.receive(sub)
+	s.Assert().Equal("psqlfx_test_events", n.Channel)
+	s.Assert().Equal("hello", string(n.Payload))
+}
+
+func (s *ListenerDBSuite) TestListenAfterStart() {
+	s.listen("psqlfx_test_other")
+	sub, err := s.listener.Listen("psqlfx_test_late", 8)
+	s.Require().NoError(err)
+
+	// The wait is interrupted to LISTEN, so the first notifications may race it.
+	for range 50 {
+		s.Require().NoError(s.listener.Notify(context.Background(), s.pool, "psqlfx_test_late", []byte("late")))
+		select {
+		case n := <-sub.C:
+			s.Assert().Equal("late", string(n.Payload))
+			return
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+	s.Fail("no notification received after subscribing to a started listener")
+}
+
+func (s *ListenerDBSuite) TestNotifyIsDeliveredOnCommit() {
+	sub := s.listen("psqlfx_test_tx")
+
+	tx, err := s.pool.Begin(context.Background())
+	s.Require().NoError(err)
+	s.Require().NoError(s.listener.Notify(context.Background(), tx, "psqlfx_test_tx", []byte("committed")))
+
+	select {
+	case <-sub.C:
+		s.Fail("notification delivered before commit")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	s.Require().NoError(tx.Commit(context.Background()))
+	s.Assert().Equal("committed", string(s.receive(sub).Payload))
+}
+
+func (s *ListenerDBSuite) TestLargePayloadByReference() {
+	sub := s.listen("psqlfx_test_large")
+	payload := []byte(strings.Repeat("x", 3*MaxNotifyPayload))
+
+	s.Require().NoError(s.listener.Notify(context.Background(), s.pool, "psqlfx_test_large", payload))
+
+	s.Assert().Equal(payload, s.receive(sub).Payload)
+}
+
+func (s *ListenerDBSuite) TestReconnectsAndRelistens() {
+	sub := s.listen("psqlfx_test_reconnect")
+	reconnected := make(chan struct{}, 1)
+	s.listener.OnReconnect(func() { reconnected <- struct{}{} })
+
+	_, err := s.pool.Exec(context.Background(),
+		"SELECT pg_terminate_backend(pid) FROM pg_stat_activity WHERE application_name = 'psqlfx_listener_test'")
+	s.Require().NoError(err)
+
+	select {
+	case <-reconnected:
+	case <-time.After(5 * time.Second):
+		s.FailNow("listener did not reconnect")
+	}
+	s.Require().NoError(s.listener.Check(context.Background()))
+
+	s.Require().NoError(s.listener.Notify(context.Background(), s.pool, "psqlfx_test_reconnect", []byte("after")))
+	s.Assert().Equal("after", string(s.receive(sub).Payload))
+}
+
+func TestListenerDBSuite(t *testing.T) {
+	suite.Run(t, new(ListenerDBSuite))
+}
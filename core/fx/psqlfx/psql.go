@@ -28,17 +28,26 @@ type Result struct {
 	Pool *pgxpool.Pool
 }
 
-func NewPool(p Params) (Result, error) {
-	dsn := p.Config.DSN()
-	if p.Defaults != nil {
-		dsnParams, err := p.Defaults.DSN()
+// connString combines the connection settings with the optional session defaults.
+func connString(cfg *Configuration, defaults *ConnectionDefaults) (string, error) {
+	dsn := cfg.DSN()
+	if defaults != nil {
+		dsnParams, err := defaults.DSN()
 		if err != nil {
-			return Result{}, fmt.Errorf("psqlfx: encode connection defaults: %w", err)
+			return "", fmt.Errorf("psqlfx: encode connection defaults: %w", err)
 		}
 		if dsnParams != "" {
 			dsn = dsn + " " + dsnParams
 		}
 	}
+	return dsn, nil
+}
+
+func NewPool(p Params) (Result, error) {
+	dsn, err := connString(p.Config, p.Defaults)
+	if err != nil {
+		return Result{}, err
+	}
 
 	poolConfig, err := pgxpool.ParseConfig(dsn)
 	if err != nil {
@@ -97,3 +106,10 @@ var Module = fx.Module(
 	"psqlfx",
 	fx.Provide(provideConfiguration, provideConnectionDefaults, NewPool),
 )
+
+// ListenerModule provides a *Listener. It needs the *Configuration supplied by
+// Module and is opt-in, since every Listener holds a connection of its own.
+var ListenerModule = fx.Module(
+	"psqlfx/listener",
+	fx.Provide(NewListener),
+)
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"context"
+	"go/parser"
+	"go/token"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bbsbb/go-edge/core/proto/internal/breaking"
+	"github.com/bbsbb/go-edge/core/proto/internal/lint"
+	"github.com/bbsbb/go-edge/core/proto/internal/schema"
+)
+
+type ArchitectureSuite struct {
+	suite.Suite
+	moduleRoot string
+	schema     *schema.Schema
+}
+
+func (s *ArchitectureSuite) SetupSuite() {
+	wd, err := os.Getwd()
+	s.Require().NoError(err)
+	s.moduleRoot = wd
+
+	s.schema, err = schema.Compile(context.Background(), wd)
+	s.Require().NoError(err)
+}
+
+func (s *ArchitectureSuite) TestLint() {
+	for _, v := range lint.Check(s.schema.Image().GetFile()) {
+		s.Fail("lint violation", v.String())
+	}
+}
+
+func (s *ArchitectureSuite) TestGeneratedCodeIsCurrent() {
+	generated, err := s.schema.Generate()
+	s.Require().NoError(err)
+
+	for name, want := range generated {
+		got, err := os.ReadFile(filepath.Join(s.moduleRoot, filepath.FromSlash(name)))
+		if err != nil {
+			s.Failf("generated code missing", "%s: %v (run `make generate`)", name, err)
+			continue
+		}
+		if string(got) != string(want) {
+			s.Failf("generated code is stale", "%s differs from its .proto source (run `make generate`)", name)
+		}
+	}
+
+	err = filepath.Walk(s.moduleRoot, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() || !strings.HasSuffix(path, ".pb.go") {
+			return err
+		}
+		rel, _ := filepath.Rel(s.moduleRoot, path)
+		if _, ok := generated[filepath.ToSlash(rel)]; !ok {
+			s.Failf("orphaned generated code", "%s has no .proto source", rel)
+		}
+		return nil
+	})
+	s.Require().NoError(err)
+}
+
+func (s *ArchitectureSuite) TestImageIsCurrent() {
+	want, err := schema.MarshalImage(s.schema.Image())
+	s.Require().NoError(err)
+
+	got, err := os.ReadFile(filepath.Join(s.moduleRoot, schema.ImageFile))
+	s.Require().NoError(err, "run `make generate`")
+	s.Assert().Equal(want, got, "%s is stale (run `make generate`)", schema.ImageFile)
+}
+
+func (s *ArchitectureSuite) TestNoBreakingChanges() {
+	// CI checkouts may be shallow; without a parent commit there is nothing to compare.
+	out, err := exec.Command("git", "show", "HEAD~1:./"+schema.ImageFile).Output()
+	if err != nil {
+		s.T().Skip("no image at HEAD~1")
+	}
+
+	previous, err := schema.UnmarshalImage(out)
+	s.Require().NoError(err)
+	for _, c := range breaking.Compare(previous, s.schema.Image()) {
+		s.Fail("breaking change", c.String())
+	}
+}
+
+func (s *ArchitectureSuite) TestGeneratedCodeImports() {
+	// Generated packages are shared contracts: they may only depend on the
+	// protobuf runtime and the standard library.
+	allowed := []string{
+		"google.golang.org/protobuf/",
+		"github.com/bbsbb/go-edge/core/proto/",
+	}
+
+	err := filepath.Walk(s.moduleRoot, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() || !strings.HasSuffix(path, ".pb.go") {
+			return err
+		}
+
+		f, parseErr := parser.ParseFile(token.NewFileSet(), path, nil, parser.ImportsOnly)
+		if parseErr != nil {
+			return parseErr
+		}
+
+		rel, _ := filepath.Rel(s.moduleRoot, path)
+		for _, imp := range f.Imports {
+			importPath := strings.Trim(imp.Path.Value, "\"")
+			if !strings.Contains(importPath, ".") {
+				continue
+			}
+			ok := false
+			for _, prefix := range allowed {
+				ok = ok || strings.HasPrefix(importPath, prefix)
+			}
+			if !ok {
+				s.Failf("forbidden import", "%s imports %q", rel, importPath)
+			}
+		}
+		return nil
+	})
+	s.Require().NoError(err)
+}
+
+func (s *ArchitectureSuite) TestFileSizeLimit() {
+	const maxLines = 500
+
+	err := filepath.Walk(s.moduleRoot, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() || !strings.HasSuffix(path, ".go") {
+			return err
+		}
+		if strings.HasSuffix(path, ".pb.go") {
+			return nil
+		}
+
+		data, readErr := os.ReadFile(path)
+		if readErr != nil {
+			return readErr
+		}
+
+		lines := strings.Count(string(data), "\n") + 1
+		if lines > maxLines {
+			rel, _ := filepath.Rel(s.moduleRoot, path)
+			s.Failf("file too large", "%s has %d lines (max %d)", rel, lines, maxLines)
+		}
+		return nil
+	})
+	s.Require().NoError(err)
+}
+
+func TestArchitectureSuite(t *testing.T) {
+	suite.Run(t, new(ArchitectureSuite))
+}
@@ -0,0 +1,7 @@
+// Command proto compiles the protobuf contracts of this module in-process and
+// maintains their committed artefacts.
+//
+//	go run . generate                      regenerate *.pb.go and image.binpb
+//	go run . lint                          apply buf-style naming and layout rules
+//	go run . breaking [-against HEAD~1]    compare against a committed image
+package main
@@ -0,0 +1,16 @@
+module github.com/bbsbb/go-edge/core/proto
+
+go 1.25.7
+
+require (
+	github.com/bufbuild/protocompile v0.14.1
+	github.com/stretchr/testify v1.11.1
+	google.golang.org/protobuf v1.36.11
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	golang.org/x/sync v0.8.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
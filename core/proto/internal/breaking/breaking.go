@@ -0,0 +1,242 @@
+// Package breaking detects wire- and source-incompatible changes between two
+// descriptor sets. The rules follow buf's FILE category: removals, renames and
+// type changes are reported, additions are not.
+package breaking
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// Change is a single breaking change found between two descriptor sets.
+type Change struct {
+	File    string
+	Element string
+	Rule    string
+	Message string
+}
+
+func (c Change) String() string {
+	if c.Element == "" {
+		return fmt.Sprintf("%s: [%s] %s", c.File, c.Rule, c.Message)
+	}
+	return fmt.Sprintf("%s: %s: [%s] %s", c.File, c.Element, c.Rule, c.Message)
+}
+
+// Compare reports every breaking change from previous to current, sorted by
+// file, element and rule.
+func Compare(previous, current *descriptorpb.FileDescriptorSet) []Change {
+	currentFiles := make(map[string]*descriptorpb.FileDescriptorProto, len(current.GetFile()))
+	for _, f := range current.GetFile() {
+		currentFiles[f.GetName()] = f
+	}
+
+	var d differ
+	for _, prev := range previous.GetFile() {
+		d.file = prev.GetName()
+		cur, ok := currentFiles[prev.GetName()]
+		if !ok {
+			d.report("", "FILE_NO_DELETE", "file was deleted")
+			continue
+		}
+		d.compareFile(prev, cur)
+	}
+
+	slices.SortStableFunc(d.changes, func(a, b Change) int {
+		return strings.Compare(a.File+"\x00"+a.Element+"\x00"+a.Rule, b.File+"\x00"+b.Element+"\x00"+b.Rule)
+	})
+	return d.changes
+}
+
+type differ struct {
+	file    string
+	changes []Change
+}
+
+func (d *differ) report(element, rule, format string, args ...any) {
+	d.changes = append(d.changes, Change{
+		File:    d.file,
+		Element: element,
+		Rule:    rule,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+func (d *differ) compareFile(prev, cur *descriptorpb.FileDescriptorProto) {
+	if prev.GetPackage() != cur.GetPackage() {
+		d.report("", "FILE_SAME_PACKAGE", "package changed from %q to %q", prev.GetPackage(), cur.GetPackage())
+		return
+	}
+	if prev.GetOptions().GetGoPackage() != cur.GetOptions().GetGoPackage() {
+		d.report("", "FILE_SAME_GO_PACKAGE", "go_package changed from %q to %q",
+			prev.GetOptions().GetGoPackage(), cur.GetOptions().GetGoPackage())
+	}
+
+	prefix := qualify(prev.GetPackage(), "")
+	d.compareMessages(prefix, prev.GetMessageType(), cur.GetMessageType())
+	d.compareEnums(prefix, prev.GetEnumType(), cur.GetEnumType())
+	d.compareServices(prefix, prev.GetService(), cur.GetService())
+}
+
+func (d *differ) compareMessages(prefix string, prev, cur []*descriptorpb.DescriptorProto) {
+	byName := index(cur, (*descriptorpb.DescriptorProto).GetName)
+	for _, p := range prev {
+		name := prefix + p.GetName()
+		c, ok := byName[p.GetName()]
+		if !ok {
+			d.report(name, "MESSAGE_NO_DELETE", "message was deleted")
+			continue
+		}
+		d.compareFields(name, p, c)
+		d.compareMessages(name+".", p.GetNestedType(), c.GetNestedType())
+		d.compareEnums(name+".", p.GetEnumType(), c.GetEnumType())
+	}
+}
+
+func (d *differ) compareFields(message string, prev, cur *descriptorpb.DescriptorProto) {
+	byNumber := index(cur.GetField(), (*descriptorpb.FieldDescriptorProto).GetNumber)
+	for _, p := range prev.GetField() {
+		element := message + "." + p.GetName()
+		c, ok := byNumber[p.GetNumber()]
+		if !ok {
+			if !reservedField(cur, p.GetNumber()) {
+				d.report(element, "FIELD_NO_DELETE",
+					"field %d was deleted without reserving its number", p.GetNumber())
+			}
+			continue
+		}
+		if p.GetName() != c.GetName() {
+			d.report(element, "FIELD_SAME_NAME", "field %d was renamed to %q", p.GetNumber(), c.GetName())
+		}
+		if p.GetType() != c.GetType() || p.GetTypeName() != c.GetTypeName() {
+			d.report(element, "FIELD_SAME_TYPE", "type changed from %s to %s", fieldType(p), fieldType(c))
+		}
+		if p.GetLabel() != c.GetLabel() || p.GetProto3Optional() != c.GetProto3Optional() {
+			d.report(element, "FIELD_SAME_CARDINALITY", "cardinality changed from %s to %s",
+				cardinality(p), cardinality(c))
+		}
+		if oneof(prev, p) != oneof(cur, c) {
+			d.report(element, "FIELD_SAME_ONEOF", "oneof changed from %q to %q", oneof(prev, p), oneof(cur, c))
+		}
+	}
+}
+
+func (d *differ) compareEnums(prefix string, prev, cur []*descriptorpb.EnumDescriptorProto) {
+	byName := index(cur, (*descriptorpb.EnumDescriptorProto).GetName)
+	for _, p := range prev {
+		name := prefix + p.GetName()
+		c, ok := byName[p.GetName()]
+		if !ok {
+			d.report(name, "ENUM_NO_DELETE", "enum was deleted")
+			continue
+		}
+
+		names := make(map[int32][]string)
+		for _, v := range c.GetValue() {
+			names[v.GetNumber()] = append(names[v.GetNumber()], v.GetName())
+		}
+		for _, v := range p.GetValue() {
+			element := name + "." + v.GetName()
+			current, ok := names[v.GetNumber()]
+			switch {
+			case !ok && !reservedEnumValue(c, v.GetNumber()):
+				d.report(element, "ENUM_VALUE_NO_DELETE",
+					"enum value %d was deleted without reserving its number", v.GetNumber())
+			case ok && !slices.Contains(current, v.GetName()):
+				d.report(element, "ENUM_VALUE_SAME_NAME", "enum value %d was renamed to %q",
+					v.GetNumber(), strings.Join(current, ", "))
+			}
+		}
+	}
+}
+
+func (d *differ) compareServices(prefix string, prev, cur []*descriptorpb.ServiceDescriptorProto) {
+	byName := index(cur, (*descriptorpb.ServiceDescriptorProto).GetName)
+	for _, p := range prev {
+		name := prefix + p.GetName()
+		c, ok := byName[p.GetName()]
+		if !ok {
+			d.report(name, "SERVICE_NO_DELETE", "service was deleted")
+			continue
+		}
+		methods := index(c.GetMethod(), (*descriptorpb.MethodDescriptorProto).GetName)
+		for _, pm := range p.GetMethod() {
+			element := name + "." + pm.GetName()
+			cm, ok := methods[pm.GetName()]
+			if !ok {
+				d.report(element, "RPC_NO_DELETE", "rpc was deleted")
+				continue
+			}
+			if pm.GetInputType() != cm.GetInputType() {
+				d.report(element, "RPC_SAME_REQUEST_TYPE", "request type changed from %s to %s",
+					pm.GetInputType(), cm.GetInputType())
+			}
+			if pm.GetOutputType() != cm.GetOutputType() {
+				d.report(element, "RPC_SAME_RESPONSE_TYPE", "response type changed from %s to %s",
+					pm.GetOutputType(), cm.GetOutputType())
+			}
+			if pm.GetClientStreaming() != cm.GetClientStreaming() || pm.GetServerStreaming() != cm.GetServerStreaming() {
+				d.report(element, "RPC_SAME_STREAMING", "streaming mode changed")
+			}
+		}
+	}
+}
+
+func index[T any, K comparable](items []T, key func(T) K) map[K]T {
+	m := make(map[K]T, len(items))
+	for _, item := range items {
+		m[key(item)] = item
+	}
+	return m
+}
+
+func qualify(pkg, name string) string {
+	if pkg == "" {
+		return name
+	}
+	return pkg + "." + name
+}
+
+func reservedField(m *descriptorpb.DescriptorProto, number int32) bool {
+	for _, r := range m.GetReservedRange() {
+		// DescriptorProto reserved ranges are end-exclusive.
+		if number >= r.GetStart() && number < r.GetEnd() {
+			return true
+		}
+	}
+	return false
+}
+
+func reservedEnumValue(e *descriptorpb.EnumDescriptorProto, number int32) bool {
+	for _, r := range e.GetReservedRange() {
+		// EnumDescriptorProto reserved ranges are end-inclusive.
+		if number >= r.GetStart() && number <= r.GetEnd() {
+			return true
+		}
+	}
+	return false
+}
+
+func fieldType(f *descriptorpb.FieldDescriptorProto) string {
+	if f.GetTypeName() != "" {
+		return strings.TrimPrefix(f.GetTypeName(), ".")
+	}
+	return strings.ToLower(strings.TrimPrefix(f.GetType().String(), "TYPE_"))
+}
+
+func cardinality(f *descriptorpb.FieldDescriptorProto) string {
+	if f.GetProto3Optional() {
+		return "optional"
+	}
+	return strings.ToLower(strings.TrimPrefix(f.GetLabel().String(), "LABEL_"))
+}
+
+func oneof(m *descriptorpb.DescriptorProto, f *descriptorpb.FieldDescriptorProto) string {
+	if f.OneofIndex == nil || f.GetProto3Optional() {
+		return ""
+	}
+	return m.GetOneofDecl()[f.GetOneofIndex()].GetName()
+}
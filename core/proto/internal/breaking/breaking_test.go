@@ -0,0 +1,232 @@
+package breaking
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	"github.com/bbsbb/go-edge/core/proto/internal/schema"
+)
+
+const baseline = `syntax = "proto3";
+package acme.v1;
+option go_package = "example.com/acme/v1;acmev1";
+
+enum Kind {
+  KIND_UNSPECIFIED = 0;
+  KIND_ROUND = 1;
+  KIND_SQUARE = 2;
+}
+
+message Widget {
+  message Part { string id = 1; }
+  string id = 1;
+  int32 size = 2;
+  repeated Part parts = 3;
+  Kind kind = 4;
+  oneof shape {
+    int32 radius = 5;
+    int32 side = 6;
+  }
+}
+
+service WidgetService {
+  rpc GetWidget(Widget) returns (Widget);
+}
+`
+
+type BreakingSuite struct {
+	suite.Suite
+}
+
+func (s *BreakingSuite) image(sources map[string]string) *descriptorpb.FileDescriptorSet {
+	sch, err := schema.CompileSources(context.Background(), sources)
+	s.Require().NoError(err)
+	return sch.Image()
+}
+
+func (s *BreakingSuite) compare(current string) []string {
+	changes := Compare(
+		s.image(map[string]string{"acme/v1/widget.proto": baseline}),
+		s.image(map[string]string{"acme/v1/widget.proto": current}),
+	)
+	out := make([]string, 0, len(changes))
+	for _, c := range changes {
+		out = append(out, c.Element+" "+c.Rule)
+	}
+	return out
+}
+
+func (s *BreakingSuite) TestUnchanged() {
+	s.Assert().Empty(s.compare(baseline))
+}
+
+func (s *BreakingSuite) TestAdditionsAreCompatible() {
+	current := `syntax = "proto3";
+package acme.v1;
+option go_package = "example.com/acme/v1;acmev1";
+
+// Comments and new elements do not break anything.
+enum Kind {
+  KIND_UNSPECIFIED = 0;
+  KIND_ROUND = 1;
+  KIND_SQUARE = 2;
+  KIND_OVAL = 3;
+}
+
+message Widget {
+  message Part { string id = 1; string label = 2; }
+  string id = 1;
+  int32 size = 2;
+  repeated Part parts = 3;
+  Kind kind = 4;
+  oneof shape {
+    int32 radius = 5;
+    int32 side = 6;
+  }
+  string colour = 7;
+}
+
+message Gadget { string id = 1; }
+
+service WidgetService {
+  rpc GetWidget(Widget) returns (Widget);
+  rpc ListWidgets(Widget) returns (stream Widget);
+}
+`
+	s.Assert().Empty(s.compare(current))
+}
+
+func (s *BreakingSuite) TestReservedDeletionsAreCompatible() {
+	current := `syntax = "proto3";
+package acme.v1;
+option go_package = "example.com/acme/v1;acmev1";
+
+enum Kind {
+  reserved 2;
+  KIND_UNSPECIFIED = 0;
+  KIND_ROUND = 1;
+}
+
+message Widget {
+  reserved 2;
+  message Part { string id = 1; }
+  string id = 1;
+  repeated Part parts = 3;
+  Kind kind = 4;
+  oneof shape {
+    int32 radius = 5;
+    int32 side = 6;
+  }
+}
+
+service WidgetService {
+  rpc GetWidget(Widget) returns (Widget);
+}
+`
+	s.Assert().Empty(s.compare(current))
+}
+
+func (s *BreakingSuite) TestBreakingChanges() {
+	current := `syntax = "proto3";
+package acme.v1;
+option go_package = "example.com/acme/v1;acmev1";
+
+enum Kind {
+  KIND_UNSPECIFIED = 0;
+  KIND_CIRCLE = 1;
+}
+
+message Widget {
+  string id = 1;
+  int64 size = 2;
+  Kind parts = 3;
+  optional Kind kind = 4;
+  int32 radius = 5;
+  int32 side = 6;
+}
+
+service WidgetService {
+  rpc GetWidget(Widget) returns (stream Widget);
+}
+`
+	s.Assert().Equal([]string{
+		"acme.v1.Kind.KIND_ROUND ENUM_VALUE_SAME_NAME",
+		"acme.v1.Kind.KIND_SQUARE ENUM_VALUE_NO_DELETE",
+		"acme.v1.Widget.Part MESSAGE_NO_DELETE",
+		"acme.v1.Widget.kind FIELD_SAME_CARDINALITY",
+		"acme.v1.Widget.parts FIELD_SAME_CARDINALITY",
+		"acme.v1.Widget.parts FIELD_SAME_TYPE",
+		"acme.v1.Widget.radius FIELD_SAME_ONEOF",
+		"acme.v1.Widget.side FIELD_SAME_ONEOF",
+		"acme.v1.Widget.size FIELD_SAME_TYPE",
+		"acme.v1.WidgetService.GetWidget RPC_SAME_STREAMING",
+	}, s.compare(current))
+}
+
+func (s *BreakingSuite) TestFieldRenameAndDelete() {
+	current := `syntax = "proto3";
+package acme.v1;
+option go_package = "example.com/acme/v1;acmev1";
+
+enum Kind {
+  KIND_UNSPECIFIED = 0;
+  KIND_ROUND = 1;
+  KIND_SQUARE = 2;
+}
+
+message Widget {
+  message Part { string id = 1; }
+  string identifier = 1;
+  repeated Part parts = 3;
+  Kind kind = 4;
+  oneof shape {
+    int32 radius = 5;
+    int32 side = 6;
+  }
+}
+`
+	s.Assert().Equal([]string{
+		"acme.v1.Widget.id FIELD_SAME_NAME",
+		"acme.v1.Widget.size FIELD_NO_DELETE",
+		"acme.v1.WidgetService SERVICE_NO_DELETE",
+	}, s.compare(current))
+}
+
+func (s *BreakingSuite) TestFileLevelChanges() {
+	previous := s.image(map[string]string{
+		"acme/v1/widget.proto": baseline,
+		"acme/v1/extra.proto":  `syntax = "proto3"; package acme.v1;`,
+	})
+	current := s.image(map[string]string{
+		"acme/v1/widget.proto": `syntax = "proto3"; package acme.v1; option go_package = "example.com/acme/v2;acmev1";
+enum Kind { KIND_UNSPECIFIED = 0; KIND_ROUND = 1; KIND_SQUARE = 2; }
+message Widget {
+  message Part { string id = 1; }
+  string id = 1; int32 size = 2; repeated Part parts = 3; Kind kind = 4;
+  oneof shape { int32 radius = 5; int32 side = 6; }
+}
+service WidgetService { rpc GetWidget(Widget) returns (Widget); }
+`,
+	})
+
+	changes := Compare(previous, current)
+	s.Require().Len(changes, 2)
+	s.Assert().Equal("acme/v1/extra.proto: [FILE_NO_DELETE] file was deleted", changes[0].String())
+	s.Assert().Equal("FILE_SAME_GO_PACKAGE", changes[1].Rule)
+}
+
+func (s *BreakingSuite) TestPackageChange() {
+	changes := Compare(
+		s.image(map[string]string{"acme/v1/widget.proto": baseline}),
+		s.image(map[string]string{"acme/v1/widget.proto": `syntax = "proto3"; package acme.v2;`}),
+	)
+	s.Require().Len(changes, 1)
+	s.Assert().Equal("FILE_SAME_PACKAGE", changes[0].Rule)
+}
+
+func TestBreakingSuite(t *testing.T) {
+	suite.Run(t, new(BreakingSuite))
+}
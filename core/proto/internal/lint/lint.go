@@ -0,0 +1,188 @@
+// Package lint checks proto descriptors against the naming and layout rules of
+// buf's DEFAULT category, plus a go_package requirement.
+package lint
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+var (
+	lowerSnake     = regexp.MustCompile(`^[a-z][a-z0-9]*(_[a-z0-9]+)*$`)
+	upperSnake     = regexp.MustCompile(`^[A-Z][A-Z0-9]*(_[A-Z0-9]+)*$`)
+	pascal         = regexp.MustCompile(`^[A-Z][a-zA-Z0-9]*$`)
+	versionSuffix  = regexp.MustCompile(`^v[1-9][0-9]*((alpha|beta)[1-9][0-9]*)?$`)
+	zeroValueIdent = "_UNSPECIFIED"
+)
+
+// Violation is a single lint finding.
+type Violation struct {
+	File    string
+	Element string
+	Rule    string
+	Message string
+}
+
+func (v Violation) String() string {
+	if v.Element == "" {
+		return fmt.Sprintf("%s: [%s] %s", v.File, v.Rule, v.Message)
+	}
+	return fmt.Sprintf("%s: %s: [%s] %s", v.File, v.Element, v.Rule, v.Message)
+}
+
+// Check lints every file and returns the violations sorted by file, element
+// and rule.
+func Check(files []*descriptorpb.FileDescriptorProto) []Violation {
+	var out []Violation
+	for _, f := range files {
+		c := checker{file: f.GetName()}
+		c.checkFile(f)
+		out = append(out, c.violations...)
+	}
+	slices.SortStableFunc(out, func(a, b Violation) int {
+		return strings.Compare(a.File+"\x00"+a.Element+"\x00"+a.Rule, b.File+"\x00"+b.Element+"\x00"+b.Rule)
+	})
+	return out
+}
+
+type checker struct {
+	file       string
+	violations []Violation
+}
+
+func (c *checker) report(element, rule, format string, args ...any) {
+	c.violations = append(c.violations, Violation{
+		File:    c.file,
+		Element: element,
+		Rule:    rule,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+func (c *checker) checkFile(f *descriptorpb.FileDescriptorProto) {
+	base := strings.TrimSuffix(path.Base(f.GetName()), ".proto")
+	if !lowerSnake.MatchString(base) {
+		c.report("", "FILE_LOWER_SNAKE_CASE", "file name %q should be lower_snake_case", path.Base(f.GetName()))
+	}
+
+	pkg := f.GetPackage()
+	if pkg == "" {
+		c.report("", "PACKAGE_DEFINED", "files must declare a package")
+	} else {
+		parts := strings.Split(pkg, ".")
+		for _, p := range parts {
+			if !lowerSnake.MatchString(p) {
+				c.report("", "PACKAGE_LOWER_SNAKE_CASE", "package %q should be lower_snake_case", pkg)
+				break
+			}
+		}
+		if !versionSuffix.MatchString(parts[len(parts)-1]) {
+			c.report("", "PACKAGE_VERSION_SUFFIX", "package %q should end in a version suffix such as \"v1\"", pkg)
+		}
+		if want := strings.ReplaceAll(pkg, ".", "/"); path.Dir(f.GetName()) != want {
+			c.report("", "PACKAGE_DIRECTORY_MATCH", "files in package %q should be in directory %q", pkg, want)
+		}
+	}
+
+	if f.GetOptions().GetGoPackage() == "" {
+		c.report("", "GO_PACKAGE_DEFINED", "files must set option go_package")
+	}
+
+	prefix := pkg
+	if prefix != "" {
+		prefix += "."
+	}
+	for _, m := range f.GetMessageType() {
+		c.checkMessage(prefix, m)
+	}
+	for _, e := range f.GetEnumType() {
+		c.checkEnum(prefix, e)
+	}
+	for _, s := range f.GetService() {
+		name := prefix + s.GetName()
+		if !pascal.MatchString(s.GetName()) {
+			c.report(name, "SERVICE_PASCAL_CASE", "service name should be PascalCase")
+		}
+		for _, m := range s.GetMethod() {
+			if !pascal.MatchString(m.GetName()) {
+				c.report(name+"."+m.GetName(), "RPC_PASCAL_CASE", "rpc name should be PascalCase")
+			}
+		}
+	}
+}
+
+func (c *checker) checkMessage(prefix string, m *descriptorpb.DescriptorProto) {
+	if m.GetOptions().GetMapEntry() {
+		return
+	}
+	name := prefix + m.GetName()
+	if !pascal.MatchString(m.GetName()) {
+		c.report(name, "MESSAGE_PASCAL_CASE", "message name should be PascalCase")
+	}
+	for _, f := range m.GetField() {
+		if !lowerSnake.MatchString(f.GetName()) {
+			c.report(name+"."+f.GetName(), "FIELD_LOWER_SNAKE_CASE", "field name should be lower_snake_case")
+		}
+	}
+	for _, o := range m.GetOneofDecl() {
+		// Synthetic oneofs backing proto3 optional fields are named after the field.
+		if strings.HasPrefix(o.GetName(), "_") {
+			continue
+		}
+		if !lowerSnake.MatchString(o.GetName()) {
+			c.report(name+"."+o.GetName(), "ONEOF_LOWER_SNAKE_CASE", "oneof name should be lower_snake_case")
+		}
+	}
+	for _, nested := range m.GetNestedType() {
+		c.checkMessage(name+".", nested)
+	}
+	for _, e := range m.GetEnumType() {
+		c.checkEnum(name+".", e)
+	}
+}
+
+func (c *checker) checkEnum(prefix string, e *descriptorpb.EnumDescriptorProto) {
+	name := prefix + e.GetName()
+	if !pascal.MatchString(e.GetName()) {
+		c.report(name, "ENUM_PASCAL_CASE", "enum name should be PascalCase")
+	}
+
+	valuePrefix := UpperSnake(e.GetName()) + "_"
+	for i, v := range e.GetValue() {
+		element := name + "." + v.GetName()
+		if !upperSnake.MatchString(v.GetName()) {
+			c.report(element, "ENUM_VALUE_UPPER_SNAKE_CASE", "enum value should be UPPER_SNAKE_CASE")
+		}
+		if !strings.HasPrefix(v.GetName(), valuePrefix) {
+			c.report(element, "ENUM_VALUE_PREFIX", "enum value should be prefixed with %q", valuePrefix)
+		}
+		if i == 0 && v.GetName() != valuePrefix[:len(valuePrefix)-1]+zeroValueIdent {
+			c.report(element, "ENUM_ZERO_VALUE_SUFFIX", "enum zero value should be %q",
+				valuePrefix[:len(valuePrefix)-1]+zeroValueIdent)
+		}
+	}
+}
+
+// UpperSnake converts a PascalCase identifier to UPPER_SNAKE_CASE, keeping
+// acronyms together (HTTPStatus becomes HTTP_STATUS).
+func UpperSnake(s string) string {
+	runes := []rune(s)
+	var b strings.Builder
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				b.WriteByte('_')
+			}
+		}
+		b.WriteRune(unicode.ToUpper(r))
+	}
+	return b.String()
+}
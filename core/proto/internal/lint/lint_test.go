@@ -0,0 +1,153 @@
+package lint
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bbsbb/go-edge/core/proto/internal/schema"
+)
+
+type LintSuite struct {
+	suite.Suite
+}
+
+func (s *LintSuite) check(path, source string) []Violation {
+	sch, err := schema.CompileSources(context.Background(), map[string]string{path: source})
+	s.Require().NoError(err)
+	return Check(sch.Image().GetFile())
+}
+
+func rules(violations []Violation) []string {
+	out := make([]string, 0, len(violations))
+	for _, v := range violations {
+		out = append(out, v.Rule)
+	}
+	return out
+}
+
+func (s *LintSuite) TestClean() {
+	violations := s.check("acme/v1/widget.proto", `syntax = "proto3";
+package acme.v1;
+option go_package = "example.com/acme/v1;acmev1";
+
+enum WidgetKind {
+  WIDGET_KIND_UNSPECIFIED = 0;
+  WIDGET_KIND_ROUND = 1;
+}
+
+message Widget {
+  message Part { string part_id = 1; }
+  string id = 1;
+  optional string label = 2;
+  map<string, string> attributes = 3;
+  oneof shape {
+    int32 radius = 4;
+    int32 side = 5;
+  }
+}
+
+service WidgetService {
+  rpc GetWidget(Widget) returns (Widget);
+}
+`)
+	s.Assert().Empty(violations)
+}
+
+func (s *LintSuite) TestFileRules() {
+	tests := []struct {
+		name   string
+		path   string
+		source string
+		want   []string
+	}{
+		{
+			name:   "missing package",
+			path:   "widget.proto",
+			source: `syntax = "proto3"; option go_package = "x/y;y";`,
+			want:   []string{"PACKAGE_DEFINED"},
+		},
+		{
+			name:   "missing version suffix and directory mismatch",
+			path:   "acme/widget.proto",
+			source: `syntax = "proto3"; package acme.widgets; option go_package = "x/y;y";`,
+			want:   []string{"PACKAGE_DIRECTORY_MATCH", "PACKAGE_VERSION_SUFFIX"},
+		},
+		{
+			name:   "file name case and go_package",
+			path:   "acme/v1/WidgetTypes.proto",
+			source: `syntax = "proto3"; package acme.v1;`,
+			want:   []string{"FILE_LOWER_SNAKE_CASE", "GO_PACKAGE_DEFINED"},
+		},
+		{
+			name:   "package case",
+			path:   "Acme/v1/widget.proto",
+			source: `syntax = "proto3"; package Acme.v1; option go_package = "x/y;y";`,
+			want:   []string{"PACKAGE_LOWER_SNAKE_CASE"},
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			s.Assert().Equal(tt.want, rules(s.check(tt.path, tt.source)))
+		})
+	}
+}
+
+func (s *LintSuite) TestNamingRules() {
+	violations := s.check("acme/v1/widget.proto", `syntax = "proto3";
+package acme.v1;
+option go_package = "example.com/acme/v1;acmev1";
+
+enum widget_kind {
+  UNKNOWN = 0;
+  WIDGET_KIND_round = 1;
+}
+
+message widget {
+  string Id = 1;
+  oneof Shape { int32 radius = 2; }
+}
+
+service widgets {
+  rpc get_widget(widget) returns (widget);
+}
+`)
+
+	s.Assert().ElementsMatch([]string{
+		"ENUM_PASCAL_CASE",
+		"ENUM_VALUE_PREFIX",
+		"ENUM_VALUE_UPPER_SNAKE_CASE",
+		"ENUM_ZERO_VALUE_SUFFIX",
+		"SERVICE_PASCAL_CASE",
+		"RPC_PASCAL_CASE",
+		"MESSAGE_PASCAL_CASE",
+		"FIELD_LOWER_SNAKE_CASE",
+		"ONEOF_LOWER_SNAKE_CASE",
+	}, rules(violations))
+}
+
+func (s *LintSuite) TestViolationString() {
+	v := Violation{File: "a.proto", Element: "acme.v1.Widget", Rule: "MESSAGE_PASCAL_CASE", Message: "bad"}
+	s.Assert().Equal("a.proto: acme.v1.Widget: [MESSAGE_PASCAL_CASE] bad", v.String())
+
+	v.Element = ""
+	s.Assert().Equal("a.proto: [MESSAGE_PASCAL_CASE] bad", v.String())
+}
+
+func (s *LintSuite) TestUpperSnake() {
+	tests := map[string]string{
+		"ProductCategory": "PRODUCT_CATEGORY",
+		"HTTPStatus":      "HTTP_STATUS",
+		"Status2Code":     "STATUS2_CODE",
+		"Kind":            "KIND",
+	}
+	for in, want := range tests {
+		s.Assert().Equal(want, UpperSnake(in), in)
+	}
+}
+
+func TestLintSuite(t *testing.T) {
+	suite.Run(t, new(LintSuite))
+}
@@ -0,0 +1,172 @@
+// Package schema compiles the .proto sources of this module, renders the
+// committed descriptor-set image and generates Go code in-process, so neither
+// protoc nor buf is required.
+package schema
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/bufbuild/protocompile"
+	gengo "google.golang.org/protobuf/cmd/protoc-gen-go/internal_gengo"
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+// ImageFile is the name of the committed descriptor-set image, relative to the
+// module root.
+const ImageFile = "image.binpb"
+
+// Schema is a compiled set of .proto files.
+type Schema struct {
+	files []protoreflect.FileDescriptor
+}
+
+// Files returns the compiled files in path order.
+func (s *Schema) Files() []protoreflect.FileDescriptor {
+	return s.files
+}
+
+// Compile parses and links every .proto file found under root. File paths
+// (and therefore import paths) are relative to root.
+func Compile(ctx context.Context, root string) (*Schema, error) {
+	var names []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || filepath.Ext(path) != ".proto" {
+			return err
+		}
+		rel, relErr := filepath.Rel(root, path)
+		if relErr != nil {
+			return relErr
+		}
+		names = append(names, filepath.ToSlash(rel))
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("find proto files: %w", err)
+	}
+	return compile(ctx, &protocompile.SourceResolver{ImportPaths: []string{root}}, names)
+}
+
+// CompileSources compiles in-memory sources keyed by file path.
+func CompileSources(ctx context.Context, sources map[string]string) (*Schema, error) {
+	names := make([]string, 0, len(sources))
+	for name := range sources {
+		names = append(names, name)
+	}
+	return compile(ctx, &protocompile.SourceResolver{
+		Accessor: protocompile.SourceAccessorFromMap(sources),
+	}, names)
+}
+
+func compile(ctx context.Context, resolver protocompile.Resolver, names []string) (*Schema, error) {
+	if len(names) == 0 {
+		return nil, errors.New("no proto files to compile")
+	}
+	slices.Sort(names)
+
+	compiler := protocompile.Compiler{
+		Resolver:       protocompile.WithStandardImports(resolver),
+		SourceInfoMode: protocompile.SourceInfoStandard,
+	}
+	files, err := compiler.Compile(ctx, names...)
+	if err != nil {
+		return nil, fmt.Errorf("compile proto files: %w", err)
+	}
+
+	s := &Schema{files: make([]protoreflect.FileDescriptor, 0, len(files))}
+	for _, f := range files {
+		s.files = append(s.files, f)
+	}
+	return s, nil
+}
+
+// Image returns the descriptor set of the compiled files, without imported
+// dependencies and without source code info, so comment and formatting
+// changes do not alter it.
+func (s *Schema) Image() *descriptorpb.FileDescriptorSet {
+	set := &descriptorpb.FileDescriptorSet{}
+	for _, f := range s.files {
+		fdp := protodesc.ToFileDescriptorProto(f)
+		fdp.SourceCodeInfo = nil
+		set.File = append(set.File, fdp)
+	}
+	return set
+}
+
+// MarshalImage encodes a descriptor set deterministically.
+func MarshalImage(set *descriptorpb.FileDescriptorSet) ([]byte, error) {
+	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(set)
+	if err != nil {
+		return nil, fmt.Errorf("marshal image: %w", err)
+	}
+	return data, nil
+}
+
+// UnmarshalImage decodes a descriptor set produced by MarshalImage.
+func UnmarshalImage(data []byte) (*descriptorpb.FileDescriptorSet, error) {
+	set := &descriptorpb.FileDescriptorSet{}
+	if err := proto.Unmarshal(data, set); err != nil {
+		return nil, fmt.Errorf("unmarshal image: %w", err)
+	}
+	return set, nil
+}
+
+// Generate runs protoc-gen-go over the compiled files and returns the
+// generated sources keyed by path relative to the module root.
+func (s *Schema) Generate() (map[string][]byte, error) {
+	req := &pluginpb.CodeGeneratorRequest{
+		Parameter: proto.String("paths=source_relative"),
+	}
+	seen := make(map[string]bool)
+	var visit func(f protoreflect.FileDescriptor)
+	visit = func(f protoreflect.FileDescriptor) {
+		if seen[f.Path()] {
+			return
+		}
+		seen[f.Path()] = true
+		imports := f.Imports()
+		for i := range imports.Len() {
+			visit(imports.Get(i).FileDescriptor)
+		}
+		req.ProtoFile = append(req.ProtoFile, protodesc.ToFileDescriptorProto(f))
+	}
+	for _, f := range s.files {
+		visit(f)
+		req.FileToGenerate = append(req.FileToGenerate, f.Path())
+	}
+
+	plugin, err := protogen.Options{}.New(req)
+	if err != nil {
+		return nil, fmt.Errorf("prepare generator: %w", err)
+	}
+	for _, f := range plugin.Files {
+		if f.Generate {
+			gengo.GenerateFile(plugin, f)
+		}
+	}
+	plugin.SupportedFeatures = gengo.SupportedFeatures
+
+	resp := plugin.Response()
+	if resp.Error != nil {
+		return nil, fmt.Errorf("generate go code: %s", resp.GetError())
+	}
+
+	out := make(map[string][]byte, len(resp.File))
+	for _, f := range resp.File {
+		if !strings.HasSuffix(f.GetName(), ".pb.go") {
+			return nil, fmt.Errorf("unexpected generated file %q", f.GetName())
+		}
+		out[f.GetName()] = []byte(f.GetContent())
+	}
+	return out, nil
+}
@@ -0,0 +1,96 @@
+package schema
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/protobuf/proto"
+)
+
+const widgetSource = `syntax = "proto3";
+
+package acme.v1;
+
+import "google/protobuf/timestamp.proto";
+
+option go_package = "example.com/acme/v1;acmev1";
+
+// Widget is documented.
+message Widget {
+  string id = 1;
+  google.protobuf.Timestamp created_at = 2;
+}
+`
+
+type SchemaSuite struct {
+	suite.Suite
+}
+
+func (s *SchemaSuite) compile() *Schema {
+	sch, err := CompileSources(context.Background(), map[string]string{"acme/v1/widget.proto": widgetSource})
+	s.Require().NoError(err)
+	return sch
+}
+
+func (s *SchemaSuite) TestCompileSources() {
+	sch := s.compile()
+
+	s.Require().Len(sch.Files(), 1)
+	s.Assert().Equal("acme/v1/widget.proto", sch.Files()[0].Path())
+	s.Assert().Equal("acme.v1.Widget", string(sch.Files()[0].Messages().Get(0).FullName()))
+}
+
+func (s *SchemaSuite) TestCompileSources_Error() {
+	_, err := CompileSources(context.Background(), map[string]string{"bad.proto": "message {"})
+	s.Assert().Error(err)
+}
+
+func (s *SchemaSuite) TestCompileSources_Empty() {
+	_, err := CompileSources(context.Background(), map[string]string{})
+	s.Assert().Error(err)
+}
+
+func (s *SchemaSuite) TestImage_ExcludesImportsAndSourceInfo() {
+	set := s.compile().Image()
+
+	s.Require().Len(set.GetFile(), 1)
+	s.Assert().Equal("acme/v1/widget.proto", set.GetFile()[0].GetName())
+	s.Assert().Nil(set.GetFile()[0].GetSourceCodeInfo())
+}
+
+func (s *SchemaSuite) TestImage_RoundTrip() {
+	set := s.compile().Image()
+
+	first, err := MarshalImage(set)
+	s.Require().NoError(err)
+	second, err := MarshalImage(s.compile().Image())
+	s.Require().NoError(err)
+	s.Assert().Equal(first, second, "image encoding must be deterministic")
+
+	decoded, err := UnmarshalImage(first)
+	s.Require().NoError(err)
+	s.Assert().True(proto.Equal(set, decoded))
+}
+
+func (s *SchemaSuite) TestUnmarshalImage_Invalid() {
+	_, err := UnmarshalImage([]byte{0xff, 0xff})
+	s.Assert().Error(err)
+}
+
+func (s *SchemaSuite) TestGenerate() {
+	files, err := s.compile().Generate()
+	s.Require().NoError(err)
+
+	s.Require().Contains(files, "acme/v1/widget.pb.go")
+	code := string(files["acme/v1/widget.pb.go"])
+	s.Assert().True(strings.HasPrefix(code, "// Code generated by protoc-gen-go. DO NOT EDIT."))
+	s.Assert().Contains(code, "package acmev1")
+	s.Assert().Contains(code, "// Widget is documented.")
+	s.Assert().Contains(code, "type Widget struct")
+}
+
+func TestSchemaSuite(t *testing.T) {
+	suite.Run(t, new(SchemaSuite))
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/bbsbb/go-edge/core/proto/internal/breaking"
+	"github.com/bbsbb/go-edge/core/proto/internal/lint"
+	"github.com/bbsbb/go-edge/core/proto/internal/schema"
+)
+
+func main() {
+	command := ""
+	if len(os.Args) > 1 {
+		command = os.Args[1]
+	}
+
+	var err error
+	switch command {
+	case "generate":
+		err = runGenerate()
+	case "lint":
+		err = runLint()
+	case "breaking":
+		err = runBreaking(os.Args[2:])
+	default:
+		err = fmt.Errorf("unknown command: %q (expected: generate, lint, breaking)", command)
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "proto: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// runGenerate writes the generated Go code and the descriptor-set image.
+func runGenerate() error {
+	s, err := schema.Compile(context.Background(), ".")
+	if err != nil {
+		return err
+	}
+
+	files, err := s.Generate()
+	if err != nil {
+		return err
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.FromSlash(name), content, 0o600); err != nil {
+			return fmt.Errorf("write %s: %w", name, err)
+		}
+	}
+
+	image, err := schema.MarshalImage(s.Image())
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(schema.ImageFile, image, 0o600); err != nil {
+		return fmt.Errorf("write %s: %w", schema.ImageFile, err)
+	}
+	return nil
+}
+
+func runLint() error {
+	s, err := schema.Compile(context.Background(), ".")
+	if err != nil {
+		return err
+	}
+
+	violations := lint.Check(s.Image().GetFile())
+	for _, v := range violations {
+		fmt.Fprintln(os.Stderr, v)
+	}
+	if len(violations) > 0 {
+		return fmt.Errorf("%d lint violation(s)", len(violations))
+	}
+	return nil
+}
+
+// runBreaking compares the working tree schema against the committed image at
+// a git revision. A revision without an image (e.g. before this module
+// existed) has nothing to break; a revision git cannot find is an error.
+func runBreaking(args []string) error {
+	flags := flag.NewFlagSet("breaking", flag.ContinueOnError)
+	against := flags.String("against", "HEAD~1", "git revision holding the previous image")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	// A missing revision (a typo, or a shallow clone without it) fails the
+	// check; only an image that did not exist yet at that revision skips it.
+	image := *against + ":./" + schema.ImageFile
+	if ok, err := gitObjectExists(*against + "^{commit}"); err != nil {
+		return err
+	} else if !ok {
+		return fmt.Errorf("revision %s not found; is the clone shallow?", *against)
+	}
+	if ok, err := gitObjectExists(image); err != nil {
+		return err
+	} else if !ok {
+		fmt.Fprintf(os.Stderr, "proto: no image at %s, skipping breaking check\n", *against)
+		return nil
+	}
+
+	var stderr bytes.Buffer
+	cmd := exec.Command("git", "show", image) //nolint:gosec // revision is an operator-supplied flag
+	cmd.Stderr = &stderr
+	previousImage, err := cmd.Output()
+	if err != nil {
+		return fmt.Errorf("read image at %s: %w: %s", *against, err, bytes.TrimSpace(stderr.Bytes()))
+	}
+
+	previous, err := schema.UnmarshalImage(previousImage)
+	if err != nil {
+		return err
+	}
+	s, err := schema.Compile(context.Background(), ".")
+	if err != nil {
+		return err
+	}
+
+	changes := breaking.Compare(previous, s.Image())
+	for _, c := range changes {
+		fmt.Fprintln(os.Stderr, c)
+	}
+	if len(changes) > 0 {
+		return fmt.Errorf("%d breaking change(s) against %s", len(changes), *against)
+	}
+	return nil
+}
+
+// gitObjectExists reports whether git can resolve object, such as a revision
+// or a path at a revision. It fails only when git itself cannot be run.
+func gitObjectExists(object string) (bool, error) {
+	err := exec.Command("git", "cat-file", "-e", object).Run() //nolint:gosec // object names an operator-supplied revision
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("git cat-file %s: %w", object, err)
+	}
+	return true, nil
+}
@@ -0,0 +1,4 @@
+// Package sweetshopv1 holds the generated Go types for the sweetshop v1
+// contracts: money, products, orders and the domain event envelope. Edit the
+// .proto sources and run `make proto-generate`; never edit the *.pb.go files.
+package sweetshopv1
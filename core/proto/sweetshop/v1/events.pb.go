@@ -0,0 +1,709 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: sweetshop/v1/events.proto
+
+package sweetshopv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Event is the envelope for every sweetshop domain event. Exactly one
+// payload is set.
+type Event struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	OrganizationId string                 `protobuf:"bytes,2,opt,name=organization_id,json=organizationId,proto3" json:"organization_id,omitempty"`
+	OccurredAt     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=occurred_at,json=occurredAt,proto3" json:"occurred_at,omitempty"`
+	CorrelationId  string                 `protobuf:"bytes,4,opt,name=correlation_id,json=correlationId,proto3" json:"correlation_id,omitempty"`
+	// Types that are valid to be assigned to Payload:
+	//
+	//	*Event_ProductCreated
+	//	*Event_ProductUpdated
+	//	*Event_ProductDeleted
+	//	*Event_OrderCreated
+	//	*Event_OrderItemAdded
+	//	*Event_OrderClosed
+	//	*Event_OrderStatusChanged
+	Payload       isEvent_Payload `protobuf_oneof:"payload"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Event) Reset() {
+	*x = Event{}
+	mi := &file_sweetshop_v1_events_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Event) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Event) ProtoMessage() {}
+
+func (x *Event) ProtoReflect() protoreflect.Message {
+	mi := &file_sweetshop_v1_events_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Event.ProtoReflect.Descriptor instead.
+func (*Event) Descriptor() ([]byte, []int) {
+	return file_sweetshop_v1_events_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Event) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Event) GetOrganizationId() string {
+	if x != nil {
+		return x.OrganizationId
+	}
+	return ""
+}
+
+func (x *Event) GetOccurredAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.OccurredAt
+	}
+	return nil
+}
+
+func (x *Event) GetCorrelationId() string {
+	if x != nil {
+		return x.CorrelationId
+	}
+	return ""
+}
+
+func (x *Event) GetPayload() isEvent_Payload {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *Event) GetProductCreated() *ProductCreated {
+	if x != nil {
+		if x, ok := x.Payload.(*Event_ProductCreated); ok {
+			return x.ProductCreated
+		}
+	}
+	return nil
+}
+
+func (x *Event) GetProductUpdated() *ProductUpdated {
+	if x != nil {
+		if x, ok := x.Payload.(*Event_ProductUpdated); ok {
+			return x.ProductUpdated
+		}
+	}
+	return nil
+}
+
+func (x *Event) GetProductDeleted() *ProductDeleted {
+	if x != nil {
+		if x, ok := x.Payload.(*Event_ProductDeleted); ok {
+			return x.ProductDeleted
+		}
+	}
+	return nil
+}
+
+func (x *Event) GetOrderCreated() *OrderCreated {
+	if x != nil {
+		if x, ok := x.Payload.(*Event_OrderCreated); ok {
+			return x.OrderCreated
+		}
+	}
+	return nil
+}
+
+func (x *Event) GetOrderItemAdded() *OrderItemAdded {
+	if x != nil {
+		if x, ok := x.Payload.(*Event_OrderItemAdded); ok {
+			return x.OrderItemAdded
+		}
+	}
+	return nil
+}
+
+// Deprecated: Marked as deprecated in sweetshop/v1/events.proto.
+func (x *Event) GetOrderClosed() *OrderClosed {
+	if x != nil {
+		if x, ok := x.Payload.(*Event_OrderClosed); ok {
+			return x.OrderClosed
+		}
+	}
+	return nil
+}
+
+func (x *Event) GetOrderStatusChanged() *OrderStatusChanged {
+	if x != nil {
+		if x, ok := x.Payload.(*Event_OrderStatusChanged); ok {
+			return x.OrderStatusChanged
+		}
+	}
+	return nil
+}
+
+type isEvent_Payload interface {
+	isEvent_Payload()
+}
+
+type Event_ProductCreated struct {
+	ProductCreated *ProductCreated `protobuf:"bytes,10,opt,name=product_created,json=productCreated,proto3,oneof"`
+}
+
+type Event_ProductUpdated struct {
+	ProductUpdated *ProductUpdated `protobuf:"bytes,11,opt,name=product_updated,json=productUpdated,proto3,oneof"`
+}
+
+type Event_ProductDeleted struct {
+	ProductDeleted *ProductDeleted `protobuf:"bytes,12,opt,name=product_deleted,json=productDeleted,proto3,oneof"`
+}
+
+type Event_OrderCreated struct {
+	OrderCreated *OrderCreated `protobuf:"bytes,13,opt,name=order_created,json=orderCreated,proto3,oneof"`
+}
+
+type Event_OrderItemAdded struct {
+	OrderItemAdded *OrderItemAdded `protobuf:"bytes,14,opt,name=order_item_added,json=orderItemAdded,proto3,oneof"`
+}
+
+type Event_OrderClosed struct {
+	// Deprecated: Marked as deprecated in sweetshop/v1/events.proto.
+	OrderClosed *OrderClosed `protobuf:"bytes,15,opt,name=order_closed,json=orderClosed,proto3,oneof"`
+}
+
+type Event_OrderStatusChanged struct {
+	OrderStatusChanged *OrderStatusChanged `protobuf:"bytes,16,opt,name=order_status_changed,json=orderStatusChanged,proto3,oneof"`
+}
+
+func (*Event_ProductCreated) isEvent_Payload() {}
+
+func (*Event_ProductUpdated) isEvent_Payload() {}
+
+func (*Event_ProductDeleted) isEvent_Payload() {}
+
+func (*Event_OrderCreated) isEvent_Payload() {}
+
+func (*Event_OrderItemAdded) isEvent_Payload() {}
+
+func (*Event_OrderClosed) isEvent_Payload() {}
+
+func (*Event_OrderStatusChanged) isEvent_Payload() {}
+
+// ProductCreated is emitted after a product is created.
+type ProductCreated struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Product       *Product               `protobuf:"bytes,1,opt,name=product,proto3" json:"product,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProductCreated) Reset() {
+	*x = ProductCreated{}
+	mi := &file_sweetshop_v1_events_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProductCreated) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProductCreated) ProtoMessage() {}
+
+func (x *ProductCreated) ProtoReflect() protoreflect.Message {
+	mi := &file_sweetshop_v1_events_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProductCreated.ProtoReflect.Descriptor instead.
+func (*ProductCreated) Descriptor() ([]byte, []int) {
+	return file_sweetshop_v1_events_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ProductCreated) GetProduct() *Product {
+	if x != nil {
+		return x.Product
+	}
+	return nil
+}
+
+// ProductUpdated is emitted after a product is updated.
+type ProductUpdated struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Product       *Product               `protobuf:"bytes,1,opt,name=product,proto3" json:"product,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProductUpdated) Reset() {
+	*x = ProductUpdated{}
+	mi := &file_sweetshop_v1_events_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProductUpdated) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProductUpdated) ProtoMessage() {}
+
+func (x *ProductUpdated) ProtoReflect() protoreflect.Message {
+	mi := &file_sweetshop_v1_events_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProductUpdated.ProtoReflect.Descriptor instead.
+func (*ProductUpdated) Descriptor() ([]byte, []int) {
+	return file_sweetshop_v1_events_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ProductUpdated) GetProduct() *Product {
+	if x != nil {
+		return x.Product
+	}
+	return nil
+}
+
+// ProductDeleted is emitted after a product is soft-deleted.
+type ProductDeleted struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProductDeleted) Reset() {
+	*x = ProductDeleted{}
+	mi := &file_sweetshop_v1_events_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProductDeleted) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProductDeleted) ProtoMessage() {}
+
+func (x *ProductDeleted) ProtoReflect() protoreflect.Message {
+	mi := &file_sweetshop_v1_events_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProductDeleted.ProtoReflect.Descriptor instead.
+func (*ProductDeleted) Descriptor() ([]byte, []int) {
+	return file_sweetshop_v1_events_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ProductDeleted) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+// OrderCreated is emitted after an order is opened.
+type OrderCreated struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Order         *Order                 `protobuf:"bytes,1,opt,name=order,proto3" json:"order,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderCreated) Reset() {
+	*x = OrderCreated{}
+	mi := &file_sweetshop_v1_events_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderCreated) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderCreated) ProtoMessage() {}
+
+func (x *OrderCreated) ProtoReflect() protoreflect.Message {
+	mi := &file_sweetshop_v1_events_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderCreated.ProtoReflect.Descriptor instead.
+func (*OrderCreated) Descriptor() ([]byte, []int) {
+	return file_sweetshop_v1_events_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *OrderCreated) GetOrder() *Order {
+	if x != nil {
+		return x.Order
+	}
+	return nil
+}
+
+// OrderItemAdded is emitted after an item is added to an open order.
+type OrderItemAdded struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	Item          *OrderItem             `protobuf:"bytes,2,opt,name=item,proto3" json:"item,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderItemAdded) Reset() {
+	*x = OrderItemAdded{}
+	mi := &file_sweetshop_v1_events_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderItemAdded) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderItemAdded) ProtoMessage() {}
+
+func (x *OrderItemAdded) ProtoReflect() protoreflect.Message {
+	mi := &file_sweetshop_v1_events_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderItemAdded.ProtoReflect.Descriptor instead.
+func (*OrderItemAdded) Descriptor() ([]byte, []int) {
+	return file_sweetshop_v1_events_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *OrderItemAdded) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *OrderItemAdded) GetItem() *OrderItem {
+	if x != nil {
+		return x.Item
+	}
+	return nil
+}
+
+// OrderClosed is emitted after an order is closed.
+//
+// Deprecated: orders no longer close; use OrderStatusChanged.
+//
+// Deprecated: Marked as deprecated in sweetshop/v1/events.proto.
+type OrderClosed struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	TotalCents    int64                  `protobuf:"varint,2,opt,name=total_cents,json=totalCents,proto3" json:"total_cents,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderClosed) Reset() {
+	*x = OrderClosed{}
+	mi := &file_sweetshop_v1_events_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderClosed) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderClosed) ProtoMessage() {}
+
+func (x *OrderClosed) ProtoReflect() protoreflect.Message {
+	mi := &file_sweetshop_v1_events_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderClosed.ProtoReflect.Descriptor instead.
+func (*OrderClosed) Descriptor() ([]byte, []int) {
+	return file_sweetshop_v1_events_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *OrderClosed) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *OrderClosed) GetTotalCents() int64 {
+	if x != nil {
+		return x.TotalCents
+	}
+	return 0
+}
+
+// OrderStatusChanged is emitted after an order moves through its lifecycle.
+// from is unset for the initial open status.
+type OrderStatusChanged struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	From          OrderStatus            `protobuf:"varint,2,opt,name=from,proto3,enum=sweetshop.v1.OrderStatus" json:"from,omitempty"`
+	To            OrderStatus            `protobuf:"varint,3,opt,name=to,proto3,enum=sweetshop.v1.OrderStatus" json:"to,omitempty"`
+	TotalCents    int64                  `protobuf:"varint,4,opt,name=total_cents,json=totalCents,proto3" json:"total_cents,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderStatusChanged) Reset() {
+	*x = OrderStatusChanged{}
+	mi := &file_sweetshop_v1_events_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderStatusChanged) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderStatusChanged) ProtoMessage() {}
+
+func (x *OrderStatusChanged) ProtoReflect() protoreflect.Message {
+	mi := &file_sweetshop_v1_events_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderStatusChanged.ProtoReflect.Descriptor instead.
+func (*OrderStatusChanged) Descriptor() ([]byte, []int) {
+	return file_sweetshop_v1_events_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *OrderStatusChanged) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *OrderStatusChanged) GetFrom() OrderStatus {
+	if x != nil {
+		return x.From
+	}
+	return OrderStatus_ORDER_STATUS_UNSPECIFIED
+}
+
+func (x *OrderStatusChanged) GetTo() OrderStatus {
+	if x != nil {
+		return x.To
+	}
+	return OrderStatus_ORDER_STATUS_UNSPECIFIED
+}
+
+func (x *OrderStatusChanged) GetTotalCents() int64 {
+	if x != nil {
+		return x.TotalCents
+	}
+	return 0
+}
+
+var File_sweetshop_v1_events_proto protoreflect.FileDescriptor
+
+const file_sweetshop_v1_events_proto_rawDesc = "" +
+	"\n" +
+	"\x19sweetshop/v1/events.proto\x12\fsweetshop.v1\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x18sweetshop/v1/order.proto\x1a\x1asweetshop/v1/product.proto\"\xb1\x05\n" +
+	"\x05Event\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12'\n" +
+	"\x0forganization_id\x18\x02 \x01(\tR\x0eorganizationId\x12;\n" +
+	"\voccurred_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"occurredAt\x12%\n" +
+	"\x0ecorrelation_id\x18\x04 \x01(\tR\rcorrelationId\x12G\n" +
+	"\x0fproduct_created\x18\n" +
+	" \x01(\v2\x1c.sweetshop.v1.ProductCreatedH\x00R\x0eproductCreated\x12G\n" +
+	"\x0fproduct_updated\x18\v \x01(\v2\x1c.sweetshop.v1.ProductUpdatedH\x00R\x0eproductUpdated\x12G\n" +
+	"\x0fproduct_deleted\x18\f \x01(\v2\x1c.sweetshop.v1.ProductDeletedH\x00R\x0eproductDeleted\x12A\n" +
+	"\rorder_created\x18\r \x01(\v2\x1a.sweetshop.v1.OrderCreatedH\x00R\forderCreated\x12H\n" +
+	"\x10order_item_added\x18\x0e \x01(\v2\x1c.sweetshop.v1.OrderItemAddedH\x00R\x0eorderItemAdded\x12B\n" +
+	"\forder_closed\x18\x0f \x01(\v2\x19.sweetshop.v1.OrderClosedB\x02\x18\x01H\x00R\vorderClosed\x12T\n" +
+	"\x14order_status_changed\x18\x10 \x01(\v2 .sweetshop.v1.OrderStatusChangedH\x00R\x12orderStatusChangedB\t\n" +
+	"\apayload\"A\n" +
+	"\x0eProductCreated\x12/\n" +
+	"\aproduct\x18\x01 \x01(\v2\x15.sweetshop.v1.ProductR\aproduct\"A\n" +
+	"\x0eProductUpdated\x12/\n" +
+	"\aproduct\x18\x01 \x01(\v2\x15.sweetshop.v1.ProductR\aproduct\"/\n" +
+	"\x0eProductDeleted\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\tR\tproductId\"9\n" +
+	"\fOrderCreated\x12)\n" +
+	"\x05order\x18\x01 \x01(\v2\x13.sweetshop.v1.OrderR\x05order\"X\n" +
+	"\x0eOrderItemAdded\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\x12+\n" +
+	"\x04item\x18\x02 \x01(\v2\x17.sweetshop.v1.OrderItemR\x04item\"M\n" +
+	"\vOrderClosed\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\x12\x1f\n" +
+	"\vtotal_cents\x18\x02 \x01(\x03R\n" +
+	"totalCents:\x02\x18\x01\"\xaa\x01\n" +
+	"\x12OrderStatusChanged\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\x12-\n" +
+	"\x04from\x18\x02 \x01(\x0e2\x19.sweetshop.v1.OrderStatusR\x04from\x12)\n" +
+	"\x02to\x18\x03 \x01(\x0e2\x19.sweetshop.v1.OrderStatusR\x02to\x12\x1f\n" +
+	"\vtotal_cents\x18\x04 \x01(\x03R\n" +
+	"totalCentsB>Z<github.com/bbsbb/go-edge/core/proto/sweetshop/v1;sweetshopv1b\x06proto3"
+
+var (
+	file_sweetshop_v1_events_proto_rawDescOnce sync.Once
+	file_sweetshop_v1_events_proto_rawDescData []byte
+)
+
+func file_sweetshop_v1_events_proto_rawDescGZIP() []byte {
+	file_sweetshop_v1_events_proto_rawDescOnce.Do(func() {
+		file_sweetshop_v1_events_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_sweetshop_v1_events_proto_rawDesc), len(file_sweetshop_v1_events_proto_rawDesc)))
+	})
+	return file_sweetshop_v1_events_proto_rawDescData
+}
+
+var file_sweetshop_v1_events_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_sweetshop_v1_events_proto_goTypes = []any{
+	(*Event)(nil),                 // 0: sweetshop.v1.Event
+	(*ProductCreated)(nil),        // 1: sweetshop.v1.ProductCreated
+	(*ProductUpdated)(nil),        // 2: sweetshop.v1.ProductUpdated
+	(*ProductDeleted)(nil),        // 3: sweetshop.v1.ProductDeleted
+	(*OrderCreated)(nil),          // 4: sweetshop.v1.OrderCreated
+	(*OrderItemAdded)(nil),        // 5: sweetshop.v1.OrderItemAdded
+	(*OrderClosed)(nil),           // 6: sweetshop.v1.OrderClosed
+	(*OrderStatusChanged)(nil),    // 7: sweetshop.v1.OrderStatusChanged
+	(*timestamppb.Timestamp)(nil), // 8: google.protobuf.Timestamp
+	(*Product)(nil),               // 9: sweetshop.v1.Product
+	(*Order)(nil),                 // 10: sweetshop.v1.Order
+	(*OrderItem)(nil),             // 11: sweetshop.v1.OrderItem
+	(OrderStatus)(0),              // 12: sweetshop.v1.OrderStatus
+}
+var file_sweetshop_v1_events_proto_depIdxs = []int32{
+	8,  // 0: sweetshop.v1.Event.occurred_at:type_name -> google.protobuf.Timestamp
+	1,  // 1: sweetshop.v1.Event.product_created:type_name -> sweetshop.v1.ProductCreated
+	2,  // 2: sweetshop.v1.Event.product_updated:type_name -> sweetshop.v1.ProductUpdated
+	3,  // 3: sweetshop.v1.Event.product_deleted:type_name -> sweetshop.v1.ProductDeleted
+	4,  // 4: sweetshop.v1.Event.order_created:type_name -> sweetshop.v1.OrderCreated
+	5,  // 5: sweetshop.v1.Event.order_item_added:type_name -> sweetshop.v1.OrderItemAdded
+	6,  // 6: sweetshop.v1.Event.order_closed:type_name -> sweetshop.v1.OrderClosed
+	7,  // 7: sweetshop.v1.Event.order_status_changed:type_name -> sweetshop.v1.OrderStatusChanged
+	9,  // 8: sweetshop.v1.ProductCreated.product:type_name -> sweetshop.v1.Product
+	9,  // 9: sweetshop.v1.ProductUpdated.product:type_name -> sweetshop.v1.Product
+	10, // 10: sweetshop.v1.OrderCreated.order:type_name -> sweetshop.v1.Order
+	11, // 11: sweetshop.v1.OrderItemAdded.item:type_name -> sweetshop.v1.OrderItem
+	12, // 12: sweetshop.v1.OrderStatusChanged.from:type_name -> sweetshop.v1.OrderStatus
+	12, // 13: sweetshop.v1.OrderStatusChanged.to:type_name -> sweetshop.v1.OrderStatus
+	14, // [14:14] is the sub-list for method output_type
+	14, // [14:14] is the sub-list for method input_type
+	14, // [14:14] is the sub-list for extension type_name
+	14, // [14:14] is the sub-list for extension extendee
+	0,  // [0:14] is the sub-list for field type_name
+}
+
+func init() { file_sweetshop_v1_events_proto_init() }
+func file_sweetshop_v1_events_proto_init() {
+	if File_sweetshop_v1_events_proto != nil {
+		return
+	}
+	file_sweetshop_v1_order_proto_init()
+	file_sweetshop_v1_product_proto_init()
+	file_sweetshop_v1_events_proto_msgTypes[0].OneofWrappers = []any{
+		(*Event_ProductCreated)(nil),
+		(*Event_ProductUpdated)(nil),
+		(*Event_ProductDeleted)(nil),
+		(*Event_OrderCreated)(nil),
+		(*Event_OrderItemAdded)(nil),
+		(*Event_OrderClosed)(nil),
+		(*Event_OrderStatusChanged)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_sweetshop_v1_events_proto_rawDesc), len(file_sweetshop_v1_events_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   8,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_sweetshop_v1_events_proto_goTypes,
+		DependencyIndexes: file_sweetshop_v1_events_proto_depIdxs,
+		MessageInfos:      file_sweetshop_v1_events_proto_msgTypes,
+	}.Build()
+	File_sweetshop_v1_events_proto = out.File
+	file_sweetshop_v1_events_proto_goTypes = nil
+	file_sweetshop_v1_events_proto_depIdxs = nil
+}
@@ -0,0 +1,57 @@
+package sweetshopv1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type EventSuite struct {
+	suite.Suite
+}
+
+func (s *EventSuite) event() *Event {
+	return &Event{
+		Id:             "0190a5f0-0000-7000-8000-000000000001",
+		OrganizationId: "0190a5f0-0000-7000-8000-000000000002",
+		OccurredAt:     timestamppb.Now(),
+		Payload: &Event_OrderItemAdded{OrderItemAdded: &OrderItemAdded{
+			OrderId: "0190a5f0-0000-7000-8000-000000000003",
+			Item: &OrderItem{
+				ProductName:    "Vanilla",
+				Quantity:       2,
+				PriceCents:     350,
+				LineTotalCents: 700,
+			},
+		}},
+	}
+}
+
+func (s *EventSuite) TestBinaryRoundTrip() {
+	original := s.event()
+
+	data, err := proto.Marshal(original)
+	s.Require().NoError(err)
+
+	decoded := &Event{}
+	s.Require().NoError(proto.Unmarshal(data, decoded))
+	s.Assert().True(proto.Equal(original, decoded))
+	s.Assert().Equal(int64(700), decoded.GetOrderItemAdded().GetItem().GetLineTotalCents())
+	s.Assert().Nil(decoded.GetOrderClosed())
+}
+
+func (s *EventSuite) TestJSONUsesCamelCaseNames() {
+	data, err := protojson.Marshal(s.event())
+	s.Require().NoError(err)
+
+	s.Assert().Contains(string(data), `"organizationId"`)
+	s.Assert().Contains(string(data), `"orderItemAdded"`)
+	s.Assert().Contains(string(data), `"lineTotalCents":"700"`)
+}
+
+func TestEventSuite(t *testing.T) {
+	suite.Run(t, new(EventSuite))
+}
@@ -0,0 +1,378 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: sweetshop/v1/order.proto
+
+package sweetshopv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// OrderStatus mirrors the sweetshop domain order lifecycle.
+type OrderStatus int32
+
+const (
+	OrderStatus_ORDER_STATUS_UNSPECIFIED OrderStatus = 0
+	OrderStatus_ORDER_STATUS_OPEN        OrderStatus = 1
+	// Deprecated: closed orders were migrated to ORDER_STATUS_FULFILLED.
+	//
+	// Deprecated: Marked as deprecated in sweetshop/v1/order.proto.
+	OrderStatus_ORDER_STATUS_CLOSED    OrderStatus = 2
+	OrderStatus_ORDER_STATUS_SUBMITTED OrderStatus = 3
+	OrderStatus_ORDER_STATUS_PAID      OrderStatus = 4
+	OrderStatus_ORDER_STATUS_FULFILLED OrderStatus = 5
+	OrderStatus_ORDER_STATUS_CANCELLED OrderStatus = 6
+)
+
+// Enum value maps for OrderStatus.
+var (
+	OrderStatus_name = map[int32]string{
+		0: "ORDER_STATUS_UNSPECIFIED",
+		1: "ORDER_STATUS_OPEN",
+		2: "ORDER_STATUS_CLOSED",
+		3: "ORDER_STATUS_SUBMITTED",
+		4: "ORDER_STATUS_PAID",
+		5: "ORDER_STATUS_FULFILLED",
+		6: "ORDER_STATUS_CANCELLED",
+	}
+	OrderStatus_value = map[string]int32{
+		"ORDER_STATUS_UNSPECIFIED": 0,
+		"ORDER_STATUS_OPEN":        1,
+		"ORDER_STATUS_CLOSED":      2,
+		"ORDER_STATUS_SUBMITTED":   3,
+		"ORDER_STATUS_PAID":        4,
+		"ORDER_STATUS_FULFILLED":   5,
+		"ORDER_STATUS_CANCELLED":   6,
+	}
+)
+
+func (x OrderStatus) Enum() *OrderStatus {
+	p := new(OrderStatus)
+	*p = x
+	return p
+}
+
+func (x OrderStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (OrderStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_sweetshop_v1_order_proto_enumTypes[0].Descriptor()
+}
+
+func (OrderStatus) Type() protoreflect.EnumType {
+	return &file_sweetshop_v1_order_proto_enumTypes[0]
+}
+
+func (x OrderStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use OrderStatus.Descriptor instead.
+func (OrderStatus) EnumDescriptor() ([]byte, []int) {
+	return file_sweetshop_v1_order_proto_rawDescGZIP(), []int{0}
+}
+
+// OrderItem is a single line of an order. Product name and price are
+// snapshotted when the item is added.
+type OrderItem struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	OrderId        string                 `protobuf:"bytes,2,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	ProductId      string                 `protobuf:"bytes,3,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	ProductName    string                 `protobuf:"bytes,4,opt,name=product_name,json=productName,proto3" json:"product_name,omitempty"`
+	CreatedAt      *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	Quantity       int32                  `protobuf:"varint,6,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	PriceCents     int32                  `protobuf:"varint,7,opt,name=price_cents,json=priceCents,proto3" json:"price_cents,omitempty"`
+	LineTotalCents int64                  `protobuf:"varint,8,opt,name=line_total_cents,json=lineTotalCents,proto3" json:"line_total_cents,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *OrderItem) Reset() {
+	*x = OrderItem{}
+	mi := &file_sweetshop_v1_order_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderItem) ProtoMessage() {}
+
+func (x *OrderItem) ProtoReflect() protoreflect.Message {
+	mi := &file_sweetshop_v1_order_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderItem.ProtoReflect.Descriptor instead.
+func (*OrderItem) Descriptor() ([]byte, []int) {
+	return file_sweetshop_v1_order_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *OrderItem) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *OrderItem) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *OrderItem) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *OrderItem) GetProductName() string {
+	if x != nil {
+		return x.ProductName
+	}
+	return ""
+}
+
+func (x *OrderItem) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *OrderItem) GetQuantity() int32 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *OrderItem) GetPriceCents() int32 {
+	if x != nil {
+		return x.PriceCents
+	}
+	return 0
+}
+
+func (x *OrderItem) GetLineTotalCents() int64 {
+	if x != nil {
+		return x.LineTotalCents
+	}
+	return 0
+}
+
+// Order groups order items for a single organization.
+type Order struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	OrganizationId string                 `protobuf:"bytes,2,opt,name=organization_id,json=organizationId,proto3" json:"organization_id,omitempty"`
+	CreatedAt      *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt      *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	Status         OrderStatus            `protobuf:"varint,5,opt,name=status,proto3,enum=sweetshop.v1.OrderStatus" json:"status,omitempty"`
+	Items          []*OrderItem           `protobuf:"bytes,6,rep,name=items,proto3" json:"items,omitempty"`
+	TotalCents     int64                  `protobuf:"varint,7,opt,name=total_cents,json=totalCents,proto3" json:"total_cents,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Order) Reset() {
+	*x = Order{}
+	mi := &file_sweetshop_v1_order_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Order) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Order) ProtoMessage() {}
+
+func (x *Order) ProtoReflect() protoreflect.Message {
+	mi := &file_sweetshop_v1_order_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Order.ProtoReflect.Descriptor instead.
+func (*Order) Descriptor() ([]byte, []int) {
+	return file_sweetshop_v1_order_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Order) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Order) GetOrganizationId() string {
+	if x != nil {
+		return x.OrganizationId
+	}
+	return ""
+}
+
+func (x *Order) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Order) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *Order) GetStatus() OrderStatus {
+	if x != nil {
+		return x.Status
+	}
+	return OrderStatus_ORDER_STATUS_UNSPECIFIED
+}
+
+func (x *Order) GetItems() []*OrderItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *Order) GetTotalCents() int64 {
+	if x != nil {
+		return x.TotalCents
+	}
+	return 0
+}
+
+var File_sweetshop_v1_order_proto protoreflect.FileDescriptor
+
+const file_sweetshop_v1_order_proto_rawDesc = "" +
+	"\n" +
+	"\x18sweetshop/v1/order.proto\x12\fsweetshop.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\x9a\x02\n" +
+	"\tOrderItem\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x19\n" +
+	"\border_id\x18\x02 \x01(\tR\aorderId\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x03 \x01(\tR\tproductId\x12!\n" +
+	"\fproduct_name\x18\x04 \x01(\tR\vproductName\x129\n" +
+	"\n" +
+	"created_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12\x1a\n" +
+	"\bquantity\x18\x06 \x01(\x05R\bquantity\x12\x1f\n" +
+	"\vprice_cents\x18\a \x01(\x05R\n" +
+	"priceCents\x12(\n" +
+	"\x10line_total_cents\x18\b \x01(\x03R\x0elineTotalCents\"\xb9\x02\n" +
+	"\x05Order\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12'\n" +
+	"\x0forganization_id\x18\x02 \x01(\tR\x0eorganizationId\x129\n" +
+	"\n" +
+	"created_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x121\n" +
+	"\x06status\x18\x05 \x01(\x0e2\x19.sweetshop.v1.OrderStatusR\x06status\x12-\n" +
+	"\x05items\x18\x06 \x03(\v2\x17.sweetshop.v1.OrderItemR\x05items\x12\x1f\n" +
+	"\vtotal_cents\x18\a \x01(\x03R\n" +
+	"totalCents*\xca\x01\n" +
+	"\vOrderStatus\x12\x1c\n" +
+	"\x18ORDER_STATUS_UNSPECIFIED\x10\x00\x12\x15\n" +
+	"\x11ORDER_STATUS_OPEN\x10\x01\x12\x1b\n" +
+	"\x13ORDER_STATUS_CLOSED\x10\x02\x1a\x02\b\x01\x12\x1a\n" +
+	"\x16ORDER_STATUS_SUBMITTED\x10\x03\x12\x15\n" +
+	"\x11ORDER_STATUS_PAID\x10\x04\x12\x1a\n" +
+	"\x16ORDER_STATUS_FULFILLED\x10\x05\x12\x1a\n" +
+	"\x16ORDER_STATUS_CANCELLED\x10\x06B>Z<github.com/bbsbb/go-edge/core/proto/sweetshop/v1;sweetshopv1b\x06proto3"
+
+var (
+	file_sweetshop_v1_order_proto_rawDescOnce sync.Once
+	file_sweetshop_v1_order_proto_rawDescData []byte
+)
+
+func file_sweetshop_v1_order_proto_rawDescGZIP() []byte {
+	file_sweetshop_v1_order_proto_rawDescOnce.Do(func() {
+		file_sweetshop_v1_order_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_sweetshop_v1_order_proto_rawDesc), len(file_sweetshop_v1_order_proto_rawDesc)))
+	})
+	return file_sweetshop_v1_order_proto_rawDescData
+}
+
+var file_sweetshop_v1_order_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_sweetshop_v1_order_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_sweetshop_v1_order_proto_goTypes = []any{
+	(OrderStatus)(0),              // 0: sweetshop.v1.OrderStatus
+	(*OrderItem)(nil),             // 1: sweetshop.v1.OrderItem
+	(*Order)(nil),                 // 2: sweetshop.v1.Order
+	(*timestamppb.Timestamp)(nil), // 3: google.protobuf.Timestamp
+}
+var file_sweetshop_v1_order_proto_depIdxs = []int32{
+	3, // 0: sweetshop.v1.OrderItem.created_at:type_name -> google.protobuf.Timestamp
+	3, // 1: sweetshop.v1.Order.created_at:type_name -> google.protobuf.Timestamp
+	3, // 2: sweetshop.v1.Order.updated_at:type_name -> google.protobuf.Timestamp
+	0, // 3: sweetshop.v1.Order.status:type_name -> sweetshop.v1.OrderStatus
+	1, // 4: sweetshop.v1.Order.items:type_name -> sweetshop.v1.OrderItem
+	5, // [5:5] is the sub-list for method output_type
+	5, // [5:5] is the sub-list for method input_type
+	5, // [5:5] is the sub-list for extension type_name
+	5, // [5:5] is the sub-list for extension extendee
+	0, // [0:5] is the sub-list for field type_name
+}
+
+func init() { file_sweetshop_v1_order_proto_init() }
+func file_sweetshop_v1_order_proto_init() {
+	if File_sweetshop_v1_order_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_sweetshop_v1_order_proto_rawDesc), len(file_sweetshop_v1_order_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_sweetshop_v1_order_proto_goTypes,
+		DependencyIndexes: file_sweetshop_v1_order_proto_depIdxs,
+		EnumInfos:         file_sweetshop_v1_order_proto_enumTypes,
+		MessageInfos:      file_sweetshop_v1_order_proto_msgTypes,
+	}.Build()
+	File_sweetshop_v1_order_proto = out.File
+	file_sweetshop_v1_order_proto_goTypes = nil
+	file_sweetshop_v1_order_proto_depIdxs = nil
+}
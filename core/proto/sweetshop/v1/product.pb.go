@@ -0,0 +1,337 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: sweetshop/v1/product.proto
+
+package sweetshopv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// ProductCategory mirrors the two product categories sweetshop started with.
+//
+// Deprecated: categories are now defined per organization and named by slug;
+// see Product.category_slug.
+//
+// Deprecated: Marked as deprecated in sweetshop/v1/product.proto.
+type ProductCategory int32
+
+const (
+	ProductCategory_PRODUCT_CATEGORY_UNSPECIFIED ProductCategory = 0
+	ProductCategory_PRODUCT_CATEGORY_ICE_CREAM   ProductCategory = 1
+	ProductCategory_PRODUCT_CATEGORY_MARSHMALLOW ProductCategory = 2
+)
+
+// Enum value maps for ProductCategory.
+var (
+	ProductCategory_name = map[int32]string{
+		0: "PRODUCT_CATEGORY_UNSPECIFIED",
+		1: "PRODUCT_CATEGORY_ICE_CREAM",
+		2: "PRODUCT_CATEGORY_MARSHMALLOW",
+	}
+	ProductCategory_value = map[string]int32{
+		"PRODUCT_CATEGORY_UNSPECIFIED": 0,
+		"PRODUCT_CATEGORY_ICE_CREAM":   1,
+		"PRODUCT_CATEGORY_MARSHMALLOW": 2,
+	}
+)
+
+func (x ProductCategory) Enum() *ProductCategory {
+	p := new(ProductCategory)
+	*p = x
+	return p
+}
+
+func (x ProductCategory) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ProductCategory) Descriptor() protoreflect.EnumDescriptor {
+	return file_sweetshop_v1_product_proto_enumTypes[0].Descriptor()
+}
+
+func (ProductCategory) Type() protoreflect.EnumType {
+	return &file_sweetshop_v1_product_proto_enumTypes[0]
+}
+
+func (x ProductCategory) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ProductCategory.Descriptor instead.
+func (ProductCategory) EnumDescriptor() ([]byte, []int) {
+	return file_sweetshop_v1_product_proto_rawDescGZIP(), []int{0}
+}
+
+// Product is a catalogue entry owned by a single organization.
+type Product struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	OrganizationId string                 `protobuf:"bytes,2,opt,name=organization_id,json=organizationId,proto3" json:"organization_id,omitempty"`
+	CreatedAt      *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt      *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	Name           string                 `protobuf:"bytes,5,opt,name=name,proto3" json:"name,omitempty"`
+	// Deprecated: use category_slug, which names any of the organization's
+	// categories.
+	//
+	// Deprecated: Marked as deprecated in sweetshop/v1/product.proto.
+	Category ProductCategory `protobuf:"varint,6,opt,name=category,proto3,enum=sweetshop.v1.ProductCategory" json:"category,omitempty"`
+	// Deprecated: use price, which carries the currency and does not overflow
+	// at 2^31 minor units.
+	//
+	// Deprecated: Marked as deprecated in sweetshop/v1/product.proto.
+	PriceCents int32 `protobuf:"varint,7,opt,name=price_cents,json=priceCents,proto3" json:"price_cents,omitempty"`
+	// Set once the product has been soft-deleted.
+	DeletedAt *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=deleted_at,json=deletedAt,proto3" json:"deleted_at,omitempty"`
+	Price     *Money                 `protobuf:"bytes,9,opt,name=price,proto3" json:"price,omitempty"`
+	// Slug of the organization's category the product belongs to, such as
+	// "ice_cream".
+	CategorySlug string `protobuf:"bytes,10,opt,name=category_slug,json=categorySlug,proto3" json:"category_slug,omitempty"`
+	// Stock keeping unit, unique among the organization's live products; unset
+	// if the product has none.
+	Sku         *string `protobuf:"bytes,11,opt,name=sku,proto3,oneof" json:"sku,omitempty"`
+	Description string  `protobuf:"bytes,12,opt,name=description,proto3" json:"description,omitempty"`
+	// False once the product is archived: it stays listed but can no longer be
+	// ordered.
+	Active bool `protobuf:"varint,13,opt,name=active,proto3" json:"active,omitempty"`
+	// Position in listings, lowest first.
+	SortOrder     int32 `protobuf:"varint,14,opt,name=sort_order,json=sortOrder,proto3" json:"sort_order,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Product) Reset() {
+	*x = Product{}
+	mi := &file_sweetshop_v1_product_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Product) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Product) ProtoMessage() {}
+
+func (x *Product) ProtoReflect() protoreflect.Message {
+	mi := &file_sweetshop_v1_product_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Product.ProtoReflect.Descriptor instead.
+func (*Product) Descriptor() ([]byte, []int) {
+	return file_sweetshop_v1_product_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Product) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Product) GetOrganizationId() string {
+	if x != nil {
+		return x.OrganizationId
+	}
+	return ""
+}
+
+func (x *Product) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Product) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *Product) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+// Deprecated: Marked as deprecated in sweetshop/v1/product.proto.
+func (x *Product) GetCategory() ProductCategory {
+	if x != nil {
+		return x.Category
+	}
+	return ProductCategory_PRODUCT_CATEGORY_UNSPECIFIED
+}
+
+// Deprecated: Marked as deprecated in sweetshop/v1/product.proto.
+func (x *Product) GetPriceCents() int32 {
+	if x != nil {
+		return x.PriceCents
+	}
+	return 0
+}
+
+func (x *Product) GetDeletedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DeletedAt
+	}
+	return nil
+}
+
+func (x *Product) GetPrice() *Money {
+	if x != nil {
+		return x.Price
+	}
+	return nil
+}
+
+func (x *Product) GetCategorySlug() string {
+	if x != nil {
+		return x.CategorySlug
+	}
+	return ""
+}
+
+func (x *Product) GetSku() string {
+	if x != nil && x.Sku != nil {
+		return *x.Sku
+	}
+	return ""
+}
+
+func (x *Product) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Product) GetActive() bool {
+	if x != nil {
+		return x.Active
+	}
+	return false
+}
+
+func (x *Product) GetSortOrder() int32 {
+	if x != nil {
+		return x.SortOrder
+	}
+	return 0
+}
+
+var File_sweetshop_v1_product_proto protoreflect.FileDescriptor
+
+const file_sweetshop_v1_product_proto_rawDesc = "" +
+	"\n" +
+	"\x1asweetshop/v1/product.proto\x12\fsweetshop.v1\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x18sweetshop/v1/money.proto\"\xb3\x04\n" +
+	"\aProduct\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12'\n" +
+	"\x0forganization_id\x18\x02 \x01(\tR\x0eorganizationId\x129\n" +
+	"\n" +
+	"created_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12\x12\n" +
+	"\x04name\x18\x05 \x01(\tR\x04name\x12=\n" +
+	"\bcategory\x18\x06 \x01(\x0e2\x1d.sweetshop.v1.ProductCategoryB\x02\x18\x01R\bcategory\x12#\n" +
+	"\vprice_cents\x18\a \x01(\x05B\x02\x18\x01R\n" +
+	"priceCents\x129\n" +
+	"\n" +
+	"deleted_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tdeletedAt\x12)\n" +
+	"\x05price\x18\t \x01(\v2\x13.sweetshop.v1.MoneyR\x05price\x12#\n" +
+	"\rcategory_slug\x18\n" +
+	" \x01(\tR\fcategorySlug\x12\x15\n" +
+	"\x03sku\x18\v \x01(\tH\x00R\x03sku\x88\x01\x01\x12 \n" +
+	"\vdescription\x18\f \x01(\tR\vdescription\x12\x16\n" +
+	"\x06active\x18\r \x01(\bR\x06active\x12\x1d\n" +
+	"\n" +
+	"sort_order\x18\x0e \x01(\x05R\tsortOrderB\x06\n" +
+	"\x04_sku*y\n" +
+	"\x0fProductCategory\x12 \n" +
+	"\x1cPRODUCT_CATEGORY_UNSPECIFIED\x10\x00\x12\x1e\n" +
+	"\x1aPRODUCT_CATEGORY_ICE_CREAM\x10\x01\x12 \n" +
+	"\x1cPRODUCT_CATEGORY_MARSHMALLOW\x10\x02\x1a\x02\x18\x01B>Z<github.com/bbsbb/go-edge/core/proto/sweetshop/v1;sweetshopv1b\x06proto3"
+
+var (
+	file_sweetshop_v1_product_proto_rawDescOnce sync.Once
+	file_sweetshop_v1_product_proto_rawDescData []byte
+)
+
+func file_sweetshop_v1_product_proto_rawDescGZIP() []byte {
+	file_sweetshop_v1_product_proto_rawDescOnce.Do(func() {
+		file_sweetshop_v1_product_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_sweetshop_v1_product_proto_rawDesc), len(file_sweetshop_v1_product_proto_rawDesc)))
+	})
+	return file_sweetshop_v1_product_proto_rawDescData
+}
+
+var file_sweetshop_v1_product_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_sweetshop_v1_product_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_sweetshop_v1_product_proto_goTypes = []any{
+	(ProductCategory)(0),          // 0: sweetshop.v1.ProductCategory
+	(*Product)(nil),               // 1: sweetshop.v1.Product
+	(*timestamppb.Timestamp)(nil), // 2: google.protobuf.Timestamp
+	(*Money)(nil),                 // 3: sweetshop.v1.Money
+}
+var file_sweetshop_v1_product_proto_depIdxs = []int32{
+	2, // 0: sweetshop.v1.Product.created_at:type_name -> google.protobuf.Timestamp
+	2, // 1: sweetshop.v1.Product.updated_at:type_name -> google.protobuf.Timestamp
+	0, // 2: sweetshop.v1.Product.category:type_name -> sweetshop.v1.ProductCategory
+	2, // 3: sweetshop.v1.Product.deleted_at:type_name -> google.protobuf.Timestamp
+	3, // 4: sweetshop.v1.Product.price:type_name -> sweetshop.v1.Money
+	5, // [5:5] is the sub-list for method output_type
+	5, // [5:5] is the sub-list for method input_type
+	5, // [5:5] is the sub-list for extension type_name
+	5, // [5:5] is the sub-list for extension extendee
+	0, // [0:5] is the sub-list for field type_name
+}
+
+func init() { file_sweetshop_v1_product_proto_init() }
+func file_sweetshop_v1_product_proto_init() {
+	if File_sweetshop_v1_product_proto != nil {
+		return
+	}
+	file_sweetshop_v1_money_proto_init()
+	file_sweetshop_v1_product_proto_msgTypes[0].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_sweetshop_v1_product_proto_rawDesc), len(file_sweetshop_v1_product_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_sweetshop_v1_product_proto_goTypes,
+		DependencyIndexes: file_sweetshop_v1_product_proto_depIdxs,
+		EnumInfos:         file_sweetshop_v1_product_proto_enumTypes,
+		MessageInfos:      file_sweetshop_v1_product_proto_msgTypes,
+	}.Build()
+	File_sweetshop_v1_product_proto = out.File
+	file_sweetshop_v1_product_proto_goTypes = nil
+	file_sweetshop_v1_product_proto_depIdxs = nil
+}
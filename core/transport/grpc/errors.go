@@ -0,0 +1,128 @@
+// Package grpc provides gRPC transport utilities: domain error to status
+// translation and organization context extraction, mirroring core/transport/http.
+package grpc
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math"
+	"sync"
+	"time"
+
+	chimw "github.com/go-chi/chi/v5/middleware"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/bbsbb/go-edge/core/domain"
+)
+
+// ErrorInfoDomain is the ErrorInfo.Domain attached to translated domain errors.
+const ErrorInfoDomain = "go-edge"
+
+var (
+	codesMu   sync.RWMutex
+	grpcCodes = map[domain.Code]codes.Code{
+		domain.CodeNotFound:   codes.NotFound,
+		domain.CodeConflict:   codes.AlreadyExists,
+		domain.CodeValidation: codes.InvalidArgument,
+		domain.CodeForbidden:  codes.PermissionDenied,
+		domain.CodeInvariant:  codes.FailedPrecondition,
+	}
+)
+
+// RegisterCode maps an application domain code to a gRPC status code, the
+// gRPC counterpart of transporthttp.RegisterProblemType. Unregistered codes
+// translate to codes.Internal, as they render 500 over HTTP.
+func RegisterCode(code domain.Code, c codes.Code) {
+	codesMu.Lock()
+	defer codesMu.Unlock()
+	grpcCodes[code] = c
+}
+
+// CodeFor returns the gRPC code registered for a domain code.
+func CodeFor(code domain.Code) codes.Code {
+	codesMu.RLock()
+	defer codesMu.RUnlock()
+
+	if c, ok := grpcCodes[code]; ok {
+		return c
+	}
+	return codes.Internal
+}
+
+// ToStatus translates err into a gRPC status error. Errors that already carry
+// a status pass through; domain errors keep their message and gain an
+// ErrorInfo detail (reason = domain code, metadata = request ID and string
+// metadata) plus BadRequest and RetryInfo details for field errors and retry
+// hints. Any other error is logged and replaced by a generic Internal status
+// so internals are never exposed to clients.
+func ToStatus(ctx context.Context, err error, logger *slog.Logger) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	if errors.Is(err, context.Canceled) {
+		return status.Error(codes.Canceled, "request canceled")
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return status.Error(codes.DeadlineExceeded, "deadline exceeded")
+	}
+
+	var domainErr *domain.Error
+	if !errors.As(err, &domainErr) {
+		logger.ErrorContext(ctx, "unhandled error", "error", err)
+		return status.Error(codes.Internal, "internal error")
+	}
+
+	st := status.New(CodeFor(domainErr.Code), domainErr.Message)
+	details := errorDetails(ctx, domainErr)
+	withDetails, detailsErr := st.WithDetails(details...)
+	if detailsErr != nil {
+		logger.ErrorContext(ctx, "failed to attach status details", "error", detailsErr)
+		return st.Err()
+	}
+	return withDetails.Err()
+}
+
+func errorDetails(ctx context.Context, domainErr *domain.Error) []protoadapt.MessageV1 {
+	info := &errdetails.ErrorInfo{
+		Reason:   string(domainErr.Code),
+		Domain:   ErrorInfoDomain,
+		Metadata: map[string]string{},
+	}
+	if reqID := chimw.GetReqID(ctx); reqID != "" {
+		info.Metadata["request_id"] = reqID
+	}
+	details := []protoadapt.MessageV1{info}
+
+	for name, value := range domainErr.Meta() {
+		switch name {
+		case domain.MetaFieldErrors.Name():
+			fieldErrs, _ := value.([]domain.FieldError)
+			br := &errdetails.BadRequest{}
+			for _, fe := range fieldErrs {
+				br.FieldViolations = append(br.FieldViolations, &errdetails.BadRequest_FieldViolation{
+					Field:       fe.Pointer,
+					Description: fe.Message,
+					Reason:      fe.Rule,
+				})
+			}
+			details = append(details, br)
+		case domain.MetaRetryAfter.Name():
+			d, _ := value.(time.Duration)
+			seconds := time.Duration(math.Ceil(d.Seconds())) * time.Second
+			details = append(details, &errdetails.RetryInfo{RetryDelay: durationpb.New(seconds)})
+		default:
+			if s, ok := value.(string); ok {
+				info.Metadata[name] = s
+			}
+		}
+	}
+	return details
+}
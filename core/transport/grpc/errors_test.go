@@ -0,0 +1,113 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	chimw "github.com/go-chi/chi/v5/middleware"
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/bbsbb/go-edge/core/domain"
+	coretesting "github.com/bbsbb/go-edge/core/testing"
+)
+
+var noopLogger = coretesting.NewNoopLogger()
+
+type ToStatusSuite struct {
+	suite.Suite
+}
+
+func (s *ToStatusSuite) TestBuiltInCodes() {
+	tests := []struct {
+		code domain.Code
+		want codes.Code
+	}{
+		{domain.CodeNotFound, codes.NotFound},
+		{domain.CodeConflict, codes.AlreadyExists},
+		{domain.CodeValidation, codes.InvalidArgument},
+		{domain.CodeForbidden, codes.PermissionDenied},
+		{domain.CodeInvariant, codes.FailedPrecondition},
+		{domain.Code("UNREGISTERED"), codes.Internal},
+	}
+
+	for _, tt := range tests {
+		s.Run(string(tt.code), func() {
+			err := ToStatus(context.Background(), domain.NewError(tt.code, "msg"), noopLogger)
+
+			st, ok := status.FromError(err)
+			s.Require().True(ok)
+			s.Assert().Equal(tt.want, st.Code())
+			s.Assert().Equal("msg", st.Message())
+		})
+	}
+}
+
+func (s *ToStatusSuite) TestRegisterCode() {
+	code := domain.Code("RATE_LIMITED")
+	RegisterCode(code, codes.ResourceExhausted)
+
+	st := status.Convert(ToStatus(context.Background(), domain.NewError(code, "slow down"), noopLogger))
+	s.Assert().Equal(codes.ResourceExhausted, st.Code())
+}
+
+func (s *ToStatusSuite) TestDetails() {
+	ctx := context.WithValue(context.Background(), chimw.RequestIDKey, "req-1")
+	err := domain.NewError(domain.CodeValidation, "invalid")
+	domain.WithMeta(err, domain.MetaFieldErrors, []domain.FieldError{{Pointer: "/name", Rule: "required", Message: "is required"}})
+	domain.WithMeta(err, domain.MetaRetryAfter, 1500*time.Millisecond)
+	domain.WithMeta(err, domain.MetaConflictingID, "abc")
+
+	st := status.Convert(ToStatus(ctx, fmt.Errorf("service: %w", err), noopLogger))
+
+	var (
+		info  *errdetails.ErrorInfo
+		br    *errdetails.BadRequest
+		retry *errdetails.RetryInfo
+	)
+	for _, d := range st.Details() {
+		switch v := d.(type) {
+		case *errdetails.ErrorInfo:
+			info = v
+		case *errdetails.BadRequest:
+			br = v
+		case *errdetails.RetryInfo:
+			retry = v
+		}
+	}
+	s.Require().NotNil(info)
+	s.Assert().Equal("VALIDATION", info.GetReason())
+	s.Assert().Equal("req-1", info.GetMetadata()["request_id"])
+	s.Assert().Equal("abc", info.GetMetadata()["conflicting_id"])
+
+	s.Require().NotNil(br)
+	s.Require().Len(br.GetFieldViolations(), 1)
+	s.Assert().Equal("/name", br.GetFieldViolations()[0].GetField())
+	s.Assert().Equal("required", br.GetFieldViolations()[0].GetReason())
+
+	s.Require().NotNil(retry)
+	s.Assert().Equal(2*time.Second, retry.GetRetryDelay().AsDuration())
+}
+
+func (s *ToStatusSuite) TestPassthroughAndGenericErrors() {
+	s.Assert().NoError(ToStatus(context.Background(), nil, noopLogger))
+
+	existing := status.Error(codes.Unavailable, "try later")
+	s.Assert().Equal(existing, ToStatus(context.Background(), existing, noopLogger))
+
+	st := status.Convert(ToStatus(context.Background(), errors.New("db password is hunter2"), noopLogger))
+	s.Assert().Equal(codes.Internal, st.Code())
+	s.Assert().Equal("internal error", st.Message())
+
+	st = status.Convert(ToStatus(context.Background(), context.DeadlineExceeded, noopLogger))
+	s.Assert().Equal(codes.DeadlineExceeded, st.Code())
+}
+
+func TestToStatusSuite(t *testing.T) {
+	suite.Run(t, new(ToStatusSuite))
+}
@@ -0,0 +1,102 @@
+package grpc
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/bbsbb/go-edge/core/domain"
+	"github.com/bbsbb/go-edge/core/transport/http/middleware"
+)
+
+const slugMetadataKey = "x-organization-slug"
+
+type WithOrganizationConfig struct {
+	// SkipMethods are full method names ("/pkg.Service/Method") served without
+	// an organization, e.g. health checks and reflection.
+	SkipMethods []string
+	Logger      *slog.Logger
+	Loader      middleware.OrganizationLoader
+}
+
+// extractSlug reads the x-organization-slug metadata, falling back to the
+// first label of the :authority pseudo-header like the HTTP middleware does
+// with the Host header.
+func extractSlug(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if slugs := md.Get(slugMetadataKey); len(slugs) > 0 && slugs[0] != "" {
+		return slugs[0]
+	}
+	if authority := md.Get(":authority"); len(authority) > 0 {
+		return strings.Split(authority[0], ".")[0]
+	}
+	return ""
+}
+
+type organizationResolver struct {
+	skip map[string]bool
+	cfg  WithOrganizationConfig
+}
+
+func newOrganizationResolver(cfg WithOrganizationConfig) *organizationResolver {
+	skip := make(map[string]bool, len(cfg.SkipMethods))
+	for _, m := range cfg.SkipMethods {
+		skip[m] = true
+	}
+	return &organizationResolver{skip: skip, cfg: cfg}
+}
+
+func (o *organizationResolver) resolve(ctx context.Context, method string) (context.Context, error) {
+	if o.skip[method] {
+		return ctx, nil
+	}
+
+	slug := extractSlug(ctx)
+	org, err := o.cfg.Loader.LoadOrganizationBySlug(ctx, slug)
+	if err != nil {
+		o.cfg.Logger.ErrorContext(ctx, "could not find organization", "slug", slug, "error", err)
+		return nil, status.Error(codes.NotFound, "organization not found")
+	}
+	return domain.ContextWithOrganization(ctx, org), nil
+}
+
+// UnaryOrganization resolves the calling organization from request metadata
+// and adds it to the context, the gRPC counterpart of middleware.WithOrganization.
+func UnaryOrganization(cfg WithOrganizationConfig) grpc.UnaryServerInterceptor {
+	o := newOrganizationResolver(cfg)
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := o.resolve(ctx, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamOrganization is the streaming variant of UnaryOrganization.
+func StreamOrganization(cfg WithOrganizationConfig) grpc.StreamServerInterceptor {
+	o := newOrganizationResolver(cfg)
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := o.resolve(ss.Context(), info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &ServerStream{ServerStream: ss, Ctx: ctx})
+	}
+}
+
+// ServerStream overrides the context of a wrapped grpc.ServerStream so stream
+// interceptors can pass values to handlers.
+type ServerStream struct {
+	grpc.ServerStream
+	Ctx context.Context
+}
+
+func (s *ServerStream) Context() context.Context {
+	return s.Ctx
+}
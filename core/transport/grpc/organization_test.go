@@ -0,0 +1,110 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/bbsbb/go-edge/core/domain"
+)
+
+type mockOrganizationLoader struct {
+	orgs map[string]*domain.Organization
+}
+
+func (m *mockOrganizationLoader) LoadOrganizationBySlug(_ context.Context, slug string) (*domain.Organization, error) {
+	if org, ok := m.orgs[slug]; ok {
+		return org, nil
+	}
+	return nil, errors.New("not found")
+}
+
+type fakeStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (f *fakeStream) Context() context.Context { return f.ctx }
+
+type OrganizationSuite struct {
+	suite.Suite
+	org *domain.Organization
+	cfg WithOrganizationConfig
+}
+
+func (s *OrganizationSuite) SetupTest() {
+	s.org = &domain.Organization{ID: uuid.Must(uuid.NewV7()), Slug: "acme"}
+	s.cfg = WithOrganizationConfig{
+		SkipMethods: []string{"/grpc.health.v1.Health/Check"},
+		Logger:      noopLogger,
+		Loader:      &mockOrganizationLoader{orgs: map[string]*domain.Organization{"acme": s.org}},
+	}
+}
+
+func (s *OrganizationSuite) call(ctx context.Context, method string) (*domain.Organization, error) {
+	var got *domain.Organization
+	_, err := UnaryOrganization(s.cfg)(ctx, nil, &grpc.UnaryServerInfo{FullMethod: method},
+		func(ctx context.Context, _ any) (any, error) {
+			got, _ = domain.OrganizationFromContext(ctx)
+			return nil, nil
+		})
+	return got, err
+}
+
+func (s *OrganizationSuite) TestUnary_FromMetadata() {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-organization-slug", "acme"))
+
+	got, err := s.call(ctx, "/svc/Method")
+
+	s.Require().NoError(err)
+	s.Assert().Equal(s.org.ID, got.ID)
+}
+
+func (s *OrganizationSuite) TestUnary_FromAuthority() {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(":authority", "acme.sweetshop.internal:9090"))
+
+	got, err := s.call(ctx, "/svc/Method")
+
+	s.Require().NoError(err)
+	s.Assert().Equal(s.org.ID, got.ID)
+}
+
+func (s *OrganizationSuite) TestUnary_UnknownOrganization() {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-organization-slug", "nope"))
+
+	_, err := s.call(ctx, "/svc/Method")
+
+	s.Assert().Equal(codes.NotFound, status.Code(err))
+}
+
+func (s *OrganizationSuite) TestUnary_SkipMethod() {
+	got, err := s.call(context.Background(), "/grpc.health.v1.Health/Check")
+
+	s.Require().NoError(err)
+	s.Assert().Nil(got)
+}
+
+func (s *OrganizationSuite) TestStream_FromMetadata() {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-organization-slug", "acme"))
+
+	var got *domain.Organization
+	err := StreamOrganization(s.cfg)(nil, &fakeStream{ctx: ctx}, &grpc.StreamServerInfo{FullMethod: "/svc/Watch"},
+		func(_ any, ss grpc.ServerStream) error {
+			got, _ = domain.OrganizationFromContext(ss.Context())
+			return nil
+		})
+
+	s.Require().NoError(err)
+	s.Assert().Equal(s.org.ID, got.ID)
+}
+
+func TestOrganizationSuite(t *testing.T) {
+	suite.Run(t, new(OrganizationSuite))
+}
@@ -0,0 +1,242 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+
+	"github.com/bbsbb/go-edge/core/configuration"
+	"github.com/bbsbb/go-edge/core/domain"
+)
+
+// ValidatingBinder decodes JSON request bodies into DTOs, rejecting unknown
+// fields and trailing data, then runs the DTO's render.Binder hook and its
+// `validate` struct tags. Failures are returned as CodeValidation domain errors
+// carrying one FieldError per problem under domain.MetaFieldErrors, which
+// WriteError renders as structured problem details.
+type ValidatingBinder struct {
+	validate *validator.Validate
+}
+
+// NewValidatingBinder returns a binder that validates with v.
+func NewValidatingBinder(v *validator.Validate) *ValidatingBinder {
+	return &ValidatingBinder{validate: v}
+}
+
+var defaultBinder = NewValidatingBinder(configuration.Validate)
+
+// Bind decodes and validates the request body into v using the shared validator.
+func Bind(r *http.Request, v render.Binder) error {
+	return defaultBinder.Bind(r, v)
+}
+
+// BindBytes decodes and validates a JSON document that did not arrive as a
+// request body, such as a WebSocket message, using the shared validator.
+func BindBytes(ctx context.Context, data []byte, v render.Binder) error {
+	r, err := http.NewRequestWithContext(ctx, http.MethodPost, "/", bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	return defaultBinder.Bind(r, v)
+}
+
+// Bind decodes and validates the request body into v.
+func (b *ValidatingBinder) Bind(r *http.Request, v render.Binder) error {
+	if err := decodeStrict(r.Body, v); err != nil {
+		return err
+	}
+	if err := v.Bind(r); err != nil {
+		var domainErr *domain.Error
+		if errors.As(err, &domainErr) {
+			return err
+		}
+		return domain.WrapError(domain.CodeValidation, "invalid request body", err)
+	}
+
+	err := b.validate.Struct(v)
+	var verrs validator.ValidationErrors
+	if !errors.As(err, &verrs) {
+		return err
+	}
+
+	fieldErrs := make([]FieldError, len(verrs))
+	for i, fe := range verrs {
+		fieldErrs[i] = FieldError{
+			Pointer: jsonPointer(reflect.TypeOf(v), fe.StructNamespace()),
+			Rule:    fe.Tag(),
+			Message: validationMessage(fe),
+		}
+	}
+	return validationError("request validation failed", fieldErrs...)
+}
+
+func decodeStrict(body io.Reader, v any) error {
+	dec := json.NewDecoder(body)
+	dec.DisallowUnknownFields()
+
+	err := dec.Decode(v)
+	if err == nil && dec.More() {
+		err = errors.New("json: trailing data after request body")
+	}
+	if err == nil {
+		return nil
+	}
+
+	return validationError("invalid request body", *decodeFieldError(err))
+}
+
+func validationError(message string, fieldErrs ...FieldError) error {
+	joined := make([]error, len(fieldErrs))
+	for i := range fieldErrs {
+		joined[i] = &fieldErrs[i]
+	}
+	err := domain.WrapError(domain.CodeValidation, message, errors.Join(joined...))
+	return domain.WithMeta(err, domain.MetaFieldErrors, fieldErrs)
+}
+
+func decodeFieldError(err error) *FieldError {
+	var (
+		syntaxErr   *json.SyntaxError
+		typeErr     *json.UnmarshalTypeError
+		maxBytesErr *http.MaxBytesError
+	)
+	switch {
+	case errors.Is(err, io.EOF):
+		return &FieldError{Rule: "required", Message: "request body is required"}
+	case errors.As(err, &maxBytesErr):
+		return &FieldError{Rule: "max", Message: fmt.Sprintf("request body exceeds %d bytes", maxBytesErr.Limit)}
+	case errors.As(err, &syntaxErr):
+		return &FieldError{Rule: "syntax", Message: fmt.Sprintf("malformed JSON at offset %d", syntaxErr.Offset)}
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		return &FieldError{Rule: "syntax", Message: "malformed JSON: unexpected end of body"}
+	case errors.As(err, &typeErr):
+		return &FieldError{
+			Pointer: "/" + strings.ReplaceAll(typeErr.Field, ".", "/"),
+			Rule:    "type",
+			Message: "must be of type " + jsonTypeName(typeErr.Type),
+		}
+	}
+
+	// encoding/json has no typed error for unknown fields.
+	if name, ok := strings.CutPrefix(err.Error(), "json: unknown field "); ok {
+		name, _ = strconv.Unquote(name)
+		return &FieldError{Pointer: "/" + name, Rule: "unknown", Message: "unknown field"}
+	}
+	return &FieldError{Rule: "syntax", Message: err.Error()}
+}
+
+func jsonTypeName(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.String:
+		return "string"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+// jsonPointer converts a validator struct namespace ("Req.Items[0].Name") into
+// a JSON pointer ("/items/0/name") by resolving each Go field name to its json tag.
+func jsonPointer(t reflect.Type, namespace string) string {
+	segments := strings.Split(namespace, ".")[1:]
+
+	var b strings.Builder
+	for _, seg := range segments {
+		name, index, hasIndex := strings.Cut(seg, "[")
+		for t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+
+		field, ok := t.FieldByName(name)
+		if !ok {
+			b.WriteString("/" + name)
+			return b.String()
+		}
+		// Untagged embedded structs are flattened by encoding/json.
+		if !field.Anonymous || field.Tag.Get("json") != "" {
+			b.WriteString("/" + jsonFieldName(field))
+		}
+		t = field.Type
+
+		if hasIndex {
+			b.WriteString("/" + strings.TrimSuffix(index, "]"))
+			for t.Kind() == reflect.Pointer {
+				t = t.Elem()
+			}
+			if t.Kind() == reflect.Slice || t.Kind() == reflect.Array || t.Kind() == reflect.Map {
+				t = t.Elem()
+			}
+		}
+	}
+	return b.String()
+}
+
+func jsonFieldName(f reflect.StructField) string {
+	name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+	if name == "" || name == "-" {
+		return f.Name
+	}
+	return name
+}
+
+func validationMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "min":
+		if unit := lengthUnit(fe.Kind()); unit != "" {
+			return "must have at least " + fe.Param() + " " + unit
+		}
+		return "must be at least " + fe.Param()
+	case "max":
+		if unit := lengthUnit(fe.Kind()); unit != "" {
+			return "must have at most " + fe.Param() + " " + unit
+		}
+		return "must be at most " + fe.Param()
+	case "gt":
+		return "must be greater than " + fe.Param()
+	case "gte":
+		return "must be greater than or equal to " + fe.Param()
+	case "lt":
+		return "must be less than " + fe.Param()
+	case "lte":
+		return "must be less than or equal to " + fe.Param()
+	case "oneof":
+		return "must be one of: " + fe.Param()
+	case "uuid", "uuid4", "uuid7":
+		return "must be a valid UUID"
+	case "stringenum":
+		return "is not an allowed value"
+	default:
+		return "failed " + fe.Tag() + " validation"
+	}
+}
+
+func lengthUnit(k reflect.Kind) string {
+	switch k {
+	case reflect.String:
+		return "characters"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return "items"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,144 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bbsbb/go-edge/core/datatype"
+	"github.com/bbsbb/go-edge/core/domain"
+)
+
+type bindingLine struct {
+	SKU      string `json:"sku" validate:"required"`
+	Quantity int32  `json:"quantity" validate:"min=1"`
+}
+
+type bindingRequest struct {
+	NoOpBinder
+	Name  string        `json:"name" validate:"required,max=5"`
+	Price int32         `json:"price_cents" validate:"gt=0"`
+	Lines []bindingLine `json:"lines" validate:"dive"`
+}
+
+type moneyRequest struct {
+	NoOpBinder
+	Price datatype.Money `json:"price" validate:"gt=0"`
+}
+
+type rejectingRequest struct {
+	Name string `json:"name"`
+}
+
+func (rejectingRequest) Bind(*http.Request) error { return errors.New("hook failed") }
+
+type BindingSuite struct {
+	suite.Suite
+}
+
+func (s *BindingSuite) bind(body string, v interface{ Bind(*http.Request) error }) error {
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	return Bind(req, v)
+}
+
+func (s *BindingSuite) fieldErrors(err error) []FieldError {
+	s.Require().Error(err)
+	var domainErr *domain.Error
+	s.Require().ErrorAs(err, &domainErr)
+	s.Require().Equal(domain.CodeValidation, domainErr.Code)
+
+	fieldErrs, ok := domain.MetaValue(err, domain.MetaFieldErrors)
+	s.Require().True(ok, "expected field errors metadata")
+	return fieldErrs
+}
+
+func (s *BindingSuite) TestValidBody() {
+	var req bindingRequest
+	err := s.bind(`{"name":"cake","price_cents":100,"lines":[{"sku":"a","quantity":1}]}`, &req)
+	s.Require().NoError(err)
+	s.Assert().Equal("cake", req.Name)
+	s.Assert().Len(req.Lines, 1)
+}
+
+func (s *BindingSuite) TestBindBytes() {
+	var req bindingRequest
+	s.Require().NoError(BindBytes(context.Background(), []byte(`{"name":"cake","price_cents":100}`), &req))
+	s.Assert().Equal("cake", req.Name)
+
+	errs := s.fieldErrors(BindBytes(context.Background(), []byte(`{"name":"cake","price_cents":0}`), &req))
+	s.Assert().Equal([]FieldError{{Pointer: "/price_cents", Rule: "gt", Message: "must be greater than 0"}}, errs)
+}
+
+func (s *BindingSuite) TestValidationFailures() {
+	var req bindingRequest
+	errs := s.fieldErrors(s.bind(`{"name":"toolong","price_cents":0,"lines":[{"sku":"","quantity":1}]}`, &req))
+
+	s.Assert().ElementsMatch([]FieldError{
+		{Pointer: "/name", Rule: "max", Message: "must have at most 5 characters"},
+		{Pointer: "/price_cents", Rule: "gt", Message: "must be greater than 0"},
+		{Pointer: "/lines/0/sku", Rule: "required", Message: "is required"},
+	}, errs)
+}
+
+func (s *BindingSuite) TestMoneyValidatesAsItsAmount() {
+	var req moneyRequest
+	s.Require().NoError(s.bind(`{"price":{"amount":350,"currency":"EUR"}}`, &req))
+	s.Assert().Equal(datatype.MustMoney(350, "EUR"), req.Price)
+
+	errs := s.fieldErrors(s.bind(`{"price":{"amount":0,"currency":"EUR"}}`, &req))
+	s.Assert().Equal([]FieldError{{Pointer: "/price", Rule: "gt", Message: "must be greater than 0"}}, errs)
+
+	errs = s.fieldErrors(s.bind(`{"price":{"amount":350,"currency":"XYZ"}}`, &req))
+	s.Assert().Equal("syntax", errs[0].Rule)
+}
+
+func (s *BindingSuite) TestDecodeFailures() {
+	tests := []struct {
+		name string
+		body string
+		want FieldError
+	}{
+		{"empty body", ``, FieldError{Rule: "required", Message: "request body is required"}},
+		{"unknown field", `{"name":"cake","colour":"red"}`, FieldError{Pointer: "/colour", Rule: "unknown", Message: "unknown field"}},
+		{"wrong type", `{"price_cents":"ten"}`, FieldError{Pointer: "/price_cents", Rule: "type", Message: "must be of type integer"}},
+		{"truncated", `{"name":`, FieldError{Rule: "syntax", Message: "malformed JSON: unexpected end of body"}},
+		{"trailing data", `{"name":"cake"} {}`, FieldError{Rule: "syntax", Message: "json: trailing data after request body"}},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			var req bindingRequest
+			errs := s.fieldErrors(s.bind(tt.body, &req))
+			s.Assert().Equal([]FieldError{tt.want}, errs)
+		})
+	}
+}
+
+func (s *BindingSuite) TestMaxBytesExceeded() {
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"name":"cake"}`))
+	req.Body = http.MaxBytesReader(rr, req.Body, 4)
+
+	var v bindingRequest
+	errs := s.fieldErrors(Bind(req, &v))
+	s.Assert().Equal([]FieldError{{Rule: "max", Message: "request body exceeds 4 bytes"}}, errs)
+}
+
+func (s *BindingSuite) TestBinderHookError() {
+	var req rejectingRequest
+	err := s.bind(`{"name":"cake"}`, &req)
+
+	var domainErr *domain.Error
+	s.Require().ErrorAs(err, &domainErr)
+	s.Assert().Equal(domain.CodeValidation, domainErr.Code)
+	s.Assert().ErrorContains(err, "hook failed")
+}
+
+func TestBindingSuite(t *testing.T) {
+	suite.Run(t, new(BindingSuite))
+}
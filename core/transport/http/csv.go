@@ -0,0 +1,207 @@
+package http
+
+import (
+	"encoding"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/render"
+)
+
+// csvFlushEvery is how many rows EncodeList writes between flushes.
+const csvFlushEvery = 100
+
+// CSVEncoder renders structs as a header row of json field names followed by
+// one row per value. Scalars are written as text, times as RFC 3339, and
+// nested structs, slices and maps as JSON in a single cell.
+type CSVEncoder struct{}
+
+func (CSVEncoder) MediaType() string { return "text/csv" }
+
+func (e CSVEncoder) Encode(w io.Writer, v any) error {
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	if rv.Kind() == reflect.Slice {
+		items := make([]render.Renderer, 0, rv.Len())
+		for i := range rv.Len() {
+			item, ok := rv.Index(i).Interface().(render.Renderer)
+			if !ok {
+				return fmt.Errorf("csv: list element %T is not a render.Renderer", rv.Index(i).Interface())
+			}
+			items = append(items, item)
+		}
+		return e.EncodeList(w, items, func() {})
+	}
+
+	cw := csv.NewWriter(w)
+	columns, err := csvColumns(rv.Type())
+	if err != nil {
+		return err
+	}
+	if err := cw.Write(csvHeader(columns)); err != nil {
+		return err
+	}
+	if err := cw.Write(csvRow(columns, rv)); err != nil {
+		return err
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// EncodeList writes the header from the first item's type, then one row per
+// item, flushing every csvFlushEvery rows. An empty list produces an empty body.
+func (e CSVEncoder) EncodeList(w io.Writer, items []render.Renderer, flush func()) error {
+	lw := e.NewListWriter(w, flush)
+	for _, item := range items {
+		if err := lw.Write(item); err != nil {
+			return err
+		}
+	}
+	return lw.Close()
+}
+
+// NewListWriter writes rows as EncodeList does, one item at a time.
+func (CSVEncoder) NewListWriter(w io.Writer, flush func()) ListWriter {
+	return &csvListWriter{cw: csv.NewWriter(w), flush: flush}
+}
+
+type csvListWriter struct {
+	cw      *csv.Writer
+	flush   func()
+	first   reflect.Type
+	columns []csvColumn
+	rows    int
+}
+
+func (l *csvListWriter) Write(item render.Renderer) error {
+	rv := reflect.Indirect(reflect.ValueOf(item))
+	if l.first == nil {
+		columns, err := csvColumns(rv.Type())
+		if err != nil {
+			return err
+		}
+		if err := l.cw.Write(csvHeader(columns)); err != nil {
+			return err
+		}
+		l.first, l.columns = rv.Type(), columns
+	} else if rv.Type() != l.first {
+		return fmt.Errorf("csv: mixed list element types %s and %s", l.first, rv.Type())
+	}
+
+	if err := l.cw.Write(csvRow(l.columns, rv)); err != nil {
+		return err
+	}
+	l.rows++
+	if l.rows%csvFlushEvery == 0 {
+		l.cw.Flush()
+		if err := l.cw.Error(); err != nil {
+			return err
+		}
+		l.flush()
+	}
+	return nil
+}
+
+func (l *csvListWriter) Close() error {
+	l.cw.Flush()
+	return l.cw.Error()
+}
+
+type csvColumn struct {
+	name  string
+	index []int
+}
+
+// csvColumns mirrors encoding/json field visibility: "-" and unexported fields
+// are skipped and untagged embedded structs are flattened.
+func csvColumns(t reflect.Type) ([]csvColumn, error) {
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("csv: cannot encode %s, want a struct", t)
+	}
+
+	var columns []csvColumn
+	for _, f := range reflect.VisibleFields(t) {
+		tag := f.Tag.Get("json")
+		if tag == "-" || !f.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		if f.Anonymous && name == "" && indirectType(f.Type).Kind() == reflect.Struct {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		columns = append(columns, csvColumn{name: name, index: f.Index})
+	}
+	return columns, nil
+}
+
+func csvHeader(columns []csvColumn) []string {
+	header := make([]string, len(columns))
+	for i, c := range columns {
+		header[i] = c.name
+	}
+	return header
+}
+
+func csvRow(columns []csvColumn, rv reflect.Value) []string {
+	row := make([]string, len(columns))
+	for i, c := range columns {
+		// A nil embedded pointer leaves its promoted fields empty.
+		if field, err := rv.FieldByIndexErr(c.index); err == nil {
+			row[i] = csvCell(field)
+		}
+	}
+	return row
+}
+
+func csvCell(v reflect.Value) string {
+	if v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+
+	switch x := v.Interface().(type) {
+	case time.Time:
+		return x.Format(time.RFC3339Nano)
+	case encoding.TextMarshaler:
+		text, err := x.MarshalText()
+		if err == nil {
+			return string(text)
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return v.String()
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, v.Type().Bits())
+	default:
+		data, err := json.Marshal(v.Interface())
+		if err != nil {
+			return ""
+		}
+		return string(data)
+	}
+}
+
+func indirectType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t
+}
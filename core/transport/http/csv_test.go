@@ -0,0 +1,78 @@
+package http
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/go-chi/render"
+	"github.com/stretchr/testify/suite"
+)
+
+type CSVSuite struct {
+	suite.Suite
+}
+
+type csvAudit struct {
+	By string `json:"by"`
+}
+
+type csvRecord struct {
+	NoOpRenderer
+	*csvAudit
+	Name    string            `json:"name"`
+	Note    *string           `json:"note"`
+	Price   float64           `json:"price"`
+	Labels  map[string]string `json:"labels,omitempty"`
+	Ignored string            `json:"-"`
+	hidden  string
+}
+
+func (s *CSVSuite) TestEncode_FlattensAndFormatsCells() {
+	note := "fresh, cold"
+	var buf bytes.Buffer
+
+	err := CSVEncoder{}.Encode(&buf, &csvRecord{
+		csvAudit: &csvAudit{By: "ana"},
+		Name:     "Vanilla",
+		Note:     &note,
+		Price:    3.5,
+		Labels:   map[string]string{"k": "v"},
+		Ignored:  "x",
+		hidden:   "y",
+	})
+
+	s.Require().NoError(err)
+	s.Assert().Equal("by,name,note,price,labels\nana,Vanilla,\"fresh, cold\",3.5,\"{\"\"k\"\":\"\"v\"\"}\"\n", buf.String())
+}
+
+func (s *CSVSuite) TestEncode_NilPointers() {
+	var buf bytes.Buffer
+
+	err := CSVEncoder{}.Encode(&buf, &csvRecord{Name: "Plain"})
+
+	s.Require().NoError(err)
+	s.Assert().Equal("by,name,note,price,labels\n,Plain,,0,null\n", buf.String())
+}
+
+func (s *CSVSuite) TestEncodeList_Empty() {
+	var buf bytes.Buffer
+
+	s.Require().NoError(CSVEncoder{}.EncodeList(&buf, nil, func() {}))
+	s.Assert().Empty(buf.String())
+}
+
+func (s *CSVSuite) TestEncodeList_RejectsMixedTypes() {
+	var buf bytes.Buffer
+	items := []render.Renderer{&csvRecord{Name: "a"}, &renderItem{ID: "b"}}
+
+	s.Assert().ErrorContains(CSVEncoder{}.EncodeList(&buf, items, func() {}), "mixed list element types")
+}
+
+func (s *CSVSuite) TestEncode_RejectsNonStruct() {
+	var buf bytes.Buffer
+	s.Assert().ErrorContains(CSVEncoder{}.Encode(&buf, 42), "want a struct")
+}
+
+func TestCSVSuite(t *testing.T) {
+	suite.Run(t, new(CSVSuite))
+}
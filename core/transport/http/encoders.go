@@ -0,0 +1,29 @@
+package http
+
+import (
+	"io"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// CBOREncoder encodes responses as RFC 8949 CBOR. Struct fields use their
+// json tag names, so DTOs need no extra tags.
+type CBOREncoder struct{}
+
+func (CBOREncoder) MediaType() string { return "application/cbor" }
+
+func (CBOREncoder) Encode(w io.Writer, v any) error {
+	return cbor.NewEncoder(w).Encode(v)
+}
+
+// MsgPackEncoder encodes responses as MessagePack using json tag names.
+type MsgPackEncoder struct{}
+
+func (MsgPackEncoder) MediaType() string { return "application/msgpack" }
+
+func (MsgPackEncoder) Encode(w io.Writer, v any) error {
+	enc := msgpack.NewEncoder(w)
+	enc.SetCustomStructTag("json")
+	return enc.Encode(v)
+}
@@ -3,10 +3,15 @@
 package http
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"log/slog"
+	"math"
 	"net/http"
+	"slices"
+	"strconv"
+	"time"
 
 	chimw "github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/render"
@@ -14,66 +19,171 @@ import (
 	"github.com/bbsbb/go-edge/core/domain"
 )
 
-// ErrorShape is the RFC 9457-inspired problem details response for all error responses.
+// ErrorShape is the RFC 9457 problem details response for all error responses.
+// Code, RequestID and Errors are extension members present on every problem;
+// Extensions carries any further members (from domain.Error metadata) and is
+// flattened into the top-level object when encoded.
 // It implements render.Renderer so applications can use render.Render(w, r, shape)
 // directly for custom error cases where application/problem+json content type
 // negotiation is handled separately.
 type ErrorShape struct {
-	Status    int      `json:"status"`
-	Code      string   `json:"code,omitempty"`
-	Detail    string   `json:"detail,omitempty"`
-	Instance  string   `json:"instance,omitempty"`
-	RequestID string   `json:"request_id,omitempty"`
-	Errors    []string `json:"errors,omitempty"`
+	Type       string         `json:"type"`
+	Title      string         `json:"title"`
+	Status     int            `json:"status"`
+	Detail     string         `json:"detail,omitempty"`
+	Instance   string         `json:"instance,omitempty"`
+	Code       string         `json:"code,omitempty"`
+	RequestID  string         `json:"request_id,omitempty"`
+	Errors     []FieldError   `json:"errors,omitempty"`
+	Extensions map[string]any `json:"-"`
 }
 
-func (p *ErrorShape) Render(w http.ResponseWriter, r *http.Request) error {
-	render.Status(r, p.Status)
+// FieldError is a single invalid request input, rendered in ErrorShape.Errors.
+type FieldError = domain.FieldError
+
+// errorShapeMembers are the members encoded from ErrorShape's own fields;
+// extensions with these names are dropped rather than overwriting them.
+var errorShapeMembers = []string{"type", "title", "status", "detail", "instance", "code", "request_id", "errors"}
+
+type errorShapeFields ErrorShape
+
+func (p *ErrorShape) MarshalJSON() ([]byte, error) {
+	data, err := json.Marshal((*errorShapeFields)(p))
+	if err != nil || len(p.Extensions) == 0 {
+		return data, err
+	}
+
+	members := make(map[string]any, len(p.Extensions))
+	for k, v := range p.Extensions {
+		if !slices.Contains(errorShapeMembers, k) {
+			members[k] = v
+		}
+	}
+	if err := json.Unmarshal(data, &members); err != nil {
+		return nil, err
+	}
+	return json.Marshal(members)
+}
+
+func (p *ErrorShape) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, (*errorShapeFields)(p)); err != nil {
+		return err
+	}
+
+	var members map[string]any
+	if err := json.Unmarshal(data, &members); err != nil {
+		return err
+	}
+	for k, v := range members {
+		if slices.Contains(errorShapeMembers, k) {
+			continue
+		}
+		if p.Extensions == nil {
+			p.Extensions = make(map[string]any)
+		}
+		p.Extensions[k] = v
+	}
 	return nil
 }
 
-var statusFromCode = map[domain.Code]int{
-	domain.CodeNotFound:   http.StatusNotFound,
-	domain.CodeConflict:   http.StatusConflict,
-	domain.CodeValidation: http.StatusBadRequest,
-	domain.CodeForbidden:  http.StatusForbidden,
-	domain.CodeInvariant:  http.StatusUnprocessableEntity,
+func (p *ErrorShape) Render(w http.ResponseWriter, r *http.Request) error {
+	render.Status(r, p.Status)
+	return nil
 }
 
 // WriteError translates a domain error (or any error) into an RFC 9457 problem details response.
-// The logger parameter is used for logging unhandled (non-domain) errors and encoding failures.
+// The status, type and title come from the ProblemType registered for the error's code;
+// domain.Error metadata becomes extension members. The logger parameter is used for logging
+// unhandled (non-domain) errors and encoding failures.
 func WriteError(w http.ResponseWriter, r *http.Request, err error, logger *slog.Logger) {
+	if !errors.As(err, new(*domain.Error)) {
+		logger.ErrorContext(r.Context(), "unhandled error", "error", err)
+	}
+	renderProblemJSON(w, r, problem(r.Context(), r.URL.Path, err, w.Header()), logger)
+}
+
+// Problem builds the problem details WriteError renders for err, for
+// transports other than a plain HTTP response such as WebSocket messages.
+// Non-domain errors become a generic 500 problem; callers log them.
+func Problem(ctx context.Context, instance string, err error) *ErrorShape {
+	return problem(ctx, instance, err, nil)
+}
+
+// problem builds the problem details for err. A non-nil header receives the
+// Retry-After hint.
+func problem(ctx context.Context, instance string, err error, header http.Header) *ErrorShape {
 	var domainErr *domain.Error
 	if errors.As(err, &domainErr) {
-		status, ok := statusFromCode[domainErr.Code]
-		if !ok {
-			status = http.StatusInternalServerError
-		}
-
+		pt, _ := ProblemTypeFor(domainErr.Code)
 		resp := &ErrorShape{
-			Status:    status,
+			Type:      pt.Type,
+			Title:     pt.Title,
+			Status:    pt.Status,
 			Code:      string(domainErr.Code),
 			Detail:    domainErr.Message,
-			Instance:  r.URL.Path,
-			RequestID: chimw.GetReqID(r.Context()),
+			Instance:  instance,
+			RequestID: chimw.GetReqID(ctx),
 		}
+		applyMeta(header, resp, domainErr)
+		return resp
+	}
+
+	pt := blankProblem(http.StatusInternalServerError)
+	return &ErrorShape{
+		Type:      pt.Type,
+		Title:     pt.Title,
+		Status:    pt.Status,
+		Instance:  instance,
+		RequestID: chimw.GetReqID(ctx),
+	}
+}
 
-		if unwrapper, ok := domainErr.Err.(interface{ Unwrap() []error }); ok {
-			for _, e := range unwrapper.Unwrap() {
-				resp.Errors = append(resp.Errors, e.Error())
+// applyMeta renders domain.Error metadata: field errors fill Errors, a retry
+// hint also sets the Retry-After header, and anything else is passed through
+// as an extension member. Errors joined into the wrapped error are listed as
+// message-only field errors when no structured ones were attached.
+func applyMeta(header http.Header, resp *ErrorShape, domainErr *domain.Error) {
+	for name, value := range domainErr.Meta() {
+		switch name {
+		case domain.MetaFieldErrors.Name():
+			resp.Errors, _ = value.([]FieldError)
+		case domain.MetaRetryAfter.Name():
+			d, _ := value.(time.Duration)
+			seconds := int(math.Ceil(d.Seconds()))
+			if header != nil {
+				header.Set("Retry-After", strconv.Itoa(seconds))
 			}
+			setExtension(resp, name, seconds)
+		default:
+			setExtension(resp, name, value)
 		}
+	}
 
-		renderProblemJSON(w, r, resp, logger)
+	if len(resp.Errors) > 0 {
 		return
 	}
+	if unwrapper, ok := domainErr.Err.(interface{ Unwrap() []error }); ok {
+		for _, e := range unwrapper.Unwrap() {
+			resp.Errors = append(resp.Errors, toFieldError(e))
+		}
+	}
+}
 
-	logger.ErrorContext(r.Context(), "unhandled error", "error", err)
-	renderProblemJSON(w, r, &ErrorShape{
-		Status:    http.StatusInternalServerError,
-		Instance:  r.URL.Path,
-		RequestID: chimw.GetReqID(r.Context()),
-	}, logger)
+func setExtension(resp *ErrorShape, name string, value any) {
+	if resp.Extensions == nil {
+		resp.Extensions = make(map[string]any)
+	}
+	resp.Extensions[name] = value
+}
+
+// toFieldError keeps structured field errors intact; any other joined error
+// becomes a message-only entry.
+func toFieldError(err error) FieldError {
+	var fieldErr *FieldError
+	if errors.As(err, &fieldErr) {
+		return *fieldErr
+	}
+	return FieldError{Message: err.Error()}
 }
 
 func renderProblemJSON(w http.ResponseWriter, r *http.Request, resp *ErrorShape, logger *slog.Logger) {
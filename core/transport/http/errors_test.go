@@ -1,12 +1,14 @@
 package http
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	chimw "github.com/go-chi/chi/v5/middleware"
 	"github.com/stretchr/testify/suite"
@@ -47,7 +49,7 @@ func (s *WriteErrorSuite) TestWriteError() {
 		wantStatus int
 		wantCode   string
 		wantDetail string
-		wantErrors []string
+		wantErrors []FieldError
 		wantReqID  bool
 	}{
 		{
@@ -81,9 +83,23 @@ func (s *WriteErrorSuite) TestWriteError() {
 			wantStatus: http.StatusBadRequest,
 			wantCode:   "VALIDATION",
 			wantDetail: "validation failed",
-			wantErrors: []string{"field A is required", "field B must be positive"},
+			wantErrors: []FieldError{{Message: "field A is required"}, {Message: "field B must be positive"}},
 			wantReqID:  true,
 		},
+		{
+			name: "domain error with field errors",
+			err: domain.WrapError(domain.CodeValidation, "request validation failed", errors.Join(
+				&FieldError{Pointer: "/name", Rule: "required", Message: "is required"},
+			)),
+			withReqID:  false,
+			method:     http.MethodPost,
+			path:       "/products",
+			wantStatus: http.StatusBadRequest,
+			wantCode:   "VALIDATION",
+			wantDetail: "request validation failed",
+			wantErrors: []FieldError{{Pointer: "/name", Rule: "required", Message: "is required"}},
+			wantReqID:  false,
+		},
 		{
 			name:       "non-domain error",
 			err:        fmt.Errorf("something went wrong"),
@@ -162,10 +178,100 @@ func (s *WriteErrorSuite) TestAllKnownCodeMappings() {
 			var resp ErrorShape
 			s.Require().NoError(json.NewDecoder(rr.Body).Decode(&resp))
 			s.Assert().Equal(tt.status, resp.Status)
+			s.Assert().Equal(http.StatusText(tt.status), resp.Title)
+			s.Assert().NotEqual(ProblemTypeBlank, resp.Type)
 		})
 	}
 }
 
+func (s *WriteErrorSuite) TestWriteError_TypeAndTitle() {
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/things/1", nil)
+
+	WriteError(rr, req, domain.NewError(domain.CodeInvariant, "order is closed"), noopLogger)
+
+	var body map[string]any
+	s.Require().NoError(json.NewDecoder(rr.Body).Decode(&body))
+	s.Assert().Equal("urn:problem-type:invariant-violated", body["type"])
+	s.Assert().Equal("Unprocessable Entity", body["title"])
+	s.Assert().Equal("order is closed", body["detail"])
+}
+
+func (s *WriteErrorSuite) TestWriteError_NonDomainErrorIsBlank() {
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	WriteError(rr, req, fmt.Errorf("boom"), noopLogger)
+
+	var resp ErrorShape
+	s.Require().NoError(json.NewDecoder(rr.Body).Decode(&resp))
+	s.Assert().Equal(ProblemTypeBlank, resp.Type)
+	s.Assert().Equal("Internal Server Error", resp.Title)
+}
+
+func (s *WriteErrorSuite) TestWriteError_MetaBecomesExtensions() {
+	err := domain.NewError(domain.CodeConflict, "name taken")
+	domain.WithMeta(err, domain.MetaConflictingID, "019505e0-0000-7000-8000-000000000000")
+	domain.WithMeta(err, domain.MetaRetryAfter, 1500*time.Millisecond)
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/products", nil)
+	WriteError(rr, req, err, noopLogger)
+
+	s.Assert().Equal("2", rr.Header().Get("Retry-After"))
+
+	var resp ErrorShape
+	s.Require().NoError(json.NewDecoder(rr.Body).Decode(&resp))
+	s.Assert().Equal("019505e0-0000-7000-8000-000000000000", resp.Extensions["conflicting_id"])
+	s.Assert().InDelta(2, resp.Extensions["retry_after"], 0)
+}
+
+func (s *WriteErrorSuite) TestProblem() {
+	err := domain.WithMeta(domain.NewError(domain.CodeConflict, "name taken"), domain.MetaRetryAfter, time.Second)
+
+	resp := Problem(context.Background(), "/orders/live", err)
+
+	s.Assert().Equal(http.StatusConflict, resp.Status)
+	s.Assert().Equal("CONFLICT", resp.Code)
+	s.Assert().Equal("/orders/live", resp.Instance)
+	s.Assert().Equal(1, resp.Extensions["retry_after"])
+
+	blank := Problem(context.Background(), "/orders/live", errors.New("boom"))
+	s.Assert().Equal(http.StatusInternalServerError, blank.Status)
+	s.Assert().Empty(blank.Detail)
+}
+
+func (s *WriteErrorSuite) TestWriteError_FieldErrorsMeta() {
+	fields := []FieldError{{Pointer: "/name", Rule: "required", Message: "is required"}}
+	err := domain.WithMeta(domain.NewError(domain.CodeValidation, "invalid"), domain.MetaFieldErrors, fields)
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/products", nil)
+	WriteError(rr, req, err, noopLogger)
+
+	var resp ErrorShape
+	s.Require().NoError(json.NewDecoder(rr.Body).Decode(&resp))
+	s.Assert().Equal(fields, resp.Errors)
+	s.Assert().Empty(resp.Extensions)
+}
+
+func (s *WriteErrorSuite) TestErrorShape_ExtensionsCannotOverrideMembers() {
+	shape := &ErrorShape{
+		Type:       ProblemTypeBlank,
+		Title:      "Not Found",
+		Status:     http.StatusNotFound,
+		Extensions: map[string]any{"status": 200, "hint": "check the id"},
+	}
+
+	data, err := json.Marshal(shape)
+	s.Require().NoError(err)
+
+	var body map[string]any
+	s.Require().NoError(json.Unmarshal(data, &body))
+	s.Assert().InDelta(http.StatusNotFound, body["status"], 0)
+	s.Assert().Equal("check the id", body["hint"])
+}
+
 func TestWriteErrorSuite(t *testing.T) {
 	suite.Run(t, new(WriteErrorSuite))
 }
@@ -58,6 +58,27 @@ func CheckPostgres(pool *pgxpool.Pool, timeout time.Duration) (HealthStatus, map
 	}
 }
 
+// ComponentCheck is an additional readiness check, reported under Name.
+type ComponentCheck struct {
+	Name          string
+	ComponentType ComponentType
+	Check         func(ctx context.Context) error
+}
+
+func runComponentCheck(c ComponentCheck, timeout time.Duration) HealthCheck {
+	if timeout == 0 {
+		timeout = 1 * time.Second
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	check := HealthCheck{ComponentType: c.ComponentType, Status: HealthStatusPass}
+	if err := c.Check(ctx); err != nil {
+		check.Status = HealthStatusFail
+	}
+	return check
+}
+
 func LivenessHandler() http.HandlerFunc {
 	resp := HealthResponse{Status: HealthStatusPass}
 	body, _ := json.Marshal(resp)
@@ -68,9 +89,17 @@ func LivenessHandler() http.HandlerFunc {
 	}
 }
 
-func ReadinessHandler(pool *pgxpool.Pool, timeout time.Duration, logger *slog.Logger) http.HandlerFunc {
+// ReadinessHandler checks Postgres and every extra component; any failure fails readiness.
+func ReadinessHandler(pool *pgxpool.Pool, timeout time.Duration, logger *slog.Logger, extra ...ComponentCheck) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		status, checks := CheckPostgres(pool, timeout)
+		for _, c := range extra {
+			check := runComponentCheck(c, timeout)
+			checks[c.Name] = append(checks[c.Name], check)
+			if check.Status == HealthStatusFail {
+				status = HealthStatusFail
+			}
+		}
 		resp := HealthResponse{Status: status, Checks: checks}
 
 		w.Header().Set("Content-Type", "application/json")
@@ -3,7 +3,9 @@
 package http
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -61,6 +63,26 @@ func (s *HealthProbeSuite) TestReadinessCustomTimeout() {
 	s.Assert().Equal(http.StatusServiceUnavailable, rr.Code)
 }
 
+func (s *HealthProbeSuite) TestReadinessReportsComponentChecks() {
+	handler := ReadinessHandler(nil, 0, coretesting.NewNoopLogger(),
+		ComponentCheck{Name: "listener", ComponentType: ComponentTypeDatastore, Check: func(context.Context) error {
+			return errors.New("disconnected")
+		}},
+		ComponentCheck{Name: "cache", ComponentType: ComponentTypeSystem, Check: func(context.Context) error {
+			return nil
+		}},
+	)
+	rr := httptest.NewRecorder()
+
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+	s.Assert().Equal(http.StatusServiceUnavailable, rr.Code)
+	var resp HealthResponse
+	s.Require().NoError(json.NewDecoder(rr.Body).Decode(&resp))
+	s.Assert().Equal([]HealthCheck{{ComponentType: ComponentTypeDatastore, Status: HealthStatusFail}}, resp.Checks["listener"])
+	s.Assert().Equal([]HealthCheck{{ComponentType: ComponentTypeSystem, Status: HealthStatusPass}}, resp.Checks["cache"])
+}
+
 func TestHealthProbeSuite(t *testing.T) {
 	suite.Run(t, new(HealthProbeSuite))
 }
@@ -0,0 +1,68 @@
+package http
+
+import (
+	"encoding/json"
+	"io"
+	"reflect"
+
+	"github.com/go-chi/render"
+)
+
+// ndjsonFlushEvery is how many lines EncodeList writes between flushes.
+const ndjsonFlushEvery = 100
+
+// NDJSONEncoder renders newline-delimited JSON: one JSON value per line for
+// each element of a list, or a single line for anything else.
+type NDJSONEncoder struct{}
+
+func (NDJSONEncoder) MediaType() string { return "application/x-ndjson" }
+
+func (NDJSONEncoder) Encode(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	if rv.Kind() != reflect.Slice {
+		return enc.Encode(v)
+	}
+	for i := range rv.Len() {
+		if err := enc.Encode(rv.Index(i).Interface()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// EncodeList writes one line per item, flushing every ndjsonFlushEvery lines.
+// An empty list produces an empty body.
+func (e NDJSONEncoder) EncodeList(w io.Writer, items []render.Renderer, flush func()) error {
+	lw := e.NewListWriter(w, flush)
+	for _, item := range items {
+		if err := lw.Write(item); err != nil {
+			return err
+		}
+	}
+	return lw.Close()
+}
+
+// NewListWriter writes lines as EncodeList does, one item at a time.
+func (NDJSONEncoder) NewListWriter(w io.Writer, flush func()) ListWriter {
+	return &ndjsonListWriter{enc: json.NewEncoder(w), flush: flush}
+}
+
+type ndjsonListWriter struct {
+	enc   *json.Encoder
+	flush func()
+	lines int
+}
+
+func (l *ndjsonListWriter) Write(item render.Renderer) error {
+	if err := l.enc.Encode(item); err != nil {
+		return err
+	}
+	l.lines++
+	if l.lines%ndjsonFlushEvery == 0 {
+		l.flush()
+	}
+	return nil
+}
+
+func (l *ndjsonListWriter) Close() error { return nil }
@@ -0,0 +1,51 @@
+package http
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/go-chi/render"
+	"github.com/stretchr/testify/suite"
+)
+
+type NDJSONSuite struct {
+	suite.Suite
+}
+
+type ndjsonRecord struct {
+	NoOpRenderer
+	Name string `json:"name"`
+}
+
+func (s *NDJSONSuite) TestEncode_OneLinePerElement() {
+	var buf bytes.Buffer
+	s.Require().NoError(NDJSONEncoder{}.Encode(&buf, []ndjsonRecord{{Name: "a"}, {Name: "b"}}))
+	s.Assert().Equal("{\"name\":\"a\"}\n{\"name\":\"b\"}\n", buf.String())
+
+	buf.Reset()
+	s.Require().NoError(NDJSONEncoder{}.Encode(&buf, &ndjsonRecord{Name: "a"}))
+	s.Assert().Equal("{\"name\":\"a\"}\n", buf.String())
+}
+
+func (s *NDJSONSuite) TestEncodeList_Flushes() {
+	items := make([]render.Renderer, 250)
+	for i := range items {
+		items[i] = &ndjsonRecord{Name: "x"}
+	}
+
+	var buf bytes.Buffer
+	flushes := 0
+	s.Require().NoError(NDJSONEncoder{}.EncodeList(&buf, items, func() { flushes++ }))
+	s.Assert().Equal(250, bytes.Count(buf.Bytes(), []byte("\n")))
+	s.Assert().Equal(2, flushes)
+}
+
+func (s *NDJSONSuite) TestEncodeList_Empty() {
+	var buf bytes.Buffer
+	s.Require().NoError(NDJSONEncoder{}.EncodeList(&buf, nil, func() {}))
+	s.Assert().Empty(buf.String())
+}
+
+func TestNDJSONSuite(t *testing.T) {
+	suite.Run(t, new(NDJSONSuite))
+}
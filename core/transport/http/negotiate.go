@@ -0,0 +1,235 @@
+package http
+
+import (
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/go-chi/render"
+
+	"github.com/bbsbb/go-edge/core/domain"
+)
+
+// CodeNotAcceptable is returned when no registered Encoder satisfies the
+// request's Accept header. It renders as 406 problem details.
+const CodeNotAcceptable domain.Code = "NOT_ACCEPTABLE"
+
+const mediaTypeJSON = "application/json"
+
+// jsonFlushEvery is how many elements a JSON list writer writes between
+// flushes.
+const jsonFlushEvery = 100
+
+// Encoder writes response values in a single media type. RenderOrLog and
+// RenderListOrLog select one by the request's Accept header.
+type Encoder interface {
+	MediaType() string
+	Encode(w io.Writer, v any) error
+}
+
+// ListEncoder is an Encoder that writes lists incrementally rather than
+// encoding the whole slice at once. flush is called periodically so rows
+// reach the client while the rest are still being encoded.
+type ListEncoder interface {
+	Encoder
+	EncodeList(w io.Writer, items []render.Renderer, flush func()) error
+}
+
+// ListWriter writes a list one item at a time; Close ends the list.
+type ListWriter interface {
+	Write(item render.Renderer) error
+	Close() error
+}
+
+// StreamEncoder is an Encoder that can write a list while its items are still
+// being produced. StreamListOrLog uses it to send each item as it arrives;
+// other encoders are handed the list once it is complete.
+type StreamEncoder interface {
+	Encoder
+	NewListWriter(w io.Writer, flush func()) ListWriter
+}
+
+var (
+	_ StreamEncoder = jsonEncoder{}
+	_ StreamEncoder = CSVEncoder{}
+	_ StreamEncoder = NDJSONEncoder{}
+)
+
+var (
+	encodersMu sync.RWMutex
+	// JSON is always first: it wins ties and serves requests without an Accept header.
+	encoders = []Encoder{jsonEncoder{}, CBOREncoder{}, MsgPackEncoder{}, CSVEncoder{}, NDJSONEncoder{}}
+)
+
+func init() {
+	RegisterProblemType(CodeNotAcceptable, ProblemType{Status: http.StatusNotAcceptable})
+}
+
+// RegisterEncoder adds e to the negotiable encoders, replacing any encoder
+// already registered for the same media type. Register at startup before
+// serving requests.
+func RegisterEncoder(e Encoder) {
+	encodersMu.Lock()
+	defer encodersMu.Unlock()
+
+	for i, existing := range encoders {
+		if existing.MediaType() == e.MediaType() {
+			encoders[i] = e
+			return
+		}
+	}
+	encoders = append(encoders, e)
+}
+
+// MediaTypes lists the media types of all registered encoders, JSON first.
+func MediaTypes() []string {
+	encodersMu.RLock()
+	defer encodersMu.RUnlock()
+	return mediaTypesLocked()
+}
+
+func mediaTypesLocked() []string {
+	types := make([]string, len(encoders))
+	for i, e := range encoders {
+		types[i] = e.MediaType()
+	}
+	return types
+}
+
+// Negotiate returns the registered Encoder that best matches the request's
+// Accept header, honoring q-values and wildcards. A missing Accept header
+// selects JSON. It returns a CodeNotAcceptable domain error when nothing matches.
+func Negotiate(r *http.Request) (Encoder, error) {
+	encodersMu.RLock()
+	defer encodersMu.RUnlock()
+
+	accept := r.Header.Get("Accept")
+	if strings.TrimSpace(accept) == "" {
+		return encoders[0], nil
+	}
+
+	ranges := parseAccept(accept)
+	var best Encoder
+	bestQ := 0.0
+	for _, e := range encoders {
+		if q := qualityFor(ranges, e.MediaType()); q > bestQ {
+			best, bestQ = e, q
+		}
+	}
+	if best == nil {
+		available := strings.Join(mediaTypesLocked(), ", ")
+		return nil, domain.NewError(CodeNotAcceptable, "none of the accepted media types are supported; available: "+available)
+	}
+	return best, nil
+}
+
+type acceptRange struct {
+	typ, subtype string
+	q            float64
+}
+
+func parseAccept(header string) []acceptRange {
+	var ranges []acceptRange
+	for part := range strings.SplitSeq(header, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		typ, subtype, ok := strings.Cut(mediaType, "/")
+		if !ok {
+			continue
+		}
+		q := 1.0
+		if raw, ok := params["q"]; ok {
+			if parsed, err := strconv.ParseFloat(raw, 64); err == nil {
+				q = parsed
+			}
+		}
+		ranges = append(ranges, acceptRange{typ: typ, subtype: subtype, q: q})
+	}
+	return ranges
+}
+
+// qualityFor returns the q-value of the most specific range matching mediaType.
+func qualityFor(ranges []acceptRange, mediaType string) float64 {
+	typ, subtype, _ := strings.Cut(mediaType, "/")
+	q, specificity := 0.0, -1
+	for _, ar := range ranges {
+		var s int
+		switch {
+		case ar.typ == typ && ar.subtype == subtype:
+			s = 2
+		case ar.typ == typ && ar.subtype == "*":
+			s = 1
+		case ar.typ == "*" && ar.subtype == "*":
+			s = 0
+		default:
+			continue
+		}
+		if s > specificity {
+			q, specificity = ar.q, s
+		}
+	}
+	return q
+}
+
+// responseStatus returns the status recorded with render.Status, defaulting to 200.
+func responseStatus(r *http.Request) int {
+	if status, ok := r.Context().Value(render.StatusCtxKey).(int); ok {
+		return status
+	}
+	return http.StatusOK
+}
+
+// jsonEncoder marks JSON in the registry; RenderOrLog hands JSON responses to
+// chi/render so existing responders keep working unchanged.
+type jsonEncoder struct{}
+
+func (jsonEncoder) MediaType() string { return mediaTypeJSON }
+
+func (jsonEncoder) Encode(w io.Writer, v any) error {
+	return json.NewEncoder(w).Encode(v)
+}
+
+// NewListWriter writes a JSON array, one element at a time.
+func (jsonEncoder) NewListWriter(w io.Writer, flush func()) ListWriter {
+	return &jsonListWriter{w: w, flush: flush}
+}
+
+type jsonListWriter struct {
+	w     io.Writer
+	flush func()
+	n     int
+}
+
+func (l *jsonListWriter) Write(item render.Renderer) error {
+	data, err := json.Marshal(item)
+	if err != nil {
+		return err
+	}
+	sep := []byte{','}
+	if l.n == 0 {
+		sep[0] = '['
+	}
+	if _, err := l.w.Write(append(sep, data...)); err != nil {
+		return err
+	}
+	l.n++
+	if l.n%jsonFlushEvery == 0 {
+		l.flush()
+	}
+	return nil
+}
+
+func (l *jsonListWriter) Close() error {
+	end := "]\n"
+	if l.n == 0 {
+		end = "[]\n"
+	}
+	_, err := io.WriteString(l.w, end)
+	return err
+}
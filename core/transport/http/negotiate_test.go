@@ -0,0 +1,89 @@
+package http
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bbsbb/go-edge/core/domain"
+)
+
+type NegotiateSuite struct {
+	suite.Suite
+}
+
+func (s *NegotiateSuite) negotiate(accept string) (Encoder, error) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	return Negotiate(req)
+}
+
+func (s *NegotiateSuite) TestNegotiate() {
+	tests := []struct {
+		name   string
+		accept string
+		want   string
+	}{
+		{"missing header", "", "application/json"},
+		{"any", "*/*", "application/json"},
+		{"exact", "text/csv", "text/csv"},
+		{"type wildcard", "text/*", "text/csv"},
+		{"ndjson", "application/x-ndjson", "application/x-ndjson"},
+		{"q-values", "application/json;q=0.5, application/cbor", "application/cbor"},
+		{"specific overrides wildcard", "*/*;q=0.1, application/msgpack;q=0.9", "application/msgpack"},
+		{"browser default", "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8", "application/json"},
+		{"excluded by q=0", "application/json;q=0, */*", "application/cbor"},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			enc, err := s.negotiate(tt.accept)
+			s.Require().NoError(err)
+			s.Assert().Equal(tt.want, enc.MediaType())
+		})
+	}
+}
+
+func (s *NegotiateSuite) TestNegotiate_NothingMatches() {
+	_, err := s.negotiate("application/xml, text/html")
+
+	s.Require().Error(err)
+	s.Assert().True(errors.Is(err, domain.NewError(CodeNotAcceptable, "")))
+	s.Assert().ErrorContains(err, "text/csv")
+
+	pt, ok := ProblemTypeFor(CodeNotAcceptable)
+	s.Assert().True(ok)
+	s.Assert().Equal(http.StatusNotAcceptable, pt.Status)
+}
+
+type yamlEncoder struct{}
+
+func (yamlEncoder) MediaType() string { return "application/yaml" }
+func (yamlEncoder) Encode(w io.Writer, _ any) error {
+	_, err := io.WriteString(w, "ok: true\n")
+	return err
+}
+
+func (s *NegotiateSuite) TestRegisterEncoder() {
+	RegisterEncoder(yamlEncoder{})
+
+	enc, err := s.negotiate("application/yaml")
+	s.Require().NoError(err)
+	s.Assert().Equal("application/yaml", enc.MediaType())
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept", "application/yaml")
+	RenderOrLog(rr, req, &NoOpRenderer{}, noopLogger)
+	s.Assert().Equal("ok: true\n", rr.Body.String())
+}
+
+func TestNegotiateSuite(t *testing.T) {
+	suite.Run(t, new(NegotiateSuite))
+}
@@ -0,0 +1,249 @@
+package openapi
+
+import (
+	"encoding"
+	"encoding/json"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/bbsbb/go-edge/core/datatype"
+)
+
+const componentsPrefix = "#/components/schemas/"
+
+var (
+	timeType          = reflect.TypeFor[time.Time]()
+	uuidType          = reflect.TypeFor[uuid.UUID]()
+	moneyType         = reflect.TypeFor[datatype.Money]()
+	rawMessageType    = reflect.TypeFor[json.RawMessage]()
+	textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+)
+
+// moneyFields mirrors the JSON encoding of datatype.Money, whose fields are
+// unexported, so the Money component describes what clients exchange.
+type moneyFields struct {
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency" validate:"min=3,max=3"`
+}
+
+// schemaRegistry reflects Go types into JSON schemas. Named struct types are
+// registered once as components and referenced by $ref.
+type schemaRegistry struct {
+	schemas map[string]*Schema
+	names   map[reflect.Type]string
+}
+
+func newSchemaRegistry() *schemaRegistry {
+	return &schemaRegistry{schemas: map[string]*Schema{}, names: map[reflect.Type]string{}}
+}
+
+// SchemaOf returns the inline schema for v's type, registering named structs as components.
+func (r *schemaRegistry) SchemaOf(v any) *Schema {
+	return r.schemaFor(reflect.TypeOf(v))
+}
+
+func (r *schemaRegistry) schemaFor(t reflect.Type) *Schema {
+	if t == nil {
+		return &Schema{}
+	}
+
+	nullable := false
+	for t.Kind() == reflect.Pointer {
+		nullable = true
+		t = t.Elem()
+	}
+
+	s := r.nonNullSchemaFor(t)
+	if nullable && s.Ref == "" && s.Type != nil {
+		s.Type = []string{s.Type.(string), "null"}
+	}
+	return s
+}
+
+func (r *schemaRegistry) nonNullSchemaFor(t reflect.Type) *Schema {
+	switch t {
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case uuidType:
+		return &Schema{Type: "string", Format: "uuid"}
+	case rawMessageType:
+		return &Schema{}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: r.schemaFor(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: r.schemaFor(t.Elem())}
+	case reflect.Struct:
+		if reflect.PointerTo(t).Implements(textMarshalerType) {
+			return &Schema{Type: "string"}
+		}
+		if t.Name() == "" {
+			return r.structSchema(t)
+		}
+		return &Schema{Ref: componentsPrefix + r.register(t)}
+	default:
+		return &Schema{}
+	}
+}
+
+func (r *schemaRegistry) register(t reflect.Type) string {
+	if name, ok := r.names[t]; ok {
+		return name
+	}
+
+	name := t.Name()
+	if _, taken := r.schemas[name]; taken {
+		pkg := t.PkgPath()
+		name = pkg[strings.LastIndex(pkg, "/")+1:] + "." + name
+	}
+
+	// Reserve the name before recursing so self-referencing types terminate.
+	r.names[t] = name
+	r.schemas[name] = &Schema{}
+	fields := t
+	if t == moneyType {
+		fields = reflect.TypeFor[moneyFields]()
+	}
+	*r.schemas[name] = *r.structSchema(fields)
+	return name
+}
+
+func (r *schemaRegistry) structSchema(t reflect.Type) *Schema {
+	s := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	r.collectFields(t, s)
+	if len(s.Properties) == 0 {
+		s.Properties = nil
+	}
+	return s
+}
+
+// collectFields mirrors encoding/json field visibility: unexported and "-"
+// fields are skipped, untagged embedded structs are flattened, and fields
+// without omitempty/omitzero are listed as required.
+func (r *schemaRegistry) collectFields(t reflect.Type, s *Schema) {
+	for i := range t.NumField() {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				r.collectFields(ft, s)
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+
+		prop := r.schemaFor(f.Type)
+		s.Properties[name] = prop
+		rules := strings.Split(f.Tag.Get("validate"), ",")
+		if prop.Ref == "" {
+			applyRules(prop, f.Type, rules)
+		}
+
+		optional := strings.Contains(opts, "omitempty") || strings.Contains(opts, "omitzero")
+		if (!optional && f.Type.Kind() != reflect.Pointer) || slices.Contains(rules, "required") {
+			s.Required = append(s.Required, name)
+		}
+	}
+}
+
+// applyRules reflects the subset of `validate` tag rules that have a JSON Schema
+// equivalent, so the document advertises the same constraints ValidatingBinder enforces.
+// Rules after "dive" apply to elements and are skipped.
+func applyRules(s *Schema, t reflect.Type, rules []string) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	for _, rule := range rules {
+		name, param, _ := strings.Cut(rule, "=")
+		if name == "dive" {
+			return
+		}
+		switch name {
+		case "oneof":
+			for _, v := range strings.Fields(param) {
+				s.Enum = append(s.Enum, v)
+			}
+		case "min", "max":
+			if n, err := strconv.ParseFloat(param, 64); err == nil {
+				applyBound(s, t.Kind(), name, n)
+			}
+		case "gt", "gte", "lt", "lte":
+			if n, err := strconv.ParseFloat(param, 64); err == nil {
+				applyNumericBound(s, name, n)
+			}
+		}
+	}
+}
+
+func applyBound(s *Schema, kind reflect.Kind, rule string, n float64) {
+	length := int(n)
+	switch kind {
+	case reflect.String:
+		if rule == "min" {
+			s.MinLength = &length
+		} else {
+			s.MaxLength = &length
+		}
+	case reflect.Slice, reflect.Array:
+		if rule == "min" {
+			s.MinItems = &length
+		} else {
+			s.MaxItems = &length
+		}
+	default:
+		if rule == "min" {
+			s.Minimum = &n
+		} else {
+			s.Maximum = &n
+		}
+	}
+}
+
+func applyNumericBound(s *Schema, rule string, n float64) {
+	switch rule {
+	case "gt":
+		s.ExclusiveMinimum = &n
+	case "gte":
+		s.Minimum = &n
+	case "lt":
+		s.ExclusiveMaximum = &n
+	case "lte":
+		s.Maximum = &n
+	}
+}
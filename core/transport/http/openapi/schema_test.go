@@ -0,0 +1,139 @@
+package openapi
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bbsbb/go-edge/core/datatype"
+)
+
+type SchemaSuite struct {
+	suite.Suite
+}
+
+type embedded struct{}
+
+type address struct {
+	City string `json:"city"`
+}
+
+type sample struct {
+	embedded
+	ID        uuid.UUID         `json:"id"`
+	Name      string            `json:"name"`
+	Count     int32             `json:"count"`
+	Total     int64             `json:"total,omitempty"`
+	Price     float64           `json:"price"`
+	Active    bool              `json:"active"`
+	CreatedAt time.Time         `json:"created_at"`
+	Note      *string           `json:"note"`
+	Tags      []string          `json:"tags"`
+	Labels    map[string]string `json:"labels"`
+	Address   address           `json:"address"`
+	Secret    string            `json:"-"`
+	hidden    string
+}
+
+func (s *SchemaSuite) TestSchemaOf_StructRegistersComponent() {
+	r := newSchemaRegistry()
+
+	ref := r.SchemaOf(sample{})
+
+	s.Assert().Equal(componentsPrefix+"sample", ref.Ref)
+	s.Require().Contains(r.schemas, "sample")
+	s.Require().Contains(r.schemas, "address")
+
+	schema := r.schemas["sample"]
+	s.Assert().Equal("object", schema.Type)
+	s.Assert().Equal(&Schema{Type: "string", Format: "uuid"}, schema.Properties["id"])
+	s.Assert().Equal(&Schema{Type: "integer", Format: "int32"}, schema.Properties["count"])
+	s.Assert().Equal(&Schema{Type: "number", Format: "double"}, schema.Properties["price"])
+	s.Assert().Equal(&Schema{Type: "boolean"}, schema.Properties["active"])
+	s.Assert().Equal(&Schema{Type: "string", Format: "date-time"}, schema.Properties["created_at"])
+	s.Assert().Equal(&Schema{Type: []string{"string", "null"}}, schema.Properties["note"])
+	s.Assert().Equal(&Schema{Type: "array", Items: &Schema{Type: "string"}}, schema.Properties["tags"])
+	s.Assert().Equal(&Schema{Type: "object", AdditionalProperties: &Schema{Type: "string"}}, schema.Properties["labels"])
+	s.Assert().Equal(componentsPrefix+"address", schema.Properties["address"].Ref)
+	s.Assert().NotContains(schema.Properties, "Secret")
+	s.Assert().NotContains(schema.Properties, "hidden")
+}
+
+func (s *SchemaSuite) TestSchemaOf_RequiredFollowsOmitemptyAndPointers() {
+	r := newSchemaRegistry()
+	r.SchemaOf(sample{})
+
+	required := r.schemas["sample"].Required
+	s.Assert().Contains(required, "name")
+	s.Assert().Contains(required, "count")
+	s.Assert().NotContains(required, "total")
+	s.Assert().NotContains(required, "note")
+}
+
+func (s *SchemaSuite) TestSchemaOf_SliceOfStructs() {
+	r := newSchemaRegistry()
+
+	schema := r.SchemaOf([]address{})
+
+	s.Assert().Equal("array", schema.Type)
+	s.Assert().Equal(componentsPrefix+"address", schema.Items.Ref)
+}
+
+func (s *SchemaSuite) TestSchemaOf_RegistersOnce() {
+	r := newSchemaRegistry()
+
+	first := r.SchemaOf(address{})
+	second := r.SchemaOf(&address{})
+
+	s.Assert().Equal(first.Ref, second.Ref)
+	s.Assert().Len(r.schemas, 1)
+}
+
+type constrained struct {
+	Name     string   `json:"name,omitempty" validate:"required,min=1,max=100"`
+	Price    int32    `json:"price_cents" validate:"gt=0"`
+	Quantity int32    `json:"quantity" validate:"min=1,max=99"`
+	Status   string   `json:"status" validate:"oneof=open closed"`
+	Tags     []string `json:"tags" validate:"max=3,dive,min=2"`
+}
+
+func (s *SchemaSuite) TestSchemaOf_ValidateTagsBecomeConstraints() {
+	r := newSchemaRegistry()
+	r.SchemaOf(constrained{})
+
+	schema := r.schemas["constrained"]
+	s.Assert().Contains(schema.Required, "name")
+
+	name := schema.Properties["name"]
+	s.Assert().Equal(1, *name.MinLength)
+	s.Assert().Equal(100, *name.MaxLength)
+
+	s.Assert().InDelta(0, *schema.Properties["price_cents"].ExclusiveMinimum, 0)
+	s.Assert().InDelta(1, *schema.Properties["quantity"].Minimum, 0)
+	s.Assert().InDelta(99, *schema.Properties["quantity"].Maximum, 0)
+	s.Assert().Equal([]any{"open", "closed"}, schema.Properties["status"].Enum)
+
+	tags := schema.Properties["tags"]
+	s.Assert().Equal(3, *tags.MaxItems)
+	s.Assert().Nil(tags.MinItems)
+}
+
+func (s *SchemaSuite) TestSchemaOf_MoneyDescribesItsJSONForm() {
+	r := newSchemaRegistry()
+
+	ref := r.SchemaOf(struct {
+		Price *datatype.Money `json:"price"`
+	}{})
+
+	s.Assert().Equal(componentsPrefix+"Money", ref.Properties["price"].Ref)
+	schema := r.schemas["Money"]
+	s.Assert().Equal(&Schema{Type: "integer", Format: "int64"}, schema.Properties["amount"])
+	s.Assert().Equal(3, *schema.Properties["currency"].MinLength)
+	s.Assert().ElementsMatch([]string{"amount", "currency"}, schema.Required)
+}
+
+func TestSchemaSuite(t *testing.T) {
+	suite.Run(t, new(SchemaSuite))
+}
@@ -0,0 +1,235 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+
+	transporthttp "github.com/bbsbb/go-edge/core/transport/http"
+)
+
+const (
+	contentTypeJSON    = "application/json"
+	contentTypeProblem = "application/problem+json"
+)
+
+var pathParamPattern = regexp.MustCompile(`\{([^}:]+)(?::[^}]*)?\}`)
+
+// Operation describes a route for the document. Request and response bodies
+// are given as zero values of the DTO types and reflected into schemas.
+type Operation struct {
+	ID         string
+	Summary    string
+	Tags       []string
+	Parameters []Parameter
+	// Request is the request body DTO; nil for operations without a body.
+	Request any
+	// RequestMediaTypes replaces application/json as the media types the
+	// request body is accepted in, e.g. text/csv for a body of Request rows.
+	RequestMediaTypes []string
+	Responses         []Response
+	// Errors lists the problem details statuses the operation is expected to return.
+	// Every operation additionally gets a "default" problem details response.
+	Errors []int
+}
+
+// Response describes a success response. Body is nil for responses without content.
+type Response struct {
+	Status      int
+	Description string
+	Body        any
+	// MediaTypes replaces the negotiable media types, e.g. text/event-stream
+	// for a stream whose events carry Body as data.
+	MediaTypes []string
+}
+
+// Spec collects operation metadata keyed by method and route pattern.
+type Spec struct {
+	info       Info
+	operations map[string]Operation
+}
+
+func NewSpec(info Info) *Spec {
+	return &Spec{info: info, operations: map[string]Operation{}}
+}
+
+// Operation documents the route registered for method and pattern.
+// Patterns use chi syntax; a trailing slash is ignored.
+func (s *Spec) Operation(method, pattern string, op Operation) *Spec {
+	s.operations[operationKey(method, pattern)] = op
+	return s
+}
+
+// Build walks routes and returns the OpenAPI document. Routes without
+// documented metadata are emitted with path parameters and error responses
+// only. Documented operations that match no route are reported as an error so
+// the spec cannot silently drift from the router.
+func (s *Spec) Build(routes chi.Routes) (*Document, error) {
+	doc := &Document{
+		OpenAPI: Version,
+		Info:    s.info,
+		Paths:   map[string]*PathItem{},
+	}
+	registry := newSchemaRegistry()
+	problem := registry.SchemaOf(transporthttp.ErrorShape{})
+	seen := map[string]bool{}
+
+	err := chi.Walk(routes, func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		path := normalizePattern(route)
+		key := operationKey(method, path)
+		seen[key] = true
+
+		item, ok := doc.Paths[openAPIPath(path)]
+		if !ok {
+			item = &PathItem{}
+			doc.Paths[openAPIPath(path)] = item
+		}
+		slot := item.operation(method)
+		if slot == nil {
+			return nil
+		}
+		*slot = s.operationObject(s.operations[key], path, registry, problem)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("openapi: walk routes: %w", err)
+	}
+
+	var missing []string
+	for key := range s.operations {
+		if !seen[key] {
+			missing = append(missing, key)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return nil, fmt.Errorf("openapi: documented operations without routes: %s", strings.Join(missing, ", "))
+	}
+
+	doc.Components.Schemas = registry.schemas
+	return doc, nil
+}
+
+func (s *Spec) operationObject(op Operation, path string, registry *schemaRegistry, problem *Schema) *OperationObject {
+	obj := &OperationObject{
+		OperationID: op.ID,
+		Summary:     op.Summary,
+		Tags:        op.Tags,
+		Parameters:  pathParameters(path, op.Parameters),
+		Responses:   map[string]*ResponseObject{},
+	}
+
+	if op.Request != nil {
+		schema := registry.SchemaOf(op.Request)
+		mediaTypes := op.RequestMediaTypes
+		if len(mediaTypes) == 0 {
+			mediaTypes = []string{contentTypeJSON}
+		}
+		obj.RequestBody = &RequestBodyObject{Required: true, Content: map[string]*MediaType{}}
+		for _, mediaType := range mediaTypes {
+			obj.RequestBody.Content[mediaType] = &MediaType{Schema: schema}
+		}
+	}
+
+	for _, resp := range op.Responses {
+		ro := &ResponseObject{Description: resp.Description}
+		if ro.Description == "" {
+			ro.Description = http.StatusText(resp.Status)
+		}
+		if resp.Body != nil {
+			// Every negotiable media type encodes the same schema.
+			schema := registry.SchemaOf(resp.Body)
+			mediaTypes := resp.MediaTypes
+			if len(mediaTypes) == 0 {
+				mediaTypes = transporthttp.MediaTypes()
+			}
+			ro.Content = map[string]*MediaType{}
+			for _, mediaType := range mediaTypes {
+				ro.Content[mediaType] = &MediaType{Schema: schema}
+			}
+		}
+		obj.Responses[strconv.Itoa(resp.Status)] = ro
+	}
+
+	for _, status := range op.Errors {
+		obj.Responses[strconv.Itoa(status)] = problemResponse(http.StatusText(status), problem)
+	}
+	obj.Responses["default"] = problemResponse("Problem details", problem)
+
+	return obj
+}
+
+func problemResponse(description string, problem *Schema) *ResponseObject {
+	return &ResponseObject{
+		Description: description,
+		Content:     map[string]*MediaType{contentTypeProblem: {Schema: problem}},
+	}
+}
+
+// pathParameters returns the declared parameters plus a string path
+// parameter for every placeholder in path that was not declared explicitly.
+func pathParameters(path string, declared []Parameter) []Parameter {
+	params := append([]Parameter(nil), declared...)
+	for _, m := range pathParamPattern.FindAllStringSubmatch(path, -1) {
+		name := m[1]
+		found := false
+		for _, p := range declared {
+			if p.In == "path" && p.Name == name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			params = append(params, Parameter{Name: name, In: "path", Required: true, Schema: &Schema{Type: "string"}})
+		}
+	}
+	return params
+}
+
+// PathParam declares a required path parameter with the given schema format.
+func PathParam(name, format string) Parameter {
+	return Parameter{Name: name, In: "path", Required: true, Schema: &Schema{Type: "string", Format: format}}
+}
+
+// QueryParam declares an optional query parameter of the given JSON schema type.
+func QueryParam(name, schemaType, description string) Parameter {
+	return Parameter{Name: name, In: "query", Description: description, Schema: &Schema{Type: schemaType}}
+}
+
+// Handler serves the document as JSON. The document is encoded once up front.
+func Handler(doc *Document, logger *slog.Logger) http.HandlerFunc {
+	body, err := json.Marshal(doc)
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err != nil {
+			transporthttp.WriteError(w, r, fmt.Errorf("openapi: encode document: %w", err), logger)
+			return
+		}
+		w.Header().Set("Content-Type", contentTypeJSON)
+		if _, werr := w.Write(body); werr != nil {
+			logger.ErrorContext(r.Context(), "failed to write openapi document", "error", werr)
+		}
+	}
+}
+
+func normalizePattern(pattern string) string {
+	if len(pattern) > 1 {
+		pattern = strings.TrimSuffix(pattern, "/")
+	}
+	return pattern
+}
+
+// openAPIPath strips chi regexp constraints ({id:[0-9]+} → {id}).
+func openAPIPath(pattern string) string {
+	return pathParamPattern.ReplaceAllString(pattern, "{$1}")
+}
+
+func operationKey(method, pattern string) string {
+	return strings.ToUpper(method) + " " + normalizePattern(pattern)
+}
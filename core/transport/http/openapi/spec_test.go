@@ -0,0 +1,183 @@
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/suite"
+
+	coretesting "github.com/bbsbb/go-edge/core/testing"
+)
+
+type SpecSuite struct {
+	suite.Suite
+}
+
+type createWidget struct {
+	Name string `json:"name"`
+}
+
+type widget struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+func noop(http.ResponseWriter, *http.Request) {}
+
+func (s *SpecSuite) router() *chi.Mux {
+	r := chi.NewRouter()
+	r.Route("/widgets", func(r chi.Router) {
+		r.Get("/", noop)
+		r.Post("/", noop)
+		r.Get("/{id}", noop)
+		r.Delete("/{id}", noop)
+	})
+	return r
+}
+
+func (s *SpecSuite) spec() *Spec {
+	return NewSpec(Info{Title: "Widgets", Version: "1.0.0"}).
+		Operation(http.MethodGet, "/widgets", Operation{
+			ID:        "listWidgets",
+			Responses: []Response{{Status: http.StatusOK, Body: []widget{}}},
+		}).
+		Operation(http.MethodPost, "/widgets", Operation{
+			ID:        "createWidget",
+			Request:   createWidget{},
+			Responses: []Response{{Status: http.StatusCreated, Body: widget{}}},
+			Errors:    []int{http.StatusBadRequest},
+		}).
+		Operation(http.MethodGet, "/widgets/{id}", Operation{
+			ID:         "getWidget",
+			Parameters: []Parameter{PathParam("id", "uuid")},
+			Responses:  []Response{{Status: http.StatusOK, Body: widget{}}},
+			Errors:     []int{http.StatusNotFound},
+		})
+}
+
+func (s *SpecSuite) TestBuild_DocumentedOperations() {
+	doc, err := s.spec().Build(s.router())
+	s.Require().NoError(err)
+
+	s.Assert().Equal(Version, doc.OpenAPI)
+	s.Require().Contains(doc.Paths, "/widgets")
+	s.Require().Contains(doc.Paths, "/widgets/{id}")
+
+	create := doc.Paths["/widgets"].Post
+	s.Require().NotNil(create)
+	s.Assert().Equal("createWidget", create.OperationID)
+	s.Assert().Equal(componentsPrefix+"createWidget", create.RequestBody.Content[contentTypeJSON].Schema.Ref)
+	s.Assert().Equal(componentsPrefix+"widget", create.Responses["201"].Content[contentTypeJSON].Schema.Ref)
+	s.Assert().Equal(componentsPrefix+"ErrorShape", create.Responses["400"].Content[contentTypeProblem].Schema.Ref)
+
+	get := doc.Paths["/widgets/{id}"].Get
+	s.Require().Len(get.Parameters, 1)
+	s.Assert().Equal("uuid", get.Parameters[0].Schema.Format)
+	s.Assert().Contains(doc.Components.Schemas, "ErrorShape")
+}
+
+func (s *SpecSuite) TestBuild_ResponseMediaTypesOverride() {
+	r := chi.NewRouter()
+	r.Get("/widgets/stream", noop)
+
+	doc, err := NewSpec(Info{Title: "Widgets", Version: "1.0.0"}).
+		Operation(http.MethodGet, "/widgets/stream", Operation{
+			ID: "streamWidgets",
+			Responses: []Response{{
+				Status:     http.StatusOK,
+				Body:       widget{},
+				MediaTypes: []string{"text/event-stream"},
+			}},
+		}).
+		Build(r)
+	s.Require().NoError(err)
+
+	content := doc.Paths["/widgets/stream"].Get.Responses["200"].Content
+	s.Require().Len(content, 1)
+	s.Assert().Equal(componentsPrefix+"widget", content["text/event-stream"].Schema.Ref)
+}
+
+func (s *SpecSuite) TestBuild_RequestMediaTypesOverride() {
+	r := chi.NewRouter()
+	r.Post("/widgets/import", noop)
+
+	doc, err := NewSpec(Info{Title: "Widgets", Version: "1.0.0"}).
+		Operation(http.MethodPost, "/widgets/import", Operation{
+			ID:                "importWidgets",
+			Request:           widget{},
+			RequestMediaTypes: []string{"text/csv", "application/x-ndjson"},
+		}).
+		Build(r)
+	s.Require().NoError(err)
+
+	content := doc.Paths["/widgets/import"].Post.RequestBody.Content
+	s.Require().Len(content, 2)
+	s.Assert().Equal(componentsPrefix+"widget", content["text/csv"].Schema.Ref)
+	s.Assert().Equal(componentsPrefix+"widget", content["application/x-ndjson"].Schema.Ref)
+}
+
+func (s *SpecSuite) TestBuild_EveryOperationHasProblemDefault() {
+	doc, err := s.spec().Build(s.router())
+	s.Require().NoError(err)
+
+	for path, item := range doc.Paths {
+		for _, op := range []*OperationObject{item.Get, item.Post, item.Put, item.Delete, item.Patch} {
+			if op == nil {
+				continue
+			}
+			s.Require().Contains(op.Responses, "default", path)
+			s.Assert().Contains(op.Responses["default"].Content, contentTypeProblem, path)
+		}
+	}
+}
+
+func (s *SpecSuite) TestBuild_UndocumentedRouteGetsPathParameters() {
+	doc, err := s.spec().Build(s.router())
+	s.Require().NoError(err)
+
+	del := doc.Paths["/widgets/{id}"].Delete
+	s.Require().NotNil(del)
+	s.Require().Len(del.Parameters, 1)
+	s.Assert().Equal("id", del.Parameters[0].Name)
+	s.Assert().True(del.Parameters[0].Required)
+}
+
+func (s *SpecSuite) TestBuild_DocumentedOperationWithoutRoute() {
+	spec := s.spec().Operation(http.MethodPut, "/widgets/{id}", Operation{ID: "updateWidget"})
+
+	_, err := spec.Build(s.router())
+
+	s.Require().Error(err)
+	s.Assert().Contains(err.Error(), "PUT /widgets/{id}")
+}
+
+func (s *SpecSuite) TestBuild_StripsRegexpConstraints() {
+	r := chi.NewRouter()
+	r.Get("/items/{id:[0-9]+}", noop)
+
+	doc, err := NewSpec(Info{Title: "Items", Version: "1"}).Build(r)
+	s.Require().NoError(err)
+
+	s.Assert().Contains(doc.Paths, "/items/{id}")
+}
+
+func (s *SpecSuite) TestHandler_ServesJSON() {
+	doc, err := s.spec().Build(s.router())
+	s.Require().NoError(err)
+
+	rec := httptest.NewRecorder()
+	Handler(doc, coretesting.NewNoopLogger())(rec, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+
+	s.Assert().Equal(http.StatusOK, rec.Code)
+	s.Assert().Equal(contentTypeJSON, rec.Header().Get("Content-Type"))
+	var decoded map[string]any
+	s.Require().NoError(json.NewDecoder(rec.Body).Decode(&decoded))
+	s.Assert().Equal(Version, decoded["openapi"])
+}
+
+func TestSpecSuite(t *testing.T) {
+	suite.Run(t, new(SpecSuite))
+}
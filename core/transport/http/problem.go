@@ -0,0 +1,77 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/bbsbb/go-edge/core/domain"
+)
+
+// ProblemType describes how a domain.Code is rendered as RFC 9457 problem
+// details: the HTTP status, the "type" URI identifying the problem, and its
+// short human-readable "title".
+type ProblemType struct {
+	Status int
+	Type   string
+	Title  string
+}
+
+// ProblemTypeBlank is the RFC 9457 default for problems with no more specific type.
+const ProblemTypeBlank = "about:blank"
+
+var (
+	problemTypesMu sync.RWMutex
+	problemTypes   = map[domain.Code]ProblemType{}
+)
+
+func init() {
+	for code, status := range map[domain.Code]int{
+		domain.CodeNotFound:   http.StatusNotFound,
+		domain.CodeConflict:   http.StatusConflict,
+		domain.CodeValidation: http.StatusBadRequest,
+		domain.CodeForbidden:  http.StatusForbidden,
+		domain.CodeInvariant:  http.StatusUnprocessableEntity,
+	} {
+		RegisterProblemType(code, ProblemType{Status: status})
+	}
+}
+
+// RegisterProblemType maps code to a problem type, replacing any previous
+// mapping. Applications register their own codes at startup (typically from an
+// init function) before serving requests. A zero Type defaults to
+// "urn:problem-type:" plus the code in kebab case, and a zero Title defaults to
+// the status text. It panics on an invalid status.
+func RegisterProblemType(code domain.Code, pt ProblemType) {
+	if pt.Status < 400 || pt.Status > 599 {
+		panic(fmt.Sprintf("transporthttp: problem type %s: status %d is not an error status", code, pt.Status))
+	}
+	if pt.Type == "" {
+		pt.Type = "urn:problem-type:" + strings.ToLower(strings.ReplaceAll(string(code), "_", "-"))
+	}
+	if pt.Title == "" {
+		pt.Title = http.StatusText(pt.Status)
+	}
+
+	problemTypesMu.Lock()
+	defer problemTypesMu.Unlock()
+	problemTypes[code] = pt
+}
+
+// ProblemTypeFor returns the problem type registered for code. Unregistered
+// codes render as a 500 with the "about:blank" type.
+func ProblemTypeFor(code domain.Code) (ProblemType, bool) {
+	problemTypesMu.RLock()
+	defer problemTypesMu.RUnlock()
+
+	pt, ok := problemTypes[code]
+	if !ok {
+		return blankProblem(http.StatusInternalServerError), false
+	}
+	return pt, true
+}
+
+func blankProblem(status int) ProblemType {
+	return ProblemType{Status: status, Type: ProblemTypeBlank, Title: http.StatusText(status)}
+}
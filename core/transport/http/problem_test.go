@@ -0,0 +1,64 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bbsbb/go-edge/core/domain"
+)
+
+type ProblemTypeSuite struct {
+	suite.Suite
+}
+
+func (s *ProblemTypeSuite) TestRegisterProblemType_Defaults() {
+	code := domain.Code("PAYMENT_DECLINED")
+	RegisterProblemType(code, ProblemType{Status: http.StatusPaymentRequired})
+
+	pt, ok := ProblemTypeFor(code)
+	s.Require().True(ok)
+	s.Assert().Equal(http.StatusPaymentRequired, pt.Status)
+	s.Assert().Equal("urn:problem-type:payment-declined", pt.Type)
+	s.Assert().Equal("Payment Required", pt.Title)
+}
+
+func (s *ProblemTypeSuite) TestRegisterProblemType_UsedByWriteError() {
+	code := domain.Code("RATE_LIMITED")
+	RegisterProblemType(code, ProblemType{
+		Status: http.StatusTooManyRequests,
+		Type:   "https://example.com/problems/rate-limited",
+		Title:  "Slow down",
+	})
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	WriteError(rr, req, domain.NewError(code, "too many orders"), noopLogger)
+
+	s.Assert().Equal(http.StatusTooManyRequests, rr.Code)
+	var resp ErrorShape
+	s.Require().NoError(json.NewDecoder(rr.Body).Decode(&resp))
+	s.Assert().Equal("https://example.com/problems/rate-limited", resp.Type)
+	s.Assert().Equal("Slow down", resp.Title)
+	s.Assert().Equal("RATE_LIMITED", resp.Code)
+}
+
+func (s *ProblemTypeSuite) TestProblemTypeFor_Unregistered() {
+	pt, ok := ProblemTypeFor(domain.Code("NEVER_REGISTERED"))
+	s.Assert().False(ok)
+	s.Assert().Equal(http.StatusInternalServerError, pt.Status)
+	s.Assert().Equal(ProblemTypeBlank, pt.Type)
+}
+
+func (s *ProblemTypeSuite) TestRegisterProblemType_RejectsNonErrorStatus() {
+	s.Assert().Panics(func() {
+		RegisterProblemType(domain.Code("OK"), ProblemType{Status: http.StatusOK})
+	})
+}
+
+func TestProblemTypeSuite(t *testing.T) {
+	suite.Run(t, new(ProblemTypeSuite))
+}
@@ -17,16 +17,128 @@ type NoOpRenderer struct{}
 
 func (NoOpRenderer) Render(http.ResponseWriter, *http.Request) error { return nil }
 
-// RenderOrLog renders v and logs encoding failures instead of silently discarding them.
+// RenderOrLog renders v in the media type negotiated from the Accept header
+// (see Negotiate) and logs encoding failures instead of silently discarding them.
+// JSON goes through chi/render; other encoders run v's Render hook and then
+// encode it. Unsatisfiable Accept headers get a 406 problem details response.
 func RenderOrLog(w http.ResponseWriter, r *http.Request, v render.Renderer, logger *slog.Logger) {
-	if err := render.Render(w, r, v); err != nil {
+	enc, err := Negotiate(r)
+	if err != nil {
+		WriteError(w, r, err, logger)
+		return
+	}
+
+	if _, ok := enc.(jsonEncoder); ok {
+		if err := render.Render(w, r, v); err != nil {
+			logger.ErrorContext(r.Context(), "failed to render response", "error", err)
+		}
+		return
+	}
+
+	if err := v.Render(w, r); err != nil {
 		logger.ErrorContext(r.Context(), "failed to render response", "error", err)
+		return
+	}
+	writeHeader(w, r, enc)
+	if err := enc.Encode(w, v); err != nil {
+		logger.ErrorContext(r.Context(), "failed to encode response", "media_type", enc.MediaType(), "error", err)
 	}
 }
 
-// RenderListOrLog renders a list and logs encoding failures instead of silently discarding them.
+// RenderListOrLog renders a list in the negotiated media type and logs encoding
+// failures instead of silently discarding them. A ListEncoder (such as CSV)
+// streams the list, flushing rows to the client as it goes.
 func RenderListOrLog(w http.ResponseWriter, r *http.Request, v []render.Renderer, logger *slog.Logger) {
-	if err := render.RenderList(w, r, v); err != nil {
-		logger.ErrorContext(r.Context(), "failed to render response list", "error", err)
+	enc, err := Negotiate(r)
+	if err != nil {
+		WriteError(w, r, err, logger)
+		return
+	}
+
+	if _, ok := enc.(jsonEncoder); ok {
+		if err := render.RenderList(w, r, v); err != nil {
+			logger.ErrorContext(r.Context(), "failed to render response list", "error", err)
+		}
+		return
+	}
+
+	for _, item := range v {
+		if err := item.Render(w, r); err != nil {
+			logger.ErrorContext(r.Context(), "failed to render response list", "error", err)
+			return
+		}
+	}
+	writeHeader(w, r, enc)
+
+	if listEnc, ok := enc.(ListEncoder); ok {
+		flush := func() { _ = http.NewResponseController(w).Flush() }
+		err = listEnc.EncodeList(w, v, flush)
+	} else {
+		err = enc.Encode(w, v)
+	}
+	if err != nil {
+		logger.ErrorContext(r.Context(), "failed to encode response list", "media_type", enc.MediaType(), "error", err)
 	}
 }
+
+// StreamListOrLog renders the list produce writes, one item at a time, in the
+// negotiated media type. With a StreamEncoder (JSON, CSV, NDJSON) each item is
+// encoded as it is written and flushed periodically, so the list is never held
+// in memory; other encoders get it once produce returns. If produce fails
+// before writing an item the error is rendered as usual; after that the
+// response is already under way, so it is cut short and the error logged.
+func StreamListOrLog(w http.ResponseWriter, r *http.Request, logger *slog.Logger, produce func(write func(render.Renderer) error) error) {
+	enc, err := Negotiate(r)
+	if err != nil {
+		WriteError(w, r, err, logger)
+		return
+	}
+
+	streamEnc, ok := enc.(StreamEncoder)
+	if !ok {
+		var items []render.Renderer
+		if err := produce(func(item render.Renderer) error {
+			items = append(items, item)
+			return nil
+		}); err != nil {
+			WriteError(w, r, err, logger)
+			return
+		}
+		RenderListOrLog(w, r, items, logger)
+		return
+	}
+
+	var lw ListWriter
+	start := func() {
+		writeHeader(w, r, enc)
+		lw = streamEnc.NewListWriter(w, func() { _ = http.NewResponseController(w).Flush() })
+	}
+	err = produce(func(item render.Renderer) error {
+		if err := item.Render(w, r); err != nil {
+			return err
+		}
+		if lw == nil {
+			start()
+		}
+		return lw.Write(item)
+	})
+	switch {
+	case err != nil && lw == nil:
+		WriteError(w, r, err, logger)
+		return
+	case err != nil:
+		logger.ErrorContext(r.Context(), "failed to stream response list", "media_type", enc.MediaType(), "error", err)
+		return
+	case lw == nil:
+		start()
+	}
+	if err := lw.Close(); err != nil {
+		logger.ErrorContext(r.Context(), "failed to stream response list", "media_type", enc.MediaType(), "error", err)
+	}
+}
+
+func writeHeader(w http.ResponseWriter, r *http.Request, enc Encoder) {
+	w.Header().Set("Content-Type", enc.MediaType())
+	w.Header().Add("Vary", "Accept")
+	w.WriteHeader(responseStatus(r))
+}
@@ -1,15 +1,23 @@
 package http
 
 import (
+	"encoding/csv"
 	"encoding/json"
 	"errors"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/fxamacker/cbor/v2"
 	"github.com/go-chi/render"
 	"github.com/stretchr/testify/suite"
+	"github.com/vmihailenco/msgpack/v5"
 
+	"github.com/bbsbb/go-edge/core/domain"
 	coretesting "github.com/bbsbb/go-edge/core/testing"
 )
 
@@ -63,6 +71,177 @@ func (s *RenderSuite) TestRenderOrLog_LogsOnError() {
 	s.Assert().Contains(output, "render failed")
 }
 
+type renderItem struct {
+	NoOpRenderer
+	ID       string    `json:"id"`
+	Quantity int32     `json:"quantity"`
+	Created  time.Time `json:"created_at"`
+	Tags     []string  `json:"tags,omitempty"`
+}
+
+func (s *RenderSuite) render(accept string, v render.Renderer) *httptest.ResponseRecorder {
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/items/1", nil)
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	render.Status(req, http.StatusCreated)
+	RenderOrLog(rr, req, v, coretesting.NewNoopLogger())
+	return rr
+}
+
+func (s *RenderSuite) TestRenderOrLog_DefaultsToJSON() {
+	rr := s.render("", &renderItem{ID: "a", Quantity: 2})
+
+	s.Assert().Equal(http.StatusCreated, rr.Code)
+	s.Assert().Contains(rr.Header().Get("Content-Type"), "application/json")
+	s.Assert().JSONEq(`{"id":"a","quantity":2,"created_at":"0001-01-01T00:00:00Z"}`, rr.Body.String())
+}
+
+func (s *RenderSuite) TestRenderOrLog_CBOR() {
+	rr := s.render("application/cbor", &renderItem{ID: "a", Quantity: 2})
+
+	s.Assert().Equal(http.StatusCreated, rr.Code)
+	s.Assert().Equal("application/cbor", rr.Header().Get("Content-Type"))
+	var got map[string]any
+	s.Require().NoError(cbor.Unmarshal(rr.Body.Bytes(), &got))
+	s.Assert().Equal("a", got["id"])
+	s.Assert().EqualValues(2, got["quantity"])
+}
+
+func (s *RenderSuite) TestRenderOrLog_MsgPack() {
+	rr := s.render("application/msgpack", &renderItem{ID: "a", Quantity: 2})
+
+	s.Assert().Equal(http.StatusCreated, rr.Code)
+	s.Assert().Equal("application/msgpack", rr.Header().Get("Content-Type"))
+	var got map[string]any
+	s.Require().NoError(msgpack.Unmarshal(rr.Body.Bytes(), &got))
+	s.Assert().Equal("a", got["id"])
+	s.Assert().EqualValues(2, got["quantity"])
+	s.Assert().NotContains(got, "tags")
+}
+
+func (s *RenderSuite) TestRenderOrLog_CSV() {
+	created := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	rr := s.render("text/csv", &renderItem{ID: "a", Quantity: 2, Created: created, Tags: []string{"x"}})
+
+	s.Assert().Equal("text/csv", rr.Header().Get("Content-Type"))
+	s.Assert().Equal("id,quantity,created_at,tags\na,2,2026-01-02T03:04:05Z,\"[\"\"x\"\"]\"\n", rr.Body.String())
+}
+
+func (s *RenderSuite) TestRenderOrLog_NotAcceptable() {
+	rr := s.render("application/xml", &renderItem{ID: "a"})
+
+	s.Assert().Equal(http.StatusNotAcceptable, rr.Code)
+	s.Assert().Equal("application/problem+json", rr.Header().Get("Content-Type"))
+	var resp ErrorShape
+	s.Require().NoError(json.NewDecoder(rr.Body).Decode(&resp))
+	s.Assert().Equal("NOT_ACCEPTABLE", resp.Code)
+}
+
+func (s *RenderSuite) TestRenderListOrLog_StreamsCSV() {
+	items := make([]render.Renderer, 250)
+	for i := range items {
+		items[i] = &renderItem{ID: strconv.Itoa(i), Quantity: int32(i)}
+	}
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/items", nil)
+	req.Header.Set("Accept", "text/csv")
+	RenderListOrLog(rr, req, items, coretesting.NewNoopLogger())
+
+	s.Assert().Equal(http.StatusOK, rr.Code)
+	s.Assert().True(rr.Flushed)
+
+	rows, err := csv.NewReader(rr.Body).ReadAll()
+	s.Require().NoError(err)
+	s.Require().Len(rows, 251)
+	s.Assert().Equal([]string{"id", "quantity", "created_at", "tags"}, rows[0])
+	s.Assert().Equal("249", rows[250][0])
+}
+
+func (s *RenderSuite) TestRenderListOrLog_CBORArray() {
+	items := []render.Renderer{&renderItem{ID: "a"}, &renderItem{ID: "b"}}
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/items", nil)
+	req.Header.Set("Accept", "application/cbor")
+	RenderListOrLog(rr, req, items, coretesting.NewNoopLogger())
+
+	var got []map[string]any
+	s.Require().NoError(cbor.Unmarshal(rr.Body.Bytes(), &got))
+	s.Assert().Len(got, 2)
+	s.Assert().Equal("b", got[1]["id"])
+}
+
+// stream streams n items, failing instead of writing item failAt.
+func (s *RenderSuite) stream(accept string, n, failAt int, logger *slog.Logger) *httptest.ResponseRecorder {
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/items", nil)
+	req.Header.Set("Accept", accept)
+	StreamListOrLog(rr, req, logger, func(write func(render.Renderer) error) error {
+		for i := range n {
+			if i == failAt {
+				return domain.NewError(domain.CodeValidation, "cursor failed")
+			}
+			if err := write(&renderItem{ID: strconv.Itoa(i)}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return rr
+}
+
+func (s *RenderSuite) TestStreamListOrLog_JSONArray() {
+	rr := s.stream("application/json", 3, -1, coretesting.NewNoopLogger())
+
+	s.Assert().Equal(http.StatusOK, rr.Code)
+	s.Assert().Equal("application/json", rr.Header().Get("Content-Type"))
+	var got []map[string]any
+	s.Require().NoError(json.Unmarshal(rr.Body.Bytes(), &got))
+	s.Require().Len(got, 3)
+	s.Assert().Equal("2", got[2]["id"])
+
+	rr = s.stream("application/json", 0, -1, coretesting.NewNoopLogger())
+	s.Assert().Equal("[]\n", rr.Body.String())
+}
+
+func (s *RenderSuite) TestStreamListOrLog_FlushesCSV() {
+	rr := s.stream("text/csv", 250, -1, coretesting.NewNoopLogger())
+
+	s.Assert().True(rr.Flushed)
+	rows, err := csv.NewReader(rr.Body).ReadAll()
+	s.Require().NoError(err)
+	s.Require().Len(rows, 251)
+	s.Assert().Equal("249", rows[250][0])
+}
+
+func (s *RenderSuite) TestStreamListOrLog_ErrorBeforeFirstItemIsRendered() {
+	rr := s.stream("application/x-ndjson", 3, 0, coretesting.NewNoopLogger())
+
+	s.Assert().Equal(http.StatusBadRequest, rr.Code)
+	s.Assert().Equal("application/problem+json", rr.Header().Get("Content-Type"))
+}
+
+func (s *RenderSuite) TestStreamListOrLog_ErrorAfterFirstItemCutsResponseShort() {
+	lc := coretesting.NewLogCapture(&s.Suite)
+
+	rr := s.stream("application/x-ndjson", 3, 2, lc.Logger)
+
+	s.Assert().Equal(http.StatusOK, rr.Code)
+	s.Assert().Equal(2, strings.Count(rr.Body.String(), "\n"))
+	s.Assert().Contains(lc.Output(), "failed to stream response list")
+}
+
+func (s *RenderSuite) TestStreamListOrLog_CollectsForOtherEncoders() {
+	rr := s.stream("application/cbor", 2, -1, coretesting.NewNoopLogger())
+
+	var got []map[string]any
+	s.Require().NoError(cbor.Unmarshal(rr.Body.Bytes(), &got))
+	s.Assert().Len(got, 2)
+}
+
 func TestRenderSuite(t *testing.T) {
 	suite.Run(t, new(RenderSuite))
 }
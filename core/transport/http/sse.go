@@ -0,0 +1,197 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// MediaTypeEventStream is the Server-Sent Events media type.
+const MediaTypeEventStream = "text/event-stream"
+
+const (
+	defaultSSEHeartbeat    = 15 * time.Second
+	defaultSSEWriteTimeout = 10 * time.Second
+)
+
+// SSEEvent is a single Server-Sent Event. Empty fields are omitted on the wire;
+// multi-line Data is split into one data line per line. Clients do not
+// dispatch events without data.
+type SSEEvent struct {
+	ID    string
+	Event string
+	Data  []byte
+	Retry time.Duration
+}
+
+// SSEOptions tunes an SSEStream. Zero values select the defaults.
+type SSEOptions struct {
+	// Heartbeat is the idle interval after which a comment line is sent to keep
+	// proxies from closing the connection. Defaults to 15s.
+	Heartbeat time.Duration
+	// WriteTimeout bounds each write. A client that cannot drain an event in
+	// time is disconnected rather than buffered for. Defaults to 10s.
+	WriteTimeout time.Duration
+	// Retry is sent once at the start of the stream as the client's reconnect delay.
+	Retry time.Duration
+}
+
+// SSEStream writes a text/event-stream response.
+//
+// The server-wide read and write deadlines are lifted for the connection and
+// replaced with a per-write deadline. Served on a route wrapped in
+// httpserverfx.NoTimeout, a stream lives until the client disconnects or the
+// handler returns.
+type SSEStream struct {
+	w           http.ResponseWriter
+	rc          *http.ResponseController
+	opts        SSEOptions
+	lastEventID string
+}
+
+// NewSSEStream writes the event-stream headers and flushes them. It must be
+// called before anything else is written to w.
+func NewSSEStream(w http.ResponseWriter, r *http.Request, opts SSEOptions) (*SSEStream, error) {
+	if opts.Heartbeat <= 0 {
+		opts.Heartbeat = defaultSSEHeartbeat
+	}
+	if opts.WriteTimeout <= 0 {
+		opts.WriteTimeout = defaultSSEWriteTimeout
+	}
+
+	rc := http.NewResponseController(w)
+	// A server ReadTimeout would otherwise cancel the request context mid-stream.
+	if err := rc.SetReadDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		return nil, fmt.Errorf("sse: clear read deadline: %w", err)
+	}
+
+	s := &SSEStream{w: w, rc: rc, opts: opts, lastEventID: LastEventID(r)}
+
+	h := w.Header()
+	h.Set("Content-Type", MediaTypeEventStream)
+	h.Set("Cache-Control", "no-cache")
+	h.Set("Connection", "keep-alive")
+	// Disables response buffering in nginx-style reverse proxies.
+	h.Set("X-Accel-Buffering", "no")
+
+	if err := s.write(func(buf *bytes.Buffer) {
+		if opts.Retry > 0 {
+			fmt.Fprintf(buf, "retry: %d\n\n", opts.Retry.Milliseconds())
+		}
+	}, true); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// LastEventID returns the resume point sent by a reconnecting client: the
+// Last-Event-ID header, or the lastEventId query parameter for clients that
+// cannot set headers.
+func LastEventID(r *http.Request) string {
+	if id := r.Header.Get("Last-Event-ID"); id != "" {
+		return id
+	}
+	return r.URL.Query().Get("lastEventId")
+}
+
+// IsEventStreamRequest reports whether the client asked for an event stream.
+func IsEventStreamRequest(r *http.Request) bool {
+	return strings.Contains(r.Header.Get("Accept"), MediaTypeEventStream)
+}
+
+// LastEventID returns the resume point the client connected with.
+func (s *SSEStream) LastEventID() string {
+	return s.lastEventID
+}
+
+// Send writes and flushes a single event.
+func (s *SSEStream) Send(e SSEEvent) error {
+	return s.write(func(buf *bytes.Buffer) {
+		if e.ID != "" {
+			fmt.Fprintf(buf, "id: %s\n", sanitizeSSEField(e.ID))
+		}
+		if e.Event != "" {
+			fmt.Fprintf(buf, "event: %s\n", sanitizeSSEField(e.Event))
+		}
+		if e.Retry > 0 {
+			fmt.Fprintf(buf, "retry: %d\n", e.Retry.Milliseconds())
+		}
+		for line := range bytes.Lines(e.Data) {
+			buf.WriteString("data: ")
+			buf.Write(bytes.TrimRight(line, "\r\n"))
+			buf.WriteByte('\n')
+		}
+		buf.WriteByte('\n')
+	}, false)
+}
+
+// Heartbeat writes a comment line, which clients ignore.
+func (s *SSEStream) Heartbeat() error {
+	return s.write(func(buf *bytes.Buffer) { buf.WriteString(":\n\n") }, false)
+}
+
+func (s *SSEStream) write(fill func(*bytes.Buffer), header bool) error {
+	var buf bytes.Buffer
+	fill(&buf)
+
+	if err := s.rc.SetWriteDeadline(time.Now().Add(s.opts.WriteTimeout)); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		return fmt.Errorf("sse: set write deadline: %w", err)
+	}
+	if header {
+		s.w.WriteHeader(http.StatusOK)
+	}
+	if buf.Len() > 0 {
+		if _, err := s.w.Write(buf.Bytes()); err != nil {
+			return fmt.Errorf("sse: write: %w", err)
+		}
+	}
+	if err := s.rc.Flush(); err != nil {
+		return fmt.Errorf("sse: flush: %w", err)
+	}
+	return nil
+}
+
+// RunSSE sends every value received on events through encode until ctx is
+// done or events is closed, and sends heartbeats while idle. encode returns
+// false to skip a value.
+//
+// Producers apply backpressure by owning a bounded channel: a producer that
+// finds it full should close it, which ends the stream so the client
+// reconnects and resumes from its Last-Event-ID instead of stalling the
+// producer.
+func RunSSE[T any](ctx context.Context, s *SSEStream, events <-chan T, encode func(T) (SSEEvent, bool)) error {
+	ticker := time.NewTicker(s.opts.Heartbeat)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := s.Heartbeat(); err != nil {
+				return err
+			}
+		case v, ok := <-events:
+			if !ok {
+				return nil
+			}
+			e, send := encode(v)
+			if !send {
+				continue
+			}
+			if err := s.Send(e); err != nil {
+				return err
+			}
+			ticker.Reset(s.opts.Heartbeat)
+		}
+	}
+}
+
+// sanitizeSSEField strips line breaks, which would otherwise start a new field.
+func sanitizeSSEField(v string) string {
+	return strings.NewReplacer("\r", "", "\n", "").Replace(v)
+}
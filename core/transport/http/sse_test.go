@@ -0,0 +1,166 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type SSESuite struct {
+	suite.Suite
+}
+
+func (s *SSESuite) TestNewSSEStream_Headers() {
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/stream", nil)
+
+	_, err := NewSSEStream(rr, req, SSEOptions{Retry: 3 * time.Second})
+	s.Require().NoError(err)
+
+	s.Assert().Equal(http.StatusOK, rr.Code)
+	s.Assert().Equal(MediaTypeEventStream, rr.Header().Get("Content-Type"))
+	s.Assert().Equal("no-cache", rr.Header().Get("Cache-Control"))
+	s.Assert().Equal("no", rr.Header().Get("X-Accel-Buffering"))
+	s.Assert().True(rr.Flushed)
+	s.Assert().Equal("retry: 3000\n\n", rr.Body.String())
+}
+
+func (s *SSESuite) TestSend() {
+	tests := []struct {
+		name  string
+		event SSEEvent
+		want  string
+	}{
+		{
+			name:  "all fields",
+			event: SSEEvent{ID: "42", Event: "order.closed", Data: []byte(`{"a":1}`), Retry: time.Second},
+			want:  "id: 42\nevent: order.closed\nretry: 1000\ndata: {\"a\":1}\n\n",
+		},
+		{
+			name:  "multi-line data",
+			event: SSEEvent{Data: []byte("one\r\ntwo\nthree")},
+			want:  "data: one\ndata: two\ndata: three\n\n",
+		},
+		{
+			name:  "line breaks in id and event are stripped",
+			event: SSEEvent{ID: "1\n2", Event: "a\r\nb", Data: []byte("x")},
+			want:  "id: 12\nevent: ab\ndata: x\n\n",
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			rr := httptest.NewRecorder()
+			stream, err := NewSSEStream(rr, httptest.NewRequest(http.MethodGet, "/", nil), SSEOptions{})
+			s.Require().NoError(err)
+
+			s.Require().NoError(stream.Send(tt.event))
+			s.Assert().Equal(tt.want, rr.Body.String())
+		})
+	}
+}
+
+func (s *SSESuite) TestLastEventID() {
+	req := httptest.NewRequest(http.MethodGet, "/stream?lastEventId=from-query", nil)
+	s.Assert().Equal("from-query", LastEventID(req))
+
+	req.Header.Set("Last-Event-ID", "from-header")
+	s.Assert().Equal("from-header", LastEventID(req))
+
+	stream, err := NewSSEStream(httptest.NewRecorder(), req, SSEOptions{})
+	s.Require().NoError(err)
+	s.Assert().Equal("from-header", stream.LastEventID())
+}
+
+func (s *SSESuite) TestIsEventStreamRequest() {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	s.Assert().False(IsEventStreamRequest(req))
+
+	req.Header.Set("Accept", "text/event-stream")
+	s.Assert().True(IsEventStreamRequest(req))
+}
+
+func (s *SSESuite) TestRunSSE_SendsAndSkips() {
+	rr := httptest.NewRecorder()
+	stream, err := NewSSEStream(rr, httptest.NewRequest(http.MethodGet, "/", nil), SSEOptions{})
+	s.Require().NoError(err)
+
+	events := make(chan int, 3)
+	events <- 1
+	events <- 2
+	events <- 3
+	close(events)
+
+	err = RunSSE(context.Background(), stream, events, func(n int) (SSEEvent, bool) {
+		return SSEEvent{ID: strconv.Itoa(n), Data: []byte("n")}, n != 2
+	})
+	s.Require().NoError(err)
+	s.Assert().Equal("id: 1\ndata: n\n\nid: 3\ndata: n\n\n", rr.Body.String())
+}
+
+func (s *SSESuite) TestRunSSE_Heartbeat() {
+	rr := httptest.NewRecorder()
+	stream, err := NewSSEStream(rr, httptest.NewRequest(http.MethodGet, "/", nil), SSEOptions{
+		Heartbeat: 10 * time.Millisecond,
+	})
+	s.Require().NoError(err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 55*time.Millisecond)
+	defer cancel()
+
+	err = RunSSE(ctx, stream, make(chan int), func(int) (SSEEvent, bool) { return SSEEvent{}, false })
+	s.Require().NoError(err)
+	s.Assert().GreaterOrEqual(bytes.Count(rr.Body.Bytes(), []byte(":\n\n")), 2)
+}
+
+func (s *SSESuite) TestRunSSE_SlowClientIsDisconnected() {
+	result := make(chan error, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		stream, err := NewSSEStream(w, r, SSEOptions{WriteTimeout: 50 * time.Millisecond})
+		if err != nil {
+			result <- err
+			return
+		}
+
+		events := make(chan []byte)
+		go func() {
+			payload := bytes.Repeat([]byte("x"), 1<<20)
+			for {
+				select {
+				case events <- payload:
+				case <-r.Context().Done():
+					return
+				}
+			}
+		}()
+		result <- RunSSE(r.Context(), stream, events, func(b []byte) (SSEEvent, bool) {
+			return SSEEvent{Data: b}, true
+		})
+	}))
+	defer srv.Close()
+
+	// A raw connection that never reads the response body.
+	conn, err := net.Dial("tcp", srv.Listener.Addr().String())
+	s.Require().NoError(err)
+	defer func() { _ = conn.Close() }()
+	_, err = conn.Write([]byte("GET / HTTP/1.1\r\nHost: test\r\nAccept: text/event-stream\r\n\r\n"))
+	s.Require().NoError(err)
+
+	select {
+	case err := <-result:
+		s.Assert().ErrorContains(err, "sse: write")
+	case <-time.After(5 * time.Second):
+		s.Fail("slow client was not disconnected")
+	}
+}
+
+func TestSSESuite(t *testing.T) {
+	suite.Run(t, new(SSESuite))
+}
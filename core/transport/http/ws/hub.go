@@ -0,0 +1,336 @@
+// Package ws provides WebSocket rooms scoped to the organization in the
+// request context, with keepalive, inbound size limits and a broadcast API.
+package ws
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/google/uuid"
+
+	"github.com/bbsbb/go-edge/core/domain"
+	transporthttp "github.com/bbsbb/go-edge/core/transport/http"
+)
+
+const (
+	defaultReadLimit    int64 = 1 << 20 // 1 MB
+	defaultPingInterval       = 30 * time.Second
+	defaultPingTimeout        = 10 * time.Second
+	defaultWriteTimeout       = 10 * time.Second
+	defaultSendBuffer         = 64
+)
+
+var (
+	ErrClientClosed = errors.New("ws: client closed")
+	ErrSlowClient   = errors.New("ws: client send buffer full")
+)
+
+// Options tunes a Hub. Zero values select the defaults.
+type Options struct {
+	// ReadLimit caps inbound message size; larger messages close the
+	// connection with status 1009. Set it from middlewarefx.MaxBytesConfig.Limit()
+	// so WebSocket messages and request bodies share one limit. Defaults to 1 MB.
+	ReadLimit int64
+	// PingInterval is how often clients are pinged. A client that does not
+	// answer within PingTimeout is disconnected. Defaults to 30s and 10s.
+	PingInterval time.Duration
+	PingTimeout  time.Duration
+	// WriteTimeout bounds each outbound message. Defaults to 10s.
+	WriteTimeout time.Duration
+	// SendBuffer is the number of outbound messages queued per client. A client
+	// whose queue is full is disconnected rather than buffered for. Defaults to 64.
+	SendBuffer int
+	// OriginPatterns lists the cross-origin hosts allowed to connect, as in
+	// websocket.AcceptOptions. Same-origin requests are always allowed.
+	OriginPatterns []string
+}
+
+func (o Options) withDefaults() Options {
+	if o.ReadLimit <= 0 {
+		o.ReadLimit = defaultReadLimit
+	}
+	if o.PingInterval <= 0 {
+		o.PingInterval = defaultPingInterval
+	}
+	if o.PingTimeout <= 0 {
+		o.PingTimeout = defaultPingTimeout
+	}
+	if o.WriteTimeout <= 0 {
+		o.WriteTimeout = defaultWriteTimeout
+	}
+	if o.SendBuffer <= 0 {
+		o.SendBuffer = defaultSendBuffer
+	}
+	return o
+}
+
+// IsUpgradeRequest reports whether r asks to upgrade to a WebSocket.
+func IsUpgradeRequest(r *http.Request) bool {
+	return strings.EqualFold(r.Header.Get("Upgrade"), "websocket") &&
+		headerContainsToken(r.Header.Get("Connection"), "upgrade")
+}
+
+func headerContainsToken(value, token string) bool {
+	for part := range strings.SplitSeq(value, ",") {
+		if strings.EqualFold(strings.TrimSpace(part), token) {
+			return true
+		}
+	}
+	return false
+}
+
+// MessageHandler handles one inbound message. Messages from a client are
+// handled one at a time on its read loop, which also processes pongs, so a
+// handler that runs longer than the ping timeout disconnects the client.
+type MessageHandler func(ctx context.Context, c *Client, data []byte)
+
+// Hub tracks connected clients in one room per organization.
+type Hub struct {
+	opts   Options
+	logger *slog.Logger
+
+	mu     sync.Mutex
+	rooms  map[uuid.UUID]map[*Client]struct{}
+	closed bool
+}
+
+func NewHub(opts Options, logger *slog.Logger) *Hub {
+	return &Hub{
+		opts:   opts.withDefaults(),
+		logger: logger,
+		rooms:  make(map[uuid.UUID]map[*Client]struct{}),
+	}
+}
+
+// Handler upgrades the request and joins the client to the room of the
+// organization in the request context. It must be mounted behind the
+// organization middleware. onMessage may be nil for send-only endpoints.
+func (h *Hub) Handler(onMessage MessageHandler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		org, err := domain.OrganizationFromContext(r.Context())
+		if err != nil {
+			transporthttp.WriteError(w, r, err, h.logger)
+			return
+		}
+
+		conn, err := websocket.Accept(w, r, &websocket.AcceptOptions{OriginPatterns: h.opts.OriginPatterns})
+		if err != nil {
+			// Accept has already written the handshake error response.
+			h.logger.DebugContext(r.Context(), "websocket upgrade failed", "error", err)
+			return
+		}
+		conn.SetReadLimit(h.opts.ReadLimit)
+
+		c := &Client{
+			conn:           conn,
+			organizationID: org.ID,
+			send:           make(chan []byte, h.opts.SendBuffer),
+			done:           make(chan struct{}),
+		}
+		if !h.join(c) {
+			_ = conn.Close(websocket.StatusGoingAway, "server shutting down")
+			return
+		}
+		defer h.leave(c)
+
+		ctx, cancel := context.WithCancel(r.Context())
+		defer cancel()
+
+		// The writer never cancels ctx: a client closed from the writer side
+		// ends the read loop through the closing handshake instead.
+		writerDone := make(chan struct{})
+		go func() {
+			defer close(writerDone)
+			h.writeLoop(ctx, c)
+		}()
+
+		err = h.readLoop(ctx, c, onMessage)
+		cancel()
+		<-writerDone
+		c.terminate()
+		h.logger.DebugContext(r.Context(), "websocket closed",
+			"organization_id", org.ID, "status", websocket.CloseStatus(err), "error", err)
+	}
+}
+
+func (h *Hub) readLoop(ctx context.Context, c *Client, onMessage MessageHandler) error {
+	for {
+		_, data, err := c.conn.Read(ctx)
+		if err != nil {
+			return err
+		}
+		if onMessage != nil {
+			onMessage(ctx, c, data)
+		}
+	}
+}
+
+func (h *Hub) writeLoop(ctx context.Context, c *Client) {
+	ticker := time.NewTicker(h.opts.PingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-c.done:
+			return
+		case data := <-c.send:
+			wctx, cancel := context.WithTimeout(ctx, h.opts.WriteTimeout)
+			err := c.conn.Write(wctx, websocket.MessageText, data)
+			cancel()
+			if err != nil {
+				// A failed write closes the connection, which ends the read loop.
+				return
+			}
+		case <-ticker.C:
+			pctx, cancel := context.WithTimeout(ctx, h.opts.PingTimeout)
+			err := c.conn.Ping(pctx)
+			cancel()
+			if err != nil {
+				c.close(websocket.StatusPolicyViolation, "ping timeout")
+				return
+			}
+		}
+	}
+}
+
+func (h *Hub) join(c *Client) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return false
+	}
+	if h.rooms[c.organizationID] == nil {
+		h.rooms[c.organizationID] = make(map[*Client]struct{})
+	}
+	h.rooms[c.organizationID][c] = struct{}{}
+	return true
+}
+
+func (h *Hub) leave(c *Client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	room := h.rooms[c.organizationID]
+	delete(room, c)
+	if len(room) == 0 {
+		delete(h.rooms, c.organizationID)
+	}
+}
+
+// Broadcast sends v as JSON to every client of the organization in ctx.
+func (h *Hub) Broadcast(ctx context.Context, v any) error {
+	org, err := domain.OrganizationFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	return h.BroadcastTo(org.ID, v)
+}
+
+// BroadcastTo sends v as JSON to every client of the organization. Clients
+// that cannot keep up are disconnected; delivery to the others is unaffected.
+func (h *Hub) BroadcastTo(organizationID uuid.UUID, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("ws: encode broadcast: %w", err)
+	}
+
+	h.mu.Lock()
+	clients := make([]*Client, 0, len(h.rooms[organizationID]))
+	for c := range h.rooms[organizationID] {
+		clients = append(clients, c)
+	}
+	h.mu.Unlock()
+
+	for _, c := range clients {
+		if err := c.enqueue(data); errors.Is(err, ErrSlowClient) {
+			h.logger.Warn("dropping slow websocket client", "organization_id", organizationID)
+		}
+	}
+	return nil
+}
+
+// Len returns the number of clients connected for the organization.
+func (h *Hub) Len(organizationID uuid.UUID) int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.rooms[organizationID])
+}
+
+// Close disconnects every client with status 1001 and rejects new ones.
+// http.Server.Shutdown does not track upgraded connections, so call it on stop.
+func (h *Hub) Close() {
+	h.mu.Lock()
+	h.closed = true
+	var clients []*Client
+	for _, room := range h.rooms {
+		for c := range room {
+			clients = append(clients, c)
+		}
+	}
+	h.mu.Unlock()
+
+	for _, c := range clients {
+		c.close(websocket.StatusGoingAway, "server shutting down")
+	}
+}
+
+// Client is one connected WebSocket.
+type Client struct {
+	conn           *websocket.Conn
+	organizationID uuid.UUID
+	send           chan []byte
+
+	closeOnce sync.Once
+	done      chan struct{}
+}
+
+// OrganizationID returns the organization whose room the client joined.
+func (c *Client) OrganizationID() uuid.UUID {
+	return c.organizationID
+}
+
+// Send queues v as JSON for this client only.
+func (c *Client) Send(v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("ws: encode message: %w", err)
+	}
+	return c.enqueue(data)
+}
+
+func (c *Client) enqueue(data []byte) error {
+	select {
+	case <-c.done:
+		return ErrClientClosed
+	default:
+	}
+	select {
+	case c.send <- data:
+		return nil
+	default:
+		c.close(websocket.StatusPolicyViolation, "client too slow")
+		return ErrSlowClient
+	}
+}
+
+// close starts the closing handshake without waiting for it to complete.
+func (c *Client) close(code websocket.StatusCode, reason string) {
+	c.closeOnce.Do(func() {
+		close(c.done)
+		go func() { _ = c.conn.Close(code, reason) }()
+	})
+}
+
+func (c *Client) terminate() {
+	c.closeOnce.Do(func() { close(c.done) })
+	_ = c.conn.CloseNow()
+}
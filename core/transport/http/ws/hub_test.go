@@ -0,0 +1,172 @@
+//go:build testing
+
+package ws
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bbsbb/go-edge/core/domain"
+	coretesting "github.com/bbsbb/go-edge/core/testing"
+)
+
+type HubSuite struct {
+	suite.Suite
+	hub *Hub
+	srv *httptest.Server
+}
+
+// serve mounts the hub behind a stand-in for the organization middleware,
+// which reads the organization ID from the "org" query parameter.
+func (s *HubSuite) serve(opts Options, onMessage MessageHandler) {
+	s.hub = NewHub(opts, coretesting.NewNoopLogger())
+	handler := s.hub.Handler(onMessage)
+	s.srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if id := r.URL.Query().Get("org"); id != "" {
+			r = r.WithContext(domain.ContextWithOrganization(r.Context(), &domain.Organization{
+				ID: uuid.MustParse(id), Slug: "test-org",
+			}))
+		}
+		handler.ServeHTTP(w, r)
+	}))
+}
+
+func (s *HubSuite) TearDownTest() {
+	if s.srv != nil {
+		s.hub.Close()
+		s.srv.Close()
+		s.srv = nil
+	}
+}
+
+func (s *HubSuite) dial(orgID uuid.UUID) *websocket.Conn {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	url := "ws" + strings.TrimPrefix(s.srv.URL, "http") + "/?org=" + orgID.String()
+	conn, _, err := websocket.Dial(ctx, url, nil)
+	s.Require().NoError(err)
+	s.T().Cleanup(func() { _ = conn.CloseNow() })
+
+	s.Eventually(func() bool { return s.hub.Len(orgID) > 0 }, time.Second, 5*time.Millisecond)
+	return conn
+}
+
+func (s *HubSuite) read(conn *websocket.Conn) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	_, data, err := conn.Read(ctx)
+	return string(data), err
+}
+
+func (s *HubSuite) TestBroadcastIsScopedToOrganization() {
+	s.serve(Options{}, nil)
+	orgA, orgB := uuid.Must(uuid.NewV7()), uuid.Must(uuid.NewV7())
+	a1, a2, b := s.dial(orgA), s.dial(orgA), s.dial(orgB)
+
+	ctx := domain.ContextWithOrganization(context.Background(), &domain.Organization{ID: orgA})
+	s.Require().NoError(s.hub.Broadcast(ctx, map[string]string{"type": "order.updated"}))
+
+	for _, conn := range []*websocket.Conn{a1, a2} {
+		msg, err := s.read(conn)
+		s.Require().NoError(err)
+		s.Assert().JSONEq(`{"type":"order.updated"}`, msg)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, _, err := b.Read(ctx)
+	s.Assert().Error(err, "another organization must not receive the broadcast")
+}
+
+func (s *HubSuite) TestBroadcastRequiresOrganization() {
+	s.serve(Options{}, nil)
+	s.Assert().ErrorIs(s.hub.Broadcast(context.Background(), "x"), domain.ErrMissingOrganization)
+}
+
+func (s *HubSuite) TestMessagesReachHandler() {
+	s.serve(Options{}, func(_ context.Context, c *Client, data []byte) {
+		_ = c.Send(map[string]string{"echo": string(data), "org": c.OrganizationID().String()})
+	})
+	orgID := uuid.Must(uuid.NewV7())
+	conn := s.dial(orgID)
+
+	s.Require().NoError(conn.Write(context.Background(), websocket.MessageText, []byte("hi")))
+
+	msg, err := s.read(conn)
+	s.Require().NoError(err)
+	s.Assert().JSONEq(`{"echo":"hi","org":"`+orgID.String()+`"}`, msg)
+}
+
+func (s *HubSuite) TestReadLimit() {
+	s.serve(Options{ReadLimit: 16}, func(context.Context, *Client, []byte) {})
+	conn := s.dial(uuid.Must(uuid.NewV7()))
+
+	_ = conn.Write(context.Background(), websocket.MessageText, bytes.Repeat([]byte("x"), 64))
+
+	_, err := s.read(conn)
+	s.Assert().Equal(websocket.StatusMessageTooBig, websocket.CloseStatus(err))
+}
+
+func (s *HubSuite) TestSlowClientIsDisconnected() {
+	s.serve(Options{SendBuffer: 1, WriteTimeout: 50 * time.Millisecond}, nil)
+	orgID := uuid.Must(uuid.NewV7())
+	s.dial(orgID) // never reads
+
+	payload := strings.Repeat("x", 1<<20)
+	s.Eventually(func() bool {
+		_ = s.hub.BroadcastTo(orgID, payload)
+		return s.hub.Len(orgID) == 0
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func (s *HubSuite) TestPingKeepsClientConnected() {
+	s.serve(Options{PingInterval: 20 * time.Millisecond, PingTimeout: time.Second}, nil)
+	orgID := uuid.Must(uuid.NewV7())
+	conn := s.dial(orgID)
+	// Pongs are only sent while the client reads.
+	conn.CloseRead(context.Background())
+
+	time.Sleep(100 * time.Millisecond)
+	s.Assert().Equal(1, s.hub.Len(orgID))
+}
+
+func (s *HubSuite) TestCloseDisconnectsWithGoingAway() {
+	s.serve(Options{}, nil)
+	conn := s.dial(uuid.Must(uuid.NewV7()))
+
+	s.hub.Close()
+
+	_, err := s.read(conn)
+	s.Assert().Equal(websocket.StatusGoingAway, websocket.CloseStatus(err))
+}
+
+func (s *HubSuite) TestRejectsRequestWithoutOrganization() {
+	s.serve(Options{}, nil)
+
+	_, resp, err := websocket.Dial(context.Background(), "ws"+strings.TrimPrefix(s.srv.URL, "http"), nil)
+	s.Require().Error(err)
+	s.Assert().Equal(http.StatusInternalServerError, resp.StatusCode)
+}
+
+func (s *HubSuite) TestIsUpgradeRequest() {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	s.Assert().False(IsUpgradeRequest(req))
+
+	req.Header.Set("Upgrade", "WebSocket")
+	req.Header.Set("Connection", "keep-alive, Upgrade")
+	s.Assert().True(IsUpgradeRequest(req))
+}
+
+func TestHubSuite(t *testing.T) {
+	suite.Run(t, new(HubSuite))
+}
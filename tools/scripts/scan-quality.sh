@@ -79,6 +79,7 @@ while IFS= read -r file; do
 done < <(find "$ROOT/core" "$ROOT/apps" -name '*.go' \
     -not -path '*/mocks/*' \
     -not -path '*/sqlcgen/*' \
+    -not -name '*.pb.go' \
     -not -path '*/vendor/*' \
     -not -path '*/.git/*' \
     2>/dev/null)
@@ -76,6 +76,7 @@ declare -A PKG_TO_DEPGUARD=(
     ["database/sql"]="database/sql"
     ["net/http"]="net/http"
     ["chi"]="go-chi/chi"
+    ["grpc"]="google.golang.org/grpc"
     ["infrastructure/"]="internal/infrastructure"
     ["transport/"]="internal/transport"
     ["service/"]="internal/service"
@@ -87,6 +88,7 @@ declare -A PKG_TO_TEST=(
     ["database/sql"]="database/sql"
     ["net/http"]="net/http"
     ["chi"]="go-chi/chi"
+    ["grpc"]="google.golang.org/grpc"
     ["infrastructure/"]="internal/infrastructure"
     ["transport/"]="internal/transport"
     ["service/"]="internal/service"
@@ -98,6 +100,7 @@ declare -A PKG_TO_TEST=(
 declare -A PKG_GOMOD_PATTERN=(
     ["pgx"]="jackc/pgx"
     ["chi"]="go-chi/chi"
+    ["grpc"]="google.golang.org/grpc"
 )
 
 # Collect all go.mod contents once for dependency checks